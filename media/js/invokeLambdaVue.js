@@ -11,35 +11,50 @@ console.log('Loaded!')
             statusCode: '',
             logs: '',
             showResponse: false,
-            isLoading: false
+            isLoading: false,
+            hasLastPayload: false,
+            selectedFile: '',
+            selectedFileText: '',
+            payloadType: 'json',
+            payloadFile: '',
+            responseView: 'text'
         },
         mounted() {
             this.$nextTick(function() {
                 window.addEventListener('message', this.handleMessageReceived)
+                vscode.postMessage({ command: 'initialize' })
             })
         },
         methods: {
             newSelection: function() {
+                // sample requests are JSON events
+                this.payloadType = 'json'
                 vscode.postMessage({
                     command: 'sampleRequestSelected',
                     value: this.selectedSampleRequest
                 })
             },
-            processFile: function($event) {
-                console.log($event)
-                console.log($event.target)
-                const inputFile = $event.target
-                const self = this
-                if ('files' in inputFile && inputFile.files.length > 0) {
-                    const reader = new FileReader()
-                    reader.onload = event => {
-                        console.log(event.target.result)
-                        self.sampleText = ''
-                        self.sampleText = event.target.result
-                    } // desired file content
-                    reader.onerror = error => reject(error)
-                    reader.readAsText(inputFile.files[0])
-                }
+            promptForFile: function() {
+                vscode.postMessage({
+                    command: 'promptForFile',
+                    payloadType: this.payloadType
+                })
+            },
+            generateFromSchemaFile: function() {
+                vscode.postMessage({
+                    command: 'generateFromSchemaFile'
+                })
+            },
+            generateFromRegistrySchema: function() {
+                vscode.postMessage({
+                    command: 'generateFromRegistrySchema'
+                })
+            },
+            retryLastPayload: function() {
+                this.isLoading = true
+                vscode.postMessage({
+                    command: 'retryLastPayload'
+                })
             },
             handleMessageReceived: function(e) {
                 const message = event.data
@@ -48,23 +63,41 @@ console.log('Loaded!')
                 switch (message.command) {
                     case 'loadedSample':
                         this.loadSampleText(message.sample)
+                        this.selectedFile = ''
+                        if (message.payloadType) {
+                            this.payloadType = message.payloadType
+                        }
+                        break
+                    case 'loadedFile':
+                        this.loadSampleText(message.sample)
+                        this.selectedFile = message.selectedFile
+                        this.selectedFileText = message.sample
+                        if (message.payloadType) {
+                            this.payloadType = message.payloadType
+                        }
+                        break
+                    case 'loadedPayloadFile':
+                        this.payloadType = 'file'
+                        this.payloadFile = message.selectedFile
+                        break
+                    case 'lastPayloadAvailable':
+                        this.hasLastPayload = message.available
+                        if (!message.available) {
+                            // a retry found nothing to invoke
+                            this.isLoading = false
+                        }
                         break
                     case 'invokedLambda':
                         this.showResponse = true
                         this.error = ''
-                        this.payload = ''
+                        this.payload = {}
                         this.statusCode = ''
                         this.logs = ''
                         if (message.error) {
                             this.error = message.error
                         } else {
-                            let parsed
-                            try {
-                                parsed = JSON.parse(message.payload)
-                            } catch (e) {
-                                parsed = message.payload
-                            }
-                            this.payload = parsed
+                            this.payload = message.payload
+                            this.responseView = message.payload.binary ? 'base64' : 'text'
                             this.statusCode = message.statusCode
                             this.logs = message.logs
                         }
@@ -78,10 +111,23 @@ console.log('Loaded!')
             sendInput: function() {
                 console.log(this.sampleText)
                 this.isLoading = true
+                if (this.payloadType === 'file') {
+                    vscode.postMessage({
+                        command: 'invokeLambda',
+                        payloadType: this.payloadType,
+                        filePath: this.payloadFile
+                    })
+                    this.hasLastPayload = true
+                    return
+                }
                 vscode.postMessage({
                     command: 'invokeLambda',
-                    value: this.sampleText
+                    value: this.sampleText,
+                    payloadType: this.payloadType,
+                    // Only remember the file if the payload was not edited after loading it.
+                    filePath: this.selectedFile && this.sampleText === this.selectedFileText ? this.selectedFile : undefined
                 })
+                this.hasLastPayload = true
             }
         }
     })
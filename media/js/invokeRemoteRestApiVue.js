@@ -9,11 +9,18 @@ console.log('Loaded!')
             methods: [],
             jsonInput: '',
             queryString: '',
+            headers: '',
+            stageName: '',
+            pathParameters: [],
+            pathParameterValues: {},
+            response: undefined,
+            invokeError: '',
             errors: [],
             isLoading: false,
             localizedMessages: {
                 noApiResource: 'noApiResource',
                 noMethod: 'noMethod',
+                noPathParameter: 'noPathParameter',
             },
         },
         mounted() {
@@ -31,12 +38,27 @@ console.log('Loaded!')
                         if (this.methods) {
                             this.selectedMethod = this.methods[0]
                         }
+                        this.pathParameters = message.pathParameters || []
+                        // build the object before assigning it so that Vue observes every parameter
+                        const values = {}
+                        for (const name of this.pathParameters) {
+                            values[name] = ''
+                        }
+                        this.pathParameterValues = values
                         break
                     case 'setLocalizedMessages':
                         this.localizedMessages = message.localizedMessages
                         break
                     case 'invokeApiStarted':
                         this.isLoading = true
+                        this.response = undefined
+                        this.invokeError = ''
+                        break
+                    case 'invokeApiResult':
+                        this.response = message.result
+                        break
+                    case 'invokeApiError':
+                        this.invokeError = message.error
                         break
                     case 'invokeApiFinished':
                         this.isLoading = false
@@ -57,6 +79,11 @@ console.log('Loaded!')
                 if (!this.selectedMethod) {
                     this.errors.push(this.localizedMessages.noMethod)
                 }
+                for (const name of this.pathParameters) {
+                    if (!this.pathParameterValues[name]) {
+                        this.errors.push(this.localizedMessages.noPathParameter + ': ' + name)
+                    }
+                }
                 if (this.errors.length > 0) {
                     return
                 }
@@ -68,6 +95,9 @@ console.log('Loaded!')
                     selectedApiResource: this.selectedApiResource,
                     selectedMethod: this.selectedMethod,
                     queryString: this.queryString,
+                    pathParameters: this.pathParameterValues,
+                    headers: this.headers,
+                    stageName: this.stageName,
                 })
             },
         },
@@ -32,6 +32,8 @@ export async function activate(activateArguments: {
                 await invokeRemoteRestApi({
                     apiNode: node,
                     outputChannel: activateArguments.outputChannel,
+                    awsContext: activateArguments.extContext.awsContext,
+                    regionProvider,
                 })
         )
     )
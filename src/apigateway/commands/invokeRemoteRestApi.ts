@@ -11,11 +11,16 @@ import { ext } from '../../shared/extensionGlobals'
 import { template } from 'lodash'
 import { toArrayAsync, toMap } from '../../shared/utilities/collectionUtils'
 import { ExtensionUtilities } from '../../shared/extensionUtilities'
-import { Resource } from 'aws-sdk/clients/apigateway'
+import { Resource, Stage } from 'aws-sdk/clients/apigateway'
 import { ApiGatewayClient } from '../../shared/clients/apiGatewayClient'
 import { APIG_REMOTE_INVOKE_TEMPLATE } from '../templates/apigTemplates'
 import { localize } from '../../shared/utilities/vsCodeUtils'
 import { recordApigatewayInvokeRemote, Result } from '../../shared/telemetry/telemetry'
+import { AwsContext, NoActiveCredentialError } from '../../shared/awsContext'
+import { RegionProvider } from '../../shared/regions/regionProvider'
+import { DEFAULT_DNS_SUFFIX } from '../../shared/regions/regionUtilities'
+import { buildDefaultApiInvokeUrl } from './copyUrl'
+import { ApiInvokeResult, buildInvokePath, getPathParameters, invokeStageUrl, parseHeaders } from './invokeStageUrl'
 
 // All the commands that we receive
 interface Command {
@@ -31,6 +36,19 @@ interface InvokeApiMessage extends Command {
     selectedApiResource: string
     selectedMethod: string
     queryString: string
+    pathParameters?: { [name: string]: string }
+    /** Headers as entered by the user, one `Name: value` pair per line. */
+    headers?: string
+    /** If set, the deployed stage to call through its invoke URL instead of using TestInvokeMethod. */
+    stageName?: string
+}
+
+export interface StageInvokeArguments {
+    stageName: string
+    path: string
+    method: string
+    headers: { [name: string]: string }
+    body: string
 }
 
 function isApiSelectedMessage(command: Command): command is ApiSelectedMessage {
@@ -41,7 +59,12 @@ function isInvokeApiMessage(command: Command): command is InvokeApiMessage {
     return command.command === 'invokeApi'
 }
 
-export async function invokeRemoteRestApi(params: { outputChannel: vscode.OutputChannel; apiNode: RestApiNode }) {
+export async function invokeRemoteRestApi(params: {
+    outputChannel: vscode.OutputChannel
+    apiNode: RestApiNode
+    awsContext: Pick<AwsContext, 'getCredentials'>
+    regionProvider: RegionProvider
+}) {
     const logger: Logger = getLogger()
     const apiNode = params.apiNode
 
@@ -74,6 +97,14 @@ export async function invokeRemoteRestApi(params: { outputChannel: vscode.Output
 
         logger.debug(`Loaded: ${resources}`)
 
+        let stages: Stage[] = []
+        try {
+            stages = (await client.getStages(apiNode.id)).item ?? []
+        } catch (err) {
+            // Test invokes still work without stages, so only the live invoke option is lost.
+            logger.warn(`Failed to load stages for API ${apiNode.name}: %O`, err)
+        }
+
         const loadScripts = ExtensionUtilities.getScriptsForHtml(['invokeRemoteRestApiVue.js'], view.webview)
         const loadLibs = ExtensionUtilities.getLibrariesForHtml(['vue.min.js'], view.webview)
 
@@ -87,6 +118,7 @@ export async function invokeRemoteRestApi(params: { outputChannel: vscode.Output
                 ApiId: apiNode.id,
                 ApiArn: apiNode.arn,
                 Resources: new Map([...resources].sort(sortResources)),
+                Stages: stages.filter(stage => stage.stageName).map(stage => stage.stageName!),
                 Scripts: loadScripts,
                 Libraries: loadLibs,
             }),
@@ -97,6 +129,7 @@ export async function invokeRemoteRestApi(params: { outputChannel: vscode.Output
             localizedMessages: {
                 noApiResource: localize('AWS.apig.remoteInvoke.noApiResource', 'Select an API Resource'),
                 noMethod: localize('AWS.apig.remoteInvoke.noMethod', 'Select a HTTP method'),
+                noPathParameter: localize('AWS.apig.remoteInvoke.noPathParameter', 'Enter a value for path parameter'),
             },
         })
 
@@ -107,6 +140,17 @@ export async function invokeRemoteRestApi(params: { outputChannel: vscode.Output
                 client: client,
                 outputChannel: params.outputChannel,
                 postMessage: message => view.webview.postMessage(message),
+                invokeStage: async ({ stageName, path, method, headers, body }) => {
+                    const credentials = await params.awsContext.getCredentials()
+                    if (!credentials) {
+                        throw new NoActiveCredentialError()
+                    }
+                    const dnsSuffix =
+                        params.regionProvider.getDnsSuffixForRegion(apiNode.regionCode) || DEFAULT_DNS_SUFFIX
+                    const url = buildDefaultApiInvokeUrl(apiNode.id, apiNode.regionCode, dnsSuffix, stageName) + path
+
+                    return await invokeStageUrl({ url, method, headers, body, credentials })
+                },
             }),
             undefined,
             ext.context.subscriptions
@@ -122,15 +166,16 @@ export function createMessageReceivedFunc({
     outputChannel,
     resources,
     postMessage,
+    invokeStage,
 }: {
     api: RestApiNode
     client: ApiGatewayClient
     outputChannel: vscode.OutputChannel
     resources: Map<string, Resource>
     postMessage: (message: any) => Thenable<boolean>
+    invokeStage: (args: StageInvokeArguments) => Promise<ApiInvokeResult>
 }) {
     const logger: Logger = getLogger()
-    let result: Result = 'Succeeded'
 
     return async (message: Command) => {
         if (isApiSelectedMessage(message)) {
@@ -142,37 +187,64 @@ export function createMessageReceivedFunc({
             postMessage({
                 command: 'setMethods',
                 methods: listValidMethods(resources, selectedResourceId),
+                pathParameters: getPathParameters(resources.get(selectedResourceId)?.path ?? ''),
             })
         } else if (isInvokeApiMessage(message)) {
+            let result: Result = 'Succeeded'
             postMessage({ command: 'invokeApiStarted' })
 
             logger.info('Invoking API Gateway resource:')
             logger.info(String(message.body))
 
-            outputChannel.show()
             outputChannel.appendLine('Loading response...')
 
-            const path = resources.get(message.selectedApiResource)?.path
-            const pathWithQueryString = path && message.queryString ? `${path}?${message.queryString}` : undefined
+            const resourcePath = resources.get(message.selectedApiResource)?.path ?? ''
+            const path = buildInvokePath(resourcePath, message.pathParameters ?? {}, message.queryString)
+            const headers = parseHeaders(message.headers ?? '')
             try {
-                const response = await client.testInvokeMethod(
-                    api.id,
-                    message.selectedApiResource,
-                    message.selectedMethod,
-                    message.body,
-                    pathWithQueryString
-                )
-
-                outputChannel.appendLine(response.log!)
-                outputChannel.appendLine('')
+                let response: ApiInvokeResult
+                if (message.stageName) {
+                    response = await invokeStage({
+                        stageName: message.stageName,
+                        path,
+                        method: message.selectedMethod,
+                        headers,
+                        body: message.body,
+                    })
+                } else {
+                    response = await client.testInvokeMethod(
+                        api.id,
+                        message.selectedApiResource,
+                        message.selectedMethod,
+                        message.body,
+                        path !== resourcePath ? path : undefined,
+                        headers
+                    )
+                }
+
+                if (response.log) {
+                    outputChannel.appendLine(response.log)
+                    outputChannel.appendLine('')
+                }
                 outputChannel.appendLine(`Request returned status: ${response.status}:`)
-                outputChannel.appendLine(response.body!)
+                outputChannel.appendLine(response.body ?? '')
+                postMessage({
+                    command: 'invokeApiResult',
+                    result: {
+                        status: response.status,
+                        latency: response.latency,
+                        headers: response.headers,
+                        body: response.body,
+                        log: response.log,
+                    },
+                })
             } catch (e) {
                 const error = e as Error
                 result = 'Failed'
                 outputChannel.appendLine(`There was an error invoking`)
                 outputChannel.appendLine(error.toString())
                 outputChannel.appendLine('')
+                postMessage({ command: 'invokeApiError', error: error.message })
             } finally {
                 postMessage({ command: 'invokeApiFinished' })
                 // only set method if it is not empty or undefined
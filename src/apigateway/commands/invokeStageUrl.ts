@@ -0,0 +1,117 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as request from 'request'
+
+export interface ApiInvokeResult {
+    status?: number
+    /** Milliseconds between sending the request and receiving the response. */
+    latency?: number
+    headers?: { [name: string]: string }
+    body?: string
+    /** Execution log, only returned by TestInvokeMethod. */
+    log?: string
+}
+
+export interface StageInvokeRequest {
+    url: string
+    method: string
+    headers: { [name: string]: string }
+    body?: string
+    credentials: AWS.Credentials
+}
+
+/**
+ * Matches path parameters of a resource path, e.g. `{petId}` or the greedy `{proxy+}`.
+ */
+const PATH_PARAMETER_PATTERN = /{([^}]+?)(\+?)}/g
+
+/**
+ * Lists the path parameters of an API Gateway resource path, e.g. `['petId']` for `/pets/{petId}`.
+ */
+export function getPathParameters(resourcePath: string): string[] {
+    const names: string[] = []
+    resourcePath.replace(PATH_PARAMETER_PATTERN, (match: string, name: string) => {
+        names.push(name)
+
+        return match
+    })
+
+    return names
+}
+
+/**
+ * Builds the path to invoke by filling in the path parameters of a resource path.
+ *
+ * Values are URI-encoded, except for the slashes of greedy (`{proxy+}`) parameters.
+ */
+export function buildInvokePath(
+    resourcePath: string,
+    pathParameters: { [name: string]: string },
+    queryString?: string
+): string {
+    const path = resourcePath.replace(PATH_PARAMETER_PATTERN, (match: string, name: string, greedy: string) => {
+        const value = pathParameters[name] ?? ''
+
+        return greedy ? value.split('/').map(encodeURIComponent).join('/') : encodeURIComponent(value)
+    })
+    const query = queryString?.replace(/^\?/, '')
+
+    return query ? `${path}?${query}` : path
+}
+
+/**
+ * Parses headers entered as one `Name: value` pair per line. Lines without a name are ignored.
+ */
+export function parseHeaders(text: string): { [name: string]: string } {
+    const headers: { [name: string]: string } = {}
+    for (const line of text.split(/\r?\n/)) {
+        const separator = line.indexOf(':')
+        const name = (separator >= 0 ? line.substring(0, separator) : line).trim()
+        if (name) {
+            headers[name] = separator >= 0 ? line.substring(separator + 1).trim() : ''
+        }
+    }
+
+    return headers
+}
+
+/**
+ * Invokes a deployed stage through its invoke URL, signing the request with SigV4 (IAM authorization).
+ */
+export async function invokeStageUrl(invokeRequest: StageInvokeRequest): Promise<ApiInvokeResult> {
+    const start = Date.now()
+
+    return await new Promise<ApiInvokeResult>((resolve, reject) => {
+        request(
+            {
+                url: invokeRequest.url,
+                method: invokeRequest.method,
+                headers: invokeRequest.headers,
+                body: invokeRequest.body || undefined,
+                aws: {
+                    key: invokeRequest.credentials.accessKeyId,
+                    secret: invokeRequest.credentials.secretAccessKey,
+                    session: invokeRequest.credentials.sessionToken,
+                    sign_version: 4,
+                    service: 'execute-api',
+                },
+            },
+            (err, response, body) => {
+                if (err) {
+                    reject(err)
+
+                    return
+                }
+
+                const headers: { [name: string]: string } = {}
+                for (const [name, value] of Object.entries(response.headers)) {
+                    headers[name] = Array.isArray(value) ? value.join(', ') : String(value)
+                }
+                resolve({ status: response.statusCode, latency: Date.now() - start, headers, body })
+            }
+        )
+    })
+}
@@ -35,18 +35,45 @@ export const APIG_REMOTE_INVOKE_TEMPLATE = `
         <select v-else>
             <option disabled value="">Select a resource first</option>
         </select>
+        <div v-if="pathParameters.length">
+            <h3>
+                Path parameters
+            </h3>
+            <div v-for="name in pathParameters" :key="name">
+                <label>{{ name }} <input type="text" v-model="pathParameterValues[name]"></label>
+            </div>
+        </div>
         <br />
         <h3>
             Query string (optional)
         </h3>
         <input type="text" v-model="queryString">
-        <br />
-        <br />
+        <h3>
+            Headers (optional, one "Name: value" per line)
+        </h3>
+        <textarea
+            rows="4"
+            cols="90"
+            v-model="headers"
+        ></textarea>
+        <h3>
+            Body
+        </h3>
         <textarea
             rows="20"
             cols="90"
             v-model="jsonInput"
         ></textarea>
+        <h3>
+            Invoke with
+        </h3>
+        <select v-model="stageName">
+            <option value="">Test invoke (TestInvokeMethod)</option>
+            <% Stages.forEach(function(stage) { %>
+                <option value="<%= stage %>">Stage <%= stage %> (invoke URL, signed with SigV4)</option>
+            <% }); %>
+        </select>
+        <br />
         <br />
         <input type="submit" v-on:click="sendInput" value="Invoke" :disabled="isLoading">
         <br />
@@ -56,6 +83,29 @@ export const APIG_REMOTE_INVOKE_TEMPLATE = `
           <li v-for="error in errors">{{ error }}</li>
         </ul>
         </p>
+        <div v-if="invokeError">
+            <h3>Error</h3>
+            <pre>{{ invokeError }}</pre>
+        </div>
+        <div v-if="response">
+            <h3>
+                Response
+            </h3>
+            <p>
+                Status: <b>{{ response.status }}</b>
+                <span v-if="response.latency !== undefined"> | Latency: {{ response.latency }} ms</span>
+            </p>
+            <details v-if="response.headers">
+                <summary>Headers</summary>
+                <pre><span v-for="(value, name) in response.headers">{{ name }}: {{ value }}\n</span></pre>
+            </details>
+            <h4>Body</h4>
+            <pre>{{ response.body }}</pre>
+            <div v-if="response.log">
+                <h4>Log</h4>
+                <pre>{{ response.log }}</pre>
+            </div>
+        </div>
     </div>
     <% Libraries.forEach(function(lib) { %>
         <script src="<%= lib %>"></script>
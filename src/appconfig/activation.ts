@@ -0,0 +1,27 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { editConfiguration } from './commands/editConfiguration'
+import { startDeployment } from './commands/startDeployment'
+import { viewConfiguration } from './commands/viewConfiguration'
+import { AppConfigProfileNode } from './explorer/appConfigProfileNode'
+
+/**
+ * Activates AppConfig components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.appConfig.viewConfiguration', async (node: AppConfigProfileNode) => {
+            await viewConfiguration(node)
+        }),
+        vscode.commands.registerCommand('aws.appConfig.editConfiguration', async (node: AppConfigProfileNode) => {
+            await editConfiguration(node)
+        }),
+        vscode.commands.registerCommand('aws.appConfig.startDeployment', async (node: AppConfigProfileNode) => {
+            await startDeployment(node)
+        })
+    )
+}
@@ -0,0 +1,76 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+import * as yaml from 'js-yaml'
+import { toArrayAsync } from '../shared/utilities/collectionUtils'
+import { localize } from '../shared/utilities/vsCodeUtils'
+import { AppConfigProfileNode } from './explorer/appConfigProfileNode'
+
+export const DEFAULT_CONTENT_TYPE = 'application/json'
+
+/**
+ * Gets the language to show configuration content with, from its content type, e.g. `application/x-yaml`.
+ */
+export function getLanguageId(contentType: string | undefined): string {
+    const type = (contentType ?? '').toLowerCase()
+    if (type.includes('json')) {
+        return 'json'
+    }
+    if (type.includes('yaml')) {
+        return 'yaml'
+    }
+
+    return 'plaintext'
+}
+
+/**
+ * Checks that configuration content can be parsed as its content type, so that a typo isn't deployed.
+ *
+ * @returns an error message, or undefined if the content is valid (or of a type that isn't checked)
+ */
+export function validateContent(content: string, contentType: string | undefined): string | undefined {
+    const language = getLanguageId(contentType)
+    try {
+        if (language === 'json') {
+            JSON.parse(content)
+        } else if (language === 'yaml') {
+            yaml.load(content)
+        }
+
+        return undefined
+    } catch (err) {
+        return localize(
+            'AWS.appConfig.invalidContent',
+            'The configuration is not valid {0}: {1}',
+            language.toUpperCase(),
+            (err as Error).message
+        )
+    }
+}
+
+export function getContentText(version: AppConfig.HostedConfigurationVersion): string {
+    const content = version.Content ?? ''
+
+    return typeof content === 'string' ? content : Buffer.from(content as Uint8Array).toString('utf8')
+}
+
+/**
+ * Gets the latest version of a hosted configuration, with its content.
+ *
+ * @returns the version, or undefined if the profile has no versions yet
+ */
+export async function getLatestHostedVersion(
+    node: AppConfigProfileNode
+): Promise<AppConfig.HostedConfigurationVersion | undefined> {
+    const { appConfig, id: applicationId } = node.application
+    const versions = await toArrayAsync(appConfig.listHostedConfigurationVersions(applicationId, node.id))
+    const latest = Math.max(0, ...versions.map(version => version.VersionNumber ?? 0))
+    if (!latest) {
+        return undefined
+    }
+
+    return await appConfig.getHostedConfigurationVersion(applicationId, node.id, latest)
+}
@@ -0,0 +1,148 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import { recordAppconfigCreateConfigurationVersion, Result } from '../../shared/telemetry/telemetry'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { DEFAULT_CONTENT_TYPE, getContentText, getLanguageId, validateContent } from '../appConfigContent'
+import { AppConfigProfileNode } from '../explorer/appConfigProfileNode'
+import { loadLatestVersion, ShowDocument, showUntitledDocument } from './viewConfiguration'
+
+/** Returned by CreateHostedConfigurationVersion when the latest version number given is out of date. */
+const VERSION_CONFLICT_CODE = 'PreconditionFailedException'
+
+/**
+ * Opens the latest configuration of a freeform profile for editing, and creates a new hosted version
+ * from the edited content when the user asks to. Creating a version doesn't deploy it.
+ */
+export async function editConfiguration(
+    node: AppConfigProfileNode,
+    window = Window.vscode(),
+    commands = Commands.vscode(),
+    showDocument: ShowDocument = showUntitledDocument
+): Promise<void> {
+    getLogger().debug('EditConfiguration called for %s', node.id)
+    let result: Result = 'Succeeded'
+
+    try {
+        const loaded = await loadLatestVersion(node, window)
+        if (!loaded) {
+            result = 'Failed'
+            return
+        }
+
+        const latestVersionNumber = loaded.latest?.VersionNumber
+        const contentType = loaded.latest?.ContentType ?? DEFAULT_CONTENT_TYPE
+        const originalContent = loaded.latest ? getContentText(loaded.latest) : '{}'
+        const document = await showDocument(originalContent, getLanguageId(contentType))
+
+        const createVersion = localize('AWS.appConfig.editConfiguration.createVersion', 'Create Version')
+        const response = await window.showInformationMessage(
+            localize(
+                'AWS.appConfig.editConfiguration.prompt',
+                'Edit the configuration of {0}, then choose {1} to save it as a new version.',
+                node.name,
+                createVersion
+            ),
+            createVersion
+        )
+        if (response !== createVersion || document.isClosed) {
+            result = 'Cancelled'
+            getLogger().info('EditConfiguration cancelled')
+            return
+        }
+
+        const content = document.getText()
+        const validationError = validateContent(content, contentType)
+        if (validationError) {
+            result = 'Failed'
+            window.showErrorMessage(validationError)
+            return
+        }
+        if (loaded.latest && content === originalContent) {
+            result = 'Cancelled'
+            window.showInformationMessage(
+                localize(
+                    'AWS.appConfig.editConfiguration.unchanged',
+                    'The configuration of {0} is unchanged; no version was created.',
+                    node.name
+                )
+            )
+            return
+        }
+
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.appConfig.editConfiguration.confirm',
+                    "Create version {0} of {1}? Deployed configurations don't change until you start a deployment.",
+                    (latestVersionNumber ?? 0) + 1,
+                    node.name
+                ),
+                confirm: createVersion,
+                cancel: localize('AWS.generic.cancel', 'Cancel'),
+            },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('EditConfiguration cancelled')
+            return
+        }
+
+        try {
+            const version = await node.application.appConfig.createHostedConfigurationVersion({
+                applicationId: node.application.id,
+                profileId: node.id,
+                content,
+                contentType,
+                latestVersionNumber,
+            })
+            getLogger().info('Created version %s of profile %s', version.VersionNumber, node.id)
+
+            const startDeployment = localize('AWS.appConfig.startDeployment.button', 'Start Deployment')
+            const selection = await window.showInformationMessage(
+                localize(
+                    'AWS.appConfig.editConfiguration.success',
+                    'Created version {0} of {1}',
+                    version.VersionNumber,
+                    node.name
+                ),
+                startDeployment
+            )
+            if (selection === startDeployment) {
+                await commands.execute('aws.appConfig.startDeployment', node)
+            }
+        } catch (err) {
+            result = 'Failed'
+            if ((err as { code?: string }).code === VERSION_CONFLICT_CODE) {
+                window.showErrorMessage(
+                    localize(
+                        'AWS.appConfig.editConfiguration.conflict',
+                        'The configuration of {0} changed since it was opened. Edit the latest version and try again.',
+                        node.name
+                    )
+                )
+                return
+            }
+
+            getLogger().error('Failed to create a version of profile %s: %s', node.id, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.appConfig.editConfiguration.failure',
+                    'Failed to create a version of {0}: {1}',
+                    node.name,
+                    (err as Error).message
+                ),
+                window
+            )
+        }
+    } finally {
+        recordAppconfigCreateConfigurationVersion({ result })
+    }
+}
@@ -0,0 +1,282 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordAppconfigStartDeployment, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { AppConfigProfileNode } from '../explorer/appConfigProfileNode'
+
+const DEPLOYMENT_POLL_INTERVAL_MS = 5000
+
+/** Deployment states after which the deployment no longer changes. */
+const FINAL_DEPLOYMENT_STATES = ['COMPLETE', 'ROLLED_BACK']
+
+interface ValueItem<T> extends vscode.QuickPickItem {
+    value: T
+}
+
+export interface StartDeploymentOptions {
+    window?: Window
+    promptUserFunction?: typeof promptUser
+    pollIntervalMs?: number
+}
+
+/**
+ * Deploys a version of a configuration profile to an environment with a deployment strategy,
+ * after the user confirms, then follows the deployment until it completes or rolls back.
+ *
+ * Cancelling the progress notification stops following the deployment; the deployment itself continues.
+ */
+export async function startDeployment(
+    node: AppConfigProfileNode,
+    {
+        window = Window.vscode(),
+        promptUserFunction = promptUser,
+        pollIntervalMs = DEPLOYMENT_POLL_INTERVAL_MS,
+    }: StartDeploymentOptions = {}
+): Promise<void> {
+    getLogger().debug('StartDeployment called for %s', node.id)
+    const { appConfig, id: applicationId } = node.application
+    let result: Result = 'Succeeded'
+
+    try {
+        const environments = await node.application.listEnvironments()
+        if (environments.length === 0) {
+            result = 'Failed'
+            window.showErrorMessage(
+                localize(
+                    'AWS.appConfig.startDeployment.noEnvironments',
+                    'Application {0} has no environments to deploy to',
+                    node.application.name
+                )
+            )
+            return
+        }
+
+        const environment = await pick(
+            localize('AWS.appConfig.startDeployment.environment', 'Choose the environment to deploy {0} to', node.name),
+            environments.map(env => ({ label: env.Name ?? '', description: env.State, value: env })),
+            promptUserFunction
+        )
+        if (!environment) {
+            result = 'Cancelled'
+            getLogger().info('StartDeployment cancelled')
+            return
+        }
+
+        const strategies = await toArrayAsync(appConfig.listDeploymentStrategies())
+        const strategy = await pick(
+            localize('AWS.appConfig.startDeployment.strategy', 'Choose a deployment strategy'),
+            strategies.map(item => ({
+                label: item.Name ?? '',
+                description: item.Description,
+                detail: describeStrategy(item),
+                value: item,
+            })),
+            promptUserFunction
+        )
+        if (!strategy) {
+            result = 'Cancelled'
+            getLogger().info('StartDeployment cancelled')
+            return
+        }
+
+        const configurationVersion = await pickVersion(node, window, promptUserFunction)
+        if (!configurationVersion) {
+            result = 'Cancelled'
+            getLogger().info('StartDeployment cancelled')
+            return
+        }
+
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.appConfig.startDeployment.confirm',
+                    'Deploy version {0} of {1} to environment {2} of {3} with strategy {4}?',
+                    configurationVersion,
+                    node.name,
+                    environment.Name,
+                    node.application.name,
+                    strategy.Name
+                ),
+                confirm: localize('AWS.appConfig.startDeployment.confirmButton', 'Deploy'),
+                cancel: localize('AWS.generic.cancel', 'Cancel'),
+            },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('StartDeployment cancelled')
+            return
+        }
+
+        try {
+            const environmentId = environment.Id ?? ''
+            const deployment = await appConfig.startDeployment({
+                applicationId,
+                environmentId,
+                profileId: node.id,
+                strategyId: strategy.Id ?? '',
+                configurationVersion,
+            })
+            getLogger().info('Started deployment %s of profile %s', deployment.DeploymentNumber, node.id)
+
+            const final = await window.withProgress(
+                {
+                    location: vscode.ProgressLocation.Notification,
+                    title: localize(
+                        'AWS.appConfig.startDeployment.progress',
+                        'Deploying {0} to {1}...',
+                        node.name,
+                        environment.Name
+                    ),
+                    cancellable: true,
+                },
+                async (progress, token) => {
+                    let current = deployment
+                    while (!FINAL_DEPLOYMENT_STATES.includes(current.State ?? '')) {
+                        if (token.isCancellationRequested) {
+                            return undefined
+                        }
+                        progress.report({
+                            message: localize(
+                                'AWS.appConfig.startDeployment.state',
+                                '{0} ({1}%)',
+                                current.State,
+                                Math.round(current.PercentageComplete ?? 0)
+                            ),
+                        })
+
+                        await new Promise(resolve => setTimeout(resolve, pollIntervalMs))
+                        current = await appConfig.getDeployment(
+                            applicationId,
+                            environmentId,
+                            deployment.DeploymentNumber ?? 0
+                        )
+                    }
+
+                    return current
+                }
+            )
+            if (!final) {
+                getLogger().info('Stopped following deployment %s', deployment.DeploymentNumber)
+                return
+            }
+            if (final.State === 'ROLLED_BACK') {
+                result = 'Failed'
+                window.showErrorMessage(
+                    localize(
+                        'AWS.appConfig.startDeployment.rolledBack',
+                        'Deployment of {0} to {1} was rolled back',
+                        node.name,
+                        environment.Name
+                    )
+                )
+                return
+            }
+
+            window.showInformationMessage(
+                localize(
+                    'AWS.appConfig.startDeployment.success',
+                    'Deployed version {0} of {1} to {2}',
+                    configurationVersion,
+                    node.name,
+                    environment.Name
+                )
+            )
+        } catch (err) {
+            result = 'Failed'
+            getLogger().error('Failed to deploy profile %s: %s', node.id, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.appConfig.startDeployment.failure',
+                    'Failed to deploy {0} to {1}: {2}',
+                    node.name,
+                    environment.Name,
+                    (err as Error).message
+                ),
+                window
+            )
+        }
+    } finally {
+        recordAppconfigStartDeployment({ result })
+    }
+}
+
+async function pick<T>(
+    title: string,
+    items: ValueItem<T>[],
+    promptUserFunction: typeof promptUser
+): Promise<T | undefined> {
+    const picker = createQuickPick<ValueItem<T>>({ options: { ignoreFocusOut: true, title }, items })
+
+    return verifySinglePickerOutput(await promptUserFunction({ picker }))?.value
+}
+
+/**
+ * Picks a hosted configuration version, newest first. Configurations stored elsewhere are versioned by their store,
+ * e.g. an S3 object version, so their version is entered instead.
+ */
+async function pickVersion(
+    node: AppConfigProfileNode,
+    window: Window,
+    promptUserFunction: typeof promptUser
+): Promise<string | undefined> {
+    if (!node.hosted) {
+        return await window.showInputBox({
+            prompt: localize(
+                'AWS.appConfig.startDeployment.versionPrompt',
+                'Enter the version of the configuration in {0} to deploy',
+                node.locationUri
+            ),
+            ignoreFocusOut: true,
+            validateInput: input =>
+                input.trim()
+                    ? undefined
+                    : localize('AWS.appConfig.startDeployment.versionEmpty', 'Version must not be empty'),
+        })
+    }
+
+    const { appConfig, id: applicationId } = node.application
+    const versions = await toArrayAsync(appConfig.listHostedConfigurationVersions(applicationId, node.id))
+    if (versions.length === 0) {
+        window.showErrorMessage(
+            localize('AWS.appConfig.noVersions', 'Configuration profile {0} has no versions yet', node.name)
+        )
+        return undefined
+    }
+
+    const version = await pick(
+        localize('AWS.appConfig.startDeployment.version', 'Choose the version of {0} to deploy', node.name),
+        versions
+            .sort((a, b) => (b.VersionNumber ?? 0) - (a.VersionNumber ?? 0))
+            .map(item => ({
+                label: String(item.VersionNumber),
+                description: item.ContentType,
+                detail: item.Description,
+                value: item,
+            })),
+        promptUserFunction
+    )
+
+    return version ? String(version.VersionNumber) : undefined
+}
+
+function describeStrategy(strategy: AppConfig.DeploymentStrategy): string {
+    return localize(
+        'AWS.appConfig.startDeployment.strategyDetail',
+        'Deploys over {0} minutes, growing by {1}% ({2}), then bakes for {3} minutes',
+        strategy.DeploymentDurationInMinutes ?? 0,
+        strategy.GrowthFactor ?? 0,
+        (strategy.GrowthType ?? 'LINEAR').toLowerCase(),
+        strategy.FinalBakeTimeInMinutes ?? 0
+    )
+}
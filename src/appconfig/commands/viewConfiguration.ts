@@ -0,0 +1,95 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordAppconfigViewConfiguration, Result } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { getContentText, getLanguageId, getLatestHostedVersion } from '../appConfigContent'
+import { AppConfigProfileNode } from '../explorer/appConfigProfileNode'
+
+export type ShowDocument = (content: string, language: string) => Promise<vscode.TextDocument>
+
+export const showUntitledDocument: ShowDocument = async (content, language) => {
+    const document = await vscode.workspace.openTextDocument({ language, content })
+    await vscode.window.showTextDocument(document)
+
+    return document
+}
+
+/**
+ * Loads the latest version of a hosted configuration, telling the user if it can't be.
+ *
+ * @returns undefined if the configuration could not be loaded, after notifying the user.
+ * Otherwise `latest` holds the latest version, or is undefined if the profile has no versions yet.
+ */
+export async function loadLatestVersion(
+    node: AppConfigProfileNode,
+    window: Window
+): Promise<{ latest?: AppConfig.HostedConfigurationVersion } | undefined> {
+    if (!node.hosted) {
+        window.showInformationMessage(
+            localize(
+                'AWS.appConfig.notHosted',
+                'The configuration of {0} is stored in {1}. Only configurations in the AppConfig hosted configuration store can be viewed.',
+                node.name,
+                node.locationUri
+            )
+        )
+
+        return undefined
+    }
+
+    try {
+        return { latest: await getLatestHostedVersion(node) }
+    } catch (err) {
+        getLogger().error('Failed to get the configuration of profile %s: %s', node.id, (err as Error).message)
+        showErrorWithLogs(
+            localize(
+                'AWS.appConfig.getConfiguration.failure',
+                'Failed to get the configuration of {0}: {1}',
+                node.name,
+                (err as Error).message
+            ),
+            window
+        )
+
+        return undefined
+    }
+}
+
+/**
+ * Shows the content of the latest hosted version of a configuration profile, highlighted as its content type.
+ */
+export async function viewConfiguration(
+    node: AppConfigProfileNode,
+    window = Window.vscode(),
+    showDocument: ShowDocument = showUntitledDocument
+): Promise<void> {
+    getLogger().debug('ViewConfiguration called for %s', node.id)
+    let result: Result = 'Succeeded'
+
+    try {
+        const loaded = await loadLatestVersion(node, window)
+        if (!loaded) {
+            result = 'Failed'
+            return
+        }
+        if (!loaded.latest) {
+            result = 'Cancelled'
+            window.showInformationMessage(
+                localize('AWS.appConfig.noVersions', 'Configuration profile {0} has no versions yet', node.name)
+            )
+            return
+        }
+
+        await showDocument(getContentText(loaded.latest), getLanguageId(loaded.latest.ContentType))
+    } finally {
+        recordAppconfigViewConfiguration({ result })
+    }
+}
@@ -0,0 +1,102 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { AppConfigClient } from '../../shared/clients/appConfigClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { AppConfigEnvironmentNode } from './appConfigEnvironmentNode'
+import { AppConfigNode } from './appConfigNode'
+import { AppConfigProfileNode } from './appConfigProfileNode'
+
+/**
+ * An AppConfig application, with its environments and configuration profiles in separate folders.
+ */
+export class AppConfigApplicationNode extends AWSTreeNodeBase {
+    public readonly id: string
+    public readonly name: string
+
+    public constructor(
+        public readonly parent: AppConfigNode,
+        public readonly appConfig: AppConfigClient,
+        application: AppConfig.Application
+    ) {
+        super(application.Name ?? '', vscode.TreeItemCollapsibleState.Collapsed)
+        this.id = application.Id ?? ''
+        this.name = application.Name ?? this.id
+        this.tooltip = application.Description ? `${this.name}\n${application.Description}` : this.name
+        this.contextValue = 'awsAppConfigApplicationNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return [new AppConfigEnvironmentsNode(this), new AppConfigProfilesNode(this)]
+    }
+
+    /**
+     * Lists the environments of the application, sorted by name.
+     */
+    public async listEnvironments(): Promise<AppConfig.Environment[]> {
+        const environments = await toArrayAsync(this.appConfig.listEnvironments(this.id))
+
+        return environments.sort((a, b) => (a.Name ?? '').localeCompare(b.Name ?? ''))
+    }
+}
+
+export class AppConfigEnvironmentsNode extends AWSTreeNodeBase {
+    public constructor(public readonly parent: AppConfigApplicationNode) {
+        super(
+            localize('AWS.explorerNode.appConfig.environments', 'Environments'),
+            vscode.TreeItemCollapsibleState.Collapsed
+        )
+        this.contextValue = 'awsAppConfigEnvironmentsNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () =>
+                (await this.parent.listEnvironments()).map(
+                    environment => new AppConfigEnvironmentNode(this.parent, environment)
+                ),
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(
+                    this,
+                    localize('AWS.explorerNode.appConfig.noEnvironments', '[No environments found]')
+                ),
+        })
+    }
+}
+
+export class AppConfigProfilesNode extends AWSTreeNodeBase {
+    public constructor(public readonly parent: AppConfigApplicationNode) {
+        super(
+            localize('AWS.explorerNode.appConfig.profiles', 'Configuration Profiles'),
+            vscode.TreeItemCollapsibleState.Collapsed
+        )
+        this.contextValue = 'awsAppConfigProfilesNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const profiles = await toArrayAsync(this.parent.appConfig.listConfigurationProfiles(this.parent.id))
+
+                return profiles.map(profile => new AppConfigProfileNode(this.parent, profile))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(
+                    this,
+                    localize('AWS.explorerNode.appConfig.noProfiles', '[No configuration profiles found]')
+                ),
+            sort: (item1: AppConfigProfileNode, item2: AppConfigProfileNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+}
@@ -0,0 +1,26 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { AppConfigApplicationNode } from './appConfigApplicationNode'
+
+/**
+ * An environment of an AppConfig application, described by its state, e.g. `ReadyForDeployment`.
+ */
+export class AppConfigEnvironmentNode extends AWSTreeNodeBase {
+    public readonly id: string
+    public readonly name: string
+
+    public constructor(public readonly application: AppConfigApplicationNode, environment: AppConfig.Environment) {
+        super(environment.Name ?? '', vscode.TreeItemCollapsibleState.None)
+        this.id = environment.Id ?? ''
+        this.name = environment.Name ?? this.id
+        this.description = environment.State
+        this.tooltip = environment.Description ? `${this.name}\n${environment.Description}` : this.name
+        this.contextValue = 'awsAppConfigEnvironmentNode'
+    }
+}
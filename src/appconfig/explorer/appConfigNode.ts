@@ -0,0 +1,49 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { AppConfigClient } from '../../shared/clients/appConfigClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { AppConfigApplicationNode } from './appConfigApplicationNode'
+
+/**
+ * An AWS Explorer node representing AppConfig.
+ *
+ * Contains applications for a specific region as child nodes.
+ */
+export class AppConfigNode extends AWSTreeNodeBase {
+    public constructor(private readonly appConfig: AppConfigClient) {
+        super('AppConfig', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsAppConfigNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const applications = await toArrayAsync(this.appConfig.listApplications())
+
+                return applications.map(application => new AppConfigApplicationNode(this, this.appConfig, application))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(
+                    this,
+                    localize('AWS.explorerNode.appConfig.noApplications', '[No applications found]')
+                ),
+            sort: (item1: AppConfigApplicationNode, item2: AppConfigApplicationNode) =>
+                item1.name.localeCompare(item2.name),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'AppConfigNode'
+    }
+}
@@ -0,0 +1,54 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { AppConfigApplicationNode } from './appConfigApplicationNode'
+
+/** The location of configurations that are stored in the AppConfig hosted configuration store. */
+export const HOSTED_LOCATION_URI = 'hosted'
+
+export const FREEFORM_PROFILE_TYPE = 'AWS.Freeform'
+
+/** Profiles have a type since feature flags were added; older profiles, and older SDKs, have none. */
+type ConfigurationProfileSummary = AppConfig.ConfigurationProfileSummary & { Type?: string }
+
+/**
+ * A configuration profile of an AppConfig application.
+ *
+ * The configuration of freeform profiles in the hosted configuration store can be edited; other profiles
+ * can only be deployed.
+ */
+export class AppConfigProfileNode extends AWSTreeNodeBase {
+    public readonly id: string
+    public readonly name: string
+    public readonly locationUri: string
+    public readonly type: string
+
+    public constructor(public readonly application: AppConfigApplicationNode, profile: ConfigurationProfileSummary) {
+        super(profile.Name ?? '', vscode.TreeItemCollapsibleState.None)
+        this.id = profile.Id ?? ''
+        this.name = profile.Name ?? this.id
+        this.locationUri = profile.LocationUri ?? ''
+        this.description = this.hosted
+            ? localize('AWS.explorerNode.appConfig.hosted', 'hosted')
+            : this.locationUri.split(':')[0]
+        this.tooltip = `${this.name}\n${this.locationUri}`
+        this.type = profile.Type ?? FREEFORM_PROFILE_TYPE
+        // only freeform configurations in the hosted store can be edited
+        this.contextValue = this.editable ? 'awsAppConfigFreeformProfileNode' : 'awsAppConfigProfileNode'
+    }
+
+    /** Whether the configuration is in the hosted configuration store, rather than e.g. in S3 or Parameter Store. */
+    public get hosted(): boolean {
+        return this.locationUri === HOSTED_LOCATION_URI
+    }
+
+    public get editable(): boolean {
+        return this.hosted && this.type === FREEFORM_PROFILE_TYPE
+    }
+}
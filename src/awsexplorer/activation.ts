@@ -7,8 +7,16 @@ import * as vscode from 'vscode'
 import { loginWithMostRecentCredentials } from '../credentials/activation'
 import { LoginManager } from '../credentials/loginManager'
 import { submitFeedback } from '../feedback/commands/submitFeedback'
+import { generatePolicyFromCloudTrail } from '../iam/commands/generatePolicyFromCloudTrail'
 import { deleteCloudFormation } from '../lambda/commands/deleteCloudFormation'
+import {
+    CLOUDFORMATION_DRIFT_SCHEME,
+    detectStackDrift,
+    showStackDrift,
+    StackDriftDocumentProvider,
+} from '../lambda/commands/detectStackDrift'
 import { CloudFormationStackNode } from '../lambda/explorer/cloudFormationNodes'
+import { viewStackEventsCommand } from '../lambda/vue/stackEvents'
 import { AwsContext } from '../shared/awsContext'
 import { AwsContextTreeCollection } from '../shared/awsContextTreeCollection'
 import { ext } from '../shared/extensionGlobals'
@@ -28,14 +36,20 @@ import { LoadMoreNode } from '../shared/treeview/nodes/loadMoreNode'
 import { downloadStateMachineDefinition } from '../stepFunctions/commands/downloadStateMachineDefinition'
 import { executeStateMachine } from '../stepFunctions/commands/executeStateMachine'
 import { StateMachineNode } from '../stepFunctions/explorer/stepFunctionsNodes'
+import { viewExecutionHistoryCommand } from '../stepFunctions/vue/executionHistory'
 import { AwsExplorer } from './awsExplorer'
 import { copyArnCommand } from './commands/copyArn'
 import { copyNameCommand } from './commands/copyName'
+import { editTagsCommand } from './commands/editTags'
+import { clearExplorerFilterCommand, filterExplorerCommand } from './commands/filterExplorer'
 import { loadMoreChildrenCommand } from './commands/loadMoreChildren'
 import { checkExplorerForDefaultRegion } from './defaultRegion'
 import { RegionNode } from './regionNode'
 import { extensionSettingsPrefix } from '../shared/constants'
-import { CredentialsStore } from '../credentials/credentialsStore'
+import { FavoritesStore } from './favorites'
+import { FavoritesExplorer } from './favoritesExplorer'
+import { FavoriteNode } from './favoriteNode'
+import { moveFavoriteCommand, pinFavoriteCommand, unpinFavoriteCommand } from './commands/favorites'
 
 let didTryAutoConnect = false
 
@@ -48,12 +62,14 @@ export async function activate(args: {
     regionProvider: RegionProvider
     toolkitOutputChannel: vscode.OutputChannel
     remoteInvokeOutputChannel: vscode.OutputChannel
+    loginManager: LoginManager
 }): Promise<void> {
     const awsExplorer = new AwsExplorer(ext.context, args.awsContext, args.regionProvider)
 
     const view = vscode.window.createTreeView(awsExplorer.viewProviderId, {
         treeDataProvider: awsExplorer,
         showCollapseAll: true,
+        canSelectMany: true,
     })
     ext.context.subscriptions.push(view)
 
@@ -64,8 +80,7 @@ export async function activate(args: {
             if (!didTryAutoConnect && e.visible && !(await args.awsContext.getCredentials())) {
                 didTryAutoConnect = true
                 const toolkitSettings = new DefaultSettingsConfiguration(extensionSettingsPrefix)
-                const loginManager = new LoginManager(args.awsContext, new CredentialsStore())
-                await loginWithMostRecentCredentials(toolkitSettings, loginManager)
+                await loginWithMostRecentCredentials(toolkitSettings, args.loginManager)
             }
         })
     )
@@ -75,6 +90,44 @@ export async function activate(args: {
     args.awsContextTrees.addTree(awsExplorer)
 
     updateAwsExplorerWhenAwsContextCredentialsChange(awsExplorer, args.awsContext, ext.context)
+
+    activateFavorites(ext.context, args.awsContext)
+}
+
+/**
+ * Activates the Favorites view, which lists resources pinned from the AWS Explorer.
+ */
+function activateFavorites(context: vscode.ExtensionContext, awsContext: AwsContext): void {
+    const store = new FavoritesStore(context.globalState)
+    const favoritesExplorer = new FavoritesExplorer(store, awsContext)
+
+    context.subscriptions.push(
+        vscode.window.createTreeView(favoritesExplorer.viewProviderId, {
+            treeDataProvider: favoritesExplorer,
+            showCollapseAll: true,
+        }),
+        store.onDidChange(() => favoritesExplorer.refresh()),
+        // favorites of the account that was disconnected become unavailable, and those of the new one available
+        awsContext.onDidChangeContext(() => favoritesExplorer.refresh()),
+        vscode.commands.registerCommand(
+            'aws.favorites.pin',
+            async (node: AWSTreeNodeBase, selectedNodes?: AWSTreeNodeBase[]) =>
+                await pinFavoriteCommand(node, selectedNodes, store, awsContext)
+        ),
+        vscode.commands.registerCommand(
+            'aws.favorites.unpin',
+            async (node: FavoriteNode) => await unpinFavoriteCommand(node, store)
+        ),
+        vscode.commands.registerCommand(
+            'aws.favorites.moveUp',
+            async (node: FavoriteNode) => await moveFavoriteCommand(node, store, -1)
+        ),
+        vscode.commands.registerCommand(
+            'aws.favorites.moveDown',
+            async (node: FavoriteNode) => await moveFavoriteCommand(node, store, 1)
+        ),
+        vscode.commands.registerCommand('aws.favorites.refresh', async () => favoritesExplorer.refresh())
+    )
 }
 
 async function registerAwsExplorerCommands(
@@ -104,6 +157,13 @@ async function registerAwsExplorerCommands(
         })
     )
 
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.iam.generatePolicyFromCloudTrail',
+            async (node: RegionNode) => await generatePolicyFromCloudTrail(node)
+        )
+    )
+
     let submitFeedbackPanel: vscode.WebviewPanel | undefined
     context.subscriptions.push(
         vscode.commands.registerCommand('aws.submitFeedback', () => {
@@ -125,7 +185,12 @@ async function registerAwsExplorerCommands(
 
     context.subscriptions.push(
         vscode.commands.registerCommand('aws.refreshAwsExplorer', async (passive: boolean = false) => {
-            awsExplorer.refresh()
+            if (!passive && awsExplorer.filter !== undefined) {
+                // setFilter() refreshes the explorer
+                awsExplorer.setFilter(undefined)
+            } else {
+                awsExplorer.refresh()
+            }
 
             if (!passive) {
                 recordAwsRefreshExplorer()
@@ -133,6 +198,14 @@ async function registerAwsExplorerCommands(
         })
     )
 
+    context.subscriptions.push(
+        vscode.commands.registerCommand('aws.filterAwsExplorer', async () => await filterExplorerCommand(awsExplorer)),
+        vscode.commands.registerCommand(
+            'aws.clearAwsExplorerFilter',
+            async () => await clearExplorerFilterCommand(awsExplorer)
+        )
+    )
+
     context.subscriptions.push(
         vscode.commands.registerCommand(
             'aws.deleteCloudFormation',
@@ -141,6 +214,25 @@ async function registerAwsExplorerCommands(
         )
     )
 
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.detectCloudFormationDrift',
+            async (node: CloudFormationStackNode) => await detectStackDrift(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.showCloudFormationDrift',
+            async (node: CloudFormationStackNode) => await showStackDrift(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.viewCloudFormationStackEvents',
+            async (node: CloudFormationStackNode) => await viewStackEventsCommand(node)
+        ),
+        vscode.workspace.registerTextDocumentContentProvider(
+            CLOUDFORMATION_DRIFT_SCHEME,
+            new StackDriftDocumentProvider()
+        )
+    )
+
     context.subscriptions.push(
         vscode.commands.registerCommand(
             'aws.downloadStateMachineDefinition',
@@ -163,6 +255,13 @@ async function registerAwsExplorerCommands(
         )
     )
 
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.viewExecutionHistory',
+            async (node: StateMachineNode) => await viewExecutionHistoryCommand(node)
+        )
+    )
+
     context.subscriptions.push(
         vscode.commands.registerCommand('aws.copyArn', async (node: AWSResourceNode) => await copyArnCommand(node))
     )
@@ -171,6 +270,14 @@ async function registerAwsExplorerCommands(
         vscode.commands.registerCommand('aws.copyName', async (node: AWSResourceNode) => await copyNameCommand(node))
     )
 
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.editTags',
+            async (node: AWSTreeNodeBase, selectedNodes?: AWSTreeNodeBase[]) =>
+                await editTagsCommand(node, selectedNodes)
+        )
+    )
+
     context.subscriptions.push(
         vscode.commands.registerCommand('aws.refreshAwsExplorerNode', async (element: AWSTreeNodeBase | undefined) => {
             awsExplorer.refresh(element)
@@ -3,6 +3,7 @@
  * SPDX-License-Identifier: Apache-2.0
  */
 
+import * as path from 'path'
 import * as vscode from 'vscode'
 import { AwsContext } from '../shared/awsContext'
 import { getIdeProperties } from '../shared/extensionUtilities'
@@ -15,14 +16,24 @@ import { AWSTreeNodeBase } from '../shared/treeview/nodes/awsTreeNodeBase'
 import { makeChildrenNodes } from '../shared/treeview/treeNodeUtilities'
 import { intersection, toMap, updateInPlace } from '../shared/utilities/collectionUtils'
 import { localize } from '../shared/utilities/vsCodeUtils'
+import { CachedNode, ExplorerCache, getChildKey, getLoadError } from './explorerCache'
+import { filterChildNodes } from './explorerFilter'
 import { RegionNode } from './regionNode'
 
+const FILTERED_CONTEXT_KEY = 'aws.explorer.filtered'
+
 export class AwsExplorer implements vscode.TreeDataProvider<AWSTreeNodeBase>, RefreshableAwsTreeProvider {
     public viewProviderId: string = 'aws.explorer'
     public readonly onDidChangeTreeData: vscode.Event<AWSTreeNodeBase | undefined>
     private readonly logger: Logger = getLogger()
     private readonly _onDidChangeTreeData: vscode.EventEmitter<AWSTreeNodeBase | undefined>
     private readonly regionNodes: Map<string, RegionNode>
+    /** Service nodes (the children of region nodes), whose resources are filtered. */
+    private readonly serviceNodes = new WeakSet<AWSTreeNodeBase>()
+    /** Keys of the nodes in {@link cache}, which identify them across sessions. */
+    private readonly nodeKeys = new WeakMap<AWSTreeNodeBase, string>()
+    private readonly cache: ExplorerCache
+    private filterText: string | undefined
 
     private readonly ROOT_NODE_SIGN_IN = new AWSCommandTreeNode(
         undefined,
@@ -48,6 +59,8 @@ export class AwsExplorer implements vscode.TreeDataProvider<AWSTreeNodeBase>, Re
         this._onDidChangeTreeData = new vscode.EventEmitter<AWSTreeNodeBase | undefined>()
         this.onDidChangeTreeData = this._onDidChangeTreeData.event
         this.regionNodes = new Map<string, RegionNode>()
+        // TODO: 'globalStoragePath' is deprecated in later versions of VS Code, use 'globalStorageUri' when min >= 1.48
+        this.cache = new ExplorerCache(path.join(this.extContext.globalStoragePath, 'explorerCache.json'))
         this.extContext.subscriptions.push(
             this.awsContext.onDidChangeContext(e => {
                 if (!e.accountId) {
@@ -74,17 +87,36 @@ export class AwsExplorer implements vscode.TreeDataProvider<AWSTreeNodeBase>, Re
 
     public async getChildren(element?: AWSTreeNodeBase): Promise<AWSTreeNodeBase[]> {
         let childNodes: AWSTreeNodeBase[] = []
+        // nodes are cached per account, so nothing is cached while no account is connected
+        const key = element ? this.nodeKeys.get(element) : this.awsContext.getCredentialAccountId()
 
         try {
-            if (element) {
+            if (element && this.serviceNodes.has(element)) {
+                childNodes = childNodes.concat(await this.getFilteredChildren(element))
+            } else if (element) {
                 childNodes = childNodes.concat(await element.getChildren())
+                if (element instanceof RegionNode) {
+                    childNodes.forEach(node => this.serviceNodes.add(node))
+                }
             } else {
                 childNodes = childNodes.concat(await this.getRootNodes())
             }
+
+            const loadError = getLoadError(childNodes)
+            if (element && key && loadError) {
+                childNodes = (await this.getCachedChildren(element, key, loadError)) ?? childNodes
+            } else if (element && key && !(element instanceof CachedNode) && !this.filterText) {
+                await this.cache.saveChildren(key, childNodes)
+            }
         } catch (err) {
             const error = err as Error
             this.logger.error(`Error getting children for node ${element?.label ?? 'Root Node'}: %O`, error)
 
+            const cachedNodes = element && key ? await this.getCachedChildren(element, key, error) : undefined
+            if (cachedNodes) {
+                return cachedNodes
+            }
+
             childNodes.splice(
                 0,
                 childNodes.length,
@@ -98,6 +130,10 @@ export class AwsExplorer implements vscode.TreeDataProvider<AWSTreeNodeBase>, Re
             )
         }
 
+        if (key) {
+            childNodes.forEach(node => this.nodeKeys.set(node, getChildKey(key, node.label)))
+        }
+
         return childNodes
     }
 
@@ -109,6 +145,47 @@ export class AwsExplorer implements vscode.TreeDataProvider<AWSTreeNodeBase>, Re
         this._onDidChangeTreeData.fire(node)
     }
 
+    public get filter(): string | undefined {
+        return this.filterText
+    }
+
+    /**
+     * Filters the resources listed under each service to those whose name contains the given text
+     * (case-insensitive). The services' APIs can only filter by case-sensitive prefix, if at all, so all
+     * resources are listed and filtered here.
+     */
+    public setFilter(filter: string | undefined): void {
+        this.filterText = filter?.trim() || undefined
+        vscode.commands.executeCommand('setContext', FILTERED_CONTEXT_KEY, this.filterText !== undefined)
+        this.refresh()
+    }
+
+    private async getFilteredChildren(serviceNode: AWSTreeNodeBase): Promise<AWSTreeNodeBase[]> {
+        const childNodes = await serviceNode.getChildren()
+
+        return this.filterText ? filterChildNodes(serviceNode, childNodes, this.filterText) : childNodes
+    }
+
+    /**
+     * Gets the children that a node last loaded, to show instead of an error when the node can't be loaded
+     * because of a network error.
+     */
+    private async getCachedChildren(
+        element: AWSTreeNodeBase,
+        key: string,
+        error: Error
+    ): Promise<AWSTreeNodeBase[] | undefined> {
+        const cachedNodes = await this.cache.getFallbackChildren(key, error)
+        if (!cachedNodes) {
+            return undefined
+        }
+        this.logger.info(`Showing cached children of node ${element.label} after a network error: ${error.message}`)
+
+        return this.filterText && this.serviceNodes.has(element)
+            ? filterChildNodes(element, cachedNodes, this.filterText)
+            : cachedNodes
+    }
+
     private async getRootNodes(): Promise<AWSTreeNodeBase[]> {
         if (!(await this.awsContext.getCredentials())) {
             return [this.ROOT_NODE_SIGN_IN]
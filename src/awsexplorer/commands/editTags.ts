@@ -0,0 +1,318 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { ResourceGroupsTaggingAPI } from 'aws-sdk'
+import * as _ from 'lodash'
+import * as vscode from 'vscode'
+import { LogGroupNode } from '../../cloudWatchLogs/explorer/logGroupNode'
+import { LambdaFunctionNode } from '../../lambda/explorer/lambdaFunctionNode'
+import { S3BucketNode } from '../../s3/explorer/s3BucketNode'
+import { ResourceGroupsTaggingClient } from '../../shared/clients/resourceGroupsTaggingClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordAwsEditTags } from '../../shared/telemetry/telemetry'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+
+const MAX_TAG_KEY_LENGTH = 128
+const MAX_TAG_VALUE_LENGTH = 256
+
+/** A selected explorer resource that can be tagged with the Resource Groups Tagging API. */
+export interface TaggableResource {
+    readonly arn: string
+    readonly name: string
+    readonly regionCode: string
+}
+
+interface TaggingFailure {
+    readonly resource: TaggableResource
+    readonly message: string
+}
+
+type TagOperation = { kind: 'add'; tags: ResourceGroupsTaggingAPI.TagMap } | { kind: 'remove'; tagKeys: string[] }
+
+/**
+ * Gets the resource of an explorer node, if it is a kind of resource that can be tagged.
+ */
+export function toTaggableResource(node: AWSTreeNodeBase): TaggableResource | undefined {
+    if (node instanceof LambdaFunctionNode) {
+        return { arn: node.arn, name: node.name, regionCode: node.regionCode }
+    }
+    if (node instanceof S3BucketNode) {
+        return { arn: node.arn, name: node.name, regionCode: node.bucket.region }
+    }
+    if (node instanceof LogGroupNode) {
+        // the tagging API takes log group ARNs without the `:*` that log group descriptions end with
+        return { arn: node.arn.replace(/:\*$/, ''), name: node.name, regionCode: node.regionCode }
+    }
+
+    return undefined
+}
+
+/**
+ * Parses tags entered as comma-separated `key=value` pairs, e.g. `team=payments, env=prod`.
+ * Values can be empty, e.g. `reviewed=`.
+ *
+ * @throws Error if a pair has no key, or a key or value is too long or reserved
+ */
+export function parseTags(text: string): ResourceGroupsTaggingAPI.TagMap {
+    const pairs = text
+        .split(',')
+        .map(pair => pair.trim())
+        .filter(pair => pair)
+    if (pairs.length === 0) {
+        throw new Error(localize('AWS.tags.edit.tags.empty', 'Enter at least one tag, e.g. team=payments'))
+    }
+
+    return pairs.reduce((tags, pair) => {
+        const separator = pair.indexOf('=')
+        if (separator < 0) {
+            throw new Error(localize('AWS.tags.edit.tags.noValue', 'Enter tag {0} as {0}=value', pair))
+        }
+        const key = pair.slice(0, separator).trim()
+        const value = pair.slice(separator + 1).trim()
+        validateTagKey(key)
+        if (value.length > MAX_TAG_VALUE_LENGTH) {
+            throw new Error(
+                localize(
+                    'AWS.tags.edit.tags.valueTooLong',
+                    'The value of tag {0} must be at most {1} characters',
+                    key,
+                    MAX_TAG_VALUE_LENGTH
+                )
+            )
+        }
+
+        return { ...tags, [key]: value }
+    }, {} as ResourceGroupsTaggingAPI.TagMap)
+}
+
+/**
+ * Parses tag keys entered as a comma-separated list, e.g. `team, env`.
+ *
+ * @throws Error if no keys are entered, or a key is too long or reserved
+ */
+export function parseTagKeys(text: string): string[] {
+    const keys = _.uniq(
+        text
+            .split(',')
+            .map(key => key.trim())
+            .filter(key => key)
+    )
+    if (keys.length === 0) {
+        throw new Error(localize('AWS.tags.edit.tagKeys.empty', 'Enter at least one tag key, e.g. team'))
+    }
+    keys.forEach(validateTagKey)
+
+    return keys
+}
+
+function validateTagKey(key: string): void {
+    if (!key) {
+        throw new Error(localize('AWS.tags.edit.tags.noKey', 'Enter a key for each tag'))
+    }
+    if (key.length > MAX_TAG_KEY_LENGTH) {
+        throw new Error(
+            localize(
+                'AWS.tags.edit.tags.keyTooLong',
+                'Tag key {0} must be at most {1} characters',
+                key,
+                MAX_TAG_KEY_LENGTH
+            )
+        )
+    }
+    if (key.toLowerCase().startsWith('aws:')) {
+        throw new Error(localize('AWS.tags.edit.tags.reserved', 'Tag keys starting with aws: are reserved for AWS'))
+    }
+}
+
+function toValidator(parse: (text: string) => unknown): (text: string) => string | undefined {
+    return text => {
+        try {
+            parse(text)
+        } catch (e) {
+            return (e as Error).message
+        }
+
+        return undefined
+    }
+}
+
+/**
+ * Adds or removes tags of the selected explorer resources: Lambda functions, S3 buckets, and log groups.
+ *
+ * All of these are tagged with the Resource Groups Tagging API, so each region takes a call per 20 resources.
+ * Failures don't stop the other resources from being tagged, and are summarized at the end.
+ *
+ * @param selectedNodes The nodes selected in the explorer, which VS Code passes when several are selected.
+ */
+export async function editTagsCommand(
+    node: AWSTreeNodeBase,
+    selectedNodes: AWSTreeNodeBase[] | undefined,
+    window = Window.vscode(),
+    promptUserFunction = promptUser,
+    createClient: (regionCode: string) => ResourceGroupsTaggingClient = regionCode =>
+        ext.toolkitClientBuilder.createResourceGroupsTaggingClient(regionCode)
+): Promise<void> {
+    // the selection doesn't include the node when another node is right-clicked
+    const nodes = selectedNodes?.includes(node) ? selectedNodes : [node]
+    const resources = _.compact(nodes.map(toTaggableResource))
+    getLogger().debug('EditTags called for %O', resources)
+
+    if (resources.length === 0) {
+        window.showErrorMessage(
+            localize(
+                'AWS.tags.edit.noTaggableResources',
+                'None of the selected resources can be tagged. Select Lambda functions, S3 buckets, or log groups.'
+            )
+        )
+        recordAwsEditTags({ result: 'Failed' })
+        return
+    }
+
+    const operation = await promptForOperation(resources, nodes.length - resources.length, window, promptUserFunction)
+    if (!operation) {
+        getLogger().info('EditTags cancelled')
+        recordAwsEditTags({ result: 'Cancelled' })
+        return
+    }
+
+    const failures = await window.withProgress(
+        {
+            location: vscode.ProgressLocation.Notification,
+            title: localize('AWS.tags.edit.progressTitle', 'Updating tags of {0} resources...', resources.length),
+        },
+        async () => applyOperation(resources, operation, createClient)
+    )
+
+    if (failures.length === 0) {
+        getLogger().info(`Updated tags of ${resources.length} resources`)
+        window.showInformationMessage(
+            localize('AWS.tags.edit.success', 'Updated tags of {0} resources', resources.length)
+        )
+        recordAwsEditTags({ result: 'Succeeded' })
+        return
+    }
+
+    failures.forEach(failure =>
+        getLogger().error(`Failed to update tags of ${failure.resource.arn}: ${failure.message}`)
+    )
+    showErrorWithLogs(
+        localize(
+            'AWS.tags.edit.partialFailure',
+            'Updated tags of {0} of {1} resources. Failed to update: {2}',
+            resources.length - failures.length,
+            resources.length,
+            failures.map(failure => failure.resource.name).join(', ')
+        ),
+        window
+    )
+    recordAwsEditTags({ result: 'Failed' })
+}
+
+async function promptForOperation(
+    resources: TaggableResource[],
+    skippedCount: number,
+    window: Window,
+    promptUserFunction: typeof promptUser
+): Promise<TagOperation | undefined> {
+    const add: vscode.QuickPickItem = {
+        label: localize('AWS.tags.edit.add', 'Add or update tags...'),
+        detail: localize('AWS.tags.edit.add.detail', 'Tags with keys the resources already have get the new values'),
+    }
+    const remove: vscode.QuickPickItem = { label: localize('AWS.tags.edit.remove', 'Remove tags...') }
+    const picker = createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.tags.edit.title', 'Edit tags of {0} resources', resources.length),
+            placeHolder:
+                skippedCount > 0
+                    ? localize(
+                          'AWS.tags.edit.skipped',
+                          '{0} of the selected resources cannot be tagged and are skipped',
+                          skippedCount
+                      )
+                    : undefined,
+        },
+        items: [add, remove],
+    })
+    const choice = verifySinglePickerOutput(await promptUserFunction({ picker }))
+    if (!choice) {
+        return undefined
+    }
+
+    if (choice.label === add.label) {
+        const tags = await window.showInputBox({
+            prompt: localize(
+                'AWS.tags.edit.tags.prompt',
+                'Enter the tags to add as key=value pairs, separated by commas'
+            ),
+            placeHolder: 'team=payments, env=prod',
+            ignoreFocusOut: true,
+            validateInput: toValidator(parseTags),
+        })
+
+        return tags ? { kind: 'add', tags: parseTags(tags) } : undefined
+    }
+
+    const keysText = await window.showInputBox({
+        prompt: localize('AWS.tags.edit.tagKeys.prompt', 'Enter the keys of the tags to remove, separated by commas'),
+        placeHolder: 'team, env',
+        ignoreFocusOut: true,
+        validateInput: toValidator(parseTagKeys),
+    })
+    if (!keysText) {
+        return undefined
+    }
+
+    const tagKeys = parseTagKeys(keysText)
+    const confirmed = await showConfirmationMessage(
+        {
+            prompt: localize(
+                'AWS.tags.edit.remove.prompt',
+                'Remove tags {0} from {1} resources? Access policies and cost allocation that use these tags stop applying to them.',
+                tagKeys.join(', '),
+                resources.length
+            ),
+            confirm: localize('AWS.tags.edit.remove.confirm', 'Remove'),
+            cancel: localize('AWS.generic.cancel', 'Cancel'),
+        },
+        window
+    )
+
+    return confirmed ? { kind: 'remove', tagKeys } : undefined
+}
+
+async function applyOperation(
+    resources: TaggableResource[],
+    operation: TagOperation,
+    createClient: (regionCode: string) => ResourceGroupsTaggingClient
+): Promise<TaggingFailure[]> {
+    const failures: TaggingFailure[] = []
+
+    for (const [regionCode, regionResources] of Object.entries(_.groupBy(resources, 'regionCode'))) {
+        const client = createClient(regionCode)
+        const arns = regionResources.map(resource => resource.arn)
+        try {
+            const failed =
+                operation.kind === 'add'
+                    ? await client.tagResources(arns, operation.tags)
+                    : await client.untagResources(arns, operation.tagKeys)
+            regionResources
+                .filter(resource => failed[resource.arn])
+                .forEach(resource =>
+                    failures.push({ resource, message: failed[resource.arn].ErrorMessage ?? 'Unknown error' })
+                )
+        } catch (e) {
+            // keep going with the other regions: the summary reports the resources of this one as failed
+            regionResources.forEach(resource => failures.push({ resource, message: (e as Error).message }))
+        }
+    }
+
+    return failures
+}
@@ -0,0 +1,79 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AwsContext } from '../../shared/awsContext'
+import { getLogger } from '../../shared/logger'
+import { recordAwsPinFavorite, recordAwsUnpinFavorite, Result } from '../../shared/telemetry/telemetry'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { FavoriteNode } from '../favoriteNode'
+import { Favorite, FavoritesStore, toFavorite } from '../favorites'
+
+/**
+ * Pins the selected AWS Explorer nodes to the Favorites view, with the region and connection they were found with.
+ * Nodes that were already pinned are skipped.
+ */
+export async function pinFavoriteCommand(
+    node: AWSTreeNodeBase,
+    selectedNodes: AWSTreeNodeBase[] | undefined,
+    store: FavoritesStore,
+    awsContext: AwsContext,
+    window = Window.vscode()
+): Promise<void> {
+    const nodes = selectedNodes?.includes(node) ? selectedNodes : [node]
+    let result: Result = 'Succeeded'
+
+    try {
+        const favorites = nodes
+            .map(selected => toFavorite(selected, awsContext))
+            .filter((favorite): favorite is Favorite => favorite !== undefined)
+        if (favorites.length === 0) {
+            result = 'Failed'
+            window.showErrorMessage(
+                localize('AWS.favorites.pin.unsupported', 'Resources of this type cannot be pinned to Favorites')
+            )
+            return
+        }
+
+        const pinned: string[] = []
+        for (const favorite of favorites) {
+            if (await store.add(favorite)) {
+                pinned.push(favorite.name)
+            }
+        }
+        getLogger().info('Pinned to favorites: %O', pinned)
+
+        if (pinned.length === 0) {
+            result = 'Cancelled'
+            window.showInformationMessage(
+                localize('AWS.favorites.pin.alreadyPinned', '{0} is already pinned to Favorites', favorites[0].name)
+            )
+            return
+        }
+
+        window.showInformationMessage(
+            localize('AWS.favorites.pin.success', 'Pinned {0} to Favorites', pinned.join(', '))
+        )
+    } finally {
+        recordAwsPinFavorite({ result })
+    }
+}
+
+/**
+ * Removes a resource from the Favorites view.
+ */
+export async function unpinFavoriteCommand(node: FavoriteNode, store: FavoritesStore): Promise<void> {
+    getLogger().info('Unpinning favorite %s', node.arn)
+    await store.remove(node.arn)
+    recordAwsUnpinFavorite({ result: 'Succeeded' })
+}
+
+/**
+ * Moves a resource up (negative offset) or down (positive offset) in the Favorites view.
+ */
+export async function moveFavoriteCommand(node: FavoriteNode, store: FavoritesStore, offset: number): Promise<void> {
+    await store.move(node.arn, offset)
+}
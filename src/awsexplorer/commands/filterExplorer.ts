@@ -0,0 +1,47 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import * as telemetry from '../../shared/telemetry/telemetry'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { AwsExplorer } from '../awsExplorer'
+
+/**
+ * Filters the resources listed in the AWS Explorer by name.
+ *
+ * Submitting an empty value clears the filter. The filter is also cleared when the explorer is refreshed.
+ */
+export async function filterExplorerCommand(explorer: AwsExplorer, window = Window.vscode()): Promise<void> {
+    const input = await window.showInputBox({
+        prompt: localize(
+            'AWS.explorer.filter.prompt',
+            'Filter resources by name (leave empty to clear). Log group filters starting with / match name prefixes.'
+        ),
+        placeHolder: localize('AWS.explorer.filter.placeHolder', 'Text contained in resource names'),
+        value: explorer.filter,
+    })
+
+    if (input === undefined) {
+        getLogger().info('FilterExplorer cancelled')
+        telemetry.recordAwsFilterExplorer({ result: 'Cancelled' })
+        return
+    }
+
+    getLogger().info('Setting AWS Explorer filter: %s', input)
+    explorer.setFilter(input)
+    telemetry.recordAwsFilterExplorer({ result: 'Succeeded' })
+}
+
+/**
+ * Removes the AWS Explorer filter, if any.
+ */
+export async function clearExplorerFilterCommand(explorer: AwsExplorer): Promise<void> {
+    if (explorer.filter === undefined) {
+        return
+    }
+
+    explorer.setFilter(undefined)
+}
@@ -0,0 +1,255 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as fs from 'fs-extra'
+import * as _ from 'lodash'
+import * as vscode from 'vscode'
+import { getLogger } from '../shared/logger'
+import { AWSTreeNodeBase } from '../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../shared/treeview/nodes/errorNode'
+import { localize } from '../shared/utilities/vsCodeUtils'
+import { MoreResultsNode } from './moreResultsNode'
+
+/** Limits the size of the cache file, which is read on the first expand and rewritten on each change. */
+const MAX_CACHED_PARENTS = 500
+const MAX_CACHED_CHILDREN = 200
+
+/** Error codes of the SDK and of Node.js for requests that failed before reaching the service. */
+const NETWORK_ERROR_CODES = [
+    'NetworkingError',
+    'TimeoutError',
+    'RequestTimeout',
+    'UnknownEndpoint',
+    'ECONNREFUSED',
+    'ECONNRESET',
+    'ENOTFOUND',
+    'ETIMEDOUT',
+    'EAI_AGAIN',
+    'EHOSTUNREACH',
+    'ENETUNREACH',
+    'EPIPE',
+]
+
+type CachedIcon = { themeIcon: string } | { light: string; dark: string }
+
+/** What is needed to show a node again, without anything that depends on a live connection. */
+export interface CachedNodeState {
+    label: string
+    description?: string
+    tooltip?: string
+    icon?: CachedIcon
+    collapsible: boolean
+}
+
+interface CachedChildren {
+    children: CachedNodeState[]
+    /** When the children were loaded, in milliseconds since the epoch. */
+    loaded: number
+}
+
+/**
+ * Whether an error is a transient network failure, such as a dropped connection or a failed DNS lookup,
+ * rather than an error returned by the service, such as `AccessDenied`.
+ */
+export function isNetworkError(error: Error): boolean {
+    const code = (error as { code?: string }).code
+
+    return code !== undefined && NETWORK_ERROR_CODES.includes(code)
+}
+
+function toIcon(iconPath: vscode.TreeItem['iconPath']): CachedIcon | undefined {
+    if (iconPath instanceof vscode.ThemeIcon) {
+        return { themeIcon: iconPath.id }
+    }
+    if (iconPath && typeof iconPath === 'object' && 'light' in iconPath) {
+        const toPath = (icon: string | vscode.Uri) => (typeof icon === 'string' ? icon : icon.fsPath)
+
+        return { light: toPath(iconPath.light), dark: toPath(iconPath.dark) }
+    }
+
+    return undefined
+}
+
+function toIconPath(icon: CachedIcon | undefined): vscode.TreeItem['iconPath'] {
+    if (!icon) {
+        return undefined
+    }
+
+    return 'themeIcon' in icon
+        ? new vscode.ThemeIcon(icon.themeIcon)
+        : { light: vscode.Uri.file(icon.light), dark: vscode.Uri.file(icon.dark) }
+}
+
+/**
+ * A node as it was last loaded, shown while its parent can't be loaded because of a network error.
+ *
+ * Cached nodes have no commands or context menus, since the resources they stand for may have changed.
+ */
+export class CachedNode extends AWSTreeNodeBase {
+    public constructor(
+        public readonly key: string,
+        state: CachedNodeState,
+        loaded: Date,
+        private readonly cache: ExplorerCache
+    ) {
+        super(
+            state.label,
+            state.collapsible ? vscode.TreeItemCollapsibleState.Collapsed : vscode.TreeItemCollapsibleState.None
+        )
+        // descriptions are shown dimmed, which tells cached nodes apart from live ones
+        this.description = state.description
+            ? localize('AWS.explorerNode.offline.description', '{0} (offline)', state.description)
+            : localize('AWS.explorerNode.offline', '(offline)')
+        this.tooltip = localize(
+            'AWS.explorerNode.offline.tooltip',
+            '{0}\n\nOffline: shown as it was loaded on {1}. Refresh to load it again.',
+            state.tooltip ?? state.label,
+            loaded.toLocaleString()
+        )
+        this.iconPath = toIconPath(state.icon)
+        this.contextValue = 'awsCachedNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return this.cache.getCachedChildren(this.key) ?? []
+    }
+}
+
+/**
+ * Keeps the last children that each explorer node loaded in a file, so that the explorer can still show
+ * them after a refresh fails because of a network error.
+ */
+export class ExplorerCache {
+    private state?: { [key: string]: CachedChildren }
+    /** Writes are made one at a time, so that an older state can't overwrite a newer one. */
+    private pendingWrite: Promise<void> = Promise.resolve()
+
+    /**
+     * @param filePath where the cache is kept, created when the first children are saved
+     */
+    public constructor(private readonly filePath: string) {}
+
+    /**
+     * Saves the children of a node, replacing what was saved before. Only the first children of nodes
+     * with many children are saved.
+     *
+     * @param key identifies the node, see {@link getChildKey}
+     */
+    public async saveChildren(key: string, children: AWSTreeNodeBase[]): Promise<void> {
+        const cached: CachedChildren = {
+            children: children
+                .filter(child => !(child instanceof MoreResultsNode) && !(child instanceof CachedNode))
+                .slice(0, MAX_CACHED_CHILDREN)
+                .map(child => ({
+                    label: child.label ?? '',
+                    description: typeof child.description === 'string' ? child.description : undefined,
+                    tooltip: child.tooltip,
+                    icon: toIcon(child.iconPath),
+                    collapsible: child.collapsibleState !== vscode.TreeItemCollapsibleState.None,
+                })),
+            loaded: Date.now(),
+        }
+        const { [key]: previous, ...others } = this.getState()
+        if (previous && _.isEqual(previous.children, cached.children)) {
+            // most expands load the same children again, which isn't worth rewriting the file for
+            previous.loaded = cached.loaded
+            return
+        }
+
+        // re-inserting the key moves it last, so the least recently loaded parents are dropped first
+        const keys = Object.keys(others)
+
+        await this.updateState({
+            ..._.pick(others, keys.slice(Math.max(0, keys.length - MAX_CACHED_PARENTS + 1))),
+            [key]: cached,
+        })
+    }
+
+    /**
+     * Removes the saved children of a node, e.g. after the node failed to load for a reason other than the network.
+     */
+    public async removeChildren(key: string): Promise<void> {
+        const { [key]: removed, ...others } = this.getState()
+        if (removed) {
+            await this.updateState(others)
+        }
+    }
+
+    /**
+     * @returns the saved children of a node, or undefined if none were saved.
+     */
+    public getCachedChildren(key: string): CachedNode[] | undefined {
+        const cached = this.getState()[key]
+        if (!cached) {
+            return undefined
+        }
+
+        return cached.children.map(
+            child => new CachedNode(getChildKey(key, child.label), child, new Date(cached.loaded), this)
+        )
+    }
+
+    /**
+     * Returns the cached children to show instead of a failed load, if the children failed to load because
+     * of a network error and were saved before.
+     *
+     * Other errors remove the saved children: showing them after e.g. `AccessDenied` would hide a real problem.
+     */
+    public async getFallbackChildren(key: string, error: Error): Promise<CachedNode[] | undefined> {
+        if (!isNetworkError(error)) {
+            await this.removeChildren(key)
+            return undefined
+        }
+
+        return this.getCachedChildren(key)
+    }
+
+    private getState(): { [key: string]: CachedChildren } {
+        if (this.state) {
+            return this.state
+        }
+
+        let state: { [key: string]: CachedChildren } = {}
+        try {
+            if (fs.existsSync(this.filePath)) {
+                state = JSON.parse(fs.readFileSync(this.filePath, 'utf8'))
+            }
+        } catch (e) {
+            getLogger().warn(`Ignoring unreadable explorer cache ${this.filePath}: %O`, e as Error)
+        }
+        this.state = state
+
+        return state
+    }
+
+    private async updateState(state: { [key: string]: CachedChildren }): Promise<void> {
+        this.state = state
+        this.pendingWrite = this.pendingWrite.then(async () => {
+            try {
+                await fs.outputFile(this.filePath, JSON.stringify(this.state))
+            } catch (e) {
+                // the explorer works without the cache, it just can't show anything offline
+                getLogger().warn(`Failed to write explorer cache ${this.filePath}: %O`, e as Error)
+            }
+        })
+        await this.pendingWrite
+    }
+}
+
+/**
+ * Identifies a child node by the path of labels from the root of the explorer.
+ */
+export function getChildKey(parentKey: string, label: string | undefined): string {
+    return `${parentKey}/${label ?? ''}`
+}
+
+/**
+ * Gets the error that a node failed to load its children with, if it did.
+ */
+export function getLoadError(children: AWSTreeNodeBase[]): Error | undefined {
+    const errorNode = children.find(child => child instanceof ErrorNode) as ErrorNode | undefined
+
+    return errorNode?.error
+}
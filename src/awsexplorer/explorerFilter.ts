@@ -0,0 +1,44 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AWSTreeNodeBase } from '../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../shared/treeview/nodes/placeholderNode'
+import { localize } from '../shared/utilities/vsCodeUtils'
+import { MoreResultsNode } from './moreResultsNode'
+
+/**
+ * Case-insensitive substring match of a node label.
+ */
+export function matchesFilter(label: string | undefined, filter: string): boolean {
+    return (label ?? '').toLowerCase().includes(filter.toLowerCase())
+}
+
+/**
+ * Filters the resource nodes of a service by label. Error, placeholder, and "Load More..." nodes are kept.
+ *
+ * @returns the matching nodes, or a placeholder if none match
+ */
+export function filterChildNodes(
+    parent: AWSTreeNodeBase,
+    childNodes: AWSTreeNodeBase[],
+    filter: string
+): AWSTreeNodeBase[] {
+    const isStatusNode = (node: AWSTreeNodeBase) =>
+        node instanceof ErrorNode || node instanceof PlaceholderNode || node instanceof MoreResultsNode
+    const matches = childNodes.filter(node => isStatusNode(node) || matchesFilter(node.label, filter))
+
+    if (matches.every(node => node instanceof MoreResultsNode)) {
+        return [
+            new PlaceholderNode(
+                parent,
+                localize('AWS.explorerNode.filter.noMatches', '[No resources match "{0}"]', filter)
+            ),
+            ...matches,
+        ]
+    }
+
+    return matches
+}
@@ -0,0 +1,189 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { LogGroupNode } from '../cloudWatchLogs/explorer/logGroupNode'
+import { CloudFormationStackNode } from '../lambda/explorer/cloudFormationNodes'
+import { LambdaFunctionNode } from '../lambda/explorer/lambdaFunctionNode'
+import { S3BucketNode } from '../s3/explorer/s3BucketNode'
+import { S3Node } from '../s3/explorer/s3Nodes'
+import { AwsContext } from '../shared/awsContext'
+import { ext } from '../shared/extensionGlobals'
+import { getLogger } from '../shared/logger'
+import { AWSResourceNode } from '../shared/treeview/nodes/awsResourceNode'
+import { AWSTreeNodeBase } from '../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../shared/utilities/vsCodeUtils'
+import { Favorite, FavoriteResourceType } from './favorites'
+
+/**
+ * - `loading`: the resource is being looked up
+ * - `available`: the resource exists, and the node shows it as the AWS Explorer does
+ * - `disconnected`: the active connection is not to the account the resource was pinned in
+ * - `notFound`: the resource no longer exists
+ * - `failed`: the resource could not be looked up, e.g. because access was denied
+ */
+export type FavoriteState = 'loading' | 'available' | 'disconnected' | 'notFound' | 'failed'
+
+/**
+ * Looks up the resource of a favorite with the active connection.
+ *
+ * @returns a node for the resource as the AWS Explorer shows it, or undefined if the resource no longer exists
+ */
+type FavoriteResolver = (favorite: Favorite, parent: AWSTreeNodeBase) => Promise<AWSTreeNodeBase | undefined>
+
+const RESOLVERS: { [type in FavoriteResourceType]: FavoriteResolver } = {
+    lambdaFunction: async (favorite, parent) => {
+        const lambda = ext.toolkitClientBuilder.createLambdaClient(favorite.regionCode)
+        try {
+            const configuration = await lambda.getFunctionConfiguration(favorite.name)
+
+            return new LambdaFunctionNode(parent, favorite.regionCode, configuration)
+        } catch (err) {
+            if ((err as { code?: string }).code === 'ResourceNotFoundException') {
+                return undefined
+            }
+            throw err
+        }
+    },
+    s3Bucket: async favorite => {
+        const s3 = ext.toolkitClientBuilder.createS3Client(favorite.regionCode)
+        // buckets are global, so all of them are listed whichever region the client is of
+        const buckets = await s3.listAllBuckets()
+        if (!buckets.some(bucket => bucket.Name === favorite.name)) {
+            return undefined
+        }
+
+        const bucket = { name: favorite.name, region: favorite.regionCode, arn: favorite.arn }
+
+        return new S3BucketNode(bucket, new S3Node(s3), s3)
+    },
+    logGroup: async (favorite, parent) => {
+        const logs = ext.toolkitClientBuilder.createCloudWatchLogsClient(favorite.regionCode)
+        for await (const logGroup of logs.describeLogGroups({ logGroupNamePrefix: favorite.name })) {
+            if (logGroup.logGroupName === favorite.name) {
+                return new LogGroupNode(parent, favorite.regionCode, logGroup)
+            }
+        }
+
+        return undefined
+    },
+    cloudFormationStack: async (favorite, parent) => {
+        const cloudFormation = ext.toolkitClientBuilder.createCloudFormationClient(favorite.regionCode)
+        try {
+            const stack = await cloudFormation.describeStack(favorite.name)
+            if (!stack || stack.StackStatus === 'DELETE_COMPLETE') {
+                return undefined
+            }
+
+            return new CloudFormationStackNode(parent, favorite.regionCode, stack)
+        } catch (err) {
+            // CloudFormation rejects names of stacks that don't exist as invalid
+            if ((err as { code?: string }).code === 'ValidationError') {
+                return undefined
+            }
+            throw err
+        }
+    },
+}
+
+const ICONS: { [type in FavoriteResourceType]: keyof typeof ext.iconPaths.dark } = {
+    lambdaFunction: 'lambda',
+    s3Bucket: 's3',
+    logGroup: 'cloudWatchLogGroup',
+    cloudFormationStack: 'cloudFormation',
+}
+
+/**
+ * A resource pinned to the Favorites view.
+ *
+ * The node shows what was saved when the resource was pinned until {@link resolve} looks the resource up,
+ * after which it shows and expands as the AWS Explorer node of the resource does. Resources that are not
+ * available are shown with a slashed icon and the reason in their (dimmed) description.
+ */
+export class FavoriteNode extends AWSTreeNodeBase implements AWSResourceNode {
+    private resource: AWSTreeNodeBase | undefined
+    private _state: FavoriteState = 'loading'
+
+    public constructor(public readonly favorite: Favorite) {
+        super(favorite.name, vscode.TreeItemCollapsibleState.None)
+        this.description = favorite.regionCode
+        this.tooltip = favorite.arn
+        this.iconPath = {
+            dark: vscode.Uri.file(ext.iconPaths.dark[ICONS[favorite.type]]),
+            light: vscode.Uri.file(ext.iconPaths.light[ICONS[favorite.type]]),
+        }
+        this.contextValue = 'awsFavoriteNode'
+    }
+
+    public get state(): FavoriteState {
+        return this._state
+    }
+
+    public get arn(): string {
+        return this.favorite.arn
+    }
+
+    public get name(): string {
+        return this.favorite.name
+    }
+
+    /**
+     * Looks up the resource in the region it was pinned in, if the active connection is to the account
+     * it was pinned in. Connections to other accounts would not find it, or would find a different resource.
+     */
+    public async resolve(awsContext: AwsContext): Promise<void> {
+        const accountId = awsContext.getCredentialAccountId()
+        if (!accountId || (this.favorite.accountId && this.favorite.accountId !== accountId)) {
+            this.setUnavailable(
+                'disconnected',
+                localize(
+                    'AWS.explorerNode.favorite.disconnected',
+                    '{0} (connect to {1})',
+                    this.favorite.regionCode,
+                    this.favorite.credentialsId ?? this.favorite.accountId
+                )
+            )
+            return
+        }
+
+        try {
+            this.resource = await RESOLVERS[this.favorite.type](this.favorite, this)
+        } catch (err) {
+            getLogger().error('Failed to look up favorite %s: %O', this.favorite.arn, err)
+            this.setUnavailable(
+                'failed',
+                localize('AWS.explorerNode.favorite.failed', '{0} (failed to load)', this.favorite.regionCode),
+                `${this.favorite.arn}\n\n${(err as Error).message}`
+            )
+            return
+        }
+
+        if (!this.resource) {
+            this.setUnavailable(
+                'notFound',
+                localize('AWS.explorerNode.favorite.notFound', '{0} (not found)', this.favorite.regionCode)
+            )
+            return
+        }
+
+        this._state = 'available'
+        this.tooltip = this.resource.tooltip
+        this.iconPath = this.resource.iconPath
+        this.collapsibleState = this.resource.collapsibleState
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return this.resource ? await this.resource.getChildren() : []
+    }
+
+    private setUnavailable(state: FavoriteState, description: string, tooltip: string = this.favorite.arn): void {
+        this._state = state
+        this.resource = undefined
+        this.description = description
+        this.tooltip = tooltip
+        this.iconPath = new vscode.ThemeIcon('circle-slash')
+        this.collapsibleState = vscode.TreeItemCollapsibleState.None
+    }
+}
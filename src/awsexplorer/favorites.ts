@@ -0,0 +1,131 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { LogGroupNode } from '../cloudWatchLogs/explorer/logGroupNode'
+import { CloudFormationStackNode } from '../lambda/explorer/cloudFormationNodes'
+import { LambdaFunctionNode } from '../lambda/explorer/lambdaFunctionNode'
+import { S3BucketNode } from '../s3/explorer/s3BucketNode'
+import { AwsContext } from '../shared/awsContext'
+import { AWSTreeNodeBase } from '../shared/treeview/nodes/awsTreeNodeBase'
+
+const FAVORITES_STATE_KEY = 'aws.explorer.favorites'
+
+export type FavoriteResourceType = 'lambdaFunction' | 's3Bucket' | 'logGroup' | 'cloudFormationStack'
+
+/**
+ * A resource pinned to the Favorites view, with the context needed to find it again after the active
+ * connection changes.
+ */
+export interface Favorite {
+    readonly type: FavoriteResourceType
+    readonly arn: string
+    readonly name: string
+    readonly regionCode: string
+    /** The credentials that were active when the resource was pinned, e.g. `profile:default`. */
+    readonly credentialsId?: string
+    readonly accountId?: string
+}
+
+/**
+ * Describes an AWS Explorer node as a favorite.
+ *
+ * @returns undefined if resources of the node's type can't be pinned
+ */
+export function toFavorite(node: AWSTreeNodeBase, awsContext: AwsContext): Favorite | undefined {
+    const context = {
+        credentialsId: awsContext.getCredentialProfileName(),
+        accountId: awsContext.getCredentialAccountId(),
+    }
+
+    if (node instanceof LambdaFunctionNode) {
+        return { type: 'lambdaFunction', arn: node.arn, name: node.name, regionCode: node.regionCode, ...context }
+    }
+    if (node instanceof S3BucketNode) {
+        return { type: 's3Bucket', arn: node.arn, name: node.name, regionCode: node.bucket.region, ...context }
+    }
+    if (node instanceof LogGroupNode) {
+        return { type: 'logGroup', arn: node.arn, name: node.name, regionCode: node.regionCode, ...context }
+    }
+    if (node instanceof CloudFormationStackNode) {
+        return { type: 'cloudFormationStack', arn: node.arn, name: node.name, regionCode: node.regionCode, ...context }
+    }
+
+    return undefined
+}
+
+/**
+ * Identifies the current workspace, so that each workspace has its own favorites.
+ * Windows without a folder open share their favorites.
+ */
+export function getWorkspaceKey(): string {
+    return vscode.workspace.workspaceFolders?.[0]?.uri.toString() ?? ''
+}
+
+/**
+ * Keeps the favorites of each workspace in the global state, in the order they are shown.
+ */
+export class FavoritesStore {
+    private readonly _onDidChange = new vscode.EventEmitter<void>()
+    public readonly onDidChange = this._onDidChange.event
+
+    public constructor(private readonly memento: vscode.Memento, private readonly workspaceKey = getWorkspaceKey()) {}
+
+    public list(): Favorite[] {
+        return this.getState()[this.workspaceKey] ?? []
+    }
+
+    public has(arn: string): boolean {
+        return this.list().some(favorite => favorite.arn === arn)
+    }
+
+    /**
+     * Adds a favorite after the existing ones.
+     *
+     * @returns false if the resource was already pinned
+     */
+    public async add(favorite: Favorite): Promise<boolean> {
+        if (this.has(favorite.arn)) {
+            return false
+        }
+        await this.update([...this.list(), favorite])
+
+        return true
+    }
+
+    public async remove(arn: string): Promise<void> {
+        await this.update(this.list().filter(favorite => favorite.arn !== arn))
+    }
+
+    /**
+     * Moves a favorite up (negative offset) or down (positive offset), stopping at the first or last position.
+     */
+    public async move(arn: string, offset: number): Promise<void> {
+        const favorites = this.list()
+        const index = favorites.findIndex(favorite => favorite.arn === arn)
+        if (index === -1) {
+            return
+        }
+
+        const [favorite] = favorites.splice(index, 1)
+        favorites.splice(Math.min(Math.max(index + offset, 0), favorites.length), 0, favorite)
+        await this.update(favorites)
+    }
+
+    private async update(favorites: Favorite[]): Promise<void> {
+        const state = this.getState()
+        if (favorites.length === 0) {
+            delete state[this.workspaceKey]
+        } else {
+            state[this.workspaceKey] = favorites
+        }
+        await this.memento.update(FAVORITES_STATE_KEY, state)
+        this._onDidChange.fire()
+    }
+
+    private getState(): { [workspaceKey: string]: Favorite[] } {
+        return { ...this.memento.get<{ [workspaceKey: string]: Favorite[] }>(FAVORITES_STATE_KEY, {}) }
+    }
+}
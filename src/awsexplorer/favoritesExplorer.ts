@@ -0,0 +1,67 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { AwsContext } from '../shared/awsContext'
+import { getIdeProperties } from '../shared/extensionUtilities'
+import { getLogger } from '../shared/logger'
+import { RefreshableAwsTreeProvider } from '../shared/treeview/awsTreeProvider'
+import { AWSCommandTreeNode } from '../shared/treeview/nodes/awsCommandTreeNode'
+import { AWSTreeNodeBase } from '../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../shared/utilities/vsCodeUtils'
+import { FavoriteNode } from './favoriteNode'
+import { FavoritesStore } from './favorites'
+
+/**
+ * Lists the resources that were pinned in the current workspace, in the order the user arranged them.
+ */
+export class FavoritesExplorer implements vscode.TreeDataProvider<AWSTreeNodeBase>, RefreshableAwsTreeProvider {
+    public readonly viewProviderId: string = 'aws.favorites'
+    public readonly onDidChangeTreeData: vscode.Event<AWSTreeNodeBase | undefined>
+    private readonly _onDidChangeTreeData = new vscode.EventEmitter<AWSTreeNodeBase | undefined>()
+
+    public constructor(public readonly store: FavoritesStore, private readonly awsContext: AwsContext) {
+        this.onDidChangeTreeData = this._onDidChangeTreeData.event
+    }
+
+    public getTreeItem(element: AWSTreeNodeBase): vscode.TreeItem {
+        return element
+    }
+
+    public async getChildren(element?: AWSTreeNodeBase): Promise<AWSTreeNodeBase[]> {
+        if (element) {
+            return await element.getChildren()
+        }
+
+        const favorites = this.store.list()
+        if (favorites.length === 0) {
+            return [
+                new AWSCommandTreeNode(
+                    undefined,
+                    localize(
+                        'AWS.explorerNode.favorites.none',
+                        '[Pin resources in the {0} Explorer to list them here]',
+                        getIdeProperties().company
+                    ),
+                    'aws.favorites.refresh'
+                ),
+            ]
+        }
+
+        const nodes = favorites.map(favorite => new FavoriteNode(favorite))
+        // look up the resources after the saved favorites are shown, so that a slow service doesn't hold up the view
+        for (const node of nodes) {
+            node.resolve(this.awsContext)
+                .then(() => this.refresh(node))
+                .catch(err => getLogger().error('Failed to show favorite %s: %O', node.arn, err))
+        }
+
+        return nodes
+    }
+
+    public refresh(node?: AWSTreeNodeBase): void {
+        this._onDidChangeTreeData.fire(node)
+    }
+}
@@ -8,9 +8,18 @@ import { ApiGatewayNode } from '../apigateway/explorer/apiGatewayNodes'
 import { SchemasNode } from '../eventSchemas/explorer/schemasNode'
 import { CloudFormationNode } from '../lambda/explorer/cloudFormationNodes'
 import { CloudWatchLogsNode } from '../cloudWatchLogs/explorer/cloudWatchLogsNode'
+import { DynamoDbNode } from '../dynamoDb/explorer/dynamoDbNode'
+import { LambdaLayersNode } from '../lambda/explorer/lambdaLayerNodes'
 import { LambdaNode } from '../lambda/explorer/lambdaNodes'
 import { S3Node } from '../s3/explorer/s3Nodes'
+import { SecretsManagerNode } from '../secretsManager/explorer/secretsManagerNode'
+import { SqsNode } from '../sqs/explorer/sqsNode'
+import { CognitoNode } from '../cognito/explorer/cognitoNode'
+import { AppConfigNode } from '../appconfig/explorer/appConfigNode'
 import { EcrNode } from '../ecr/explorer/ecrNode'
+import { Ec2Node } from '../ec2/explorer/ec2Node'
+import { EcsNode } from '../ecs/explorer/ecsNode'
+import { KinesisNode } from '../kinesis/explorer/kinesisNode'
 import { isCloud9 } from '../shared/extensionUtilities'
 import { ext } from '../shared/extensionGlobals'
 import { Region } from '../shared/regions/endpoints'
@@ -50,17 +59,58 @@ export class RegionNode extends AWSTreeNodeBase {
         const partitionId = regionProvider.getPartitionId(this.regionCode) ?? DEFAULT_PARTITION
         const serviceCandidates = [
             { serviceId: 'apigateway', createFn: () => new ApiGatewayNode(partitionId, this.regionCode) },
+            {
+                serviceId: 'appconfig',
+                createFn: () => new AppConfigNode(ext.toolkitClientBuilder.createAppConfigClient(this.regionCode)),
+            },
             { serviceId: 'cloudformation', createFn: () => new CloudFormationNode(this.regionCode) },
+            {
+                serviceId: 'cognito-idp',
+                createFn: () => new CognitoNode(ext.toolkitClientBuilder.createCognitoClient(this.regionCode)),
+            },
+            {
+                serviceId: 'dynamodb',
+                createFn: () => new DynamoDbNode(ext.toolkitClientBuilder.createDynamoDbClient(this.regionCode)),
+            },
+            {
+                serviceId: 'ec2',
+                createFn: () =>
+                    new Ec2Node(
+                        ext.toolkitClientBuilder.createEc2Client(this.regionCode),
+                        ext.toolkitClientBuilder.createSsmClient(this.regionCode)
+                    ),
+            },
             {
                 serviceId: 'ecr',
                 createFn: () => new EcrNode(ext.toolkitClientBuilder.createEcrClient(this.regionCode)),
             },
+            {
+                serviceId: 'ecs',
+                createFn: () => new EcsNode(ext.toolkitClientBuilder.createEcsClient(this.regionCode)),
+            },
+            {
+                serviceId: 'kinesis',
+                createFn: () => new KinesisNode(ext.toolkitClientBuilder.createKinesisClient(this.regionCode)),
+            },
             { serviceId: 'lambda', createFn: () => new LambdaNode(this.regionCode) },
+            {
+                serviceId: 'lambda',
+                createFn: () => new LambdaLayersNode(ext.toolkitClientBuilder.createLambdaClient(this.regionCode)),
+            },
             { serviceId: 'logs', createFn: () => new CloudWatchLogsNode(this.regionCode) },
             {
                 serviceId: 's3',
                 createFn: () => new S3Node(ext.toolkitClientBuilder.createS3Client(this.regionCode)),
             },
+            {
+                serviceId: 'secretsmanager',
+                createFn: () =>
+                    new SecretsManagerNode(ext.toolkitClientBuilder.createSecretsManagerClient(this.regionCode)),
+            },
+            {
+                serviceId: 'sqs',
+                createFn: () => new SqsNode(ext.toolkitClientBuilder.createSqsClient(this.regionCode)),
+            },
             ...(isCloud9() ? [] : [{ serviceId: 'schemas', createFn: () => new SchemasNode(this.regionCode) }]),
             ...(isCloud9() ? [] : [{ serviceId: 'states', createFn: () => new StepFunctionsNode(this.regionCode) }]),
             ...(isCloud9() ? [] : [{ serviceId: 'ssm', createFn: () => new SsmDocumentNode(this.regionCode) }]),
@@ -7,16 +7,25 @@ import * as vscode from 'vscode'
 import { CLOUDWATCH_LOGS_SCHEME } from '../shared/constants'
 import { SettingsConfiguration } from '../shared/settingsConfiguration'
 import { addLogEvents } from './commands/addLogEvents'
+import { copyLogEvents } from './commands/copyLogEvents'
 import { copyLogStreamName } from './commands/copyLogStreamName'
 import { saveCurrentLogStreamContent } from './commands/saveCurrentLogStreamContent'
+import { toggleLogStreamTail } from './commands/toggleLogStreamTail'
 import { viewLogStream } from './commands/viewLogStream'
 import { LogStreamCodeLensProvider } from './document/logStreamCodeLensProvider'
 import { LogStreamDocumentProvider } from './document/logStreamDocumentProvider'
+import { CloudWatchLogsNode } from './explorer/cloudWatchLogsNode'
 import { LogGroupNode } from './explorer/logGroupNode'
+import { LambdaFunctionNode } from '../lambda/explorer/lambdaFunctionNode'
+import { viewLambdaLogs } from '../lambda/commands/viewLambdaLogs'
 import { LogStreamRegistry } from './registry/logStreamRegistry'
+import { LogStreamTailer } from './registry/logStreamTailer'
+import { openInsightsCommand } from './vue/logsInsights'
 
 export async function activate(context: vscode.ExtensionContext, configuration: SettingsConfiguration): Promise<void> {
     const registry = new LogStreamRegistry(configuration)
+    const tailer = new LogStreamTailer(registry, configuration)
+    context.subscriptions.push(tailer)
 
     context.subscriptions.push(
         vscode.workspace.registerTextDocumentContentProvider(
@@ -28,22 +37,49 @@ export async function activate(context: vscode.ExtensionContext, configuration:
     context.subscriptions.push(
         vscode.workspace.onDidCloseTextDocument(doc => {
             if (doc.isClosed && doc.uri.scheme === CLOUDWATCH_LOGS_SCHEME) {
+                tailer.stop(doc.uri)
                 registry.deregisterLog(doc.uri)
             }
         })
     )
 
+    // Keep the end of the log in view while tailing.
+    context.subscriptions.push(
+        vscode.workspace.onDidChangeTextDocument(event => {
+            const uri = event.document.uri
+            if (uri.scheme !== CLOUDWATCH_LOGS_SCHEME || !tailer.isTailing(uri)) {
+                return
+            }
+            for (const editor of vscode.window.visibleTextEditors) {
+                if (editor.document === event.document) {
+                    const lastLine = editor.document.lineCount - 1
+                    editor.revealRange(new vscode.Range(lastLine, 0, lastLine, 0))
+                }
+            }
+        })
+    )
+
     context.subscriptions.push(
         vscode.languages.registerCodeLensProvider(
             {
                 language: 'log',
                 scheme: CLOUDWATCH_LOGS_SCHEME,
             },
-            new LogStreamCodeLensProvider(registry)
+            new LogStreamCodeLensProvider(registry, tailer)
         )
     )
 
     context.subscriptions.push(vscode.commands.registerCommand('aws.copyLogStreamName', copyLogStreamName))
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.cloudWatchLogs.copyLogEventsAsJson',
+            async () => await copyLogEvents(registry)
+        ),
+        vscode.commands.registerCommand(
+            'aws.cloudWatchLogs.copyLogEventsWithTimestamp',
+            async () => await copyLogEvents(registry, { timestamp: true })
+        )
+    )
     context.subscriptions.push(
         vscode.commands.registerCommand(
             'aws.addLogEvents',
@@ -55,6 +91,12 @@ export async function activate(context: vscode.ExtensionContext, configuration:
             ) => addLogEvents(document, registry, headOrTail, onDidChangeCodeLensEvent, configuration)
         )
     )
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.cloudWatchLogs.toggleTail',
+            async (uri?: vscode.Uri) => await toggleLogStreamTail(uri, tailer)
+        )
+    )
     context.subscriptions.push(
         vscode.commands.registerCommand(
             'aws.saveCurrentLogStreamContent',
@@ -70,4 +112,16 @@ export async function activate(context: vscode.ExtensionContext, configuration:
             async (node: LogGroupNode) => await viewLogStream(node, registry)
         )
     )
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.cloudWatchLogs.openInsights',
+            async (node: CloudWatchLogsNode | LogGroupNode) => await openInsightsCommand(node, context)
+        )
+    )
+    context.subscriptions.push(
+        vscode.commands.registerCommand(
+            'aws.lambda.viewLogs',
+            async (node: LambdaFunctionNode) => await viewLambdaLogs(node, registry)
+        )
+    )
 }
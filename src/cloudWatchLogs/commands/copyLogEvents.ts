@@ -0,0 +1,133 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { CloudWatchLogs } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { CLOUDWATCH_LOGS_SCHEME, COPY_TO_CLIPBOARD_INFO_TIMEOUT_MS } from '../../shared/constants'
+import { recordCloudwatchlogsCopyLogEvents, Result } from '../../shared/telemetry/telemetry'
+import { addCodiconToString } from '../../shared/utilities/textUtilities'
+import { Env } from '../../shared/vscode/env'
+import { Window } from '../../shared/vscode/window'
+import { LogStreamRegistry } from '../registry/logStreamRegistry'
+
+const TRAILING_NEW_LINE_REGEX = /(\r\n|\n|\r)$/
+
+export interface CopyLogEventsOptions {
+    /** Prepends the ISO timestamp of each event. */
+    timestamp?: boolean
+}
+
+/**
+ * Formats the message of a log event for the clipboard: messages that are JSON objects or arrays are
+ * pretty-printed, so that they aren't copied as one escaped line; other messages are copied as they are.
+ */
+export function formatLogMessage(message: string, timestamp?: number): string {
+    const text = message.replace(TRAILING_NEW_LINE_REGEX, '')
+    let formatted = text
+    try {
+        const parsed = JSON.parse(text)
+        if (typeof parsed === 'object' && parsed !== null) {
+            formatted = JSON.stringify(parsed, undefined, 4)
+        }
+    } catch (e) {
+        // not JSON, so copy the raw text
+    }
+
+    return timestamp === undefined ? formatted : `${new Date(timestamp).toISOString()} ${formatted}`
+}
+
+/**
+ * Copies the log events under the selections of a CloudWatch Logs editor to the clipboard, formatting
+ * JSON messages as pretty-printed JSON.
+ *
+ * When several events are selected, e.g. the lines of a stack trace that was logged as separate events,
+ * the user can choose to coalesce them into one message before formatting.
+ */
+export async function copyLogEvents(
+    registry: LogStreamRegistry,
+    { timestamp = false }: CopyLogEventsOptions = {},
+    editor: vscode.TextEditor | undefined = vscode.window.activeTextEditor,
+    window = Window.vscode(),
+    env = Env.vscode()
+): Promise<void> {
+    let result: Result = 'Succeeded'
+
+    try {
+        if (!editor || editor.document.uri.scheme !== CLOUDWATCH_LOGS_SCHEME) {
+            result = 'Failed'
+            window.showErrorMessage(
+                localize(
+                    'AWS.cloudWatchLogs.invalidEditor',
+                    'Not a Cloudwatch Log stream: {0}',
+                    editor?.document.fileName
+                )
+            )
+            return
+        }
+
+        const uri = editor.document.uri
+        const events: CloudWatchLogs.OutputLogEvents = []
+        for (const selection of editor.selections) {
+            for (const event of registry.getLogEventsInRange(uri, selection.start.line, selection.end.line)) {
+                if (!events.includes(event)) {
+                    events.push(event)
+                }
+            }
+        }
+        if (events.length === 0) {
+            result = 'Cancelled'
+            return
+        }
+
+        let text: string
+        if (events.length === 1) {
+            text = formatLogMessage(events[0].message ?? '', timestamp ? events[0].timestamp : undefined)
+        } else {
+            const coalesce = localize('AWS.cloudWatchLogs.copyLogEvents.coalesce', 'Coalesce')
+            const separately = localize('AWS.cloudWatchLogs.copyLogEvents.separately', 'Copy Separately')
+            const selection = await window.showInformationMessage(
+                localize(
+                    'AWS.cloudWatchLogs.copyLogEvents.prompt',
+                    'Coalesce the {0} selected log events into one message, e.g. to copy a stack trace that was logged line by line?',
+                    events.length
+                ),
+                { modal: true },
+                coalesce,
+                separately
+            )
+            if (selection === coalesce) {
+                const message = events
+                    .map(event => (event.message ?? '').replace(TRAILING_NEW_LINE_REGEX, ''))
+                    .join('\n')
+                text = formatLogMessage(message, timestamp ? events[0].timestamp : undefined)
+            } else if (selection === separately) {
+                text = events
+                    .map(event => formatLogMessage(event.message ?? '', timestamp ? event.timestamp : undefined))
+                    .join('\n')
+            } else {
+                result = 'Cancelled'
+                return
+            }
+        }
+
+        await env.clipboard.writeText(text)
+        window.setStatusBarMessage(
+            addCodiconToString(
+                'clippy',
+                localize(
+                    'AWS.cloudWatchLogs.copyLogEvents.copied',
+                    'Copied {0} log event(s) to clipboard',
+                    events.length
+                )
+            ),
+            COPY_TO_CLIPBOARD_INFO_TIMEOUT_MS
+        )
+    } finally {
+        recordCloudwatchlogsCopyLogEvents({ result })
+    }
+}
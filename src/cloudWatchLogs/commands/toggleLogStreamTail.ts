@@ -0,0 +1,40 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import * as vscode from 'vscode'
+import * as telemetry from '../../shared/telemetry/telemetry'
+import { parseCloudWatchLogsUri } from '../cloudWatchLogsUtils'
+import { LogStreamTailer } from '../registry/logStreamTailer'
+
+/**
+ * Starts or stops following new events for a CloudWatch Logs stream document.
+ */
+export async function toggleLogStreamTail(uri: vscode.Uri | undefined, tailer: LogStreamTailer): Promise<void> {
+    try {
+        if (!uri) {
+            // No URI = used command palette or status bar as entrypoint, attempt to get URI from active editor
+            uri = vscode.window.activeTextEditor?.document.uri
+            if (!uri) {
+                throw new Error()
+            }
+        }
+        parseCloudWatchLogsUri(uri)
+    } catch (e) {
+        vscode.window.showErrorMessage(
+            localize(
+                'AWS.cloudWatchLogs.invalidEditor',
+                'Not a Cloudwatch Log stream: {0}',
+                vscode.window.activeTextEditor?.document.fileName
+            )
+        )
+        return
+    }
+
+    tailer.toggle(uri)
+    telemetry.recordCloudwatchlogsTailStream({ result: 'Succeeded' })
+}
@@ -30,15 +30,7 @@ export async function viewLogStream(node: LogGroupNode, registry: LogStreamRegis
     let result: telemetry.Result = 'Succeeded'
     const logStreamResponse = await new SelectLogStreamWizard(node).run()
     if (logStreamResponse) {
-        const uri = convertLogGroupInfoToUri(
-            logStreamResponse.logGroupName,
-            logStreamResponse.logStreamName,
-            logStreamResponse.region
-        )
-        await registry.registerLog(uri)
-        const doc = await vscode.workspace.openTextDocument(uri) // calls back into the provider
-        vscode.languages.setTextDocumentLanguage(doc, 'log')
-        await vscode.window.showTextDocument(doc, { preview: false })
+        await openLogStream(logStreamResponse, registry)
     } else {
         result = 'Cancelled'
     }
@@ -46,6 +38,17 @@ export async function viewLogStream(node: LogGroupNode, registry: LogStreamRegis
     telemetry.recordCloudwatchlogsOpenStream({ result })
 }
 
+/**
+ * Opens a log stream in the CloudWatch Logs viewer.
+ */
+export async function openLogStream(logStream: SelectLogStreamResponse, registry: LogStreamRegistry): Promise<void> {
+    const uri = convertLogGroupInfoToUri(logStream.logGroupName, logStream.logStreamName, logStream.region)
+    await registry.registerLog(uri)
+    const doc = await vscode.workspace.openTextDocument(uri) // calls back into the provider
+    vscode.languages.setTextDocumentLanguage(doc, 'log')
+    await vscode.window.showTextDocument(doc, { preview: false })
+}
+
 export interface SelectLogStreamWizardContext {
     pickLogStream(): Promise<string | undefined>
 }
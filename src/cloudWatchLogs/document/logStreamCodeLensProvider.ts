@@ -9,9 +9,12 @@ const localize = nls.loadMessageBundle()
 import * as vscode from 'vscode'
 import { CLOUDWATCH_LOGS_SCHEME } from '../../shared/constants'
 import { LogStreamRegistry } from '../registry/logStreamRegistry'
+import { LogStreamTailer } from '../registry/logStreamTailer'
 
 export class LogStreamCodeLensProvider implements vscode.CodeLensProvider {
-    public constructor(private readonly registry: LogStreamRegistry) {}
+    public constructor(private readonly registry: LogStreamRegistry, private readonly tailer?: LogStreamTailer) {
+        tailer?.onDidChangeTailing(() => this._onDidChangeCodeLenses.fire())
+    }
 
     private _onDidChangeCodeLenses = new vscode.EventEmitter<void>()
     public get onDidChangeCodeLenses(): vscode.Event<void> {
@@ -30,7 +33,7 @@ export class LogStreamCodeLensProvider implements vscode.CodeLensProvider {
             command: 'aws.doNothingCommand',
         }
 
-        const codelenses = [
+        const codelenses: vscode.CodeLens[] = [
             // first line of virtual doc: always show "Load Older"
             {
                 range: new vscode.Range(new vscode.Position(0, 0), new vscode.Position(0, 0)),
@@ -60,6 +63,21 @@ export class LogStreamCodeLensProvider implements vscode.CodeLensProvider {
             },
         ]
 
+        if (this.tailer) {
+            const isTailing = this.tailer.isTailing(uri)
+            codelenses.push({
+                range: new vscode.Range(new vscode.Position(0, 0), new vscode.Position(0, 0)),
+                isResolved: true,
+                command: {
+                    title: isTailing
+                        ? localize('AWS.cloudWatchLogs.codeLens.stopTail', 'Stop tailing')
+                        : localize('AWS.cloudWatchLogs.codeLens.startTail', 'Tail (follow new events)'),
+                    command: 'aws.cloudWatchLogs.toggleTail',
+                    arguments: [uri],
+                },
+            })
+        }
+
         return uri.scheme === CLOUDWATCH_LOGS_SCHEME ? codelenses : []
     }
 }
@@ -18,12 +18,15 @@ import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
 import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
 import { LogGroupNode } from './logGroupNode'
 
+export const CONTEXT_VALUE_CLOUDWATCH_LOG_PARENT = 'awsCloudWatchLogParentNode'
+
 export class CloudWatchLogsNode extends AWSTreeNodeBase {
     private readonly logGroupNodes: Map<string, LogGroupNode>
 
-    public constructor(private readonly regionCode: string) {
+    public constructor(public readonly regionCode: string) {
         super('CloudWatch Logs', vscode.TreeItemCollapsibleState.Collapsed)
         this.logGroupNodes = new Map<string, LogGroupNode>()
+        this.contextValue = CONTEXT_VALUE_CLOUDWATCH_LOG_PARENT
     }
 
     public async getChildren(): Promise<AWSTreeNodeBase[]> {
@@ -0,0 +1,202 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// This file is also used by the Logs Insights webview, so it must not depend on `vscode`.
+
+import { CloudWatchLogs } from 'aws-sdk'
+
+/** Maximum number of log groups that a single Logs Insights query can search. */
+export const MAX_QUERY_LOG_GROUPS = 20
+
+/** Field holding the identifier of the log event behind a result row, which is not shown. */
+const POINTER_FIELD = '@ptr'
+
+export const DEFAULT_QUERY = 'fields @timestamp, @message\n| sort @timestamp desc\n| limit 20'
+
+export type InsightsTimeRange = { type: 'relative'; minutes: number } | { type: 'absolute'; start: number; end: number }
+
+export const RELATIVE_TIME_RANGES: readonly { label: string; minutes: number }[] = [
+    { label: '5m', minutes: 5 },
+    { label: '30m', minutes: 30 },
+    { label: '1h', minutes: 60 },
+    { label: '3h', minutes: 3 * 60 },
+    { label: '12h', minutes: 12 * 60 },
+    { label: '1d', minutes: 24 * 60 },
+    { label: '1w', minutes: 7 * 24 * 60 },
+]
+
+export interface InsightsQuery {
+    queryString: string
+    logGroupNames: string[]
+}
+
+export interface QueryResultTable {
+    columns: string[]
+    rows: { [column: string]: string }[]
+}
+
+/**
+ * Statuses after which `GetQueryResults` no longer changes.
+ */
+export function isQueryFinished(status: string | undefined): boolean {
+    return status !== 'Scheduled' && status !== 'Running'
+}
+
+/**
+ * Converts a time range to the start and end times expected by `StartQuery`, in seconds since the epoch.
+ *
+ * @throws Error if the range ends before it starts
+ */
+export function resolveTimeRange(
+    range: InsightsTimeRange,
+    now: number = Date.now()
+): { startTime: number; endTime: number } {
+    const [start, end] = range.type === 'relative' ? [now - range.minutes * 60 * 1000, now] : [range.start, range.end]
+    if (!(start < end)) {
+        throw new Error('The start of the time range must be before its end')
+    }
+
+    return { startTime: Math.floor(start / 1000), endTime: Math.ceil(end / 1000) }
+}
+
+/**
+ * Converts query results into table rows, with columns in the order the fields first appear.
+ */
+export function toResultTable(results: CloudWatchLogs.QueryResults): QueryResultTable {
+    const columns: string[] = []
+    const rows = results.map(fields => {
+        const row: QueryResultTable['rows'][number] = {}
+        for (const { field, value } of fields) {
+            if (!field || field === POINTER_FIELD) {
+                continue
+            }
+            if (!columns.includes(field)) {
+                columns.push(field)
+            }
+            row[field] = value ?? ''
+        }
+
+        return row
+    })
+
+    return { columns, rows }
+}
+
+/**
+ * Compares cell values numerically when both are numbers, and as text otherwise.
+ * Timestamps such as `2021-01-01 12:00:00.000` sort correctly as text.
+ */
+export function compareCells(a: string | undefined, b: string | undefined): number {
+    const left = a ?? ''
+    const right = b ?? ''
+    const leftNumber = Number(left)
+    const rightNumber = Number(right)
+    if (left.trim() !== '' && right.trim() !== '' && !isNaN(leftNumber) && !isNaN(rightNumber)) {
+        return leftNumber - rightNumber
+    }
+
+    return left.localeCompare(right)
+}
+
+export function sortRows(
+    rows: QueryResultTable['rows'],
+    column: string,
+    descending: boolean
+): QueryResultTable['rows'] {
+    const direction = descending ? -1 : 1
+
+    return [...rows].sort((a, b) => direction * compareCells(a[column], b[column]))
+}
+
+export type QueryTokenType =
+    | 'command'
+    | 'keyword'
+    | 'function'
+    | 'field'
+    | 'string'
+    | 'regex'
+    | 'number'
+    | 'comment'
+    | 'text'
+
+export interface QueryToken {
+    type: QueryTokenType
+    text: string
+}
+
+const COMMANDS = ['display', 'fields', 'filter', 'stats', 'sort', 'limit', 'parse', 'dedup']
+const KEYWORDS = ['by', 'as', 'and', 'or', 'not', 'like', 'in', 'asc', 'desc']
+
+const TOKEN_PATTERN = /(#[^\n]*)|('(?:[^'\\\n]|\\.)*'?|"(?:[^"\\\n]|\\.)*"?|`[^`\n]*`?)|(@[\w.]+)|(\d+(?:\.\d+)?)|([A-Za-z_][\w.]*)|(\/(?:[^/\\\n]|\\.)*\/?)|([\s\S])/g
+
+/**
+ * Splits a Logs Insights query into tokens for syntax highlighting. Joining the text of the tokens gives back the
+ * query, so that the highlighted text lines up with the text being edited.
+ */
+export function tokenizeQuery(query: string): QueryToken[] {
+    const tokens: QueryToken[] = []
+    let match: RegExpExecArray | null
+
+    TOKEN_PATTERN.lastIndex = 0
+    while ((match = TOKEN_PATTERN.exec(query))) {
+        const [text, comment, quoted, field, num, word, regex] = match
+        const before = findLast(tokens, token => token.text.trim() !== '')
+        let type: QueryTokenType = 'text'
+        if (comment) {
+            type = 'comment'
+        } else if (quoted) {
+            type = 'string'
+        } else if (field) {
+            type = 'field'
+        } else if (num) {
+            type = 'number'
+        } else if (word) {
+            const lower = word.toLowerCase()
+            if (COMMANDS.includes(lower) && (!before || before.text.endsWith('|'))) {
+                type = 'command'
+            } else if (KEYWORDS.includes(lower)) {
+                type = 'keyword'
+            } else if (query.charAt(match.index + word.length) === '(') {
+                type = 'function'
+            }
+        } else if (regex) {
+            // "/" is also the division operator; it only starts a pattern after a command, keyword, or comparison.
+            if (!before || before.type === 'command' || before.type === 'keyword' || /[=~<>(,]$/.test(before.text)) {
+                type = 'regex'
+            } else {
+                TOKEN_PATTERN.lastIndex = match.index + 1
+                pushToken(tokens, { type, text: '/' })
+                continue
+            }
+        }
+
+        pushToken(tokens, { type, text })
+    }
+
+    return tokens
+}
+
+function findLast<T>(items: T[], predicate: (item: T) => boolean): T | undefined {
+    for (let i = items.length - 1; i >= 0; i--) {
+        if (predicate(items[i])) {
+            return items[i]
+        }
+    }
+
+    return undefined
+}
+
+/**
+ * Adds a token, merging consecutive plain text so that fewer elements are rendered.
+ */
+function pushToken(tokens: QueryToken[], token: QueryToken): void {
+    const last = tokens[tokens.length - 1]
+    const isSpace = (text: string) => text.trim() === ''
+    if (last && last.type === 'text' && token.type === 'text' && isSpace(last.text) === isSpace(token.text)) {
+        last.text += token.text
+    } else {
+        tokens.push(token)
+    }
+}
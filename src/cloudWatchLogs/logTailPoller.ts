@@ -0,0 +1,106 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+
+/** How often new events are requested while tailing. */
+export const TAIL_POLL_INTERVAL_MILLIS = 2000
+/** Upper bound for the delay between reconnect attempts after a failure. */
+export const TAIL_MAX_BACKOFF_MILLIS = 30000
+
+export interface LogTailPollerOptions {
+    pollIntervalMillis?: number
+    /** Called when a poll fails, with the number of consecutive failures. */
+    onFailure?(error: Error, failures: number): void
+    /** Called when a poll succeeds after failing. */
+    onRecover?(): void
+}
+
+/**
+ * Requests new log events until stopped (similar to `tail -f`).
+ *
+ * Note: the `StartLiveTail` API is not available in the AWS SDK version used by the
+ * Toolkit, so new events are polled for instead. Failures (expired credentials, throttling,
+ * network loss) do not stop the poller; polls are retried with backoff until it is stopped.
+ */
+export class LogTailPoller implements vscode.Disposable {
+    private readonly pollIntervalMillis: number
+    private timer?: NodeJS.Timeout
+    private _failures = 0
+    private _stopped = false
+    private resolveDone!: () => void
+    /** Resolves once the poller is stopped. */
+    public readonly done = new Promise<void>(resolve => (this.resolveDone = resolve))
+
+    public constructor(
+        private readonly pollFn: () => Promise<void>,
+        private readonly options: LogTailPollerOptions = {}
+    ) {
+        this.pollIntervalMillis = options.pollIntervalMillis ?? TAIL_POLL_INTERVAL_MILLIS
+    }
+
+    /** The number of consecutive failed polls. */
+    public get failures(): number {
+        return this._failures
+    }
+
+    public get stopped(): boolean {
+        return this._stopped
+    }
+
+    public start(): void {
+        if (!this.timer && !this._stopped) {
+            this.schedule(0)
+        }
+    }
+
+    /**
+     * Stops polling. The result of a poll in flight should be discarded by checking {@link stopped}.
+     */
+    public stop(): void {
+        if (this._stopped) {
+            return
+        }
+        this._stopped = true
+        if (this.timer) {
+            clearTimeout(this.timer)
+        }
+        this.resolveDone()
+    }
+
+    public dispose(): void {
+        this.stop()
+    }
+
+    private schedule(delayMillis: number): void {
+        this.timer = setTimeout(async () => {
+            await this.poll()
+            if (!this._stopped) {
+                this.schedule(this.getDelay())
+            }
+        }, delayMillis)
+    }
+
+    private async poll(): Promise<void> {
+        try {
+            await this.pollFn()
+            if (this._failures > 0) {
+                this._failures = 0
+                this.options.onRecover?.()
+            }
+        } catch (e) {
+            this._failures++
+            this.options.onFailure?.(e as Error, this._failures)
+        }
+    }
+
+    private getDelay(): number {
+        if (this._failures === 0) {
+            return this.pollIntervalMillis
+        }
+
+        return Math.min(this.pollIntervalMillis * Math.pow(2, this._failures), TAIL_MAX_BACKOFF_MILLIS)
+    }
+}
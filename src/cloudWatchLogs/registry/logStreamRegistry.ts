@@ -18,6 +18,9 @@ import { SettingsConfiguration } from '../../shared/settingsConfiguration'
 
 // TODO: Add debug logging statements
 
+// newlines within a log message, which split it across lines; a trailing newline doesn't
+const INLINE_NEW_LINE_REGEX = /((\r\n)|\n|\r)(?!$)/g
+
 /**
  * Class which contains CRUD operations and persistence for CloudWatch Logs streams.
  */
@@ -74,8 +77,6 @@ export class LogStreamRegistry {
      * @param formatting Optional params for outputting log messages.
      */
     public getLogContent(uri: vscode.Uri, formatting?: { timestamps?: boolean }): string | undefined {
-        const inlineNewLineRegex = /((\r\n)|\n|\r)(?!$)/g
-
         // if no timestamp for some reason, entering a blank of equal length (29 characters long)
         const timestampSpaceEquivalent = '                             '
 
@@ -95,7 +96,7 @@ export class LogStreamRegistry {
                     : timestampSpaceEquivalent
                 line = timestamp.concat('\t', line)
                 // log entries containing newlines are indented to the same length as the timestamp.
-                line = line.replace(inlineNewLineRegex, `\n${timestampSpaceEquivalent}\t`)
+                line = line.replace(INLINE_NEW_LINE_REGEX, `\n${timestampSpaceEquivalent}\t`)
             }
             if (!line.endsWith('\n')) {
                 line = line.concat('\n')
@@ -106,6 +107,35 @@ export class LogStreamRegistry {
         return output
     }
 
+    /**
+     * Returns the log events shown on the given (zero-based, inclusive) lines of the log content.
+     * Events with newlines in their message span several lines.
+     * @param uri Document URI
+     * @param startLine First line of the range
+     * @param endLine Last line of the range
+     */
+    public getLogEventsInRange(uri: vscode.Uri, startLine: number, endLine: number): CloudWatchLogs.OutputLogEvents {
+        const currData = this.getLog(uri)
+        if (!currData) {
+            return []
+        }
+
+        const events: CloudWatchLogs.OutputLogEvents = []
+        let line = 0
+        for (const datum of currData.data) {
+            const lineCount = ((datum.message ?? '').match(INLINE_NEW_LINE_REGEX) ?? []).length + 1
+            if (line > endLine) {
+                break
+            }
+            if (line + lineCount > startLine) {
+                events.push(datum)
+            }
+            line += lineCount
+        }
+
+        return events
+    }
+
     /**
      * Retrieves the next set of data for a log and adds it to the registry. Data can either be added to the front of the log (`'head'`) or end (`'tail'`)
      * @param uri Document URI
@@ -0,0 +1,168 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import * as vscode from 'vscode'
+import { CloudWatchLogs } from 'aws-sdk'
+import { parseCloudWatchLogsUri } from '../cloudWatchLogsUtils'
+import { CloudWatchLogsClient } from '../../shared/clients/cloudWatchLogsClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { SettingsConfiguration } from '../../shared/settingsConfiguration'
+import { LogTailPoller, TAIL_POLL_INTERVAL_MILLIS } from '../logTailPoller'
+import { LogStreamRegistry } from './logStreamRegistry'
+
+type GetLogEventsFn = (
+    logGroupInfo: { groupName: string; streamName: string; regionName: string },
+    nextToken?: string
+) => Promise<CloudWatchLogs.GetLogEventsResponse>
+
+interface TailSession {
+    readonly uri: vscode.Uri
+    readonly poller: LogTailPoller
+}
+
+/**
+ * Follows CloudWatch Logs streams that are open in the log viewer, appending new events
+ * to the document as they arrive. New events are requested with the stream's forward token.
+ */
+export class LogStreamTailer implements vscode.Disposable {
+    private readonly sessions = new Map<string, TailSession>()
+    private readonly _onDidChangeTailing = new vscode.EventEmitter<vscode.Uri>()
+    private readonly statusBarItem: vscode.StatusBarItem
+
+    public constructor(
+        private readonly registry: LogStreamRegistry,
+        private readonly configuration: SettingsConfiguration,
+        private readonly getLogEventsFn?: GetLogEventsFn,
+        private readonly pollIntervalMillis: number = TAIL_POLL_INTERVAL_MILLIS
+    ) {
+        this.statusBarItem = vscode.window.createStatusBarItem(vscode.StatusBarAlignment.Left, 1)
+        this.statusBarItem.command = 'aws.cloudWatchLogs.toggleTail'
+    }
+
+    /**
+     * Event fired when tailing starts or stops for a document.
+     */
+    public get onDidChangeTailing(): vscode.Event<vscode.Uri> {
+        return this._onDidChangeTailing.event
+    }
+
+    public isTailing(uri: vscode.Uri): boolean {
+        return this.sessions.has(uri.path)
+    }
+
+    public start(uri: vscode.Uri): void {
+        if (this.isTailing(uri) || !this.registry.hasLog(uri)) {
+            return
+        }
+        getLogger().debug(`Starting tail of ${uri.path}`)
+        const poller: LogTailPoller = new LogTailPoller(async () => this.poll(uri, poller), {
+            pollIntervalMillis: this.pollIntervalMillis,
+            onFailure: (error, failures) => {
+                getLogger().warn(`Failed to tail ${uri.path} (attempt ${failures}): %O`, error)
+                this.updateStatusBar()
+            },
+            onRecover: () => {
+                getLogger().info(`Reconnected tail of ${uri.path}`)
+                this.updateStatusBar()
+            },
+        })
+        this.sessions.set(uri.path, { uri, poller })
+        poller.start()
+        this.updateStatusBar()
+        this._onDidChangeTailing.fire(uri)
+    }
+
+    /**
+     * Stops tailing the given document. Any request in flight is discarded.
+     */
+    public stop(uri: vscode.Uri): void {
+        const session = this.sessions.get(uri.path)
+        if (!session) {
+            return
+        }
+        getLogger().debug(`Stopping tail of ${uri.path}`)
+        session.poller.stop()
+        this.sessions.delete(uri.path)
+        this.updateStatusBar()
+        this._onDidChangeTailing.fire(uri)
+    }
+
+    public toggle(uri: vscode.Uri): void {
+        if (this.isTailing(uri)) {
+            this.stop(uri)
+        } else {
+            this.start(uri)
+        }
+    }
+
+    public dispose(): void {
+        for (const session of this.sessions.values()) {
+            session.poller.stop()
+        }
+        this.sessions.clear()
+        this.statusBarItem.dispose()
+        this._onDidChangeTailing.dispose()
+    }
+
+    private async poll(uri: vscode.Uri, poller: LogTailPoller): Promise<void> {
+        let error: Error | undefined
+        await this.registry.updateLog(uri, 'tail', this.configuration, async (logGroupInfo, nextToken) => {
+            try {
+                const response = await this.getLogEvents(logGroupInfo, nextToken)
+
+                return poller.stopped ? { nextForwardToken: nextToken } : response
+            } catch (e) {
+                error = e as Error
+
+                // Keep the current position so that the next attempt resumes where this one left off.
+                return { nextForwardToken: nextToken }
+            }
+        })
+        if (error) {
+            throw error
+        }
+    }
+
+    private async getLogEvents(
+        logGroupInfo: { groupName: string; streamName: string; regionName: string },
+        nextToken?: string
+    ): Promise<CloudWatchLogs.GetLogEventsResponse> {
+        if (this.getLogEventsFn) {
+            return this.getLogEventsFn(logGroupInfo, nextToken)
+        }
+        const client: CloudWatchLogsClient = ext.toolkitClientBuilder.createCloudWatchLogsClient(
+            logGroupInfo.regionName
+        )
+
+        return client.getLogEvents({
+            logGroupName: logGroupInfo.groupName,
+            logStreamName: logGroupInfo.streamName,
+            nextToken,
+            limit: this.configuration.readSetting('cloudWatchLogs.limit', 1000),
+        })
+    }
+
+    private updateStatusBar(): void {
+        if (this.sessions.size === 0) {
+            this.statusBarItem.hide()
+            return
+        }
+        const reconnecting = [...this.sessions.values()].some(session => session.poller.failures > 0)
+        const streams = [...this.sessions.values()].map(session => parseCloudWatchLogsUri(session.uri).streamName)
+        this.statusBarItem.text = reconnecting
+            ? localize('AWS.cloudWatchLogs.tail.statusBar.reconnecting', '$(sync~spin) Tailing logs (reconnecting...)')
+            : localize('AWS.cloudWatchLogs.tail.statusBar', '$(sync~spin) Tailing {0} log stream(s)', streams.length)
+        this.statusBarItem.tooltip = localize(
+            'AWS.cloudWatchLogs.tail.statusBar.tooltip',
+            'Tailing: {0}. Click to stop tailing the active log stream.',
+            streams.join(', ')
+        )
+        this.statusBarItem.show()
+    }
+}
@@ -0,0 +1,264 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CloudWatchLogs } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { CloudWatchLogsClient } from '../../shared/clients/cloudWatchLogsClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import {
+    recordCloudwatchlogsOpenInsights,
+    recordCloudwatchlogsRunInsightsQuery,
+    Result,
+} from '../../shared/telemetry/telemetry'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { createVueWebview } from '../../webviews/main'
+import { CloudWatchLogsNode } from '../explorer/cloudWatchLogsNode'
+import { LogGroupNode } from '../explorer/logGroupNode'
+import {
+    InsightsQuery,
+    InsightsTimeRange,
+    isQueryFinished,
+    MAX_QUERY_LOG_GROUPS,
+    QueryResultTable,
+    resolveTimeRange,
+    toResultTable,
+} from '../insightsUtils'
+
+/** Time between `GetQueryResults` calls while a query is running. */
+export const QUERY_POLL_INTERVAL_MS = 1000
+
+export const MAX_RECENT_QUERIES = 10
+
+export interface LogsInsightsState {
+    queryString: string
+    logGroupNames: string[]
+    timeRange: InsightsTimeRange
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface RunQueryRequest {
+    command: 'runQuery'
+    data: InsightsQuery & {
+        timeRange: InsightsTimeRange
+    }
+}
+
+export interface StopQueryRequest {
+    command: 'stopQuery'
+}
+
+export interface InitializeResponse {
+    command: 'initialize'
+    data: {
+        regionCode: string
+        logGroupNames: string[]
+        selectedLogGroupNames: string[]
+        recentQueries: InsightsQuery[]
+    }
+}
+
+export interface QueryResultsResponse {
+    command: 'queryResults'
+    data: QueryResultTable & {
+        status: string
+        recordsMatched?: number
+        recordsScanned?: number
+    }
+}
+
+export interface RecentQueriesResponse {
+    command: 'recentQueries'
+    data: {
+        recentQueries: InsightsQuery[]
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        message: string
+    }
+}
+
+export type LogsInsightsRequest = InitializeRequest | RunQueryRequest | StopQueryRequest
+export type LogsInsightsResponse = InitializeResponse | QueryResultsResponse | RecentQueriesResponse | ErrorResponse
+
+/**
+ * Keeps the most recently run queries of a workspace, most recent first.
+ */
+export class RecentInsightsQueries {
+    private static readonly stateKey = 'aws.cloudWatchLogs.insights.recentQueries'
+
+    public constructor(private readonly memento: vscode.Memento) {}
+
+    public get(): InsightsQuery[] {
+        return this.memento.get<InsightsQuery[]>(RecentInsightsQueries.stateKey, [])
+    }
+
+    public async add(query: InsightsQuery): Promise<InsightsQuery[]> {
+        const isSame = (other: InsightsQuery) =>
+            other.queryString === query.queryString &&
+            other.logGroupNames.length === query.logGroupNames.length &&
+            other.logGroupNames.every(name => query.logGroupNames.includes(name))
+        const queries = [query, ...this.get().filter(other => !isSame(other))].slice(0, MAX_RECENT_QUERIES)
+        await this.memento.update(RecentInsightsQueries.stateKey, queries)
+
+        return queries
+    }
+}
+
+/**
+ * Runs a Logs Insights query, polling for results until the query finishes or is stopped.
+ */
+export class InsightsQueryRunner {
+    private queryId: string | undefined
+    private stopped = false
+
+    public constructor(
+        private readonly client: CloudWatchLogsClient,
+        private readonly pollIntervalMs: number = QUERY_POLL_INTERVAL_MS
+    ) {}
+
+    /**
+     * Starts a query and reports its results every time they are polled. Results are partial until the status
+     * is `Complete`.
+     *
+     * @returns the final status of the query
+     */
+    public async run(
+        request: CloudWatchLogs.StartQueryRequest,
+        onResults: (data: QueryResultsResponse['data']) => Promise<unknown>
+    ): Promise<string> {
+        const { queryId } = await this.client.startQuery(request)
+        if (!queryId) {
+            throw new Error('StartQuery did not return a query ID')
+        }
+        this.queryId = queryId
+
+        try {
+            while (true) {
+                const response = await this.client.getQueryResults(queryId)
+                const status = this.stopped ? 'Cancelled' : response.status ?? 'Unknown'
+                await onResults({
+                    ...toResultTable(response.results ?? []),
+                    status,
+                    recordsMatched: response.statistics?.recordsMatched,
+                    recordsScanned: response.statistics?.recordsScanned,
+                })
+                if (isQueryFinished(status)) {
+                    return status
+                }
+                await new Promise(resolve => setTimeout(resolve, this.pollIntervalMs))
+            }
+        } finally {
+            this.queryId = undefined
+        }
+    }
+
+    /**
+     * Stops the running query, if any. Results found so far are reported with the status `Cancelled`.
+     */
+    public async stop(): Promise<void> {
+        if (!this.queryId || this.stopped) {
+            return
+        }
+        this.stopped = true
+        await this.client.stopQuery(this.queryId)
+    }
+}
+
+/**
+ * Opens a Logs Insights query editor for the log groups of a region.
+ *
+ * @param node the CloudWatch Logs node of a region, or a log group to select initially
+ */
+export async function openInsightsCommand(
+    node: CloudWatchLogsNode | LogGroupNode,
+    context: vscode.ExtensionContext = ext.context
+): Promise<void> {
+    const regionCode = node.regionCode
+    const client = ext.toolkitClientBuilder.createCloudWatchLogsClient(regionCode)
+    const recentQueries = new RecentInsightsQueries(context.workspaceState)
+    let runner: InsightsQueryRunner | undefined
+
+    await createVueWebview<LogsInsightsRequest, LogsInsightsResponse>({
+        id: 'cloudWatchLogsInsights',
+        name: localize('AWS.cloudWatchLogs.insights.title', 'Logs Insights: {0}', regionCode),
+        webviewJs: 'cloudWatchLogsInsightsVue.js',
+        cssFiles: ['cloudWatchLogsInsights.css'],
+        context,
+        persistWithoutFocus: true,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            try {
+                switch (message.command) {
+                    case 'initialize': {
+                        const logGroups = await toArrayAsync(client.describeLogGroups())
+                        await postMessageFn({
+                            command: 'initialize',
+                            data: {
+                                regionCode,
+                                logGroupNames: logGroups.map(logGroup => logGroup.logGroupName!).sort(),
+                                selectedLogGroupNames: node instanceof LogGroupNode ? [node.name] : [],
+                                recentQueries: recentQueries.get(),
+                            },
+                        })
+                        break
+                    }
+                    case 'runQuery': {
+                        const { queryString, logGroupNames, timeRange } = message.data
+                        if (logGroupNames.length === 0 || logGroupNames.length > MAX_QUERY_LOG_GROUPS) {
+                            throw new Error(
+                                localize(
+                                    'AWS.cloudWatchLogs.insights.logGroupCount',
+                                    'Select between 1 and {0} log groups',
+                                    MAX_QUERY_LOG_GROUPS
+                                )
+                            )
+                        }
+                        await runner?.stop()
+                        const current = new InsightsQueryRunner(client)
+                        runner = current
+
+                        await postMessageFn({
+                            command: 'recentQueries',
+                            data: { recentQueries: await recentQueries.add({ queryString, logGroupNames }) },
+                        })
+                        let result: Result = 'Failed'
+                        try {
+                            const status = await current.run(
+                                { queryString, logGroupNames, ...resolveTimeRange(timeRange) },
+                                // Results of a query that was replaced by a newer one are dropped
+                                async data =>
+                                    current === runner ? postMessageFn({ command: 'queryResults', data }) : undefined
+                            )
+                            result =
+                                status === 'Complete' ? 'Succeeded' : status === 'Cancelled' ? 'Cancelled' : 'Failed'
+                        } finally {
+                            recordCloudwatchlogsRunInsightsQuery({ result })
+                        }
+                        break
+                    }
+                    case 'stopQuery':
+                        await runner?.stop()
+                        break
+                }
+            } catch (e) {
+                const error = e as Error
+                getLogger().error(`Logs Insights query failed in ${regionCode}: %O`, error)
+                await postMessageFn({ command: 'error', data: { message: error.message } })
+            }
+        },
+        onDidDisposeFunction: () => {
+            runner?.stop().catch(err => getLogger().warn('Failed to stop Logs Insights query: %O', err))
+        },
+    })
+
+    recordCloudwatchlogsOpenInsights({ result: 'Succeeded' })
+}
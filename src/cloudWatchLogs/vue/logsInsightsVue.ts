@@ -0,0 +1,301 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import {
+    DEFAULT_QUERY,
+    InsightsQuery,
+    InsightsTimeRange,
+    isQueryFinished,
+    MAX_QUERY_LOG_GROUPS,
+    QueryResultTable,
+    QueryToken,
+    RELATIVE_TIME_RANGES,
+    sortRows,
+    tokenizeQuery,
+} from '../insightsUtils'
+import { LogsInsightsResponse, LogsInsightsState } from './logsInsights'
+
+declare const vscode: WebviewApi<LogsInsightsState>
+
+const CUSTOM_TIME_RANGE = 'custom'
+const DEFAULT_RELATIVE_MINUTES = 60
+
+export interface LogsInsightsVueData {
+    regionCode: string
+    logGroupNames: string[]
+    selectedLogGroupNames: string[]
+    logGroupFilter: string
+    recentQueries: InsightsQuery[]
+    queryString: string
+    relativeTimeRanges: typeof RELATIVE_TIME_RANGES
+    /** Minutes of the selected relative time range, or `custom`. */
+    timeRangeSelection: number | typeof CUSTOM_TIME_RANGE
+    customStart: string
+    customEnd: string
+    status: string
+    columns: string[]
+    rows: QueryResultTable['rows']
+    recordsMatched?: number
+    recordsScanned?: number
+    sortColumn: string
+    sortDescending: boolean
+    errorMsg: string
+}
+
+/**
+ * Formats a time as the local time expected by `<input type="datetime-local">`.
+ */
+function toDateTimeLocal(time: number): string {
+    const date = new Date(time - new Date(time).getTimezoneOffset() * 60 * 1000)
+
+    return date.toISOString().substring(0, 16)
+}
+
+export const Component = Vue.extend({
+    created() {
+        const oldState = vscode.getState()
+        if (oldState) {
+            this.queryString = oldState.queryString
+            this.selectedLogGroupNames = oldState.logGroupNames
+            this.setTimeRange(oldState.timeRange)
+        }
+        window.addEventListener('message', ev => {
+            const event = ev.data as LogsInsightsResponse
+            switch (event.command) {
+                case 'initialize':
+                    this.regionCode = event.data.regionCode
+                    this.logGroupNames = event.data.logGroupNames
+                    this.recentQueries = event.data.recentQueries
+                    if (!oldState) {
+                        this.selectedLogGroupNames = event.data.selectedLogGroupNames
+                    }
+                    break
+                case 'queryResults':
+                    this.errorMsg = ''
+                    this.status = event.data.status
+                    this.columns = event.data.columns
+                    this.rows = event.data.rows
+                    this.recordsMatched = event.data.recordsMatched
+                    this.recordsScanned = event.data.recordsScanned
+                    break
+                case 'recentQueries':
+                    this.recentQueries = event.data.recentQueries
+                    break
+                case 'error':
+                    this.status = ''
+                    this.errorMsg = event.data.message
+                    break
+            }
+        })
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): LogsInsightsVueData {
+        const now = Date.now()
+
+        return {
+            regionCode: '',
+            logGroupNames: [],
+            selectedLogGroupNames: [],
+            logGroupFilter: '',
+            recentQueries: [],
+            queryString: DEFAULT_QUERY,
+            relativeTimeRanges: RELATIVE_TIME_RANGES,
+            timeRangeSelection: DEFAULT_RELATIVE_MINUTES,
+            customStart: toDateTimeLocal(now - DEFAULT_RELATIVE_MINUTES * 60 * 1000),
+            customEnd: toDateTimeLocal(now),
+            status: '',
+            columns: [],
+            rows: [],
+            recordsMatched: undefined,
+            recordsScanned: undefined,
+            sortColumn: '',
+            sortDescending: false,
+            errorMsg: '',
+        }
+    },
+    computed: {
+        running(): boolean {
+            return this.status !== '' && !isQueryFinished(this.status)
+        },
+        tokens(): QueryToken[] {
+            // A trailing newline is not rendered by <pre>, which would misalign the last line.
+            return tokenizeQuery(this.queryString + '\n')
+        },
+        filteredLogGroupNames(): string[] {
+            const filter = this.logGroupFilter.toLowerCase()
+
+            return this.logGroupNames.filter(name => name.toLowerCase().includes(filter))
+        },
+        sortedRows(): QueryResultTable['rows'] {
+            return this.sortColumn ? sortRows(this.rows, this.sortColumn, this.sortDescending) : this.rows
+        },
+        tooManyLogGroups(): boolean {
+            return this.selectedLogGroupNames.length > MAX_QUERY_LOG_GROUPS
+        },
+        maxLogGroups(): number {
+            return MAX_QUERY_LOG_GROUPS
+        },
+    },
+    methods: {
+        getTimeRange(): InsightsTimeRange {
+            if (this.timeRangeSelection === CUSTOM_TIME_RANGE) {
+                return {
+                    type: 'absolute',
+                    start: new Date(this.customStart).getTime(),
+                    end: new Date(this.customEnd).getTime(),
+                }
+            }
+
+            return { type: 'relative', minutes: this.timeRangeSelection }
+        },
+        setTimeRange(timeRange: InsightsTimeRange) {
+            if (timeRange.type === 'relative') {
+                this.timeRangeSelection = timeRange.minutes
+            } else {
+                this.timeRangeSelection = CUSTOM_TIME_RANGE
+                this.customStart = toDateTimeLocal(timeRange.start)
+                this.customEnd = toDateTimeLocal(timeRange.end)
+            }
+        },
+        runQuery() {
+            const data = {
+                queryString: this.queryString,
+                logGroupNames: this.selectedLogGroupNames,
+                timeRange: this.getTimeRange(),
+            }
+            vscode.setState(data)
+            this.errorMsg = ''
+            this.status = 'Scheduled'
+            this.columns = []
+            this.rows = []
+            this.recordsMatched = undefined
+            this.recordsScanned = undefined
+            vscode.postMessage({ command: 'runQuery', data })
+        },
+        stopQuery() {
+            vscode.postMessage({ command: 'stopQuery' })
+        },
+        loadRecentQuery(event: Event) {
+            const select = event.target as HTMLSelectElement
+            const query = select.value ? this.recentQueries[Number(select.value)] : undefined
+            if (query) {
+                this.queryString = query.queryString
+                // Log groups that were deleted since the query was run are dropped
+                this.selectedLogGroupNames = query.logGroupNames.filter(name => this.logGroupNames.includes(name))
+            }
+            select.value = ''
+        },
+        sortBy(column: string) {
+            this.sortDescending = this.sortColumn === column ? !this.sortDescending : false
+            this.sortColumn = column
+        },
+        syncScroll(event: Event) {
+            const textarea = event.target as HTMLTextAreaElement
+            const highlight = this.$refs.highlight as HTMLElement
+            highlight.scrollTop = textarea.scrollTop
+            highlight.scrollLeft = textarea.scrollLeft
+        },
+    },
+    template: `
+    <div class="logs-insights">
+        <h1>Logs Insights: {{ regionCode }}</h1>
+        <div class="insights-query">
+            <div class="log-groups">
+                <label for="log-group-filter">Log groups ({{ selectedLogGroupNames.length }} selected)</label>
+                <input id="log-group-filter" type="text" placeholder="Filter log groups" v-model="logGroupFilter" />
+                <div class="log-group-list">
+                    <label v-for="name in filteredLogGroupNames" :key="name">
+                        <input type="checkbox" :value="name" v-model="selectedLogGroupNames" /> {{ name }}
+                    </label>
+                    <p v-if="logGroupNames.length === 0">No log groups found.</p>
+                </div>
+                <p class="error" v-if="tooManyLogGroups">A query can search at most {{ maxLogGroups }} log groups.</p>
+            </div>
+            <div class="query-editor">
+                <pre class="query-highlight" ref="highlight" aria-hidden="true"><span
+                    v-for="(token, index) in tokens"
+                    :key="index"
+                    :class="'token-' + token.type"
+                >{{ token.text }}</span></pre>
+                <textarea
+                    class="query-input"
+                    spellcheck="false"
+                    v-model="queryString"
+                    v-on:scroll="syncScroll"
+                    v-on:keydown.ctrl.enter="runQuery"
+                    v-on:keydown.meta.enter="runQuery"
+                ></textarea>
+            </div>
+            <div class="query-controls">
+                <select v-model="timeRangeSelection">
+                    <option v-for="range in relativeTimeRanges" :key="range.minutes" :value="range.minutes">
+                        Last {{ range.label }}
+                    </option>
+                    <option value="custom">Custom</option>
+                </select>
+                <span v-if="timeRangeSelection === 'custom'">
+                    <input type="datetime-local" v-model="customStart" /> to
+                    <input type="datetime-local" v-model="customEnd" />
+                </span>
+                <button
+                    v-if="!running"
+                    :disabled="selectedLogGroupNames.length === 0 || tooManyLogGroups || !queryString.trim()"
+                    v-on:click="runQuery"
+                >
+                    Run query
+                </button>
+                <button v-else v-on:click="stopQuery">Cancel</button>
+                <select v-if="recentQueries.length > 0" v-on:change="loadRecentQuery">
+                    <option value="">Recent queries</option>
+                    <option
+                        v-for="(query, index) in recentQueries"
+                        :key="index"
+                        :value="index"
+                        :title="query.queryString"
+                    >
+                        {{ query.queryString.split('\\n').join(' ') }}
+                    </option>
+                </select>
+            </div>
+        </div>
+        <p class="error" v-if="errorMsg">{{ errorMsg }}</p>
+        <p class="query-status" v-if="status">
+            <span v-if="running">Running query...</span>
+            <span v-else-if="status === 'Cancelled'">Query cancelled.</span>
+            <span v-else-if="status !== 'Complete'">Query ended with status {{ status }}.</span>
+            <span v-if="recordsMatched !== undefined">
+                {{ rows.length }} of {{ recordsMatched }} records matched, {{ recordsScanned }} scanned.
+            </span>
+        </p>
+        <p v-if="status === 'Complete' && rows.length === 0">
+            No results. The selected log groups have no log events matching the query in this time range.
+        </p>
+        <table class="query-results" v-if="rows.length > 0">
+            <thead>
+                <tr>
+                    <th v-for="column in columns" :key="column" v-on:click="sortBy(column)">
+                        {{ column }}
+                        <span v-if="sortColumn === column">{{ sortDescending ? '▼' : '▲' }}</span>
+                    </th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr v-for="(row, index) in sortedRows" :key="index">
+                    <td v-for="column in columns" :key="column" :title="row[column]">{{ row[column] }}</td>
+                </tr>
+            </tbody>
+        </table>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
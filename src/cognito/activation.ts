@@ -0,0 +1,39 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { resetUserPassword } from './commands/resetUserPassword'
+import { searchUsers } from './commands/searchUsers'
+import { setUserEnabled } from './commands/setUserEnabled'
+import { viewUser } from './commands/viewUser'
+import { viewUserPool } from './commands/viewUserPool'
+import { CognitoUserNode } from './explorer/cognitoUserNode'
+import { CognitoUserPoolNode } from './explorer/cognitoUserPoolNode'
+
+/**
+ * Activates Cognito components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.cognito.viewUserPool', async (node: CognitoUserPoolNode) => {
+            await viewUserPool(node)
+        }),
+        vscode.commands.registerCommand('aws.cognito.searchUsers', async (node: CognitoUserPoolNode) => {
+            await searchUsers(node)
+        }),
+        vscode.commands.registerCommand('aws.cognito.viewUser', async (node: CognitoUserNode) => {
+            await viewUser(node)
+        }),
+        vscode.commands.registerCommand('aws.cognito.resetUserPassword', async (node: CognitoUserNode) => {
+            await resetUserPassword(node)
+        }),
+        vscode.commands.registerCommand('aws.cognito.enableUser', async (node: CognitoUserNode) => {
+            await setUserEnabled(node, true)
+        }),
+        vscode.commands.registerCommand('aws.cognito.disableUser', async (node: CognitoUserNode) => {
+            await setUserEnabled(node, false)
+        })
+    )
+}
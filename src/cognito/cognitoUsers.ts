@@ -0,0 +1,55 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../shared/logger'
+import { localize } from '../shared/utilities/vsCodeUtils'
+import { Commands } from '../shared/vscode/commands'
+import { Window } from '../shared/vscode/window'
+import { CognitoUserNode } from './explorer/cognitoUserNode'
+
+/** Matches filter expressions that are entered as they are, e.g. `phone_number ^= "+1"` or `status = "Enabled"`. */
+const FILTER_EXPRESSION = /^\s*[\w:]+\s*\^?=\s*".*"\s*$/
+
+export class UserNotFoundError extends Error {
+    public constructor(public readonly username: string, public readonly userPoolName: string) {
+        super(localize('AWS.cognito.userNotFound', 'User {0} no longer exists in {1}', username, userPoolName))
+    }
+}
+
+export function isUserNotFound(err: unknown): boolean {
+    return (err as { code?: string }).code === 'UserNotFoundException'
+}
+
+/**
+ * Tells the user that a user was deleted, and refreshes the user pool so that it is no longer shown.
+ */
+export async function showUserNotFound(node: CognitoUserNode, window: Window, commands: Commands): Promise<void> {
+    const error = new UserNotFoundError(node.username, node.parent.name)
+    getLogger().warn(error.message)
+    window.showWarningMessage(error.message)
+    await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+}
+
+/**
+ * Makes the ListUsers filter expression to search users with:
+ * - text with an `@` searches emails that start with it
+ * - other text searches usernames that start with it
+ * - filter expressions, e.g. `name ^= "Jane"`, are used as they are
+ *
+ * @returns the filter expression, or undefined to list all users
+ */
+export function toUserFilter(search: string): string | undefined {
+    const text = search.trim()
+    if (!text) {
+        return undefined
+    }
+    if (FILTER_EXPRESSION.test(text)) {
+        return text
+    }
+
+    const attribute = text.includes('@') ? 'email' : 'username'
+
+    return `${attribute} ^= "${text.replace(/(["\\])/g, '\\$1')}"`
+}
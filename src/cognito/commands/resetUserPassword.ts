@@ -0,0 +1,125 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordCognitoResetUserPassword, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { isUserNotFound, showUserNotFound } from '../cognitoUsers'
+import { CognitoUserNode } from '../explorer/cognitoUserNode'
+
+interface PasswordKindItem extends vscode.QuickPickItem {
+    permanent: boolean
+}
+
+/**
+ * Sets a new password for a Cognito user after the user confirms. Temporary passwords must be changed
+ * when the user next signs in. The password is entered in a password input box, and is never logged.
+ */
+export async function resetUserPassword(
+    node: CognitoUserNode,
+    window = Window.vscode(),
+    commands = Commands.vscode(),
+    promptUserFunction = promptUser
+): Promise<void> {
+    getLogger().debug('ResetUserPassword called for %s', node.username)
+    let result: Result = 'Succeeded'
+
+    try {
+        const password = await window.showInputBox({
+            prompt: localize('AWS.cognito.resetPassword.prompt', 'Enter the new password of user {0}', node.username),
+            password: true,
+            ignoreFocusOut: true,
+            validateInput: input =>
+                input ? undefined : localize('AWS.cognito.resetPassword.empty', 'Password must not be empty'),
+        })
+        if (password === undefined) {
+            result = 'Cancelled'
+            getLogger().info('ResetUserPassword cancelled')
+            return
+        }
+
+        const picker = createQuickPick<PasswordKindItem>({
+            options: {
+                ignoreFocusOut: true,
+                title: localize('AWS.cognito.resetPassword.title', 'Reset password of user {0}', node.username),
+            },
+            items: [
+                {
+                    label: localize('AWS.cognito.resetPassword.temporary', 'Temporary'),
+                    detail: localize(
+                        'AWS.cognito.resetPassword.temporaryDetail',
+                        'The user must choose a new password when they next sign in'
+                    ),
+                    permanent: false,
+                },
+                {
+                    label: localize('AWS.cognito.resetPassword.permanent', 'Permanent'),
+                    detail: localize(
+                        'AWS.cognito.resetPassword.permanentDetail',
+                        'The user signs in with this password'
+                    ),
+                    permanent: true,
+                },
+            ],
+        })
+        const kind = verifySinglePickerOutput(await promptUserFunction({ picker }))
+        if (!kind) {
+            result = 'Cancelled'
+            getLogger().info('ResetUserPassword cancelled')
+            return
+        }
+
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.cognito.resetPassword.confirm',
+                    'Reset the password of user {0}? The user can no longer sign in with their current password.',
+                    node.username
+                ),
+                confirm: localize('AWS.cognito.resetPassword.confirmButton', 'Reset password'),
+                cancel: localize('AWS.generic.cancel', 'Cancel'),
+            },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('ResetUserPassword cancelled')
+            return
+        }
+
+        try {
+            await node.parent.cognito.adminSetUserPassword(node.parent.id, node.username, password, kind.permanent)
+            getLogger().info('Reset the password of user %s', node.username)
+            window.showInformationMessage(
+                localize('AWS.cognito.resetPassword.success', 'Reset the password of user {0}', node.username)
+            )
+            await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+        } catch (err) {
+            result = 'Failed'
+            if (isUserNotFound(err)) {
+                await showUserNotFound(node, window, commands)
+                return
+            }
+
+            getLogger().error('Failed to reset the password of user %s: %s', node.username, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.cognito.resetPassword.failure',
+                    'Failed to reset the password of user {0}: {1}',
+                    node.username,
+                    (err as Error).message
+                ),
+                window
+            )
+        }
+    } finally {
+        recordCognitoResetUserPassword({ result })
+    }
+}
@@ -0,0 +1,43 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import { recordCognitoSearchUsers } from '../../shared/telemetry/telemetry'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { toUserFilter } from '../cognitoUsers'
+import { CognitoUserPoolNode } from '../explorer/cognitoUserPoolNode'
+
+/**
+ * Filters the users shown under a user pool by email or username, or by a ListUsers filter expression.
+ * Searching for nothing shows all users again.
+ */
+export async function searchUsers(
+    node: CognitoUserPoolNode,
+    window = Window.vscode(),
+    commands = Commands.vscode()
+): Promise<void> {
+    getLogger().debug('SearchUsers called for %s', node.id)
+
+    const search = await window.showInputBox({
+        prompt: localize(
+            'AWS.cognito.searchUsers.prompt',
+            'Enter the start of an email or username, or a filter expression such as name ^= "Jane". Leave empty to show all users.'
+        ),
+        placeHolder: localize('AWS.cognito.searchUsers.placeholder', 'jane@example.com'),
+        value: node.userFilter,
+        ignoreFocusOut: true,
+    })
+    if (search === undefined) {
+        getLogger().info('SearchUsers cancelled')
+        recordCognitoSearchUsers({ result: 'Cancelled' })
+        return
+    }
+
+    node.setUserFilter(toUserFilter(search))
+    await commands.execute('aws.refreshAwsExplorerNode', node)
+    recordCognitoSearchUsers({ result: 'Succeeded' })
+}
@@ -0,0 +1,97 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import { recordCognitoSetUserEnabled, Result } from '../../shared/telemetry/telemetry'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { isUserNotFound, showUserNotFound } from '../cognitoUsers'
+import { CognitoUserNode } from '../explorer/cognitoUserNode'
+
+/**
+ * Enables or disables a Cognito user after the user confirms. Disabled users can't sign in.
+ */
+export async function setUserEnabled(
+    node: CognitoUserNode,
+    enabled: boolean,
+    window = Window.vscode(),
+    commands = Commands.vscode()
+): Promise<void> {
+    getLogger().debug('SetUserEnabled called for %s (enabled: %s)', node.username, enabled)
+    let result: Result = 'Succeeded'
+
+    try {
+        const isConfirmed = await showConfirmationMessage(
+            enabled
+                ? {
+                      prompt: localize(
+                          'AWS.cognito.enableUser.confirm',
+                          'Enable user {0}? The user can sign in again.',
+                          node.username
+                      ),
+                      confirm: localize('AWS.cognito.enableUser.confirmButton', 'Enable'),
+                      cancel: localize('AWS.generic.cancel', 'Cancel'),
+                  }
+                : {
+                      prompt: localize(
+                          'AWS.cognito.disableUser.confirm',
+                          'Disable user {0}? The user can no longer sign in.',
+                          node.username
+                      ),
+                      confirm: localize('AWS.cognito.disableUser.confirmButton', 'Disable'),
+                      cancel: localize('AWS.generic.cancel', 'Cancel'),
+                  },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('SetUserEnabled cancelled')
+            return
+        }
+
+        try {
+            if (enabled) {
+                await node.parent.cognito.adminEnableUser(node.parent.id, node.username)
+            } else {
+                await node.parent.cognito.adminDisableUser(node.parent.id, node.username)
+            }
+            getLogger().info('%s user %s', enabled ? 'Enabled' : 'Disabled', node.username)
+            window.showInformationMessage(
+                enabled
+                    ? localize('AWS.cognito.enableUser.success', 'Enabled user {0}', node.username)
+                    : localize('AWS.cognito.disableUser.success', 'Disabled user {0}', node.username)
+            )
+            await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+        } catch (err) {
+            result = 'Failed'
+            if (isUserNotFound(err)) {
+                await showUserNotFound(node, window, commands)
+                return
+            }
+
+            getLogger().error('Failed to update user %s: %s', node.username, (err as Error).message)
+            showErrorWithLogs(
+                enabled
+                    ? localize(
+                          'AWS.cognito.enableUser.failure',
+                          'Failed to enable user {0}: {1}',
+                          node.username,
+                          (err as Error).message
+                      )
+                    : localize(
+                          'AWS.cognito.disableUser.failure',
+                          'Failed to disable user {0}: {1}',
+                          node.username,
+                          (err as Error).message
+                      ),
+                window
+            )
+        }
+    } finally {
+        recordCognitoSetUserEnabled({ result })
+    }
+}
@@ -0,0 +1,108 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CognitoIdentityServiceProvider } from 'aws-sdk'
+import * as vscode from 'vscode'
+import * as localizedText from '../../shared/localizedText'
+import { getLogger } from '../../shared/logger'
+import { recordCognitoViewUser, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Env } from '../../shared/vscode/env'
+import { Window } from '../../shared/vscode/window'
+import { isUserNotFound, showUserNotFound } from '../cognitoUsers'
+import { CognitoUserNode } from '../explorer/cognitoUserNode'
+
+const COPY_DISPLAY_TIMEOUT_MS = 2000
+
+export interface UserDetailItem extends vscode.QuickPickItem {
+    value: string
+}
+
+/**
+ * Builds the items showing the status and attributes of a user.
+ */
+export function makeUserDetailItems(user: CognitoIdentityServiceProvider.AdminGetUserResponse): UserDetailItem[] {
+    const item = (label: string, value: string | undefined) => ({ label, description: value ?? '', value: value ?? '' })
+    const mfa = user.UserMFASettingList?.length
+        ? user.UserMFASettingList.join(', ')
+        : localize('AWS.cognito.viewUser.noMfa', 'None')
+    const details: UserDetailItem[] = [
+        item(localize('AWS.cognito.viewUser.status', 'Status'), user.UserStatus),
+        item(
+            localize('AWS.cognito.viewUser.enabled', 'Enabled'),
+            user.Enabled === false ? localizedText.no : localizedText.yes
+        ),
+        item(localize('AWS.cognito.viewUser.mfa', 'MFA'), mfa),
+        item(localize('AWS.cognito.viewUser.created', 'Created'), user.UserCreateDate?.toLocaleString()),
+        item(localize('AWS.cognito.viewUser.modified', 'Last modified'), user.UserLastModifiedDate?.toLocaleString()),
+    ]
+    const attributes = (user.UserAttributes ?? []).map(attribute => item(attribute.Name, attribute.Value))
+
+    return [...details, ...attributes]
+}
+
+/**
+ * Shows the status and attributes of a Cognito user. Choosing one copies its value to the clipboard.
+ */
+export async function viewUser(
+    node: CognitoUserNode,
+    window = Window.vscode(),
+    env = Env.vscode(),
+    commands = Commands.vscode(),
+    promptUserFunction = promptUser
+): Promise<void> {
+    getLogger().debug('ViewUser called for %s', node.username)
+    let result: Result = 'Succeeded'
+
+    try {
+        let user: CognitoIdentityServiceProvider.AdminGetUserResponse
+        try {
+            user = await node.parent.cognito.adminGetUser(node.parent.id, node.username)
+        } catch (err) {
+            result = 'Failed'
+            if (isUserNotFound(err)) {
+                await showUserNotFound(node, window, commands)
+                return
+            }
+
+            getLogger().error('Failed to get user %s: %s', node.username, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.cognito.viewUser.failure',
+                    'Failed to get user {0}: {1}',
+                    node.username,
+                    (err as Error).message
+                ),
+                window
+            )
+            return
+        }
+
+        const picker = createQuickPick({
+            options: {
+                ignoreFocusOut: true,
+                title: localize('AWS.cognito.viewUser.title', 'User {0}', node.username),
+                placeHolder: localize('AWS.cognito.viewUser.placeholder', 'Choose a value to copy it'),
+            },
+            items: makeUserDetailItems(user),
+        })
+        const response = verifySinglePickerOutput(await promptUserFunction({ picker }))
+        if (!response) {
+            result = 'Cancelled'
+            return
+        }
+
+        await env.clipboard.writeText(response.value)
+        window.setStatusBarMessage(
+            localize('AWS.explorerNode.copiedToClipboard', '$(clippy) Copied {0} to clipboard', response.label),
+            COPY_DISPLAY_TIMEOUT_MS
+        )
+    } finally {
+        recordCognitoViewUser({ result })
+    }
+}
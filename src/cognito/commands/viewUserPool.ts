@@ -0,0 +1,101 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CognitoIdentityServiceProvider } from 'aws-sdk'
+import { getLogger } from '../../shared/logger'
+import { recordCognitoViewUserPool, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Env } from '../../shared/vscode/env'
+import { Window } from '../../shared/vscode/window'
+import { CognitoUserPoolNode } from '../explorer/cognitoUserPoolNode'
+import { UserDetailItem } from './viewUser'
+
+const COPY_DISPLAY_TIMEOUT_MS = 2000
+
+/**
+ * Builds the items showing the MFA and verification settings of a user pool.
+ */
+export function makeUserPoolDetailItems(userPool: CognitoIdentityServiceProvider.UserPoolType): UserDetailItem[] {
+    const none = localize('AWS.cognito.viewUserPool.none', 'None')
+    const item = (label: string, value: string | undefined) => ({ label, description: value ?? '', value: value ?? '' })
+    const list = (values: string[] | undefined) => (values?.length ? values.join(', ') : none)
+
+    return [
+        item(localize('AWS.cognito.viewUserPool.id', 'ID'), userPool.Id),
+        item(localize('AWS.cognito.viewUserPool.arn', 'ARN'), userPool.Arn),
+        item(localize('AWS.cognito.viewUserPool.mfa', 'MFA'), userPool.MfaConfiguration),
+        item(
+            localize('AWS.cognito.viewUserPool.autoVerified', 'Verified automatically'),
+            list(userPool.AutoVerifiedAttributes)
+        ),
+        item(
+            localize('AWS.cognito.viewUserPool.usernameAttributes', 'Sign in with'),
+            list(userPool.UsernameAttributes)
+        ),
+        item(
+            localize('AWS.cognito.viewUserPool.users', 'Estimated users'),
+            userPool.EstimatedNumberOfUsers?.toString()
+        ),
+        item(localize('AWS.cognito.viewUserPool.created', 'Created'), userPool.CreationDate?.toLocaleString()),
+    ]
+}
+
+/**
+ * Shows the MFA and verification settings of a user pool, which can't be changed from the toolkit.
+ * Choosing one copies its value to the clipboard.
+ */
+export async function viewUserPool(
+    node: CognitoUserPoolNode,
+    window = Window.vscode(),
+    env = Env.vscode(),
+    promptUserFunction = promptUser
+): Promise<void> {
+    getLogger().debug('ViewUserPool called for %s', node.id)
+    let result: Result = 'Succeeded'
+
+    try {
+        let userPool: CognitoIdentityServiceProvider.UserPoolType
+        try {
+            userPool = await node.cognito.describeUserPool(node.id)
+        } catch (err) {
+            result = 'Failed'
+            getLogger().error('Failed to describe user pool %s: %s', node.id, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.cognito.viewUserPool.failure',
+                    'Failed to describe user pool {0}: {1}',
+                    node.name,
+                    (err as Error).message
+                ),
+                window
+            )
+            return
+        }
+
+        const picker = createQuickPick({
+            options: {
+                ignoreFocusOut: true,
+                title: localize('AWS.cognito.viewUserPool.title', 'User pool {0}', node.name),
+                placeHolder: localize('AWS.cognito.viewUserPool.placeholder', 'Choose a value to copy it'),
+            },
+            items: makeUserPoolDetailItems(userPool),
+        })
+        const response = verifySinglePickerOutput(await promptUserFunction({ picker }))
+        if (!response) {
+            result = 'Cancelled'
+            return
+        }
+
+        await env.clipboard.writeText(response.value)
+        window.setStatusBarMessage(
+            localize('AWS.explorerNode.copiedToClipboard', '$(clippy) Copied {0} to clipboard', response.label),
+            COPY_DISPLAY_TIMEOUT_MS
+        )
+    } finally {
+        recordCognitoViewUserPool({ result })
+    }
+}
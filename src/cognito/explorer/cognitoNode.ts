@@ -0,0 +1,45 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { CognitoClient } from '../../shared/clients/cognitoClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { CognitoUserPoolNode } from './cognitoUserPoolNode'
+
+/**
+ * An AWS Explorer node representing Cognito.
+ *
+ * Contains user pools for a specific region as child nodes.
+ */
+export class CognitoNode extends AWSTreeNodeBase {
+    public constructor(private readonly cognito: CognitoClient) {
+        super('Cognito', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsCognitoNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const userPools = await toArrayAsync(this.cognito.listUserPools())
+
+                return userPools.map(userPool => new CognitoUserPoolNode(this, this.cognito, userPool))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.cognito.noUserPools', '[No user pools found]')),
+            sort: (item1: CognitoUserPoolNode, item2: CognitoUserPoolNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'CognitoNode'
+    }
+}
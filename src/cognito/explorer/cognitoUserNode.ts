@@ -0,0 +1,37 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CognitoIdentityServiceProvider } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { CognitoUserPoolNode } from './cognitoUserPoolNode'
+
+/**
+ * A user of a Cognito user pool, described by its email (if it has one) and status, e.g. `CONFIRMED`.
+ */
+export class CognitoUserNode extends AWSTreeNodeBase {
+    public readonly username: string
+
+    public constructor(
+        public readonly parent: CognitoUserPoolNode,
+        public readonly user: CognitoIdentityServiceProvider.UserType
+    ) {
+        super(user.Username ?? '', vscode.TreeItemCollapsibleState.None)
+        this.username = user.Username ?? ''
+        const email = user.Attributes?.find(attribute => attribute.Name === 'email')?.Value
+        const status = this.enabled
+            ? user.UserStatus
+            : localize('AWS.explorerNode.cognito.disabled', '{0}, disabled', user.UserStatus)
+        this.description = email ? `${email} (${status})` : status
+        this.tooltip = `${this.username}\n${status}`
+        // the context value tells which of enable and disable applies
+        this.contextValue = this.enabled ? 'awsCognitoUserNode' : 'awsCognitoDisabledUserNode'
+    }
+
+    public get enabled(): boolean {
+        return this.user.Enabled !== false
+    }
+}
@@ -0,0 +1,106 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CognitoIdentityServiceProvider } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { ChildNodeLoader, ChildNodePage } from '../../awsexplorer/childNodeLoader'
+import { CognitoClient } from '../../shared/clients/cognitoClient'
+import { getLogger } from '../../shared/logger'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { LoadMoreNode } from '../../shared/treeview/nodes/loadMoreNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { CognitoNode } from './cognitoNode'
+import { CognitoUserNode } from './cognitoUserNode'
+
+/** The most users that ListUsers returns at once. */
+const USERS_PER_PAGE = 60
+
+/**
+ * A Cognito user pool, whose users are listed a page at a time.
+ */
+export class CognitoUserPoolNode extends AWSTreeNodeBase implements LoadMoreNode {
+    public readonly id: string
+    public readonly name: string
+    private readonly childLoader: ChildNodeLoader
+    private filter: string | undefined
+
+    public constructor(
+        public readonly parent: CognitoNode,
+        public readonly cognito: CognitoClient,
+        userPool: CognitoIdentityServiceProvider.UserPoolDescriptionType
+    ) {
+        super(userPool.Name ?? '', vscode.TreeItemCollapsibleState.Collapsed)
+        this.id = userPool.Id ?? ''
+        this.name = userPool.Name ?? this.id
+        this.tooltip = `${this.name}\n${this.id}`
+        this.contextValue = 'awsCognitoUserPoolNode'
+        this.childLoader = new ChildNodeLoader(this, token => this.loadPage(token))
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => this.childLoader.getChildren(),
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(
+                    this,
+                    this.filter
+                        ? localize('AWS.explorerNode.cognito.noMatchingUsers', '[No users match {0}]', this.filter)
+                        : localize('AWS.explorerNode.cognito.noUsers', '[No users found]')
+                ),
+        })
+    }
+
+    public async loadMoreChildren(): Promise<void> {
+        await this.childLoader.loadMoreChildren()
+    }
+
+    public isLoadingMoreChildren(): boolean {
+        return this.childLoader.isLoadingMoreChildren()
+    }
+
+    public clearChildren(): void {
+        this.childLoader.clearChildren()
+    }
+
+    public get userFilter(): string | undefined {
+        return this.filter
+    }
+
+    /**
+     * Sets (or clears, if undefined) the ListUsers filter expression of the users listed under this node,
+     * e.g. `email ^= "jane"`.
+     */
+    public setUserFilter(filter: string | undefined): void {
+        this.filter = filter
+        this.label = filter
+            ? localize('AWS.explorerNode.cognito.filtered', '{0} (filter: {1})', this.name, filter)
+            : this.name
+        this.clearChildren()
+    }
+
+    private async loadPage(continuationToken: string | undefined): Promise<ChildNodePage> {
+        getLogger().debug(`Loading page for %O using continuationToken %s`, this, continuationToken)
+        const response = await this.cognito.listUsers({
+            userPoolId: this.id,
+            filter: this.filter,
+            paginationToken: continuationToken,
+            limit: USERS_PER_PAGE,
+        })
+
+        return {
+            newContinuationToken: response.paginationToken,
+            newChildren: response.users.map(user => new CognitoUserNode(this, user)),
+        }
+    }
+
+    public [inspect.custom](): string {
+        return `CognitoUserPoolNode (userPool=${this.id})`
+    }
+}
@@ -9,10 +9,13 @@ const localize = nls.loadMessageBundle()
 import * as vscode from 'vscode'
 import { AwsContext, ContextChangeEventsArgs } from '../shared/awsContext'
 import { getIdeProperties } from '../shared/extensionUtilities'
+import { CredentialsExpiryMonitor, CredentialsExpiryState } from './credentialsExpiryMonitor'
 
 const STATUSBAR_PRIORITY = 100
 const STATUSBAR_TEXT_NO_CREDENTIALS = localize('AWS.credentials.statusbar.no.credentials', '(not connected)')
 const STATUSBAR_TEXT_CONNECTED = localize('AWS.credentials.statusbar.connected', '(connected)')
+const STATUSBAR_TEXT_EXPIRING_SOON = localize('AWS.credentials.statusbar.expiringSoon', '(expiring soon)')
+const STATUSBAR_TEXT_EXPIRED = localize('AWS.credentials.statusbar.expired', '(expired)')
 const STATUSBAR_CONNECTED_DELAY = 1000
 
 // This is a module global since this code doesn't really warrant its own class
@@ -20,7 +23,8 @@ let timeoutID: NodeJS.Timeout
 
 export async function initializeAwsCredentialsStatusBarItem(
     awsContext: AwsContext,
-    context: vscode.ExtensionContext
+    context: vscode.ExtensionContext,
+    expiryMonitor?: CredentialsExpiryMonitor
 ): Promise<void> {
     const statusBarItem = vscode.window.createStatusBarItem(vscode.StatusBarAlignment.Right, STATUSBAR_PRIORITY)
     statusBarItem.command = 'aws.login'
@@ -39,12 +43,36 @@ export async function initializeAwsCredentialsStatusBarItem(
             updateCredentialsStatusBarItem(statusBarItem, awsContextChangedEvent.profileName)
         })
     )
+
+    if (expiryMonitor) {
+        context.subscriptions.push(
+            expiryMonitor.onDidChangeState(state =>
+                updateCredentialsStatusBarItem(statusBarItem, awsContext.getCredentialProfileName(), state)
+            )
+        )
+    }
 }
 
 // Resolves when the status bar reaches its final state
-export async function updateCredentialsStatusBarItem(statusBarItem: vscode.StatusBarItem, credentialsId?: string): Promise<void> {
+export async function updateCredentialsStatusBarItem(
+    statusBarItem: vscode.StatusBarItem,
+    credentialsId?: string,
+    expiryState: CredentialsExpiryState = 'valid'
+): Promise<void> {
     clearTimeout(timeoutID)
 
+    if (credentialsId && expiryState !== 'valid') {
+        statusBarItem.text = localize(
+            'AWS.credentials.statusbar.textWithState',
+            '{0}: {1} {2}',
+            getIdeProperties().company,
+            credentialsId,
+            expiryState === 'expired' ? STATUSBAR_TEXT_EXPIRED : STATUSBAR_TEXT_EXPIRING_SOON
+        )
+
+        return
+    }
+
     // Shows confirmation text in the status bar message
     let delay = 0
     if (credentialsId) {
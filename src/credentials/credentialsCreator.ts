@@ -7,6 +7,7 @@ import * as nls from 'vscode-nls'
 const localize = nls.loadMessageBundle()
 
 import { createInputBox, promptUser } from '../shared/ui/input'
+import { Timeout } from '../shared/utilities/timeoutUtils'
 
 const ERROR_MESSAGE_USER_CANCELLED = localize(
     'AWS.error.mfa.userCancelled',
@@ -29,25 +30,60 @@ export async function getMfaTokenFromUser(
     callback: (err?: Error, token?: string) => void
 ): Promise<void> {
     try {
-        const inputBox = createInputBox({
-            options: {
-                ignoreFocusOut: true,
-                placeHolder: localize('AWS.prompt.mfa.enterCode.placeholder', 'Enter Authentication Code Here'),
-                title: localize('AWS.prompt.mfa.enterCode.title', 'MFA Challenge for {0}', profileName),
-                prompt: localize('AWS.prompt.mfa.enterCode.prompt', 'Enter code for MFA device {0}', mfaSerial),
-            },
-        })
-
-        const token = await promptUser({ inputBox: inputBox })
-
-        // Distinguish user cancel vs code entry issues with the error message
-        if (!token) {
-            throw new Error(ERROR_MESSAGE_USER_CANCELLED)
-        }
-
-        callback(undefined, token)
+        callback(undefined, await promptForMfaToken(mfaSerial, profileName))
     } catch (err) {
         const error = err as Error
         callback(error)
     }
 }
+
+/**
+ * Prompts user for MFA token
+ *
+ * @param mfaSerial Serial arn of MFA device
+ * @param profileName Name of Credentials profile we are asking an MFA Token for
+ * @param opts.invalidCode Whether a code was already entered and rejected, which the prompt tells the user
+ * @param opts.timeout The prompt is dismissed when this completes, e.g. when the user cancels getting credentials
+ *
+ * @returns the entered token
+ * @throws Error with a fixed message string if the user cancels out, or the prompt is dismissed
+ */
+export async function promptForMfaToken(
+    mfaSerial: string,
+    profileName: string,
+    opts: { invalidCode?: boolean; timeout?: Timeout } = {}
+): Promise<string> {
+    const inputBox = createInputBox({
+        options: {
+            ignoreFocusOut: true,
+            placeHolder: localize('AWS.prompt.mfa.enterCode.placeholder', 'Enter Authentication Code Here'),
+            title: localize('AWS.prompt.mfa.enterCode.title', 'MFA Challenge for {0}', profileName),
+            prompt: localize('AWS.prompt.mfa.enterCode.prompt', 'Enter code for MFA device {0}', mfaSerial),
+        },
+    })
+    if (opts.invalidCode) {
+        // Cleared by the validation once the user types a new code
+        inputBox.validationMessage = localize(
+            'AWS.prompt.mfa.enterCode.invalid',
+            'Invalid MFA code. Enter the current code from the device.'
+        )
+    }
+
+    const hide = () => inputBox.hide()
+    opts.timeout?.timer.then(hide, hide)
+
+    const token = await promptUser({
+        inputBox: inputBox,
+        onValidateInput: value =>
+            /^\d{6}$/.test(value.trim())
+                ? undefined
+                : localize('AWS.prompt.mfa.enterCode.format', 'Authentication codes are 6 digits'),
+    })
+
+    // Distinguish user cancel vs code entry issues with the error message
+    if (!token) {
+        throw new Error(ERROR_MESSAGE_USER_CANCELLED)
+    }
+
+    return token.trim()
+}
@@ -0,0 +1,144 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import * as vscode from 'vscode'
+import { getLogger } from '../shared/logger'
+import { Window } from '../shared/vscode/window'
+import { asString, CredentialsId } from './providers/credentials'
+
+export type CredentialsExpiryState = 'valid' | 'expiringSoon' | 'expired'
+
+/** How long before the credentials expire the user is offered to re-authenticate. */
+export const EXPIRY_WARNING_MS = 10 * 60 * 1000
+const MS_PER_MINUTE = 60 * 1000
+
+export interface ExpiringCredentials {
+    readonly credentialsId: CredentialsId
+    readonly expiration: Date
+    /**
+     * Renews the credentials without prompting the user (which also tracks the renewed credentials).
+     * Resolves to false if the user has to log in again.
+     */
+    refresh?(): Promise<boolean>
+}
+
+/**
+ * Tracks when the active credentials expire, so that they can be renewed before AWS calls start failing.
+ *
+ * Shortly before the credentials expire, they are refreshed silently if possible. Otherwise the user is
+ * offered (without blocking) to re-authenticate.
+ */
+export class CredentialsExpiryMonitor implements vscode.Disposable {
+    private readonly onDidChangeStateEmitter = new vscode.EventEmitter<CredentialsExpiryState>()
+    private tracked: ExpiringCredentials | undefined
+    private timers: NodeJS.Timeout[] = []
+    private _state: CredentialsExpiryState = 'valid'
+
+    public readonly onDidChangeState: vscode.Event<CredentialsExpiryState> = this.onDidChangeStateEmitter.event
+
+    public constructor(
+        private readonly reauthenticate: (credentialsId: CredentialsId) => Promise<unknown>,
+        private readonly window: Window = Window.vscode(),
+        private readonly warningMs: number = EXPIRY_WARNING_MS
+    ) {}
+
+    public get state(): CredentialsExpiryState {
+        return this._state
+    }
+
+    /**
+     * When the tracked credentials expire, or undefined if they do not expire.
+     */
+    public get expiration(): Date | undefined {
+        return this.tracked?.expiration
+    }
+
+    /**
+     * Starts tracking the given credentials, replacing the previously tracked ones.
+     *
+     * @param credentials The active credentials, or undefined if there are none or they do not expire.
+     */
+    public track(credentials: ExpiringCredentials | undefined): void {
+        this.clearTimers()
+        this.tracked = credentials
+        if (!credentials) {
+            this.setState('valid')
+
+            return
+        }
+
+        const remainingMs = credentials.expiration.getTime() - Date.now()
+        if (remainingMs <= 0) {
+            this.setState('expired')
+
+            return
+        }
+
+        this.setState('valid')
+        this.timers.push(
+            setTimeout(() => this.onExpiringSoon(credentials), Math.max(remainingMs - this.warningMs, 0)),
+            setTimeout(() => this.onExpired(credentials), remainingMs)
+        )
+    }
+
+    public dispose(): void {
+        this.clearTimers()
+        this.onDidChangeStateEmitter.dispose()
+    }
+
+    private async onExpiringSoon(credentials: ExpiringCredentials): Promise<void> {
+        if (credentials.refresh) {
+            try {
+                if (await credentials.refresh()) {
+                    return
+                }
+            } catch (err) {
+                getLogger().warn('Failed to refresh credentials %s: %O', asString(credentials.credentialsId), err)
+            }
+        }
+        if (credentials !== this.tracked) {
+            return
+        }
+
+        this.setState('expiringSoon')
+        const minutes = Math.max(Math.round((credentials.expiration.getTime() - Date.now()) / MS_PER_MINUTE), 1)
+        const reauthenticate = localize('AWS.credentials.expiry.reauthenticate', 'Re-authenticate')
+        const selection = await this.window.showWarningMessage(
+            localize(
+                'AWS.credentials.expiry.expiringSoon',
+                'Credentials {0} expire in {1} minute(s). Re-authenticate to keep using them.',
+                asString(credentials.credentialsId),
+                minutes
+            ),
+            reauthenticate
+        )
+
+        if (selection === reauthenticate) {
+            await this.reauthenticate(credentials.credentialsId)
+        }
+    }
+
+    private onExpired(credentials: ExpiringCredentials): void {
+        if (credentials === this.tracked) {
+            getLogger().info('Credentials expired: %s', asString(credentials.credentialsId))
+            this.setState('expired')
+        }
+    }
+
+    private setState(state: CredentialsExpiryState): void {
+        if (state !== this._state) {
+            this._state = state
+            this.onDidChangeStateEmitter.fire(state)
+        }
+    }
+
+    private clearTimers(): void {
+        this.timers.forEach(timer => clearTimeout(timer))
+        this.timers = []
+    }
+}
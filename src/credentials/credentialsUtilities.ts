@@ -15,7 +15,7 @@ import { CredentialsId, asString } from './providers/credentials'
 import { waitTimeout, Timeout } from '../shared/utilities/timeoutUtils'
 import { showMessageWithCancel } from '../shared/utilities/messages'
 
-const CREDENTIALS_TIMEOUT = 300000 // 5 minutes
+export const CREDENTIALS_TIMEOUT = 300000 // 5 minutes
 const CREDENTIALS_PROGRESS_DELAY = 1000
 
 export function asEnvironmentVariables(credentials: Credentials): NodeJS.ProcessEnv {
@@ -4,9 +4,10 @@
  */
 
 import { AwsContext } from '../shared/awsContext'
-import { getAccountId } from '../shared/credentials/accountId'
+import { getAccountIdentity } from '../shared/credentials/accountId'
 import { getLogger } from '../shared/logger'
 import { recordAwsSetCredentials } from '../shared/telemetry/telemetry'
+import { CredentialsExpiryMonitor } from './credentialsExpiryMonitor'
 import { CredentialsStore } from './credentialsStore'
 import { notifyUserInvalidCredentials } from './credentialsUtilities'
 import { asString, CredentialsProvider, CredentialsId } from './providers/credentials'
@@ -14,12 +15,16 @@ import { CredentialsProviderManager } from './providers/credentialsProviderManag
 
 export class LoginManager {
     private readonly defaultCredentialsRegion = 'us-east-1'
+    public readonly expiryMonitor: CredentialsExpiryMonitor
 
     public constructor(
         private readonly awsContext: AwsContext,
         private readonly store: CredentialsStore,
-        public readonly recordAwsSetCredentialsFn: typeof recordAwsSetCredentials = recordAwsSetCredentials
-    ) {}
+        public readonly recordAwsSetCredentialsFn: typeof recordAwsSetCredentials = recordAwsSetCredentials,
+        expiryMonitor?: CredentialsExpiryMonitor
+    ) {
+        this.expiryMonitor = expiryMonitor ?? new CredentialsExpiryMonitor(id => this.reauthenticate(id))
+    }
 
     /**
      * Establishes a Credentials for the Toolkit to use. Essentially the Toolkit becomes "logged in".
@@ -44,17 +49,23 @@ export class LoginManager {
             }
 
             const credentialsRegion = provider.getDefaultRegion() ?? this.defaultCredentialsRegion
-            const accountId = await getAccountId(storedCredentials.credentials, credentialsRegion)
-            if (!accountId) {
+            const identity = await getAccountIdentity(
+                storedCredentials.credentials,
+                credentialsRegion,
+                asString(args.providerId)
+            )
+            if (!identity) {
                 throw new Error('Could not determine Account Id for credentials')
             }
 
             await this.awsContext.setCredentials({
                 credentials: storedCredentials.credentials,
                 credentialsId: asString(args.providerId),
-                accountId: accountId,
+                accountId: identity.accountId,
                 defaultRegion: provider.getDefaultRegion(),
+                partitionId: identity.partitionId,
             })
+            this.trackExpiration(args.providerId, provider, storedCredentials.credentials)
 
             return true
         } catch (err) {
@@ -88,5 +99,36 @@ export class LoginManager {
      */
     public async logout(): Promise<void> {
         await this.awsContext.setCredentials(undefined)
+        this.expiryMonitor.track(undefined)
+    }
+
+    /**
+     * Logs in again with fresh credentials, prompting the user if needed (e.g. for an expired SSO session).
+     */
+    public async reauthenticate(providerId: CredentialsId): Promise<boolean> {
+        this.store.invalidateCredentials(providerId)
+
+        return await this.login({ passive: false, providerId })
+    }
+
+    private trackExpiration(providerId: CredentialsId, provider: CredentialsProvider, credentials: AWS.Credentials) {
+        if (!credentials.expireTime) {
+            this.expiryMonitor.track(undefined)
+
+            return
+        }
+
+        this.expiryMonitor.track({
+            credentialsId: providerId,
+            expiration: credentials.expireTime,
+            refresh: async () => {
+                if (!provider.refreshSession || !(await provider.refreshSession())) {
+                    return false
+                }
+                this.store.invalidateCredentials(providerId)
+
+                return await this.login({ passive: true, providerId })
+            },
+        })
     }
 }
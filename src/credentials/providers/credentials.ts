@@ -119,4 +119,9 @@ export interface CredentialsProvider {
      * Determines if the provider is currently capable of producing credentials.
      */
     isAvailable(): Promise<boolean>
+    /**
+     * Renews the session that the credentials are derived from (such as an SSO access token) without
+     * prompting the user. Resolves to false if the user has to log in again.
+     */
+    refreshSession?(): Promise<boolean>
 }
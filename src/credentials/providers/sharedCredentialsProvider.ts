@@ -7,8 +7,8 @@ import * as AWS from 'aws-sdk'
 import { Profile } from '../../shared/credentials/credentialsFile'
 import { getLogger } from '../../shared/logger'
 import { getStringHash } from '../../shared/utilities/textUtilities'
-import { getMfaTokenFromUser } from '../credentialsCreator'
-import { hasProfileProperty, resolveProviderWithCancel } from '../credentialsUtilities'
+import { getMfaTokenFromUser, promptForMfaToken } from '../credentialsCreator'
+import { CREDENTIALS_TIMEOUT, hasProfileProperty, resolveProviderWithCancel } from '../credentialsUtilities'
 import { SSO_PROFILE_PROPERTIES, validateSsoProfile } from '../sso/sso'
 import { DiskCache } from '../sso/diskCache'
 import { SsoAccessTokenProvider } from '../sso/ssoAccessTokenProvider'
@@ -16,6 +16,7 @@ import { CredentialsProvider, CredentialsProviderType ,CredentialsId } from './c
 import { SsoCredentialProvider } from './ssoCredentialProvider'
 import { CredentialType } from '../../shared/telemetry/telemetry.gen'
 import { EnvVarsCredentialsProvider } from './envVarsCredentialsProvider'
+import { Timeout } from '../../shared/utilities/timeoutUtils'
 
 const SHARED_CREDENTIAL_PROPERTIES = {
     AWS_ACCESS_KEY_ID: 'aws_access_key_id',
@@ -31,6 +32,9 @@ const SHARED_CREDENTIAL_PROPERTIES = {
     SSO_REGION: 'sso_region',
     SSO_ACCOUNT_ID: 'sso_account_id',
     SSO_ROLE_NAME: 'sso_role_name',
+    EXTERNAL_ID: 'external_id',
+    ROLE_SESSION_NAME: 'role_session_name',
+    DURATION_SECONDS: 'duration_seconds',
 }
 
 const CREDENTIAL_SOURCES = {
@@ -39,6 +43,64 @@ const CREDENTIAL_SOURCES = {
     ENVIRONMENT: 'Environment'
 }
 
+/** Assumed role credentials that expire within this window are not reused. */
+const ASSUMED_ROLE_EXPIRY_WINDOW_MILLIS = 5 * 60 * 1000
+
+/** How many times the user can enter an MFA code that STS rejects before assuming the role fails. */
+const MFA_ATTEMPTS = 2
+
+export type AssumeRoleFn = (
+    request: AWS.STS.AssumeRoleRequest,
+    sourceCredentials: AWS.Credentials
+) => Promise<AWS.STS.AssumeRoleResponse>
+
+const assumeRoleWithSts: AssumeRoleFn = async (request, sourceCredentials) =>
+    new AWS.STS({ credentials: sourceCredentials }).assumeRole(request).promise()
+
+/**
+ * STS rejects codes that are mistyped or no longer current with e.g.
+ * `AccessDenied: MultiFactorAuthentication failed with invalid MFA one time pass code.`
+ */
+function isInvalidMfaCodeError(error: Error): boolean {
+    return (error as { code?: string }).code === 'AccessDenied' && /MultiFactorAuthentication/i.test(error.message)
+}
+
+/**
+ * Caches the credentials of each role profile resolved while walking a `source_profile` chain,
+ * so that profiles sharing part of a chain don't assume the same roles again.
+ */
+export class AssumedRoleCredentialsCache {
+    private readonly cache = new Map<string, { profileHashCode: string; credentials: AWS.Credentials }>()
+
+    /**
+     * Returns undefined if the credentials are not cached, are about to expire,
+     * or were produced from a different version of the profile.
+     */
+    public get(profileName: string, profileHashCode: string): AWS.Credentials | undefined {
+        const entry = this.cache.get(profileName)
+        if (!entry || entry.profileHashCode !== profileHashCode) {
+            return undefined
+        }
+        const expireTime = entry.credentials.expireTime
+        if (expireTime && expireTime.getTime() - ASSUMED_ROLE_EXPIRY_WINDOW_MILLIS <= Date.now()) {
+            this.cache.delete(profileName)
+            return undefined
+        }
+
+        return entry.credentials
+    }
+
+    public set(profileName: string, profileHashCode: string, credentials: AWS.Credentials): void {
+        this.cache.set(profileName, { profileHashCode, credentials })
+    }
+
+    public clear(): void {
+        this.cache.clear()
+    }
+}
+
+const defaultAssumedRoleCache = new AssumedRoleCredentialsCache()
+
 /**
  * Represents one profile from the AWS Shared Credentials files.
  */
@@ -47,7 +109,9 @@ export class SharedCredentialsProvider implements CredentialsProvider {
 
     public constructor(
         private readonly profileName: string,
-        private readonly allSharedCredentialProfiles: Map<string, Profile>
+        private readonly allSharedCredentialProfiles: Map<string, Profile>,
+        private readonly assumedRoleCache: AssumedRoleCredentialsCache = defaultAssumedRoleCache,
+        private readonly assumeRole: AssumeRoleFn = assumeRoleWithSts
     ) {
         const profile = this.allSharedCredentialProfiles.get(profileName)
 
@@ -156,6 +220,15 @@ export class SharedCredentialsProvider implements CredentialsProvider {
                 const ssoCredentialProvider = this.makeSsoProvider()
                 return await ssoCredentialProvider.refreshCredentials()
             }
+            if (hasProfileProperty(this.profile, SHARED_CREDENTIAL_PROPERTIES.ROLE_ARN)) {
+                // Shared with MFA prompts, so that they are dismissed if getting credentials is cancelled or expires
+                const timeout = new Timeout(CREDENTIALS_TIMEOUT)
+                return await resolveProviderWithCancel(
+                    this.profileName,
+                    this.resolveRoleCredentials([], timeout),
+                    timeout
+                )
+            }
             const provider = new AWS.CredentialProviderChain([this.makeCredentialsProvider()])
             return await resolveProviderWithCancel(this.profileName, provider.resolvePromise())
         } finally {
@@ -164,6 +237,141 @@ export class SharedCredentialsProvider implements CredentialsProvider {
         }
     }
 
+    public async refreshSession(): Promise<boolean> {
+        if (!this.isSsoProfile()) {
+            return false
+        }
+
+        return await this.makeSsoProvider().refreshSession()
+    }
+
+    /**
+     * Resolves credentials for this profile as one link of a `source_profile` chain.
+     *
+     * @param chain Names of the profiles that (transitively) source this profile, used to detect cycles.
+     * @param timeout Timeout of getting the credentials of the profile at the end of the chain.
+     */
+    private async resolveChainedCredentials(chain: string[], timeout: Timeout): Promise<AWS.Credentials> {
+        if (this.isSsoProfile()) {
+            return await this.makeSsoProvider().refreshCredentials()
+        }
+        if (hasProfileProperty(this.profile, SHARED_CREDENTIAL_PROPERTIES.ROLE_ARN)) {
+            return await this.resolveRoleCredentials(chain, timeout)
+        }
+
+        return await new AWS.CredentialProviderChain([this.makeCredentialsProvider()]).resolvePromise()
+    }
+
+    /**
+     * Assumes the profile's role using credentials from its `source_profile` (which may itself be a role
+     * profile) or `credential_source`. Profiles with an `mfa_serial` prompt for a code from the device.
+     */
+    private async resolveRoleCredentials(chain: string[], timeout: Timeout): Promise<AWS.Credentials> {
+        const profilesTraversed = [...chain, this.profileName]
+        if (chain.includes(this.profileName)) {
+            throw new Error(
+                `Cycle detected within Shared Credentials Profiles. Reference chain: ${profilesTraversed.join(' -> ')}`
+            )
+        }
+
+        const cached = this.assumedRoleCache.get(this.profileName, this.getHashCode())
+        if (cached) {
+            getLogger().verbose(`Profile ${this.profileName}: using cached assumed role credentials`)
+            return cached
+        }
+
+        const sourceCredentials = await this.resolveSourceCredentials(profilesTraversed, timeout)
+        const roleArn = this.profile[SHARED_CREDENTIAL_PROPERTIES.ROLE_ARN]!
+        const mfaSerial = this.profile[SHARED_CREDENTIAL_PROPERTIES.MFA_SERIAL]
+        const durationSeconds = this.profile[SHARED_CREDENTIAL_PROPERTIES.DURATION_SECONDS]
+        const externalId = this.profile[SHARED_CREDENTIAL_PROPERTIES.EXTERNAL_ID]
+        const request: AWS.STS.AssumeRoleRequest = {
+            RoleArn: roleArn,
+            RoleSessionName:
+                this.profile[SHARED_CREDENTIAL_PROPERTIES.ROLE_SESSION_NAME] ?? `aws-toolkit-vscode-${Date.now()}`,
+            ...(externalId ? { ExternalId: externalId } : {}),
+            ...(durationSeconds ? { DurationSeconds: Number(durationSeconds) } : {}),
+        }
+
+        getLogger().verbose(`Profile ${this.profileName}: assuming role ${roleArn}`)
+        const response = mfaSerial
+            ? await this.assumeRoleWithMfa(request, sourceCredentials, mfaSerial, timeout)
+            : await this.assumeRole(request, sourceCredentials)
+        if (!response.Credentials) {
+            throw new Error(`Profile ${this.profileName}: no credentials returned when assuming role ${roleArn}`)
+        }
+        const credentials = new AWS.Credentials({
+            accessKeyId: response.Credentials.AccessKeyId,
+            secretAccessKey: response.Credentials.SecretAccessKey,
+            sessionToken: response.Credentials.SessionToken,
+        })
+        credentials.expireTime = new Date(response.Credentials.Expiration)
+        this.assumedRoleCache.set(this.profileName, this.getHashCode(), credentials)
+
+        return credentials
+    }
+
+    /**
+     * Assumes a role that requires MFA, prompting again if STS rejects the code (e.g. a mistyped or expired code).
+     */
+    private async assumeRoleWithMfa(
+        request: AWS.STS.AssumeRoleRequest,
+        sourceCredentials: AWS.Credentials,
+        mfaSerial: string,
+        timeout: Timeout
+    ): Promise<AWS.STS.AssumeRoleResponse> {
+        for (let attempt = 1; ; attempt++) {
+            const tokenCode = await promptForMfaToken(mfaSerial, this.profileName, {
+                invalidCode: attempt > 1,
+                timeout,
+            })
+            try {
+                return await this.assumeRole(
+                    { ...request, SerialNumber: mfaSerial, TokenCode: tokenCode },
+                    sourceCredentials
+                )
+            } catch (err) {
+                if (!isInvalidMfaCodeError(err as Error)) {
+                    throw err
+                }
+                if (attempt >= MFA_ATTEMPTS) {
+                    throw new Error(`Profile ${this.profileName}: invalid MFA code for device ${mfaSerial}`)
+                }
+                getLogger().warn(`Profile ${this.profileName}: MFA code was rejected, prompting again`)
+            }
+        }
+    }
+
+    private async resolveSourceCredentials(profilesTraversed: string[], timeout: Timeout): Promise<AWS.Credentials> {
+        if (hasProfileProperty(this.profile, SHARED_CREDENTIAL_PROPERTIES.CREDENTIAL_SOURCE)) {
+            return await new AWS.CredentialProviderChain([this.makeSourcedCredentialsProvider()]).resolvePromise()
+        }
+
+        const sourceProfileName = this.profile[SHARED_CREDENTIAL_PROPERTIES.SOURCE_PROFILE]
+        if (!sourceProfileName) {
+            throw new Error(
+                `Profile ${this.profileName} contains role_arn but does not specify source_profile or credential_source`
+            )
+        }
+        if (!this.allSharedCredentialProfiles.has(sourceProfileName)) {
+            throw new Error(
+                `Shared Credentials Profile ${sourceProfileName} not found. Reference chain: ${[
+                    ...profilesTraversed,
+                    sourceProfileName,
+                ].join(' -> ')}`
+            )
+        }
+
+        const sourceProvider = new SharedCredentialsProvider(
+            sourceProfileName,
+            this.allSharedCredentialProfiles,
+            this.assumedRoleCache,
+            this.assumeRole
+        )
+
+        return await sourceProvider.resolveChainedCredentials(profilesTraversed, timeout)
+    }
+
     private getMissingProperties(propertyNames: string[]): string[] {
         return propertyNames.filter(propertyName => !this.profile[propertyName])
     }
@@ -222,14 +430,6 @@ export class SharedCredentialsProvider implements CredentialsProvider {
             return this.makeSourcedCredentialsProvider()
         }
 
-        if (hasProfileProperty(this.profile, SHARED_CREDENTIAL_PROPERTIES.ROLE_ARN)) {
-            logger.verbose(
-                `Profile ${this.profileName} contains ${SHARED_CREDENTIAL_PROPERTIES.ROLE_ARN} - treating as regular Shared Credentials`
-            )
-
-            return this.makeSharedIniFileCredentialsProvider()
-        }
-
         if (hasProfileProperty(this.profile, SHARED_CREDENTIAL_PROPERTIES.CREDENTIAL_PROCESS)) {
             logger.verbose(
                 `Profile ${this.profileName} contains ${SHARED_CREDENTIAL_PROPERTIES.CREDENTIAL_PROCESS} - treating as Process Credentials`
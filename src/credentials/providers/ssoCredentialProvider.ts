@@ -30,11 +30,15 @@ export class SsoCredentialProvider {
                 })
                 .promise()
 
-            return new Credentials({
+            const credentials = new Credentials({
                 accessKeyId: roleCredentials.roleCredentials!.accessKeyId!,
                 secretAccessKey: roleCredentials.roleCredentials!.secretAccessKey!,
                 sessionToken: roleCredentials.roleCredentials?.sessionToken,
             })
+            // Once the access token expires, the role credentials can only be renewed by logging in again.
+            credentials.expireTime = new Date(accessToken.expiresAt)
+
+            return credentials
         } catch (err) {
             if (err.code === 'UnauthorizedException') {
                 this.ssoAccessTokenProvider.invalidate()
@@ -46,4 +50,13 @@ export class SsoCredentialProvider {
             throw err
         }
     }
+
+    /**
+     * Renews the SSO access token without prompting the user.
+     *
+     * @returns false if the user has to log in again
+     */
+    public async refreshSession(): Promise<boolean> {
+        return (await this.ssoAccessTokenProvider.refreshAccessToken()) !== undefined
+    }
 }
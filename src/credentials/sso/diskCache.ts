@@ -46,13 +46,16 @@ export class DiskCache implements SsoCache {
             fs.unlinkSync(this.registrationCache(ssoRegion))
         }
     }
-    public loadAccessToken(ssoUrl: string): SsoAccessToken | undefined {
+    /**
+     * @param allowExpired Also returns an expired token, e.g. to use its refresh token.
+     */
+    public loadAccessToken(ssoUrl: string, allowExpired: boolean = false): SsoAccessToken | undefined {
         if (!this.tokenExists(ssoUrl)) {
             return undefined
         }
         try {
             const accessToken = JSON.parse(fs.readFileSync(this.accessTokenCache(ssoUrl)).toString())
-            if (accessToken && this.isNotExpired(accessToken)) {
+            if (accessToken && (allowExpired || this.isNotExpired(accessToken))) {
                 return accessToken
             }
         } catch (error) {
@@ -33,6 +33,12 @@ export interface SsoAccessToken {
      * The expiration time of the accessToken as an RFC 3339 formatted timestamp.
      */
     readonly expiresAt: string
+
+    /**
+     * Exchanged for a new access token when the current one nears its expiration, without involving the user.
+     * Only returned by the SSO-OIDC service for some client registrations.
+     */
+    readonly refreshToken?: string
 }
 
 export interface SsoCache {
@@ -40,7 +46,7 @@ export interface SsoCache {
     saveClientRegistration(ssoRegion: string, registration: SsoClientRegistration): void
     invalidateClientRegistration(ssoRegion: string): void
 
-    loadAccessToken(ssoUrl: string): SsoAccessToken | undefined
+    loadAccessToken(ssoUrl: string, allowExpired?: boolean): SsoAccessToken | undefined
     saveAccessToken(ssoUrl: string, accessToken: SsoAccessToken): void
     invalidateAccessToken(ssoUrl: string): void
 }
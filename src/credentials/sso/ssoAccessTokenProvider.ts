@@ -8,12 +8,13 @@ import { SsoClientRegistration } from './ssoClientRegistration'
 import { openSsoPortalLink, SsoAccessToken } from './sso'
 import { DiskCache } from './diskCache'
 import { getLogger } from '../../shared/logger'
-import { StartDeviceAuthorizationResponse } from 'aws-sdk/clients/ssooidc'
+import { CreateTokenResponse, StartDeviceAuthorizationResponse } from 'aws-sdk/clients/ssooidc'
 
 const CLIENT_REGISTRATION_TYPE = 'public'
 const CLIENT_NAME = 'aws-toolkit-vscode'
 // Grant type specified by the 'SSO Login Token Flow' spec.
 const GRANT_TYPE = 'urn:ietf:params:oauth:grant-type:device_code'
+const REFRESH_GRANT_TYPE = 'refresh_token'
 const MS_PER_SECOND = 1000
 const BACKOFF_DELAY_MS = 5000
 
@@ -57,6 +58,10 @@ export class SsoAccessTokenProvider {
         if (accessToken) {
             return accessToken
         }
+        const refreshedToken = await this.refreshAccessToken()
+        if (refreshedToken) {
+            return refreshedToken
+        }
         // SSO step 1
         const registration = await this.registerClient()
         // SSO step 2
@@ -71,6 +76,41 @@ export class SsoAccessTokenProvider {
         this.cache.invalidateAccessToken(this.ssoUrl)
     }
 
+    /**
+     * Exchanges the refresh token of the cached access token, if it has one, for a new access token. Unlike
+     * the login flow, this does not involve the user.
+     *
+     * @returns the new access token, or undefined if the cached token cannot be refreshed
+     */
+    public async refreshAccessToken(): Promise<SsoAccessToken | undefined> {
+        const cachedToken = this.cache.loadAccessToken(this.ssoUrl, true)
+        const registration = this.cache.loadClientRegistration(this.ssoRegion)
+        if (!cachedToken?.refreshToken || !registration) {
+            return undefined
+        }
+
+        try {
+            const tokenResponse = await this.ssoOidcClient
+                .createToken({
+                    clientId: registration.clientId,
+                    clientSecret: registration.clientSecret,
+                    grantType: REFRESH_GRANT_TYPE,
+                    refreshToken: cachedToken.refreshToken,
+                })
+                .promise()
+            const token = this.toAccessToken(tokenResponse)
+            // The refresh token is not necessarily rotated, in which case the current one remains valid.
+            const refreshedToken = { ...token, refreshToken: token.refreshToken ?? cachedToken.refreshToken }
+            this.cache.saveAccessToken(this.ssoUrl, refreshedToken)
+            getLogger().info('SSO: refreshed access token for %s', this.ssoUrl)
+
+            return refreshedToken
+        } catch (err) {
+            getLogger().warn('SSO: failed to refresh access token, login flow must be reinitiated: %O', err)
+            return undefined
+        }
+    }
+
     /**
      * SSO step 3: poll for the access token.
      */
@@ -98,13 +138,7 @@ export class SsoAccessTokenProvider {
         while (true) {
             try {
                 const tokenResponse = await this.ssoOidcClient.createToken(createTokenParams).promise()
-                const accessToken: SsoAccessToken = {
-                    startUrl: this.ssoUrl,
-                    region: this.ssoRegion,
-                    accessToken: tokenResponse.accessToken!,
-                    expiresAt: new Date(this.currentTimePlusSecondsInMs(tokenResponse.expiresIn!)).toISOString(),
-                }
-                return accessToken
+                return this.toAccessToken(tokenResponse)
             } catch (err) {
                 if (err.code === 'SlowDownException') {
                     retryInterval += BACKOFF_DELAY_MS
@@ -181,6 +215,16 @@ export class SsoAccessTokenProvider {
         return registration
     }
 
+    private toAccessToken(tokenResponse: CreateTokenResponse): SsoAccessToken {
+        return {
+            startUrl: this.ssoUrl,
+            region: this.ssoRegion,
+            accessToken: tokenResponse.accessToken!,
+            expiresAt: new Date(this.currentTimePlusSecondsInMs(tokenResponse.expiresIn!)).toISOString(),
+            refreshToken: tokenResponse.refreshToken,
+        }
+    }
+
     /**
      * Takes the current time and adds the param seconds, returns in milliseconds
      * @param seconds Number of seconds to add
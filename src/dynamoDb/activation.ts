@@ -0,0 +1,19 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { DynamoDbTableNode } from './explorer/dynamoDbTableNode'
+import { viewTableCommand } from './vue/tableViewer'
+
+/**
+ * Activates DynamoDB components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.dynamoDb.viewTable', async (node: DynamoDbTableNode) => {
+            await viewTableCommand(node, extensionContext)
+        })
+    )
+}
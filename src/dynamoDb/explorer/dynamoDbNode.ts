@@ -0,0 +1,46 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { DynamoDbClient } from '../../shared/clients/dynamoDbClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { DynamoDbTableNode } from './dynamoDbTableNode'
+
+/**
+ * An AWS Explorer node representing DynamoDB.
+ *
+ * Contains tables for a specific region as child nodes.
+ */
+export class DynamoDbNode extends AWSTreeNodeBase {
+    public constructor(public readonly dynamoDb: DynamoDbClient) {
+        super('DynamoDB', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsDynamoDbNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const tableNames = await toArrayAsync(this.dynamoDb.listTables())
+
+                return tableNames.map(tableName => new DynamoDbTableNode(this, this.dynamoDb, tableName))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.dynamoDb.noTables', '[No tables found]')),
+            sort: (item1: DynamoDbTableNode, item2: DynamoDbTableNode) =>
+                item1.tableName.localeCompare(item2.tableName),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'DynamoDbNode'
+    }
+}
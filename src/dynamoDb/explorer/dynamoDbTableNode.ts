@@ -0,0 +1,43 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { DynamoDbClient } from '../../shared/clients/dynamoDbClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { DynamoDbNode } from './dynamoDbNode'
+
+/**
+ * Represents a DynamoDB table. Selecting the node opens the table viewer.
+ */
+export class DynamoDbTableNode extends AWSTreeNodeBase {
+    public constructor(
+        public readonly parent: DynamoDbNode,
+        public readonly dynamoDb: DynamoDbClient,
+        public readonly tableName: string
+    ) {
+        super(tableName, vscode.TreeItemCollapsibleState.None)
+        this.tooltip = tableName
+        this.contextValue = 'awsDynamoDbTableNode'
+        this.command = {
+            command: 'aws.dynamoDb.viewTable',
+            title: localize('AWS.command.dynamoDb.viewTable', 'View Table Items'),
+            arguments: [this],
+        }
+    }
+
+    public get name(): string {
+        return this.tableName
+    }
+
+    public get regionCode(): string {
+        return this.dynamoDb.regionCode
+    }
+
+    public [inspect.custom](): string {
+        return `DynamoDbTableNode (table=${this.tableName})`
+    }
+}
@@ -0,0 +1,275 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { DynamoDB } from 'aws-sdk'
+
+export type KeyAttributeType = 'S' | 'N' | 'B'
+
+export interface KeyAttribute {
+    readonly name: string
+    readonly type: KeyAttributeType
+}
+
+export interface TableKeySchema {
+    readonly partitionKey: KeyAttribute
+    readonly sortKey?: KeyAttribute
+}
+
+export const SORT_KEY_OPERATORS = ['=', '<', '<=', '>', '>=', 'begins_with', 'between'] as const
+export type SortKeyOperator = typeof SORT_KEY_OPERATORS[number]
+
+export interface SortKeyCondition {
+    readonly operator: SortKeyOperator
+    readonly value: string
+    /** Upper bound, only used by `between`. */
+    readonly value2?: string
+}
+
+/**
+ * Inputs from the table viewer. Values are entered as text and converted
+ * to the key attribute's type.
+ */
+export interface ItemQuery {
+    readonly partitionKeyValue?: string
+    readonly sortKeyCondition?: SortKeyCondition
+    readonly projectionExpression?: string
+}
+
+export interface ItemGridPage {
+    readonly columns: string[]
+    readonly rows: { [column: string]: string }[]
+}
+
+/**
+ * Reads the partition and sort key of a table.
+ */
+export function getKeySchema(table: DynamoDB.TableDescription): TableKeySchema {
+    const getAttribute = (keyType: 'HASH' | 'RANGE'): KeyAttribute | undefined => {
+        const name = table.KeySchema?.find(key => key.KeyType === keyType)?.AttributeName
+        if (!name) {
+            return undefined
+        }
+        const type = table.AttributeDefinitions?.find(def => def.AttributeName === name)?.AttributeType ?? 'S'
+
+        return { name, type: type as KeyAttributeType }
+    }
+    const partitionKey = getAttribute('HASH')
+    if (!partitionKey) {
+        throw new Error(`Table ${table.TableName} does not have a partition key`)
+    }
+
+    return { partitionKey, sortKey: getAttribute('RANGE') }
+}
+
+/**
+ * Converts text entered by the user into an attribute value of the key's type.
+ * Binary values are expected to be base64 encoded.
+ */
+export function toKeyAttributeValue(attribute: KeyAttribute, value: string): DynamoDB.AttributeValue {
+    switch (attribute.type) {
+        case 'N':
+            if (value.trim() === '' || isNaN(Number(value))) {
+                throw new Error(`Value for ${attribute.name} must be a number: ${value}`)
+            }
+            return { N: value.trim() }
+        case 'B':
+            return { B: Buffer.from(value, 'base64') }
+        default:
+            return { S: value }
+    }
+}
+
+/**
+ * Replaces each attribute name in a projection expression (e.g. `id, info.rating, tags[0]`)
+ * with a placeholder, so that reserved words such as `name` or `status` can be projected.
+ */
+export function toProjection(
+    projectionExpression: string
+): { ProjectionExpression: string; ExpressionAttributeNames: DynamoDB.ExpressionAttributeNameMap } | undefined {
+    const paths = projectionExpression
+        .split(',')
+        .map(path => path.trim())
+        .filter(path => path)
+    if (paths.length === 0) {
+        return undefined
+    }
+    const names: DynamoDB.ExpressionAttributeNameMap = {}
+    const placeholders = new Map<string, string>()
+    const toPlaceholder = (name: string): string => {
+        let placeholder = placeholders.get(name)
+        if (!placeholder) {
+            placeholder = `#p${placeholders.size}`
+            placeholders.set(name, placeholder)
+            names[placeholder] = name
+        }
+        return placeholder
+    }
+    const expression = paths
+        .map(path =>
+            path
+                .split('.')
+                .map(segment => segment.replace(/^([^[]+)/, name => toPlaceholder(name.trim())))
+                .join('.')
+        )
+        .join(', ')
+
+    return { ProjectionExpression: expression, ExpressionAttributeNames: names }
+}
+
+export function buildScanInput(tableName: string, query: ItemQuery, limit?: number): DynamoDB.ScanInput {
+    const projection = query.projectionExpression ? toProjection(query.projectionExpression) : undefined
+
+    return {
+        TableName: tableName,
+        Limit: limit,
+        ...projection,
+    }
+}
+
+export function buildQueryInput(
+    tableName: string,
+    keySchema: TableKeySchema,
+    query: ItemQuery,
+    limit?: number
+): DynamoDB.QueryInput {
+    if (query.partitionKeyValue === undefined || query.partitionKeyValue === '') {
+        throw new Error(`A value for partition key ${keySchema.partitionKey.name} is required to query`)
+    }
+    const names: DynamoDB.ExpressionAttributeNameMap = { '#pk': keySchema.partitionKey.name }
+    const values: DynamoDB.ExpressionAttributeValueMap = {
+        ':pk': toKeyAttributeValue(keySchema.partitionKey, query.partitionKeyValue),
+    }
+    let keyCondition = '#pk = :pk'
+
+    const sortKeyCondition = query.sortKeyCondition
+    if (sortKeyCondition && sortKeyCondition.value !== '') {
+        if (!keySchema.sortKey) {
+            throw new Error(`Table ${tableName} does not have a sort key`)
+        }
+        names['#sk'] = keySchema.sortKey.name
+        values[':sk'] = toKeyAttributeValue(keySchema.sortKey, sortKeyCondition.value)
+        switch (sortKeyCondition.operator) {
+            case 'begins_with':
+                keyCondition += ' AND begins_with(#sk, :sk)'
+                break
+            case 'between':
+                values[':sk2'] = toKeyAttributeValue(keySchema.sortKey, sortKeyCondition.value2 ?? '')
+                keyCondition += ' AND #sk BETWEEN :sk AND :sk2'
+                break
+            default:
+                keyCondition += ` AND #sk ${sortKeyCondition.operator} :sk`
+        }
+    }
+
+    const projection = query.projectionExpression ? toProjection(query.projectionExpression) : undefined
+
+    return {
+        TableName: tableName,
+        Limit: limit,
+        KeyConditionExpression: keyCondition,
+        ExpressionAttributeNames: { ...names, ...projection?.ExpressionAttributeNames },
+        ExpressionAttributeValues: values,
+        ProjectionExpression: projection?.ProjectionExpression,
+    }
+}
+
+/**
+ * Formats an attribute value for display in the item grid.
+ *
+ * Binary values are shown base64 encoded and sets are shown as a bracketed list,
+ * since neither has a natural JSON representation.
+ */
+export function formatAttributeValue(value: DynamoDB.AttributeValue): string {
+    if (value.S !== undefined) {
+        return value.S
+    }
+    if (value.N !== undefined) {
+        return value.N
+    }
+    if (value.B !== undefined) {
+        return `<binary> ${toBase64(value.B)}`
+    }
+    if (value.BOOL !== undefined) {
+        return String(value.BOOL)
+    }
+    if (value.NULL !== undefined) {
+        return 'null'
+    }
+    if (value.SS !== undefined) {
+        return `<string set> [${value.SS.join(', ')}]`
+    }
+    if (value.NS !== undefined) {
+        return `<number set> [${value.NS.join(', ')}]`
+    }
+    if (value.BS !== undefined) {
+        return `<binary set> [${value.BS.map(toBase64).join(', ')}]`
+    }
+    if (value.L !== undefined || value.M !== undefined) {
+        return JSON.stringify(toPlainValue(value))
+    }
+
+    return ''
+}
+
+/**
+ * Converts a page of items into grid rows. Key attributes are shown first, followed by
+ * the remaining attributes (from all items in the page) in alphabetical order.
+ */
+export function toItemGridPage(items: DynamoDB.ItemList, keySchema?: TableKeySchema): ItemGridPage {
+    const keyColumns = [keySchema?.partitionKey.name, keySchema?.sortKey?.name].filter(
+        (name): name is string => !!name
+    )
+    const otherColumns = new Set<string>()
+    for (const item of items) {
+        Object.keys(item)
+            .filter(name => !keyColumns.includes(name))
+            .forEach(name => otherColumns.add(name))
+    }
+    const columns = [...keyColumns, ...[...otherColumns].sort()]
+    const rows = items.map(item => {
+        const row: { [column: string]: string } = {}
+        for (const [name, value] of Object.entries(item)) {
+            row[name] = formatAttributeValue(value)
+        }
+        return row
+    })
+
+    return { columns, rows }
+}
+
+function toPlainValue(value: DynamoDB.AttributeValue): any {
+    if (value.L !== undefined) {
+        return value.L.map(toPlainValue)
+    }
+    if (value.M !== undefined) {
+        const result: { [key: string]: any } = {}
+        for (const [key, inner] of Object.entries(value.M)) {
+            result[key] = toPlainValue(inner)
+        }
+        return result
+    }
+    if (value.N !== undefined) {
+        return Number(value.N)
+    }
+    if (value.BOOL !== undefined) {
+        return value.BOOL
+    }
+    if (value.NULL !== undefined) {
+        return null
+    }
+    if (value.S !== undefined) {
+        return value.S
+    }
+
+    return formatAttributeValue(value)
+}
+
+function toBase64(value: DynamoDB.BinaryAttributeValue): string {
+    if (typeof value === 'string') {
+        return Buffer.from(value).toString('base64')
+    }
+
+    return Buffer.from(value as Uint8Array).toString('base64')
+}
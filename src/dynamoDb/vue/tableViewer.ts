@@ -0,0 +1,204 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { DynamoDB } from 'aws-sdk'
+import { DynamoDbClient } from '../../shared/clients/dynamoDbClient'
+import { getLogger } from '../../shared/logger'
+import { recordDynamodbOpenTable } from '../../shared/telemetry/telemetry.gen'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { createVueWebview } from '../../webviews/main'
+import { DynamoDbTableNode } from '../explorer/dynamoDbTableNode'
+import {
+    buildQueryInput,
+    buildScanInput,
+    getKeySchema,
+    ItemGridPage,
+    ItemQuery,
+    KeyAttribute,
+    TableKeySchema,
+    toItemGridPage,
+} from '../utils'
+
+/** Number of items requested per page of results. */
+export const TABLE_VIEWER_PAGE_SIZE = 50
+
+export type ReadMode = 'scan' | 'query'
+export type PageRequest = 'first' | 'next' | 'previous'
+
+export interface TableViewerState {
+    mode: ReadMode
+    query: ItemQuery
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface FetchItemsRequest {
+    command: 'fetchItems'
+    data: {
+        mode: ReadMode
+        query: ItemQuery
+        page: PageRequest
+    }
+}
+
+export interface TableDescriptionResponse {
+    command: 'tableDescription'
+    data: {
+        tableName: string
+        partitionKey: KeyAttribute
+        sortKey?: KeyAttribute
+        itemCount?: number
+    }
+}
+
+export interface ItemsResponse {
+    command: 'items'
+    data: ItemGridPage & {
+        pageNumber: number
+        hasNextPage: boolean
+        hasPreviousPage: boolean
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        message: string
+    }
+}
+
+export type TableViewerRequest = InitializeRequest | FetchItemsRequest
+export type TableViewerResponse = TableDescriptionResponse | ItemsResponse | ErrorResponse
+
+/**
+ * Tracks the start key of each page read so far, so results can be paged backwards.
+ * DynamoDB only supports reading forwards from an `ExclusiveStartKey`.
+ */
+export class ItemPager {
+    private readonly startKeys: (DynamoDB.Key | undefined)[] = [undefined]
+    private pageIndex = 0
+    private nextKey: DynamoDB.Key | undefined
+
+    public get pageNumber(): number {
+        return this.pageIndex + 1
+    }
+
+    public get hasNextPage(): boolean {
+        return this.nextKey !== undefined
+    }
+
+    public get hasPreviousPage(): boolean {
+        return this.pageIndex > 0
+    }
+
+    /**
+     * Moves to the requested page and returns the key to start reading from.
+     */
+    public move(page: PageRequest): DynamoDB.Key | undefined {
+        if (page === 'first') {
+            this.startKeys.splice(1)
+            this.pageIndex = 0
+        } else if (page === 'next' && this.nextKey) {
+            this.pageIndex++
+            this.startKeys.splice(this.pageIndex, this.startKeys.length, this.nextKey)
+        } else if (page === 'previous' && this.pageIndex > 0) {
+            this.pageIndex--
+        }
+
+        return this.startKeys[this.pageIndex]
+    }
+
+    /**
+     * Records the `LastEvaluatedKey` of the page that was just read.
+     */
+    public setLastEvaluatedKey(key: DynamoDB.Key | undefined): void {
+        this.nextKey = key
+    }
+}
+
+/**
+ * Reads pages of items from a table, either by scanning it or by querying a partition.
+ */
+export class TableItemReader {
+    private readonly pager = new ItemPager()
+
+    public constructor(
+        private readonly dynamoDb: DynamoDbClient,
+        private readonly tableName: string,
+        private readonly keySchema: TableKeySchema,
+        private readonly pageSize: number = TABLE_VIEWER_PAGE_SIZE
+    ) {}
+
+    public async read(mode: ReadMode, query: ItemQuery, page: PageRequest): Promise<ItemsResponse['data']> {
+        // The query is rebuilt before moving so that invalid input doesn't change the current page.
+        const request =
+            mode === 'query'
+                ? buildQueryInput(this.tableName, this.keySchema, query, this.pageSize)
+                : buildScanInput(this.tableName, query, this.pageSize)
+        const exclusiveStartKey = this.pager.move(page)
+        const paged = { ...request, ...(exclusiveStartKey ? { ExclusiveStartKey: exclusiveStartKey } : {}) }
+        const response =
+            mode === 'query'
+                ? await this.dynamoDb.query(paged as DynamoDB.QueryInput)
+                : await this.dynamoDb.scan(paged as DynamoDB.ScanInput)
+        this.pager.setLastEvaluatedKey(response.LastEvaluatedKey)
+
+        return {
+            ...toItemGridPage(response.Items ?? [], this.keySchema),
+            pageNumber: this.pager.pageNumber,
+            hasNextPage: this.pager.hasNextPage,
+            hasPreviousPage: this.pager.hasPreviousPage,
+        }
+    }
+}
+
+/**
+ * Opens a read-only view of a table's items.
+ */
+export async function viewTableCommand(node: DynamoDbTableNode, context: vscode.ExtensionContext): Promise<void> {
+    let reader: TableItemReader | undefined
+
+    await createVueWebview<TableViewerRequest, TableViewerResponse>({
+        id: 'dynamoDbTableViewer',
+        name: localize('AWS.dynamoDb.tableViewer.title', 'DynamoDB: {0}', node.name),
+        webviewJs: 'dynamoDbTableViewerVue.js',
+        cssFiles: ['dynamoDbTableViewer.css'],
+        context,
+        persistWithoutFocus: true,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            try {
+                switch (message.command) {
+                    case 'initialize': {
+                        const table = await node.dynamoDb.describeTable(node.name)
+                        const keySchema = getKeySchema(table)
+                        reader = new TableItemReader(node.dynamoDb, node.name, keySchema)
+                        await postMessageFn({
+                            command: 'tableDescription',
+                            data: { tableName: node.name, ...keySchema, itemCount: table.ItemCount },
+                        })
+                        break
+                    }
+                    case 'fetchItems': {
+                        if (!reader) {
+                            throw new Error('Table description has not been loaded')
+                        }
+                        const page = await reader.read(message.data.mode, message.data.query, message.data.page)
+                        await postMessageFn({ command: 'items', data: page })
+                        break
+                    }
+                }
+            } catch (e) {
+                const error = e as Error
+                getLogger().error(`Failed to read DynamoDB table ${node.name}: %O`, error)
+                await postMessageFn({ command: 'error', data: { message: error.message } })
+            }
+        },
+    })
+
+    recordDynamodbOpenTable({ result: 'Succeeded' })
+}
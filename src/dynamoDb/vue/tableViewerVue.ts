@@ -0,0 +1,181 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import { KeyAttribute, SORT_KEY_OPERATORS, SortKeyOperator } from '../utils'
+import { PageRequest, ReadMode, TableViewerResponse, TableViewerState } from './tableViewer'
+
+declare const vscode: WebviewApi<TableViewerState>
+
+export interface TableViewerVueData {
+    tableName: string
+    partitionKey?: KeyAttribute
+    sortKey?: KeyAttribute
+    itemCount?: number
+    mode: ReadMode
+    partitionKeyValue: string
+    sortKeyOperator: SortKeyOperator
+    sortKeyValue: string
+    sortKeyValue2: string
+    projectionExpression: string
+    operators: readonly SortKeyOperator[]
+    columns: string[]
+    rows: { [column: string]: string }[]
+    pageNumber: number
+    hasNextPage: boolean
+    hasPreviousPage: boolean
+    loading: boolean
+    errorMsg: string
+}
+
+export const Component = Vue.extend({
+    created() {
+        const oldState = vscode.getState()
+        if (oldState) {
+            this.mode = oldState.mode
+            this.partitionKeyValue = oldState.query.partitionKeyValue ?? ''
+            this.sortKeyOperator = oldState.query.sortKeyCondition?.operator ?? '='
+            this.sortKeyValue = oldState.query.sortKeyCondition?.value ?? ''
+            this.sortKeyValue2 = oldState.query.sortKeyCondition?.value2 ?? ''
+            this.projectionExpression = oldState.query.projectionExpression ?? ''
+        }
+        window.addEventListener('message', ev => {
+            const event = ev.data as TableViewerResponse
+            switch (event.command) {
+                case 'tableDescription':
+                    this.tableName = event.data.tableName
+                    this.partitionKey = event.data.partitionKey
+                    this.sortKey = event.data.sortKey
+                    this.itemCount = event.data.itemCount
+                    this.fetchItems('first')
+                    break
+                case 'items':
+                    this.loading = false
+                    this.errorMsg = ''
+                    this.columns = event.data.columns
+                    this.rows = event.data.rows
+                    this.pageNumber = event.data.pageNumber
+                    this.hasNextPage = event.data.hasNextPage
+                    this.hasPreviousPage = event.data.hasPreviousPage
+                    break
+                case 'error':
+                    this.loading = false
+                    this.errorMsg = event.data.message
+                    break
+            }
+        })
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): TableViewerVueData {
+        return {
+            tableName: '',
+            partitionKey: undefined,
+            sortKey: undefined,
+            itemCount: undefined,
+            mode: 'scan',
+            partitionKeyValue: '',
+            sortKeyOperator: '=',
+            sortKeyValue: '',
+            sortKeyValue2: '',
+            projectionExpression: '',
+            operators: SORT_KEY_OPERATORS,
+            columns: [],
+            rows: [],
+            pageNumber: 1,
+            hasNextPage: false,
+            hasPreviousPage: false,
+            loading: false,
+            errorMsg: '',
+        }
+    },
+    methods: {
+        fetchItems(page: PageRequest) {
+            const query = {
+                partitionKeyValue: this.partitionKeyValue,
+                sortKeyCondition: {
+                    operator: this.sortKeyOperator,
+                    value: this.sortKeyValue,
+                    value2: this.sortKeyValue2,
+                },
+                projectionExpression: this.projectionExpression,
+            }
+            vscode.setState({ mode: this.mode, query })
+            this.loading = true
+            vscode.postMessage({
+                command: 'fetchItems',
+                data: { mode: this.mode, query, page },
+            })
+        },
+    },
+    template: `
+    <div class="table-viewer">
+        <h1>{{ tableName }}</h1>
+        <p class="table-keys" v-if="partitionKey">
+            Partition key: <code>{{ partitionKey.name }} ({{ partitionKey.type }})</code>
+            <span v-if="sortKey">&nbsp;Sort key: <code>{{ sortKey.name }} ({{ sortKey.type }})</code></span>
+            <span v-if="itemCount !== undefined">&nbsp;Approximate item count: {{ itemCount }}</span>
+        </p>
+        <form class="table-query" v-on:submit.prevent="fetchItems('first')">
+            <div>
+                <label><input type="radio" value="scan" v-model="mode" /> Scan</label>
+                <label><input type="radio" value="query" v-model="mode" /> Query</label>
+            </div>
+            <div v-if="mode === 'query' && partitionKey">
+                <label for="partition-key-value">{{ partitionKey.name }} =</label>
+                <input id="partition-key-value" type="text" v-model="partitionKeyValue" />
+            </div>
+            <div v-if="mode === 'query' && sortKey">
+                <label for="sort-key-value">{{ sortKey.name }}</label>
+                <select v-model="sortKeyOperator">
+                    <option v-for="operator in operators" :key="operator" :value="operator">{{ operator }}</option>
+                </select>
+                <input id="sort-key-value" type="text" v-model="sortKeyValue" />
+                <span v-if="sortKeyOperator === 'between'">
+                    and <input type="text" v-model="sortKeyValue2" />
+                </span>
+            </div>
+            <div>
+                <label for="projection">Attributes to return</label>
+                <input
+                    id="projection"
+                    type="text"
+                    placeholder="All attributes (e.g. id, info.rating)"
+                    v-model="projectionExpression"
+                />
+            </div>
+            <p class="key-hint" v-if="mode === 'query'">Binary key values must be base64 encoded.</p>
+            <button type="submit" :disabled="loading">{{ mode === 'query' ? 'Run query' : 'Scan' }}</button>
+        </form>
+        <p class="error" v-if="errorMsg">{{ errorMsg }}</p>
+        <div class="table-pager">
+            <button :disabled="loading || !hasPreviousPage" v-on:click="fetchItems('previous')">Previous</button>
+            <span>Page {{ pageNumber }}</span>
+            <button :disabled="loading || !hasNextPage" v-on:click="fetchItems('next')">Next</button>
+            <span v-if="loading">Loading...</span>
+        </div>
+        <p v-if="!loading && !errorMsg && rows.length === 0">No items found.</p>
+        <table class="table-items" v-else-if="rows.length > 0">
+            <thead>
+                <tr>
+                    <th v-for="column in columns" :key="column">{{ column }}</th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr v-for="(row, index) in rows" :key="index">
+                    <td v-for="column in columns" :key="column" :title="row[column]">{{ row[column] }}</td>
+                </tr>
+            </tbody>
+        </table>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
@@ -0,0 +1,29 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { rebootInstance, startInstance, stopInstance } from './commands/changeInstanceState'
+import { connectViaSsm } from './commands/connectViaSsm'
+import { Ec2InstanceNode } from './explorer/ec2InstanceNode'
+
+/**
+ * Activates EC2 components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.ec2.startInstance', async (node: Ec2InstanceNode) => {
+            await startInstance(node)
+        }),
+        vscode.commands.registerCommand('aws.ec2.stopInstance', async (node: Ec2InstanceNode) => {
+            await stopInstance(node)
+        }),
+        vscode.commands.registerCommand('aws.ec2.rebootInstance', async (node: Ec2InstanceNode) => {
+            await rebootInstance(node)
+        }),
+        vscode.commands.registerCommand('aws.ec2.connectViaSsm', async (node: Ec2InstanceNode) => {
+            await connectViaSsm(node)
+        })
+    )
+}
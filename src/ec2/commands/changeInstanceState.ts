@@ -0,0 +1,237 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import {
+    recordEc2RebootInstance,
+    recordEc2StartInstance,
+    recordEc2StopInstance,
+    Result,
+} from '../../shared/telemetry/telemetry'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { Ec2InstanceNode } from '../explorer/ec2InstanceNode'
+
+const STATE_POLL_INTERVAL_MS = 3000
+const STATE_TIMEOUT_MS = 10 * 60 * 1000
+
+export interface InstanceStateOptions {
+    window?: Window
+    commands?: Commands
+    pollIntervalMs?: number
+    timeoutMs?: number
+}
+
+/**
+ * Starts a stopped instance, and updates its node until it is running.
+ */
+export async function startInstance(
+    node: Ec2InstanceNode,
+    {
+        window = Window.vscode(),
+        commands = Commands.vscode(),
+        pollIntervalMs = STATE_POLL_INTERVAL_MS,
+        timeoutMs = STATE_TIMEOUT_MS,
+    }: InstanceStateOptions = {}
+): Promise<void> {
+    let result: Result = 'Succeeded'
+    try {
+        await node.ec2.startInstance(node.instanceId)
+        const reached = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.ec2.startInstance.progress', 'Starting instance {0}...', node.name),
+                cancellable: true,
+            },
+            async (progress, token) =>
+                waitForInstanceState(node, 'running', { commands, progress, token, pollIntervalMs, timeoutMs })
+        )
+        if (!reached) {
+            result = 'Cancelled'
+            return
+        }
+
+        window.showInformationMessage(localize('AWS.ec2.startInstance.success', 'Instance {0} is running', node.name))
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to start instance %s: %O', node.instanceId, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.ec2.startInstance.error',
+                'Failed to start instance {0}: {1}',
+                node.name,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordEc2StartInstance({ result })
+    }
+}
+
+/**
+ * Stops a running instance after confirmation, and updates its node until it is stopped.
+ */
+export async function stopInstance(
+    node: Ec2InstanceNode,
+    {
+        window = Window.vscode(),
+        commands = Commands.vscode(),
+        pollIntervalMs = STATE_POLL_INTERVAL_MS,
+        timeoutMs = STATE_TIMEOUT_MS,
+    }: InstanceStateOptions = {}
+): Promise<void> {
+    const stop = localize('AWS.ec2.stopInstance.confirm', 'Stop')
+    const prompt = localize(
+        'AWS.ec2.stopInstance.prompt',
+        'Are you sure you want to stop instance {0} ({1})?',
+        node.name,
+        node.instanceId
+    )
+    if (!(await confirm(node, prompt, stop, window))) {
+        recordEc2StopInstance({ result: 'Cancelled' })
+        return
+    }
+
+    let result: Result = 'Succeeded'
+    try {
+        await node.ec2.stopInstance(node.instanceId)
+        const reached = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.ec2.stopInstance.progress', 'Stopping instance {0}...', node.name),
+                cancellable: true,
+            },
+            async (progress, token) =>
+                waitForInstanceState(node, 'stopped', { commands, progress, token, pollIntervalMs, timeoutMs })
+        )
+        if (!reached) {
+            result = 'Cancelled'
+            return
+        }
+
+        window.showInformationMessage(localize('AWS.ec2.stopInstance.success', 'Instance {0} is stopped', node.name))
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to stop instance %s: %O', node.instanceId, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.ec2.stopInstance.error',
+                'Failed to stop instance {0}: {1}',
+                node.name,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordEc2StopInstance({ result })
+    }
+}
+
+/**
+ * Reboots a running instance after confirmation. The instance stays in the running state while it reboots, so
+ * there is no state change to wait for.
+ */
+export async function rebootInstance(node: Ec2InstanceNode, window = Window.vscode()): Promise<void> {
+    const reboot = localize('AWS.ec2.rebootInstance.confirm', 'Reboot')
+    const prompt = localize(
+        'AWS.ec2.rebootInstance.prompt',
+        'Are you sure you want to reboot instance {0} ({1})?',
+        node.name,
+        node.instanceId
+    )
+    if (!(await confirm(node, prompt, reboot, window))) {
+        recordEc2RebootInstance({ result: 'Cancelled' })
+        return
+    }
+
+    let result: Result = 'Succeeded'
+    try {
+        await node.ec2.rebootInstance(node.instanceId)
+        window.showInformationMessage(localize('AWS.ec2.rebootInstance.success', 'Rebooting instance {0}', node.name))
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to reboot instance %s: %O', node.instanceId, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.ec2.rebootInstance.error',
+                'Failed to reboot instance {0}: {1}',
+                node.name,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordEc2RebootInstance({ result })
+    }
+}
+
+/**
+ * Asks to confirm an action that interrupts an instance, warning that its Auto Scaling group may replace it.
+ */
+async function confirm(node: Ec2InstanceNode, prompt: string, action: string, window: Window): Promise<boolean> {
+    const message = node.autoScalingGroup
+        ? `${prompt} ${localize(
+              'AWS.ec2.autoScalingWarning',
+              'It is part of Auto Scaling group {0}, which may terminate it and launch a replacement.',
+              node.autoScalingGroup
+          )}`
+        : prompt
+    const selection = await window.showWarningMessage(message, { modal: true }, action)
+
+    return selection === action
+}
+
+/**
+ * Polls the instance of a node until it reaches `targetState`, updating the node with each state it goes through.
+ *
+ * @returns true if the state was reached, false if the user cancelled
+ */
+async function waitForInstanceState(
+    node: Ec2InstanceNode,
+    targetState: string,
+    options: {
+        commands: Commands
+        progress: vscode.Progress<{ message?: string }>
+        token: vscode.CancellationToken
+        pollIntervalMs: number
+        timeoutMs: number
+    }
+): Promise<boolean> {
+    const deadline = Date.now() + options.timeoutMs
+
+    while (!options.token.isCancellationRequested) {
+        const instance = await node.ec2.getInstance(node.instanceId)
+        if (!instance) {
+            throw new Error(localize('AWS.ec2.instanceNotFound', 'instance {0} no longer exists', node.instanceId))
+        }
+
+        if (instance.State?.Name !== node.state) {
+            node.update(instance)
+            await options.commands.execute('aws.refreshAwsExplorerNode', node)
+        }
+        options.progress.report({ message: node.state })
+
+        if (node.state === targetState) {
+            return true
+        }
+        if (node.state === 'shutting-down' || node.state === 'terminated') {
+            throw new Error(localize('AWS.ec2.instanceTerminated', 'instance {0} is {1}', node.instanceId, node.state))
+        }
+        if (Date.now() + options.pollIntervalMs > deadline) {
+            throw new Error(
+                localize(
+                    'AWS.ec2.stateTimeout',
+                    'instance did not reach the {0} state within {1} seconds',
+                    targetState,
+                    Math.round(options.timeoutMs / 1000)
+                )
+            )
+        }
+
+        await new Promise(resolve => setTimeout(resolve, options.pollIntervalMs))
+    }
+
+    return false
+}
@@ -0,0 +1,65 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { AwsContext } from '../../shared/awsContext'
+import { SsmDocumentClient } from '../../shared/clients/ssmDocumentClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { openSessionTerminal } from '../../shared/ssm/sessionTerminal'
+import { recordEc2ConnectViaSsm, Result } from '../../shared/telemetry/telemetry'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { Ec2InstanceNode } from '../explorer/ec2InstanceNode'
+
+/**
+ * Opens a Session Manager shell on a running, SSM managed instance, in an integrated terminal.
+ */
+export async function connectViaSsm(
+    node: Ec2InstanceNode,
+    {
+        window = Window.vscode(),
+        ssm = ext.toolkitClientBuilder.createSsmClient(node.ec2.regionCode),
+        awsContext = ext.awsContext,
+        createTerminal = vscode.window.createTerminal,
+    }: {
+        window?: Window
+        ssm?: SsmDocumentClient
+        awsContext?: Pick<AwsContext, 'getCredentialProfileName'>
+        createTerminal?(options: vscode.TerminalOptions): vscode.Terminal
+    } = {}
+): Promise<void> {
+    let result: Result = 'Succeeded'
+    try {
+        const session = await ssm.startSession(node.instanceId)
+        if (!session.SessionId) {
+            throw new Error(localize('AWS.ec2.connectViaSsm.noSession', 'StartSession did not return a session'))
+        }
+
+        await openSessionTerminal(
+            {
+                name: localize('AWS.ec2.connectViaSsm.terminalName', 'EC2: {0}', node.name),
+                session,
+                sessionId: session.SessionId,
+                target: node.instanceId,
+                ssm,
+            },
+            { awsContext, createTerminal }
+        )
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to connect to instance %s: %O', node.instanceId, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.ec2.connectViaSsm.error',
+                'Failed to connect to instance {0}: {1}',
+                node.name,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordEc2ConnectViaSsm({ result })
+    }
+}
@@ -0,0 +1,75 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { EC2 } from 'aws-sdk'
+import { Ec2Client } from '../../shared/clients/ec2Client'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { Ec2Node } from './ec2Node'
+
+/** Tag added by EC2 Auto Scaling to the instances it launches. */
+export const AUTO_SCALING_GROUP_TAG = 'aws:autoscaling:groupName'
+
+export class Ec2InstanceNode extends AWSTreeNodeBase {
+    public constructor(
+        public readonly parent: Ec2Node,
+        public readonly ec2: Ec2Client,
+        public instance: EC2.Instance,
+        public ssmManaged: boolean = false
+    ) {
+        super('', vscode.TreeItemCollapsibleState.None)
+        this.update(instance)
+    }
+
+    public get instanceId(): string {
+        return this.instance.InstanceId ?? ''
+    }
+
+    public get name(): string {
+        return getTag(this.instance, 'Name') || this.instanceId
+    }
+
+    public get state(): string {
+        return this.instance.State?.Name ?? 'unknown'
+    }
+
+    /**
+     * The Auto Scaling group that launched this instance, if any. The group may replace the instance when it is
+     * stopped or fails its health checks.
+     */
+    public get autoScalingGroup(): string | undefined {
+        return getTag(this.instance, AUTO_SCALING_GROUP_TAG)
+    }
+
+    public update(instance: EC2.Instance): void {
+        this.instance = instance
+        // The SSM Agent goes offline with the instance, and takes a while to come back after it starts
+        if (this.state !== 'running') {
+            this.ssmManaged = false
+        }
+        this.label = this.name
+        this.description = this.state
+        this.tooltip = `${this.name}\n${this.instanceId}\n${this.state}`
+        this.contextValue = getContextValue(this.state, this.ssmManaged)
+    }
+}
+
+/**
+ * Only running instances can be stopped or rebooted, and connected to if their SSM Agent is online.
+ */
+function getContextValue(state: string, ssmManaged: boolean): string {
+    switch (state) {
+        case 'running':
+            return ssmManaged ? 'awsEc2RunningSsmNode' : 'awsEc2RunningNode'
+        case 'stopped':
+            return 'awsEc2StoppedNode'
+        default:
+            return 'awsEc2InstanceNode'
+    }
+}
+
+function getTag(instance: EC2.Instance, key: string): string | undefined {
+    return instance.Tags?.find(tag => tag.Key === key)?.Value
+}
@@ -0,0 +1,70 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { Ec2Client } from '../../shared/clients/ec2Client'
+import { SsmDocumentClient } from '../../shared/clients/ssmDocumentClient'
+import { getLogger } from '../../shared/logger'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Ec2InstanceNode } from './ec2InstanceNode'
+
+/**
+ * An AWS Explorer node representing EC2.
+ *
+ * Contains instances for a specific region as child nodes.
+ */
+export class Ec2Node extends AWSTreeNodeBase {
+    public constructor(private readonly ec2: Ec2Client, private readonly ssm: SsmDocumentClient) {
+        super('EC2', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsEc2Node'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const instances = await toArrayAsync(this.ec2.describeInstances())
+                const runningIds = instances
+                    .filter(instance => instance.State?.Name === 'running')
+                    .map(instance => instance.InstanceId!)
+                const managedIds = await this.getOnlineManagedInstanceIds(runningIds)
+
+                return instances.map(
+                    instance => new Ec2InstanceNode(this, this.ec2, instance, managedIds.has(instance.InstanceId!))
+                )
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.ec2.noInstances', '[No instances found]')),
+            sort: (item1: Ec2InstanceNode, item2: Ec2InstanceNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    /**
+     * Listing SSM managed instances needs its own permission, so instances are shown even if it is missing.
+     */
+    private async getOnlineManagedInstanceIds(instanceIds: string[]): Promise<Set<string>> {
+        if (instanceIds.length === 0) {
+            return new Set()
+        }
+
+        try {
+            return await this.ssm.getOnlineManagedInstanceIds(instanceIds)
+        } catch (err) {
+            getLogger().warn('Failed to get the SSM managed instances of %s: %O', this.ssm.regionCode, err)
+
+            return new Set()
+        }
+    }
+
+    public [inspect.custom](): string {
+        return 'Ec2Node'
+    }
+}
@@ -12,6 +12,8 @@ import { EcrNode } from './explorer/ecrNode'
 import { EcrRepositoryNode } from './explorer/ecrRepositoryNode'
 import { EcrTagNode } from './explorer/ecrTagNode'
 import { deleteTag } from './commands/deleteTag'
+import { pullImage } from './commands/pullImage'
+import { pushImage } from './commands/pushImage'
 
 /**
  * Activates ECR components.
@@ -32,6 +34,12 @@ export async function activate(extensionContext: vscode.ExtensionContext): Promi
         }),
         vscode.commands.registerCommand('aws.ecr.deleteTag', async (node: EcrTagNode) => {
             await deleteTag(node)
+        }),
+        vscode.commands.registerCommand('aws.ecr.pushImage', async (node: EcrRepositoryNode) => {
+            await pushImage(node)
+        }),
+        vscode.commands.registerCommand('aws.ecr.pullImage', async (node: EcrTagNode) => {
+            await pullImage(node)
         })
     )
 }
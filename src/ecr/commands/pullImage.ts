@@ -0,0 +1,67 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordEcrPullImage, Result } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { checkDockerRunning, DOCKER, dockerLogin, DockerRunner, DockerUnavailableError, runDocker } from '../docker'
+import { EcrTagNode } from '../explorer/ecrTagNode'
+
+/**
+ * Pulls a tagged image from a repository, showing the progress of the pull in a terminal.
+ *
+ * A new authorization token is requested for every pull, as tokens expire after 12 hours.
+ */
+export async function pullImage(
+    node: EcrTagNode,
+    {
+        window = Window.vscode(),
+        run = runDocker,
+        createTerminal = vscode.window.createTerminal,
+    }: {
+        window?: Window
+        run?: DockerRunner
+        createTerminal?(options: vscode.TerminalOptions): vscode.Terminal
+    } = {}
+): Promise<void> {
+    getLogger().debug('pullImage called for: %O', node)
+    const image = `${node.repository.repositoryUri}:${node.tag}`
+    let result: Result = 'Succeeded'
+
+    try {
+        await checkDockerRunning(run)
+        await dockerLogin(await node.getAuthorizationToken(), run)
+
+        getLogger().info('Pulling %s', image)
+        const terminal = createTerminal({
+            name: localize(
+                'AWS.ecr.pullImage.terminalName',
+                'ECR: pull {0}:{1}',
+                node.repository.repositoryName,
+                node.tag
+            ),
+            shellPath: DOCKER,
+            shellArgs: ['pull', image],
+        })
+        terminal.show()
+    } catch (err) {
+        result = 'Failed'
+        if (err instanceof DockerUnavailableError) {
+            window.showErrorMessage(err.message)
+            return
+        }
+
+        getLogger().error('Failed to pull image %s: %O', image, err)
+        showErrorWithLogs(
+            localize('AWS.ecr.pullImage.failure', 'Failed to pull image {0}: {1}', image, (err as Error).message),
+            window
+        )
+    } finally {
+        recordEcrPullImage({ result })
+    }
+}
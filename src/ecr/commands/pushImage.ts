@@ -0,0 +1,128 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordEcrPushImage, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import {
+    checkDockerRunning,
+    DOCKER,
+    dockerLogin,
+    DockerRunner,
+    DockerUnavailableError,
+    getImageTag,
+    listLocalImages,
+    runDocker,
+    validateImageTag,
+} from '../docker'
+import { EcrRepositoryNode } from '../explorer/ecrRepositoryNode'
+
+/**
+ * Tags a local image with the URI of a repository and pushes it, showing the progress of the push in a terminal.
+ *
+ * A new authorization token is requested for every push, as tokens expire after 12 hours.
+ */
+export async function pushImage(
+    node: EcrRepositoryNode,
+    {
+        window = Window.vscode(),
+        run = runDocker,
+        createTerminal = vscode.window.createTerminal,
+        promptUserFunction = promptUser,
+    }: {
+        window?: Window
+        run?: DockerRunner
+        createTerminal?(options: vscode.TerminalOptions): vscode.Terminal
+        promptUserFunction?: typeof promptUser
+    } = {}
+): Promise<void> {
+    getLogger().debug('pushImage called for: %O', node)
+    const repositoryName = node.repository.repositoryName
+    let result: Result = 'Succeeded'
+
+    try {
+        await checkDockerRunning(run)
+
+        const image = await pickLocalImage(repositoryName, await listLocalImages(run), window, promptUserFunction)
+        if (!image) {
+            result = 'Cancelled'
+            return
+        }
+
+        const tag = await window.showInputBox({
+            prompt: localize('AWS.ecr.pushImage.tagPrompt', 'Enter the tag to push {0} as', image),
+            value: getImageTag(image),
+            validateInput: validateImageTag,
+            ignoreFocusOut: true,
+        })
+        if (!tag) {
+            result = 'Cancelled'
+            return
+        }
+
+        await dockerLogin(await node.getAuthorizationToken(), run)
+
+        const remoteImage = `${node.repository.repositoryUri}:${tag}`
+        const tagResult = await run(['tag', image, remoteImage])
+        if (tagResult.exitCode !== 0) {
+            throw new Error(tagResult.stderr || `docker tag failed with exit code ${tagResult.exitCode}`)
+        }
+
+        getLogger().info('Pushing %s to %s', image, remoteImage)
+        const terminal = createTerminal({
+            name: localize('AWS.ecr.pushImage.terminalName', 'ECR: push {0}:{1}', repositoryName, tag),
+            shellPath: DOCKER,
+            shellArgs: ['push', remoteImage],
+        })
+        terminal.show()
+    } catch (err) {
+        result = 'Failed'
+        if (err instanceof DockerUnavailableError) {
+            window.showErrorMessage(err.message)
+            return
+        }
+
+        getLogger().error('Failed to push image to repository %s: %O', repositoryName, err)
+        showErrorWithLogs(
+            localize(
+                'AWS.ecr.pushImage.failure',
+                'Failed to push image to repository {0}: {1}',
+                repositoryName,
+                (err as Error).message
+            ),
+            window
+        )
+    } finally {
+        recordEcrPushImage({ result })
+    }
+}
+
+async function pickLocalImage(
+    repositoryName: string,
+    images: string[],
+    window: Window,
+    promptUserFunction: typeof promptUser
+): Promise<string | undefined> {
+    if (images.length === 0) {
+        window.showInformationMessage(localize('AWS.ecr.pushImage.noImages', 'No local Docker images found'))
+        return undefined
+    }
+
+    const picker = createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.ecr.pushImage.title', 'Push image to {0}', repositoryName),
+            placeHolder: localize('AWS.ecr.pushImage.placeholder', 'Choose a local image to push'),
+        },
+        items: images.map(image => ({ label: image })),
+    })
+    const response = verifySinglePickerOutput(await promptUserFunction({ picker }))
+
+    return response?.label
+}
@@ -0,0 +1,102 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { EcrAuthorization } from '../shared/clients/ecrClient'
+import { getLogger } from '../shared/logger'
+import { ChildProcess, ChildProcessResult } from '../shared/utilities/childProcess'
+import { localize } from '../shared/utilities/vsCodeUtils'
+
+export const DOCKER = 'docker'
+
+const DOCKER_TAG_REGEX = /^[\w][\w.-]{0,127}$/
+const NO_VALUE = '<none>'
+
+/**
+ * Runs a Docker CLI command to completion, writing `input` to its stdin.
+ */
+export type DockerRunner = (args: string[], input?: string) => Promise<ChildProcessResult>
+
+export const runDocker: DockerRunner = async (args, input) =>
+    await new ChildProcess(true, DOCKER, undefined, ...args).run(undefined, undefined, input)
+
+/**
+ * Thrown when Docker cannot be used, with a message that can be shown to the user as is.
+ */
+export class DockerUnavailableError extends Error {}
+
+/**
+ * Checks that the Docker CLI is installed and that it can reach the Docker daemon.
+ *
+ * @throws DockerUnavailableError if Docker is not installed or not running
+ */
+export async function checkDockerRunning(run: DockerRunner = runDocker): Promise<void> {
+    const result = await run(['info', '--format', '{{.ServerVersion}}'])
+    if (result.error) {
+        getLogger().error('Failed to run docker: %O', result.error)
+        throw new DockerUnavailableError(
+            localize(
+                'AWS.ecr.docker.notInstalled',
+                'Docker was not found. Install Docker and make sure "docker" is on your PATH.'
+            )
+        )
+    }
+    if (result.exitCode !== 0) {
+        getLogger().error('docker info failed with exit code %d: %s', result.exitCode, result.stderr)
+        throw new DockerUnavailableError(
+            localize('AWS.ecr.docker.notRunning', 'Docker is not running. Start Docker and try again.')
+        )
+    }
+}
+
+/**
+ * Lists the tagged images in the local Docker image store, as `repository:tag`.
+ */
+export async function listLocalImages(run: DockerRunner = runDocker): Promise<string[]> {
+    const result = await run(['images', '--format', '{{.Repository}}:{{.Tag}}'])
+    if (result.exitCode !== 0) {
+        throw new Error(result.stderr || `docker images failed with exit code ${result.exitCode}`)
+    }
+
+    const images = result.stdout
+        .split(/\r?\n/)
+        .map(line => line.trim())
+        .filter(line => line && !line.includes(NO_VALUE))
+
+    return [...new Set(images)]
+}
+
+/**
+ * Logs in to a registry. The password is written to stdin so it does not appear in the process arguments or logs.
+ */
+export async function dockerLogin(authorization: EcrAuthorization, run: DockerRunner = runDocker): Promise<void> {
+    const result = await run(
+        ['login', '--username', authorization.username, '--password-stdin', authorization.proxyEndpoint],
+        authorization.password
+    )
+    if (result.exitCode !== 0) {
+        throw new Error(result.stderr || `docker login failed with exit code ${result.exitCode}`)
+    }
+}
+
+/**
+ * Gets the tag of an image reference such as `registry:5000/name:tag`, or `latest` if it has none.
+ */
+export function getImageTag(image: string): string {
+    const name = image.substring(image.lastIndexOf('/') + 1)
+    const separator = name.lastIndexOf(':')
+
+    return separator >= 0 ? name.substring(separator + 1) : 'latest'
+}
+
+export function validateImageTag(tag: string): string | undefined {
+    if (!DOCKER_TAG_REGEX.test(tag)) {
+        return localize(
+            'AWS.ecr.validateTag.error',
+            'Tag must be at most 128 letters, numbers, underscores, periods, and hyphens, and must not start with a period or hyphen'
+        )
+    }
+
+    return undefined
+}
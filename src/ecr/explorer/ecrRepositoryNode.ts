@@ -7,7 +7,7 @@ import * as vscode from 'vscode'
 import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
 import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
 import { EcrNode } from './ecrNode'
-import { EcrClient, EcrRepository } from '../../shared/clients/ecrClient'
+import { EcrAuthorization, EcrClient, EcrRepository } from '../../shared/clients/ecrClient'
 import { ext } from '../../shared/extensionGlobals'
 import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
 import { toArrayAsync } from '../../shared/utilities/collectionUtils'
@@ -51,4 +51,8 @@ export class EcrRepositoryNode extends AWSTreeNodeBase implements AWSResourceNod
     public async deleteRepository(): Promise<void> {
         await this.ecr.deleteRepository(this.repository.repositoryName)
     }
+
+    public async getAuthorizationToken(): Promise<EcrAuthorization> {
+        return await this.ecr.getAuthorizationToken()
+    }
 }
@@ -5,7 +5,7 @@
 
 import * as vscode from 'vscode'
 import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
-import { EcrClient, EcrRepository } from '../../shared/clients/ecrClient'
+import { EcrAuthorization, EcrClient, EcrRepository } from '../../shared/clients/ecrClient'
 import { EcrRepositoryNode } from './ecrRepositoryNode'
 
 export class EcrTagNode extends AWSTreeNodeBase {
@@ -22,4 +22,8 @@ export class EcrTagNode extends AWSTreeNodeBase {
     public async deleteTag(): Promise<void> {
         await this.ecr.deleteTag(this.repository.repositoryName, this.tag)
     }
+
+    public async getAuthorizationToken(): Promise<EcrAuthorization> {
+        return await this.ecr.getAuthorizationToken()
+    }
 }
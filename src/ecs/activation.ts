@@ -0,0 +1,19 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { executeCommand } from './commands/executeCommand'
+import { EcsTaskNode } from './explorer/ecsTaskNode'
+
+/**
+ * Activates ECS components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.ecs.executeCommand', async (node: EcsTaskNode) => {
+            await executeCommand(node)
+        })
+    )
+}
@@ -0,0 +1,182 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { ECS } from 'aws-sdk'
+import { AwsContext } from '../../shared/awsContext'
+import { SsmDocumentClient } from '../../shared/clients/ssmDocumentClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { openSessionTerminal } from '../../shared/ssm/sessionTerminal'
+import { recordEcsExecuteCommand, Result } from '../../shared/telemetry/telemetry'
+import * as picker from '../../shared/ui/picker'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { EcsServiceNode } from '../explorer/ecsServiceNode'
+import { EcsTaskNode } from '../explorer/ecsTaskNode'
+
+export const ECS_EXEC_DOCS_URL = 'https://docs.aws.amazon.com/AmazonECS/latest/developerguide/ecs-exec.html'
+const EXECUTE_COMMAND_AGENT = 'ExecuteCommandAgent'
+const DEFAULT_COMMAND = '/bin/sh'
+
+/**
+ * Opens an interactive ECS Exec session into a container of a running task.
+ *
+ * The session returned by `ExecuteCommand` is handed to the Session Manager plugin, running in an
+ * integrated terminal.
+ */
+export async function executeCommand(
+    node: EcsTaskNode,
+    {
+        window = Window.vscode(),
+        ssm = ext.toolkitClientBuilder.createSsmClient(node.ecs.regionCode),
+        awsContext = ext.awsContext,
+        createTerminal = vscode.window.createTerminal,
+    }: {
+        window?: Window
+        ssm?: SsmDocumentClient
+        awsContext?: Pick<AwsContext, 'getCredentialProfileName'>
+        createTerminal?(options: vscode.TerminalOptions): vscode.Terminal
+    } = {}
+): Promise<void> {
+    getLogger().debug('executeCommand called for: %O', node)
+    let result: Result = 'Succeeded'
+    try {
+        const serviceNode = node.parent
+        if (!serviceNode.service.enableExecuteCommand) {
+            result = 'Cancelled'
+            await promptToEnableExecuteCommand(serviceNode, window)
+
+            return
+        }
+
+        const container = await pickContainer(node.task)
+        if (!container) {
+            result = 'Cancelled'
+
+            return
+        }
+
+        const command = await window.showInputBox({
+            prompt: localize(
+                'AWS.ecs.executeCommand.prompt',
+                'Enter the command to run in container {0}',
+                container.name
+            ),
+            value: DEFAULT_COMMAND,
+            ignoreFocusOut: true,
+        })
+        if (!command) {
+            result = 'Cancelled'
+
+            return
+        }
+
+        const response = await node.ecs.executeCommand({
+            cluster: serviceNode.parent.arn,
+            task: node.arn,
+            container: container.name,
+            command,
+            interactive: true,
+        })
+        if (!response.session?.sessionId) {
+            throw new Error(localize('AWS.ecs.executeCommand.noSession', 'ExecuteCommand did not return a session'))
+        }
+
+        await openSessionTerminal(
+            {
+                name: localize('AWS.ecs.executeCommand.terminalName', 'ECS: {0}/{1}', node.name, container.name),
+                session: response.session,
+                sessionId: response.session.sessionId,
+                target: getExecTarget(serviceNode.parent.arn, node.arn, container),
+                ssm,
+            },
+            { awsContext, createTerminal }
+        )
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to execute command in task %s: %O', node.arn, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.ecs.executeCommand.error',
+                'Failed to execute command in task {0}: {1}',
+                node.name,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordEcsExecuteCommand({ result })
+    }
+}
+
+async function promptToEnableExecuteCommand(node: EcsServiceNode, window: Window): Promise<void> {
+    const enable = localize('AWS.ecs.executeCommand.enable', 'Enable and Redeploy')
+    const learnMore = localize('AWS.generic.message.learnMore', 'Learn More')
+    const selection = await window.showWarningMessage(
+        localize(
+            'AWS.ecs.executeCommand.disabled',
+            'Execute Command is not enabled for service {0}. Enabling it redeploys the service.',
+            node.name
+        ),
+        enable,
+        learnMore
+    )
+
+    if (selection === learnMore) {
+        vscode.env.openExternal(vscode.Uri.parse(ECS_EXEC_DOCS_URL))
+    } else if (selection === enable) {
+        await node.enableExecuteCommand()
+        window.showInformationMessage(
+            localize(
+                'AWS.ecs.executeCommand.enabled',
+                'Enabled Execute Command for service {0}. Refresh the explorer once the new tasks are running.',
+                node.name
+            )
+        )
+    }
+}
+
+/**
+ * Picks the container to attach to, prompting only if the task has more than one that runs the ECS Exec agent.
+ */
+async function pickContainer(task: ECS.Task): Promise<ECS.Container | undefined> {
+    const containers = (task.containers ?? []).filter(container => container.name && isExecAgentRunning(container))
+
+    if (containers.length === 0) {
+        throw new Error(
+            localize('AWS.ecs.executeCommand.noContainers', 'no container in the task is running the ECS Exec agent')
+        )
+    }
+    if (containers.length === 1) {
+        return containers[0]
+    }
+
+    const quickPick = picker.createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.ecs.executeCommand.pickContainer', 'Choose a container'),
+        },
+        items: containers.map(container => ({ label: container.name!, description: container.image, container })),
+    })
+    const choice = picker.verifySinglePickerOutput(await picker.promptUser({ picker: quickPick }))
+
+    return choice?.container
+}
+
+/**
+ * The SSM target of an ECS Exec session, as the AWS CLI builds it.
+ */
+function getExecTarget(clusterArn: string, taskArn: string, container: ECS.Container): string {
+    const clusterName = clusterArn.split('/').pop()
+    const taskId = taskArn.split('/').pop()
+
+    return `ecs:${clusterName}_${taskId}_${container.runtimeId}`
+}
+
+function isExecAgentRunning(container: ECS.Container): boolean {
+    return !!container.managedAgents?.some(
+        agent => agent.name === EXECUTE_COMMAND_AGENT && agent.lastStatus === 'RUNNING'
+    )
+}
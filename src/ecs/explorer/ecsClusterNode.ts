@@ -0,0 +1,50 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { ECS } from 'aws-sdk'
+import { EcsClient } from '../../shared/clients/ecsClient'
+import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { getResourceName } from '../utils'
+import { EcsNode } from './ecsNode'
+import { EcsServiceNode } from './ecsServiceNode'
+
+/** DescribeServices accepts at most this many services per call. */
+const DESCRIBE_SERVICES_BATCH_SIZE = 10
+
+export class EcsClusterNode extends AWSTreeNodeBase implements AWSResourceNode {
+    public readonly name: string = getResourceName(this.arn)
+
+    public constructor(public readonly parent: EcsNode, private readonly ecs: EcsClient, public readonly arn: string) {
+        super(getResourceName(arn), vscode.TreeItemCollapsibleState.Collapsed)
+        this.tooltip = arn
+        this.contextValue = 'awsEcsClusterNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const serviceArns = await toArrayAsync(this.ecs.listServices(this.arn))
+                const services: ECS.Service[] = []
+                for (let i = 0; i < serviceArns.length; i += DESCRIBE_SERVICES_BATCH_SIZE) {
+                    const batch = serviceArns.slice(i, i + DESCRIBE_SERVICES_BATCH_SIZE)
+                    services.push(...(await this.ecs.describeServices(this.arn, batch)))
+                }
+
+                return services.map(service => new EcsServiceNode(this, this.ecs, service))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.ecs.noServices', '[No services found]')),
+            sort: (item1: EcsServiceNode, item2: EcsServiceNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+}
@@ -0,0 +1,45 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { EcsClient } from '../../shared/clients/ecsClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { EcsClusterNode } from './ecsClusterNode'
+
+/**
+ * An AWS Explorer node representing ECS.
+ *
+ * Contains clusters for a specific region as child nodes.
+ */
+export class EcsNode extends AWSTreeNodeBase {
+    public constructor(private readonly ecs: EcsClient) {
+        super('ECS', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsEcsNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const response = await toArrayAsync(this.ecs.listClusters())
+
+                return response.map(arn => new EcsClusterNode(this, this.ecs, arn))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.ecs.noClusters', '[No clusters found]')),
+            sort: (item1: EcsClusterNode, item2: EcsClusterNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'EcsNode'
+    }
+}
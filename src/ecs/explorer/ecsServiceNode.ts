@@ -0,0 +1,62 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { ECS } from 'aws-sdk'
+import { EcsClient } from '../../shared/clients/ecsClient'
+import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { EcsClusterNode } from './ecsClusterNode'
+import { EcsTaskNode } from './ecsTaskNode'
+
+/** DescribeTasks accepts at most this many tasks per call. */
+const DESCRIBE_TASKS_BATCH_SIZE = 100
+
+export class EcsServiceNode extends AWSTreeNodeBase implements AWSResourceNode {
+    public readonly name: string = this.service.serviceName ?? ''
+    public readonly arn: string = this.service.serviceArn ?? ''
+
+    public constructor(
+        public readonly parent: EcsClusterNode,
+        private readonly ecs: EcsClient,
+        public service: ECS.Service
+    ) {
+        super(service.serviceName ?? '', vscode.TreeItemCollapsibleState.Collapsed)
+        this.tooltip = service.serviceArn
+        this.contextValue = 'awsEcsServiceNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const taskArns = await toArrayAsync(this.ecs.listTasks(this.parent.arn, this.name))
+                const tasks: ECS.Task[] = []
+                for (let i = 0; i < taskArns.length; i += DESCRIBE_TASKS_BATCH_SIZE) {
+                    const batch = taskArns.slice(i, i + DESCRIBE_TASKS_BATCH_SIZE)
+                    tasks.push(...(await this.ecs.describeTasks(this.parent.arn, batch)))
+                }
+
+                return tasks.map(task => new EcsTaskNode(this, this.ecs, task))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.ecs.noTasks', '[No running tasks found]')),
+            sort: (item1: EcsTaskNode, item2: EcsTaskNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    /**
+     * Enables ECS Exec and redeploys the service so that its tasks pick up the setting.
+     */
+    public async enableExecuteCommand(): Promise<void> {
+        await this.ecs.enableExecuteCommand(this.parent.arn, this.name)
+        this.service = { ...this.service, enableExecuteCommand: true }
+    }
+}
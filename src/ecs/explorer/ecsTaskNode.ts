@@ -0,0 +1,28 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { ECS } from 'aws-sdk'
+import { EcsClient } from '../../shared/clients/ecsClient'
+import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { getResourceName } from '../utils'
+import { EcsServiceNode } from './ecsServiceNode'
+
+export class EcsTaskNode extends AWSTreeNodeBase implements AWSResourceNode {
+    public readonly arn: string = this.task.taskArn ?? ''
+    public readonly name: string = getResourceName(this.arn)
+
+    public constructor(
+        public readonly parent: EcsServiceNode,
+        public readonly ecs: EcsClient,
+        public readonly task: ECS.Task
+    ) {
+        super(getResourceName(task.taskArn ?? ''), vscode.TreeItemCollapsibleState.None)
+        this.description = task.lastStatus
+        this.tooltip = task.taskArn
+        this.contextValue = 'awsEcsTaskNode'
+    }
+}
@@ -0,0 +1,14 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+/**
+ * Gets the name of an ECS resource from its ARN.
+ *
+ * Handles both the long ARN format (`arn:aws:ecs:us-east-1:123456789012:task/my-cluster/0123`)
+ * and the older short format (`arn:aws:ecs:us-east-1:123456789012:task/0123`).
+ */
+export function getResourceName(arn: string): string {
+    return arn.substring(arn.lastIndexOf('/') + 1)
+}
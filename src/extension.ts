@@ -47,6 +47,15 @@ import { EndpointsProvider } from './shared/regions/endpointsProvider'
 import { FileResourceFetcher } from './shared/resourcefetcher/fileResourceFetcher'
 import { HttpResourceFetcher } from './shared/resourcefetcher/httpResourceFetcher'
 import { activate as activateEcr } from './ecr/activation'
+import { activate as activateSecretsManager } from './secretsManager/activation'
+import { activate as activateKinesis } from './kinesis/activation'
+import { activate as activateSqs } from './sqs/activation'
+import { activate as activateEcs } from './ecs/activation'
+import { activate as activateEc2 } from './ec2/activation'
+import { onDidCloseTerminal as onDidCloseSessionTerminal } from './shared/ssm/sessionTerminal'
+import { activate as activateDynamoDb } from './dynamoDb/activation'
+import { activate as activateCognito } from './cognito/activation'
+import { activate as activateAppConfig } from './appconfig/activation'
 import { activate as activateSam } from './shared/sam/activation'
 import { DefaultSettingsConfiguration } from './shared/settingsConfiguration'
 import { activate as activateTelemetry } from './shared/telemetry/activation'
@@ -111,7 +120,8 @@ export async function activate(context: vscode.ExtensionContext) {
             .filter(x => x)
             .forEach(line => getLogger().info(line))
 
-        await initializeAwsCredentialsStatusBarItem(awsContext, context)
+        await initializeAwsCredentialsStatusBarItem(awsContext, context, loginManager.expiryMonitor)
+        context.subscriptions.push(loginManager.expiryMonitor)
         ext.awsContextCommands = new DefaultAWSContextCommands(
             awsContext,
             awsContextTrees,
@@ -212,6 +222,7 @@ export async function activate(context: vscode.ExtensionContext) {
             regionProvider,
             toolkitOutputChannel,
             remoteInvokeOutputChannel,
+            loginManager,
         })
 
         await activateApiGateway({
@@ -231,6 +242,25 @@ export async function activate(context: vscode.ExtensionContext) {
 
         await activateEcr(context)
 
+        // the SSM sessions of EC2 and ECS terminals end when their terminal closes
+        context.subscriptions.push(vscode.window.onDidCloseTerminal(onDidCloseSessionTerminal))
+
+        await activateEc2(context)
+
+        await activateEcs(context)
+
+        await activateSecretsManager(context)
+
+        await activateKinesis(context)
+
+        await activateSqs(context)
+
+        await activateDynamoDb(context)
+
+        await activateCognito(context)
+
+        await activateAppConfig(context)
+
         await activateCloudWatchLogs(context, toolkitSettings)
 
         // Features which aren't currently functional in Cloud9
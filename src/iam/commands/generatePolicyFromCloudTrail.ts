@@ -0,0 +1,294 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CloudTrail } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { RegionNode } from '../../awsexplorer/regionNode'
+import { CloudTrailClient } from '../../shared/clients/cloudTrailClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordIamGeneratePolicyFromCloudTrail } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+
+/** How many days of events CloudTrail event history keeps, and so how far back `LookupEvents` can read. */
+export const EVENT_HISTORY_DAYS = 90
+
+const DEFAULT_LOOKBACK_DAYS = 30
+
+/** Endpoint prefixes of services whose IAM action prefix differs. */
+const ACTION_PREFIX_OVERRIDES: { [endpointPrefix: string]: string } = {
+    monitoring: 'cloudwatch',
+}
+
+/** Event sources that record activity which isn't authorized by IAM actions, e.g. console sign-in. */
+const IGNORED_EVENT_SOURCES = ['signin.amazonaws.com']
+
+export interface PolicyStatement {
+    Sid: string
+    Effect: 'Allow'
+    Action: string[]
+    Resource: string
+}
+
+export interface PolicyDocument {
+    Version: '2012-10-17'
+    Statement: PolicyStatement[]
+}
+
+export interface RoleActivity {
+    /** IAM actions (e.g. `s3:ListBuckets`) the role was allowed to call. */
+    actions: Set<string>
+    /** Events read, including those of other principals. */
+    eventCount: number
+}
+
+interface CloudTrailEventRecord {
+    errorCode?: string
+    userIdentity?: {
+        type?: string
+        arn?: string
+        sessionContext?: { sessionIssuer?: { type?: string; arn?: string } }
+    }
+}
+
+/**
+ * Validates a role ARN, e.g. `arn:aws:iam::123456789012:role/service-role/MyRole`.
+ *
+ * @returns an error message, or undefined if the ARN is valid.
+ */
+export function validateRoleArn(value: string): string | undefined {
+    if (!/^arn:[^:]+:iam::\d{12}:role\/\S+$/.test(value.trim())) {
+        return localize(
+            'AWS.iam.generatePolicy.roleArn.invalid',
+            'Enter a role ARN, e.g. arn:aws:iam::123456789012:role/MyRole'
+        )
+    }
+
+    return undefined
+}
+
+function validateLookbackDays(value: string): string | undefined {
+    const days = Number(value)
+    if (!Number.isInteger(days) || days < 1 || days > EVENT_HISTORY_DAYS) {
+        return localize(
+            'AWS.iam.generatePolicy.lookbackDays.invalid',
+            'Enter a whole number of days from 1 to {0}',
+            EVENT_HISTORY_DAYS
+        )
+    }
+
+    return undefined
+}
+
+/**
+ * Maps a CloudTrail event to the IAM action it was authorized by, e.g. `ec2.amazonaws.com` and
+ * `DescribeInstances` to `ec2:DescribeInstances`.
+ *
+ * @returns the action, or undefined if the event isn't from a service API.
+ */
+export function toIamAction(eventSource: string, eventName: string): string | undefined {
+    const match = /^([a-z0-9-]+)\.amazonaws\.com$/.exec(eventSource)
+    if (!match || IGNORED_EVENT_SOURCES.includes(eventSource) || !eventName) {
+        return undefined
+    }
+    const prefix = ACTION_PREFIX_OVERRIDES[match[1]] ?? match[1]
+    // Lambda records the API version in event names, e.g. `GetFunction20150331v2`
+    const name = eventName.replace(/\d{8}(v\d+)?$/, '')
+
+    return `${prefix}:${name}`
+}
+
+/**
+ * Whether an event was recorded for a call made with a session of the given role, and was allowed.
+ * Denied calls are left out: the role didn't have permission for them, and may not need it.
+ */
+export function isAllowedEventOfRole(event: CloudTrail.Event, roleArn: string): boolean {
+    let record: CloudTrailEventRecord
+    try {
+        record = JSON.parse(event.CloudTrailEvent ?? '{}')
+    } catch (e) {
+        getLogger().debug(`Skipping CloudTrail event ${event.EventId} that is not valid JSON: %O`, e as Error)
+        return false
+    }
+    if (record.errorCode && /AccessDenied|Unauthorized/.test(record.errorCode)) {
+        return false
+    }
+
+    const identity = record.userIdentity
+
+    return identity?.type === 'AssumedRole' && identity.sessionContext?.sessionIssuer?.arn === roleArn
+}
+
+/**
+ * Makes a policy that allows the given actions, with a statement per service.
+ * Statements apply to all resources, so they should be scoped down before the policy is used.
+ */
+export function synthesizePolicy(actions: Iterable<string>): PolicyDocument {
+    const actionsByPrefix = new Map<string, Set<string>>()
+    for (const action of actions) {
+        const prefix = action.split(':')[0]
+        actionsByPrefix.set(prefix, (actionsByPrefix.get(prefix) ?? new Set<string>()).add(action))
+    }
+
+    const statements = [...actionsByPrefix.keys()].sort().map(prefix => {
+        const alphanumeric = prefix.replace(/[^A-Za-z0-9]/g, '')
+
+        return {
+            Sid: `${alphanumeric.charAt(0).toUpperCase()}${alphanumeric.slice(1)}Actions`,
+            Effect: 'Allow' as const,
+            Action: [...actionsByPrefix.get(prefix)!].sort(),
+            Resource: '*',
+        }
+    })
+
+    return { Version: '2012-10-17', Statement: statements }
+}
+
+/**
+ * Reads the actions a role called within the given times from the CloudTrail event history of a region.
+ * `LookupEvents` can't filter by role, so all events of the region are read and filtered.
+ *
+ * @param onProgress Called with the number of events read so far.
+ * @returns the activity read, or undefined if cancelled.
+ */
+export async function readRoleActivity(
+    client: CloudTrailClient,
+    roleArn: string,
+    startTime: Date,
+    endTime: Date,
+    { token, onProgress }: { token?: vscode.CancellationToken; onProgress?: (eventCount: number) => void } = {}
+): Promise<RoleActivity | undefined> {
+    const actions = new Set<string>()
+    let eventCount = 0
+
+    for await (const event of client.lookupEvents(startTime, endTime)) {
+        if (token?.isCancellationRequested) {
+            return undefined
+        }
+        eventCount++
+        if (isAllowedEventOfRole(event, roleArn)) {
+            const action = toIamAction(event.EventSource ?? '', event.EventName ?? '')
+            if (action) {
+                actions.add(action)
+            }
+        }
+        // a page of events is 50 at most
+        if (eventCount % 50 === 0) {
+            onProgress?.(eventCount)
+        }
+    }
+
+    return { actions, eventCount }
+}
+
+/**
+ * Generates a least-privilege policy for a role from the actions it called, as recorded by CloudTrail in the
+ * node's region, and opens it in a JSON document.
+ */
+export async function generatePolicyFromCloudTrail(
+    node: RegionNode,
+    window = Window.vscode(),
+    client = ext.toolkitClientBuilder.createCloudTrailClient(node.regionCode),
+    openDocument = async (content: string) =>
+        vscode.window.showTextDocument(await vscode.workspace.openTextDocument({ language: 'json', content }))
+): Promise<void> {
+    getLogger().debug('GeneratePolicyFromCloudTrail called for %s', node.regionCode)
+
+    const roleArn = (
+        await window.showInputBox({
+            prompt: localize(
+                'AWS.iam.generatePolicy.roleArn.prompt',
+                'Enter the ARN of the role to generate a policy for'
+            ),
+            placeHolder: 'arn:aws:iam::123456789012:role/MyRole',
+            ignoreFocusOut: true,
+            validateInput: validateRoleArn,
+        })
+    )?.trim()
+    const lookbackDays = roleArn
+        ? await window.showInputBox({
+              prompt: localize(
+                  'AWS.iam.generatePolicy.lookbackDays.prompt',
+                  'Enter how many days of CloudTrail event history to read (at most {0})',
+                  EVENT_HISTORY_DAYS
+              ),
+              value: `${DEFAULT_LOOKBACK_DAYS}`,
+              ignoreFocusOut: true,
+              validateInput: validateLookbackDays,
+          })
+        : undefined
+    if (!roleArn || !lookbackDays) {
+        getLogger().info('GeneratePolicyFromCloudTrail cancelled')
+        recordIamGeneratePolicyFromCloudTrail({ result: 'Cancelled' })
+        return
+    }
+
+    const endTime = new Date()
+    const startTime = new Date(endTime.getTime() - Number(lookbackDays) * 24 * 60 * 60 * 1000)
+
+    let activity: RoleActivity | undefined
+    try {
+        activity = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize(
+                    'AWS.iam.generatePolicy.progressTitle',
+                    'Reading CloudTrail events in {0}...',
+                    node.regionCode
+                ),
+                cancellable: true,
+            },
+            async (progress, token) =>
+                readRoleActivity(client, roleArn, startTime, endTime, {
+                    token,
+                    onProgress: eventCount =>
+                        progress.report({
+                            message: localize('AWS.iam.generatePolicy.progress', '{0} events read', eventCount),
+                        }),
+                })
+        )
+    } catch (e) {
+        getLogger().error(`Failed to read CloudTrail events of role ${roleArn}: %O`, e as Error)
+        showErrorWithLogs(
+            localize('AWS.iam.generatePolicy.error', 'Failed to read CloudTrail events of role {0}', roleArn),
+            window
+        )
+        recordIamGeneratePolicyFromCloudTrail({ result: 'Failed' })
+        return
+    }
+    if (!activity) {
+        getLogger().info('GeneratePolicyFromCloudTrail cancelled while reading events')
+        recordIamGeneratePolicyFromCloudTrail({ result: 'Cancelled' })
+        return
+    }
+
+    getLogger().info(`Found ${activity.actions.size} actions of ${roleArn} in ${activity.eventCount} CloudTrail events`)
+    if (activity.actions.size === 0) {
+        window.showInformationMessage(
+            localize(
+                'AWS.iam.generatePolicy.noActivity',
+                'No activity of role {0} was found in the CloudTrail event history of {1} for the last {2} days',
+                roleArn,
+                node.regionCode,
+                lookbackDays
+            )
+        )
+        recordIamGeneratePolicyFromCloudTrail({ result: 'Succeeded' })
+        return
+    }
+
+    await openDocument(JSON.stringify(synthesizePolicy(activity.actions), undefined, 4))
+    window.showWarningMessage(
+        localize(
+            'AWS.iam.generatePolicy.caveat',
+            'This policy is a starting point, not a complete policy. It only includes management events recorded in the CloudTrail event history of {0} for the last {1} days: data events (e.g. S3 object reads) are not included, and events of global services like IAM are recorded in us-east-1. Its statements apply to all resources. Review and scope it down before use.',
+            node.regionCode,
+            lookbackDays
+        )
+    )
+    recordIamGeneratePolicyFromCloudTrail({ result: 'Succeeded' })
+}
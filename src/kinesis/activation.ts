@@ -0,0 +1,19 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { KinesisStreamNode } from './explorer/kinesisStreamNode'
+import { viewRecordsCommand } from './vue/recordViewer'
+
+/**
+ * Activates Kinesis components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.kinesis.viewRecords', async (node: KinesisStreamNode) => {
+            await viewRecordsCommand(node, extensionContext)
+        })
+    )
+}
@@ -0,0 +1,45 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { KinesisClient } from '../../shared/clients/kinesisClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { KinesisStreamNode } from './kinesisStreamNode'
+
+/**
+ * An AWS Explorer node representing Kinesis Data Streams.
+ *
+ * Contains streams for a specific region as child nodes.
+ */
+export class KinesisNode extends AWSTreeNodeBase {
+    public constructor(private readonly kinesis: KinesisClient) {
+        super('Kinesis', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsKinesisNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const streamNames = await toArrayAsync(this.kinesis.listStreams())
+
+                return streamNames.map(streamName => new KinesisStreamNode(this, this.kinesis, streamName))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.kinesis.noStreams', '[No streams found]')),
+            sort: (item1: KinesisStreamNode, item2: KinesisStreamNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'KinesisNode'
+    }
+}
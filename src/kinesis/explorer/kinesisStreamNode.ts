@@ -0,0 +1,21 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { KinesisClient } from '../../shared/clients/kinesisClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { KinesisNode } from './kinesisNode'
+
+export class KinesisStreamNode extends AWSTreeNodeBase {
+    public constructor(
+        public readonly parent: KinesisNode,
+        public readonly kinesis: KinesisClient,
+        public readonly name: string
+    ) {
+        super(name, vscode.TreeItemCollapsibleState.None)
+        this.tooltip = name
+        this.contextValue = 'awsKinesisStreamNode'
+    }
+}
@@ -0,0 +1,176 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { Kinesis } from 'aws-sdk'
+import { KinesisClient } from '../shared/clients/kinesisClient'
+import { getLogger } from '../shared/logger'
+import { toArrayAsync } from '../shared/utilities/collectionUtils'
+
+/** Number of records requested from each shard per read. */
+export const RECORDS_PER_REQUEST = 100
+/** Initial delay before reading a shard again after its read throughput was exceeded. */
+export const THROTTLE_BACKOFF_MILLIS = 1000
+/** Upper bound for the delay before reading a throttled shard again. */
+export const MAX_THROTTLE_BACKOFF_MILLIS = 30000
+
+export type StartingPosition = 'LATEST' | 'TRIM_HORIZON'
+
+export interface StreamRecord {
+    shardId: string
+    partitionKey: string
+    sequenceNumber: string
+    approximateArrivalTimestamp?: string
+    /** Data decoded as UTF-8. */
+    text: string
+    /** Data encoded as base64, as returned by the Kinesis API. */
+    base64: string
+}
+
+export interface StreamReadResult {
+    records: StreamRecord[]
+    /** Number of shards that are being skipped until their read throughput recovers. */
+    throttledShards: number
+    /** Number of shards that can still have records to read. */
+    activeShards: number
+}
+
+interface ShardPosition {
+    readonly shardId: string
+    iterator?: string
+    lastSequenceNumber?: string
+    throttles: number
+    retryAt: number
+}
+
+const THROTTLING_ERRORS = ['ProvisionedThroughputExceededException', 'LimitExceededException']
+
+/**
+ * Reads the records of all shards of a stream, from the start of the stream or from new records only.
+ *
+ * Each shard supports only 5 `GetRecords` calls per second, shared with the stream's other consumers.
+ * Shards whose throughput is exceeded are backed off exponentially instead of failing the read.
+ */
+export class KinesisStreamReader {
+    private shards: ShardPosition[] | undefined
+
+    public constructor(
+        private readonly kinesis: KinesisClient,
+        private readonly streamName: string,
+        private readonly startingPosition: StartingPosition,
+        private readonly now: () => number = Date.now
+    ) {}
+
+    /**
+     * Reads the next records of every shard that is not backing off.
+     */
+    public async read(): Promise<StreamReadResult> {
+        if (!this.shards) {
+            this.shards = await this.listShards()
+        }
+
+        const time = this.now()
+        const ready = this.shards.filter(shard => shard.retryAt <= time)
+        const records = ([] as StreamRecord[]).concat(...(await Promise.all(ready.map(shard => this.readShard(shard)))))
+        // Records are only ordered within a shard
+        records.sort((a, b) =>
+            (a.approximateArrivalTimestamp ?? '').localeCompare(b.approximateArrivalTimestamp ?? '')
+        )
+
+        return {
+            records,
+            throttledShards: this.shards.filter(shard => shard.retryAt > this.now()).length,
+            activeShards: this.shards.length,
+        }
+    }
+
+    private async listShards(): Promise<ShardPosition[]> {
+        const shards = await toArrayAsync(this.kinesis.listShards(this.streamName))
+        // Closed shards only hold records written before the stream was resharded
+        const readable =
+            this.startingPosition === 'TRIM_HORIZON'
+                ? shards
+                : shards.filter(shard => !shard.SequenceNumberRange.EndingSequenceNumber)
+
+        return readable.map(shard => ({ shardId: shard.ShardId, throttles: 0, retryAt: 0 }))
+    }
+
+    private async getShardIterator(shard: ShardPosition): Promise<string | undefined> {
+        const request: Kinesis.GetShardIteratorInput = {
+            StreamName: this.streamName,
+            ShardId: shard.shardId,
+            ShardIteratorType: this.startingPosition,
+        }
+        if (shard.lastSequenceNumber) {
+            request.ShardIteratorType = 'AFTER_SEQUENCE_NUMBER'
+            request.StartingSequenceNumber = shard.lastSequenceNumber
+        }
+
+        return await this.kinesis.getShardIterator(request)
+    }
+
+    private async readShard(shard: ShardPosition): Promise<StreamRecord[]> {
+        try {
+            shard.iterator = shard.iterator ?? (await this.getShardIterator(shard))
+            if (!shard.iterator) {
+                this.removeShard(shard)
+                return []
+            }
+
+            const response = await this.kinesis.getRecords(shard.iterator, RECORDS_PER_REQUEST)
+            shard.throttles = 0
+            shard.iterator = response.NextShardIterator
+            if (!shard.iterator) {
+                getLogger().debug(`Finished reading closed shard ${shard.shardId} of ${this.streamName}`)
+                this.removeShard(shard)
+            }
+            if (response.Records.length > 0) {
+                shard.lastSequenceNumber = response.Records[response.Records.length - 1].SequenceNumber
+            }
+
+            return response.Records.map(record => toStreamRecord(shard.shardId, record))
+        } catch (e) {
+            const error = e as Error & { code?: string }
+            if (error.code && THROTTLING_ERRORS.includes(error.code)) {
+                shard.throttles++
+                const delay = Math.min(
+                    THROTTLE_BACKOFF_MILLIS * Math.pow(2, shard.throttles - 1),
+                    MAX_THROTTLE_BACKOFF_MILLIS
+                )
+                shard.retryAt = this.now() + delay
+                getLogger().warn(`Read throughput exceeded for shard ${shard.shardId}, retrying in ${delay} ms`)
+
+                return []
+            }
+            if (error.code === 'ExpiredIteratorException') {
+                // Iterators expire after 5 minutes; a new one resumes after the last record read
+                shard.iterator = undefined
+
+                return []
+            }
+
+            throw error
+        }
+    }
+
+    private removeShard(shard: ShardPosition): void {
+        this.shards = this.shards?.filter(other => other !== shard)
+    }
+}
+
+export function toStreamRecord(shardId: string, record: Kinesis.Record): StreamRecord {
+    const data =
+        typeof record.Data === 'string' ? Buffer.from(record.Data, 'base64') : Buffer.from(record.Data as Uint8Array)
+
+    return {
+        shardId,
+        partitionKey: record.PartitionKey,
+        sequenceNumber: record.SequenceNumber,
+        approximateArrivalTimestamp: record.ApproximateArrivalTimestamp
+            ? new Date(record.ApproximateArrivalTimestamp).toISOString()
+            : undefined,
+        text: data.toString('utf8'),
+        base64: data.toString('base64'),
+    }
+}
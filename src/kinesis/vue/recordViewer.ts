@@ -0,0 +1,175 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordKinesisViewRecords } from '../../shared/telemetry/telemetry'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { createVueWebview } from '../../webviews/main'
+import { KinesisStreamNode } from '../explorer/kinesisStreamNode'
+import { KinesisStreamReader, StartingPosition, StreamReadResult } from '../streamReader'
+
+/**
+ * How often records are read while tailing. Reads of a shard are limited to 5 per second, shared with the other
+ * consumers of the stream.
+ */
+export const TAIL_POLL_INTERVAL_MILLIS = 1000
+
+export type DataEncoding = 'text' | 'base64'
+
+export interface RecordViewerState {
+    startingPosition: StartingPosition
+    encoding: DataEncoding
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface StartReadingRequest {
+    command: 'startReading'
+    data: {
+        startingPosition: StartingPosition
+    }
+}
+
+export interface ReadRecordsRequest {
+    command: 'readRecords'
+}
+
+export interface SetTailRequest {
+    command: 'setTail'
+    data: {
+        tail: boolean
+    }
+}
+
+export interface StreamResponse {
+    command: 'stream'
+    data: {
+        streamName: string
+        regionCode: string
+    }
+}
+
+export interface RecordsResponse {
+    command: 'records'
+    data: StreamReadResult & {
+        /** True if the records are the first read from a new starting position. */
+        reset: boolean
+    }
+}
+
+export interface TailingResponse {
+    command: 'tailing'
+    data: {
+        tailing: boolean
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        message: string
+    }
+}
+
+export type RecordViewerRequest = InitializeRequest | StartReadingRequest | ReadRecordsRequest | SetTailRequest
+export type RecordViewerResponse = StreamResponse | RecordsResponse | TailingResponse | ErrorResponse
+
+/**
+ * Opens a view of the records of a stream, which can keep reading new records as they arrive.
+ */
+export async function viewRecordsCommand(
+    node: KinesisStreamNode,
+    context: vscode.ExtensionContext = ext.context
+): Promise<void> {
+    let reader: KinesisStreamReader | undefined
+    let tailTimer: NodeJS.Timeout | undefined
+    let postMessage: ((response: RecordViewerResponse) => Thenable<boolean>) | undefined
+
+    function stopTail(): void {
+        if (tailTimer) {
+            clearTimeout(tailTimer)
+            tailTimer = undefined
+        }
+    }
+
+    async function readRecords(reset: boolean): Promise<void> {
+        const current = reader
+        if (!current || !postMessage) {
+            throw new Error('Reading has not been started')
+        }
+        const result = await current.read()
+        // A new starting position was selected while reading
+        if (current === reader) {
+            await postMessage({ command: 'records', data: { ...result, reset } })
+        }
+    }
+
+    function scheduleTail(): void {
+        tailTimer = setTimeout(async () => {
+            try {
+                await readRecords(false)
+                // Cleared if tailing was stopped during the read
+                if (tailTimer) {
+                    scheduleTail()
+                }
+            } catch (e) {
+                const error = e as Error
+                getLogger().error(`Failed to tail Kinesis stream ${node.name}: %O`, error)
+                stopTail()
+                await postMessage?.({ command: 'error', data: { message: error.message } })
+                await postMessage?.({ command: 'tailing', data: { tailing: false } })
+            }
+        }, TAIL_POLL_INTERVAL_MILLIS)
+    }
+
+    await createVueWebview<RecordViewerRequest, RecordViewerResponse>({
+        id: 'kinesisRecordViewer',
+        name: localize('AWS.kinesis.recordViewer.title', 'Kinesis: {0}', node.name),
+        webviewJs: 'kinesisRecordViewerVue.js',
+        cssFiles: ['kinesisRecordViewer.css'],
+        context,
+        persistWithoutFocus: true,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            postMessage = postMessageFn
+            try {
+                switch (message.command) {
+                    case 'initialize':
+                        await postMessageFn({
+                            command: 'stream',
+                            data: { streamName: node.name, regionCode: node.kinesis.regionCode },
+                        })
+                        break
+                    case 'startReading':
+                        stopTail()
+                        await postMessageFn({ command: 'tailing', data: { tailing: false } })
+                        reader = new KinesisStreamReader(node.kinesis, node.name, message.data.startingPosition)
+                        await readRecords(true)
+                        break
+                    case 'readRecords':
+                        await readRecords(false)
+                        break
+                    case 'setTail':
+                        stopTail()
+                        if (message.data.tail) {
+                            scheduleTail()
+                        }
+                        await postMessageFn({ command: 'tailing', data: { tailing: message.data.tail } })
+                        break
+                }
+            } catch (e) {
+                const error = e as Error
+                getLogger().error(`Failed to read Kinesis stream ${node.name}: %O`, error)
+                await postMessageFn({ command: 'error', data: { message: error.message } })
+            }
+        },
+        onDidDisposeFunction: stopTail,
+    })
+
+    recordKinesisViewRecords({ result: 'Succeeded' })
+}
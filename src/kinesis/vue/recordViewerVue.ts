@@ -0,0 +1,169 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import { StartingPosition, StreamRecord } from '../streamReader'
+import { DataEncoding, RecordViewerResponse, RecordViewerState } from './recordViewer'
+
+declare const vscode: WebviewApi<RecordViewerState>
+
+/** Oldest records are dropped beyond this many, so that tailing a busy stream doesn't slow down the view. */
+const MAX_DISPLAYED_RECORDS = 1000
+
+export interface RecordViewerVueData {
+    streamName: string
+    regionCode: string
+    startingPosition: StartingPosition
+    encoding: DataEncoding
+    records: StreamRecord[]
+    droppedRecords: number
+    started: boolean
+    tailing: boolean
+    loading: boolean
+    throttledShards: number
+    activeShards: number
+    errorMsg: string
+}
+
+export const Component = Vue.extend({
+    created() {
+        const oldState = vscode.getState()
+        if (oldState) {
+            this.startingPosition = oldState.startingPosition
+            this.encoding = oldState.encoding
+        }
+        window.addEventListener('message', ev => {
+            const event = ev.data as RecordViewerResponse
+            switch (event.command) {
+                case 'stream':
+                    this.streamName = event.data.streamName
+                    this.regionCode = event.data.regionCode
+                    break
+                case 'records': {
+                    this.loading = false
+                    this.errorMsg = ''
+                    this.started = true
+                    const records = event.data.reset ? event.data.records : [...this.records, ...event.data.records]
+                    const dropped = Math.max(records.length - MAX_DISPLAYED_RECORDS, 0)
+                    this.droppedRecords = (event.data.reset ? 0 : this.droppedRecords) + dropped
+                    this.records = records.slice(dropped)
+                    this.throttledShards = event.data.throttledShards
+                    this.activeShards = event.data.activeShards
+                    break
+                }
+                case 'tailing':
+                    this.tailing = event.data.tailing
+                    break
+                case 'error':
+                    this.loading = false
+                    this.errorMsg = event.data.message
+                    break
+            }
+        })
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): RecordViewerVueData {
+        return {
+            streamName: '',
+            regionCode: '',
+            startingPosition: 'LATEST',
+            encoding: 'text',
+            records: [],
+            droppedRecords: 0,
+            started: false,
+            tailing: false,
+            loading: false,
+            throttledShards: 0,
+            activeShards: 0,
+            errorMsg: '',
+        }
+    },
+    methods: {
+        saveState() {
+            vscode.setState({ startingPosition: this.startingPosition, encoding: this.encoding })
+        },
+        startReading() {
+            this.saveState()
+            this.loading = true
+            vscode.postMessage({ command: 'startReading', data: { startingPosition: this.startingPosition } })
+        },
+        readRecords() {
+            this.loading = true
+            vscode.postMessage({ command: 'readRecords' })
+        },
+        toggleTail() {
+            vscode.postMessage({ command: 'setTail', data: { tail: !this.tailing } })
+        },
+        formatData(record: StreamRecord): string {
+            return this.encoding === 'base64' ? record.base64 : record.text
+        },
+    },
+    template: `
+    <div class="record-viewer">
+        <h1>{{ streamName }}</h1>
+        <div class="record-controls">
+            <label for="starting-position">Start from</label>
+            <select id="starting-position" v-model="startingPosition">
+                <option value="LATEST">New records (LATEST)</option>
+                <option value="TRIM_HORIZON">Oldest available record (TRIM_HORIZON)</option>
+            </select>
+            <button :disabled="loading" v-on:click="startReading">{{ started ? 'Restart' : 'Start reading' }}</button>
+            <button :disabled="!started || loading || tailing || activeShards === 0" v-on:click="readRecords">
+                Get records
+            </button>
+            <button :disabled="!started || activeShards === 0" v-on:click="toggleTail">
+                {{ tailing ? 'Stop tailing' : 'Tail' }}
+            </button>
+        </div>
+        <div class="record-controls">
+            Show data as
+            <label><input type="radio" value="text" v-model="encoding" v-on:change="saveState" /> UTF-8</label>
+            <label><input type="radio" value="base64" v-model="encoding" v-on:change="saveState" /> Base64</label>
+        </div>
+        <p class="error" v-if="errorMsg">{{ errorMsg }}</p>
+        <p class="record-status" v-if="started">
+            <span v-if="tailing">Tailing new records...</span>
+            <span v-if="loading">Loading...</span>
+            <span v-if="activeShards === 0">All shards have been read.</span>
+            <span v-if="throttledShards > 0">
+                Read throughput exceeded on {{ throttledShards }} shard(s); reading them again after a delay.
+            </span>
+            <span v-if="droppedRecords > 0">{{ droppedRecords }} older record(s) are no longer shown.</span>
+        </p>
+        <p v-if="started && !loading && records.length === 0">
+            No records yet.
+            <span v-if="startingPosition === 'LATEST'">Only records added after reading started are shown.</span>
+        </p>
+        <table class="stream-records" v-if="records.length > 0">
+            <thead>
+                <tr>
+                    <th>Approximate arrival time</th>
+                    <th>Partition key</th>
+                    <th>Sequence number</th>
+                    <th>Shard</th>
+                    <th>Data</th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr v-for="record in records" :key="record.shardId + record.sequenceNumber">
+                    <td>{{ record.approximateArrivalTimestamp }}</td>
+                    <td :title="record.partitionKey">{{ record.partitionKey }}</td>
+                    <td :title="record.sequenceNumber">{{ record.sequenceNumber }}</td>
+                    <td>{{ record.shardId }}</td>
+                    <td class="record-data"><pre>{{ formatData(record) }}</pre></td>
+                </tr>
+            </tbody>
+        </table>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
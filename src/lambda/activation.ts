@@ -7,18 +7,39 @@ import * as vscode from 'vscode'
 import { ext } from '../shared/extensionGlobals'
 import { deleteLambda } from './commands/deleteLambda'
 import { invokeLambda } from './commands/invokeLambda'
+import {
+    invokeAndTailLogs,
+    InvocationLogDocumentProvider,
+    LAMBDA_INVOCATION_LOGS_SCHEME,
+} from './commands/invokeAndTailLogs'
 import { uploadLambdaCommand } from './commands/uploadLambda'
 import { LambdaFunctionNode } from './explorer/lambdaFunctionNode'
 import { downloadLambdaCommand } from './commands/downloadLambda'
+import { downloadSamProjectCommand } from './commands/downloadSamProject'
+import { viewLayerVersion } from './commands/viewLayerVersion'
+import { downloadLayerVersionCommand } from './commands/downloadLayerVersion'
+import { deleteLayerVersion } from './commands/deleteLayerVersion'
+import { LambdaLayerVersionNode } from './explorer/lambdaLayerNodes'
 import { tryRemoveFolder } from '../shared/filesystemUtilities'
 import { registerSamInvokeVueCommand } from './vue/samInvoke'
+import { editConfigurationCommand } from './vue/editConfiguration'
+import { viewFunctionMetricsCommand } from './vue/functionMetrics'
 import { ExtContext } from '../shared/extensions'
+import { publishVersion } from './commands/publishVersion'
+import { createAlias, setAliasRouting, updateAlias } from './commands/editAlias'
+import {
+    LambdaAliasesNode,
+    LambdaAliasNode,
+    LambdaVersionNode,
+    LambdaVersionsNode,
+} from './explorer/lambdaQualifierNodes'
 
 /**
  * Activates Lambda components.
  */
 export async function activate(context: ExtContext): Promise<void> {
     const outputChannel = vscode.window.createOutputChannel('AWS Lambda')
+    const invocationLogs = new InvocationLogDocumentProvider()
 
     context.extensionContext.subscriptions.push(
         vscode.commands.registerCommand(
@@ -34,12 +55,21 @@ export async function activate(context: ExtContext): Promise<void> {
         ),
         vscode.commands.registerCommand(
             'aws.invokeLambda',
-            async (node: LambdaFunctionNode) =>
-                await invokeLambda({
-                    functionNode: node,
-                    outputChannel,
-                })
+            async (node: LambdaFunctionNode | LambdaVersionNode | LambdaAliasNode) =>
+                node instanceof LambdaFunctionNode
+                    ? await invokeLambda({ functionNode: node, outputChannel })
+                    : await invokeLambda({ functionNode: node.functionNode, qualifier: node.qualifier, outputChannel })
         ),
+        vscode.commands.registerCommand(
+            'aws.lambda.invokeAndTailLogs',
+            async (node: LambdaFunctionNode) => await invokeAndTailLogs(node, invocationLogs, { outputChannel })
+        ),
+        vscode.workspace.registerTextDocumentContentProvider(LAMBDA_INVOCATION_LOGS_SCHEME, invocationLogs),
+        vscode.workspace.onDidCloseTextDocument(document => {
+            if (document.uri.scheme === LAMBDA_INVOCATION_LOGS_SCHEME) {
+                invocationLogs.remove(document.uri)
+            }
+        }),
         // Capture debug finished events, and delete the base build dir if it exists
         vscode.debug.onDidTerminateDebugSession(async session => {
             // if it has a base build dir, then we remove it. We can't find out the type easily since
@@ -53,9 +83,54 @@ export async function activate(context: ExtContext): Promise<void> {
             'aws.downloadLambda',
             async (node: LambdaFunctionNode) => await downloadLambdaCommand(node)
         ),
+        vscode.commands.registerCommand(
+            'aws.lambda.downloadSamProject',
+            async (node: LambdaFunctionNode) => await downloadSamProjectCommand(node)
+        ),
         vscode.commands.registerCommand('aws.uploadLambda', async (node: LambdaFunctionNode) => {
             await uploadLambdaCommand(node)
         }),
+        vscode.commands.registerCommand(
+            'aws.lambda.editConfiguration',
+            async (node: LambdaFunctionNode) => await editConfigurationCommand(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.viewMetrics',
+            async (node: LambdaFunctionNode | LambdaVersionNode | LambdaAliasNode) =>
+                node instanceof LambdaFunctionNode
+                    ? await viewFunctionMetricsCommand(node)
+                    : await viewFunctionMetricsCommand(node.functionNode, node.qualifier)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.viewLayerVersion',
+            async (node: LambdaLayerVersionNode) => await viewLayerVersion(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.downloadLayerVersion',
+            async (node: LambdaLayerVersionNode) => await downloadLayerVersionCommand(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.deleteLayerVersion',
+            async (node: LambdaLayerVersionNode) => await deleteLayerVersion(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.publishVersion',
+            async (node: LambdaFunctionNode | LambdaVersionsNode) =>
+                await publishVersion(node instanceof LambdaVersionsNode ? node.parent : node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.createAlias',
+            async (node: LambdaFunctionNode | LambdaAliasesNode) =>
+                await createAlias(node instanceof LambdaAliasesNode ? node.parent : node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.updateAlias',
+            async (node: LambdaAliasNode) => await updateAlias(node)
+        ),
+        vscode.commands.registerCommand(
+            'aws.lambda.setAliasRouting',
+            async (node: LambdaAliasNode) => await setAliasRouting(node)
+        ),
         registerSamInvokeVueCommand(context)
     )
 }
@@ -0,0 +1,77 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as localizedText from '../../shared/localizedText'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaDeleteLayerVersion } from '../../shared/telemetry/telemetry'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { LambdaLayerVersionNode } from '../explorer/lambdaLayerNodes'
+
+/**
+ * Deletes a layer version after confirmation. Functions that use the version keep working, but it can no
+ * longer be added to functions.
+ */
+export async function deleteLayerVersion(
+    node: LambdaLayerVersionNode,
+    window = Window.vscode(),
+    commands = Commands.vscode()
+): Promise<void> {
+    getLogger().debug('DeleteLayerVersion called for %s', node.arn)
+
+    if (node.readOnly) {
+        // menus hide the action for shared layers; this guards against invoking it some other way
+        window.showErrorMessage(
+            localize(
+                'AWS.lambda.layer.delete.readOnly',
+                'Layer version {0} is shared by account {1} and cannot be deleted',
+                node.name,
+                node.accountId
+            )
+        )
+        recordLambdaDeleteLayerVersion({ result: 'Failed' })
+        return
+    }
+
+    const isConfirmed = await showConfirmationMessage(
+        {
+            prompt: localize(
+                'AWS.lambda.layer.delete.prompt',
+                'Are you sure you want to delete version {0} of layer {1}? Functions that use it are not affected, but it can no longer be added to functions.',
+                node.version,
+                node.layerName
+            ),
+            confirm: localizedText.localizedDelete,
+            cancel: localizedText.cancel,
+        },
+        window
+    )
+    if (!isConfirmed) {
+        getLogger().info('DeleteLayerVersion cancelled')
+        recordLambdaDeleteLayerVersion({ result: 'Cancelled' })
+        return
+    }
+
+    try {
+        await node.lambda.deleteLayerVersion(node.layerName, node.version)
+
+        getLogger().info(`Deleted layer version ${node.arn}`)
+        window.showInformationMessage(
+            localize('AWS.lambda.layer.delete.success', 'Deleted layer version {0}', node.name)
+        )
+        recordLambdaDeleteLayerVersion({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to delete layer version ${node.arn}: %O`, e)
+        showErrorWithLogs(
+            localize('AWS.lambda.layer.delete.error', 'Failed to delete layer version {0}', node.name),
+            window
+        )
+        recordLambdaDeleteLayerVersion({ result: 'Failed' })
+    } finally {
+        await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+    }
+}
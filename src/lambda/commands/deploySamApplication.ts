@@ -22,7 +22,8 @@ import { SettingsConfiguration } from '../../shared/settingsConfiguration'
 import { recordSamDeploy, Result } from '../../shared/telemetry/telemetry'
 import { makeCheckLogsMessage } from '../../shared/utilities/messages'
 import { addCodiconToString } from '../../shared/utilities/textUtilities'
-import { SamDeployWizardResponse, writeSavedBucket } from '../wizards/samDeployWizard'
+import { openStackEvents } from '../vue/stackEvents'
+import { SamDeployWizardResponse, writeSavedBucket, writeSavedStack } from '../wizards/samDeployWizard'
 
 const localize = nls.loadMessageBundle()
 
@@ -35,6 +36,8 @@ interface DeploySamApplicationParameters {
     ecrRepo?: string
     destinationStackName: string
     parameterOverrides: Map<string, string>
+    noEchoParameters?: Set<string>
+    tags?: Map<string, string>
 }
 
 export interface WindowFunctions {
@@ -64,6 +67,7 @@ export async function deploySamApplication(
     let deployResult: Result = 'Succeeded'
     let samVersion: string | undefined
     let deployFolder: string | undefined
+    let showStackEvents: (() => Promise<void>) | undefined
     try {
         const credentials = await awsContext.getCredentials()
         if (!credentials) {
@@ -87,16 +91,27 @@ export async function deploySamApplication(
             packageBucketName: deployWizardResponse.s3Bucket,
             ecrRepo: deployWizardResponse.ecrRepo?.repositoryUri,
             parameterOverrides: deployWizardResponse.parameterOverrides,
+            noEchoParameters: deployWizardResponse.noEchoParameters,
+            tags: deployWizardResponse.tags,
             environmentVariables: asEnvironmentVariables(credentials),
             region: deployWizardResponse.region,
             sourceTemplatePath: deployWizardResponse.template.fsPath,
         }
 
+        const deployStart = new Date()
         const deployApplicationPromise = deploy({
             deployParameters,
             invoker: samCliContext.invoker,
             window,
         })
+        showStackEvents = async () =>
+            await openStackEvents({
+                client: ext.toolkitClientBuilder.createCloudFormationClient(deployParameters.region),
+                stackName: deployParameters.destinationStackName,
+                since: deployStart,
+                deployment: deployApplicationPromise,
+                templatePath: deployParameters.sourceTemplatePath,
+            })
 
         window.setStatusBarMessage(
             addCodiconToString(
@@ -109,6 +124,17 @@ export async function deploySamApplication(
             ),
             deployApplicationPromise
         )
+        offerStackEvents(
+            window.showInformationMessage(
+                localize(
+                    'AWS.samcli.deploy.workflow.started',
+                    'Deploying SAM Application to {0}...',
+                    deployWizardResponse.stackName
+                ),
+                localizedStackEvents
+            ),
+            showStackEvents
+        )
 
         await deployApplicationPromise
         // no need to await, doesn't need to block further execution (true -> no telemetry)
@@ -121,18 +147,38 @@ export async function deploySamApplication(
         } else {
             getLogger().warn('Profile not provided; cannot write recent buckets.')
         }
+
+        // retain parameter overrides (except NoEcho values) and tags to pre-fill the next deploy to this stack
+        writeSavedStack(settings, deployWizardResponse.region, deployWizardResponse.stackName, deployWizardResponse)
     } catch (err) {
         deployResult = 'Failed'
         outputDeployError(err as Error)
-        vscode.window.showErrorMessage(
-            localize('AWS.samcli.deploy.workflow.error', 'Failed to deploy SAM application.')
-        )
+        const errorMessage = localize('AWS.samcli.deploy.workflow.error', 'Failed to deploy SAM application.')
+        if (showStackEvents) {
+            offerStackEvents(vscode.window.showErrorMessage(errorMessage, localizedStackEvents), showStackEvents)
+        } else {
+            vscode.window.showErrorMessage(errorMessage)
+        }
     } finally {
         await tryRemoveFolder(deployFolder)
         recordSamDeploy({ result: deployResult, version: samVersion })
     }
 }
 
+const localizedStackEvents = localize('AWS.samcli.deploy.viewStackEvents', 'View Stack Events')
+
+/**
+ * Opens the stack events if the "View Stack Events" item of a message is chosen.
+ * The message isn't awaited so that it doesn't block the deploy.
+ */
+function offerStackEvents(message: Thenable<string | undefined>, showStackEvents: () => Promise<void>): void {
+    message.then(async selection => {
+        if (selection === localizedStackEvents) {
+            await showStackEvents()
+        }
+    })
+}
+
 function getBuildRootFolder(deployRootFolder: string): string {
     return path.join(deployRootFolder, 'build')
 }
@@ -239,6 +285,8 @@ async function deployOperation(params: {
         await runSamCliDeploy(
             {
                 parameterOverrides: params.deployParameters.parameterOverrides,
+                noEchoParameters: params.deployParameters.noEchoParameters,
+                tags: params.deployParameters.tags,
                 environmentVariables: params.deployParameters.environmentVariables,
                 templateFile: packageTemplatePath,
                 region: params.deployParameters.region,
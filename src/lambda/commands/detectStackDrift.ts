@@ -0,0 +1,269 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { CloudFormation } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { CloudFormationClient } from '../../shared/clients/cloudFormationClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordCloudformationDetectDrift, Result } from '../../shared/telemetry/telemetry'
+import * as picker from '../../shared/ui/picker'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { CloudFormationStackNode } from '../explorer/cloudFormationNodes'
+
+export const CLOUDFORMATION_DRIFT_SCHEME = 'aws-cloudformation-drift'
+const DRIFT_POLL_INTERVAL_MS = 2000
+const DRIFT_TIMEOUT_MS = 5 * 60 * 1000
+
+export interface DriftDetectionOptions {
+    client?: CloudFormationClient
+    window?: Window
+    commands?: Commands
+    pollIntervalMs?: number
+    timeoutMs?: number
+}
+
+/**
+ * Detects drift of the resources of a stack, and annotates the stack node and its resource nodes with the results.
+ *
+ * Drift detection runs asynchronously in CloudFormation, so its status is polled until it completes, the user
+ * cancels, or it times out.
+ */
+export async function detectStackDrift(
+    node: CloudFormationStackNode,
+    {
+        client = ext.toolkitClientBuilder.createCloudFormationClient(node.regionCode),
+        window = Window.vscode(),
+        commands = Commands.vscode(),
+        pollIntervalMs = DRIFT_POLL_INTERVAL_MS,
+        timeoutMs = DRIFT_TIMEOUT_MS,
+    }: DriftDetectionOptions = {}
+): Promise<void> {
+    let result: Result = 'Succeeded'
+
+    try {
+        const status = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.cloudFormation.drift.progress', 'Detecting drift of stack {0}...', node.stackName),
+                cancellable: true,
+            },
+            async (progress, token) => {
+                const detectionId = await client.detectStackDrift(node.stackName)
+
+                return await waitForDriftDetection(client, detectionId, token, pollIntervalMs, timeoutMs)
+            }
+        )
+        if (!status) {
+            result = 'Cancelled'
+            getLogger().info('Cancelled drift detection of stack %s', node.stackName)
+
+            return
+        }
+        if (status.DetectionStatus === 'DETECTION_FAILED' && !status.StackDriftStatus) {
+            throw new Error(status.DetectionStatusReason)
+        }
+
+        const drifts = await client.describeStackResourceDrifts(node.stackName)
+        node.setDrifts(status.StackDriftStatus ?? 'UNKNOWN', drifts)
+        await commands.execute('aws.refreshAwsExplorerNode', node)
+
+        await showDriftResult(node, drifts, status, window)
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to detect drift of stack %s: %O', node.stackName, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.cloudFormation.drift.error',
+                'Failed to detect drift of stack {0}: {1}',
+                node.stackName,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordCloudformationDetectDrift({ result })
+    }
+}
+
+/**
+ * Polls the status of a drift detection until it is no longer in progress.
+ *
+ * @returns the final status, or undefined if the user cancelled
+ */
+async function waitForDriftDetection(
+    client: CloudFormationClient,
+    detectionId: string,
+    token: vscode.CancellationToken,
+    pollIntervalMs: number,
+    timeoutMs: number
+): Promise<CloudFormation.DescribeStackDriftDetectionStatusOutput | undefined> {
+    const deadline = Date.now() + timeoutMs
+
+    while (!token.isCancellationRequested) {
+        const status = await client.describeStackDriftDetectionStatus(detectionId)
+        if (status.DetectionStatus !== 'DETECTION_IN_PROGRESS') {
+            return status
+        }
+        if (Date.now() + pollIntervalMs > deadline) {
+            throw new Error(
+                localize(
+                    'AWS.cloudFormation.drift.timeout',
+                    'drift detection did not complete within {0} seconds',
+                    Math.round(timeoutMs / 1000)
+                )
+            )
+        }
+
+        await new Promise(resolve => setTimeout(resolve, pollIntervalMs))
+    }
+
+    return undefined
+}
+
+async function showDriftResult(
+    node: CloudFormationStackNode,
+    drifts: CloudFormation.StackResourceDrift[],
+    status: CloudFormation.DescribeStackDriftDetectionStatusOutput,
+    window: Window
+): Promise<void> {
+    const driftedCount = status.DriftedStackResourceCount ?? drifts.filter(isDrifted).length
+    if (driftedCount === 0) {
+        window.showInformationMessage(
+            localize('AWS.cloudFormation.drift.inSync', 'The resources of stack {0} are in sync.', node.stackName)
+        )
+
+        return
+    }
+
+    const showDifferences = localize('AWS.cloudFormation.drift.showDifferences', 'Show Differences')
+    const selection = await window.showWarningMessage(
+        localize(
+            'AWS.cloudFormation.drift.drifted',
+            '{0} resource(s) of stack {1} have drifted.',
+            driftedCount,
+            node.stackName
+        ),
+        showDifferences
+    )
+    if (selection === showDifferences) {
+        await showStackDrift(node, { window })
+    }
+}
+
+/**
+ * Lets the user pick a drifted resource of a stack, and opens a diff of its expected vs. actual properties.
+ */
+export async function showStackDrift(
+    node: CloudFormationStackNode,
+    { window = Window.vscode(), commands = Commands.vscode() }: { window?: Window; commands?: Commands } = {}
+): Promise<void> {
+    const drifts = node.drifts
+    if (!drifts) {
+        window.showInformationMessage(
+            localize(
+                'AWS.cloudFormation.drift.notDetected',
+                'Detect drift of stack {0} to see how its resources differ from the template.',
+                node.stackName
+            )
+        )
+
+        return
+    }
+
+    const drifted = drifts.filter(isDrifted)
+    if (drifted.length === 0) {
+        window.showInformationMessage(
+            localize('AWS.cloudFormation.drift.inSync', 'The resources of stack {0} are in sync.', node.stackName)
+        )
+
+        return
+    }
+
+    const drift = drifted.length === 1 ? drifted[0] : await pickDrift(drifted)
+    if (drift) {
+        await commands.execute(
+            'vscode.diff',
+            getDriftUri(node, drift, 'expected'),
+            getDriftUri(node, drift, 'actual'),
+            localize(
+                'AWS.cloudFormation.drift.diffTitle',
+                '{0}: expected ↔ actual ({1})',
+                drift.LogicalResourceId,
+                drift.StackResourceDriftStatus
+            )
+        )
+    }
+}
+
+async function pickDrift(
+    drifts: CloudFormation.StackResourceDrift[]
+): Promise<CloudFormation.StackResourceDrift | undefined> {
+    const quickPick = picker.createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.cloudFormation.drift.pickResource', 'Choose a drifted resource'),
+        },
+        items: drifts.map(drift => ({
+            label: drift.LogicalResourceId,
+            description: drift.StackResourceDriftStatus,
+            detail: (drift.PropertyDifferences ?? []).map(difference => difference.PropertyPath).join(', '),
+            drift,
+        })),
+    })
+    const choice = picker.verifySinglePickerOutput(await picker.promptUser({ picker: quickPick }))
+
+    return choice?.drift
+}
+
+function isDrifted(drift: CloudFormation.StackResourceDrift): boolean {
+    return drift.StackResourceDriftStatus === 'MODIFIED' || drift.StackResourceDriftStatus === 'DELETED'
+}
+
+/** Contents of the opened drift diffs, keyed by document URI. */
+const driftDocuments = new Map<string, string>()
+
+function getDriftUri(
+    node: CloudFormationStackNode,
+    drift: CloudFormation.StackResourceDrift,
+    side: 'expected' | 'actual'
+): vscode.Uri {
+    const uri = vscode.Uri.parse(
+        `${CLOUDFORMATION_DRIFT_SCHEME}:${drift.LogicalResourceId}.${side}.json?${encodeURIComponent(node.arn)}`
+    )
+    driftDocuments.set(
+        uri.toString(),
+        formatProperties(side === 'expected' ? drift.ExpectedProperties : drift.ActualProperties)
+    )
+
+    return uri
+}
+
+/**
+ * Serves the expected and actual properties of drifted resources, for the diffs opened by {@link showStackDrift}.
+ */
+export class StackDriftDocumentProvider implements vscode.TextDocumentContentProvider {
+    public provideTextDocumentContent(uri: vscode.Uri): string {
+        return driftDocuments.get(uri.toString()) ?? ''
+    }
+}
+
+/**
+ * Pretty-prints the JSON properties of a resource, so that the diff shows one property per line.
+ */
+export function formatProperties(properties: string | undefined): string {
+    if (!properties) {
+        return ''
+    }
+
+    try {
+        return JSON.stringify(JSON.parse(properties), undefined, 4)
+    } catch (err) {
+        return properties
+    }
+}
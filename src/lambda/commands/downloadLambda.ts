@@ -145,17 +145,30 @@ async function downloadAndUnzipLambda(
     lambda = ext.toolkitClientBuilder.createLambdaClient(functionNode.regionCode)
 ): Promise<void> {
     const functionArn = functionNode.configuration.FunctionArn!
+    const response = await lambda.getFunction(functionArn)
+
+    // arbitrary increments since there's no "busy" state for progress bars
+    progress.report({ increment: 10 })
+
+    await downloadAndUnzipCode(response.Code!.Location!, extractLocation, progress)
+}
+
+/**
+ * Downloads the deployment package of a function from the `Location` returned by `GetFunction`, and extracts it.
+ */
+export async function downloadAndUnzipCode(
+    codeLocation: string,
+    extractLocation: string,
+    progress: vscode.Progress<{
+        message?: string | undefined
+        increment?: number | undefined
+    }>
+): Promise<void> {
     let tempDir: string | undefined
     try {
         tempDir = await makeTemporaryToolkitFolder()
         const downloadLocation = path.join(tempDir, 'function.zip')
 
-        const response = await lambda.getFunction(functionArn)
-        const codeLocation = response.Code!.Location!
-
-        // arbitrary increments since there's no "busy" state for progress bars
-        progress.report({ increment: 10 })
-
         const fetcher = new HttpResourceFetcher(codeLocation, {
             pipeLocation: downloadLocation,
             showUrl: false,
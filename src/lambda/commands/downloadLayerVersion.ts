@@ -0,0 +1,94 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { ext } from '../../shared/extensionGlobals'
+import { downloadsDir } from '../../shared/filesystemUtilities'
+import { getLogger } from '../../shared/logger'
+import { HttpResourceFetcher } from '../../shared/resourcefetcher/httpResourceFetcher'
+import { recordLambdaDownloadLayerVersion } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs, showOutputMessage } from '../../shared/utilities/messages'
+import { waitUntil } from '../../shared/utilities/timeoutUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { LambdaLayerVersionNode } from '../explorer/lambdaLayerNodes'
+
+/**
+ * Downloads the content .zip of a layer version from the presigned `Location` returned by `GetLayerVersionByArn`.
+ *
+ * @param fetch Downloads the URL to the given file, resolving once the request completes.
+ */
+export async function downloadLayerVersionCommand(
+    node: LambdaLayerVersionNode,
+    window = Window.vscode(),
+    outputChannel = ext.outputChannel,
+    fetch = async (url: string, saveLocation: string) =>
+        new HttpResourceFetcher(url, {
+            pipeLocation: saveLocation,
+            showUrl: false,
+            friendlyName: 'Lambda layer .zip file',
+        }).get()
+): Promise<void> {
+    getLogger().debug('DownloadLayerVersion called for %s', node.arn)
+
+    const saveLocation = await window.showSaveDialog({
+        defaultUri: vscode.Uri.file(path.join(downloadsDir(), `${node.layerName}-${node.version}.zip`)),
+        saveLabel: localize('AWS.lambda.layer.download.saveButton', 'Download'),
+        filters: { '*.zip': ['zip'], 'All Files': ['*'] },
+    })
+    if (!saveLocation) {
+        getLogger().info('DownloadLayerVersion cancelled')
+        recordLambdaDownloadLayerVersion({ result: 'Cancelled' })
+        return
+    }
+
+    try {
+        showOutputMessage(`Downloading layer version ${node.arn} to ${saveLocation.fsPath}`, outputChannel)
+
+        await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.lambda.layer.download.progressTitle', 'Downloading {0}...', node.name),
+            },
+            async () => {
+                const content = (await node.lambda.getLayerVersion(node.arn)).Content
+                if (!content?.Location) {
+                    throw new Error(`Layer version ${node.arn} has no content location`)
+                }
+
+                if ((await fetch(content.Location, saveLocation.fsPath)) === undefined) {
+                    throw new Error(`Failed to download the content of layer version ${node.arn}`)
+                }
+
+                // The file may still be flushing to disk after the request completes.
+                await waitForFileSize(saveLocation.fsPath, content.CodeSize)
+            }
+        )
+
+        showOutputMessage(`Successfully downloaded layer version to ${saveLocation.fsPath}`, outputChannel)
+        recordLambdaDownloadLayerVersion({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to download layer version ${node.arn}: %O`, e)
+        showErrorWithLogs(
+            localize('AWS.lambda.layer.download.error', 'Failed to download layer version {0}', node.name),
+            window
+        )
+        recordLambdaDownloadLayerVersion({ result: 'Failed' })
+    }
+}
+
+async function waitForFileSize(file: string, size: number | undefined): Promise<void> {
+    if (size === undefined) {
+        return
+    }
+
+    await waitUntil(async () => (await fs.pathExists(file)) && (await fs.stat(file)).size >= size, {
+        timeout: 10000,
+        interval: 500,
+        truthy: true,
+    })
+}
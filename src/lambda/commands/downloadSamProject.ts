@@ -0,0 +1,235 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { Lambda } from 'aws-sdk'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { CloudFormation } from '../../shared/cloudformation/cloudformation'
+import { ext } from '../../shared/extensionGlobals'
+import * as localizedText from '../../shared/localizedText'
+import { getLogger } from '../../shared/logger'
+import * as telemetry from '../../shared/telemetry/telemetry'
+import { SamTemplateGenerator } from '../../shared/templates/sam/samTemplateGenerator'
+import { showConfirmationMessage } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { addFolderToWorkspace } from '../../shared/utilities/workspaceUtils'
+import { Window } from '../../shared/vscode/window'
+import { promptUserForLocation, WizardContext } from '../../shared/wizards/multiStepWizard'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+import { downloadAndUnzipCode } from './downloadLambda'
+
+export const TEMPLATE_FILE_NAME = 'template.yaml'
+export const CODE_DIRECTORY = 'src'
+
+/**
+ * Packages above this size (the limit for uploading a .zip file directly) can take a long time to download, and the
+ * `Location` URL returned by `GetFunction` is only valid for 10 minutes.
+ */
+export const LARGE_PACKAGE_BYTES = 50 * 1024 * 1024
+
+const TEMPLATE_FORMAT_VERSION = '2010-09-09'
+const SERVERLESS_TRANSFORM = 'AWS::Serverless-2016-10-31'
+
+/**
+ * Downloads a deployed function into a new folder, with a SAM template describing it, so that it can be edited
+ * and deployed again with "Deploy SAM Application".
+ *
+ * The code of .zip functions is extracted into a `src` folder; for image functions only the template is generated.
+ */
+export async function downloadSamProjectCommand(functionNode: LambdaFunctionNode): Promise<void> {
+    const result = await downloadSamProject(functionNode)
+
+    telemetry.recordLambdaDownloadSamProject({
+        result,
+        runtime: functionNode.configuration.Runtime as telemetry.Runtime | undefined,
+    })
+}
+
+async function downloadSamProject(
+    functionNode: LambdaFunctionNode,
+    window = Window.vscode(),
+    lambda = ext.toolkitClientBuilder.createLambdaClient(functionNode.regionCode)
+): Promise<telemetry.Result> {
+    const functionName = functionNode.configuration.FunctionName!
+    const isImage = functionNode.configuration.PackageType === 'Image'
+
+    const selectedUri = await promptUserForLocation(new WizardContext(), {
+        step: 1,
+        totalSteps: 1,
+        overrideText: {
+            title: localize(
+                'AWS.lambda.downloadSamProject.location',
+                'Select a folder to download {0} into',
+                functionName
+            ),
+        },
+    })
+    if (!selectedUri) {
+        return 'Cancelled'
+    }
+
+    const projectLocation = path.join(selectedUri.fsPath, functionName)
+    if (await fs.pathExists(projectLocation)) {
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.lambda.downloadSamProject.overwrite',
+                    'Folder {0} already exists. Its contents will be overwritten. Proceed with download?',
+                    projectLocation
+                ),
+                confirm: localize('AWS.lambda.download.download', 'Download'),
+                cancel: localizedText.cancel,
+            },
+            window
+        )
+        if (!isConfirmed) {
+            getLogger().info('DownloadSamProject cancelled')
+            return 'Cancelled'
+        }
+    }
+
+    const codeSize = functionNode.configuration.CodeSize ?? 0
+    if (!isImage && codeSize > LARGE_PACKAGE_BYTES) {
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.lambda.downloadSamProject.largePackage',
+                    'The deployment package of {0} is {1} MB. It may not finish downloading before the download link expires after 10 minutes. Download anyway?',
+                    functionName,
+                    Math.round(codeSize / (1024 * 1024))
+                ),
+                confirm: localize('AWS.lambda.download.download', 'Download'),
+                cancel: localizedText.cancel,
+            },
+            window
+        )
+        if (!isConfirmed) {
+            getLogger().info('DownloadSamProject cancelled')
+            return 'Cancelled'
+        }
+    }
+
+    const templatePath = path.join(projectLocation, TEMPLATE_FILE_NAME)
+    try {
+        await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                cancellable: false,
+                title: localize(
+                    'AWS.lambda.downloadSamProject.status',
+                    'Downloading Lambda function {0} into {1}...',
+                    functionName,
+                    projectLocation
+                ),
+            },
+            async progress => {
+                const response = await lambda.getFunction(functionNode.configuration.FunctionArn!)
+                progress.report({ increment: 10 })
+
+                if (!isImage) {
+                    await downloadAndUnzipCode(
+                        response.Code!.Location!,
+                        path.join(projectLocation, CODE_DIRECTORY),
+                        progress
+                    )
+                }
+                await generateSamTemplate(response, templatePath)
+            }
+        )
+    } catch (err) {
+        getLogger().error('Failed to download Lambda function %s: %O', functionName, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.lambda.download.downloadError',
+                'Error downloading Lambda function {0}: {1}',
+                functionNode.configuration.FunctionArn!,
+                (err as Error).message
+            )
+        )
+
+        return 'Failed'
+    }
+
+    try {
+        await addFolderToWorkspace({ uri: selectedUri }, true)
+        await vscode.window.showTextDocument(vscode.Uri.file(templatePath))
+    } catch (err) {
+        // not a failure since the project is downloaded
+        getLogger().warn('Failed to open downloaded SAM template %s: %O', templatePath, err)
+    }
+
+    return 'Succeeded'
+}
+
+/**
+ * Makes a valid logical ID for the template resource of a function, from its name.
+ */
+export function getLogicalId(functionName: string): string {
+    const id = functionName
+        .split(/[^A-Za-z0-9]+/)
+        .map(part => part.charAt(0).toUpperCase() + part.substring(1))
+        .join('')
+
+    return /^[A-Za-z]/.test(id) ? id : `Function${id}`
+}
+
+/**
+ * Writes a minimal SAM template for a deployed function, with its runtime, handler, memory, timeout, and
+ * environment variables.
+ */
+export async function generateSamTemplate(response: Lambda.GetFunctionResponse, templatePath: string): Promise<void> {
+    const configuration = response.Configuration!
+    const logicalId = getLogicalId(configuration.FunctionName!)
+    const generator = new SamTemplateGenerator({
+        AWSTemplateFormatVersion: TEMPLATE_FORMAT_VERSION,
+        Transform: SERVERLESS_TRANSFORM,
+    })
+
+    // TODO: use the SDK type once it includes `Architectures`
+    const architectures = (configuration as { Architectures?: CloudFormation.Architecture[] }).Architectures
+
+    if (configuration.PackageType === 'Image') {
+        const properties: CloudFormation.ImageResourceProperties = {
+            PackageType: 'Image',
+            ImageUri: response.Code?.ImageUri,
+            MemorySize: configuration.MemorySize,
+            Timeout: configuration.Timeout,
+            Architectures: architectures,
+            Environment: configuration.Environment?.Variables
+                ? { Variables: configuration.Environment.Variables }
+                : undefined,
+        }
+        if (configuration.ImageConfigResponse?.ImageConfig) {
+            properties.ImageConfig = configuration.ImageConfigResponse.ImageConfig
+        }
+        await generator
+            .withTemplateResources({
+                [logicalId]: { Type: CloudFormation.SERVERLESS_FUNCTION_TYPE, Properties: properties },
+            })
+            .generate(templatePath)
+
+        return
+    }
+
+    generator
+        .withResourceName(logicalId)
+        .withCodeUri(`${CODE_DIRECTORY}/`)
+        .withFunctionHandler(configuration.Handler!)
+        .withRuntime(configuration.Runtime!)
+    if (configuration.MemorySize !== undefined) {
+        generator.withMemorySize(configuration.MemorySize)
+    }
+    if (configuration.Timeout !== undefined) {
+        generator.withTimeout(configuration.Timeout)
+    }
+    if (architectures) {
+        generator.withArchitectures(architectures)
+    }
+    if (configuration.Environment?.Variables) {
+        generator.withEnvironment({ Variables: configuration.Environment.Variables })
+    }
+    await generator.generate(templatePath)
+}
@@ -0,0 +1,371 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { ext } from '../../shared/extensionGlobals'
+import * as localizedText from '../../shared/localizedText'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaCreateAlias, recordLambdaUpdateAlias, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+import {
+    describeAliasRouting,
+    LambdaAliasNode,
+    LATEST_VERSION,
+    listPublishedVersions,
+} from '../explorer/lambdaQualifierNodes'
+
+// alias names can't be all digits, so that they can't be mistaken for versions
+const ALIAS_NAME_REGEX = /^(?![0-9]+$)[a-zA-Z0-9-_]{1,128}$/
+
+export interface AliasOptions {
+    window?: Window
+    commands?: Commands
+    promptUserFunction?: typeof promptUser
+    lambda?: LambdaClient
+}
+
+interface VersionItem extends vscode.QuickPickItem {
+    version: string
+}
+
+/**
+ * Creates an alias of a function that points at a version.
+ */
+export async function createAlias(
+    node: LambdaFunctionNode,
+    {
+        window = Window.vscode(),
+        commands = Commands.vscode(),
+        promptUserFunction = promptUser,
+        lambda = ext.toolkitClientBuilder.createLambdaClient(node.regionCode),
+    }: AliasOptions = {}
+): Promise<void> {
+    getLogger().debug('CreateAlias called for %s', node.functionName)
+    let result: Result = 'Succeeded'
+
+    try {
+        const name = await window.showInputBox({
+            prompt: localize(
+                'AWS.lambda.createAlias.prompt',
+                'Enter the name of the new alias of {0}',
+                node.functionName
+            ),
+            ignoreFocusOut: true,
+            validateInput: input =>
+                ALIAS_NAME_REGEX.test(input)
+                    ? undefined
+                    : localize(
+                          'AWS.lambda.createAlias.invalidName',
+                          'Alias names have up to 128 letters, digits, hyphens, and underscores, and are not all digits'
+                      ),
+        })
+        if (!name) {
+            result = 'Cancelled'
+            getLogger().info('CreateAlias cancelled')
+            return
+        }
+
+        const version = await pickVersion(
+            lambda,
+            node,
+            localize('AWS.lambda.createAlias.version', 'Choose the version alias {0} points at', name),
+            { includeLatest: true, window, promptUserFunction }
+        )
+        if (!version) {
+            result = 'Cancelled'
+            getLogger().info('CreateAlias cancelled')
+            return
+        }
+
+        try {
+            await lambda.createAlias({ FunctionName: node.functionName, Name: name, FunctionVersion: version })
+            getLogger().info('Created alias %s of function %s', name, node.functionName)
+            window.showInformationMessage(
+                localize(
+                    'AWS.lambda.createAlias.success',
+                    'Created alias {0} of {1}, pointing at version {2}',
+                    name,
+                    node.functionName,
+                    version
+                )
+            )
+            await commands.execute('aws.refreshAwsExplorerNode', node)
+        } catch (err) {
+            result = 'Failed'
+            getLogger().error('Failed to create alias %s of %s: %s', name, node.functionName, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.lambda.createAlias.failure',
+                    'Failed to create alias {0} of {1}: {2}',
+                    name,
+                    node.functionName,
+                    (err as Error).message
+                ),
+                window
+            )
+        }
+    } finally {
+        recordLambdaCreateAlias({ result })
+    }
+}
+
+/**
+ * Points an alias at a version after confirmation. All of the traffic of the alias goes to that version,
+ * ending any weighted routing.
+ */
+export async function updateAlias(
+    node: LambdaAliasNode,
+    {
+        window = Window.vscode(),
+        commands = Commands.vscode(),
+        promptUserFunction = promptUser,
+        lambda = ext.toolkitClientBuilder.createLambdaClient(node.functionNode.regionCode),
+    }: AliasOptions = {}
+): Promise<void> {
+    getLogger().debug('UpdateAlias called for %s', node.alias.AliasArn)
+    const functionName = node.functionNode.functionName
+    let result: Result = 'Succeeded'
+
+    try {
+        const version = await pickVersion(
+            lambda,
+            node.functionNode,
+            localize('AWS.lambda.updateAlias.version', 'Choose the version alias {0} points at', node.qualifier),
+            { includeLatest: true, window, promptUserFunction }
+        )
+        if (!version) {
+            result = 'Cancelled'
+            getLogger().info('UpdateAlias cancelled')
+            return
+        }
+
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.lambda.updateAlias.confirm',
+                    'Point alias {0} of {1} at version {2}? All of its traffic goes to that version; it currently goes to {3}.',
+                    node.qualifier,
+                    functionName,
+                    version,
+                    describeAliasRouting(node.alias)
+                ),
+                confirm: localize('AWS.lambda.updateAlias.confirmButton', 'Update Alias'),
+                cancel: localizedText.cancel,
+            },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('UpdateAlias cancelled')
+            return
+        }
+
+        result = await updateAliasConfiguration(node, lambda, version, {}, window, commands)
+    } finally {
+        recordLambdaUpdateAlias({ result })
+    }
+}
+
+/**
+ * Routes a percentage of the traffic of an alias to a second version after confirmation, e.g. to test a
+ * canary version. The rest of the traffic goes to the version the alias points at.
+ */
+export async function setAliasRouting(
+    node: LambdaAliasNode,
+    {
+        window = Window.vscode(),
+        commands = Commands.vscode(),
+        promptUserFunction = promptUser,
+        lambda = ext.toolkitClientBuilder.createLambdaClient(node.functionNode.regionCode),
+    }: AliasOptions = {}
+): Promise<void> {
+    getLogger().debug('SetAliasRouting called for %s', node.alias.AliasArn)
+    const primaryVersion = node.alias.FunctionVersion ?? ''
+    let result: Result = 'Succeeded'
+
+    try {
+        if (primaryVersion === LATEST_VERSION) {
+            result = 'Failed'
+            window.showErrorMessage(
+                localize(
+                    'AWS.lambda.setAliasRouting.latest',
+                    'Alias {0} points at {1}. Point it at a published version to route traffic between versions.',
+                    node.qualifier,
+                    LATEST_VERSION
+                )
+            )
+            return
+        }
+
+        const version = await pickVersion(
+            lambda,
+            node.functionNode,
+            localize(
+                'AWS.lambda.setAliasRouting.version',
+                'Choose the version to route some of the traffic of alias {0} to',
+                node.qualifier
+            ),
+            { exclude: primaryVersion, window, promptUserFunction }
+        )
+        if (!version) {
+            result = 'Cancelled'
+            getLogger().info('SetAliasRouting cancelled')
+            return
+        }
+
+        const currentWeight = node.alias.RoutingConfig?.AdditionalVersionWeights?.[version]
+        const percentage = await window.showInputBox({
+            prompt: localize(
+                'AWS.lambda.setAliasRouting.weight',
+                'Enter the percentage of the traffic of alias {0} to route to version {1}',
+                node.qualifier,
+                version
+            ),
+            value: currentWeight !== undefined ? String(currentWeight * 100) : '10',
+            ignoreFocusOut: true,
+            validateInput: input => {
+                const value = Number(input)
+
+                return input.trim() && value >= 0 && value <= 100
+                    ? undefined
+                    : localize('AWS.lambda.setAliasRouting.invalidWeight', 'Enter a percentage from 0 to 100')
+            },
+        })
+        if (percentage === undefined) {
+            result = 'Cancelled'
+            getLogger().info('SetAliasRouting cancelled')
+            return
+        }
+
+        // weights have at most 5 decimal places, so percentages have at most 3
+        const percent = Math.round(Number(percentage) * 1000) / 1000
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.lambda.setAliasRouting.confirm',
+                    'Route {0}% of the traffic of alias {1} to version {2}, and the rest to version {3}?',
+                    percent,
+                    node.qualifier,
+                    version,
+                    primaryVersion
+                ),
+                confirm: localize('AWS.lambda.setAliasRouting.confirmButton', 'Update Routing'),
+                cancel: localizedText.cancel,
+            },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('SetAliasRouting cancelled')
+            return
+        }
+
+        result = await updateAliasConfiguration(
+            node,
+            lambda,
+            primaryVersion,
+            { [version]: percent / 100 },
+            window,
+            commands
+        )
+    } finally {
+        recordLambdaUpdateAlias({ result })
+    }
+}
+
+async function updateAliasConfiguration(
+    node: LambdaAliasNode,
+    lambda: LambdaClient,
+    version: string,
+    additionalVersionWeights: { [version: string]: number },
+    window: Window,
+    commands: Commands
+): Promise<Result> {
+    const functionName = node.functionNode.functionName
+    try {
+        const alias = await lambda.updateAlias({
+            FunctionName: functionName,
+            Name: node.qualifier,
+            FunctionVersion: version,
+            // an empty map removes any weighted routing
+            RoutingConfig: { AdditionalVersionWeights: additionalVersionWeights },
+        })
+        getLogger().info('Updated alias %s of function %s', node.qualifier, functionName)
+        window.showInformationMessage(
+            localize(
+                'AWS.lambda.updateAlias.success',
+                'Alias {0} of {1} now routes to {2}',
+                node.qualifier,
+                functionName,
+                describeAliasRouting(alias)
+            )
+        )
+        await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+
+        return 'Succeeded'
+    } catch (err) {
+        getLogger().error('Failed to update alias %s of %s: %s', node.qualifier, functionName, (err as Error).message)
+        showErrorWithLogs(
+            localize(
+                'AWS.lambda.updateAlias.failure',
+                'Failed to update alias {0} of {1}: {2}',
+                node.qualifier,
+                functionName,
+                (err as Error).message
+            ),
+            window
+        )
+
+        return 'Failed'
+    }
+}
+
+/**
+ * Picks a version of a function, newest first.
+ */
+async function pickVersion(
+    lambda: LambdaClient,
+    node: LambdaFunctionNode,
+    title: string,
+    {
+        includeLatest = false,
+        exclude,
+        window,
+        promptUserFunction,
+    }: { includeLatest?: boolean; exclude?: string; window: Window; promptUserFunction: typeof promptUser }
+): Promise<string | undefined> {
+    const versions = await listPublishedVersions(lambda, node.functionName)
+    const items: VersionItem[] = versions
+        .filter(version => version.Version !== exclude)
+        .sort((a, b) => Number(b.Version) - Number(a.Version))
+        .map(version => ({
+            label: localize('AWS.explorerNode.lambda.version', 'Version {0}', version.Version),
+            description: version.Description,
+            version: version.Version ?? '',
+        }))
+    if (includeLatest) {
+        items.unshift({ label: LATEST_VERSION, version: LATEST_VERSION })
+    }
+    if (items.length === 0) {
+        window.showErrorMessage(
+            localize(
+                'AWS.lambda.noOtherVersions',
+                'Function {0} has no other published versions. Publish a version first.',
+                node.functionName
+            )
+        )
+        return undefined
+    }
+
+    const picker = createQuickPick<VersionItem>({ options: { ignoreFocusOut: true, title }, items })
+
+    return verifySinglePickerOutput(await promptUserFunction({ picker }))?.version
+}
@@ -0,0 +1,237 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import * as vscode from 'vscode'
+import { CloudWatchLogsClient } from '../../shared/clients/cloudWatchLogsClient'
+import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaInvokeAndTailLogs, Result } from '../../shared/telemetry/telemetry'
+import { Window } from '../../shared/vscode/window'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+import { getRequestId, InvocationLogTail } from '../invocationLogTail'
+import { InvokePayloadStore } from '../invokePayloadStore'
+import { LogTailPoller } from '../../cloudWatchLogs/logTailPoller'
+import { getLambdaLogGroupName } from './viewLambdaLogs'
+
+export const LAMBDA_INVOCATION_LOGS_SCHEME = 'aws-lambda-invocation-logs'
+/** How often the log group is polled while tailing. */
+export const INVOCATION_LOG_POLL_INTERVAL_MILLIS = 1000
+/** Logs are delivered asynchronously, so polling continues this long after the invocation has logged its end. */
+export const INVOCATION_LOG_GRACE_PERIOD_MILLIS = 3000
+/** Stop waiting for the invocation's logs this long after the invoke returned. */
+export const INVOCATION_LOG_TIMEOUT_MILLIS = 60000
+
+/**
+ * Serves the logs collected by {@link invokeAndTailLogs}, while the documents are open.
+ */
+export class InvocationLogDocumentProvider implements vscode.TextDocumentContentProvider {
+    private readonly tails = new Map<string, InvocationLogTail>()
+    private readonly _onDidChange = new vscode.EventEmitter<vscode.Uri>()
+    private count = 0
+
+    public get onDidChange(): vscode.Event<vscode.Uri> {
+        return this._onDidChange.event
+    }
+
+    public add(functionName: string, tail: InvocationLogTail): vscode.Uri {
+        const uri = vscode.Uri.parse(`${LAMBDA_INVOCATION_LOGS_SCHEME}:${functionName}.log?${++this.count}`)
+        this.tails.set(uri.toString(), tail)
+
+        return uri
+    }
+
+    public remove(uri: vscode.Uri): void {
+        this.tails.delete(uri.toString())
+    }
+
+    public refresh(uri: vscode.Uri): void {
+        this._onDidChange.fire(uri)
+    }
+
+    public provideTextDocumentContent(uri: vscode.Uri): string {
+        return this.tails.get(uri.toString())?.getContent() ?? ''
+    }
+}
+
+export interface InvokeAndTailLogsOptions {
+    lambdaClient?: LambdaClient
+    logsClient?: CloudWatchLogsClient
+    window?: Window
+    outputChannel?: vscode.OutputChannel
+    payloadStore?: InvokePayloadStore
+    /** The account of the credentials in use, so saved payloads are not offered in other accounts. */
+    accountId?: string
+    pollIntervalMillis?: number
+    gracePeriodMillis?: number
+    timeoutMillis?: number
+    showLogs?(uri: vscode.Uri): Promise<vscode.TextEditor | undefined>
+}
+
+/**
+ * Invokes a Lambda function while following its log group, then scrolls to the logs of the invocation (matched
+ * by its request ID). Tailing stops shortly after the invocation ends, or when the logs are closed.
+ */
+export async function invokeAndTailLogs(
+    node: LambdaFunctionNode,
+    provider: InvocationLogDocumentProvider,
+    {
+        lambdaClient = ext.toolkitClientBuilder.createLambdaClient(node.regionCode),
+        logsClient = ext.toolkitClientBuilder.createCloudWatchLogsClient(node.regionCode),
+        window = Window.vscode(),
+        outputChannel = ext.outputChannel,
+        payloadStore = new InvokePayloadStore(ext.context.globalState),
+        accountId = ext.awsContext.getCredentialAccountId() ?? '',
+        pollIntervalMillis = INVOCATION_LOG_POLL_INTERVAL_MILLIS,
+        gracePeriodMillis = INVOCATION_LOG_GRACE_PERIOD_MILLIS,
+        timeoutMillis = INVOCATION_LOG_TIMEOUT_MILLIS,
+        showLogs = showLogsDocument,
+    }: InvokeAndTailLogsOptions = {}
+): Promise<void> {
+    // the input box only takes JSON, and the last payload may be text or binary from the invoke form
+    const isJson = (payloadStore.get(accountId, node.arn)?.payloadType ?? 'json') === 'json'
+    const payload = await window.showInputBox({
+        prompt: localize('AWS.lambda.invokeAndTailLogs.payload', 'Enter the JSON payload for {0}', node.name),
+        value: (isJson ? await payloadStore.load(accountId, node.arn) : undefined) ?? '{}',
+        ignoreFocusOut: true,
+        validateInput: validatePayload,
+    })
+    if (payload === undefined) {
+        recordLambdaInvokeAndTailLogs({ result: 'Cancelled' })
+        return
+    }
+    await payloadStore.set(accountId, node.arn, { json: payload })
+
+    const tail = new InvocationLogTail(logsClient, getLambdaLogGroupName(node.functionName), Date.now())
+    const uri = provider.add(node.functionName, tail)
+    const editor = await showLogs(uri)
+
+    let revealedRequest = false
+    const reveal = (): void => {
+        if (!editor || revealedRequest) {
+            return
+        }
+        const requestLine = tail.getRequestStartLine()
+        if (requestLine !== undefined) {
+            revealedRequest = true
+            editor.revealRange(new vscode.Range(requestLine, 0, requestLine, 0), vscode.TextEditorRevealType.AtTop)
+        } else {
+            // Keep the newest logs in view until the invocation's logs arrive
+            const lastLine = editor.document.lineCount - 1
+            editor.revealRange(new vscode.Range(lastLine, 0, lastLine, 0))
+        }
+    }
+
+    let invocationEnd: number | undefined
+    let requestCompleteTime: number | undefined
+    // Without a request ID, the end of the invocation's logs cannot be found
+    let waitMillis = timeoutMillis
+    const isDone = (now: number): boolean => {
+        if (invocationEnd === undefined) {
+            return false
+        }
+        if (tail.isRequestComplete()) {
+            requestCompleteTime = requestCompleteTime ?? now
+            return now - requestCompleteTime >= gracePeriodMillis
+        }
+        return now - invocationEnd >= waitMillis
+    }
+    const poller = new LogTailPoller(
+        async () => {
+            if (isDone(Date.now())) {
+                poller.stop()
+            } else if ((await tail.poll()) && !poller.stopped) {
+                provider.refresh(uri)
+            }
+        },
+        {
+            pollIntervalMillis,
+            // Failed polls are retried, so that a transient error does not lose the invocation's logs
+            onFailure: error => getLogger().warn(`Failed to get logs of ${tail.logGroupName}: %O`, error),
+        }
+    )
+    const listeners = [
+        vscode.workspace.onDidChangeTextDocument(event => {
+            if (event.document.uri.toString() === uri.toString()) {
+                reveal()
+            }
+        }),
+        vscode.workspace.onDidCloseTextDocument(document => {
+            if (document.uri.toString() === uri.toString()) {
+                poller.stop()
+            }
+        }),
+    ]
+
+    poller.start()
+
+    let result: Result = 'Succeeded'
+    try {
+        const response = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.lambda.invokeAndTailLogs.progress', 'Invoking {0}...', node.name),
+            },
+            async () => lambdaClient.invoke(node.arn, payload)
+        )
+
+        const requestId = getRequestId(response)
+        if (requestId) {
+            tail.setRequestId(requestId)
+        } else {
+            waitMillis = gracePeriodMillis
+        }
+
+        outputChannel.appendLine(`Invocation result for ${node.arn} (request ID: ${requestId ?? 'unknown'})`)
+        if (response.FunctionError) {
+            result = 'Failed'
+            outputChannel.appendLine(`Function error: ${response.FunctionError}`)
+        }
+        outputChannel.appendLine('Payload:')
+        outputChannel.appendLine(response.Payload?.toString() ?? '')
+        outputChannel.appendLine('')
+        outputChannel.show(true)
+    } catch (err) {
+        result = 'Failed'
+        waitMillis = gracePeriodMillis
+        getLogger().error(`Failed to invoke Lambda function ${node.name}: %O`, err as Error)
+        window.showErrorMessage(
+            localize(
+                'AWS.lambda.invokeAndTailLogs.error',
+                'Failed to invoke {0}: {1}',
+                node.name,
+                (err as Error).message
+            )
+        )
+    } finally {
+        invocationEnd = Date.now()
+    }
+
+    try {
+        await poller.done
+    } finally {
+        listeners.forEach(listener => listener.dispose())
+        recordLambdaInvokeAndTailLogs({ result })
+    }
+}
+
+function validatePayload(payload: string): string | undefined {
+    try {
+        JSON.parse(payload)
+        return undefined
+    } catch (err) {
+        return localize('AWS.lambda.invokeAndTailLogs.invalidPayload', 'Payload must be valid JSON')
+    }
+}
+
+async function showLogsDocument(uri: vscode.Uri): Promise<vscode.TextEditor> {
+    const document = await vscode.workspace.openTextDocument(uri)
+    await vscode.languages.setTextDocumentLanguage(document, 'log')
+
+    return await vscode.window.showTextDocument(document, { preview: false })
+}
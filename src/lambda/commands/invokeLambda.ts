@@ -4,23 +4,30 @@
  */
 
 import { _Blob } from 'aws-sdk/clients/lambda'
+import * as fs from 'fs-extra'
 import _ = require('lodash')
 import * as vscode from 'vscode'
 import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { SchemaClient } from '../../shared/clients/schemaClient'
 import { ext } from '../../shared/extensionGlobals'
 import { ExtensionUtilities } from '../../shared/extensionUtilities'
 import { getLogger, Logger } from '../../shared/logger'
-import { HttpResourceFetcher } from '../../shared/resourcefetcher/httpResourceFetcher'
 import { recordLambdaInvokeRemote, Result, Runtime } from '../../shared/telemetry/telemetry'
 import { BaseTemplates } from '../../shared/templates/baseTemplates'
-import { sampleRequestPath } from '../constants'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
 import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+import { formatResponsePayload, InvokePayloadType, toInvokePayload } from '../invokePayload'
+import { InvokePayloadStore } from '../invokePayloadStore'
+import { generatePayload, getPayloadSources, parseSchemaDocument } from '../schemaPayload'
 import { LambdaTemplates } from '../templates/lambdaTemplates'
-import { getSampleLambdaPayloads } from '../utils'
+import { getSampleLambdaPayload, getSampleLambdaPayloads } from '../utils'
 
 interface CommandMessage {
     command: string
-    value?: _Blob
+    value?: string
+    filePath?: string
+    payloadType?: InvokePayloadType
 }
 
 export async function invokeLambda(params: {
@@ -32,15 +39,21 @@ export async function invokeLambda(params: {
      */
     outputChannel: vscode.OutputChannel
     functionNode: LambdaFunctionNode
+    /** Version or alias to invoke, instead of `$LATEST`. */
+    qualifier?: string
 }) {
     const logger: Logger = getLogger()
     const functionNode = params.functionNode
+    const qualifier = params.qualifier
+    const functionName = qualifier
+        ? `${functionNode.configuration.FunctionName}:${qualifier}`
+        : functionNode.configuration.FunctionName
     let invokeResult: Result = 'Succeeded'
 
     try {
         const view = vscode.window.createWebviewPanel(
             'html',
-            `Invoked ${functionNode.configuration.FunctionName}`,
+            `Invoked ${functionName}`,
             vscode.ViewColumn.One,
             {
                 enableScripts: true,
@@ -68,8 +81,10 @@ export async function invokeLambda(params: {
             view.webview.html = baseTemplateFn({
                 cspSource: view.webview.cspSource,
                 content: invokeTemplateFn({
-                    FunctionName: functionNode.configuration.FunctionName,
-                    FunctionArn: functionNode.configuration.FunctionArn,
+                    FunctionName: functionName,
+                    FunctionArn: qualifier
+                        ? `${functionNode.configuration.FunctionArn}:${qualifier}`
+                        : functionNode.configuration.FunctionArn,
                     FunctionRegion: functionNode.regionCode,
                     InputSamples: inputs,
                     Scripts: loadScripts,
@@ -80,7 +95,10 @@ export async function invokeLambda(params: {
             view.webview.onDidReceiveMessage(
                 createMessageReceivedFunc({
                     fn: functionNode,
+                    qualifier,
                     outputChannel: params.outputChannel,
+                    payloadStore: new InvokePayloadStore(ext.context.globalState),
+                    accountId: ext.awsContext.getCredentialAccountId() ?? '',
                     onPostMessage: message => view.webview.postMessage(message),
                 }),
                 undefined,
@@ -103,23 +121,133 @@ export async function invokeLambda(params: {
 
 function createMessageReceivedFunc({
     fn,
+    qualifier,
     outputChannel,
+    payloadStore,
+    accountId,
     ...restParams
 }: {
     // TODO: Consider passing lambdaClient: LambdaClient
     fn: LambdaFunctionNode // TODO: Replace w/ invokeParams: {functionArn: string} // or Lambda.Types.InvocationRequest
+    qualifier?: string
     outputChannel: vscode.OutputChannel
+    payloadStore: InvokePayloadStore
+    /** The account of the credentials in use, so saved payloads are not offered in other accounts. */
+    accountId: string
     onPostMessage(message: any): Thenable<boolean>
 }) {
     const logger: Logger = getLogger()
+    // saved payloads are shared by all versions and aliases of the function
+    const functionArn = fn.configuration.FunctionArn ?? ''
+    const invokedArn = qualifier ? `${functionArn}:${qualifier}` : functionArn
+
+    const invoke = async (payloadType: InvokePayloadType, value: string, filePath?: string) => {
+        logger.info(`invoking lambda function with the following ${payloadType} payload:`)
+        logger.info(payloadType === 'file' ? `file ${filePath}` : value)
+
+        outputChannel.show()
+        outputChannel.appendLine('Loading response...')
+
+        try {
+            if (!fn.configuration.FunctionArn) {
+                throw new Error(`Could not determine ARN for function ${fn.configuration.FunctionName}`)
+            }
+            // non-JSON payloads are sent as they are, without JSON-quoting
+            const payload: _Blob = await toInvokePayload(payloadType, payloadType === 'file' ? filePath ?? '' : value)
+            await payloadStore.set(accountId, functionArn, {
+                json: payloadType === 'file' ? '' : value,
+                filePath,
+                payloadType,
+            })
+
+            const client: LambdaClient = ext.toolkitClientBuilder.createLambdaClient(fn.regionCode)
+            const funcResponse = await client.invoke(fn.configuration.FunctionArn, payload, qualifier)
+            const logs = funcResponse.LogResult ? Buffer.from(funcResponse.LogResult, 'base64').toString() : ''
+            const responsePayload = formatResponsePayload(funcResponse.Payload ?? JSON.stringify({}))
+
+            outputChannel.appendLine(`Invocation result for ${invokedArn}`)
+            outputChannel.appendLine('Logs:')
+            outputChannel.appendLine(logs)
+            outputChannel.appendLine('')
+            if (responsePayload.binary) {
+                outputChannel.appendLine(`Payload (binary, ${responsePayload.sizeBytes} bytes, base64):`)
+                outputChannel.appendLine(responsePayload.base64)
+            } else {
+                outputChannel.appendLine('Payload:')
+                outputChannel.appendLine(responsePayload.text ?? '')
+            }
+            outputChannel.appendLine('')
+            restParams.onPostMessage({
+                command: 'invokedLambda',
+                payload: responsePayload,
+                statusCode: funcResponse.StatusCode,
+                logs,
+            })
+        } catch (e) {
+            const error = e as Error
+            outputChannel.appendLine(`There was an error invoking ${invokedArn}`)
+            outputChannel.appendLine(error.toString())
+            outputChannel.appendLine('')
+            restParams.onPostMessage({ command: 'invokedLambda', error: error.message })
+        }
+    }
+
+    const loadGeneratedPayload = async (schemaText: string, schemaName: string) => {
+        try {
+            const document = parseSchemaDocument(schemaText)
+            const sources = getPayloadSources(document)
+            if (sources.length === 0) {
+                vscode.window.showErrorMessage(
+                    localize(
+                        'AWS.lambda.invoke.schema.noRequestBodies',
+                        'No request bodies or schemas found in {0}',
+                        schemaName
+                    )
+                )
+                return
+            }
+            const source =
+                sources.length === 1
+                    ? sources[0]
+                    : await vscode.window.showQuickPick(sources, {
+                          placeHolder: localize(
+                              'AWS.lambda.invoke.schema.pickSource',
+                              'Select the request body or schema to generate a payload from'
+                          ),
+                          ignoreFocusOut: true,
+                      })
+            if (!source) {
+                return
+            }
+
+            const generated = generatePayload(document, source)
+            restParams.onPostMessage({
+                command: 'loadedSample',
+                sample: JSON.stringify(generated.payload, undefined, 4),
+                payloadType: 'json',
+            })
+            if (generated.warnings.length > 0) {
+                logger.warn(`Generated payload from ${schemaName} with warnings: %O`, generated.warnings)
+                vscode.window.showWarningMessage(
+                    localize(
+                        'AWS.lambda.invoke.schema.warnings',
+                        'Generated the payload from {0} with warnings: {1}',
+                        schemaName,
+                        generated.warnings.join('; ')
+                    )
+                )
+            }
+        } catch (err) {
+            logger.error(`Failed to generate payload from ${schemaName}: %O`, err as Error)
+            vscode.window.showErrorMessage((err as Error).message)
+        }
+    }
 
     return async (message: CommandMessage) => {
         switch (message.command) {
             case 'sampleRequestSelected': {
                 logger.info(`Requesting ${message.value}`)
-                const sampleUrl = `${sampleRequestPath}${message.value}`
-
-                const sample = (await new HttpResourceFetcher(sampleUrl, { showUrl: true }).get()) ?? ''
+                const sample = await getSampleLambdaPayload(String(message.value))
 
                 logger.debug(`Retrieved: ${sample}`)
 
@@ -127,37 +255,140 @@ function createMessageReceivedFunc({
 
                 return
             }
-            case 'invokeLambda':
-                logger.info('invoking lambda function with the following payload:')
-                logger.info(String(message.value))
+            case 'initialize':
+                restParams.onPostMessage({
+                    command: 'lastPayloadAvailable',
+                    available: payloadStore.get(accountId, functionArn) !== undefined,
+                })
+
+                return
+            case 'promptForFile': {
+                const fileLocations = await vscode.window.showOpenDialog({ openLabel: 'Open' })
+                if (!fileLocations || fileLocations.length === 0) {
+                    return
+                }
+                const selectedFile = fileLocations[0].fsPath
+                if (message.payloadType === 'file') {
+                    // the file is read when invoking, so binary files aren't decoded as text
+                    restParams.onPostMessage({ command: 'loadedPayloadFile', selectedFile })
+                    return
+                }
+                try {
+                    const sample = await fs.readFile(selectedFile, 'utf8')
+                    restParams.onPostMessage({ command: 'loadedFile', sample, selectedFile })
+                } catch (err) {
+                    logger.error(`Failed to read payload file ${selectedFile}: %O`, err as Error)
+                }
 
-                outputChannel.show()
-                outputChannel.appendLine('Loading response...')
+                return
+            }
+            case 'generateFromSchemaFile': {
+                const fileLocations = await vscode.window.showOpenDialog({
+                    openLabel: 'Open',
+                    filters: { 'OpenAPI or JSON Schema': ['json', 'yaml', 'yml'] },
+                })
+                if (!fileLocations || fileLocations.length === 0) {
+                    return
+                }
+                const schemaFile = fileLocations[0].fsPath
+                try {
+                    await loadGeneratedPayload(await fs.readFile(schemaFile, 'utf8'), schemaFile)
+                } catch (err) {
+                    logger.error(`Failed to read schema file ${schemaFile}: %O`, err as Error)
+                }
 
+                return
+            }
+            case 'generateFromRegistrySchema': {
                 try {
-                    if (!fn.configuration.FunctionArn) {
-                        throw new Error(`Could not determine ARN for function ${fn.configuration.FunctionName}`)
+                    const schemaClient = ext.toolkitClientBuilder.createSchemaClient(fn.regionCode)
+                    const schema = await pickRegistrySchema(schemaClient)
+                    if (!schema) {
+                        return
                     }
-                    const client: LambdaClient = ext.toolkitClientBuilder.createLambdaClient(fn.regionCode)
-                    const funcResponse = await client.invoke(fn.configuration.FunctionArn, message.value)
-                    const logs = funcResponse.LogResult ? Buffer.from(funcResponse.LogResult, 'base64').toString() : ''
-                    const payload = funcResponse.Payload ? funcResponse.Payload : JSON.stringify({})
-
-                    outputChannel.appendLine(`Invocation result for ${fn.configuration.FunctionArn}`)
-                    outputChannel.appendLine('Logs:')
-                    outputChannel.appendLine(logs)
-                    outputChannel.appendLine('')
-                    outputChannel.appendLine('Payload:')
-                    outputChannel.appendLine(payload.toString())
-                    outputChannel.appendLine('')
-                } catch (e) {
-                    const error = e as Error
-                    outputChannel.appendLine(`There was an error invoking ${fn.configuration.FunctionArn}`)
-                    outputChannel.appendLine(error.toString())
-                    outputChannel.appendLine('')
+                    const response = await schemaClient.describeSchema(schema.registryName, schema.schemaName)
+                    await loadGeneratedPayload(response.Content ?? '', schema.schemaName)
+                } catch (err) {
+                    logger.error('Failed to load schema from the schema registry: %O', err as Error)
+                    vscode.window.showErrorMessage(
+                        localize(
+                            'AWS.lambda.invoke.schema.registryError',
+                            'Could not load the schema from the schema registry: {0}',
+                            (err as Error).message
+                        )
+                    )
+                }
+
+                return
+            }
+            case 'retryLastPayload': {
+                const saved = payloadStore.get(accountId, functionArn)
+                const payloadType = saved?.payloadType ?? 'json'
+                const filePath = saved?.filePath && (await fs.pathExists(saved.filePath)) ? saved.filePath : undefined
+                if (payloadType === 'file') {
+                    if (!filePath) {
+                        restParams.onPostMessage({ command: 'lastPayloadAvailable', available: false })
+                        return
+                    }
+                    restParams.onPostMessage({ command: 'loadedPayloadFile', selectedFile: filePath })
+                    await invoke(payloadType, '', filePath)
+
+                    return
                 }
 
+                const payload = await payloadStore.load(accountId, functionArn)
+                if (payload === undefined) {
+                    restParams.onPostMessage({ command: 'lastPayloadAvailable', available: false })
+                    return
+                }
+                if (filePath) {
+                    restParams.onPostMessage({
+                        command: 'loadedFile',
+                        sample: payload,
+                        selectedFile: filePath,
+                        payloadType,
+                    })
+                } else {
+                    restParams.onPostMessage({ command: 'loadedSample', sample: payload, payloadType })
+                }
+                await invoke(payloadType, payload, filePath)
+
+                return
+            }
+            case 'invokeLambda':
+                await invoke(message.payloadType ?? 'json', message.value ?? '', message.filePath)
+
                 return
         }
     }
 }
+
+/**
+ * Prompts for a registry in the region of the function, then for a schema in it.
+ */
+async function pickRegistrySchema(
+    schemaClient: SchemaClient
+): Promise<{ registryName: string; schemaName: string } | undefined> {
+    const registries = await toArrayAsync(schemaClient.listRegistries())
+    const registryName = await vscode.window.showQuickPick(
+        registries.map(registry => registry.RegistryName ?? '').filter(name => name),
+        {
+            placeHolder: localize('AWS.lambda.invoke.schema.pickRegistry', 'Select a schema registry'),
+            ignoreFocusOut: true,
+        }
+    )
+    if (!registryName) {
+        return undefined
+    }
+
+    const schemas = await toArrayAsync(schemaClient.listSchemas(registryName))
+    const schemaName = await vscode.window.showQuickPick(
+        schemas.map(schema => schema.SchemaName ?? '').filter(name => name),
+        {
+            placeHolder: localize('AWS.lambda.invoke.schema.pickSchema', 'Select a schema from {0}', registryName),
+            ignoreFocusOut: true,
+        }
+    )
+
+    return schemaName ? { registryName, schemaName } : undefined
+}
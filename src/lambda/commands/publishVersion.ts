@@ -0,0 +1,70 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaPublishVersion, Result } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+
+/**
+ * Publishes a version from the current code and configuration of a function, with an optional description.
+ */
+export async function publishVersion(
+    node: LambdaFunctionNode,
+    window = Window.vscode(),
+    commands = Commands.vscode(),
+    lambda = ext.toolkitClientBuilder.createLambdaClient(node.regionCode)
+): Promise<void> {
+    getLogger().debug('PublishVersion called for %s', node.functionName)
+    let result: Result = 'Succeeded'
+
+    try {
+        const description = await window.showInputBox({
+            prompt: localize(
+                'AWS.lambda.publishVersion.prompt',
+                'Enter a description of the new version of {0} (optional)',
+                node.functionName
+            ),
+            ignoreFocusOut: true,
+        })
+        if (description === undefined) {
+            result = 'Cancelled'
+            getLogger().info('PublishVersion cancelled')
+            return
+        }
+
+        try {
+            const version = await lambda.publishVersion(node.functionName, description || undefined)
+            getLogger().info('Published version %s of function %s', version.Version, node.functionName)
+            window.showInformationMessage(
+                localize(
+                    'AWS.lambda.publishVersion.success',
+                    'Published version {0} of {1}',
+                    version.Version,
+                    node.functionName
+                )
+            )
+            await commands.execute('aws.refreshAwsExplorerNode', node)
+        } catch (err) {
+            result = 'Failed'
+            getLogger().error('Failed to publish a version of %s: %s', node.functionName, (err as Error).message)
+            showErrorWithLogs(
+                localize(
+                    'AWS.lambda.publishVersion.failure',
+                    'Failed to publish a version of {0}: {1}',
+                    node.functionName,
+                    (err as Error).message
+                ),
+                window
+            )
+        }
+    } finally {
+        recordLambdaPublishVersion({ result })
+    }
+}
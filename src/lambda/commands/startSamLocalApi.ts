@@ -0,0 +1,663 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as child_process from 'child_process'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import * as nls from 'vscode-nls'
+
+import { asEnvironmentVariables } from '../../credentials/credentialsUtilities'
+import { AwsContext } from '../../shared/awsContext'
+import { CloudFormation } from '../../shared/cloudformation/cloudformation'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { buildSamCliStartApiArguments } from '../../shared/sam/cli/samCliStartApi'
+import { WAIT_FOR_DEBUGGER_MESSAGES } from '../../shared/sam/cli/samCliLocalInvoke'
+import { attachDebugger } from '../../shared/sam/localLambdaRunner'
+import { recordSamStartApi, Result } from '../../shared/telemetry/telemetry'
+import * as picker from '../../shared/ui/picker'
+import { ChildProcess } from '../../shared/utilities/childProcess'
+import { getStartPort } from '../../shared/utilities/debuggerUtils'
+import { getLocalRootVariants } from '../../shared/utilities/pathUtils'
+import { removeAnsi } from '../../shared/utilities/textUtilities'
+import { waitUntil } from '../../shared/utilities/timeoutUtils'
+import { Window } from '../../shared/vscode/window'
+import { getFamily, RuntimeFamily } from '../models/samLambdaRuntime'
+
+const localize = nls.loadMessageBundle()
+
+export const DEFAULT_LOCAL_API_PORT = 3000
+export const DEFAULT_LOCAL_API_DEBUG_PORT = 5858
+
+/** How long to wait for more file changes before offering to restart, since saving often touches several files. */
+const RESTART_DEBOUNCE_MS = 500
+
+const STATUS_MESSAGE_TIMEOUT_MS = 10000
+
+/** SAM CLI (Flask) fails with e.g. `OSError: [Errno 98] Address already in use` (or `WinError 10048` on Windows). */
+const PORT_IN_USE_PATTERN = /address already in use|WinError 10048/i
+
+/** Folders that change on every build, and that never hold handler source. */
+const IGNORED_FOLDERS = ['.aws-sam', 'node_modules', '.git', '__pycache__']
+
+/**
+ * What functions log once they wait for a debugger, for the runtimes that can be attached to without a debugger
+ * mounted in the container. .NET and Go need one, which `sam local start-api` isn't given.
+ */
+const ATTACHABLE_RUNTIMES: [string, RuntimeFamily][] = [
+    [WAIT_FOR_DEBUGGER_MESSAGES.NODEJS, RuntimeFamily.NodeJS],
+    [WAIT_FOR_DEBUGGER_MESSAGES.PYTHON, RuntimeFamily.Python],
+    [WAIT_FOR_DEBUGGER_MESSAGES.JAVA, RuntimeFamily.Java],
+]
+
+export interface LocalApiSettings {
+    templatePath: string
+    port: number
+    /** Requests start their function in debug mode and wait for a debugger on this port. */
+    debugPort?: number
+}
+
+interface LocalApi {
+    process: ChildProcess
+    settings: LocalApiSettings
+    /** Restarts on changes without asking. */
+    autoRestart: boolean
+    portInUse: boolean
+    /** Whether the user is being offered to attach a debugger, so that requests don't stack up offers. */
+    offeringAttach: boolean
+    watchers: vscode.Disposable[]
+}
+
+export interface LocalFunction {
+    name: string
+    runtime?: string
+    /** The folder mounted as `/var/task` in the function's container. */
+    codeRoot: string
+}
+
+export interface LocalApiContext {
+    awsContext: Pick<AwsContext, 'getCredentials'>
+    getSamCliPath(): Promise<string>
+    outputChannel: vscode.OutputChannel
+    window?: Window
+    createProcess?(command: string, args: string[], options: child_process.SpawnOptions): ChildProcess
+    createFileSystemWatcher?(pattern: vscode.GlobPattern): vscode.FileSystemWatcher
+    startDebugging?: typeof vscode.debug.startDebugging
+}
+
+let activeApi: LocalApi | undefined
+
+export function getActiveLocalApi(): LocalApi | undefined {
+    return activeApi
+}
+
+/**
+ * Starts `sam local start-api` for a template, with its request logs in a dedicated output channel.
+ *
+ * Only one local API runs at a time; the process is kept so that {@link stopSamLocalApi} can stop it. When the
+ * template or the code of its functions changes, the user is offered to restart the local API.
+ *
+ * @param templateUri the template to start, e.g. when started from the context menu of a template;
+ * the user chooses one of the templates in the workspace otherwise
+ */
+export async function startSamLocalApi(ctx: LocalApiContext, templateUri?: vscode.Uri): Promise<void> {
+    const window = ctx.window ?? Window.vscode()
+    let result: Result = 'Succeeded'
+
+    try {
+        if (activeApi) {
+            ctx.outputChannel.show(true)
+            window.showInformationMessage(
+                localize(
+                    'AWS.samcli.startApi.alreadyRunning',
+                    'A local API is already running on port {0}. Stop it before starting another one.',
+                    activeApi.settings.port
+                )
+            )
+            result = 'Cancelled'
+
+            return
+        }
+
+        const templatePath = templateUri?.fsPath ?? (await pickTemplate(window))
+        if (!templatePath) {
+            result = 'Cancelled'
+
+            return
+        }
+
+        const port = await promptForPort(
+            window,
+            localize('AWS.samcli.startApi.portPrompt', 'Enter the port to serve the local API on'),
+            DEFAULT_LOCAL_API_PORT
+        )
+        if (port === undefined) {
+            result = 'Cancelled'
+
+            return
+        }
+
+        const debugPort = await promptForPort(
+            window,
+            localize(
+                'AWS.samcli.startApi.debugPortPrompt',
+                'Enter the port to attach a debugger to requests on, or leave it empty to run without debugging'
+            ),
+            await getStartPort(Math.max(DEFAULT_LOCAL_API_DEBUG_PORT, port + 1)),
+            true
+        )
+        if (debugPort === undefined) {
+            result = 'Cancelled'
+
+            return
+        }
+
+        await runLocalApi(ctx, { templatePath, port, debugPort: debugPort || undefined })
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to start the local API: %O', err)
+        window.showErrorMessage(
+            localize('AWS.samcli.startApi.error', 'Failed to start the local API: {0}', (err as Error).message)
+        )
+    } finally {
+        recordSamStartApi({ result })
+    }
+}
+
+/**
+ * Stops the running local API, if any.
+ */
+export async function stopSamLocalApi(window: Window = Window.vscode()): Promise<void> {
+    if (!activeApi) {
+        window.showInformationMessage(localize('AWS.samcli.startApi.notRunning', 'The local API is not running.'))
+
+        return
+    }
+
+    const { port } = activeApi.settings
+    await stopProcess(activeApi)
+    window.showInformationMessage(localize('AWS.samcli.startApi.stopped', 'Stopped the local API on port {0}.', port))
+}
+
+/**
+ * Stops the running local API when the extension is deactivated, so that SAM CLI doesn't keep the port.
+ */
+export function disposeSamLocalApi(): void {
+    if (activeApi) {
+        disposeWatchers(activeApi)
+        if (!activeApi.process.stopped) {
+            activeApi.process.stop(true)
+        }
+        activeApi = undefined
+    }
+}
+
+/**
+ * Gets the functions of a template that run local code. Functions without a (local) `CodeUri`, e.g. image
+ * functions, are skipped.
+ */
+export function getLocalFunctions(templatePath: string, template: CloudFormation.Template): LocalFunction[] {
+    const functions: LocalFunction[] = []
+    for (const [name, resource] of Object.entries(template.Resources ?? {})) {
+        if (resource?.Type !== CloudFormation.SERVERLESS_FUNCTION_TYPE) {
+            continue
+        }
+        if (!CloudFormation.isZipLambdaResource(resource.Properties)) {
+            continue
+        }
+
+        const codeUri = CloudFormation.getStringForProperty(resource.Properties, 'CodeUri', template) ?? '.'
+        if (!codeUri.startsWith('s3://')) {
+            functions.push({
+                name,
+                runtime: CloudFormation.getStringForProperty(resource.Properties, 'Runtime', template),
+                codeRoot: path.resolve(path.dirname(templatePath), codeUri),
+            })
+        }
+    }
+
+    return functions
+}
+
+/**
+ * Gets the folders whose changes should restart the local API: the code of each function of the template.
+ */
+export function getCodeFolders(templatePath: string, template: CloudFormation.Template): string[] {
+    return [...new Set(getLocalFunctions(templatePath, template).map(fn => fn.codeRoot))]
+}
+
+/**
+ * Makes the configuration to attach to a function of the local API, the same way as the debugger is attached
+ * to a function invoked locally (see e.g. `makeTypescriptConfig`).
+ *
+ * @param codeRoot the folder of the function's code, which is mounted as `/var/task`
+ */
+export function makeLocalApiAttachConfig(
+    runtimeFamily: RuntimeFamily,
+    debugPort: number,
+    codeRoot: string
+): (vscode.DebugConfiguration & { runtimeFamily: RuntimeFamily }) | undefined {
+    const name = localize('AWS.samcli.startApi.attachName', 'Attach to Local API')
+    switch (runtimeFamily) {
+        case RuntimeFamily.NodeJS:
+            return {
+                type: 'node',
+                request: 'attach',
+                name,
+                runtimeFamily,
+                address: 'localhost',
+                port: debugPort,
+                localRoot: codeRoot,
+                remoteRoot: '/var/task',
+                protocol: 'inspector',
+                skipFiles: ['/var/runtime/node_modules/**/*.js', '<node_internals>/**/*.js'],
+            }
+        case RuntimeFamily.Python:
+            return {
+                type: 'python',
+                request: 'attach',
+                name,
+                runtimeFamily,
+                host: 'localhost',
+                port: debugPort,
+                pathMappings: getLocalRootVariants(codeRoot).map(localRoot => ({ localRoot, remoteRoot: '/var/task' })),
+            }
+        case RuntimeFamily.Java:
+            return {
+                type: 'java',
+                request: 'attach',
+                name,
+                runtimeFamily,
+                hostName: '127.0.0.1',
+                port: debugPort,
+            }
+        default:
+            return undefined
+    }
+}
+
+export function isPortInUseError(text: string): boolean {
+    return PORT_IN_USE_PATTERN.test(text)
+}
+
+async function runLocalApi(
+    ctx: LocalApiContext,
+    settings: LocalApiSettings,
+    autoRestart: boolean = false
+): Promise<void> {
+    const window = ctx.window ?? Window.vscode()
+    const credentials = await ctx.awsContext.getCredentials()
+    const args = await buildSamCliStartApiArguments({
+        templatePath: settings.templatePath,
+        port: settings.port.toString(),
+        debugPort: settings.debugPort?.toString(),
+    })
+    const options: child_process.SpawnOptions = {
+        cwd: path.dirname(settings.templatePath),
+        // functions are called with the active credentials, if any
+        env: { ...process.env, ...(credentials ? asEnvironmentVariables(credentials) : {}) },
+    }
+    const createProcess =
+        ctx.createProcess ??
+        ((command: string, args: string[], options: child_process.SpawnOptions) =>
+            new ChildProcess(true, command, options, ...args))
+    const childProcess = createProcess((await ctx.getSamCliPath()) || 'sam', args, options)
+    const api: LocalApi = {
+        process: childProcess,
+        settings,
+        autoRestart,
+        portInUse: false,
+        offeringAttach: false,
+        watchers: [],
+    }
+    activeApi = api
+
+    const debuggerCues = Object.values(WAIT_FOR_DEBUGGER_MESSAGES)
+    const onOutput = (text: string) => {
+        ctx.outputChannel.append(removeAnsi(text))
+        if (isPortInUseError(text)) {
+            api.portInUse = true
+        }
+        if (!settings.debugPort || !debuggerCues.some(cue => text.includes(cue))) {
+            return
+        }
+        const runtimeFamily = ATTACHABLE_RUNTIMES.find(([cue]) => text.includes(cue))?.[1]
+        if (runtimeFamily !== undefined) {
+            offerToAttach(ctx, api, runtimeFamily, window).catch(err => {
+                getLogger().error('Failed to attach a debugger to the local API: %O', err)
+            })
+        } else {
+            window.setStatusBarMessage(
+                localize(
+                    'AWS.samcli.startApi.waitingForDebugger',
+                    '$(debug) A request is waiting for a debugger on port {0}',
+                    settings.debugPort
+                ),
+                STATUS_MESSAGE_TIMEOUT_MS
+            )
+        }
+    }
+
+    ctx.outputChannel.show(true)
+    ctx.outputChannel.appendLine(
+        localize('AWS.samcli.startApi.starting', 'Starting the local API: {0}', `${childProcess}`)
+    )
+    await childProcess.start({
+        onStdout: onOutput,
+        onStderr: onOutput,
+        onError: error => {
+            ctx.outputChannel.appendLine(error.message)
+        },
+        onClose: code => {
+            ctx.outputChannel.appendLine('')
+            ctx.outputChannel.appendLine(
+                localize('AWS.samcli.startApi.exited', 'The local API stopped (exit code: {0})', code)
+            )
+            onLocalApiStopped(ctx, api, window)
+        },
+    })
+
+    api.watchers = watchSources(ctx, api, window)
+    window.setStatusBarMessage(
+        localize(
+            'AWS.samcli.startApi.started',
+            '$(globe) Local API starting on http://127.0.0.1:{0}',
+            settings.port
+        ),
+        STATUS_MESSAGE_TIMEOUT_MS
+    )
+}
+
+/**
+ * Offers to attach a debugger to a request that waits for one.
+ */
+async function offerToAttach(
+    ctx: LocalApiContext,
+    api: LocalApi,
+    runtimeFamily: RuntimeFamily,
+    window: Window
+): Promise<void> {
+    if (api.offeringAttach || !api.settings.debugPort) {
+        return
+    }
+
+    api.offeringAttach = true
+    try {
+        const attach = localize('AWS.samcli.startApi.attach', 'Attach Debugger')
+        const selection = await window.showInformationMessage(
+            localize(
+                'AWS.samcli.startApi.attachPrompt',
+                'A request to the local API is waiting for a debugger on port {0}.',
+                api.settings.debugPort
+            ),
+            attach
+        )
+        if (selection !== attach || activeApi !== api) {
+            return
+        }
+
+        const codeRoot = await pickCodeRoot(api.settings.templatePath, runtimeFamily)
+        if (!codeRoot) {
+            return
+        }
+        const debugConfig = makeLocalApiAttachConfig(runtimeFamily, api.settings.debugPort, codeRoot)
+        if (debugConfig) {
+            await attachDebugger({ debugConfig, onStartDebugging: ctx.startDebugging })
+        }
+    } finally {
+        api.offeringAttach = false
+    }
+}
+
+/**
+ * Gets the code folder of the function that waits for a debugger, asking the user which function it is when
+ * the template has functions of the same runtime in different folders.
+ */
+async function pickCodeRoot(templatePath: string, runtimeFamily: RuntimeFamily): Promise<string | undefined> {
+    const template = ext.templateRegistry.getRegisteredItem(templatePath)?.item
+    const functions = (template ? getLocalFunctions(templatePath, template) : []).filter(
+        fn => fn.runtime && getFamily(fn.runtime) === runtimeFamily
+    )
+    const codeRoots = [...new Set(functions.map(fn => fn.codeRoot))]
+    if (codeRoots.length <= 1) {
+        return codeRoots[0] ?? path.dirname(templatePath)
+    }
+
+    const quickPick = picker.createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.samcli.startApi.attachFunctionPrompt', 'Which function would you like to debug?'),
+        },
+        items: functions.map(fn => ({ label: fn.name, detail: fn.codeRoot })),
+    })
+    const choice = picker.verifySinglePickerOutput(await picker.promptUser({ picker: quickPick }))
+
+    return choice?.detail
+}
+
+function onLocalApiStopped(ctx: LocalApiContext, api: LocalApi, window: Window): void {
+    // stopped by the toolkit, rather than by e.g. an error
+    if (activeApi !== api) {
+        return
+    }
+
+    disposeWatchers(api)
+    activeApi = undefined
+    if (!api.portInUse) {
+        return
+    }
+
+    // the port was taken between checking it and SAM CLI binding it, e.g. by another local API
+    getStartPort(api.settings.port + 1)
+        .then(async alternatePort => {
+            const usePort = localize('AWS.samcli.startApi.usePort', 'Use port {0}', alternatePort)
+            const selection = await window.showErrorMessage(
+                localize(
+                    'AWS.samcli.startApi.portInUse',
+                    'Port {0} is already in use. Start the local API on port {1} instead?',
+                    api.settings.port,
+                    alternatePort
+                ),
+                usePort
+            )
+            if (selection === usePort && !activeApi) {
+                await runLocalApi(ctx, { ...api.settings, port: alternatePort })
+            }
+        })
+        .catch(err => {
+            getLogger().error('Failed to restart the local API on another port: %O', err)
+        })
+}
+
+/**
+ * Watches the template and the code of its functions, and offers to restart the local API when they change.
+ */
+function watchSources(ctx: LocalApiContext, api: LocalApi, window: Window): vscode.Disposable[] {
+    const createWatcher = ctx.createFileSystemWatcher ?? vscode.workspace.createFileSystemWatcher
+    const { templatePath } = api.settings
+    const template = ext.templateRegistry.getRegisteredItem(templatePath)?.item
+    const patterns = [
+        new vscode.RelativePattern(path.dirname(templatePath), path.basename(templatePath)),
+        ...(template ? getCodeFolders(templatePath, template) : []).map(
+            folder => new vscode.RelativePattern(folder, '**/*')
+        ),
+    ]
+
+    let timer: NodeJS.Timer | undefined
+    let prompting = false
+    const onChange = (uri: vscode.Uri) => {
+        if (uri.fsPath.split(path.sep).some(part => IGNORED_FOLDERS.includes(part))) {
+            return
+        }
+        if (timer) {
+            clearTimeout(timer)
+        }
+        timer = setTimeout(async () => {
+            if (activeApi !== api || prompting) {
+                return
+            }
+            if (api.autoRestart) {
+                await restartLocalApi(ctx, api, window)
+                return
+            }
+
+            prompting = true
+            try {
+                const restart = localize('AWS.samcli.startApi.restart', 'Restart')
+                const alwaysRestart = localize('AWS.samcli.startApi.alwaysRestart', 'Always Restart')
+                const selection = await window.showInformationMessage(
+                    localize(
+                        'AWS.samcli.startApi.changed',
+                        'The template or code of the local API changed. Restart the local API to serve the changes?'
+                    ),
+                    restart,
+                    alwaysRestart
+                )
+                if (selection === alwaysRestart) {
+                    api.autoRestart = true
+                }
+                if ((selection === restart || selection === alwaysRestart) && activeApi === api) {
+                    await restartLocalApi(ctx, api, window)
+                }
+            } finally {
+                prompting = false
+            }
+        }, RESTART_DEBOUNCE_MS)
+    }
+
+    const watchers = patterns.map(pattern => {
+        const watcher = createWatcher(pattern)
+        watcher.onDidChange(onChange)
+        watcher.onDidCreate(onChange)
+        watcher.onDidDelete(onChange)
+
+        return watcher
+    })
+
+    return [...watchers, { dispose: () => timer && clearTimeout(timer) }]
+}
+
+async function restartLocalApi(ctx: LocalApiContext, api: LocalApi, window: Window): Promise<void> {
+    getLogger().info('Restarting the local API on port %d', api.settings.port)
+    try {
+        await stopProcess(api)
+        await runLocalApi(ctx, api.settings, api.autoRestart)
+    } catch (err) {
+        disposeSamLocalApi()
+        getLogger().error('Failed to restart the local API: %O', err)
+        window.showErrorMessage(
+            localize('AWS.samcli.startApi.restartError', 'Failed to restart the local API: {0}', (err as Error).message)
+        )
+    }
+}
+
+async function stopProcess(api: LocalApi): Promise<void> {
+    disposeWatchers(api)
+    if (activeApi === api) {
+        activeApi = undefined
+    }
+    if (!api.process.stopped) {
+        api.process.stop(true)
+        // the port is only free once the process has exited
+        await waitUntil(async () => api.process.stopped, { timeout: 5000, interval: 200, truthy: true })
+    }
+}
+
+function disposeWatchers(api: LocalApi): void {
+    vscode.Disposable.from(...api.watchers).dispose()
+    api.watchers = []
+}
+
+async function pickTemplate(window: Window): Promise<string | undefined> {
+    const templates = ext.templateRegistry.registeredItems
+        .filter(template => Object.values(template.item.Resources ?? {}).some(isServerlessFunction))
+        .map(template => template.path)
+        .sort()
+    if (templates.length === 0) {
+        window.showErrorMessage(
+            localize('AWS.samcli.startApi.noTemplates', 'No SAM templates with functions were found in the workspace.')
+        )
+
+        return undefined
+    }
+    if (templates.length === 1) {
+        return templates[0]
+    }
+
+    const quickPick = picker.createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.samcli.startApi.templatePrompt', 'Which template would you like to serve locally?'),
+        },
+        items: templates.map(templatePath => ({
+            label: path.basename(path.dirname(templatePath)),
+            detail: templatePath,
+        })),
+    })
+    const choice = picker.verifySinglePickerOutput(await picker.promptUser({ picker: quickPick }))
+
+    return choice?.detail
+}
+
+function isServerlessFunction(resource: CloudFormation.Resource | undefined): boolean {
+    return resource?.Type === CloudFormation.SERVERLESS_FUNCTION_TYPE
+}
+
+/**
+ * Prompts for a port, and suggests the next free port if the entered one is in use.
+ *
+ * @returns the port, 0 if the prompt is optional and left empty, or undefined if cancelled
+ */
+async function promptForPort(
+    window: Window,
+    prompt: string,
+    defaultPort: number,
+    optional: boolean = false
+): Promise<number | undefined> {
+    let value = defaultPort.toString()
+    while (true) {
+        const input = await window.showInputBox({
+            prompt,
+            value,
+            ignoreFocusOut: true,
+            validateInput: text => {
+                if (optional && !text.trim()) {
+                    return undefined
+                }
+                const port = Number(text)
+
+                return Number.isInteger(port) && port > 0 && port < 65536
+                    ? undefined
+                    : localize('AWS.samcli.startApi.invalidPort', 'Enter a port between 1 and 65535')
+            },
+        })
+        if (input === undefined) {
+            return undefined
+        }
+        if (optional && !input.trim()) {
+            return 0
+        }
+
+        const port = Number(input)
+        const freePort = await getStartPort(port)
+        if (freePort === port) {
+            return port
+        }
+
+        const usePort = localize('AWS.samcli.startApi.usePort', 'Use port {0}', freePort)
+        const selection = await window.showWarningMessage(
+            localize('AWS.samcli.startApi.portTaken', 'Port {0} is already in use.', port),
+            usePort,
+            localize('AWS.samcli.startApi.otherPort', 'Enter another port')
+        )
+        if (selection === usePort) {
+            return freePort
+        }
+        if (selection === undefined) {
+            return undefined
+        }
+        value = freePort.toString()
+    }
+}
@@ -0,0 +1,286 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as path from 'path'
+import * as semver from 'semver'
+import * as vscode from 'vscode'
+import * as nls from 'vscode-nls'
+
+import { asEnvironmentVariables } from '../../credentials/credentialsUtilities'
+import { AwsContext, NoActiveCredentialError } from '../../shared/awsContext'
+import { samAboutInstallUrl } from '../../shared/constants'
+import { getLogger } from '../../shared/logger'
+import { getSamCliContext, getSamCliVersion, SamCliContext } from '../../shared/sam/cli/samCliContext'
+import { maskParameterOverrides } from '../../shared/sam/cli/samCliInvokerUtils'
+import { buildSamCliSyncArguments } from '../../shared/sam/cli/samCliSync'
+import { MINIMUM_SAM_CLI_VERSION_INCLUSIVE_FOR_SYNC } from '../../shared/sam/cli/samCliValidator'
+import { recordSamSync, Result } from '../../shared/telemetry/telemetry'
+import * as picker from '../../shared/ui/picker'
+import { Window } from '../../shared/vscode/window'
+import { SamDeployWizardResponse } from '../wizards/samDeployWizard'
+
+const localize = nls.loadMessageBundle()
+
+export const LAST_SYNC_TARGET_KEY = 'aws.sam.lastSyncTarget'
+
+/**
+ * The stack a SAM application was last synced to. Persisted so the next sync can skip the wizard.
+ */
+export interface SamSyncTarget {
+    templatePath: string
+    stackName: string
+    region: string
+    s3Bucket: string
+    ecrRepo?: string
+    parameterOverrides: [string, string][]
+    /** Names of overridden `NoEcho` parameters. Their values are not saved, and are asked for again on reuse. */
+    noEchoParameters?: string[]
+    /** Credentials profile that was active when the target was chosen. */
+    profile?: string
+}
+
+interface ActiveSync {
+    terminal: vscode.Terminal
+    target: SamSyncTarget
+}
+
+let activeSync: ActiveSync | undefined
+
+export function getActiveSync(): ActiveSync | undefined {
+    return activeSync
+}
+
+/**
+ * Starts `sam sync --watch` in a dedicated terminal.
+ *
+ * Only one sync runs at a time; the terminal is kept so that {@link stopSamSync} can terminate it.
+ */
+export async function syncSamApplication(
+    {
+        samCliContext = getSamCliContext(),
+        samDeployWizard,
+        getSamCliPath,
+    }: {
+        samCliContext?: SamCliContext
+        samDeployWizard: () => Promise<SamDeployWizardResponse | undefined>
+        getSamCliPath(): Promise<string>
+    },
+    {
+        awsContext,
+        state,
+        window = Window.vscode(),
+        createTerminal = vscode.window.createTerminal,
+    }: {
+        awsContext: Pick<AwsContext, 'getCredentials' | 'getCredentialProfileName'>
+        state: vscode.Memento
+        window?: Window
+        createTerminal?(options: vscode.TerminalOptions): vscode.Terminal
+    }
+): Promise<void> {
+    let syncResult: Result = 'Succeeded'
+    let samVersion: string | undefined
+    try {
+        if (activeSync) {
+            activeSync.terminal.show()
+            window.showInformationMessage(
+                localize(
+                    'AWS.samcli.sync.alreadyRunning',
+                    'SAM sync is already running for stack {0}. Stop it before starting another sync.',
+                    activeSync.target.stackName
+                )
+            )
+            syncResult = 'Cancelled'
+
+            return
+        }
+
+        const credentials = await awsContext.getCredentials()
+        if (!credentials) {
+            throw new NoActiveCredentialError()
+        }
+
+        samVersion = await getSamCliVersion(samCliContext)
+        if (semver.lt(samVersion, MINIMUM_SAM_CLI_VERSION_INCLUSIVE_FOR_SYNC)) {
+            const updateSam = localize('AWS.samcli.sync.updateSamCli', 'Update SAM CLI')
+            window
+                .showErrorMessage(
+                    localize(
+                        'AWS.samcli.sync.unsupportedVersion',
+                        'SAM sync requires SAM CLI {0} or newer, but version {1} is installed.',
+                        MINIMUM_SAM_CLI_VERSION_INCLUSIVE_FOR_SYNC,
+                        samVersion
+                    ),
+                    updateSam
+                )
+                .then(selection => {
+                    if (selection === updateSam) {
+                        vscode.env.openExternal(vscode.Uri.parse(samAboutInstallUrl))
+                    }
+                })
+            syncResult = 'Failed'
+
+            return
+        }
+
+        const profile = awsContext.getCredentialProfileName()
+        const target = await pickSyncTarget(state, profile, samDeployWizard, window)
+        if (!target) {
+            syncResult = 'Cancelled'
+
+            return
+        }
+        const noEchoParameters = new Set(target.noEchoParameters)
+        await state.update(LAST_SYNC_TARGET_KEY, {
+            ...target,
+            parameterOverrides: target.parameterOverrides.filter(([name]) => !noEchoParameters.has(name)),
+        })
+
+        const samCommand = getSamCommand(
+            await getSamCliPath(),
+            buildSamCliSyncArguments({
+                templateFile: target.templatePath,
+                stackName: target.stackName,
+                region: target.region,
+                s3Bucket: target.s3Bucket,
+                ecrRepo: target.ecrRepo,
+                parameterOverrides: new Map(target.parameterOverrides),
+            })
+        )
+        getLogger().info('Starting SAM sync: %O', {
+            ...samCommand,
+            shellArgs: maskParameterOverrides(samCommand.shellArgs, noEchoParameters),
+        })
+
+        const terminal = createTerminal({
+            name: localize('AWS.samcli.sync.terminalName', 'SAM Sync: {0}', target.stackName),
+            cwd: path.dirname(target.templatePath),
+            env: asEnvironmentVariables(credentials),
+            ...samCommand,
+        })
+        terminal.show()
+        activeSync = { terminal, target }
+    } catch (err) {
+        syncResult = 'Failed'
+        getLogger().error('Failed to start SAM sync: %O', err)
+        window.showErrorMessage(
+            localize('AWS.samcli.sync.error', 'Failed to start SAM sync: {0}', (err as Error).message)
+        )
+    } finally {
+        recordSamSync({ result: syncResult, version: samVersion })
+    }
+}
+
+/**
+ * Terminates the running `sam sync`, if any.
+ */
+export function stopSamSync(window: Window = Window.vscode()): void {
+    if (!activeSync) {
+        window.showInformationMessage(localize('AWS.samcli.sync.notRunning', 'SAM sync is not running.'))
+
+        return
+    }
+
+    const { terminal, target } = activeSync
+    activeSync = undefined
+    terminal.dispose()
+    window.showInformationMessage(
+        localize('AWS.samcli.sync.stopped', 'Stopped SAM sync for stack {0}.', target.stackName)
+    )
+}
+
+/**
+ * Forgets the active sync when the user closes its terminal.
+ */
+export function onDidCloseTerminal(terminal: vscode.Terminal): void {
+    if (activeSync?.terminal === terminal) {
+        getLogger().info('SAM sync terminal closed for stack: %O', activeSync.target.stackName)
+        activeSync = undefined
+    }
+}
+
+/**
+ * Offers to reuse the last sync target (for the current profile), or runs the deploy wizard to choose one.
+ */
+async function pickSyncTarget(
+    state: vscode.Memento,
+    profile: string | undefined,
+    samDeployWizard: () => Promise<SamDeployWizardResponse | undefined>,
+    window: Window
+): Promise<SamSyncTarget | undefined> {
+    const lastTarget = state.get<SamSyncTarget>(LAST_SYNC_TARGET_KEY)
+    if (lastTarget && lastTarget.profile === profile) {
+        const reuseItem: vscode.QuickPickItem = {
+            label: localize('AWS.samcli.sync.lastTarget', 'Sync to {0}', lastTarget.stackName),
+            description: lastTarget.region,
+            detail: lastTarget.templatePath,
+        }
+        const quickPick = picker.createQuickPick({
+            options: {
+                ignoreFocusOut: true,
+                title: localize('AWS.samcli.sync.target.prompt', 'Which stack would you like to sync?'),
+            },
+            items: [
+                reuseItem,
+                { label: localize('AWS.samcli.sync.otherTarget', 'Choose a different template or stack...') },
+            ],
+        })
+        const choice = picker.verifySinglePickerOutput(await picker.promptUser({ picker: quickPick }))
+        if (!choice) {
+            return undefined
+        }
+        if (choice === reuseItem) {
+            return await promptForNoEchoParameters(lastTarget, window)
+        }
+    }
+
+    const response = await samDeployWizard()
+    if (!response) {
+        return undefined
+    }
+
+    return {
+        templatePath: response.template.fsPath,
+        stackName: response.stackName,
+        region: response.region,
+        s3Bucket: response.s3Bucket,
+        ecrRepo: response.ecrRepo?.repositoryUri,
+        parameterOverrides: [...response.parameterOverrides.entries()],
+        noEchoParameters: [...response.parameterOverrides.keys()].filter(name => response.noEchoParameters?.has(name)),
+        profile,
+    }
+}
+
+/**
+ * Asks again for the values of the saved target's `NoEcho` parameters.
+ */
+async function promptForNoEchoParameters(target: SamSyncTarget, window: Window): Promise<SamSyncTarget | undefined> {
+    const parameterOverrides = [...target.parameterOverrides]
+    for (const name of target.noEchoParameters ?? []) {
+        const value = await window.showInputBox({
+            prompt: localize('AWS.samcli.sync.noEchoParameter.prompt', 'Enter a value for the parameter {0}', name),
+            password: true,
+            ignoreFocusOut: true,
+        })
+        if (value === undefined) {
+            return undefined
+        }
+        parameterOverrides.push([name, value])
+    }
+
+    return { ...target, parameterOverrides }
+}
+
+/**
+ * The terminal runs SAM CLI directly (rather than through a shell) so that disposing it stops the sync.
+ * Windows can only start batch files (e.g. `sam.cmd`) through `cmd.exe`.
+ */
+function getSamCommand(samCliPath: string, args: string[]): { shellPath: string; shellArgs: string[] } {
+    const samCommand = samCliPath || 'sam'
+    if (process.platform === 'win32' && !samCommand.toLowerCase().endsWith('.exe')) {
+        return { shellPath: process.env.ComSpec ?? 'cmd.exe', shellArgs: ['/c', samCommand, ...args] }
+    }
+
+    return { shellPath: samCommand, shellArgs: args }
+}
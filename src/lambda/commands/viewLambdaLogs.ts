@@ -0,0 +1,96 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { openLogStream, SelectLogStreamResponse } from '../../cloudWatchLogs/commands/viewLogStream'
+import { LogStreamRegistry } from '../../cloudWatchLogs/registry/logStreamRegistry'
+import { CloudWatchLogsClient } from '../../shared/clients/cloudWatchLogsClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaViewLogs, Result } from '../../shared/telemetry/telemetry'
+import { Window } from '../../shared/vscode/window'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+
+/**
+ * Lambda writes the logs of a function to this log group, which is created on the first invoke.
+ */
+export function getLambdaLogGroupName(functionName: string): string {
+    return `/aws/lambda/${functionName}`
+}
+
+/**
+ * Opens the most recent log stream of a Lambda function in the CloudWatch Logs viewer.
+ */
+export async function viewLambdaLogs(
+    node: LambdaFunctionNode,
+    registry: LogStreamRegistry,
+    {
+        client = ext.toolkitClientBuilder.createCloudWatchLogsClient(node.regionCode),
+        window = Window.vscode(),
+        open = openLogStream,
+    }: {
+        client?: CloudWatchLogsClient
+        window?: Window
+        open?(logStream: SelectLogStreamResponse, registry: LogStreamRegistry): Promise<void>
+    } = {}
+): Promise<void> {
+    const logGroupName = getLambdaLogGroupName(node.functionName)
+    let result: Result = 'Succeeded'
+
+    try {
+        const logStreamName = await getLatestLogStreamName(client, logGroupName)
+        if (!logStreamName) {
+            result = 'Cancelled'
+            window.showInformationMessage(
+                localize(
+                    'AWS.lambda.viewLogs.noLogs',
+                    'There are no logs for {0} yet. Logs appear after the function is invoked.',
+                    node.functionName
+                )
+            )
+
+            return
+        }
+
+        await open({ region: node.regionCode, logGroupName, logStreamName }, registry)
+    } catch (err) {
+        result = 'Failed'
+        getLogger().error('Failed to open logs for Lambda function %s: %O', node.functionName, err)
+        window.showErrorMessage(
+            localize(
+                'AWS.lambda.viewLogs.error',
+                'Failed to open logs for {0}: {1}',
+                node.functionName,
+                (err as Error).message
+            )
+        )
+    } finally {
+        recordLambdaViewLogs({ result })
+    }
+}
+
+/**
+ * @returns the name of the log stream with the latest event, or undefined if the log group does not exist (yet)
+ * or has no streams.
+ */
+async function getLatestLogStreamName(client: CloudWatchLogsClient, logGroupName: string): Promise<string | undefined> {
+    try {
+        const response = await client.describeLogStreams({
+            logGroupName,
+            orderBy: 'LastEventTime',
+            descending: true,
+            limit: 1,
+        })
+
+        return response.logStreams?.[0]?.logStreamName
+    } catch (err) {
+        if ((err as { code?: string }).code === 'ResourceNotFoundException') {
+            return undefined
+        }
+        throw err
+    }
+}
@@ -0,0 +1,62 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { Lambda } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaViewLayerVersion } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { LambdaLayerVersionNode } from '../explorer/lambdaLayerNodes'
+
+/**
+ * Formats the details of a layer version, leaving out the presigned `Content.Location`.
+ */
+export function formatLayerVersionDetails(layerVersion: Lambda.GetLayerVersionResponse): string {
+    // TODO: use the SDK type once it includes `CompatibleArchitectures`
+    const architectures = (layerVersion as { CompatibleArchitectures?: string[] }).CompatibleArchitectures
+
+    const details = {
+        LayerVersionArn: layerVersion.LayerVersionArn,
+        Description: layerVersion.Description,
+        CreatedDate: layerVersion.CreatedDate,
+        CompatibleRuntimes: layerVersion.CompatibleRuntimes ?? [],
+        CompatibleArchitectures: architectures ?? [],
+        LicenseInfo: layerVersion.LicenseInfo,
+        CodeSize: layerVersion.Content?.CodeSize,
+        CodeSha256: layerVersion.Content?.CodeSha256,
+    }
+
+    return JSON.stringify(details, undefined, 4)
+}
+
+/**
+ * Opens the details of a layer version, including its compatible runtimes and architectures, in a JSON document.
+ */
+export async function viewLayerVersion(
+    node: LambdaLayerVersionNode,
+    window = Window.vscode(),
+    openDocument = async (content: string) =>
+        vscode.window.showTextDocument(await vscode.workspace.openTextDocument({ language: 'json', content }))
+): Promise<void> {
+    getLogger().debug('ViewLayerVersion called for %s', node.arn)
+
+    let content: string
+    try {
+        content = formatLayerVersionDetails(await node.lambda.getLayerVersion(node.arn))
+    } catch (e) {
+        getLogger().error(`Failed to get layer version ${node.arn}: %O`, e)
+        showErrorWithLogs(
+            localize('AWS.lambda.layer.view.error', 'Failed to get layer version {0}', node.name),
+            window
+        )
+        recordLambdaViewLayerVersion({ result: 'Failed' })
+        return
+    }
+
+    await openDocument(content)
+    recordLambdaViewLayerVersion({ result: 'Succeeded' })
+}
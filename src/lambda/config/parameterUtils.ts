@@ -16,16 +16,24 @@ export interface GetParametersContext {
     loadTemplate: typeof CloudFormation.load
 }
 
+export interface TemplateParameter {
+    required: boolean
+    defaultValue?: string
+    allowedValues?: string[]
+    description?: string
+    noEcho?: boolean
+}
+
 export async function getParameters(
     templateUri: vscode.Uri,
     context: GetParametersContext = { loadTemplate: CloudFormation.load }
-): Promise<Map<string, { required: boolean }>> {
+): Promise<Map<string, TemplateParameter>> {
     const template = await context.loadTemplate(templateUri.fsPath)
     if (!template.Parameters) {
         return new Map()
     }
 
-    const result = new Map<string, { required: boolean }>()
+    const result = new Map<string, TemplateParameter>()
 
     for (const name of Object.getOwnPropertyNames(template.Parameters)) {
         const parameter = template.Parameters[name]!
@@ -33,6 +41,11 @@ export async function getParameters(
         result.set(name, {
             // Explicitly compare with undefined, as a valid default value may be falsy.
             required: parameter.Default === undefined,
+            defaultValue: parameter.Default === undefined ? undefined : String(parameter.Default),
+            allowedValues: parameter.AllowedValues?.map(String),
+            description: parameter.Description,
+            // YAML templates may spell this as the string 'true'.
+            noEcho: String(parameter.NoEcho).toLowerCase() === 'true',
         })
     }
 
@@ -61,8 +61,13 @@ export class CloudFormationNode extends AWSTreeNodeBase {
     }
 }
 
+/** Drift status of resources that the last drift detection did not check, e.g. because they do not support it. */
+export const DRIFT_STATUS_NOT_CHECKED: CloudFormation.StackResourceDriftStatus = 'NOT_CHECKED'
+
 export class CloudFormationStackNode extends AWSTreeNodeBase implements AWSResourceNode {
     private readonly functionNodes: Map<string, LambdaFunctionNode>
+    /** Results of the last drift detection, keyed by physical resource ID. */
+    private resourceDrifts: Map<string, CloudFormation.StackResourceDrift> | undefined
 
     public constructor(
         public readonly parent: AWSTreeNodeBase,
@@ -125,6 +130,36 @@ export class CloudFormationStackNode extends AWSTreeNodeBase implements AWSResou
         this.tooltip = `${this.stackName}${os.EOL}${this.stackId}`
     }
 
+    /**
+     * The resource drifts found by the last drift detection, or undefined if drift has not been detected yet.
+     */
+    public get drifts(): CloudFormation.StackResourceDrift[] | undefined {
+        return this.resourceDrifts ? [...this.resourceDrifts.values()] : undefined
+    }
+
+    /**
+     * Annotates the stack and its resource nodes with the results of a drift detection.
+     */
+    public setDrifts(stackDriftStatus: CloudFormation.StackDriftStatus, drifts: CloudFormation.StackResourceDrift[]) {
+        this.resourceDrifts = new Map(drifts.map(drift => [drift.PhysicalResourceId ?? drift.LogicalResourceId, drift]))
+        this.description = stackDriftStatus
+        this.functionNodes.forEach(node => this.annotateDrift(node))
+    }
+
+    public getDriftStatus(physicalResourceId: string): CloudFormation.StackResourceDriftStatus | undefined {
+        if (!this.resourceDrifts) {
+            return undefined
+        }
+
+        return this.resourceDrifts.get(physicalResourceId)?.StackResourceDriftStatus ?? DRIFT_STATUS_NOT_CHECKED
+    }
+
+    private annotateDrift(node: LambdaFunctionNode): LambdaFunctionNode {
+        node.description = this.getDriftStatus(node.functionName)
+
+        return node
+    }
+
     private async updateChildren(): Promise<void> {
         const resources: string[] = await this.resolveLambdaResources()
         const client: LambdaClient = ext.toolkitClientBuilder.createLambdaClient(this.regionCode)
@@ -136,8 +171,8 @@ export class CloudFormationStackNode extends AWSTreeNodeBase implements AWSResou
         updateInPlace(
             this.functionNodes,
             intersection(resources, functions.keys()),
-            key => this.functionNodes.get(key)!.update(functions.get(key)!),
-            key => makeCloudFormationLambdaFunctionNode(this, this.regionCode, functions.get(key)!)
+            key => this.annotateDrift(this.functionNodes.get(key)!).update(functions.get(key)!),
+            key => this.annotateDrift(makeCloudFormationLambdaFunctionNode(this, this.regionCode, functions.get(key)!))
         )
     }
 
@@ -5,10 +5,12 @@
 
 import { Lambda } from 'aws-sdk'
 import * as os from 'os'
-import { Uri } from 'vscode'
+import { TreeItemCollapsibleState, Uri } from 'vscode'
 import { ext } from '../../shared/extensionGlobals'
 import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
 import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { makeLayerReferenceNodes } from './lambdaLayerNodes'
+import { LambdaAliasesNode, LambdaVersionsNode } from './lambdaQualifierNodes'
 
 export class LambdaFunctionNode extends AWSTreeNodeBase implements AWSResourceNode {
     public constructor(
@@ -16,7 +18,7 @@ export class LambdaFunctionNode extends AWSTreeNodeBase implements AWSResourceNo
         public readonly regionCode: string,
         public configuration: Lambda.FunctionConfiguration
     ) {
-        super('')
+        super('', TreeItemCollapsibleState.Collapsed)
         this.update(configuration)
         this.iconPath = {
             dark: Uri.file(ext.iconPaths.dark.lambda),
@@ -30,6 +32,21 @@ export class LambdaFunctionNode extends AWSTreeNodeBase implements AWSResourceNo
         this.tooltip = `${this.configuration.FunctionName}${os.EOL}${this.configuration.FunctionArn}`
     }
 
+    /**
+     * Folders of the published versions and aliases of the function, followed by the layers it references.
+     */
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        const lambda = ext.toolkitClientBuilder.createLambdaClient(this.regionCode)
+        const layers = makeLayerReferenceNodes(
+            this,
+            lambda,
+            this.configuration.Layers ?? [],
+            this.configuration.FunctionArn?.split(':')[4]
+        )
+
+        return [new LambdaVersionsNode(this, lambda), new LambdaAliasesNode(this, lambda), ...layers]
+    }
+
     public get functionName(): string {
         return this.configuration.FunctionName || ''
     }
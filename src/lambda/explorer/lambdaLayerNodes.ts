@@ -0,0 +1,171 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { Lambda } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+
+export const CONTEXT_VALUE_LAYER_VERSION = 'awsLambdaLayerVersionNode'
+export const CONTEXT_VALUE_LAYER_VERSION_READ_ONLY = 'awsLambdaLayerVersionReadOnlyNode'
+
+export interface LayerVersionArn {
+    accountId: string
+    layerName: string
+    version: number
+}
+
+/**
+ * Parses a layer version ARN, e.g. `arn:aws:lambda:us-west-2:123456789012:layer:my-layer:3`.
+ */
+export function parseLayerVersionArn(arn: string): LayerVersionArn | undefined {
+    const match = /^arn:[^:]+:lambda:[^:]+:(\d{12}):layer:([^:]+):(\d+)$/.exec(arn)
+    if (!match) {
+        return undefined
+    }
+
+    return { accountId: match[1], layerName: match[2], version: parseInt(match[3], 10) }
+}
+
+/**
+ * An AWS Explorer node representing the Lambda layers of an account.
+ * Contains a node for each layer in a specific region as child nodes.
+ */
+export class LambdaLayersNode extends AWSTreeNodeBase {
+    public constructor(private readonly lambda: LambdaClient) {
+        super('Lambda Layers', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsLambdaLayersNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const layers = await toArrayAsync(this.lambda.listLayers())
+
+                return layers.map(layer => new LambdaLayerNode(this, this.lambda, layer))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.lambda.noLayers', '[No Layers found]')),
+            sort: (nodeA: LambdaLayerNode, nodeB: LambdaLayerNode) => nodeA.layerName.localeCompare(nodeB.layerName),
+        })
+    }
+}
+
+/**
+ * A Lambda layer, containing its versions as child nodes.
+ */
+export class LambdaLayerNode extends AWSTreeNodeBase implements AWSResourceNode {
+    public constructor(
+        public readonly parent: AWSTreeNodeBase,
+        private readonly lambda: LambdaClient,
+        public readonly layer: Lambda.LayersListItem
+    ) {
+        super(layer.LayerName ?? '', vscode.TreeItemCollapsibleState.Collapsed)
+        this.tooltip = layer.LayerArn
+        this.contextValue = 'awsLambdaLayerNode'
+    }
+
+    public get layerName(): string {
+        return this.layer.LayerName ?? ''
+    }
+
+    public get arn(): string {
+        return this.layer.LayerArn ?? ''
+    }
+
+    public get name(): string {
+        return this.layerName
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const versions = await toArrayAsync(this.lambda.listLayerVersions(this.layerName))
+
+                return versions.map(
+                    version =>
+                        new LambdaLayerVersionNode(this, this.lambda, version.LayerVersionArn!, {
+                            label: localize('AWS.explorerNode.lambda.layerVersion', 'Version {0}', version.Version),
+                            description: version.Description,
+                        })
+                )
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.lambda.noLayerVersions', '[No versions found]')),
+            // newest version first
+            sort: (nodeA: LambdaLayerVersionNode, nodeB: LambdaLayerVersionNode) => nodeB.version - nodeA.version,
+        })
+    }
+}
+
+/**
+ * A version of a Lambda layer. Versions shared by other accounts are read-only: they can be viewed and
+ * downloaded, but not deleted.
+ */
+export class LambdaLayerVersionNode extends AWSTreeNodeBase implements AWSResourceNode {
+    public readonly layerName: string
+    public readonly version: number
+    public readonly accountId: string
+
+    public constructor(
+        public readonly parent: AWSTreeNodeBase,
+        public readonly lambda: LambdaClient,
+        public readonly arn: string,
+        { label, description, readOnly = false }: { label?: string; description?: string; readOnly?: boolean } = {}
+    ) {
+        super('')
+        const parsed = parseLayerVersionArn(arn)
+        this.layerName = parsed?.layerName ?? arn
+        this.version = parsed?.version ?? 0
+        this.accountId = parsed?.accountId ?? ''
+        this.label = label ?? `${this.layerName}:${this.version}`
+        this.description = description
+        this.tooltip = arn
+        this.contextValue = readOnly ? CONTEXT_VALUE_LAYER_VERSION_READ_ONLY : CONTEXT_VALUE_LAYER_VERSION
+    }
+
+    public get name(): string {
+        return `${this.layerName}:${this.version}`
+    }
+
+    public get readOnly(): boolean {
+        return this.contextValue === CONTEXT_VALUE_LAYER_VERSION_READ_ONLY
+    }
+}
+
+/**
+ * Makes nodes for the layers a function references, which offer the same actions as the corresponding nodes
+ * under "Lambda Layers". Layers owned by other accounts are read-only.
+ *
+ * @param accountId Account that owns the function. If unknown, layers are assumed to be owned by it.
+ */
+export function makeLayerReferenceNodes(
+    parent: AWSTreeNodeBase,
+    lambda: LambdaClient,
+    layers: Lambda.Layer[],
+    accountId: string | undefined
+): LambdaLayerVersionNode[] {
+    return layers
+        .filter(layer => layer.Arn)
+        .map(layer => {
+            const owner = parseLayerVersionArn(layer.Arn!)?.accountId
+            const readOnly = !!accountId && !!owner && owner !== accountId
+
+            return new LambdaLayerVersionNode(parent, lambda, layer.Arn!, {
+                description: readOnly
+                    ? localize('AWS.explorerNode.lambda.sharedLayer', 'Shared by account {0}', owner)
+                    : undefined,
+                readOnly,
+            })
+        })
+}
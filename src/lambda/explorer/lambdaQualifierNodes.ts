@@ -0,0 +1,147 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { Lambda } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { LambdaFunctionNode } from './lambdaFunctionNode'
+
+export const LATEST_VERSION = '$LATEST'
+
+/**
+ * Describes the versions an alias routes traffic to, with their weights if it routes to more than one,
+ * e.g. `3 (90%), 4 (10%)`.
+ */
+export function describeAliasRouting(alias: Lambda.AliasConfiguration): string {
+    const weights = alias.RoutingConfig?.AdditionalVersionWeights ?? {}
+    const additionalVersions = Object.keys(weights)
+    if (additionalVersions.length === 0) {
+        return alias.FunctionVersion ?? ''
+    }
+
+    const additionalWeight = additionalVersions.reduce((sum, version) => sum + weights[version], 0)
+
+    return [
+        `${alias.FunctionVersion} (${toPercent(1 - additionalWeight)}%)`,
+        ...additionalVersions.map(version => `${version} (${toPercent(weights[version])}%)`),
+    ].join(', ')
+}
+
+function toPercent(weight: number): number {
+    // weights have at most 5 decimal places, e.g. 0.00001
+    return Math.round(weight * 100000) / 1000
+}
+
+/**
+ * The published versions of a function, newest first.
+ */
+export class LambdaVersionsNode extends AWSTreeNodeBase {
+    public constructor(public readonly parent: LambdaFunctionNode, private readonly lambda: LambdaClient) {
+        super(localize('AWS.explorerNode.lambda.versions', 'Versions'), vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsLambdaVersionsNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const versions = await listPublishedVersions(this.lambda, this.parent.functionName)
+
+                return versions.map(version => new LambdaVersionNode(this.parent, this, version))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(
+                    this,
+                    localize('AWS.explorerNode.lambda.noVersions', '[No published versions found]')
+                ),
+            // newest version first
+            sort: (nodeA: LambdaVersionNode, nodeB: LambdaVersionNode) =>
+                Number(nodeB.qualifier) - Number(nodeA.qualifier),
+        })
+    }
+}
+
+/**
+ * Lists the versions of a function that were published, i.e. all but `$LATEST`.
+ */
+export async function listPublishedVersions(
+    lambda: LambdaClient,
+    functionName: string
+): Promise<Lambda.FunctionConfiguration[]> {
+    const versions = await toArrayAsync(lambda.listVersions(functionName))
+
+    return versions.filter(version => version.Version !== LATEST_VERSION)
+}
+
+/**
+ * A published version of a function, which can be invoked.
+ */
+export class LambdaVersionNode extends AWSTreeNodeBase {
+    public readonly qualifier: string
+
+    public constructor(
+        public readonly functionNode: LambdaFunctionNode,
+        public readonly parent: LambdaVersionsNode,
+        public readonly configuration: Lambda.FunctionConfiguration
+    ) {
+        super(
+            localize('AWS.explorerNode.lambda.version', 'Version {0}', configuration.Version),
+            vscode.TreeItemCollapsibleState.None
+        )
+        this.qualifier = configuration.Version ?? ''
+        this.description = configuration.Description
+        this.tooltip = configuration.FunctionArn
+        this.contextValue = 'awsLambdaVersionNode'
+    }
+}
+
+/**
+ * The aliases of a function, sorted by name.
+ */
+export class LambdaAliasesNode extends AWSTreeNodeBase {
+    public constructor(public readonly parent: LambdaFunctionNode, private readonly lambda: LambdaClient) {
+        super(localize('AWS.explorerNode.lambda.aliases', 'Aliases'), vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsLambdaAliasesNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const aliases = await toArrayAsync(this.lambda.listAliases(this.parent.functionName))
+
+                return aliases.map(alias => new LambdaAliasNode(this.parent, this, alias))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.lambda.noAliases', '[No aliases found]')),
+            sort: (nodeA: LambdaAliasNode, nodeB: LambdaAliasNode) => nodeA.qualifier.localeCompare(nodeB.qualifier),
+        })
+    }
+}
+
+/**
+ * An alias of a function, labeled with the versions it routes traffic to.
+ */
+export class LambdaAliasNode extends AWSTreeNodeBase {
+    public readonly qualifier: string
+
+    public constructor(
+        public readonly functionNode: LambdaFunctionNode,
+        public readonly parent: LambdaAliasesNode,
+        public readonly alias: Lambda.AliasConfiguration
+    ) {
+        super(`${alias.Name} → ${describeAliasRouting(alias)}`, vscode.TreeItemCollapsibleState.None)
+        this.qualifier = alias.Name ?? ''
+        this.description = alias.Description
+        this.tooltip = alias.AliasArn
+        this.contextValue = 'awsLambdaAliasNode'
+    }
+}
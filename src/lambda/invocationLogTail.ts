@@ -0,0 +1,144 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CloudWatchLogs, Lambda } from 'aws-sdk'
+import * as moment from 'moment'
+import { CloudWatchLogsClient } from '../shared/clients/cloudWatchLogsClient'
+import { INSIGHTS_TIMESTAMP_FORMAT } from '../shared/constants'
+
+/** Upper bound for the number of `FilterLogEvents` pages requested per poll. */
+const MAX_PAGES_PER_POLL = 10
+
+const REQUEST_ID_PATTERN = /^(?:START|END|REPORT) RequestId: ([0-9a-fA-F-]+)/m
+
+/**
+ * Finds the request ID of an invocation, from the `START`/`REPORT` lines of the log tail returned by the invoke.
+ */
+export function getRequestId(response: Lambda.InvocationResponse): string | undefined {
+    if (response.LogResult) {
+        const logs = Buffer.from(response.LogResult, 'base64').toString()
+        const match = REQUEST_ID_PATTERN.exec(logs)
+        if (match) {
+            return match[1]
+        }
+    }
+
+    // The request ID of a synchronous invoke is also the ID of the invocation
+    return (response as { $response?: { requestId?: string } }).$response?.requestId
+}
+
+/**
+ * Collects the events logged to a Lambda function's log group since an invocation started, and finds the
+ * events of that invocation by its request ID. Each {@link poll} requests new events with `FilterLogEvents`;
+ * Lambda logs can take a few seconds to be delivered.
+ */
+export class InvocationLogTail {
+    private readonly events: CloudWatchLogs.FilteredLogEvent[] = []
+    private readonly seenEventIds = new Set<string>()
+    private requestId: string | undefined
+
+    public constructor(
+        private readonly client: CloudWatchLogsClient,
+        public readonly logGroupName: string,
+        private startTime: number
+    ) {}
+
+    public setRequestId(requestId: string): void {
+        this.requestId = requestId
+    }
+
+    /**
+     * Requests the events logged since the last poll.
+     *
+     * @returns true if there are new events
+     */
+    public async poll(): Promise<boolean> {
+        const added: CloudWatchLogs.FilteredLogEvent[] = []
+        let nextToken: string | undefined
+        let pages = 0
+
+        do {
+            let response: CloudWatchLogs.FilterLogEventsResponse
+            try {
+                response = await this.client.filterLogEvents({
+                    logGroupName: this.logGroupName,
+                    startTime: this.startTime,
+                    nextToken,
+                })
+            } catch (err) {
+                // The log group is created on the first invoke of a function
+                if ((err as { code?: string }).code === 'ResourceNotFoundException') {
+                    return false
+                }
+                throw err
+            }
+
+            for (const event of response.events ?? []) {
+                if (event.eventId && !this.seenEventIds.has(event.eventId)) {
+                    this.seenEventIds.add(event.eventId)
+                    added.push(event)
+                }
+            }
+            nextToken = response.nextToken
+        } while (nextToken && ++pages < MAX_PAGES_PER_POLL)
+
+        if (added.length === 0) {
+            return false
+        }
+
+        this.events.push(...added)
+        this.events.sort((a, b) => (a.timestamp ?? 0) - (b.timestamp ?? 0))
+        // Events with the same timestamp as the latest one are requested again, and skipped by their ID
+        this.startTime = Math.max(this.startTime, ...added.map(event => event.timestamp ?? 0))
+
+        return true
+    }
+
+    /**
+     * True once the `REPORT` line of the invocation has been logged; the invocation logs nothing after it.
+     */
+    public isRequestComplete(): boolean {
+        return this.findEvent('REPORT') !== undefined
+    }
+
+    /**
+     * Returns the line of {@link getContent} where the invocation starts, if it has been logged yet.
+     */
+    public getRequestStartLine(): number | undefined {
+        const start = this.findEvent('START')
+        if (start === undefined) {
+            return undefined
+        }
+
+        return this.events
+            .slice(0, start)
+            .reduce((lines, event) => lines + formatLogEvent(event).split('\n').length - 1, 0)
+    }
+
+    public getContent(): string {
+        return this.events.map(formatLogEvent).join('')
+    }
+
+    private findEvent(kind: 'START' | 'REPORT'): number | undefined {
+        if (!this.requestId) {
+            return undefined
+        }
+        const prefix = `${kind} RequestId: ${this.requestId}`
+        const index = this.events.findIndex(event => event.message?.startsWith(prefix))
+
+        return index === -1 ? undefined : index
+    }
+}
+
+/**
+ * Formats an event like the CloudWatch Logs viewer does, with continuation lines indented past the timestamp.
+ */
+export function formatLogEvent(event: CloudWatchLogs.FilteredLogEvent): string {
+    const timestamp = event.timestamp ? moment(event.timestamp).format(INSIGHTS_TIMESTAMP_FORMAT) : ''
+    const indent = ' '.repeat(timestamp.length)
+    const message = (event.message ?? '').replace(/(\r\n|\n|\r)$/, '').replace(/\r\n|\n|\r/g, `\n${indent}\t`)
+
+    return `${timestamp}\t${message}\n`
+}
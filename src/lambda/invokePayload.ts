@@ -0,0 +1,127 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { _Blob } from 'aws-sdk/clients/lambda'
+import * as fs from 'fs-extra'
+import * as _ from 'lodash'
+import { localize } from '../shared/utilities/vsCodeUtils'
+
+/**
+ * How the payload entered in the invoke form is sent:
+ * - `json`: validated as JSON, and sent as it was entered
+ * - `text`: sent as it was entered, e.g. a form-encoded body
+ * - `base64`: decoded, and sent as the decoded bytes
+ * - `file`: the bytes of a local file
+ */
+export type InvokePayloadType = 'json' | 'text' | 'base64' | 'file'
+
+/** Hex views of larger responses are cut off, since they are three times the size of the response. */
+export const MAX_HEX_VIEW_BYTES = 64 * 1024
+
+const HEX_BYTES_PER_LINE = 16
+
+export interface FormattedPayload {
+    /** Whether the payload is binary rather than UTF-8 text. */
+    readonly binary: boolean
+    readonly sizeBytes: number
+    /** The payload as UTF-8 text, or undefined if it is binary. */
+    readonly text?: string
+    readonly base64: string
+    /** A hex dump of (up to {@link MAX_HEX_VIEW_BYTES} of) the payload, with the offset of each line. */
+    readonly hex: string
+}
+
+/**
+ * Makes the payload to invoke a function with, without JSON-quoting payloads that aren't JSON.
+ *
+ * @param value the payload as entered in the form, or the path of the file for `file` payloads
+ * @throws Error if the payload isn't valid for its type, or if the file can't be read
+ */
+export async function toInvokePayload(type: InvokePayloadType, value: string): Promise<_Blob> {
+    switch (type) {
+        case 'json':
+            try {
+                JSON.parse(value)
+            } catch (err) {
+                throw new Error(
+                    localize(
+                        'AWS.lambda.invoke.invalidJson',
+                        'Payload is not valid JSON: {0}. Choose "Raw text" to send it as it is.',
+                        (err as Error).message
+                    )
+                )
+            }
+            return value
+        case 'text':
+            return value
+        case 'base64': {
+            const base64 = value.replace(/\s/g, '')
+            if (!/^[A-Za-z0-9+/]*={0,2}$/.test(base64) || base64.length % 4 === 1) {
+                throw new Error(localize('AWS.lambda.invoke.invalidBase64', 'Payload is not valid base64'))
+            }
+            return Buffer.from(base64, 'base64')
+        }
+        case 'file':
+            if (!value) {
+                throw new Error(localize('AWS.lambda.invoke.noFile', 'Choose a file to send as the payload'))
+            }
+            return fs.readFile(value)
+    }
+}
+
+/**
+ * Whether a payload is binary: it has a NUL byte, or isn't valid UTF-8.
+ */
+export function isBinaryPayload(payload: Buffer): boolean {
+    return payload.includes(0) || !Buffer.from(payload.toString('utf8'), 'utf8').equals(payload)
+}
+
+/**
+ * Formats a payload as hex, e.g. `00000000  7b 22 61 22 3a 31 7d`.
+ */
+export function toHexView(payload: Buffer): string {
+    const lines: string[] = []
+    const end = Math.min(payload.length, MAX_HEX_VIEW_BYTES)
+    for (let offset = 0; offset < end; offset += HEX_BYTES_PER_LINE) {
+        const bytes = Array.from(payload.subarray(offset, Math.min(offset + HEX_BYTES_PER_LINE, end)))
+        const hex = bytes.map(byte => _.padStart(byte.toString(16), 2, '0')).join(' ')
+        lines.push(`${_.padStart(offset.toString(16), 8, '0')}  ${hex}`)
+    }
+    if (payload.length > end) {
+        lines.push(
+            localize(
+                'AWS.lambda.invoke.hexTruncated',
+                '... {0} more bytes. Use the base64 view to see the whole response.',
+                payload.length - end
+            )
+        )
+    }
+
+    return lines.join('\n')
+}
+
+/**
+ * Formats the payload of an invoke response for display as text, base64, and hex.
+ */
+export function formatResponsePayload(payload: _Blob | undefined): FormattedPayload {
+    const bytes = toBuffer(payload ?? '')
+    const binary = isBinaryPayload(bytes)
+
+    return {
+        binary,
+        sizeBytes: bytes.length,
+        text: binary ? undefined : bytes.toString('utf8'),
+        base64: bytes.toString('base64'),
+        hex: toHexView(bytes),
+    }
+}
+
+function toBuffer(payload: _Blob): Buffer {
+    if (typeof payload === 'string') {
+        return Buffer.from(payload, 'utf8')
+    }
+
+    return Buffer.from(payload as Uint8Array)
+}
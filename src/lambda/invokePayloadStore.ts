@@ -0,0 +1,70 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as fs from 'fs-extra'
+import * as vscode from 'vscode'
+import { getLogger } from '../shared/logger'
+import { InvokePayloadType } from './invokePayload'
+
+export interface SavedInvokePayload {
+    /** Inline copy of the payload, used when `filePath` is no longer available. Empty for `file` payloads. */
+    readonly json: string
+    /** Local file the payload was loaded from, if any. */
+    readonly filePath?: string
+    /** How the payload is sent. Payloads saved without a type are JSON. */
+    readonly payloadType?: InvokePayloadType
+}
+
+interface SavedInvokePayloads {
+    [accountId: string]: { [functionArn: string]: SavedInvokePayload } | undefined
+}
+
+/**
+ * Remembers the last payload used to invoke a remote Lambda function.
+ *
+ * Payloads are keyed by function ARN and scoped to the account that invoked them, so payloads from one account
+ * are not offered in another, whichever credentials are used to connect to it.
+ */
+export class InvokePayloadStore {
+    private static readonly stateKey = 'lambdaLastInvokePayloads'
+
+    public constructor(private readonly memento: vscode.Memento) {}
+
+    public get(accountId: string, functionArn: string): SavedInvokePayload | undefined {
+        return this.getAll()[accountId]?.[functionArn]
+    }
+
+    public async set(accountId: string, functionArn: string, payload: SavedInvokePayload): Promise<void> {
+        const all = this.getAll()
+        all[accountId] = { ...all[accountId], [functionArn]: payload }
+
+        await this.memento.update(InvokePayloadStore.stateKey, all)
+    }
+
+    /**
+     * Returns the contents of the last payload for a function, preferring the
+     * file it was loaded from and falling back to the inline copy if the file
+     * has since been deleted.
+     */
+    public async load(accountId: string, functionArn: string): Promise<string | undefined> {
+        const saved = this.get(accountId, functionArn)
+        if (!saved) {
+            return undefined
+        }
+        if (saved.filePath && (await fs.pathExists(saved.filePath))) {
+            try {
+                return await fs.readFile(saved.filePath, 'utf8')
+            } catch (err) {
+                getLogger().warn(`Could not read saved payload file ${saved.filePath}: %O`, err as Error)
+            }
+        }
+
+        return saved.json
+    }
+
+    private getAll(): SavedInvokePayloads {
+        return { ...this.memento.get<SavedInvokePayloads>(InvokePayloadStore.stateKey, {}) }
+    }
+}
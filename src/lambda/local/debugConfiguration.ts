@@ -234,3 +234,18 @@ export function isImageLambdaConfig(config: SamLaunchRequestArgs): boolean {
 
     return CloudFormation.isImageLambdaResource(templateResource?.Properties)
 }
+
+/**
+ * Checks if the current configuration should be treated as a response streaming Lambda.
+ *
+ * True if the user set `lambda.streamingResponse`, or if the template resource declares
+ * `FunctionUrlConfig.InvokeMode: RESPONSE_STREAM`.
+ */
+export function isStreamingResponseConfig(config: SamLaunchRequestArgs): boolean {
+    if (config.lambda?.streamingResponse !== undefined) {
+        return config.lambda.streamingResponse
+    }
+    const templateResource = getTemplateResource(config.workspaceFolder, config)
+
+    return CloudFormation.isStreamingLambdaResource(templateResource?.Properties)
+}
@@ -0,0 +1,83 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+const SAMPLE_ACCOUNT = '123456789012'
+const SAMPLE_REGION = 'us-east-1'
+const SAMPLE_TIME = '1970-01-01T00:00:00Z'
+
+export interface BundledSampleRequest {
+    name: string
+    filename: string
+    payload: object
+}
+
+/**
+ * Builds the envelope that EventBridge wraps around events delivered to a Lambda function.
+ */
+export function makeEventBridgeEvent({
+    source,
+    detailType,
+    resources = [],
+    detail = {},
+}: {
+    source: string
+    detailType: string
+    resources?: string[]
+    detail?: object
+}): object {
+    return {
+        version: '0',
+        id: '12345678-1234-1234-1234-123456789012',
+        'detail-type': detailType,
+        source,
+        account: SAMPLE_ACCOUNT,
+        time: SAMPLE_TIME,
+        region: SAMPLE_REGION,
+        resources,
+        detail,
+    }
+}
+
+/**
+ * EventBridge sample payloads that the hosted sample request manifest does not provide. They are listed
+ * alongside the hosted samples, but served from here.
+ */
+export const EVENTBRIDGE_SAMPLE_REQUESTS: BundledSampleRequest[] = [
+    {
+        // Scheduler passes the input of the schedule through as-is, without an EventBridge envelope
+        name: 'EventBridge Scheduler (Schedule Input)',
+        filename: 'EventBridgeScheduler.json',
+        payload: { key1: 'value1' },
+    },
+    {
+        name: 'EventBridge Rule (Schedule)',
+        filename: 'EventBridgeRuleSchedule.json',
+        payload: makeEventBridgeEvent({
+            source: 'aws.events',
+            detailType: 'Scheduled Event',
+            resources: [`arn:aws:events:${SAMPLE_REGION}:${SAMPLE_ACCOUNT}:rule/my-schedule-rule`],
+        }),
+    },
+    {
+        name: 'EventBridge Rule (Custom Event)',
+        filename: 'EventBridgeRuleCustomEvent.json',
+        payload: makeEventBridgeEvent({
+            source: 'com.mycompany.myapp',
+            detailType: 'MyDetailType',
+            resources: [`arn:aws:events:${SAMPLE_REGION}:${SAMPLE_ACCOUNT}:rule/my-rule`],
+            detail: { key1: 'value1' },
+        }),
+    },
+    {
+        name: 'EventBridge Rule (AWS Service Event)',
+        filename: 'EventBridgeRuleServiceEvent.json',
+        payload: makeEventBridgeEvent({
+            source: 'aws.ec2',
+            detailType: 'EC2 Instance State-change Notification',
+            resources: [`arn:aws:ec2:${SAMPLE_REGION}:${SAMPLE_ACCOUNT}:instance/i-1234567890abcdef0`],
+            detail: { 'instance-id': 'i-1234567890abcdef0', state: 'running' },
+        }),
+    },
+]
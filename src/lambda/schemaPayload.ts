@@ -0,0 +1,341 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { load } from 'js-yaml'
+import { localize } from '../shared/utilities/vsCodeUtils'
+
+/** How deep nested (and recursive) schemas are expanded before they are left out of the sample. */
+const MAX_DEPTH = 10
+
+const HTTP_METHODS = ['get', 'put', 'post', 'delete', 'options', 'head', 'patch', 'trace']
+
+/** EventBridge schemas name the schema of the whole event `AWSEvent`. */
+const EVENTBRIDGE_ROOT_SCHEMA = 'AWSEvent'
+
+/**
+ * Something in a schema document that a payload can be generated from: the request body of an operation, or a
+ * schema.
+ */
+export interface PayloadSource {
+    /** e.g. `POST /pets`, or the name of the schema */
+    readonly label: string
+    readonly detail?: string
+    readonly schema?: any
+    /** An example given by the document, which is used as it is. */
+    readonly example?: unknown
+}
+
+export interface GeneratedPayload {
+    readonly payload: unknown
+    /** Problems in the document that were worked around, e.g. references that could not be resolved. */
+    readonly warnings: string[]
+}
+
+/**
+ * Parses an OpenAPI (JSON or YAML) or JSON Schema document.
+ *
+ * @throws Error if the document is neither JSON nor YAML, or isn't an object
+ */
+export function parseSchemaDocument(text: string): any {
+    let document: unknown
+    try {
+        document = load(text)
+    } catch (err) {
+        throw new Error(
+            localize(
+                'AWS.lambda.invoke.schema.invalid',
+                'Schema is not valid JSON or YAML: {0}',
+                (err as Error).message
+            )
+        )
+    }
+    if (!isObject(document)) {
+        throw new Error(
+            localize('AWS.lambda.invoke.schema.notObject', 'Schema is not an OpenAPI or JSON Schema document')
+        )
+    }
+
+    return document
+}
+
+/**
+ * Lists what payloads can be generated from:
+ * - the request bodies of the operations of an OpenAPI 3 or Swagger 2 document
+ * - otherwise, the schemas of an OpenAPI document, such as an EventBridge schema, with the event schema first
+ * - otherwise, the document itself, as a JSON Schema
+ */
+export function getPayloadSources(document: any): PayloadSource[] {
+    const requestBodies = isObject(document.paths) ? listRequestBodies(document) : []
+    if (requestBodies.length > 0) {
+        return requestBodies
+    }
+
+    const schemas = document.components?.schemas ?? (document.swagger ? document.definitions : undefined)
+    if (isObject(schemas)) {
+        const names = Object.keys(schemas).sort((a, b) =>
+            a === EVENTBRIDGE_ROOT_SCHEMA ? -1 : b === EVENTBRIDGE_ROOT_SCHEMA ? 1 : 0
+        )
+        return names.map(name => ({ label: name, detail: schemas[name]?.description, schema: schemas[name] }))
+    }
+
+    if (document.openapi || document.swagger) {
+        return []
+    }
+
+    return [{ label: document.title ?? localize('AWS.lambda.invoke.schema.label', 'Schema'), schema: document }]
+}
+
+/**
+ * Generates a payload from a source of a document: the example the document gives for it, or else a sample that
+ * follows its schema. Values come from the `example`, `examples`, `default`, `const` or `enum` of each schema where
+ * there are any, and are placeholders of the schema's type otherwise.
+ *
+ * References within the document are resolved. References that can't be resolved are warned about, and left
+ * as empty objects.
+ */
+export function generatePayload(document: any, source: PayloadSource): GeneratedPayload {
+    if (source.example !== undefined) {
+        return { payload: source.example, warnings: [] }
+    }
+
+    const generator = new SampleGenerator(document)
+    const payload = generator.generate(source.schema)
+
+    return { payload: payload === undefined ? {} : payload, warnings: generator.warnings }
+}
+
+function listRequestBodies(document: any): PayloadSource[] {
+    const sources: PayloadSource[] = []
+    const resolver = new SampleGenerator(document)
+
+    for (const [path, pathItem] of Object.entries<any>(document.paths)) {
+        if (!isObject(pathItem)) {
+            continue
+        }
+        for (const method of HTTP_METHODS) {
+            const operation = pathItem[method]
+            if (!isObject(operation)) {
+                continue
+            }
+            const label = `${method.toUpperCase()} ${path}`
+            const detail = operation.summary ?? operation.operationId
+
+            if (operation.requestBody !== undefined) {
+                const requestBody = resolver.dereference(operation.requestBody)
+                const media = pickMediaType(requestBody?.content)
+                if (media) {
+                    sources.push({ label, detail, schema: media.schema, example: getMediaExample(media, resolver) })
+                }
+                continue
+            }
+
+            // Swagger 2 sends the body as a parameter
+            const parameters = [...(pathItem.parameters ?? []), ...(operation.parameters ?? [])]
+            const body = parameters.map(parameter => resolver.dereference(parameter)).find(p => p?.in === 'body')
+            if (body) {
+                sources.push({ label, detail, schema: body.schema })
+            }
+        }
+    }
+
+    return sources
+}
+
+/**
+ * Prefers JSON request bodies, since payloads of other types can't be generated from a schema.
+ */
+function pickMediaType(content: any): any {
+    if (!isObject(content)) {
+        return undefined
+    }
+    const types = Object.keys(content)
+    const type =
+        types.find(t => t === 'application/json') ?? types.find(t => /[/+]json\b/.test(t)) ?? types.find(() => true)
+
+    return type !== undefined ? content[type] : undefined
+}
+
+function getMediaExample(media: any, resolver: SampleGenerator): unknown {
+    if (!isObject(media)) {
+        return undefined
+    }
+    if (media.example !== undefined) {
+        return media.example
+    }
+    if (isObject(media.examples)) {
+        const first = Object.values(media.examples).find(example => resolver.dereference(example)?.value !== undefined)
+        return first !== undefined ? resolver.dereference(first).value : undefined
+    }
+
+    return undefined
+}
+
+class SampleGenerator {
+    public readonly warnings: string[] = []
+
+    public constructor(private readonly document: any) {}
+
+    /**
+     * @returns a sample of the schema, or undefined for a recursive schema that is nested too deeply to expand
+     */
+    public generate(schema: any, depth: number = 0, refs: string[] = []): unknown {
+        if (!isObject(schema)) {
+            return {}
+        }
+        if (depth > MAX_DEPTH) {
+            return undefined
+        }
+
+        if (typeof schema.$ref === 'string') {
+            const ref = schema.$ref
+            if (refs.includes(ref)) {
+                return undefined
+            }
+            const resolved = this.resolve(ref)
+            return resolved === undefined ? {} : this.generate(resolved, depth + 1, [...refs, ref])
+        }
+
+        if (schema.example !== undefined) {
+            return schema.example
+        }
+        if (Array.isArray(schema.examples) && schema.examples.length > 0) {
+            return schema.examples[0]
+        }
+        if (schema.default !== undefined) {
+            return schema.default
+        }
+        if (schema.const !== undefined) {
+            return schema.const
+        }
+        if (Array.isArray(schema.enum) && schema.enum.length > 0) {
+            return schema.enum[0]
+        }
+
+        if (Array.isArray(schema.allOf) && schema.allOf.length > 0) {
+            const { allOf, ...rest } = schema
+            const parts = [...allOf, ...(Object.keys(rest).length > 0 ? [rest] : [])]
+                .map(part => this.generate(part, depth + 1, refs))
+                .filter(part => part !== undefined)
+            return parts.every(isObject) ? Object.assign({}, ...parts) : parts[0]
+        }
+
+        const alternatives = schema.oneOf ?? schema.anyOf
+        if (Array.isArray(alternatives) && alternatives.length > 0) {
+            return this.generate(alternatives[0], depth + 1, refs)
+        }
+
+        return this.generateType(schema, depth, refs)
+    }
+
+    /**
+     * Resolves a schema, request body, etc. that may be a reference.
+     */
+    public dereference(value: any): any {
+        const seen: string[] = []
+        while (isObject(value) && typeof value.$ref === 'string' && !seen.includes(value.$ref)) {
+            seen.push(value.$ref)
+            value = this.resolve(value.$ref)
+        }
+
+        return value
+    }
+
+    private generateType(schema: any, depth: number, refs: string[]): unknown {
+        const type = Array.isArray(schema.type)
+            ? schema.type.find((t: string) => t !== 'null') ?? 'null'
+            : schema.type ?? (schema.properties ? 'object' : schema.items ? 'array' : undefined)
+
+        switch (type) {
+            case 'object': {
+                const sample: { [property: string]: unknown } = {}
+                for (const [name, property] of Object.entries<any>(schema.properties ?? {})) {
+                    // read-only properties are only sent in responses
+                    if (property?.readOnly) {
+                        continue
+                    }
+                    const value = this.generate(property, depth + 1, refs)
+                    if (value !== undefined) {
+                        sample[name] = value
+                    }
+                }
+                return sample
+            }
+            case 'array': {
+                const items = Array.isArray(schema.items) ? schema.items : [schema.items]
+                return items
+                    .filter(isObject)
+                    .map(item => this.generate(item, depth + 1, refs))
+                    .filter(item => item !== undefined)
+            }
+            case 'string':
+                return sampleString(schema.format)
+            case 'integer':
+            case 'number':
+                return typeof schema.minimum === 'number' ? schema.minimum : 0
+            case 'boolean':
+                return false
+            case 'null':
+                return null
+            default:
+                return {}
+        }
+    }
+
+    private resolve(ref: string): any {
+        if (!ref.startsWith('#')) {
+            this.warnings.push(
+                localize(
+                    'AWS.lambda.invoke.schema.externalRef',
+                    'References to other documents are not supported: {0}',
+                    ref
+                )
+            )
+            return undefined
+        }
+
+        let value = this.document
+        for (const token of ref.substring(1).split('/').slice(1)) {
+            const key = decodeURIComponent(token).replace(/~1/g, '/').replace(/~0/g, '~')
+            value = isObject(value) || Array.isArray(value) ? (value as any)[key] : undefined
+        }
+        if (value === undefined) {
+            this.warnings.push(
+                localize('AWS.lambda.invoke.schema.unresolvedRef', 'Could not resolve reference: {0}', ref)
+            )
+        }
+
+        return value
+    }
+}
+
+function sampleString(format: string | undefined): string {
+    switch (format) {
+        case 'date-time':
+            return '2021-01-01T00:00:00Z'
+        case 'date':
+            return '2021-01-01'
+        case 'time':
+            return '00:00:00Z'
+        case 'email':
+            return 'user@example.com'
+        case 'uuid':
+            return '00000000-0000-0000-0000-000000000000'
+        case 'uri':
+        case 'url':
+            return 'https://example.com'
+        case 'hostname':
+            return 'example.com'
+        case 'ipv4':
+            return '192.0.2.0'
+        case 'byte':
+            return ''
+        default:
+            return 'string'
+    }
+}
+
+function isObject(value: unknown): value is { [key: string]: any } {
+    return typeof value === 'object' && value !== null && !Array.isArray(value)
+}
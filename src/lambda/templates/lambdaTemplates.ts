@@ -18,29 +18,75 @@ export class LambdaTemplates {
         </p>
         
         <h3>
-            Select a file to use as payload:
+            Payload type:
         </h3>
-        <input type="file" @change="processFile($event)">
-        <br />
-        <h3>
-            Or, use a sample request payload from a template:
-        </h3>
-        <select v-model="selectedSampleRequest" v-on:change="newSelection">
-            <option disabled value="">Select an example input</option>
-            <% InputSamples.forEach(function(el) { %>
-                <option value="<%= el.filename %>"><%= el.name %></option>
-            <% }); %>
+        <select v-model="payloadType">
+            <option value="json">JSON</option>
+            <option value="text">Raw text</option>
+            <option value="base64">Base64 binary</option>
+            <option value="file">File</option>
         </select>
+        <div v-if="payloadType === 'file'">
+            <h3>
+                Select a file to send as the payload:
+            </h3>
+            <button v-on:click="promptForFile">Choose file...</button>
+            <span>{{ payloadFile }}</span>
+        </div>
+        <div v-else>
+            <h3>
+                Select a file to use as payload:
+            </h3>
+            <button v-on:click="promptForFile">Choose file...</button>
+            <span>{{ selectedFile }}</span>
+            <br />
+            <h3>
+                Or, use a sample request payload from a template:
+            </h3>
+            <select v-model="selectedSampleRequest" v-on:change="newSelection">
+                <option disabled value="">Select an example input</option>
+                <% InputSamples.forEach(function(el) { %>
+                    <option value="<%= el.filename %>"><%= el.name %></option>
+                <% }); %>
+            </select>
+            <br />
+            <h3>
+                Or, generate a request body from an OpenAPI or JSON Schema document:
+            </h3>
+            <button v-on:click="generateFromSchemaFile">Choose schema file...</button>
+            <button v-on:click="generateFromRegistrySchema">Choose from schema registry...</button>
+            <br />
+            <br />
+            <textarea
+                rows="20"
+                cols="90"
+                v-model="sampleText"
+            ></textarea>
+        </div>
         <br />
+        <input type="submit" v-on:click="sendInput" value="Invoke" :disabled="isLoading">
+        <input type="submit" v-on:click="retryLastPayload" value="Retry with last payload" v-if="hasLastPayload" :disabled="isLoading">
         <br />
-        <textarea
-            rows="20"
-            cols="90"
-            v-model="sampleText"
-        ></textarea>
-        <br />
-        <input type="submit" v-on:click="sendInput" value="Invoke">
-        <br />
+        <div v-if="showResponse">
+            <h3>
+                Response:
+            </h3>
+            <p v-if="error" style="color: var(--vscode-errorForeground)">{{ error }}</p>
+            <div v-else>
+                <p>Status code: {{ statusCode }}</p>
+                <select v-model="responseView">
+                    <option value="text" :disabled="payload.binary">Text</option>
+                    <option value="base64">Base64</option>
+                    <option value="hex">Hex</option>
+                </select>
+                <span v-if="payload.binary">Binary response of {{ payload.sizeBytes }} bytes</span>
+                <pre>{{ payload[responseView] }}</pre>
+                <h3>
+                    Logs:
+                </h3>
+                <pre>{{ logs }}</pre>
+            </div>
+        </div>
     </div>
     <% Libraries.forEach(function(lib) { %>
         <script src="<%= lib %>"></script>
@@ -18,7 +18,8 @@ import { CompositeResourceFetcher } from '../shared/resourcefetcher/compositeRes
 import { HttpResourceFetcher } from '../shared/resourcefetcher/httpResourceFetcher'
 import { FileResourceFetcher } from '../shared/resourcefetcher/fileResourceFetcher'
 import { ext } from '../shared/extensionGlobals'
-import { sampleRequestManifestPath } from './constants'
+import { sampleRequestManifestPath, sampleRequestPath } from './constants'
+import { EVENTBRIDGE_SAMPLE_REQUESTS } from './models/eventBridgeSampleRequests'
 
 export async function* listCloudFormationStacks(
     client: CloudFormationClient
@@ -111,10 +112,25 @@ export async function getSampleLambdaPayloads(): Promise<SampleRequest[]> {
 
         inputs.push(...result.requests.request)
     })
+    inputs.push(...EVENTBRIDGE_SAMPLE_REQUESTS.map(({ name, filename }) => ({ name, filename })))
 
     return inputs
 }
 
+/**
+ * Gets the payload of a sample request listed by {@link getSampleLambdaPayloads}.
+ */
+export async function getSampleLambdaPayload(filename: string): Promise<string> {
+    const bundledSample = EVENTBRIDGE_SAMPLE_REQUESTS.find(sample => sample.filename === filename)
+    if (bundledSample) {
+        return JSON.stringify(bundledSample.payload, undefined, 4)
+    }
+
+    const sampleUrl = `${sampleRequestPath}${filename}`
+
+    return (await new HttpResourceFetcher(sampleUrl, { showUrl: true }).get()) ?? ''
+}
+
 function makeSampleRequestManifestResourceFetcher(): ResourceFetcher {
     return new CompositeResourceFetcher(
         new HttpResourceFetcher(sampleRequestManifestPath, { showUrl: true }),
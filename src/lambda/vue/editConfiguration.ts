@@ -0,0 +1,348 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { Lambda } from 'aws-sdk'
+import * as _ from 'lodash'
+import * as vscode from 'vscode'
+import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaEditConfiguration } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { createVueWebview } from '../../webviews/main'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+
+/** Interval between polls of the function's configuration while an update is in progress. */
+export const UPDATE_POLL_INTERVAL_MILLIS = 1000
+const UPDATE_TIMEOUT_MILLIS = 5 * 60 * 1000
+
+export const MEMORY_SIZE_RANGE = { min: 128, max: 10240 }
+export const TIMEOUT_RANGE = { min: 1, max: 900 }
+export const EPHEMERAL_STORAGE_RANGE = { min: 512, max: 10240 }
+
+/** Environment variables that Lambda sets itself, and doesn't allow functions to set. */
+const RESERVED_VARIABLE_NAMES = [
+    'AWS_REGION',
+    'AWS_DEFAULT_REGION',
+    'AWS_EXECUTION_ENV',
+    'AWS_LAMBDA_FUNCTION_NAME',
+    'AWS_LAMBDA_FUNCTION_MEMORY_SIZE',
+    'AWS_LAMBDA_FUNCTION_VERSION',
+    'AWS_LAMBDA_LOG_GROUP_NAME',
+    'AWS_LAMBDA_LOG_STREAM_NAME',
+    'AWS_LAMBDA_RUNTIME_API',
+    'AWS_ACCESS_KEY',
+    'AWS_ACCESS_KEY_ID',
+    'AWS_SECRET_ACCESS_KEY',
+    'AWS_SESSION_TOKEN',
+    'LAMBDA_TASK_ROOT',
+    'LAMBDA_RUNTIME_DIR',
+]
+
+// TODO: use the SDK type once it includes EphemeralStorage
+type FunctionConfigurationWithStorage = Lambda.FunctionConfiguration & { EphemeralStorage?: { Size: number } }
+type UpdateRequestWithStorage = Lambda.UpdateFunctionConfigurationRequest & { EphemeralStorage?: { Size: number } }
+
+export interface EnvironmentVariable {
+    name: string
+    value: string
+    /** Whether the value looks like a secret, and is hidden in the form until revealed. */
+    masked: boolean
+}
+
+export interface ConfigurationForm {
+    memorySize: number
+    timeout: number
+    ephemeralStorage: number
+    /** Undefined for functions deployed as container images, which have no handler. */
+    handler?: string
+    variables: EnvironmentVariable[]
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface SaveRequest {
+    command: 'save'
+    data: {
+        configuration: ConfigurationForm
+    }
+}
+
+export interface ConfigurationResponse {
+    command: 'configuration'
+    data: {
+        functionName: string
+        region: string
+        configuration: ConfigurationForm
+    }
+}
+
+export interface SavingResponse {
+    command: 'saving'
+}
+
+export interface SavedResponse {
+    command: 'saved'
+    data: {
+        configuration: ConfigurationForm
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        messages: string[]
+    }
+}
+
+export type EditConfigurationRequest = InitializeRequest | SaveRequest
+export type EditConfigurationResponse = ConfigurationResponse | SavingResponse | SavedResponse | ErrorResponse
+
+/**
+ * Whether an environment variable probably holds a secret, going by its name, e.g. `DB_PASSWORD` or `API_KEY`.
+ */
+export function isSecretName(name: string): boolean {
+    return /secret|passw(or)?d|pwd|token|api_?key|private_?key|credential|auth/i.test(name)
+}
+
+export function toConfigurationForm(configuration: Lambda.FunctionConfiguration): ConfigurationForm {
+    const variables = configuration.Environment?.Variables ?? {}
+
+    return {
+        memorySize: configuration.MemorySize ?? MEMORY_SIZE_RANGE.min,
+        timeout: configuration.Timeout ?? 3,
+        ephemeralStorage:
+            (configuration as FunctionConfigurationWithStorage).EphemeralStorage?.Size ?? EPHEMERAL_STORAGE_RANGE.min,
+        handler: configuration.PackageType === 'Image' ? undefined : configuration.Handler ?? '',
+        variables: Object.keys(variables)
+            .sort()
+            .map(name => ({ name, value: variables[name], masked: isSecretName(name) })),
+    }
+}
+
+function validateRange(value: number, range: { min: number; max: number }, label: string): string | undefined {
+    if (!Number.isInteger(value) || value < range.min || value > range.max) {
+        return localize(
+            'AWS.lambda.editConfiguration.outOfRange',
+            '{0} must be a whole number from {1} to {2}',
+            label,
+            range.min,
+            range.max
+        )
+    }
+
+    return undefined
+}
+
+/**
+ * Validates an edited configuration.
+ *
+ * @returns the problems found, or an empty array if the configuration is valid.
+ */
+export function validateConfigurationForm(form: ConfigurationForm): string[] {
+    const memoryLabel = localize('AWS.lambda.editConfiguration.memory', 'Memory (MB)')
+    const timeoutLabel = localize('AWS.lambda.editConfiguration.timeout', 'Timeout (seconds)')
+    const storageLabel = localize('AWS.lambda.editConfiguration.ephemeralStorage', 'Ephemeral storage (MB)')
+    const errors = [
+        validateRange(form.memorySize, MEMORY_SIZE_RANGE, memoryLabel),
+        validateRange(form.timeout, TIMEOUT_RANGE, timeoutLabel),
+        validateRange(form.ephemeralStorage, EPHEMERAL_STORAGE_RANGE, storageLabel),
+    ]
+    if (form.handler !== undefined && !form.handler.trim()) {
+        errors.push(localize('AWS.lambda.editConfiguration.handler.empty', 'Handler cannot be empty'))
+    }
+
+    for (const { name } of form.variables) {
+        if (!/^[a-zA-Z]\w*$/.test(name)) {
+            errors.push(
+                localize(
+                    'AWS.lambda.editConfiguration.variable.invalid',
+                    'Environment variable "{0}" must start with a letter and have only letters, numbers, and underscores',
+                    name
+                )
+            )
+        } else if (RESERVED_VARIABLE_NAMES.includes(name)) {
+            errors.push(
+                localize(
+                    'AWS.lambda.editConfiguration.variable.reserved',
+                    'Environment variable {0} is reserved by Lambda',
+                    name
+                )
+            )
+        }
+    }
+    const names = form.variables.map(variable => variable.name)
+    _.uniq(names.filter((name, index) => names.indexOf(name) !== index)).forEach(name =>
+        errors.push(
+            localize('AWS.lambda.editConfiguration.variable.duplicate', 'Environment variable {0} is set twice', name)
+        )
+    )
+
+    return _.compact(errors)
+}
+
+/**
+ * Makes a request that updates what was changed in the form, leaving the rest of the configuration as it is.
+ *
+ * @returns the request, or undefined if nothing was changed.
+ */
+export function toUpdateRequest(
+    functionName: string,
+    original: ConfigurationForm,
+    edited: ConfigurationForm
+): Lambda.UpdateFunctionConfigurationRequest | undefined {
+    const request: UpdateRequestWithStorage = { FunctionName: functionName }
+    if (edited.memorySize !== original.memorySize) {
+        request.MemorySize = edited.memorySize
+    }
+    if (edited.timeout !== original.timeout) {
+        request.Timeout = edited.timeout
+    }
+    if (edited.ephemeralStorage !== original.ephemeralStorage) {
+        request.EphemeralStorage = { Size: edited.ephemeralStorage }
+    }
+    if (edited.handler !== undefined && edited.handler.trim() !== original.handler) {
+        request.Handler = edited.handler.trim()
+    }
+
+    const toVariables = (form: ConfigurationForm) =>
+        form.variables.reduce(
+            (variables, { name, value }) => ({ ...variables, [name]: value }),
+            {} as Lambda.EnvironmentVariables
+        )
+    const variables = toVariables(edited)
+    // the update replaces all variables, so they're sent together
+    if (!_.isEqual(variables, toVariables(original))) {
+        request.Environment = { Variables: variables }
+    }
+
+    return Object.keys(request).length > 1 ? request : undefined
+}
+
+/**
+ * Waits for an update of a function's configuration to finish.
+ *
+ * @returns the updated configuration.
+ * @throws Error with the `LastUpdateStatusReason` if the update failed, or if it doesn't finish in time.
+ */
+export async function waitForConfigurationUpdate(
+    client: LambdaClient,
+    functionName: string,
+    { pollIntervalMillis = UPDATE_POLL_INTERVAL_MILLIS, timeoutMillis = UPDATE_TIMEOUT_MILLIS } = {}
+): Promise<Lambda.FunctionConfiguration> {
+    const deadline = Date.now() + timeoutMillis
+
+    while (true) {
+        const configuration = await client.getFunctionConfiguration(functionName)
+        switch (configuration.LastUpdateStatus) {
+            case 'InProgress':
+                break
+            case 'Failed':
+                throw new Error(
+                    configuration.LastUpdateStatusReason ??
+                        localize('AWS.lambda.editConfiguration.updateFailed', 'The update failed')
+                )
+            default:
+                return configuration
+        }
+
+        if (Date.now() >= deadline) {
+            throw new Error(
+                localize(
+                    'AWS.lambda.editConfiguration.updateTimeout',
+                    'The update did not finish in {0} minutes',
+                    timeoutMillis / 60000
+                )
+            )
+        }
+        await new Promise(resolve => setTimeout(resolve, pollIntervalMillis))
+    }
+}
+
+/**
+ * Opens a form to edit a function's memory, timeout, ephemeral storage, handler, and environment variables.
+ * Saving waits for the update to finish, so the form only reports success once the function uses the new
+ * configuration.
+ */
+export async function editConfigurationCommand(
+    node: LambdaFunctionNode,
+    client = ext.toolkitClientBuilder.createLambdaClient(node.regionCode),
+    context: vscode.ExtensionContext = ext.context
+): Promise<void> {
+    getLogger().debug('EditConfiguration called for %s', node.name)
+
+    let original: ConfigurationForm
+    try {
+        original = toConfigurationForm(await client.getFunctionConfiguration(node.name))
+    } catch (e) {
+        getLogger().error(`Failed to get configuration of function ${node.name}: %O`, e as Error)
+        showErrorWithLogs(
+            localize('AWS.lambda.editConfiguration.loadError', 'Failed to get the configuration of {0}', node.name)
+        )
+        recordLambdaEditConfiguration({ result: 'Failed' })
+        return
+    }
+
+    const save = async (
+        edited: ConfigurationForm,
+        postMessageFn: (response: EditConfigurationResponse) => Thenable<boolean>
+    ) => {
+        const errors = validateConfigurationForm(edited)
+        if (errors.length > 0) {
+            await postMessageFn({ command: 'error', data: { messages: errors } })
+            return
+        }
+        const request = toUpdateRequest(node.name, original, edited)
+        if (!request) {
+            await postMessageFn({ command: 'saved', data: { configuration: original } })
+            return
+        }
+
+        await postMessageFn({ command: 'saving' })
+        try {
+            await client.updateFunctionConfiguration(request)
+            original = toConfigurationForm(await waitForConfigurationUpdate(client, node.name))
+        } catch (e) {
+            const error = e as Error
+            getLogger().error(`Failed to update configuration of function ${node.name}: %O`, error)
+            await postMessageFn({ command: 'error', data: { messages: [error.message] } })
+            recordLambdaEditConfiguration({ result: 'Failed' })
+            return
+        }
+
+        getLogger().info(`Updated configuration of function ${node.name}`)
+        await postMessageFn({ command: 'saved', data: { configuration: original } })
+        vscode.window.showInformationMessage(
+            localize('AWS.lambda.editConfiguration.success', 'Updated the configuration of {0}', node.name)
+        )
+        recordLambdaEditConfiguration({ result: 'Succeeded' })
+    }
+
+    await createVueWebview<EditConfigurationRequest, EditConfigurationResponse>({
+        id: 'lambdaEditConfiguration',
+        name: localize('AWS.lambda.editConfiguration.title', 'Configuration: {0}', node.name),
+        webviewJs: 'lambdaEditConfigurationVue.js',
+        cssFiles: ['lambdaEditConfiguration.css'],
+        context,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            switch (message.command) {
+                case 'initialize':
+                    await postMessageFn({
+                        command: 'configuration',
+                        data: { functionName: node.name, region: node.regionCode, configuration: original },
+                    })
+                    break
+                case 'save':
+                    await save(message.data.configuration, postMessageFn)
+                    break
+            }
+        },
+    })
+}
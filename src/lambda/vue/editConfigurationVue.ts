@@ -0,0 +1,175 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import { ConfigurationForm, EditConfigurationResponse, EnvironmentVariable } from './editConfiguration'
+
+declare const vscode: WebviewApi<null>
+
+export interface EditConfigurationVueData {
+    functionName: string
+    region: string
+    configuration?: ConfigurationForm
+    /** Names of the masked variables whose values are shown. */
+    revealed: string[]
+    saving: boolean
+    saved: boolean
+    errorMessages: string[]
+}
+
+export const Component = Vue.extend({
+    created() {
+        window.addEventListener('message', ev => {
+            const event = ev.data as EditConfigurationResponse
+            switch (event.command) {
+                case 'configuration':
+                    this.functionName = event.data.functionName
+                    this.region = event.data.region
+                    this.configuration = event.data.configuration
+                    break
+                case 'saving':
+                    this.saving = true
+                    this.saved = false
+                    this.errorMessages = []
+                    break
+                case 'saved':
+                    this.saving = false
+                    this.saved = true
+                    this.errorMessages = []
+                    this.configuration = event.data.configuration
+                    this.revealed = []
+                    break
+                case 'error':
+                    this.saving = false
+                    this.saved = false
+                    this.errorMessages = event.data.messages
+                    break
+            }
+        })
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): EditConfigurationVueData {
+        return {
+            functionName: '',
+            region: '',
+            configuration: undefined,
+            revealed: [],
+            saving: false,
+            saved: false,
+            errorMessages: [],
+        }
+    },
+    methods: {
+        isHidden(variable: EnvironmentVariable): boolean {
+            return variable.masked && !this.revealed.includes(variable.name)
+        },
+        toggleReveal(variable: EnvironmentVariable) {
+            this.revealed = this.revealed.includes(variable.name)
+                ? this.revealed.filter(name => name !== variable.name)
+                : [...this.revealed, variable.name]
+        },
+        addVariable() {
+            this.configuration?.variables.push({ name: '', value: '', masked: false })
+            this.saved = false
+        },
+        removeVariable(index: number) {
+            this.configuration?.variables.splice(index, 1)
+            this.saved = false
+        },
+        save() {
+            if (!this.configuration) {
+                return
+            }
+            vscode.postMessage({
+                command: 'save',
+                data: {
+                    configuration: {
+                        ...this.configuration,
+                        memorySize: Number(this.configuration.memorySize),
+                        timeout: Number(this.configuration.timeout),
+                        ephemeralStorage: Number(this.configuration.ephemeralStorage),
+                    },
+                },
+            })
+        },
+    },
+    template: `
+    <div class="edit-configuration">
+        <h1>{{ functionName }}</h1>
+        <p class="region">{{ region }}</p>
+        <p v-if="!configuration">Loading configuration...</p>
+        <form v-else v-on:submit.prevent="save" v-on:input="saved = false">
+            <fieldset :disabled="saving">
+                <div class="field">
+                    <label for="memorySize">Memory (MB)</label>
+                    <input id="memorySize" type="number" min="128" max="10240" v-model="configuration.memorySize" />
+                </div>
+                <div class="field">
+                    <label for="timeout">Timeout (seconds)</label>
+                    <input id="timeout" type="number" min="1" max="900" v-model="configuration.timeout" />
+                </div>
+                <div class="field">
+                    <label for="ephemeralStorage">Ephemeral storage (MB)</label>
+                    <input
+                        id="ephemeralStorage"
+                        type="number"
+                        min="512"
+                        max="10240"
+                        v-model="configuration.ephemeralStorage"
+                    />
+                </div>
+                <div class="field" v-if="configuration.handler !== undefined">
+                    <label for="handler">Handler</label>
+                    <input id="handler" type="text" v-model="configuration.handler" />
+                </div>
+                <h2>Environment variables</h2>
+                <table v-if="configuration.variables.length > 0">
+                    <thead>
+                        <tr>
+                            <th>Name</th>
+                            <th>Value</th>
+                            <th></th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        <tr v-for="(variable, index) in configuration.variables" :key="index">
+                            <td><input type="text" v-model="variable.name" /></td>
+                            <td class="variable-value">
+                                <input :type="isHidden(variable) ? 'password' : 'text'" v-model="variable.value" />
+                                <button
+                                    v-if="variable.masked"
+                                    type="button"
+                                    v-on:click="toggleReveal(variable)"
+                                >{{ isHidden(variable) ? 'Show' : 'Hide' }}</button>
+                            </td>
+                            <td>
+                                <button type="button" title="Remove" v-on:click="removeVariable(index)">Remove</button>
+                            </td>
+                        </tr>
+                    </tbody>
+                </table>
+                <p v-else>No environment variables.</p>
+                <button type="button" v-on:click="addVariable">Add variable</button>
+                <div class="actions">
+                    <button type="submit">Save</button>
+                    <span v-if="saving">Saving and waiting for the update to finish...</span>
+                    <span v-if="saved" class="saved">Saved.</span>
+                </div>
+            </fieldset>
+        </form>
+        <ul class="error" v-if="errorMessages.length > 0">
+            <li v-for="message in errorMessages">{{ message }}</li>
+        </ul>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
@@ -0,0 +1,280 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { CloudWatch } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { CloudWatchClient } from '../../shared/clients/cloudWatchClient'
+import { LambdaClient } from '../../shared/clients/lambdaClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordLambdaViewMetrics } from '../../shared/telemetry/telemetry'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { createVueWebview } from '../../webviews/main'
+import { LambdaFunctionNode } from '../explorer/lambdaFunctionNode'
+import { listPublishedVersions } from '../explorer/lambdaQualifierNodes'
+
+export type MetricsTimeRange = '1h' | '3h' | '12h' | '1d' | '1w'
+export type MetricUnit = 'count' | 'milliseconds'
+
+/**
+ * How far back each time range goes, and the period of its data points. Periods are chosen so that each chart
+ * has at most a few hundred data points.
+ */
+export const METRICS_TIME_RANGES: { [range in MetricsTimeRange]: { millis: number; periodSeconds: number } } = {
+    '1h': { millis: 60 * 60 * 1000, periodSeconds: 60 },
+    '3h': { millis: 3 * 60 * 60 * 1000, periodSeconds: 300 },
+    '12h': { millis: 12 * 60 * 60 * 1000, periodSeconds: 600 },
+    '1d': { millis: 24 * 60 * 60 * 1000, periodSeconds: 900 },
+    '1w': { millis: 7 * 24 * 60 * 60 * 1000, periodSeconds: 3600 },
+}
+
+const LAMBDA_NAMESPACE = 'AWS/Lambda'
+
+/**
+ * The charts of the view. Lambda has no metric of cold starts: they show up as the spikes of the p99 duration.
+ */
+const METRIC_QUERIES: { id: string; label: string; metricName: string; stat: string; unit: MetricUnit }[] = [
+    {
+        id: 'invocations',
+        label: localize('AWS.lambda.metrics.invocations', 'Invocations'),
+        metricName: 'Invocations',
+        stat: 'Sum',
+        unit: 'count',
+    },
+    {
+        id: 'durationP50',
+        label: localize('AWS.lambda.metrics.durationP50', 'Duration (p50)'),
+        metricName: 'Duration',
+        stat: 'p50',
+        unit: 'milliseconds',
+    },
+    {
+        id: 'durationP99',
+        label: localize('AWS.lambda.metrics.durationP99', 'Duration (p99)'),
+        metricName: 'Duration',
+        stat: 'p99',
+        unit: 'milliseconds',
+    },
+    {
+        id: 'errors',
+        label: localize('AWS.lambda.metrics.errors', 'Errors'),
+        metricName: 'Errors',
+        stat: 'Sum',
+        unit: 'count',
+    },
+    {
+        id: 'throttles',
+        label: localize('AWS.lambda.metrics.throttles', 'Throttles'),
+        metricName: 'Throttles',
+        stat: 'Sum',
+        unit: 'count',
+    },
+]
+
+export interface MetricSeries {
+    id: string
+    label: string
+    unit: MetricUnit
+    /** Milliseconds since the epoch, oldest first. Periods without data have no data point. */
+    timestamps: number[]
+    values: number[]
+}
+
+export interface FunctionMetrics {
+    series: MetricSeries[]
+    startTime: number
+    endTime: number
+    /** Whether the function (or its alias or version) wasn't invoked in the time range. */
+    empty: boolean
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface LoadMetricsRequest {
+    command: 'loadMetrics'
+    data: {
+        timeRange: MetricsTimeRange
+        /** An alias or version, or undefined for all invocations of the function. */
+        qualifier?: string
+    }
+}
+
+export interface FunctionResponse {
+    command: 'function'
+    data: {
+        functionName: string
+        region: string
+        /** Aliases, then published versions. */
+        qualifiers: string[]
+        qualifier?: string
+    }
+}
+
+export interface MetricsResponse {
+    command: 'metrics'
+    data: FunctionMetrics & {
+        timeRange: MetricsTimeRange
+        qualifier?: string
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        message: string
+    }
+}
+
+export type FunctionMetricsRequest = InitializeRequest | LoadMetricsRequest
+export type FunctionMetricsResponse = FunctionResponse | MetricsResponse | ErrorResponse
+
+/**
+ * Gets the invocations, durations, errors and throttles of a function over a time range.
+ *
+ * @param qualifier An alias or version. Lambda reports the metrics of invocations of a qualifier with the
+ * `Resource` dimension, as well as under the function as a whole.
+ */
+export async function getFunctionMetrics(
+    client: CloudWatchClient,
+    functionName: string,
+    { timeRange, qualifier, now = new Date() }: { timeRange: MetricsTimeRange; qualifier?: string; now?: Date }
+): Promise<FunctionMetrics> {
+    const { millis, periodSeconds } = METRICS_TIME_RANGES[timeRange]
+    const periodMillis = periodSeconds * 1000
+    // the end of the last whole period, so that the last data point isn't the period still in progress
+    const endTime = Math.floor(now.getTime() / periodMillis) * periodMillis
+    const startTime = endTime - millis
+
+    const dimensions: CloudWatch.Dimensions = [{ Name: 'FunctionName', Value: functionName }]
+    if (qualifier) {
+        dimensions.push({ Name: 'Resource', Value: `${functionName}:${qualifier}` })
+    }
+
+    const results = await client.getMetricData({
+        StartTime: new Date(startTime),
+        EndTime: new Date(endTime),
+        ScanBy: 'TimestampAscending',
+        MetricDataQueries: METRIC_QUERIES.map(query => ({
+            Id: query.id,
+            MetricStat: {
+                Metric: { Namespace: LAMBDA_NAMESPACE, MetricName: query.metricName, Dimensions: dimensions },
+                Period: periodSeconds,
+                Stat: query.stat,
+            },
+        })),
+    })
+
+    const series = METRIC_QUERIES.map(query => {
+        const result = results.find(r => r.Id === query.id)
+        const timestamps = (result?.Timestamps ?? []).map(timestamp => new Date(timestamp).getTime())
+        const values = result?.Values ?? []
+        const points = timestamps
+            .map((timestamp, i) => ({ timestamp, value: values[i] }))
+            .sort((a, b) => a.timestamp - b.timestamp)
+
+        return {
+            id: query.id,
+            label: query.label,
+            unit: query.unit,
+            timestamps: points.map(point => point.timestamp),
+            values: points.map(point => point.value),
+        }
+    })
+    const invocations = series.find(s => s.id === 'invocations')?.values ?? []
+
+    return { series, startTime, endTime, empty: !invocations.some(value => value > 0) }
+}
+
+async function listQualifiers(client: LambdaClient, functionName: string): Promise<string[]> {
+    const aliases = await toArrayAsync(client.listAliases(functionName))
+    const versions = await listPublishedVersions(client, functionName)
+
+    return [
+        ...aliases.map(alias => alias.Name ?? ''),
+        ...versions.map(version => version.Version ?? '').reverse(),
+    ].filter(qualifier => qualifier !== '')
+}
+
+/**
+ * Opens a view of a function's metrics, which can be narrowed to one of its aliases or versions.
+ */
+export async function openFunctionMetrics(
+    {
+        cloudWatchClient,
+        lambdaClient,
+        functionName,
+        qualifier,
+    }: {
+        cloudWatchClient: CloudWatchClient
+        lambdaClient: LambdaClient
+        functionName: string
+        qualifier?: string
+    },
+    context: vscode.ExtensionContext = ext.context
+): Promise<void> {
+    const title = qualifier ? `${functionName}:${qualifier}` : functionName
+
+    await createVueWebview<FunctionMetricsRequest, FunctionMetricsResponse>({
+        id: 'lambdaFunctionMetrics',
+        name: localize('AWS.lambda.metrics.title', 'Metrics: {0}', title),
+        webviewJs: 'lambdaFunctionMetricsVue.js',
+        cssFiles: ['lambdaFunctionMetrics.css'],
+        context,
+        persistWithoutFocus: true,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            switch (message.command) {
+                case 'initialize': {
+                    let qualifiers: string[] = []
+                    try {
+                        qualifiers = await listQualifiers(lambdaClient, functionName)
+                    } catch (e) {
+                        // the metrics of the function can still be shown
+                        getLogger().warn(`Failed to list aliases and versions of ${functionName}: %O`, e as Error)
+                    }
+                    await postMessageFn({
+                        command: 'function',
+                        data: { functionName, region: cloudWatchClient.regionCode, qualifiers, qualifier },
+                    })
+                    break
+                }
+                case 'loadMetrics':
+                    try {
+                        const metrics = await getFunctionMetrics(cloudWatchClient, functionName, message.data)
+                        await postMessageFn({ command: 'metrics', data: { ...metrics, ...message.data } })
+                    } catch (e) {
+                        const error = e as Error
+                        getLogger().error(`Failed to get metrics of function ${functionName}: %O`, error)
+                        await postMessageFn({ command: 'error', data: { message: error.message } })
+                    }
+                    break
+            }
+        },
+    })
+}
+
+/**
+ * Opens the metrics of a function in the AWS Explorer.
+ *
+ * @param qualifier The alias or version to show the metrics of first.
+ */
+export async function viewFunctionMetricsCommand(node: LambdaFunctionNode, qualifier?: string): Promise<void> {
+    try {
+        await openFunctionMetrics({
+            cloudWatchClient: ext.toolkitClientBuilder.createCloudWatchClient(node.regionCode),
+            lambdaClient: ext.toolkitClientBuilder.createLambdaClient(node.regionCode),
+            functionName: node.name,
+            qualifier,
+        })
+        recordLambdaViewMetrics({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to open metrics of function ${node.name}: %O`, e as Error)
+        recordLambdaViewMetrics({ result: 'Failed' })
+    }
+}
@@ -0,0 +1,184 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import { FunctionMetricsResponse, MetricSeries, MetricsTimeRange } from './functionMetrics'
+
+declare const vscode: WebviewApi<null>
+
+/** Size of the charts' view boxes. The charts are scaled to the width of the view. */
+const CHART_WIDTH = 600
+const CHART_HEIGHT = 80
+
+export interface FunctionMetricsVueData {
+    functionName: string
+    region: string
+    qualifiers: string[]
+    qualifier: string
+    timeRange: MetricsTimeRange
+    timeRanges: { value: MetricsTimeRange; label: string }[]
+    series: MetricSeries[]
+    startTime: number
+    endTime: number
+    empty: boolean
+    loading: boolean
+    errorMsg: string
+}
+
+function formatValue(value: number, unit: MetricSeries['unit']): string {
+    if (unit === 'milliseconds') {
+        return value >= 1000 ? `${(value / 1000).toFixed(2)} s` : `${value.toFixed(value < 10 ? 2 : 0)} ms`
+    }
+
+    return value.toLocaleString()
+}
+
+export const Component = Vue.extend({
+    created() {
+        window.addEventListener('message', ev => {
+            const event = ev.data as FunctionMetricsResponse
+            switch (event.command) {
+                case 'function':
+                    this.functionName = event.data.functionName
+                    this.region = event.data.region
+                    this.qualifiers = event.data.qualifiers
+                    this.qualifier = event.data.qualifier ?? ''
+                    this.loadMetrics()
+                    break
+                case 'metrics':
+                    // ignore responses to requests that were superseded
+                    if (event.data.timeRange !== this.timeRange || (event.data.qualifier ?? '') !== this.qualifier) {
+                        break
+                    }
+                    this.loading = false
+                    this.errorMsg = ''
+                    this.series = event.data.series
+                    this.startTime = event.data.startTime
+                    this.endTime = event.data.endTime
+                    this.empty = event.data.empty
+                    break
+                case 'error':
+                    this.loading = false
+                    this.errorMsg = event.data.message
+                    break
+            }
+        })
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): FunctionMetricsVueData {
+        return {
+            functionName: '',
+            region: '',
+            qualifiers: [],
+            qualifier: '',
+            timeRange: '3h',
+            timeRanges: [
+                { value: '1h', label: '1 hour' },
+                { value: '3h', label: '3 hours' },
+                { value: '12h', label: '12 hours' },
+                { value: '1d', label: '1 day' },
+                { value: '1w', label: '1 week' },
+            ],
+            series: [],
+            startTime: 0,
+            endTime: 0,
+            empty: false,
+            loading: true,
+            errorMsg: '',
+        }
+    },
+    computed: {
+        viewBox(): string {
+            return `0 0 ${CHART_WIDTH} ${CHART_HEIGHT}`
+        },
+    },
+    methods: {
+        loadMetrics() {
+            this.loading = true
+            vscode.postMessage({
+                command: 'loadMetrics',
+                data: { timeRange: this.timeRange, qualifier: this.qualifier || undefined },
+            })
+        },
+        /** The points of a series' line, with time along the chart and values from 0 to the largest value. */
+        points(series: MetricSeries): string {
+            const max = Math.max(...series.values, 0)
+            const duration = this.endTime - this.startTime || 1
+
+            return series.timestamps
+                .map((timestamp, i) => {
+                    const x = ((timestamp - this.startTime) / duration) * CHART_WIDTH
+                    const y = max > 0 ? CHART_HEIGHT - (series.values[i] / max) * CHART_HEIGHT : CHART_HEIGHT
+                    return `${x.toFixed(1)},${y.toFixed(1)}`
+                })
+                .join(' ')
+        },
+        /** Counts are summed over the time range, durations are the longest of any period. */
+        summary(series: MetricSeries): string {
+            if (series.values.length === 0) {
+                return 'No data'
+            }
+            if (series.unit === 'count') {
+                const total = series.values.reduce((sum, value) => sum + value, 0)
+                return `Total: ${formatValue(total, series.unit)}`
+            }
+
+            return `Max: ${formatValue(Math.max(...series.values), series.unit)}`
+        },
+        formatTime(millis: number): string {
+            return new Date(millis).toLocaleString()
+        },
+    },
+    template: `
+    <div class="function-metrics">
+        <h1>{{ functionName }}</h1>
+        <p class="function-region">{{ region }}</p>
+        <div class="metrics-options">
+            <label>
+                Time range
+                <select v-model="timeRange" v-on:change="loadMetrics">
+                    <option v-for="range in timeRanges" :key="range.value" :value="range.value">
+                        {{ range.label }}
+                    </option>
+                </select>
+            </label>
+            <label>
+                Alias or version
+                <select v-model="qualifier" v-on:change="loadMetrics">
+                    <option value="">All invocations</option>
+                    <option v-for="q in qualifiers" :key="q" :value="q">{{ q }}</option>
+                </select>
+            </label>
+            <button v-on:click="loadMetrics" :disabled="loading">Refresh</button>
+            <span v-if="loading">Loading...</span>
+        </div>
+        <p class="error" v-if="errorMsg">{{ errorMsg }}</p>
+        <div class="metrics-empty" v-else-if="empty && !loading">
+            There were no invocations in this time range. Invoke the function, or choose a longer time range.
+        </div>
+        <div v-else-if="series.length > 0">
+            <p class="metrics-time">{{ formatTime(startTime) }} - {{ formatTime(endTime) }}</p>
+            <div class="metric" v-for="s in series" :key="s.id">
+                <div class="metric-header">
+                    <strong>{{ s.label }}</strong>
+                    <span>{{ summary(s) }}</span>
+                </div>
+                <svg class="metric-chart" :viewBox="viewBox" preserveAspectRatio="none">
+                    <polyline :points="points(s)" />
+                </svg>
+            </div>
+            <p class="metrics-note">Cold starts show up as spikes in the p99 duration.</p>
+        </div>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
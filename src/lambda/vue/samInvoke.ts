@@ -11,7 +11,6 @@ import { LaunchConfiguration } from '../../shared/debug/launchConfiguration'
 import { ext } from '../../shared/extensionGlobals'
 import { ExtContext } from '../../shared/extensions'
 import { getLogger } from '../../shared/logger'
-import { HttpResourceFetcher } from '../../shared/resourcefetcher/httpResourceFetcher'
 import {
     AwsSamDebuggerConfiguration,
     isCodeTargetProperties,
@@ -26,12 +25,11 @@ import * as input from '../../shared/ui/input'
 import * as picker from '../../shared/ui/picker'
 import { addCodiconToString } from '../../shared/utilities/textUtilities'
 import { createVueWebview } from '../../webviews/main'
-import { sampleRequestPath } from '../constants'
 import { tryGetAbsolutePath } from '../../shared/utilities/workspaceUtils'
 import { CloudFormation } from '../../shared/cloudformation/cloudformation'
 import { openLaunchJsonFile } from '../../shared/sam/debugger/commands/addSamDebugConfiguration'
 import { recordSamOpenConfigUi } from '../../shared/telemetry/telemetry.gen'
-import { getSampleLambdaPayloads } from '../utils'
+import { getSampleLambdaPayload, getSampleLambdaPayloads } from '../utils'
 import { isCloud9 } from '../../shared/extensionUtilities'
 import { SamDebugConfigProvider } from '../../shared/sam/debugger/awsSamDebugger'
 import { samLambdaCreatableRuntimes } from '../models/samLambdaRuntime'
@@ -243,8 +241,7 @@ async function getSamplePayload(
         if (!pickerResponse) {
             return
         }
-        const sampleUrl = `${sampleRequestPath}${pickerResponse.filename}`
-        const sample = (await new HttpResourceFetcher(sampleUrl, { showUrl: true }).get()) ?? ''
+        const sample = await getSampleLambdaPayload(pickerResponse.filename)
 
         postMessageFn({
             command: 'getSamplePayload',
@@ -19,6 +19,7 @@ export interface SamInvokeVueData {
     showAllFields: boolean
     targetTypes: { [k: string]: string }[]
     runtimes: string[]
+    architectures: string[]
     httpMethods: string[]
     launchConfig: AwsSamDebuggerConfigurationLoose
     payload: VueDataLaunchPropertyObject
@@ -29,6 +30,9 @@ export interface SamInvokeVueData {
     parameters: VueDataLaunchPropertyObject
     containerBuildStr: string
     skipNewImageCheckStr: string
+    cachedBuildStr: string
+    forceCleanBuildStr: string
+    streamingResponseStr: string
 }
 
 function newLaunchConfig(existingConfig?: AwsSamDebuggerConfiguration): AwsSamDebuggerConfigurationLoose {
@@ -68,6 +72,8 @@ function newLaunchConfig(existingConfig?: AwsSamDebuggerConfiguration): AwsSamDe
             dockerNetwork: '',
             localArguments: undefined,
             skipNewImageCheck: false,
+            cachedBuild: false,
+            forceCleanBuild: false,
             ...(existingConfig?.sam ? existingConfig.sam : {}),
             template: {
                 parameters: existingConfig?.sam?.template?.parameters ? existingConfig.sam.template.parameters : {},
@@ -139,6 +145,21 @@ export const Component = Vue.extend({
                     } else if (event.data.launchConfig.sam?.skipNewImageCheck === false) {
                         this.skipNewImageCheckStr = 'False'
                     }
+                    if (event.data.launchConfig.sam?.cachedBuild === true) {
+                        this.cachedBuildStr = 'True'
+                    } else if (event.data.launchConfig.sam?.cachedBuild === false) {
+                        this.cachedBuildStr = 'False'
+                    }
+                    if (event.data.launchConfig.sam?.forceCleanBuild === true) {
+                        this.forceCleanBuildStr = 'True'
+                    } else if (event.data.launchConfig.sam?.forceCleanBuild === false) {
+                        this.forceCleanBuildStr = 'False'
+                    }
+                    if (event.data.launchConfig.lambda?.streamingResponse === true) {
+                        this.streamingResponseStr = 'True'
+                    } else if (event.data.launchConfig.lambda?.streamingResponse === false) {
+                        this.streamingResponseStr = 'False'
+                    }
                     this.msg = `Loaded config ${event.data.launchConfig.name}`
                     break
             }
@@ -155,6 +176,10 @@ export const Component = Vue.extend({
             ],
             containerBuildStr: '',
             skipNewImageCheckStr: '',
+            cachedBuildStr: '',
+            forceCleanBuildStr: '',
+            streamingResponseStr: '',
+            architectures: ['x86_64', 'arm64'],
             runtimes: [],
             httpMethods: ['GET', 'POST', 'PUT', 'DELETE', 'HEAD', 'OPTIONS', 'PATCH'],
             launchConfig: newLaunchConfig(),
@@ -272,6 +297,11 @@ export const Component = Vue.extend({
                                 json: payloadJson,
                             },
                             environmentVariables: environmentVariablesJson,
+                            // Leave unset so the template's FunctionUrlConfig is used.
+                            streamingResponse: this.streamingResponseStr
+                                ? this.stringToBoolean(this.streamingResponseStr)
+                                : undefined,
+                            architecture: this.launchConfig.lambda?.architecture || undefined,
                         },
                         sam: {
                             ...this.launchConfig.sam,
@@ -283,6 +313,8 @@ export const Component = Vue.extend({
                             ),
                             containerBuild: this.stringToBoolean(this.containerBuildStr),
                             skipNewImageCheck: this.stringToBoolean(this.skipNewImageCheckStr),
+                            cachedBuild: this.stringToBoolean(this.cachedBuildStr),
+                            forceCleanBuild: this.stringToBoolean(this.forceCleanBuildStr),
                             template: {
                                 parameters: parametersJson,
                             },
@@ -305,6 +337,9 @@ export const Component = Vue.extend({
             this.launchConfig = newLaunchConfig()
             this.containerBuildStr = ''
             this.skipNewImageCheckStr = ''
+            this.cachedBuildStr = ''
+            this.forceCleanBuildStr = ''
+            this.streamingResponseStr = ''
             this.payload = { value: '', errorMsg: '' }
             this.apiPayload = { value: '', errorMsg: '' }
             this.environmentVariables = { value: '', errorMsg: '' }
@@ -366,6 +401,15 @@ export const Component = Vue.extend({
                     </select>
                     <span class="data-view">runtime in data: {{ launchConfig.lambda.runtime }}</span>
                 </div>
+                <div class="config-item">
+                    <label for="architecture-selector">Architecture</label>
+                    <select name="architecture" id="architecture-selector" v-model="launchConfig.lambda.architecture">
+                        <option value="" :key="-1">Default (x86_64)</option>
+                        <option v-for="(architecture, index) in architectures" v-bind:value="architecture" :key="index">
+                            {{ architecture }}
+                        </option>
+                    </select>
+                </div>
             </div>
             <div class="target-template" v-else-if="launchConfig.invokeTarget.target === 'template'">
                 <button v-on:click.prevent="loadResource">Load Resource</button><br />
@@ -479,6 +523,14 @@ export const Component = Vue.extend({
                     <label for="timeoutSec">Timeout (s)</label>
                     <input type="number" v-model.number="launchConfig.lambda.timeoutSec" >
                 </div>
+                <div class="config-item">
+                    <label for="streamingResponse">Response Streaming</label>
+                    <select name="streamingResponse" id="streamingResponse" v-model="streamingResponseStr">
+                        <option value="" :key="0">Detect from template</option>
+                        <option value="False" :key="1">False</option>
+                        <option value="True" :key="2">True</option>
+                    </select>
+                </div>
                 <!-- <div class="config-item">
                     <label for="pathMappings">Path Mappings</label>
                     <input type="text" v-model="launchConfig.lambda.pathMappings" >
@@ -488,6 +540,13 @@ export const Component = Vue.extend({
                     <label for="buildArguments">Build Arguments</label>
                     <input type="text" v-model="launchConfig.sam.buildArguments" placeholder="Enter as a comma separated list" >
                 </div>
+                <div class="config-item">
+                    <label for="cachedBuild">Cached Build</label>
+                    <select name="cachedBuild" id="cachedBuild" v-model="cachedBuildStr">
+                        <option value="False" :key="0">False</option>
+                        <option value="True" :key="1">True</option>
+                    </select>
+                </div>
                 <div class="config-item">
                     <label for="containerBuild">Container Build</label>
                     <select name="containerBuild" id="containerBuild" v-model="containerBuildStr">
@@ -499,6 +558,13 @@ export const Component = Vue.extend({
                     <label for="dockerNetwork">Docker Network</label>
                     <input type="text" v-model="launchConfig.sam.dockerNetwork">
                 </div>
+                <div class="config-item">
+                    <label for="forceCleanBuild">Force Clean Build</label>
+                    <select name="forceCleanBuild" id="forceCleanBuild" v-model="forceCleanBuildStr">
+                        <option value="False" :key="0">False</option>
+                        <option value="True" :key="1">True</option>
+                    </select>
+                </div>
                 <div class="config-item">
                     <label for="localArguments">Local Arguments</label>
                     <input type="text" v-model="launchConfig.sam.localArguments" placeholder="Enter as a comma separated list" >
@@ -0,0 +1,294 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { CloudFormation } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { CloudFormationClient } from '../../shared/clients/cloudFormationClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordCloudformationViewStackEvents } from '../../shared/telemetry/telemetry'
+import { createVueWebview } from '../../webviews/main'
+import { CloudFormationStackNode } from '../explorer/cloudFormationNodes'
+
+/** Interval between polls of the stack's events. */
+export const STACK_EVENTS_POLL_INTERVAL_MILLIS = 5000
+
+export interface StackEventItem {
+    id: string
+    timestamp: string
+    logicalId: string
+    resourceType: string
+    status: string
+    reason?: string
+    failed: boolean
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface RevealResourceRequest {
+    command: 'revealResource'
+    data: {
+        logicalId: string
+    }
+}
+
+export interface StackResponse {
+    command: 'stack'
+    data: {
+        stackName: string
+        region: string
+    }
+}
+
+export interface EventsResponse {
+    command: 'events'
+    data: {
+        /** Events read so far, newest first. */
+        events: StackEventItem[]
+        stackStatus?: string
+        polling: boolean
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        message: string
+    }
+}
+
+export type StackEventsRequest = InitializeRequest | RevealResourceRequest
+export type StackEventsResponse = StackResponse | EventsResponse | ErrorResponse
+
+/**
+ * Whether a stack is done changing, e.g. `UPDATE_COMPLETE` or `ROLLBACK_FAILED`.
+ */
+export function isTerminalStackStatus(status: string): boolean {
+    return !status.endsWith('_IN_PROGRESS')
+}
+
+function toStackEventItem(event: CloudFormation.StackEvent): StackEventItem {
+    const status = event.ResourceStatus ?? ''
+
+    return {
+        id: event.EventId,
+        timestamp: new Date(event.Timestamp).toISOString(),
+        logicalId: event.LogicalResourceId ?? '',
+        resourceType: event.ResourceType ?? '',
+        status,
+        reason: event.ResourceStatusReason,
+        failed: status.endsWith('_FAILED'),
+    }
+}
+
+function isStackNotFoundError(error: Error): boolean {
+    return (error as { code?: string }).code === 'ValidationError' && error.message.includes('does not exist')
+}
+
+/**
+ * Reads the events of a stack as they happen, keeping the events read so far.
+ */
+export class StackEventsReader {
+    private readonly events: StackEventItem[] = []
+    private readonly eventIds = new Set<string>()
+
+    /**
+     * @param since Only events from this time on are read. If undefined, the most recent page of events is read first.
+     */
+    public constructor(
+        private readonly client: CloudFormationClient,
+        private readonly stackName: string,
+        private readonly since?: Date
+    ) {}
+
+    /**
+     * Reads the events since the last poll, and the current status of the stack.
+     * A stack that doesn't exist yet (e.g. before SAM CLI creates its change set) has no events and no status.
+     */
+    public async poll(): Promise<Omit<EventsResponse['data'], 'polling'>> {
+        try {
+            const newEvents: StackEventItem[] = []
+            let nextToken: string | undefined
+            do {
+                const response = await this.client.describeStackEvents(this.stackName, nextToken)
+                const page = response.StackEvents ?? []
+                const end = page.findIndex(
+                    event => this.eventIds.has(event.EventId) || (this.since && event.Timestamp < this.since)
+                )
+                newEvents.push(...(end === -1 ? page : page.slice(0, end)).map(toStackEventItem))
+
+                // Without a starting time, the first page is enough: older events are not of interest
+                nextToken = end === -1 && this.since ? response.NextToken : undefined
+            } while (nextToken)
+
+            newEvents.forEach(event => this.eventIds.add(event.id))
+            this.events.unshift(...newEvents)
+
+            const stack = await this.client.describeStack(this.stackName)
+
+            return { events: this.events, stackStatus: stack?.StackStatus }
+        } catch (e) {
+            if (isStackNotFoundError(e as Error)) {
+                return { events: this.events, stackStatus: undefined }
+            }
+
+            throw e
+        }
+    }
+}
+
+/**
+ * Finds where a resource is defined within a CloudFormation template.
+ *
+ * @returns the offset of the resource's logical ID, or undefined if the resource isn't defined in the template.
+ */
+export function findResourceDefinitionOffset(text: string, logicalId: string, isYaml: boolean): number | undefined {
+    const name = logicalId.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')
+    // Only match indented keys whose value is an object, so top-level keys and references (`!Ref name`) are skipped
+    const pattern = isYaml
+        ? new RegExp(`^([ \\t]+(['"]?))${name}\\2[ \\t]*:[ \\t]*(#.*)?$`, 'm')
+        : new RegExp(`(")${name}"\\s*:\\s*\\{`)
+    const match = pattern.exec(text)
+
+    return match ? match.index + match[1].length : undefined
+}
+
+/**
+ * Selects a resource in the given template, or else in a template open in the editor.
+ */
+async function revealResource(logicalId: string, templatePath: string | undefined): Promise<void> {
+    const editors: { document: vscode.TextDocument; viewColumn?: vscode.ViewColumn }[] = templatePath
+        ? [{ document: await vscode.workspace.openTextDocument(templatePath) }]
+        : vscode.window.visibleTextEditors
+
+    for (const { document, viewColumn } of editors) {
+        if (!['yaml', 'json'].includes(document.languageId)) {
+            continue
+        }
+        const offset = findResourceDefinitionOffset(document.getText(), logicalId, document.languageId === 'yaml')
+        if (offset !== undefined) {
+            const range = new vscode.Range(document.positionAt(offset), document.positionAt(offset + logicalId.length))
+            await vscode.window.showTextDocument(document, { viewColumn, selection: range })
+
+            return
+        }
+    }
+
+    vscode.window.showInformationMessage(
+        localize(
+            'AWS.cloudFormation.stackEvents.resourceNotFound',
+            'Resource "{0}" was not found in an open template',
+            logicalId
+        )
+    )
+}
+
+/**
+ * Opens a view of a stack's events, which polls for new events until the stack reaches a terminal status.
+ *
+ * @param since Only show events from this time on, e.g. the start of a deploy.
+ * @param deployment A deploy in progress. Polling continues at least until it settles, since the stack may not
+ * change (or even exist) until SAM CLI executes its change set.
+ * @param templatePath Template to reveal resources in. If undefined, open templates are searched.
+ */
+export async function openStackEvents(
+    {
+        client,
+        stackName,
+        since,
+        deployment,
+        templatePath,
+    }: {
+        client: CloudFormationClient
+        stackName: string
+        since?: Date
+        deployment?: Thenable<unknown>
+        templatePath?: string
+    },
+    context: vscode.ExtensionContext = ext.context
+): Promise<void> {
+    const reader = new StackEventsReader(client, stackName, since)
+    let deploymentSettled = !deployment
+    let disposed = false
+    let timer: NodeJS.Timeout | undefined
+
+    deployment?.then(
+        () => (deploymentSettled = true),
+        () => (deploymentSettled = true)
+    )
+
+    const poll = async (postMessageFn: (response: StackEventsResponse) => Thenable<boolean>) => {
+        timer = undefined
+        let polling = false
+        try {
+            const data = await reader.poll()
+            polling =
+                !deploymentSettled || (data.stackStatus !== undefined && !isTerminalStackStatus(data.stackStatus))
+            if (!disposed) {
+                await postMessageFn({ command: 'events', data: { ...data, polling } })
+            }
+        } catch (e) {
+            const error = e as Error
+            getLogger().error(`Failed to get events of stack ${stackName}: %O`, error)
+            if (!disposed) {
+                await postMessageFn({ command: 'error', data: { message: error.message } })
+            }
+        }
+
+        if (polling && !disposed) {
+            timer = setTimeout(() => poll(postMessageFn), STACK_EVENTS_POLL_INTERVAL_MILLIS)
+        }
+    }
+
+    await createVueWebview<StackEventsRequest, StackEventsResponse>({
+        id: 'cloudFormationStackEvents',
+        name: localize('AWS.cloudFormation.stackEvents.title', 'Stack Events: {0}', stackName),
+        webviewJs: 'cloudFormationStackEventsVue.js',
+        cssFiles: ['cloudFormationStackEvents.css'],
+        context,
+        persistWithoutFocus: true,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            switch (message.command) {
+                case 'initialize':
+                    await postMessageFn({ command: 'stack', data: { stackName, region: client.regionCode } })
+                    if (timer) {
+                        clearTimeout(timer)
+                    }
+                    await poll(postMessageFn)
+                    break
+                case 'revealResource':
+                    await revealResource(message.data.logicalId, templatePath)
+                    break
+            }
+        },
+        onDidDisposeFunction: () => {
+            disposed = true
+            if (timer) {
+                clearTimeout(timer)
+            }
+        },
+    })
+}
+
+/**
+ * Opens the events of a stack in the AWS Explorer.
+ */
+export async function viewStackEventsCommand(node: CloudFormationStackNode): Promise<void> {
+    try {
+        await openStackEvents({
+            client: ext.toolkitClientBuilder.createCloudFormationClient(node.regionCode),
+            stackName: node.stackName,
+        })
+        recordCloudformationViewStackEvents({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to open events of stack ${node.stackName}: %O`, e as Error)
+        recordCloudformationViewStackEvents({ result: 'Failed' })
+    }
+}
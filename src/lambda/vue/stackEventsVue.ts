@@ -0,0 +1,123 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import { StackEventItem, StackEventsResponse } from './stackEvents'
+
+declare const vscode: WebviewApi<null>
+
+export interface StackEventsVueData {
+    stackName: string
+    region: string
+    events: StackEventItem[]
+    stackStatus: string
+    polling: boolean
+    errorMsg: string
+}
+
+export const Component = Vue.extend({
+    created() {
+        window.addEventListener('message', ev => {
+            const event = ev.data as StackEventsResponse
+            switch (event.command) {
+                case 'stack':
+                    this.stackName = event.data.stackName
+                    this.region = event.data.region
+                    break
+                case 'events':
+                    this.errorMsg = ''
+                    this.events = event.data.events
+                    this.stackStatus = event.data.stackStatus ?? ''
+                    this.polling = event.data.polling
+                    break
+                case 'error':
+                    this.polling = false
+                    this.errorMsg = event.data.message
+                    break
+            }
+        })
+        this.polling = true
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): StackEventsVueData {
+        return {
+            stackName: '',
+            region: '',
+            events: [],
+            stackStatus: '',
+            polling: false,
+            errorMsg: '',
+        }
+    },
+    computed: {
+        firstFailure(): StackEventItem | undefined {
+            // events are newest first
+            for (let i = this.events.length - 1; i >= 0; i--) {
+                if (this.events[i].failed) {
+                    return this.events[i]
+                }
+            }
+
+            return undefined
+        },
+    },
+    methods: {
+        revealResource(event: StackEventItem) {
+            vscode.postMessage({ command: 'revealResource', data: { logicalId: event.logicalId } })
+        },
+    },
+    template: `
+    <div class="stack-events">
+        <h1>{{ stackName }}</h1>
+        <p class="stack-status">
+            <span>{{ region }}</span>
+            <span v-if="stackStatus">{{ stackStatus }}</span>
+            <span v-if="polling">Waiting for the stack to finish...</span>
+        </p>
+        <p class="error" v-if="errorMsg">{{ errorMsg }}</p>
+        <div class="first-failure" v-if="firstFailure">
+            <strong>{{ firstFailure.logicalId }}</strong>
+            {{ firstFailure.status }}: {{ firstFailure.reason }}
+        </div>
+        <table v-if="events.length > 0">
+            <thead>
+                <tr>
+                    <th>Timestamp</th>
+                    <th>Logical ID</th>
+                    <th>Type</th>
+                    <th>Status</th>
+                    <th>Status reason</th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr v-for="event in events" :key="event.id" :class="{ failed: event.failed }">
+                    <td class="event-timestamp">{{ event.timestamp }}</td>
+                    <td>
+                        <a
+                            v-if="event.failed"
+                            href="#"
+                            title="Show resource in the template"
+                            v-on:click.prevent="revealResource(event)"
+                        >{{ event.logicalId }}</a>
+                        <span v-else>{{ event.logicalId }}</span>
+                    </td>
+                    <td>{{ event.resourceType }}</td>
+                    <td>{{ event.status }}</td>
+                    <td>{{ event.reason }}</td>
+                </tr>
+            </tbody>
+        </table>
+        <p v-else-if="!errorMsg">No events found.</p>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
@@ -11,7 +11,6 @@ const localize = nls.loadMessageBundle()
 import * as path from 'path'
 import * as vscode from 'vscode'
 import { samDeployDocUrl } from '../../shared/constants'
-import * as localizedText from '../../shared/localizedText'
 import { getLogger } from '../../shared/logger'
 import { getRegionsForActiveCredentials } from '../../shared/regions/regionUtilities'
 import { createHelpButton } from '../../shared/ui/buttons'
@@ -27,8 +26,7 @@ import {
     WizardStep,
     WIZARD_RETRY,
 } from '../../shared/wizards/multiStepWizard'
-import { configureParameterOverrides } from '../config/configureParameterOverrides'
-import { getOverriddenParameters, getParameters } from '../config/parameterUtils'
+import { getOverriddenParameters, getParameters, TemplateParameter } from '../config/parameterUtils'
 import { ext } from '../../shared/extensionGlobals'
 import { EcrRepository } from '../../shared/clients/ecrClient'
 import { getSamCliVersion } from '../../shared/sam/cli/samCliContext'
@@ -43,13 +41,29 @@ import { SettingsConfiguration } from '../../shared/settingsConfiguration'
 const CREATE_NEW_BUCKET = localize('AWS.command.s3.createBucket', 'Create Bucket...')
 const ENTER_BUCKET = localize('AWS.samcli.deploy.bucket.existingLabel', 'Enter Existing Bucket Name...')
 export const CHOSEN_BUCKET_KEY = 'manuallySelectedBuckets'
+export const SAVED_STACKS_KEY = 'savedStackParameters'
 
 export interface SavedBuckets {
     [profile: string]: { [region: string]: string }
 }
 
+/**
+ * Parameter overrides and tags last deployed to a stack. Values of `NoEcho` parameters are never saved.
+ */
+export interface SavedStack {
+    parameterOverrides: { [name: string]: string }
+    tags: { [key: string]: string }
+}
+
+export interface SavedStacks {
+    [region: string]: { [stackName: string]: SavedStack }
+}
+
 export interface SamDeployWizardResponse {
     parameterOverrides: Map<string, string>
+    /** Names of `NoEcho` parameters, whose values must not be persisted. */
+    noEchoParameters?: Set<string>
+    tags?: Map<string, string>
     region: string
     template: vscode.Uri
     s3Bucket: string
@@ -57,11 +71,6 @@ export interface SamDeployWizardResponse {
     stackName: string
 }
 
-export const enum ParameterPromptResult {
-    Cancel,
-    Continue,
-}
-
 export interface SamDeployWizardContext {
     readonly extContext: ExtContext
     readonly workspaceFolders: vscode.Uri[] | undefined
@@ -99,20 +108,6 @@ export interface SamDeployWizardContext {
      */
     promptUserForSamTemplate(initialValue?: vscode.Uri): Promise<vscode.Uri | undefined>
 
-    /**
-     * Prompts the user to configure parameter overrides, then either pre-fills and opens
-     * `templates.json`, or returns true.
-     *
-     * @param options.templateUri The URL of the SAM template to inspect.
-     * @param options.missingParameters The names of required parameters that are not yet overridden.
-     * @returns A value indicating whether the wizard should proceed. `false` if `missingParameters` was
-     *          non-empty, or if it was empty and the user opted to configure overrides instead of continuing.
-     */
-    promptUserForParametersIfApplicable(options: {
-        templateUri: vscode.Uri
-        missingParameters?: Set<string>
-    }): Promise<ParameterPromptResult>
-
     promptUserForRegion(step: number, initialValue?: string): Promise<string | undefined>
 
     /**
@@ -174,24 +169,40 @@ export interface SamDeployWizardContext {
      * @returns Stack name. Undefined represents cancel.
      */
     promptUserForStackName({
+        step,
         initialValue,
         validateInput,
     }: {
+        step: number
         initialValue?: string
         validateInput(value: string): string | undefined
     }): Promise<string | undefined>
-}
 
-function getSingleResponse(responses: vscode.QuickPickItem[] | undefined): string | undefined {
-    if (!responses) {
-        return undefined
-    }
-
-    if (responses.length !== 1) {
-        throw new Error(`Expected a single response, but got ${responses.length}`)
-    }
+    /**
+     * Retrieves the value of a template parameter from the user. Parameters with `AllowedValues` are
+     * picked from a list, and the input of `NoEcho` parameters is masked.
+     *
+     * @param options.initialValue Optional, Initial value to prompt with
+     *
+     * @returns Parameter value. Undefined represents cancel.
+     */
+    promptUserForParameter(
+        step: number,
+        options: {
+            name: string
+            parameter: TemplateParameter
+            initialValue?: string
+        }
+    ): Promise<string | undefined>
 
-    return responses[0].label
+    /**
+     * Retrieves stack tags from the user, as space-separated `key=value` pairs.
+     *
+     * @param initialValue Optional, Initial value to prompt with
+     *
+     * @returns Tags. Undefined represents cancel.
+     */
+    promptUserForTags(step: number, initialValue?: string): Promise<string | undefined>
 }
 
 /**
@@ -235,12 +246,73 @@ export function writeSavedBucket(
     )
 }
 
+export function readSavedStack(
+    settings: SettingsConfiguration,
+    region: string,
+    stackName: string
+): SavedStack | undefined {
+    return settings.readSetting<SavedStacks | undefined>(SAVED_STACKS_KEY)?.[region]?.[stackName]
+}
+
+/**
+ * Saves the parameter overrides and tags of a deployed stack so that the next deploy to it can pre-fill them.
+ * Values of `NoEcho` parameters are left out.
+ */
+export function writeSavedStack(
+    settings: SettingsConfiguration,
+    region: string,
+    stackName: string,
+    {
+        parameterOverrides,
+        noEchoParameters = new Set<string>(),
+        tags = new Map<string, string>(),
+    }: Pick<SamDeployWizardResponse, 'parameterOverrides' | 'noEchoParameters' | 'tags'>
+): void {
+    const oldStacks = settings.readSetting<SavedStacks | undefined>(SAVED_STACKS_KEY)
+    const savedOverrides: { [name: string]: string } = {}
+    for (const [name, value] of parameterOverrides) {
+        if (!noEchoParameters.has(name)) {
+            savedOverrides[name] = value
+        }
+    }
+
+    settings.writeSetting(
+        SAVED_STACKS_KEY,
+        {
+            ...oldStacks,
+            [region]: {
+                ...(oldStacks && oldStacks[region] ? oldStacks[region] : {}),
+                [stackName]: { parameterOverrides: savedOverrides, tags: _.fromPairs([...tags]) },
+            },
+        } as SavedStacks,
+        vscode.ConfigurationTarget.Global
+    )
+}
+
+/**
+ * Parses space-separated `key=value` pairs, as accepted by `sam deploy --tags`.
+ *
+ * @returns the tags, or undefined if any pair is malformed
+ */
+export function parseTags(text: string): Map<string, string> | undefined {
+    const tags = new Map<string, string>()
+    for (const pair of text.split(/\s+/).filter(pair => pair !== '')) {
+        const separator = pair.indexOf('=')
+        if (separator < 1) {
+            return undefined
+        }
+        tags.set(pair.substring(0, separator), pair.substring(separator + 1))
+    }
+
+    return tags
+}
+
 export class DefaultSamDeployWizardContext implements SamDeployWizardContext {
     public readonly getParameters = getParameters
     public readonly getOverriddenParameters = getOverriddenParameters
     private readonly helpButton = createHelpButton(localize('AWS.command.help', 'View Toolkit Documentation'))
 
-    private readonly totalSteps: number = 4
+    private readonly totalSteps: number = 5
     public additionalSteps: number = 0
     public newBucketCalled = false
 
@@ -301,97 +373,6 @@ export class DefaultSamDeployWizardContext implements SamDeployWizardContext {
         return val ? val.uri : undefined
     }
 
-    public async promptUserForParametersIfApplicable({
-        templateUri,
-        missingParameters = new Set<string>(),
-    }: {
-        templateUri: vscode.Uri
-        missingParameters?: Set<string>
-    }): Promise<ParameterPromptResult> {
-        if (missingParameters.size < 1) {
-            const prompt = localize(
-                'AWS.samcli.deploy.parameters.optionalPrompt.message',
-                // prettier-ignore
-                'The template {0} contains parameters. Would you like to override the default values for these parameters?',
-                templateUri.fsPath
-            )
-            const quickPick = picker.createQuickPick<vscode.QuickPickItem>({
-                options: {
-                    ignoreFocusOut: true,
-                    title: prompt,
-                    step: 2,
-                    totalSteps: this.totalSteps + this.additionalSteps,
-                },
-                buttons: [this.helpButton, vscode.QuickInputButtons.Back],
-                items: [{ label: localizedText.yes }, { label: localizedText.no }],
-            })
-            const response = getSingleResponse(
-                await picker.promptUser({
-                    picker: quickPick,
-                    onDidTriggerButton: (button, resolve, reject) => {
-                        if (button === vscode.QuickInputButtons.Back) {
-                            resolve(undefined)
-                        } else if (button === this.helpButton) {
-                            vscode.env.openExternal(vscode.Uri.parse(samDeployDocUrl))
-                        }
-                    },
-                })
-            )
-            if (response !== localizedText.yes) {
-                return ParameterPromptResult.Continue
-            }
-
-            await configureParameterOverrides({
-                templateUri,
-                requiredParameterNames: missingParameters.keys(),
-            })
-
-            return ParameterPromptResult.Cancel
-        } else {
-            const prompt = localize(
-                'AWS.samcli.deploy.parameters.mandatoryPrompt.message',
-                // prettier-ignore
-                'The template {0} contains parameters without default values. In order to deploy, you must provide values for these parameters. Configure them now?',
-                templateUri.fsPath
-            )
-            const responseConfigure = localize(
-                'AWS.samcli.deploy.parameters.mandatoryPrompt.responseConfigure',
-                'Configure'
-            )
-            const responseCancel = localizedText.cancel
-
-            // no step number needed since this is a dead end?
-            const quickPick = picker.createQuickPick<vscode.QuickPickItem>({
-                options: {
-                    ignoreFocusOut: true,
-                    title: prompt,
-                },
-                buttons: [this.helpButton, vscode.QuickInputButtons.Back],
-                items: [{ label: responseConfigure }, { label: responseCancel }],
-            })
-            const response = getSingleResponse(
-                await picker.promptUser({
-                    picker: quickPick,
-                    onDidTriggerButton: (button, resolve, reject) => {
-                        if (button === vscode.QuickInputButtons.Back) {
-                            resolve(undefined)
-                        } else if (button === this.helpButton) {
-                            vscode.env.openExternal(vscode.Uri.parse(samDeployDocUrl))
-                        }
-                    },
-                })
-            )
-            if (response === responseConfigure) {
-                await configureParameterOverrides({
-                    templateUri,
-                    requiredParameterNames: missingParameters.keys(),
-                })
-            }
-
-            return ParameterPromptResult.Cancel
-        }
-    }
-
     public async promptUserForRegion(step: number, initialRegionCode?: string): Promise<string | undefined> {
         const partitionRegions = getRegionsForActiveCredentials(
             this.extContext.awsContext,
@@ -624,9 +605,11 @@ export class DefaultSamDeployWizardContext implements SamDeployWizardContext {
      * @returns Stack name. Undefined represents cancel.
      */
     public async promptUserForStackName({
+        step,
         initialValue,
         validateInput,
     }: {
+        step: number
         initialValue?: string
         validateInput(value: string): string | undefined
     }): Promise<string | undefined> {
@@ -635,7 +618,7 @@ export class DefaultSamDeployWizardContext implements SamDeployWizardContext {
             options: {
                 title: localize('AWS.samcli.deploy.stackName.prompt', 'Enter the name to use for the deployed stack'),
                 ignoreFocusOut: true,
-                step: 4 + this.additionalSteps,
+                step: step,
                 totalSteps: this.totalSteps + this.additionalSteps,
             },
         })
@@ -657,6 +640,120 @@ export class DefaultSamDeployWizardContext implements SamDeployWizardContext {
             },
         })
     }
+
+    public async promptUserForParameter(
+        step: number,
+        {
+            name,
+            parameter,
+            initialValue,
+        }: {
+            name: string
+            parameter: TemplateParameter
+            initialValue?: string
+        }
+    ): Promise<string | undefined> {
+        const title = localize('AWS.samcli.deploy.parameters.prompt', 'Enter a value for parameter {0}', name)
+
+        if (parameter.allowedValues && parameter.allowedValues.length > 0) {
+            const items: vscode.QuickPickItem[] = parameter.allowedValues.map(value => ({
+                label: value,
+                description:
+                    value === parameter.defaultValue ? localize('AWS.samcli.deploy.parameters.default', 'default') : '',
+            }))
+            const quickPick = picker.createQuickPick<vscode.QuickPickItem>({
+                options: {
+                    title: title,
+                    placeHolder: parameter.description,
+                    ignoreFocusOut: true,
+                    step: step,
+                    totalSteps: this.totalSteps + this.additionalSteps,
+                },
+                buttons: [this.helpButton, vscode.QuickInputButtons.Back],
+                items: items,
+            })
+            quickPick.activeItems = items.filter(item => item.label === initialValue)
+
+            const choices = await picker.promptUser({
+                picker: quickPick,
+                onDidTriggerButton: (button, resolve, reject) => {
+                    if (button === vscode.QuickInputButtons.Back) {
+                        resolve(undefined)
+                    } else if (button === this.helpButton) {
+                        vscode.env.openExternal(vscode.Uri.parse(samDeployDocUrl))
+                    }
+                },
+            })
+
+            return picker.verifySinglePickerOutput(choices)?.label
+        }
+
+        const inputBox = input.createInputBox({
+            buttons: [this.helpButton, vscode.QuickInputButtons.Back],
+            options: {
+                title: title,
+                prompt: parameter.description,
+                ignoreFocusOut: true,
+                step: step,
+                totalSteps: this.totalSteps + this.additionalSteps,
+            },
+        })
+        inputBox.password = !!parameter.noEcho
+
+        if (initialValue) {
+            inputBox.value = initialValue
+        }
+
+        return await input.promptUser({
+            inputBox: inputBox,
+            onValidateInput: value =>
+                parameter.required && !value
+                    ? localize('AWS.samcli.deploy.parameters.error.required', 'Parameter {0} requires a value', name)
+                    : undefined,
+            onDidTriggerButton: (button, resolve, reject) => {
+                if (button === vscode.QuickInputButtons.Back) {
+                    resolve(undefined)
+                } else if (button === this.helpButton) {
+                    vscode.env.openExternal(vscode.Uri.parse(samDeployDocUrl))
+                }
+            },
+        })
+    }
+
+    public async promptUserForTags(step: number, initialValue?: string): Promise<string | undefined> {
+        const inputBox = input.createInputBox({
+            buttons: [this.helpButton, vscode.QuickInputButtons.Back],
+            options: {
+                title: localize('AWS.samcli.deploy.tags.prompt', 'Enter tags to apply to the stack (optional)'),
+                placeHolder: 'key1=value1 key2=value2',
+                ignoreFocusOut: true,
+                step: step,
+                totalSteps: this.totalSteps + this.additionalSteps,
+            },
+        })
+
+        if (initialValue) {
+            inputBox.value = initialValue
+        }
+
+        return await input.promptUser({
+            inputBox: inputBox,
+            onValidateInput: value =>
+                parseTags(value) === undefined
+                    ? localize(
+                          'AWS.samcli.deploy.tags.error.invalid',
+                          'Tags must be space-separated key=value pairs, e.g. team=serverless stage=beta'
+                      )
+                    : undefined,
+            onDidTriggerButton: (button, resolve, reject) => {
+                if (button === vscode.QuickInputButtons.Back) {
+                    resolve(undefined)
+                } else if (button === this.helpButton) {
+                    vscode.env.openExternal(vscode.Uri.parse(samDeployDocUrl))
+                }
+            },
+        })
+    }
 }
 
 export class SamDeployWizard extends MultiStepWizard<SamDeployWizardResponse> {
@@ -666,6 +763,12 @@ export class SamDeployWizard extends MultiStepWizard<SamDeployWizardResponse> {
      * an ECR repo to push the images to
      */
     private hasImages: boolean = false
+    /**
+     * Template parameters to prompt for after the stack name, along with values from `templates.json` to pre-fill.
+     * Empty if the template has no parameters, or if `templates.json` overrides every required parameter.
+     */
+    private parameters: [string, TemplateParameter][] = []
+    private legacyOverrides = new Map<string, string>()
 
     /**
      * Initial treenode passed as a command arg to the "Deploy" command
@@ -705,13 +808,16 @@ export class SamDeployWizard extends MultiStepWizard<SamDeployWizardResponse> {
             !this.response.template ||
             !this.response.region ||
             !this.response.s3Bucket ||
-            !this.response.stackName
+            !this.response.stackName ||
+            !this.response.tags
         ) {
             return undefined
         }
 
         return {
             parameterOverrides: this.response.parameterOverrides,
+            noEchoParameters: this.response.noEchoParameters,
+            tags: this.response.tags,
             template: this.response.template,
             region: this.response.region,
             s3Bucket: this.response.s3Bucket,
@@ -739,16 +845,6 @@ export class SamDeployWizard extends MultiStepWizard<SamDeployWizardResponse> {
             return WIZARD_TERMINATE
         }
 
-        // also ask user to setup CFN parameters if they haven't already done so
-        const getNextStep = (result: ParameterPromptResult) => {
-            switch (result) {
-                case ParameterPromptResult.Cancel:
-                    return WIZARD_TERMINATE
-                case ParameterPromptResult.Continue:
-                    return wizardContinue(this.skipOrPromptRegion(this.S3_BUCKET))
-            }
-        }
-
         this.hasImages = await this.context.determineIfTemplateHasImages(this.response.template)
         if (this.hasImages) {
             // TODO: remove check when min version is high enough
@@ -767,45 +863,31 @@ export class SamDeployWizard extends MultiStepWizard<SamDeployWizardResponse> {
         }
 
         const parameters = await this.context.getParameters(this.response.template)
+        this.parameters = []
+        this.legacyOverrides = new Map<string, string>()
+        this.response.parameterOverrides = new Map<string, string>()
+        this.response.noEchoParameters = new Set<string>(
+            filter(parameters.keys(), name => !!parameters.get(name)!.noEcho)
+        )
         if (parameters.size < 1) {
-            this.response.parameterOverrides = new Map<string, string>()
-
             return wizardContinue(this.skipOrPromptRegion(this.S3_BUCKET))
         }
 
+        // Overrides in the legacy `templates.json` are used as-is if they cover every required parameter.
+        // Otherwise, prompt for each parameter after the stack name, pre-filling what `templates.json` has.
         const requiredParameterNames = new Set<string>(
             filter(parameters.keys(), name => parameters.get(name)!.required)
         )
         const overriddenParameters = await this.context.getOverriddenParameters(this.response.template)
-        if (!overriddenParameters) {
-            // In there are no missing required parameters case, it isn't mandatory to override any parameters,
-            // but we still want to inform users of the option to override. Once we have prompted (i.e., if the
-            // parameter overrides section is empty instead of undefined), don't prompt again unless required.
-            this.context.additionalSteps++
-
-            const options = {
-                templateUri: this.response.template,
-                missingParameters: requiredParameterNames.size > 0 ? requiredParameterNames : undefined,
-            }
-
-            this.response.parameterOverrides = new Map<string, string>()
+        if (overriddenParameters && difference(requiredParameterNames, overriddenParameters.keys()).size < 1) {
+            this.response.parameterOverrides = overriddenParameters
 
-            return getNextStep(await this.context.promptUserForParametersIfApplicable(options))
-        }
-
-        const missingParameters = difference(requiredParameterNames, overriddenParameters.keys())
-        if (missingParameters.size > 0) {
-            this.context.additionalSteps++
-
-            return getNextStep(
-                await this.context.promptUserForParametersIfApplicable({
-                    templateUri: this.response.template,
-                    missingParameters,
-                })
-            )
+            return wizardContinue(this.skipOrPromptRegion(this.S3_BUCKET))
         }
 
-        this.response.parameterOverrides = overriddenParameters
+        this.parameters = [...parameters.entries()]
+        this.legacyOverrides = overriddenParameters ?? new Map<string, string>()
+        this.context.additionalSteps += parameters.size
 
         return wizardContinue(this.skipOrPromptRegion(this.S3_BUCKET))
     }
@@ -882,13 +964,74 @@ export class SamDeployWizard extends MultiStepWizard<SamDeployWizardResponse> {
         return response ? wizardContinue(this.STACK_NAME) : WIZARD_GOBACK
     }
 
-    private readonly STACK_NAME: WizardStep = async () => {
+    private readonly STACK_NAME: WizardStep = async step => {
         this.response.stackName = await this.context.promptUserForStackName({
+            step,
             initialValue: this.response.stackName,
             validateInput: validateStackName,
         })
 
-        return this.response.stackName ? WIZARD_TERMINATE : WIZARD_GOBACK
+        if (!this.response.stackName) {
+            return WIZARD_GOBACK
+        }
+
+        return this.parameters.length > 0 ? wizardContinue(this.parameterStep(0)) : wizardContinue(this.TAGS)
+    }
+
+    /**
+     * Prompts for the template parameter at `index`, pre-filled with (in order of precedence) the value entered
+     * earlier in this wizard, the value from `templates.json`, the value last deployed to the stack, or the default.
+     */
+    private parameterStep(index: number): WizardStep {
+        return async step => {
+            const [name, parameter] = this.parameters[index]
+            const savedStack = readSavedStack(
+                this.context.extContext.settings,
+                this.response.region!,
+                this.response.stackName!
+            )
+            const value = await this.context.promptUserForParameter(step, {
+                name,
+                parameter,
+                initialValue:
+                    this.response.parameterOverrides!.get(name) ??
+                    this.legacyOverrides.get(name) ??
+                    (parameter.noEcho ? undefined : savedStack?.parameterOverrides[name]) ??
+                    parameter.defaultValue,
+            })
+
+            if (value === undefined) {
+                return WIZARD_GOBACK
+            }
+
+            // An empty value for an optional parameter leaves it to the template default.
+            if (value === '' && !parameter.required) {
+                this.response.parameterOverrides!.delete(name)
+            } else {
+                this.response.parameterOverrides!.set(name, value)
+            }
+
+            return index + 1 < this.parameters.length
+                ? wizardContinue(this.parameterStep(index + 1))
+                : wizardContinue(this.TAGS)
+        }
+    }
+
+    private readonly TAGS: WizardStep = async step => {
+        const savedTags = readSavedStack(
+            this.context.extContext.settings,
+            this.response.region!,
+            this.response.stackName!
+        )?.tags
+        const initialTags = this.response.tags ?? new Map(Object.entries(savedTags ?? {}))
+        const response = await this.context.promptUserForTags(
+            step,
+            [...initialTags].map(([key, value]) => `${key}=${value}`).join(' ')
+        )
+
+        this.response.tags = response === undefined ? undefined : parseTags(response)
+
+        return this.response.tags ? WIZARD_TERMINATE : WIZARD_GOBACK
     }
 
     private skipOrPromptRegion(skipToStep: WizardStep): WizardStep {
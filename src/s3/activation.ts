@@ -4,12 +4,16 @@
  */
 
 import * as vscode from 'vscode'
+import { compareObjectsCommand, S3ObjectDocumentProvider, S3_COMPARE_SCHEME } from './commands/compareObjects'
 import { copyPathCommand } from './commands/copyPath'
+import { copyPresignedUrlCommand } from './commands/copyPresignedUrl'
 import { createBucketCommand } from './commands/createBucket'
 import { createFolderCommand } from './commands/createFolder'
 import { deleteBucketCommand } from './commands/deleteBucket'
 import { deleteFileCommand } from './commands/deleteFile'
 import { downloadFileAsCommand } from './commands/downloadFileAs'
+import { downloadFolderCommand } from './commands/downloadFolder'
+import { clearObjectFilterCommand, filterObjectsCommand } from './commands/filterObjects'
 import { uploadFileCommand } from './commands/uploadFile'
 import { uploadFileToParentCommand } from './commands/uploadFileToParent'
 import { S3BucketNode } from './explorer/s3BucketNode'
@@ -24,12 +28,22 @@ import { ExtContext } from '../shared/extensions'
  */
 export async function activate(ctx: ExtContext): Promise<void> {
     ctx.extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.s3.compareObjects', async (node: S3FileNode) => {
+            await compareObjectsCommand(node)
+        }),
+        vscode.workspace.registerTextDocumentContentProvider(S3_COMPARE_SCHEME, new S3ObjectDocumentProvider()),
         vscode.commands.registerCommand('aws.s3.copyPath', async (node: S3FolderNode | S3FileNode) => {
             await copyPathCommand(node)
         }),
+        vscode.commands.registerCommand('aws.s3.copyPresignedUrl', async (node: S3FileNode) => {
+            await copyPresignedUrlCommand(node)
+        }),
         vscode.commands.registerCommand('aws.s3.downloadFileAs', async (node: S3FileNode) => {
             await downloadFileAsCommand(node)
         }),
+        vscode.commands.registerCommand('aws.s3.downloadFolder', async (node: S3FolderNode) => {
+            await downloadFolderCommand(node)
+        }),
         vscode.commands.registerCommand('aws.s3.uploadFile', async (node: S3BucketNode | S3FolderNode) => {
             if (!node) {
                 const awsContext = ctx.awsContext
@@ -50,6 +64,12 @@ export async function activate(ctx: ExtContext): Promise<void> {
         vscode.commands.registerCommand('aws.s3.createFolder', async (node: S3BucketNode | S3FolderNode) => {
             await createFolderCommand(node)
         }),
+        vscode.commands.registerCommand('aws.s3.filterObjects', async (node: S3BucketNode | S3FolderNode) => {
+            await filterObjectsCommand(node)
+        }),
+        vscode.commands.registerCommand('aws.s3.clearObjectFilter', async (node: S3BucketNode | S3FolderNode) => {
+            await clearObjectFilterCommand(node)
+        }),
         vscode.commands.registerCommand('aws.s3.deleteBucket', async (node: S3BucketNode) => {
             await deleteBucketCommand(node)
         }),
@@ -0,0 +1,263 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as moment from 'moment'
+import * as vscode from 'vscode'
+import { ObjectVersion } from '../../shared/clients/s3Client'
+import { getLogger } from '../../shared/logger'
+import { recordS3CompareObjects } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { addCodiconToString } from '../../shared/utilities/textUtilities'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { formatBytes, S3FileNode, S3_DATE_FORMAT } from '../explorer/s3FileNode'
+import { readablePath } from '../util'
+
+export const S3_COMPARE_SCHEME = 'aws-s3-compare'
+
+/** Objects larger than this are only downloaded for a comparison after confirming. */
+export const LARGE_OBJECT_BYTES = 5 * 1024 * 1024
+
+/** How much of an object is checked for NUL bytes to tell whether it is binary, as git does. */
+const BINARY_CHECK_BYTES = 8000
+
+/** An object, or a version of it, to compare. */
+interface ObjectToCompare {
+    readonly key: string
+    readonly versionId?: string
+    readonly sizeBytes?: number
+    /** Tells the object apart in the diff title, e.g. by its version. */
+    readonly label: string
+}
+
+type VersionItem = vscode.QuickPickItem & { version?: ObjectVersion }
+
+/** Contents of the opened comparisons, keyed by document URI. */
+const objectDocuments = new Map<string, string>()
+
+/**
+ * Whether content is binary rather than text: binary files almost always have a NUL byte near the start,
+ * and text files never do.
+ */
+export function isBinary(content: Buffer): boolean {
+    return content.subarray(0, BINARY_CHECK_BYTES).includes(0)
+}
+
+/**
+ * Compares an object with another version of it, or with another object of its bucket, in a diff editor.
+ *
+ * Objects are downloaded into memory and opened as read-only documents, so large objects are only
+ * downloaded after confirming, and binary objects are refused.
+ */
+export async function compareObjectsCommand(
+    node: S3FileNode,
+    window = Window.vscode(),
+    commands = Commands.vscode(),
+    promptUserFunction = promptUser
+): Promise<void> {
+    getLogger().debug('CompareObjects called for %O', node)
+
+    const other = await promptForObject(node, window, promptUserFunction)
+    if (!other) {
+        getLogger().info('CompareObjects cancelled')
+        recordS3CompareObjects({ result: 'Cancelled' })
+        return
+    }
+    const current: ObjectToCompare = {
+        key: node.file.key,
+        sizeBytes: node.file.sizeBytes,
+        label: localize('AWS.s3.compareObjects.latest', 'latest'),
+    }
+
+    try {
+        const sizes = await Promise.all([other, current].map(async object => getSize(node, object)))
+        if (Math.max(...sizes) > LARGE_OBJECT_BYTES && !(await confirmLargeDownload(sizes, window))) {
+            getLogger().info('CompareObjects cancelled for large objects')
+            recordS3CompareObjects({ result: 'Cancelled' })
+            return
+        }
+
+        const contents = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.s3.compareObjects.progressTitle', 'Downloading objects to compare...'),
+            },
+            async () =>
+                Promise.all(
+                    [other, current].map(async object => (await node.getObject(object.key, object.versionId)).body)
+                )
+        )
+
+        const binary = [other, current].find((object, index) => isBinary(contents[index]))
+        if (binary) {
+            window.showWarningMessage(
+                localize(
+                    'AWS.s3.compareObjects.binary',
+                    '{0} is a binary file and cannot be compared as text. Download it with "Download As..." to compare it with another tool.',
+                    binary.key
+                )
+            )
+            recordS3CompareObjects({ result: 'Failed' })
+            return
+        }
+
+        await commands.execute(
+            'vscode.diff',
+            getObjectUri(node, other, contents[0]),
+            getObjectUri(node, current, contents[1]),
+            localize(
+                'AWS.s3.compareObjects.diffTitle',
+                '{0} ({1}) ↔ {2} ({3})',
+                getName(other.key),
+                other.label,
+                node.file.name,
+                current.label
+            )
+        )
+        recordS3CompareObjects({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to compare ${readablePath(node)} with ${other.key}: %O`, e as Error)
+        showErrorWithLogs(
+            localize('AWS.s3.compareObjects.error', 'Failed to compare {0} with {1}', node.file.name, other.key),
+            window
+        )
+        recordS3CompareObjects({ result: 'Failed' })
+    }
+}
+
+async function promptForObject(
+    node: S3FileNode,
+    window: Window,
+    promptUserFunction: typeof promptUser
+): Promise<ObjectToCompare | undefined> {
+    let versions: ObjectVersion[] = []
+    try {
+        versions = (await node.listVersions()).filter(version => !version.isLatest)
+    } catch (e) {
+        // listing versions needs its own permission, and comparing with another object doesn't
+        getLogger().warn(`Failed to list versions of ${readablePath(node)}: %O`, e as Error)
+    }
+    if (versions.length === 0) {
+        return promptForKey(node, window)
+    }
+
+    const otherObject: VersionItem = {
+        label: addCodiconToString(
+            'file',
+            localize('AWS.s3.compareObjects.otherObject', 'Another object in {0}...', node.bucket.name)
+        ),
+        alwaysShow: true,
+    }
+    const picker = createQuickPick<VersionItem>({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.s3.compareObjects.title', 'Compare {0} with...', node.file.name),
+            placeHolder: localize('AWS.s3.compareObjects.placeholder', 'Choose a previous version, or another object'),
+            matchOnDetail: true,
+        },
+        items: [
+            ...versions.map(version => ({
+                label: version.lastModified ? moment(version.lastModified).format(S3_DATE_FORMAT) : version.versionId,
+                description: version.sizeBytes !== undefined ? formatBytes(version.sizeBytes) : undefined,
+                detail: localize('AWS.s3.compareObjects.versionId', 'Version {0}', version.versionId),
+                version,
+            })),
+            otherObject,
+        ],
+    })
+    const choice = verifySinglePickerOutput(await promptUserFunction({ picker }))
+    if (!choice) {
+        return undefined
+    }
+    if (!choice.version) {
+        return promptForKey(node, window)
+    }
+
+    return {
+        key: node.file.key,
+        versionId: choice.version.versionId,
+        sizeBytes: choice.version.sizeBytes,
+        label: choice.label,
+    }
+}
+
+async function promptForKey(node: S3FileNode, window: Window): Promise<ObjectToCompare | undefined> {
+    const key = await window.showInputBox({
+        prompt: localize(
+            'AWS.s3.compareObjects.key.prompt',
+            'Enter the key of the object in {0} to compare {1} with',
+            node.bucket.name,
+            node.file.name
+        ),
+        // start in the folder of the object, where the other object usually is
+        value: node.file.key.slice(0, node.file.key.length - node.file.name.length),
+        ignoreFocusOut: true,
+        validateInput: value => {
+            if (!value) {
+                return localize('AWS.s3.compareObjects.key.empty', 'Enter the key of an object')
+            }
+            if (value === node.file.key) {
+                return localize('AWS.s3.compareObjects.key.same', 'Enter the key of another object')
+            }
+
+            return undefined
+        },
+    })
+    if (!key) {
+        return undefined
+    }
+
+    return { key, label: localize('AWS.s3.compareObjects.latest', 'latest') }
+}
+
+async function confirmLargeDownload(sizes: number[], window: Window): Promise<boolean> {
+    return showConfirmationMessage(
+        {
+            prompt: localize(
+                'AWS.s3.compareObjects.largeObjects',
+                'The objects to compare are {0} and {1}. Download them to compare?',
+                formatBytes(sizes[0]),
+                formatBytes(sizes[1])
+            ),
+            confirm: localize('AWS.s3.compareObjects.download', 'Download'),
+            cancel: localize('AWS.generic.cancel', 'Cancel'),
+        },
+        window
+    )
+}
+
+async function getSize(node: S3FileNode, object: ObjectToCompare): Promise<number> {
+    if (object.sizeBytes !== undefined) {
+        return object.sizeBytes
+    }
+
+    return (await node.headObject(object.key, object.versionId)).sizeBytes ?? 0
+}
+
+function getName(key: string): string {
+    return key.split('/').pop() ?? key
+}
+
+function getObjectUri(node: S3FileNode, object: ObjectToCompare, content: Buffer): vscode.Uri {
+    // the path keeps the extension of the key, so the document gets the language of the object
+    const uri = vscode.Uri.parse(`${S3_COMPARE_SCHEME}:/`).with({
+        path: `/${node.bucket.name}/${object.key}`,
+        query: object.versionId ? `versionId=${encodeURIComponent(object.versionId)}` : '',
+    })
+    objectDocuments.set(uri.toString(), content.toString('utf8'))
+
+    return uri
+}
+
+/**
+ * Serves the contents of the objects compared by {@link compareObjectsCommand}.
+ */
+export class S3ObjectDocumentProvider implements vscode.TextDocumentContentProvider {
+    public provideTextDocumentContent(uri: vscode.Uri): string {
+        return objectDocuments.get(uri.toString()) ?? ''
+    }
+}
@@ -0,0 +1,139 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as moment from 'moment'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import * as telemetry from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { addCodiconToString } from '../../shared/utilities/textUtilities'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Env } from '../../shared/vscode/env'
+import { Window } from '../../shared/vscode/window'
+import { S3FileNode } from '../explorer/s3FileNode'
+import { readablePath } from '../util'
+
+const COPY_URL_DISPLAY_TIMEOUT_MS = 2000
+export const DEFAULT_PRESIGNED_URL_EXPIRATION = '1h'
+/** SigV4 presigned URLs are valid for at most 7 days. */
+export const MAX_PRESIGNED_URL_EXPIRATION_SECONDS = 7 * 24 * 60 * 60
+
+const DURATION_UNITS: { [unit: string]: number } = { s: 1, m: 60, h: 60 * 60, d: 24 * 60 * 60 }
+
+/**
+ * Parses a duration such as `90s`, `30m`, `12h` or `7d`.
+ *
+ * @returns the duration in seconds, or undefined if it is not a valid presigned URL expiration
+ */
+export function parseExpiration(text: string): number | undefined {
+    const match = /^\s*(\d+)\s*([smhd])\s*$/i.exec(text)
+    if (!match) {
+        return undefined
+    }
+
+    const seconds = parseInt(match[1], 10) * DURATION_UNITS[match[2].toLowerCase()]
+    if (seconds < 1 || seconds > MAX_PRESIGNED_URL_EXPIRATION_SECONDS) {
+        return undefined
+    }
+
+    return seconds
+}
+
+/**
+ * Copies a presigned URL that downloads the file represented by the given node.
+ *
+ * Prompts the user for how long the URL is valid. The URL stops working when the credentials that signed it
+ * expire, so the expiration is shortened to that of temporary credentials, with a warning.
+ */
+export async function copyPresignedUrlCommand(
+    node: S3FileNode,
+    window = Window.vscode(),
+    env = Env.vscode(),
+    getCredentials = async () => ext.awsContext.getCredentials()
+): Promise<void> {
+    getLogger().debug('CopyPresignedUrl called for %O', node)
+
+    const expiration = await window.showInputBox({
+        prompt: localize('AWS.s3.copyPresignedUrl.prompt', 'Enter how long the URL is valid, e.g. 30m, 12h, or 7d'),
+        value: DEFAULT_PRESIGNED_URL_EXPIRATION,
+        validateInput: text =>
+            parseExpiration(text) === undefined
+                ? localize(
+                      'AWS.s3.copyPresignedUrl.invalidExpiration',
+                      'Enter a duration between 1 second and 7 days, e.g. 30m, 12h, or 7d'
+                  )
+                : undefined,
+    })
+    if (expiration === undefined) {
+        getLogger().info('CopyPresignedUrl cancelled')
+        telemetry.recordS3CopyPresignedUrl({ result: 'Cancelled' })
+        return
+    }
+
+    const requestedSeconds = parseExpiration(expiration)!
+    let expiresInSeconds = requestedSeconds
+    const credentials = await getCredentials()
+    if (credentials?.expireTime) {
+        const remainingSeconds = Math.floor((credentials.expireTime.getTime() - Date.now()) / 1000)
+        if (remainingSeconds > 0 && remainingSeconds < expiresInSeconds) {
+            expiresInSeconds = remainingSeconds
+        }
+    }
+
+    let url: string
+    try {
+        url = await node.getSignedUrl(expiresInSeconds)
+    } catch (e) {
+        getLogger().error(`Failed to presign URL for ${readablePath(node)}: %O`, e)
+        showErrorWithLogs(
+            localize('AWS.s3.copyPresignedUrl.error', 'Failed to create presigned URL for {0}', node.file.name),
+            window
+        )
+        telemetry.recordS3CopyPresignedUrl({ result: 'Failed' })
+        return
+    }
+
+    await env.clipboard.writeText(url)
+    getLogger().info(`Copied presigned URL for ${readablePath(node)} to clipboard, expires in ${expiresInSeconds}s`)
+    telemetry.recordS3CopyPresignedUrl({ result: 'Succeeded' })
+
+    window.setStatusBarMessage(
+        addCodiconToString(
+            'clippy',
+            localize('AWS.explorerNode.copiedToClipboard', 'Copied {0} to clipboard', 'presigned URL')
+        ),
+        COPY_URL_DISPLAY_TIMEOUT_MS
+    )
+
+    if (expiresInSeconds < requestedSeconds) {
+        window.showWarningMessage(
+            localize(
+                'AWS.s3.copyPresignedUrl.clamped',
+                'The presigned URL expires in {0} instead of {1}, when the credentials that signed it expire.',
+                moment.duration(expiresInSeconds, 'seconds').humanize(),
+                moment.duration(requestedSeconds, 'seconds').humanize()
+            )
+        )
+    }
+
+    if (await isKmsEncrypted(node)) {
+        window.showInformationMessage(
+            localize(
+                'AWS.s3.copyPresignedUrl.kms',
+                '{0} is encrypted with AWS KMS, so the URL only works while its signer can decrypt with the key.',
+                node.file.name
+            )
+        )
+    }
+}
+
+async function isKmsEncrypted(node: S3FileNode): Promise<boolean> {
+    try {
+        return (await node.getServerSideEncryption())?.startsWith('aws:kms') ?? false
+    } catch (e) {
+        getLogger().warn(`Failed to get the encryption of ${readablePath(node)}: %O`, e)
+        return false
+    }
+}
@@ -0,0 +1,262 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { File } from '../../shared/clients/s3Client'
+import { ext } from '../../shared/extensionGlobals'
+import { downloadsDir } from '../../shared/filesystemUtilities'
+import { getLogger } from '../../shared/logger'
+import * as telemetry from '../../shared/telemetry/telemetry'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { showErrorWithLogs, showOutputMessage } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { S3FolderNode } from '../explorer/s3FolderNode'
+import { readablePath, toLocalRelativePath } from '../util'
+
+/** Maximum number of objects downloaded at the same time. */
+export const DOWNLOAD_FOLDER_CONCURRENCY = 8
+
+interface PlannedDownload {
+    readonly file: File
+    readonly localPath: string
+}
+
+/**
+ * Downloads every object under the folder represented by the given node.
+ *
+ * Prompts the user for a local directory.
+ * Lists the folder recursively and maps each key to a path relative to the directory,
+ * renaming keys that can't be used as local file names.
+ * Prompts to overwrite or skip files that already exist.
+ * Downloads the objects (showing a single cancellable progress notification).
+ * Shows the output channel with a summary, including any renamed keys.
+ */
+export async function downloadFolderCommand(
+    node: S3FolderNode,
+    window = Window.vscode(),
+    outputChannel = ext.outputChannel,
+    concurrency = DOWNLOAD_FOLDER_CONCURRENCY
+): Promise<void> {
+    getLogger().debug('DownloadFolder called for %O', node)
+
+    const selection = await window.showOpenDialog({
+        defaultUri: vscode.Uri.file(downloadsDir()),
+        openLabel: localize('AWS.s3.downloadFolder.openButton', 'Download Here'),
+        canSelectFiles: false,
+        canSelectFolders: true,
+        canSelectMany: false,
+    })
+    if (!selection || selection.length === 0) {
+        getLogger().info('DownloadFolder cancelled')
+        telemetry.recordS3DownloadFolder({ result: 'Cancelled' })
+        return
+    }
+    const destination = selection[0].fsPath
+    const sourcePath = readablePath(node)
+
+    try {
+        const files = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Window,
+                title: localize('AWS.s3.downloadFolder.listing', 'Listing objects in {0}...', sourcePath),
+            },
+            async () =>
+                toArrayAsync(node.s3.listFilesRecursive({ bucketName: node.bucket.name, prefix: node.folder.path }))
+        )
+        const { downloads, renamed } = planDownloads(node.folder.path, files, destination)
+        if (downloads.length === 0) {
+            window.showInformationMessage(
+                localize('AWS.s3.downloadFolder.empty', 'No objects found in {0}', sourcePath)
+            )
+            telemetry.recordS3DownloadFolder({ result: 'Succeeded' })
+            return
+        }
+
+        const pending = await filterExistingFiles(downloads, destination, window)
+        if (!pending) {
+            getLogger().info('DownloadFolder cancelled')
+            telemetry.recordS3DownloadFolder({ result: 'Cancelled' })
+            return
+        }
+
+        showOutputMessage(`Downloading ${pending.length} file(s) from ${sourcePath} to ${destination}`, outputChannel)
+        for (const download of renamed) {
+            showOutputMessage(`Renamed ${download.file.key} to ${download.localPath}`, outputChannel)
+        }
+
+        const { succeeded, failed, cancelled } = await downloadWithProgress(node, pending, concurrency, window)
+
+        showOutputMessage(
+            `Downloaded ${succeeded} of ${pending.length} file(s) from ${sourcePath} to ${destination}` +
+                (renamed.length > 0 ? ` (${renamed.length} renamed)` : ''),
+            outputChannel
+        )
+        if (cancelled) {
+            telemetry.recordS3DownloadFolder({ result: 'Cancelled' })
+        } else if (failed.length > 0) {
+            showErrorWithLogs(
+                localize(
+                    'AWS.s3.downloadFolder.error.partial',
+                    'Failed to download {0} of {1} files from {2}',
+                    failed.length,
+                    pending.length,
+                    sourcePath
+                ),
+                window
+            )
+            telemetry.recordS3DownloadFolder({ result: 'Failed' })
+        } else {
+            telemetry.recordS3DownloadFolder({ result: 'Succeeded' })
+        }
+    } catch (e) {
+        getLogger().error(`Failed to download folder from ${sourcePath} to ${destination}: %O`, e)
+        showErrorWithLogs(
+            localize('AWS.s3.downloadFolder.error.general', 'Failed to download folder {0}', node.folder.name),
+            window
+        )
+        telemetry.recordS3DownloadFolder({ result: 'Failed' })
+    }
+}
+
+/**
+ * Maps each object to a local path under the destination, preserving the key structure
+ * relative to the folder. Folder placeholder objects (keys ending in '/') are skipped.
+ */
+function planDownloads(
+    prefix: string,
+    files: File[],
+    destination: string
+): { downloads: PlannedDownload[]; renamed: PlannedDownload[] } {
+    const downloads: PlannedDownload[] = []
+    const renamed: PlannedDownload[] = []
+    const usedPaths = new Set<string>()
+
+    for (const file of files) {
+        const relativeKey = file.key.substring(prefix.length)
+        if (!relativeKey || relativeKey.endsWith('/')) {
+            continue
+        }
+        let relativePath = toLocalRelativePath(relativeKey)
+        // Sanitized keys may collide with each other (e.g. 'a:b' and 'a_b' on Windows)
+        for (let i = 1; usedPaths.has(normalizePath(relativePath)); i++) {
+            relativePath = `${toLocalRelativePath(relativeKey)}_${i}`
+        }
+        usedPaths.add(normalizePath(relativePath))
+
+        const download = { file, localPath: path.join(destination, ...relativePath.split('/')) }
+        downloads.push(download)
+        if (relativePath !== relativeKey) {
+            renamed.push(download)
+        }
+    }
+
+    return { downloads, renamed }
+}
+
+function normalizePath(relativePath: string): string {
+    return process.platform === 'win32' ? relativePath.toLowerCase() : relativePath
+}
+
+/**
+ * Prompts the user to overwrite or skip files that already exist locally.
+ *
+ * @returns the downloads to perform, or undefined if the user cancelled.
+ */
+async function filterExistingFiles(
+    downloads: PlannedDownload[],
+    destination: string,
+    window: Window
+): Promise<PlannedDownload[] | undefined> {
+    const existing = new Set<PlannedDownload>()
+    for (const download of downloads) {
+        if (await fs.pathExists(download.localPath)) {
+            existing.add(download)
+        }
+    }
+    if (existing.size === 0) {
+        return downloads
+    }
+
+    const overwrite = localize('AWS.s3.downloadFolder.overwrite', 'Overwrite')
+    const skip = localize('AWS.s3.downloadFolder.skip', 'Skip Existing')
+    const response = await window.showWarningMessage(
+        localize(
+            'AWS.s3.downloadFolder.prompt.existing',
+            '{0} of {1} files already exist in {2}.',
+            existing.size,
+            downloads.length,
+            destination
+        ),
+        { modal: true },
+        overwrite,
+        skip
+    )
+    if (response === overwrite) {
+        return downloads
+    } else if (response === skip) {
+        return downloads.filter(download => !existing.has(download))
+    }
+
+    return undefined
+}
+
+async function downloadWithProgress(
+    node: S3FolderNode,
+    downloads: PlannedDownload[],
+    concurrency: number,
+    window: Window
+): Promise<{ succeeded: number; failed: PlannedDownload[]; cancelled: boolean }> {
+    return window.withProgress(
+        {
+            location: vscode.ProgressLocation.Notification,
+            title: localize('AWS.s3.downloadFolder.progressTitle', 'Downloading {0}...', node.folder.name),
+            cancellable: true,
+        },
+        async (progress, token) => {
+            let succeeded = 0
+            const failed: PlannedDownload[] = []
+            let next = 0
+
+            const worker = async () => {
+                while (next < downloads.length && !token.isCancellationRequested) {
+                    const download = downloads[next++]
+                    try {
+                        await fs.ensureDir(path.dirname(download.localPath))
+                        await node.s3.downloadFile({
+                            bucketName: node.bucket.name,
+                            key: download.file.key,
+                            saveLocation: vscode.Uri.file(download.localPath),
+                            cancellationToken: token,
+                        })
+                        succeeded++
+                        progress.report({
+                            message: localize(
+                                'AWS.s3.downloadFolder.progress',
+                                '{0}/{1} files',
+                                succeeded,
+                                downloads.length
+                            ),
+                            increment: 100 / downloads.length,
+                        })
+                    } catch (e) {
+                        if (token.isCancellationRequested) {
+                            // Don't leave partially downloaded files behind
+                            await fs.remove(download.localPath)
+                        } else {
+                            getLogger().error(`Failed to download ${download.file.key}: %O`, e)
+                            failed.push(download)
+                        }
+                    }
+                }
+            }
+            await Promise.all(Array.from({ length: Math.min(concurrency, downloads.length) }, worker))
+
+            return { succeeded, failed, cancelled: token.isCancellationRequested }
+        }
+    )
+}
@@ -0,0 +1,66 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import * as telemetry from '../../shared/telemetry/telemetry'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { S3BucketNode } from '../explorer/s3BucketNode'
+import { S3FolderNode } from '../explorer/s3FolderNode'
+import { formatObjectFilter, parseObjectFilter, readablePath } from '../util'
+
+/**
+ * Filters the objects listed under the given bucket or folder node.
+ *
+ * Prompts the user for a prefix and optional suffix in the form `prefix*suffix`.
+ * Submitting an empty value clears the filter.
+ * Refreshes the node, which is reset to displaying its first page of (filtered) results.
+ */
+export async function filterObjectsCommand(
+    node: S3BucketNode | S3FolderNode,
+    window = Window.vscode(),
+    commands = Commands.vscode()
+): Promise<void> {
+    getLogger().debug('FilterObjects called for %O', node)
+
+    const input = await window.showInputBox({
+        prompt: localize(
+            'AWS.s3.filterObjects.prompt',
+            'Filter objects in {0} by prefix and optional suffix (leave empty to clear)',
+            readablePath(node)
+        ),
+        placeHolder: localize('AWS.s3.filterObjects.placeHolder', 'prefix*suffix, e.g. logs/2024*.gz'),
+        value: node.objectFilter ? formatObjectFilter(node.objectFilter) : undefined,
+    })
+
+    if (input === undefined) {
+        getLogger().info('FilterObjects cancelled')
+        telemetry.recordS3FilterObjects({ result: 'Cancelled' })
+        return
+    }
+
+    const filter = parseObjectFilter(input)
+    getLogger().info(`Setting object filter for ${readablePath(node)}: %O`, filter)
+    node.setObjectFilter(filter)
+    telemetry.recordS3FilterObjects({ result: 'Succeeded' })
+
+    await commands.execute('aws.refreshAwsExplorerNode', node)
+}
+
+/**
+ * Removes the object filter from the given bucket or folder node, if any, and refreshes it.
+ */
+export async function clearObjectFilterCommand(
+    node: S3BucketNode | S3FolderNode,
+    commands = Commands.vscode()
+): Promise<void> {
+    if (!node.objectFilter) {
+        return
+    }
+
+    node.setObjectFilter(undefined)
+    await commands.execute('aws.refreshAwsExplorerNode', node)
+}
@@ -24,6 +24,16 @@ import { createBucketCommand } from './createBucket'
 import { recordAwsRefreshExplorer } from '../../shared/telemetry/telemetry'
 import { S3BucketNode } from '../explorer/s3BucketNode'
 import { S3FolderNode } from '../explorer/s3FolderNode'
+import { promptForUploadMetadata, UploadMetadata } from './uploadMetadata'
+import {
+    abortIncompleteUpload,
+    findIncompleteUpload,
+    getPartCount,
+    IncompleteUpload,
+    MULTIPART_THRESHOLD_BYTES,
+    MultipartUploadStore,
+    uploadMultipart,
+} from '../multipartUpload'
 
 export interface FileSizeBytes {
     /**
@@ -35,6 +45,9 @@ export interface FileSizeBytes {
 /**
  * Wizard to upload a file.
  *
+ * Files of at least {@link MULTIPART_THRESHOLD_BYTES} are uploaded in parts, and offered to be resumed if the upload
+ * is interrupted, or if the same file is uploaded to the same key again.
+ *
  * @param s3Client account to upload the file to
  * @param nodeOrDocument node to upload to or file currently open, if undefined then there was no active editor
  *
@@ -47,7 +60,9 @@ export async function uploadFileCommand(
     getFile = getFileToUpload,
     window = Window.vscode(),
     outputChannel = ext.outputChannel,
-    commands = Commands.vscode()
+    commands = Commands.vscode(),
+    getMetadata = promptForUploadMetadata,
+    uploadStore?: MultipartUploadStore
 ): Promise<void> {
     let key: string
     let bucket: S3.Bucket
@@ -120,41 +135,166 @@ export async function uploadFileCommand(
     const fileName = path.basename(file.fsPath)
     const destinationPath = readablePath({ bucket: { name: bucket.Name! }, path: key })
 
-    try {
-        showOutputMessage(localize(
-            'AWS.s3.uploadFile.startUpload', 
-            'Uploading file {0} to {1}', 
-            fileName, 
-            destinationPath), 
-        outputChannel)
-
-        const request = {
-            bucketName: bucket.Name!,
-            key: key,
-            fileLocation: file,
-            fileSizeBytes: fileSizeBytes(file),
-            s3Client,
-            window: window,
+    const sizeBytes = fileSizeBytes(file)
+    const uploads =
+        sizeBytes >= MULTIPART_THRESHOLD_BYTES
+            ? uploadStore ?? new MultipartUploadStore(ext.context.globalState)
+            : undefined
+
+    let resume: IncompleteUpload | undefined
+    if (uploads) {
+        const incomplete = await findIncompleteUpload(uploads, bucket.Name!, key, file)
+        if (incomplete) {
+            const choice = await promptToResume(incomplete, destinationPath, window)
+            if (!choice) {
+                showOutputMessage(
+                    localize('AWS.message.error.uploadFileCommand.cancelled', 'Cancelled upload of {0}', fileName),
+                    outputChannel
+                )
+                getLogger().info('UploadFile cancelled')
+                telemetry.recordS3UploadObject({ result: 'Cancelled' })
+                return
+            }
+            if (choice === 'resume') {
+                resume = incomplete
+            } else {
+                await abortIncompleteUpload(s3Client, uploads, incomplete)
+            }
+        } else {
+            // the file changed since, or another file is uploaded to the key, so the parts can't be reused
+            const stale = uploads.get(bucket.Name!, key)
+            if (stale) {
+                await abortIncompleteUpload(s3Client, uploads, stale)
+            }
         }
+    }
 
-        await uploadWithProgress(request)
-
-        showOutputMessage(localize(
-            'AWS.s3.uploadFile.success', 
-            'Successfully uploaded file {0} to {1}', 
-            fileName, 
-            bucket.Name), 
-        outputChannel)
-        telemetry.recordS3UploadObject({ result: 'Succeeded' })
-        recordAwsRefreshExplorer()
-        commands.execute('aws.refreshAwsExplorer')
-        return
-    } catch (e) {
-        getLogger().error(`Failed to upload file from ${file} to ${destinationPath}: %O`, e)
-        showErrorWithLogs(localize('AWS.s3.uploadFile.error.general', 'Failed to upload file {0}', fileName), window)
-        telemetry.recordS3UploadObject({ result: 'Failed' })
+    // a resumed upload keeps the metadata it was started with, which uploads saved before it was kept lack
+    const metadata = resume?.metadata ?? (await getMetadata(file, window))
+    if (!metadata) {
+        showOutputMessage(
+            localize('AWS.message.error.uploadFileCommand.cancelled', 'Cancelled upload of {0}', fileName),
+            outputChannel
+        )
+        getLogger().info('UploadFile cancelled')
+        telemetry.recordS3UploadObject({ result: 'Cancelled' })
         return
     }
+
+    showOutputMessage(localize(
+        'AWS.s3.uploadFile.startUpload', 
+        'Uploading file {0} to {1}', 
+        fileName, 
+        destinationPath), 
+    outputChannel)
+
+    while (true) {
+        try {
+            const request = {
+                bucketName: bucket.Name!,
+                key: key,
+                fileLocation: file,
+                fileSizeBytes: sizeBytes,
+                s3Client,
+                window: window,
+                metadata,
+                uploads,
+                resume,
+            }
+
+            if (!(await uploadWithProgress(request))) {
+                showOutputMessage(
+                    localize('AWS.message.error.uploadFileCommand.cancelled', 'Cancelled upload of {0}', fileName),
+                    outputChannel
+                )
+                getLogger().info('UploadFile cancelled')
+                telemetry.recordS3UploadObject({ result: 'Cancelled' })
+                return
+            }
+
+            showOutputMessage(localize(
+                'AWS.s3.uploadFile.success', 
+                'Successfully uploaded file {0} to {1}', 
+                fileName, 
+                bucket.Name), 
+            outputChannel)
+            telemetry.recordS3UploadObject({ result: 'Succeeded' })
+            recordAwsRefreshExplorer()
+            commands.execute('aws.refreshAwsExplorer')
+            return
+        } catch (e) {
+            getLogger().error(`Failed to upload file from ${file} to ${destinationPath}: %O`, e)
+            resume = uploads?.get(bucket.Name!, key)
+            if (resume && (await promptToResumeFailed(resume, fileName, window))) {
+                showOutputMessage(
+                    localize(
+                        'AWS.s3.uploadFile.resumeUpload',
+                        'Resuming upload of {0} to {1}',
+                        fileName,
+                        destinationPath
+                    ),
+                    outputChannel
+                )
+                continue
+            }
+            if (!resume) {
+                showErrorWithLogs(
+                    localize('AWS.s3.uploadFile.error.general', 'Failed to upload file {0}', fileName),
+                    window
+                )
+            }
+            telemetry.recordS3UploadObject({ result: 'Failed' })
+            return
+        }
+    }
+}
+
+/**
+ * Asks whether to resume an interrupted upload of the file, or to start over.
+ */
+async function promptToResume(
+    upload: IncompleteUpload,
+    destinationPath: string,
+    window: Window
+): Promise<'resume' | 'startOver' | undefined> {
+    const resumeItem = localize('AWS.s3.uploadFile.resume', 'Resume Upload')
+    const startOverItem = localize('AWS.s3.uploadFile.startOver', 'Start Over')
+    const choice = await window.showInformationMessage(
+        localize(
+            'AWS.s3.uploadFile.incompleteUpload',
+            'An earlier upload of {0} to {1} was interrupted after {2} of {3} parts.',
+            path.basename(upload.filePath),
+            destinationPath,
+            upload.parts.length,
+            getPartCount(upload)
+        ),
+        resumeItem,
+        startOverItem
+    )
+
+    return choice === resumeItem ? 'resume' : choice === startOverItem ? 'startOver' : undefined
+}
+
+/**
+ * Shows that an upload failed, with an action to resume it. Declining leaves the upload to be resumed later, by
+ * uploading the file to the same key again.
+ *
+ * @returns true if the upload should be resumed.
+ */
+async function promptToResumeFailed(upload: IncompleteUpload, fileName: string, window: Window): Promise<boolean> {
+    const resumeItem = localize('AWS.s3.uploadFile.resume', 'Resume Upload')
+    const choice = await window.showErrorMessage(
+        localize(
+            'AWS.s3.uploadFile.error.resumable',
+            'Failed to upload file {0} after {1} of {2} parts. Resume the upload to continue where it stopped.',
+            fileName,
+            upload.parts.length,
+            getPartCount(upload)
+        ),
+        resumeItem
+    )
+
+    return choice === resumeItem
 }
 
 async function promptForFileLocation(window: Window): Promise<vscode.Uri | undefined> {
@@ -173,6 +313,9 @@ function statFile(file: vscode.Uri) {
     return statSync(file.fsPath).size
 }
 
+/**
+ * @returns true if the file was uploaded, false if the user cancelled a multipart upload.
+ */
 async function uploadWithProgress({
     bucketName,
     key,
@@ -180,6 +323,9 @@ async function uploadWithProgress({
     fileSizeBytes,
     s3Client,
     window,
+    metadata,
+    uploads,
+    resume,
 }: {
     bucketName: string
     key: string
@@ -187,18 +333,56 @@ async function uploadWithProgress({
     fileSizeBytes: number
     s3Client: S3Client
     window: Window
-}): Promise<void> {
+    metadata?: UploadMetadata
+    /** Uploads the file in parts, recording them in this store. */
+    uploads?: MultipartUploadStore
+    resume?: IncompleteUpload
+}): Promise<boolean> {
     return window.withProgress(
         {
             location: vscode.ProgressLocation.Notification,
             title: localize('AWS.s3.uploadFile.progressTitle', 'Uploading {0}...', path.basename(fileLocation.fsPath)),
+            cancellable: uploads !== undefined,
         },
-        progress => {
-            return s3Client.uploadFile({
-                bucketName: bucketName,
-                key: key,
+        async (progress, token) => {
+            if (!uploads) {
+                await s3Client.uploadFile({
+                    bucketName: bucketName,
+                    key: key,
+                    fileLocation,
+                    progressListener: progressReporter({ progress, totalBytes: fileSizeBytes }),
+                    contentType: metadata?.contentType,
+                    cacheControl: metadata?.cacheControl,
+                    metadata: metadata?.metadata,
+                })
+                return true
+            }
+
+            const reportBytes = progressReporter({ progress, totalBytes: fileSizeBytes })
+            let completedParts: number | undefined
+            return uploadMultipart({
+                s3Client,
+                store: uploads,
+                bucketName,
+                key,
                 fileLocation,
-                progressListener: progressReporter({ progress, totalBytes: fileSizeBytes }),
+                metadata,
+                resume,
+                onProgress: multipartProgress => {
+                    reportBytes(multipartProgress.loadedBytes)
+                    if (multipartProgress.completedParts !== completedParts) {
+                        completedParts = multipartProgress.completedParts
+                        progress.report({
+                            message: localize(
+                                'AWS.s3.uploadFile.partProgress',
+                                '{0}/{1} parts',
+                                completedParts,
+                                multipartProgress.totalParts
+                            ),
+                        })
+                    }
+                },
+                cancellationToken: token,
             })
         }
     )
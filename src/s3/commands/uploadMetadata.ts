@@ -0,0 +1,171 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as mime from 'mime-types'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { DEFAULT_CONTENT_TYPE } from '../../shared/clients/s3Client'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+
+/** The prefix of user-defined metadata headers, which S3 adds to the metadata keys. */
+const METADATA_HEADER_PREFIX = 'x-amz-meta-'
+
+export interface UploadMetadata {
+    readonly contentType: string
+    readonly cacheControl?: string
+    readonly metadata?: { [key: string]: string }
+}
+
+/**
+ * Overrides entered during this session. Content types are remembered per file extension, so that uploading
+ * e.g. several `.html` files only requires setting it once.
+ */
+const contentTypeOverrides = new Map<string, string>()
+let cacheControlOverride: string | undefined
+let metadataOverride: string | undefined
+
+export function clearUploadMetadataOverrides(): void {
+    contentTypeOverrides.clear()
+    cacheControlOverride = undefined
+    metadataOverride = undefined
+}
+
+/**
+ * Gets the content type to upload a file with: the override for its extension, if one was set during this
+ * session, or else the type detected from the extension.
+ */
+export function getDefaultContentType(file: vscode.Uri): string {
+    const extension = path.extname(file.fsPath).toLowerCase()
+
+    return contentTypeOverrides.get(extension) ?? (mime.lookup(path.basename(file.fsPath)) || DEFAULT_CONTENT_TYPE)
+}
+
+/**
+ * Parses user-defined metadata entered as comma-separated `key=value` pairs.
+ * Keys may include the `x-amz-meta-` prefix, which is removed.
+ *
+ * @throws Error if a pair has no key
+ */
+export function parseMetadata(text: string): { [key: string]: string } {
+    const metadata: { [key: string]: string } = {}
+    for (const pair of text.split(',')) {
+        if (!pair.trim()) {
+            continue
+        }
+
+        const separator = pair.indexOf('=')
+        let key = (separator >= 0 ? pair.substring(0, separator) : pair).trim().toLowerCase()
+        if (key.startsWith(METADATA_HEADER_PREFIX)) {
+            key = key.substring(METADATA_HEADER_PREFIX.length)
+        }
+        if (!key) {
+            throw new Error(localize('AWS.s3.uploadMetadata.invalidMetadata', 'Metadata must be key=value pairs'))
+        }
+        metadata[key] = separator >= 0 ? pair.substring(separator + 1).trim() : ''
+    }
+
+    return metadata
+}
+
+function validateMetadata(text: string): string | undefined {
+    try {
+        parseMetadata(text)
+
+        return undefined
+    } catch (err) {
+        return (err as Error).message
+    }
+}
+
+/**
+ * Builds the metadata to upload a file with from the defaults and the overrides of this session.
+ */
+export function getUploadMetadata(file: vscode.Uri): UploadMetadata {
+    return {
+        contentType: getDefaultContentType(file),
+        cacheControl: cacheControlOverride || undefined,
+        metadata: metadataOverride ? parseMetadata(metadataOverride) : undefined,
+    }
+}
+
+/**
+ * Optional step of the upload wizard to set the content type, cache control, and user-defined metadata of the
+ * uploaded object. The values entered are remembered for the rest of the session.
+ *
+ * @returns the metadata to upload with, or undefined if the user cancelled
+ */
+export async function promptForUploadMetadata(
+    file: vscode.Uri,
+    window = Window.vscode(),
+    promptUserFunction = promptUser
+): Promise<UploadMetadata | undefined> {
+    const defaults = getUploadMetadata(file)
+    const upload: vscode.QuickPickItem = {
+        label: localize('AWS.s3.uploadMetadata.upload', 'Upload'),
+        description: localize('AWS.s3.uploadMetadata.contentType', 'Content-Type: {0}', defaults.contentType),
+        detail: defaults.cacheControl
+            ? localize('AWS.s3.uploadMetadata.cacheControl', 'Cache-Control: {0}', defaults.cacheControl)
+            : undefined,
+    }
+    const setMetadata: vscode.QuickPickItem = {
+        label: localize('AWS.s3.uploadMetadata.set', 'Set content type and metadata...'),
+    }
+
+    const picker = createQuickPick({
+        options: {
+            ignoreFocusOut: true,
+            title: localize('AWS.s3.uploadMetadata.title', 'Upload {0}', path.basename(file.fsPath)),
+        },
+        items: [upload, setMetadata],
+    })
+    const response = verifySinglePickerOutput(await promptUserFunction({ picker }))
+    if (!response) {
+        return undefined
+    }
+    if (response.label === upload.label) {
+        return defaults
+    }
+
+    const contentType = await window.showInputBox({
+        prompt: localize('AWS.s3.uploadMetadata.contentTypePrompt', 'Enter the Content-Type'),
+        value: defaults.contentType,
+        ignoreFocusOut: true,
+    })
+    if (contentType === undefined) {
+        return undefined
+    }
+    const cacheControl = await window.showInputBox({
+        prompt: localize('AWS.s3.uploadMetadata.cacheControlPrompt', 'Enter the Cache-Control (optional)'),
+        placeHolder: 'max-age=3600',
+        value: cacheControlOverride,
+        ignoreFocusOut: true,
+    })
+    if (cacheControl === undefined) {
+        return undefined
+    }
+    const metadata = await window.showInputBox({
+        prompt: localize(
+            'AWS.s3.uploadMetadata.metadataPrompt',
+            'Enter user-defined metadata as comma-separated key=value pairs (optional)'
+        ),
+        placeHolder: 'x-amz-meta-author=me, version=2',
+        value: metadataOverride,
+        validateInput: validateMetadata,
+        ignoreFocusOut: true,
+    })
+    if (metadata === undefined) {
+        return undefined
+    }
+
+    if (contentType.trim()) {
+        contentTypeOverrides.set(path.extname(file.fsPath).toLowerCase(), contentType.trim())
+    }
+    cacheControlOverride = cacheControl.trim()
+    metadataOverride = metadata.trim()
+
+    return getUploadMetadata(file)
+}
@@ -26,6 +26,7 @@ import { S3FileNode } from './s3FileNode'
 import { S3FolderNode } from './s3FolderNode'
 import { inspect } from 'util'
 import { getLogger } from '../../shared/logger'
+import { formatObjectFilter, ObjectFilter } from '../util'
 import { S3Node } from './s3Nodes'
 
 /**
@@ -33,6 +34,7 @@ import { S3Node } from './s3Nodes'
  */
 export class S3BucketNode extends AWSTreeNodeBase implements AWSResourceNode, LoadMoreNode {
     private readonly childLoader: ChildNodeLoader
+    private filter: ObjectFilter | undefined
 
     public constructor(
         public readonly bucket: Bucket,
@@ -71,16 +73,41 @@ export class S3BucketNode extends AWSTreeNodeBase implements AWSResourceNode, Lo
         this.childLoader.clearChildren()
     }
 
+    public get objectFilter(): ObjectFilter | undefined {
+        return this.filter
+    }
+
+    /**
+     * Sets (or clears, if undefined) the filter applied to the objects listed under this node.
+     *
+     * The filter is kept until cleared, including when loading more pages.
+     */
+    public setObjectFilter(filter: ObjectFilter | undefined): void {
+        this.filter = filter
+        this.label = filter
+            ? localize(
+                  'AWS.explorerNode.s3.filtered',
+                  '{0} (filter: {1})',
+                  this.bucket.name,
+                  formatObjectFilter(filter)
+              )
+            : this.bucket.name
+        this.clearChildren()
+    }
+
     private async loadPage(continuationToken: string | undefined): Promise<ChildNodePage> {
         getLogger().debug(`Loading page for %O using continuationToken %s`, this, continuationToken)
         const response = await this.s3.listFiles({
             bucketName: this.bucket.name,
+            ...(this.filter ? { folderPath: this.filter.prefix } : {}),
             continuationToken,
             maxResults: this.getMaxItemsPerPage(),
         })
 
         const newFolders = response.folders.map(folder => new S3FolderNode(this.bucket, folder, this.s3))
-        const newFiles = response.files.map(file => new S3FileNode(this.bucket, file, this, this.s3))
+        const newFiles = response.files
+            .filter(file => !this.filter?.suffix || file.key.endsWith(this.filter.suffix))
+            .map(file => new S3FileNode(this.bucket, file, this, this.s3))
 
         getLogger().debug(`Loaded folders: %O and files: %O`, newFolders, newFiles)
         return {
@@ -5,7 +5,15 @@
 
 import * as moment from 'moment'
 import * as bytes from 'bytes'
-import { Bucket, DownloadFileRequest, File, S3Client } from '../../shared/clients/s3Client'
+import {
+    Bucket,
+    DownloadFileRequest,
+    File,
+    GetObjectResponse,
+    HeadObjectResponse,
+    ObjectVersion,
+    S3Client,
+} from '../../shared/clients/s3Client'
 import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
 import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
 import { localize } from '../../shared/utilities/vsCodeUtils'
@@ -71,6 +79,41 @@ export class S3FileNode extends AWSTreeNodeBase implements AWSResourceNode {
         await this.s3.deleteObject({ bucketName: this.bucket.name, key: this.file.key })
     }
 
+    /**
+     * See {@link S3Client.getSignedUrl}.
+     */
+    public async getSignedUrl(expiresInSeconds: number): Promise<string> {
+        return this.s3.getSignedUrl({ bucketName: this.bucket.name, key: this.file.key, expiresInSeconds })
+    }
+
+    /**
+     * See {@link S3Client.getServerSideEncryption}.
+     */
+    public async getServerSideEncryption(): Promise<string | undefined> {
+        return this.s3.getServerSideEncryption({ bucketName: this.bucket.name, key: this.file.key })
+    }
+
+    /**
+     * See {@link S3Client.listKeyVersions}.
+     */
+    public async listVersions(): Promise<ObjectVersion[]> {
+        return this.s3.listKeyVersions({ bucketName: this.bucket.name, key: this.file.key })
+    }
+
+    /**
+     * See {@link S3Client.headObject}. Heads an object of the node's bucket, by default the node's.
+     */
+    public async headObject(key: string = this.file.key, versionId?: string): Promise<HeadObjectResponse> {
+        return this.s3.headObject({ bucketName: this.bucket.name, key, versionId })
+    }
+
+    /**
+     * See {@link S3Client.getObject}. Gets an object of the node's bucket, by default the node's.
+     */
+    public async getObject(key: string = this.file.key, versionId?: string): Promise<GetObjectResponse> {
+        return this.s3.getObject({ bucketName: this.bucket.name, key, versionId })
+    }
+
     public get arn(): string {
         return this.file.arn
     }
@@ -88,6 +131,6 @@ export class S3FileNode extends AWSTreeNodeBase implements AWSResourceNode {
     }
 }
 
-function formatBytes(numBytes: number): string {
+export function formatBytes(numBytes: number): string {
     return bytes(numBytes, { unitSeparator: ' ', decimalPlaces: 0 })
 }
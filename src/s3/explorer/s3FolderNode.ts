@@ -26,12 +26,14 @@ import { S3FileNode } from './s3FileNode'
 import { inspect } from 'util'
 import { Workspace } from '../../shared/vscode/workspace'
 import { getLogger } from '../../shared/logger'
+import { formatObjectFilter, ObjectFilter } from '../util'
 
 /**
  * Represents a folder in an S3 bucket that may contain subfolders and/or objects.
  */
 export class S3FolderNode extends AWSTreeNodeBase implements AWSResourceNode, LoadMoreNode {
     private readonly childLoader: ChildNodeLoader
+    private filter: ObjectFilter | undefined
 
     public constructor(
         public readonly bucket: Bucket,
@@ -67,17 +69,41 @@ export class S3FolderNode extends AWSTreeNodeBase implements AWSResourceNode, Lo
         this.childLoader.clearChildren()
     }
 
+    public get objectFilter(): ObjectFilter | undefined {
+        return this.filter
+    }
+
+    /**
+     * Sets (or clears, if undefined) the filter applied to the objects listed under this node.
+     *
+     * The filter is kept until cleared, including when loading more pages.
+     */
+    public setObjectFilter(filter: ObjectFilter | undefined): void {
+        this.filter = filter
+        this.label = filter
+            ? localize(
+                  'AWS.explorerNode.s3.filtered',
+                  '{0} (filter: {1})',
+                  this.folder.name,
+                  formatObjectFilter(filter)
+              )
+            : this.folder.name
+        this.clearChildren()
+    }
+
     private async loadPage(continuationToken: string | undefined): Promise<ChildNodePage> {
         getLogger().debug(`Loading page for %O using continuationToken %s`, this, continuationToken)
         const response = await this.s3.listFiles({
             bucketName: this.bucket.name,
-            folderPath: this.folder.path,
+            folderPath: this.folder.path + (this.filter?.prefix ?? ''),
             continuationToken,
             maxResults: this.getMaxItemsPerPage(),
         })
 
         const newFolders = response.folders.map(folder => new S3FolderNode(this.bucket, folder, this.s3))
-        const newFiles = response.files.map(file => new S3FileNode(this.bucket, file, this, this.s3))
+        const newFiles = response.files
+            .filter(file => !this.filter?.suffix || file.key.endsWith(this.filter.suffix))
+            .map(file => new S3FileNode(this.bucket, file, this, this.s3))
 
         getLogger().debug(`Loaded folders: %O and files: %O`, newFolders, newFiles)
         return {
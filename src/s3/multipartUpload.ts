@@ -0,0 +1,288 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as fs from 'fs-extra'
+import * as vscode from 'vscode'
+import { CompletedPart, S3Client } from '../shared/clients/s3Client'
+import { getLogger } from '../shared/logger'
+import { UploadMetadata } from './commands/uploadMetadata'
+
+const MIB = 1024 * 1024
+
+/** Files of at least this size are uploaded in parts, so that an interrupted upload can be resumed. */
+export const MULTIPART_THRESHOLD_BYTES = 100 * MIB
+
+const MIN_PART_SIZE_BYTES = 8 * MIB
+/** The most parts S3 allows in an upload. */
+const MAX_PARTS = 10000
+const DEFAULT_CONCURRENCY = 4
+
+/**
+ * A multipart upload that was started but not completed, e.g. because the connection dropped.
+ */
+export interface IncompleteUpload {
+    readonly bucketName: string
+    readonly key: string
+    readonly uploadId: string
+    readonly filePath: string
+    /** The size and modification time of the file when the upload started, to tell whether it changed since. */
+    readonly fileSizeBytes: number
+    readonly fileModifiedMillis: number
+    readonly partSizeBytes: number
+    /** The parts that were uploaded, with the ETags to complete the upload with. */
+    readonly parts: CompletedPart[]
+    /** The metadata the upload was started with, to start it over with if it expired. */
+    readonly metadata?: UploadMetadata
+}
+
+interface IncompleteUploads {
+    [bucketAndKey: string]: IncompleteUpload | undefined
+}
+
+/**
+ * Remembers multipart uploads until they are completed or aborted, so that they can be resumed.
+ */
+export class MultipartUploadStore {
+    private static readonly stateKey = 's3IncompleteUploads'
+
+    public constructor(private readonly memento: vscode.Memento) {}
+
+    public get(bucketName: string, key: string): IncompleteUpload | undefined {
+        return this.getAll()[`${bucketName}/${key}`]
+    }
+
+    public async set(upload: IncompleteUpload): Promise<void> {
+        await this.memento.update(MultipartUploadStore.stateKey, {
+            ...this.getAll(),
+            [`${upload.bucketName}/${upload.key}`]: upload,
+        })
+    }
+
+    public async delete(bucketName: string, key: string): Promise<void> {
+        const all = this.getAll()
+        delete all[`${bucketName}/${key}`]
+
+        await this.memento.update(MultipartUploadStore.stateKey, all)
+    }
+
+    private getAll(): IncompleteUploads {
+        return { ...this.memento.get<IncompleteUploads>(MultipartUploadStore.stateKey, {}) }
+    }
+}
+
+/**
+ * Parts are as small as possible, while keeping to the number of parts S3 allows.
+ */
+export function getPartSizeBytes(fileSizeBytes: number): number {
+    return Math.max(MIN_PART_SIZE_BYTES, Math.ceil(fileSizeBytes / MAX_PARTS / MIB) * MIB)
+}
+
+export function getPartCount(upload: Pick<IncompleteUpload, 'fileSizeBytes' | 'partSizeBytes'>): number {
+    return Math.max(1, Math.ceil(upload.fileSizeBytes / upload.partSizeBytes))
+}
+
+/**
+ * Finds the incomplete upload of a file to a key, if the file hasn't changed since it was started.
+ */
+export async function findIncompleteUpload(
+    store: MultipartUploadStore,
+    bucketName: string,
+    key: string,
+    fileLocation: vscode.Uri
+): Promise<IncompleteUpload | undefined> {
+    const upload = store.get(bucketName, key)
+    if (!upload || upload.filePath !== fileLocation.fsPath) {
+        return undefined
+    }
+    const stats = await fs.stat(fileLocation.fsPath)
+
+    return stats.size === upload.fileSizeBytes && stats.mtimeMs === upload.fileModifiedMillis ? upload : undefined
+}
+
+export interface MultipartUploadProgress {
+    /** Bytes of the file uploaded so far, including the parts of the upload being resumed. */
+    readonly loadedBytes: number
+    readonly completedParts: number
+    readonly totalParts: number
+}
+
+/**
+ * Uploads a file in parts, several at a time. The parts are recorded in the store as they are uploaded, so that
+ * the upload can be resumed, until it is completed.
+ *
+ * When cancellation is requested, the upload is aborted, so that its parts are no longer charged for.
+ *
+ * @param metadata the metadata to start a new upload with. Defaults to that of the upload being resumed.
+ * @param resume the incomplete upload to resume. If S3 no longer has it, e.g. because it expired, a new upload is
+ * started instead.
+ * @returns true if the upload was completed, false if it was cancelled.
+ * @throws Error if there is an error calling S3. The upload can be resumed from the store.
+ */
+export async function uploadMultipart({
+    s3Client,
+    store,
+    bucketName,
+    key,
+    fileLocation,
+    metadata,
+    resume,
+    onProgress,
+    cancellationToken,
+    concurrency = DEFAULT_CONCURRENCY,
+}: {
+    s3Client: S3Client
+    store: MultipartUploadStore
+    bucketName: string
+    key: string
+    fileLocation: vscode.Uri
+    metadata?: UploadMetadata
+    resume?: IncompleteUpload
+    onProgress?(progress: MultipartUploadProgress): void
+    cancellationToken?: vscode.CancellationToken
+    concurrency?: number
+}): Promise<boolean> {
+    let upload = resume && (await uploadExists(s3Client, resume)) ? resume : undefined
+    if (upload) {
+        getLogger().info('Resuming upload %s of %s with %d parts uploaded', upload.uploadId, key, upload.parts.length)
+    } else {
+        upload = await startUpload(s3Client, bucketName, key, fileLocation, metadata ?? resume?.metadata)
+        await store.set(upload)
+    }
+
+    const record = upload
+    const { uploadId, partSizeBytes, fileSizeBytes } = record
+    const totalParts = getPartCount(record)
+    const parts = [...record.parts]
+    const pending = Array.from({ length: totalParts }, (_, i) => i + 1).filter(
+        partNumber => !parts.some(part => part.partNumber === partNumber)
+    )
+
+    // parts that are being uploaded report their progress separately, and are summed into one progress
+    const partBytes = (partNumber: number) => Math.min(partSizeBytes, fileSizeBytes - (partNumber - 1) * partSizeBytes)
+    let completedBytes = parts.reduce((sum, part) => sum + partBytes(part.partNumber), 0)
+    const loadingBytes = new Map<number, number>()
+    let reportedBytes = 0
+    const report = () => {
+        let loadedBytes = completedBytes
+        loadingBytes.forEach(bytes => (loadedBytes += bytes))
+        // progress can't go backwards, e.g. when the SDK retries a part
+        reportedBytes = Math.min(fileSizeBytes, Math.max(reportedBytes, loadedBytes))
+        onProgress?.({ loadedBytes: reportedBytes, completedParts: parts.length, totalParts })
+    }
+    report()
+
+    const file = await fs.open(fileLocation.fsPath, 'r')
+    let error: unknown
+    try {
+        let next = 0
+        const worker = async () => {
+            while (next < pending.length && error === undefined && !cancellationToken?.isCancellationRequested) {
+                const partNumber = pending[next++]
+                try {
+                    const body = Buffer.alloc(partBytes(partNumber))
+                    await fs.read(file, body, 0, body.length, (partNumber - 1) * partSizeBytes)
+                    const part = await s3Client.uploadPart({
+                        bucketName,
+                        key,
+                        uploadId,
+                        partNumber,
+                        body,
+                        progressListener: loadedBytes => {
+                            loadingBytes.set(partNumber, loadedBytes)
+                            report()
+                        },
+                        cancellationToken,
+                    })
+                    parts.push(part)
+                    completedBytes += body.length
+                    await store.set({ ...record, parts: [...parts] })
+                } catch (e) {
+                    error = error ?? e
+                } finally {
+                    loadingBytes.delete(partNumber)
+                }
+                report()
+            }
+        }
+        await Promise.all(Array.from({ length: Math.min(concurrency, pending.length) }, worker))
+    } finally {
+        await fs.close(file)
+    }
+
+    if (cancellationToken?.isCancellationRequested) {
+        getLogger().info('Aborting cancelled upload %s of %s', uploadId, key)
+        await store.delete(bucketName, key)
+        await s3Client.abortMultipartUpload({ bucketName, key, uploadId })
+        return false
+    }
+    if (error !== undefined) {
+        throw error
+    }
+
+    parts.sort((a, b) => a.partNumber - b.partNumber)
+    await s3Client.completeMultipartUpload({ bucketName, key, uploadId, parts })
+    await store.delete(bucketName, key)
+
+    return true
+}
+
+/**
+ * Aborts an incomplete upload that won't be resumed, and forgets it.
+ * Failing to abort it is only logged, since S3 can also clean up incomplete uploads with a lifecycle rule.
+ */
+export async function abortIncompleteUpload(
+    s3Client: S3Client,
+    store: MultipartUploadStore,
+    upload: IncompleteUpload
+): Promise<void> {
+    await store.delete(upload.bucketName, upload.key)
+    try {
+        await s3Client.abortMultipartUpload(upload)
+    } catch (e) {
+        getLogger().warn('Failed to abort incomplete upload %s of %s: %O', upload.uploadId, upload.key, e)
+    }
+}
+
+async function startUpload(
+    s3Client: S3Client,
+    bucketName: string,
+    key: string,
+    fileLocation: vscode.Uri,
+    metadata: UploadMetadata | undefined
+): Promise<IncompleteUpload> {
+    const stats = await fs.stat(fileLocation.fsPath)
+    const uploadId = await s3Client.createMultipartUpload({
+        bucketName,
+        key,
+        contentType: metadata?.contentType,
+        cacheControl: metadata?.cacheControl,
+        metadata: metadata?.metadata,
+    })
+
+    return {
+        bucketName,
+        key,
+        uploadId,
+        filePath: fileLocation.fsPath,
+        fileSizeBytes: stats.size,
+        fileModifiedMillis: stats.mtimeMs,
+        partSizeBytes: getPartSizeBytes(stats.size),
+        parts: [],
+        metadata,
+    }
+}
+
+async function uploadExists(s3Client: S3Client, upload: IncompleteUpload): Promise<boolean> {
+    try {
+        await s3Client.listParts(upload)
+        return true
+    } catch (e) {
+        if ((e as { code?: string }).code === 'NoSuchUpload') {
+            getLogger().info('Upload %s of %s no longer exists, starting over', upload.uploadId, upload.key)
+            return false
+        }
+        throw e
+    }
+}
@@ -19,6 +19,43 @@ export function readablePath({ bucket, path }: { bucket: { name: string }; path:
     return path ? `s3://${bucket.name}/${path}` : `s3://${bucket.name}`
 }
 
+/**
+ * Narrows the objects listed under a bucket or folder node.
+ *
+ * The prefix is relative to the node and is sent to S3, so only matching keys are listed.
+ * The suffix is not supported by S3 and is applied to each page of results.
+ */
+export interface ObjectFilter {
+    readonly prefix: string
+    readonly suffix?: string
+}
+
+/**
+ * Parses a filter in the form `prefix*suffix` (e.g. `logs/2024*.gz`).
+ *
+ * @returns undefined if the text is empty, which clears the filter.
+ */
+export function parseObjectFilter(text: string): ObjectFilter | undefined {
+    const trimmed = text.trim()
+    if (!trimmed) {
+        return undefined
+    }
+    const wildcard = trimmed.indexOf('*')
+    if (wildcard < 0) {
+        return { prefix: trimmed }
+    }
+    const suffix = trimmed.substring(wildcard + 1)
+
+    return { prefix: trimmed.substring(0, wildcard), suffix: suffix || undefined }
+}
+
+/**
+ * Inverse of {@link parseObjectFilter}.
+ */
+export function formatObjectFilter(filter: ObjectFilter): string {
+    return filter.suffix ? `${filter.prefix}*${filter.suffix}` : filter.prefix
+}
+
 /**
  * Validates an S3 bucket name.
  *
@@ -70,3 +107,37 @@ export function validateBucketName(name: string): string | undefined {
 
     return undefined
 }
+
+const WINDOWS_ILLEGAL_CHARACTERS = /[<>:"|?*\\\x00-\x1f]/g
+const WINDOWS_RESERVED_NAMES = /^(con|prn|aux|nul|com[0-9]|lpt[0-9])(\..*)?$/i
+
+/**
+ * Converts an object key (relative to the folder being downloaded) into a relative local path.
+ *
+ * Empty, `.` and `..` segments are always replaced so that a key can't point outside of the
+ * download directory. On Windows, characters and names that are not allowed in file names are
+ * replaced with `_` as well.
+ *
+ * @returns the path segments, joined with '/'.
+ */
+export function toLocalRelativePath(relativeKey: string, platform: NodeJS.Platform = process.platform): string {
+    return relativeKey
+        .split('/')
+        .map(segment => {
+            if (segment === '' || segment === '.' || segment === '..') {
+                return segment.replace(/\./g, '_') || '_'
+            }
+            if (platform !== 'win32') {
+                return segment
+            }
+            let sanitized = segment
+                .replace(WINDOWS_ILLEGAL_CHARACTERS, '_')
+                .replace(/[. ]+$/, match => '_'.repeat(match.length))
+            if (WINDOWS_RESERVED_NAMES.test(sanitized)) {
+                sanitized = `_${sanitized}`
+            }
+
+            return sanitized
+        })
+        .join('/')
+}
@@ -0,0 +1,23 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { editSecret } from './commands/editSecret'
+import { viewSecret } from './commands/viewSecret'
+import { SecretNode } from './explorer/secretNode'
+
+/**
+ * Activates Secrets Manager components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.secretsManager.viewSecret', async (node: SecretNode) => {
+            await viewSecret(node)
+        }),
+        vscode.commands.registerCommand('aws.secretsManager.editSecret', async (node: SecretNode) => {
+            await editSecret(node)
+        })
+    )
+}
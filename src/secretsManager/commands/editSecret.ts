@@ -0,0 +1,211 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import { recordSecretsmanagerEditSecret, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showConfirmationMessage, showErrorWithLogs } from '../../shared/utilities/messages'
+import { addCodiconToString } from '../../shared/utilities/textUtilities'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Window } from '../../shared/vscode/window'
+import { SecretNode } from '../explorer/secretNode'
+import {
+    formatFieldValue,
+    isResourceNotFound,
+    parseFieldValue,
+    SecretFieldValue,
+    SecretNotFoundError,
+    SecretValue,
+    serializeSecretValue,
+} from '../secretValue'
+import { loadSecretValue, makeSecretValueItems, SecretValueItem } from './viewSecret'
+
+/**
+ * Edits the value of a secret, and writes it as a new version after the user confirms.
+ *
+ * Secrets that are flat JSON objects are edited one key at a time, others as a single plaintext value.
+ * Values are entered in password input boxes, and are never logged.
+ */
+export async function editSecret(
+    node: SecretNode,
+    window = Window.vscode(),
+    commands = Commands.vscode(),
+    promptUserFunction = promptUser
+): Promise<void> {
+    getLogger().debug('EditSecret called for %s', node.name)
+    let result: Result = 'Succeeded'
+
+    try {
+        const value = await loadSecretValue(node, window, commands)
+        if (!value) {
+            result = 'Failed'
+            return
+        }
+
+        const edited =
+            value.kind === 'json'
+                ? await editFields(node.name, value.fields, window, promptUserFunction)
+                : await editText(node.name, value.text, window)
+        if (!edited) {
+            result = 'Cancelled'
+            getLogger().info('EditSecret cancelled')
+            return
+        }
+
+        const secretString = serializeSecretValue(edited)
+        if (secretString === serializeSecretValue(value)) {
+            result = 'Cancelled'
+            window.showInformationMessage(
+                localize('AWS.secretsManager.edit.unchanged', 'Secret {0} was not changed', node.name)
+            )
+            return
+        }
+
+        const isConfirmed = await showConfirmationMessage(
+            {
+                prompt: localize(
+                    'AWS.secretsManager.edit.confirm',
+                    'Write a new version of secret {0}? Applications that get the current version of the secret will get the new value.',
+                    node.name
+                ),
+                confirm: localize('AWS.secretsManager.edit.confirmButton', 'Write new version'),
+                cancel: localize('AWS.generic.cancel', 'Cancel'),
+            },
+            window
+        )
+        if (!isConfirmed) {
+            result = 'Cancelled'
+            getLogger().info('EditSecret cancelled')
+            return
+        }
+
+        result = await writeSecretValue(node, secretString, window, commands)
+    } finally {
+        recordSecretsmanagerEditSecret({ result })
+    }
+}
+
+async function writeSecretValue(
+    node: SecretNode,
+    secretString: string,
+    window: Window,
+    commands: Commands
+): Promise<Result> {
+    try {
+        await node.putSecretValue(secretString)
+        getLogger().info('Wrote a new version of secret %s', node.name)
+        window.showInformationMessage(
+            localize('AWS.secretsManager.edit.success', 'Wrote a new version of secret {0}', node.name)
+        )
+
+        return 'Succeeded'
+    } catch (err) {
+        if (isResourceNotFound(err)) {
+            const error = new SecretNotFoundError(node.name)
+            getLogger().warn(error.message)
+            window.showWarningMessage(error.message)
+            await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+
+            return 'Failed'
+        }
+
+        getLogger().error('Failed to write a new version of secret %s: %s', node.name, (err as Error).message)
+        showErrorWithLogs(
+            localize(
+                'AWS.secretsManager.edit.failure',
+                'Failed to write a new version of secret {0}: {1}',
+                node.name,
+                (err as Error).message
+            ),
+            window
+        )
+
+        return 'Failed'
+    }
+}
+
+async function editText(secretName: string, text: string, window: Window): Promise<SecretValue | undefined> {
+    const edited = await window.showInputBox({
+        prompt: localize('AWS.secretsManager.edit.textPrompt', 'Enter the new value of secret {0}', secretName),
+        value: text,
+        password: true,
+        ignoreFocusOut: true,
+    })
+
+    return edited === undefined ? undefined : { kind: 'text', text: edited }
+}
+
+/**
+ * Lets the user pick keys to edit or add, until they choose to save or cancel.
+ */
+async function editFields(
+    secretName: string,
+    original: { [key: string]: SecretFieldValue },
+    window: Window,
+    promptUserFunction: typeof promptUser
+): Promise<SecretValue | undefined> {
+    const fields = { ...original }
+    const save: SecretValueItem = {
+        label: addCodiconToString('check', localize('AWS.secretsManager.edit.save', 'Save new version...')),
+        value: '',
+        alwaysShow: true,
+    }
+    const add: SecretValueItem = {
+        label: addCodiconToString('add', localize('AWS.secretsManager.edit.addKey', 'Add key...')),
+        value: '',
+        alwaysShow: true,
+    }
+
+    while (true) {
+        const picker = createQuickPick({
+            options: {
+                ignoreFocusOut: true,
+                title: localize('AWS.secretsManager.edit.title', 'Edit secret {0}', secretName),
+                placeHolder: localize('AWS.secretsManager.edit.placeholder', 'Choose a key to edit its value'),
+            },
+            items: [save, ...makeSecretValueItems({ kind: 'json', fields }, false), add],
+        })
+        const response = verifySinglePickerOutput(await promptUserFunction({ picker }))
+        if (!response) {
+            return undefined
+        }
+        if (response.label === save.label) {
+            return { kind: 'json', fields }
+        }
+
+        const key = response.label === add.label ? await promptForKey(fields, window) : response.key
+        if (key === undefined) {
+            continue
+        }
+
+        const text = await window.showInputBox({
+            prompt: localize('AWS.secretsManager.edit.valuePrompt', 'Enter the new value of {0}', key),
+            value: Object.keys(fields).includes(key) ? formatFieldValue(fields[key]) : '',
+            password: true,
+            ignoreFocusOut: true,
+        })
+        if (text !== undefined) {
+            fields[key] = parseFieldValue(text, original[key])
+        }
+    }
+}
+
+async function promptForKey(fields: { [key: string]: SecretFieldValue }, window: Window): Promise<string | undefined> {
+    return await window.showInputBox({
+        prompt: localize('AWS.secretsManager.edit.keyPrompt', 'Enter the new key'),
+        ignoreFocusOut: true,
+        validateInput: input => {
+            if (!input) {
+                return localize('AWS.secretsManager.edit.emptyKey', 'Key must not be empty')
+            }
+            if (Object.keys(fields).includes(input)) {
+                return localize('AWS.secretsManager.edit.duplicateKey', 'Key {0} already exists', input)
+            }
+
+            return undefined
+        },
+    })
+}
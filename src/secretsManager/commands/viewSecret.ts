@@ -0,0 +1,156 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordSecretsmanagerViewSecret, Result } from '../../shared/telemetry/telemetry'
+import { createQuickPick, promptUser, verifySinglePickerOutput } from '../../shared/ui/picker'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Commands } from '../../shared/vscode/commands'
+import { Env } from '../../shared/vscode/env'
+import { Window } from '../../shared/vscode/window'
+import { SecretNode } from '../explorer/secretNode'
+import {
+    formatFieldValue,
+    isResourceNotFound,
+    MASKED_VALUE,
+    parseSecretValue,
+    SecretNotFoundError,
+    SecretValue,
+} from '../secretValue'
+
+const COPY_DISPLAY_TIMEOUT_MS = 2000
+
+export interface SecretValueItem extends vscode.QuickPickItem {
+    key?: string
+    value: string
+}
+
+/**
+ * Gets and parses the current value of a secret.
+ *
+ * If the secret no longer exists, the user is told so and the explorer is refreshed.
+ *
+ * @returns the value, or undefined if it could not be loaded (the user has been notified)
+ */
+export async function loadSecretValue(
+    node: SecretNode,
+    window: Window,
+    commands: Commands
+): Promise<SecretValue | undefined> {
+    try {
+        return parseSecretValue(await node.getSecretValue())
+    } catch (err) {
+        if (isResourceNotFound(err)) {
+            const error = new SecretNotFoundError(node.name)
+            getLogger().warn(error.message)
+            window.showWarningMessage(error.message)
+            await commands.execute('aws.refreshAwsExplorerNode', node.parent)
+
+            return undefined
+        }
+
+        getLogger().error('Failed to get the value of secret %s: %s', node.name, (err as Error).message)
+        showErrorWithLogs(
+            localize(
+                'AWS.secretsManager.getValue.failure',
+                'Failed to get the value of secret {0}: {1}',
+                node.name,
+                (err as Error).message
+            ),
+            window
+        )
+
+        return undefined
+    }
+}
+
+/**
+ * Builds the items showing a secret value, with the values masked unless revealed.
+ */
+export function makeSecretValueItems(value: SecretValue, revealed: boolean): SecretValueItem[] {
+    const show = (text: string) => (revealed ? text : MASKED_VALUE)
+
+    if (value.kind === 'text') {
+        return [
+            {
+                label: localize('AWS.secretsManager.plaintext', 'Plaintext'),
+                description: show(value.text),
+                value: value.text,
+            },
+        ]
+    }
+
+    return Object.keys(value.fields).map(key => {
+        const text = formatFieldValue(value.fields[key])
+
+        return { label: key, description: show(text), key, value: text }
+    })
+}
+
+/**
+ * Shows the value of a secret, masked until the user toggles revealing it. Choosing a value copies it
+ * to the clipboard.
+ */
+export async function viewSecret(
+    node: SecretNode,
+    window = Window.vscode(),
+    env = Env.vscode(),
+    commands = Commands.vscode(),
+    promptUserFunction = promptUser
+): Promise<void> {
+    getLogger().debug('ViewSecret called for %s', node.name)
+    let result: Result = 'Succeeded'
+
+    try {
+        const value = await loadSecretValue(node, window, commands)
+        if (!value) {
+            result = 'Failed'
+            return
+        }
+
+        const reveal: vscode.QuickInputButton = {
+            iconPath: new vscode.ThemeIcon('eye'),
+            tooltip: localize('AWS.secretsManager.reveal', 'Show values'),
+        }
+        const hide: vscode.QuickInputButton = {
+            iconPath: new vscode.ThemeIcon('eye-closed'),
+            tooltip: localize('AWS.secretsManager.hide', 'Hide values'),
+        }
+        const picker = createQuickPick({
+            options: {
+                ignoreFocusOut: true,
+                title: localize('AWS.secretsManager.view.title', 'Secret {0}', node.name),
+                placeHolder: localize('AWS.secretsManager.view.placeholder', 'Choose a value to copy it'),
+            },
+            items: makeSecretValueItems(value, false),
+            buttons: [reveal],
+        })
+
+        const response = verifySinglePickerOutput(
+            await promptUserFunction({
+                picker,
+                onDidTriggerButton: button => {
+                    const revealed = button === reveal
+                    picker.items = makeSecretValueItems(value, revealed)
+                    picker.buttons = [revealed ? hide : reveal]
+                },
+            })
+        )
+        if (!response) {
+            result = 'Cancelled'
+            return
+        }
+
+        await env.clipboard.writeText(response.value)
+        window.setStatusBarMessage(
+            localize('AWS.explorerNode.copiedToClipboard', '$(clippy) Copied {0} to clipboard', response.label),
+            COPY_DISPLAY_TIMEOUT_MS
+        )
+    } finally {
+        recordSecretsmanagerViewSecret({ result })
+    }
+}
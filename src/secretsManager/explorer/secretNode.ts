@@ -0,0 +1,38 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { SecretsManager } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { SecretsManagerClient } from '../../shared/clients/secretsManagerClient'
+import { AWSResourceNode } from '../../shared/treeview/nodes/awsResourceNode'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { SecretsManagerNode } from './secretsManagerNode'
+
+export class SecretNode extends AWSTreeNodeBase implements AWSResourceNode {
+    public readonly name: string = this.secret.Name!
+    public readonly arn: string = this.secret.ARN!
+
+    public constructor(
+        public readonly parent: SecretsManagerNode,
+        private readonly secretsManager: SecretsManagerClient,
+        public readonly secret: SecretsManager.SecretListEntry
+    ) {
+        super(secret.Name!, vscode.TreeItemCollapsibleState.None)
+        this.tooltip = secret.Description ? `${secret.Name}\n${secret.Description}` : secret.Name
+        if (secret.DeletedDate) {
+            this.description = localize('AWS.explorerNode.secretsManager.deleted', '(scheduled for deletion)')
+        }
+        this.contextValue = 'awsSecretNode'
+    }
+
+    public async getSecretValue(): Promise<SecretsManager.GetSecretValueResponse> {
+        return await this.secretsManager.getSecretValue(this.arn)
+    }
+
+    public async putSecretValue(secretString: string): Promise<void> {
+        await this.secretsManager.putSecretValue(this.arn, secretString)
+    }
+}
@@ -0,0 +1,47 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { SecretsManagerClient } from '../../shared/clients/secretsManagerClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { SecretNode } from './secretNode'
+
+/**
+ * An AWS Explorer node representing Secrets Manager.
+ *
+ * Contains secrets for a specific region as child nodes.
+ */
+export class SecretsManagerNode extends AWSTreeNodeBase {
+    public constructor(private readonly secretsManager: SecretsManagerClient) {
+        super('Secrets Manager', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsSecretsManagerNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const secrets = await toArrayAsync(this.secretsManager.listSecrets())
+
+                return secrets
+                    .filter(secret => secret.Name && secret.ARN)
+                    .map(secret => new SecretNode(this, this.secretsManager, secret))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.secretsManager.noSecrets', '[No secrets found]')),
+            sort: (item1: SecretNode, item2: SecretNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'SecretsManagerNode'
+    }
+}
@@ -0,0 +1,97 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { SecretsManager } from 'aws-sdk'
+import { localize } from '../shared/utilities/vsCodeUtils'
+
+export const MASKED_VALUE = '••••••••'
+
+export type SecretFieldValue = string | number | boolean | null
+
+/**
+ * The value of a secret: key/value pairs if the secret string is a flat JSON object (as created by the
+ * Secrets Manager console), or else the raw text.
+ */
+export type SecretValue = { kind: 'json'; fields: { [key: string]: SecretFieldValue } } | { kind: 'text'; text: string }
+
+/**
+ * Thrown when a secret no longer exists, e.g. it was deleted after the explorer was loaded.
+ */
+export class SecretNotFoundError extends Error {
+    public constructor(public readonly secretName: string) {
+        super(localize('AWS.secretsManager.notFound', 'Secret {0} no longer exists', secretName))
+    }
+}
+
+export function isResourceNotFound(err: unknown): boolean {
+    return (err as { code?: string }).code === 'ResourceNotFoundException'
+}
+
+/**
+ * Parses the secret string of a secret.
+ *
+ * @throws Error if the secret only has a binary value, which is not supported
+ */
+export function parseSecretValue(response: SecretsManager.GetSecretValueResponse): SecretValue {
+    if (response.SecretString === undefined) {
+        throw new Error(
+            localize(
+                'AWS.secretsManager.binaryNotSupported',
+                'Secret {0} has a binary value, which cannot be viewed or edited',
+                response.Name
+            )
+        )
+    }
+
+    const text = response.SecretString
+    try {
+        const parsed = JSON.parse(text)
+        if (isFlatObject(parsed)) {
+            return { kind: 'json', fields: parsed }
+        }
+    } catch (err) {
+        // Not JSON, show as plaintext
+    }
+
+    return { kind: 'text', text }
+}
+
+function isFlatObject(value: unknown): value is { [key: string]: SecretFieldValue } {
+    return (
+        typeof value === 'object' &&
+        value !== null &&
+        !Array.isArray(value) &&
+        Object.values(value as object).every(field => field === null || typeof field !== 'object')
+    )
+}
+
+export function serializeSecretValue(value: SecretValue): string {
+    return value.kind === 'json' ? JSON.stringify(value.fields) : value.text
+}
+
+export function formatFieldValue(value: SecretFieldValue): string {
+    return typeof value === 'string' ? value : JSON.stringify(value)
+}
+
+/**
+ * Converts the text entered for a field back to a field value, keeping the type of non-string values
+ * (numbers, booleans, null) if the text is still a value of that type.
+ */
+export function parseFieldValue(text: string, previous: SecretFieldValue | undefined): SecretFieldValue {
+    if (previous === undefined || typeof previous === 'string') {
+        return text
+    }
+
+    try {
+        const parsed = JSON.parse(text)
+        if (parsed === null || typeof parsed === typeof previous) {
+            return parsed
+        }
+    } catch (err) {
+        // Not a JSON value, keep as text
+    }
+
+    return text
+}
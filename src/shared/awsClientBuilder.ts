@@ -4,9 +4,70 @@
  */
 
 import { ServiceConfigurationOptions } from 'aws-sdk/lib/service'
+import { URL } from 'url'
 import { env, version } from 'vscode'
 import { AwsContext } from './awsContext'
+import { extensionSettingsPrefix } from './constants'
 import { pluginVersion } from './extensionUtilities'
+import { DefaultSettingsConfiguration, SettingsConfiguration } from './settingsConfiguration'
+import { localize } from './utilities/vsCodeUtils'
+
+/**
+ * Gets the options that point clients of a service to its endpoint in the `aws.endpoints` setting, e.g. to use a
+ * local emulator like LocalStack. If `aws.endpointsProfile` is set, endpoints only apply to that profile.
+ *
+ * These settings are only read from the user settings: a workspace could otherwise send the requests of the
+ * active credentials, and their payloads, to any host.
+ *
+ * @param serviceId  SDK identifier of the service, e.g. `s3` or `cloudwatchlogs`
+ * @param credentialsId  Credentials the clients use, e.g. `profile:localstack`
+ * @throws Error if the endpoint is not an HTTP(S) URL
+ */
+export function getEndpointOptions(
+    serviceId: string | undefined,
+    credentialsId: string | undefined,
+    settings: SettingsConfiguration
+): ServiceConfigurationOptions {
+    const endpoints = settings.readUserSetting<{ [serviceId: string]: string }>('endpoints')
+    const endpoint = serviceId ? endpoints?.[serviceId] : ''
+    if (!endpoint || !endpointsApplyTo(credentialsId, settings.readUserSetting<string>('endpointsProfile'))) {
+        return {}
+    }
+
+    if (!isHttpUrl(endpoint)) {
+        throw new Error(
+            localize(
+                'AWS.configuration.endpoints.invalid',
+                'Invalid endpoint for {0} in setting aws.endpoints: "{1}" is not an http or https URL',
+                serviceId,
+                endpoint
+            )
+        )
+    }
+
+    // Emulators serve all buckets from one host, so bucket names can't be part of the host name
+    const forcePathStyle = serviceId === 's3' && (settings.readUserSetting<boolean>('s3.forcePathStyle') ?? false)
+
+    return forcePathStyle ? { endpoint, s3ForcePathStyle: true } : { endpoint }
+}
+
+function isHttpUrl(value: string): boolean {
+    try {
+        return ['http:', 'https:'].includes(new URL(value).protocol)
+    } catch (e) {
+        return false
+    }
+}
+
+function endpointsApplyTo(credentialsId: string | undefined, profile: string | undefined): boolean {
+    if (!profile) {
+        return true
+    }
+    // the profile can be given by name, or as a credentials ID like `profile:localstack`
+    const profileName = credentialsId?.substring(credentialsId.indexOf(':') + 1)
+
+    return credentialsId === profile || profileName === profile
+}
 
 export interface AWSClientBuilder {
     /**
@@ -28,9 +89,14 @@ export interface AWSClientBuilder {
 
 export class DefaultAWSClientBuilder implements AWSClientBuilder {
     private readonly _awsContext: AwsContext
+    private readonly _settings: SettingsConfiguration
 
-    public constructor(awsContext: AwsContext) {
+    public constructor(
+        awsContext: AwsContext,
+        settings: SettingsConfiguration = new DefaultSettingsConfiguration(extensionSettingsPrefix)
+    ) {
         this._awsContext = awsContext
+        this._settings = settings
     }
 
     /** @inheritdoc */
@@ -41,6 +107,8 @@ export class DefaultAWSClientBuilder implements AWSClientBuilder {
         userAgent: boolean = true
     ): Promise<T> {
         const opt = { ...options } as ServiceConfigurationOptions
+        // Clients given other credentials (e.g. while logging in) set their own endpoints
+        const usesActiveCredentials = !opt.credentials
 
         if (!opt.credentials) {
             opt.credentials = await this._awsContext.getCredentials()
@@ -50,6 +118,12 @@ export class DefaultAWSClientBuilder implements AWSClientBuilder {
             opt.region = region
         }
 
+        if (usesActiveCredentials && !opt.endpoint) {
+            const serviceId = ((type as unknown) as { serviceIdentifier?: string }).serviceIdentifier
+            const profile = this._awsContext.getCredentialProfileName()
+            Object.assign(opt, getEndpointOptions(serviceId, profile, this._settings))
+        }
+
         if (userAgent && !opt.customUserAgent) {
             const platformName = env.appName.replace(/\s/g, '-')
             opt.customUserAgent = `AWS-Toolkit-For-VSCode/${pluginVersion} ${platformName}/${version}`
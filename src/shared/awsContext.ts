@@ -10,6 +10,8 @@ export interface AwsContextCredentials {
     readonly credentialsId: string
     readonly accountId?: string
     readonly defaultRegion?: string
+    // partition the account belongs to, e.g. `aws-us-gov`
+    readonly partitionId?: string
 }
 
 // Carries the current context data on events
@@ -31,6 +33,9 @@ export interface AwsContext {
 
     getCredentialAccountId(): string | undefined
 
+    // returns the partition of the configured profile's account, if known
+    getCredentialPartition(): string | undefined
+
     getCredentialDefaultRegion(): string
 
     getExplorerRegions(): Promise<string[]>
@@ -62,7 +62,8 @@ export class DefaultApiGatewayClient {
         resourceId: string,
         method: string,
         body: string,
-        pathWithQueryString: string | undefined
+        pathWithQueryString: string | undefined,
+        headers?: APIGateway.MapOfStringToString
     ): Promise<APIGateway.TestInvokeMethodResponse> {
         const client = await this.createSdkClient()
         const request: APIGateway.TestInvokeMethodRequest = {
@@ -74,6 +75,9 @@ export class DefaultApiGatewayClient {
         if (pathWithQueryString) {
             request.pathWithQueryString = pathWithQueryString
         }
+        if (headers && Object.keys(headers).length > 0) {
+            request.headers = headers
+        }
 
         return await client.testInvokeMethod(request).promise()
     }
@@ -0,0 +1,171 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AppConfig } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export interface CreateHostedConfigurationVersionRequest {
+    applicationId: string
+    profileId: string
+    content: string
+    contentType: string
+    /** Fails the request if another version was created since this one, so that it isn't overwritten unknowingly. */
+    latestVersionNumber?: number
+}
+
+export interface StartDeploymentRequest {
+    applicationId: string
+    environmentId: string
+    profileId: string
+    strategyId: string
+    configurationVersion: string
+}
+
+export type AppConfigClient = ClassToInterfaceType<DefaultAppConfigClient>
+export class DefaultAppConfigClient {
+    public constructor(public readonly regionCode: string) {}
+
+    public async *listApplications(): AsyncIterableIterator<AppConfig.Application> {
+        const client = await this.createSdkClient()
+        const request: AppConfig.ListApplicationsRequest = {}
+
+        do {
+            const response = await client.listApplications(request).promise()
+
+            yield* response.Items ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async *listEnvironments(applicationId: string): AsyncIterableIterator<AppConfig.Environment> {
+        const client = await this.createSdkClient()
+        const request: AppConfig.ListEnvironmentsRequest = { ApplicationId: applicationId }
+
+        do {
+            const response = await client.listEnvironments(request).promise()
+
+            yield* response.Items ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async *listConfigurationProfiles(
+        applicationId: string
+    ): AsyncIterableIterator<AppConfig.ConfigurationProfileSummary> {
+        const client = await this.createSdkClient()
+        const request: AppConfig.ListConfigurationProfilesRequest = { ApplicationId: applicationId }
+
+        do {
+            const response = await client.listConfigurationProfiles(request).promise()
+
+            yield* response.Items ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async *listDeploymentStrategies(): AsyncIterableIterator<AppConfig.DeploymentStrategy> {
+        const client = await this.createSdkClient()
+        const request: AppConfig.ListDeploymentStrategiesRequest = {}
+
+        do {
+            const response = await client.listDeploymentStrategies(request).promise()
+
+            yield* response.Items ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async *listHostedConfigurationVersions(
+        applicationId: string,
+        profileId: string
+    ): AsyncIterableIterator<AppConfig.HostedConfigurationVersionSummary> {
+        const client = await this.createSdkClient()
+        const request: AppConfig.ListHostedConfigurationVersionsRequest = {
+            ApplicationId: applicationId,
+            ConfigurationProfileId: profileId,
+        }
+
+        do {
+            const response = await client.listHostedConfigurationVersions(request).promise()
+
+            yield* response.Items ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async getHostedConfigurationVersion(
+        applicationId: string,
+        profileId: string,
+        versionNumber: number
+    ): Promise<AppConfig.HostedConfigurationVersion> {
+        const client = await this.createSdkClient()
+
+        return await client
+            .getHostedConfigurationVersion({
+                ApplicationId: applicationId,
+                ConfigurationProfileId: profileId,
+                VersionNumber: versionNumber,
+            })
+            .promise()
+    }
+
+    public async createHostedConfigurationVersion(
+        request: CreateHostedConfigurationVersionRequest
+    ): Promise<AppConfig.HostedConfigurationVersion> {
+        const client = await this.createSdkClient()
+
+        return await client
+            .createHostedConfigurationVersion({
+                ApplicationId: request.applicationId,
+                ConfigurationProfileId: request.profileId,
+                Content: Buffer.from(request.content, 'utf8'),
+                ContentType: request.contentType,
+                LatestVersionNumber: request.latestVersionNumber,
+            })
+            .promise()
+    }
+
+    public async startDeployment(request: StartDeploymentRequest): Promise<AppConfig.Deployment> {
+        const client = await this.createSdkClient()
+
+        return await client
+            .startDeployment({
+                ApplicationId: request.applicationId,
+                EnvironmentId: request.environmentId,
+                ConfigurationProfileId: request.profileId,
+                DeploymentStrategyId: request.strategyId,
+                ConfigurationVersion: request.configurationVersion,
+            })
+            .promise()
+    }
+
+    public async getDeployment(
+        applicationId: string,
+        environmentId: string,
+        deploymentNumber: number
+    ): Promise<AppConfig.Deployment> {
+        const client = await this.createSdkClient()
+
+        return await client
+            .getDeployment({
+                ApplicationId: applicationId,
+                EnvironmentId: environmentId,
+                DeploymentNumber: deploymentNumber,
+            })
+            .promise()
+    }
+
+    private async createSdkClient(): Promise<AppConfig> {
+        return await ext.sdkClientBuilder.createAwsService(AppConfig, undefined, this.regionCode)
+    }
+}
@@ -52,6 +52,85 @@ export class DefaultCloudFormationClient {
             .promise()
     }
 
+    /**
+     * Starts detecting drift of the stack's resources.
+     *
+     * @returns the ID of the drift detection operation
+     */
+    public async detectStackDrift(name: string): Promise<CloudFormation.StackDriftDetectionId> {
+        const client = await this.createSdkClient()
+
+        const response = await client
+            .detectStackDrift({
+                StackName: name,
+            })
+            .promise()
+
+        return response.StackDriftDetectionId
+    }
+
+    public async describeStackDriftDetectionStatus(
+        detectionId: CloudFormation.StackDriftDetectionId
+    ): Promise<CloudFormation.DescribeStackDriftDetectionStatusOutput> {
+        const client = await this.createSdkClient()
+
+        return await client
+            .describeStackDriftDetectionStatus({
+                StackDriftDetectionId: detectionId,
+            })
+            .promise()
+    }
+
+    /**
+     * Lists the drift of each resource that the last drift detection of the stack checked.
+     */
+    public async describeStackResourceDrifts(name: string): Promise<CloudFormation.StackResourceDrift[]> {
+        const client = await this.createSdkClient()
+
+        const request: CloudFormation.DescribeStackResourceDriftsInput = {
+            StackName: name,
+        }
+        const drifts: CloudFormation.StackResourceDrift[] = []
+
+        do {
+            const response = await client.describeStackResourceDrifts(request).promise()
+            drifts.push(...response.StackResourceDrifts)
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+
+        return drifts
+    }
+
+    public async describeStack(name: string): Promise<CloudFormation.Stack | undefined> {
+        const client = await this.createSdkClient()
+
+        const response = await client
+            .describeStacks({
+                StackName: name,
+            })
+            .promise()
+
+        return response.Stacks?.[0]
+    }
+
+    /**
+     * Gets a page of the stack's events, newest first.
+     */
+    public async describeStackEvents(
+        name: string,
+        nextToken?: string
+    ): Promise<CloudFormation.DescribeStackEventsOutput> {
+        const client = await this.createSdkClient()
+
+        return await client
+            .describeStackEvents({
+                StackName: name,
+                NextToken: nextToken,
+            })
+            .promise()
+    }
+
     private async createSdkClient(): Promise<CloudFormation> {
         return await ext.sdkClientBuilder.createAwsService(CloudFormation, undefined, this.regionCode)
     }
@@ -0,0 +1,35 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CloudTrail } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export type CloudTrailClient = ClassToInterfaceType<DefaultCloudTrailClient>
+export class DefaultCloudTrailClient {
+    public constructor(public readonly regionCode: string) {}
+
+    /**
+     * Lists the management events recorded in the region between the given times, newest first.
+     */
+    public async *lookupEvents(startTime: Date, endTime: Date): AsyncIterableIterator<CloudTrail.Event> {
+        const client = await this.createSdkClient()
+        const request: CloudTrail.LookupEventsRequest = { StartTime: startTime, EndTime: endTime }
+
+        do {
+            const response: CloudTrail.LookupEventsResponse = await client.lookupEvents(request).promise()
+
+            yield* response.Events ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    private async createSdkClient(): Promise<CloudTrail> {
+        return await ext.sdkClientBuilder.createAwsService(CloudTrail, undefined, this.regionCode)
+    }
+}
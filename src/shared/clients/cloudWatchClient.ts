@@ -0,0 +1,52 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CloudWatch } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export type CloudWatchClient = ClassToInterfaceType<DefaultCloudWatchClient>
+export class DefaultCloudWatchClient {
+    public constructor(public readonly regionCode: string) {}
+
+    /**
+     * Gets the data points of metrics, reading all pages of results.
+     *
+     * @returns the results of each query, with the data points of all pages combined.
+     */
+    public async getMetricData(request: CloudWatch.GetMetricDataInput): Promise<CloudWatch.MetricDataResult[]> {
+        const client = await this.createSdkClient()
+        request = { ...request }
+        const results = new Map<string, CloudWatch.MetricDataResult>()
+
+        do {
+            const response: CloudWatch.GetMetricDataOutput = await client.getMetricData(request).promise()
+
+            for (const result of response.MetricDataResults ?? []) {
+                const id = result.Id ?? ''
+                const previous = results.get(id)
+                results.set(
+                    id,
+                    previous
+                        ? {
+                              ...previous,
+                              Timestamps: [...(previous.Timestamps ?? []), ...(result.Timestamps ?? [])],
+                              Values: [...(previous.Values ?? []), ...(result.Values ?? [])],
+                          }
+                        : result
+                )
+            }
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+
+        return [...results.values()]
+    }
+
+    private async createSdkClient(): Promise<CloudWatch> {
+        return await ext.sdkClientBuilder.createAwsService(CloudWatch, undefined, this.regionCode)
+    }
+}
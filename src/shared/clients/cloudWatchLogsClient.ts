@@ -11,9 +11,11 @@ export type CloudWatchLogsClient = ClassToInterfaceType<DefaultCloudWatchLogsCli
 export class DefaultCloudWatchLogsClient {
     public constructor(public readonly regionCode: string) {}
 
-    public async *describeLogGroups(): AsyncIterableIterator<CloudWatchLogs.LogGroup> {
+    public async *describeLogGroups(
+        request: CloudWatchLogs.DescribeLogGroupsRequest = {}
+    ): AsyncIterableIterator<CloudWatchLogs.LogGroup> {
         const sdkClient = await this.createSdkClient()
-        const request: CloudWatchLogs.DescribeLogGroupsRequest = {}
+        request = { ...request }
         do {
             const response = await this.invokeDescribeLogGroups(request, sdkClient)
             if (response.logGroups) {
@@ -39,6 +41,32 @@ export class DefaultCloudWatchLogsClient {
         return sdkClient.getLogEvents(request).promise()
     }
 
+    public async filterLogEvents(
+        request: CloudWatchLogs.FilterLogEventsRequest
+    ): Promise<CloudWatchLogs.FilterLogEventsResponse> {
+        const sdkClient = await this.createSdkClient()
+
+        return sdkClient.filterLogEvents(request).promise()
+    }
+
+    public async startQuery(request: CloudWatchLogs.StartQueryRequest): Promise<CloudWatchLogs.StartQueryResponse> {
+        const sdkClient = await this.createSdkClient()
+
+        return sdkClient.startQuery(request).promise()
+    }
+
+    public async getQueryResults(queryId: string): Promise<CloudWatchLogs.GetQueryResultsResponse> {
+        const sdkClient = await this.createSdkClient()
+
+        return sdkClient.getQueryResults({ queryId }).promise()
+    }
+
+    public async stopQuery(queryId: string): Promise<CloudWatchLogs.StopQueryResponse> {
+        const sdkClient = await this.createSdkClient()
+
+        return sdkClient.stopQuery({ queryId }).promise()
+    }
+
     protected async invokeDescribeLogGroups(
         request: CloudWatchLogs.DescribeLogGroupsRequest,
         sdkClient: CloudWatchLogs
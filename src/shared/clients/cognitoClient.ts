@@ -0,0 +1,112 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { CognitoIdentityServiceProvider } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export interface ListUsersRequest {
+    userPoolId: string
+    /** A filter expression, e.g. `email ^= "jane"`. */
+    filter?: string
+    paginationToken?: string
+    /** At most 60, which is also the default. */
+    limit?: number
+}
+
+export interface ListUsersResponse {
+    users: CognitoIdentityServiceProvider.UserType[]
+    paginationToken?: string
+}
+
+export type CognitoClient = ClassToInterfaceType<DefaultCognitoClient>
+export class DefaultCognitoClient {
+    public constructor(public readonly regionCode: string) {}
+
+    public async *listUserPools(): AsyncIterableIterator<CognitoIdentityServiceProvider.UserPoolDescriptionType> {
+        const client = await this.createSdkClient()
+        const request: CognitoIdentityServiceProvider.ListUserPoolsRequest = { MaxResults: 60 }
+
+        do {
+            const response = await client.listUserPools(request).promise()
+
+            yield* response.UserPools ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async describeUserPool(userPoolId: string): Promise<CognitoIdentityServiceProvider.UserPoolType> {
+        const client = await this.createSdkClient()
+        const response = await client.describeUserPool({ UserPoolId: userPoolId }).promise()
+
+        return response.UserPool ?? {}
+    }
+
+    /**
+     * Lists a page of the users of a user pool.
+     */
+    public async listUsers(request: ListUsersRequest): Promise<ListUsersResponse> {
+        const client = await this.createSdkClient()
+        const response = await client
+            .listUsers({
+                UserPoolId: request.userPoolId,
+                Filter: request.filter,
+                PaginationToken: request.paginationToken,
+                Limit: request.limit,
+            })
+            .promise()
+
+        return { users: response.Users ?? [], paginationToken: response.PaginationToken }
+    }
+
+    public async adminGetUser(
+        userPoolId: string,
+        username: string
+    ): Promise<CognitoIdentityServiceProvider.AdminGetUserResponse> {
+        const client = await this.createSdkClient()
+
+        return await client.adminGetUser({ UserPoolId: userPoolId, Username: username }).promise()
+    }
+
+    /**
+     * Sets the password of a user. A temporary password must be changed when the user next signs in.
+     */
+    public async adminSetUserPassword(
+        userPoolId: string,
+        username: string,
+        password: string,
+        permanent: boolean
+    ): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client
+            .adminSetUserPassword({
+                UserPoolId: userPoolId,
+                Username: username,
+                Password: password,
+                Permanent: permanent,
+            })
+            .promise()
+    }
+
+    public async adminEnableUser(userPoolId: string, username: string): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.adminEnableUser({ UserPoolId: userPoolId, Username: username }).promise()
+    }
+
+    public async adminDisableUser(userPoolId: string, username: string): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.adminDisableUser({ UserPoolId: userPoolId, Username: username }).promise()
+    }
+
+    private async createSdkClient(): Promise<CognitoIdentityServiceProvider> {
+        return await ext.sdkClientBuilder.createAwsService(CognitoIdentityServiceProvider, undefined, this.regionCode)
+    }
+}
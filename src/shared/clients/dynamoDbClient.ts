@@ -0,0 +1,99 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AWSError, DynamoDB } from 'aws-sdk'
+import { ext } from '../extensionGlobals'
+import { getLogger } from '../logger'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+/** Attempts made (including the first) before a throttled read is reported as a failure. */
+export const DEFAULT_THROTTLE_MAX_ATTEMPTS = 5
+const THROTTLE_BASE_DELAY_MILLIS = 250
+
+export type DynamoDbClient = ClassToInterfaceType<DefaultDynamoDbClient>
+export class DefaultDynamoDbClient {
+    public constructor(
+        public readonly regionCode: string,
+        private readonly throttleMaxAttempts: number = DEFAULT_THROTTLE_MAX_ATTEMPTS,
+        private readonly throttleBaseDelayMillis: number = THROTTLE_BASE_DELAY_MILLIS
+    ) {}
+
+    public async *listTables(): AsyncIterableIterator<string> {
+        const sdkClient = await this.createSdkClient()
+        const request: DynamoDB.ListTablesInput = {}
+        do {
+            const response = await this.invokeListTables(request, sdkClient)
+            if (response.TableNames) {
+                yield* response.TableNames
+            }
+            request.ExclusiveStartTableName = response.LastEvaluatedTableName
+        } while (request.ExclusiveStartTableName)
+    }
+
+    public async describeTable(tableName: string): Promise<DynamoDB.TableDescription> {
+        const sdkClient = await this.createSdkClient()
+        const response = await sdkClient.describeTable({ TableName: tableName }).promise()
+        if (!response.Table) {
+            throw new Error(`Table not found: ${tableName}`)
+        }
+
+        return response.Table
+    }
+
+    /**
+     * Reads a single page of items. Retries with exponential backoff when the table's
+     * provisioned throughput is exceeded.
+     */
+    public async scan(request: DynamoDB.ScanInput): Promise<DynamoDB.ScanOutput> {
+        const sdkClient = await this.createSdkClient()
+
+        return this.retryThrottled(() => this.invokeScan(request, sdkClient))
+    }
+
+    /**
+     * Reads a single page of items. Retries with exponential backoff when the table's
+     * provisioned throughput is exceeded.
+     */
+    public async query(request: DynamoDB.QueryInput): Promise<DynamoDB.QueryOutput> {
+        const sdkClient = await this.createSdkClient()
+
+        return this.retryThrottled(() => this.invokeQuery(request, sdkClient))
+    }
+
+    protected async invokeListTables(
+        request: DynamoDB.ListTablesInput,
+        sdkClient: DynamoDB
+    ): Promise<DynamoDB.ListTablesOutput> {
+        return sdkClient.listTables(request).promise()
+    }
+
+    protected async invokeScan(request: DynamoDB.ScanInput, sdkClient: DynamoDB): Promise<DynamoDB.ScanOutput> {
+        return sdkClient.scan(request).promise()
+    }
+
+    protected async invokeQuery(request: DynamoDB.QueryInput, sdkClient: DynamoDB): Promise<DynamoDB.QueryOutput> {
+        return sdkClient.query(request).promise()
+    }
+
+    protected async createSdkClient(): Promise<DynamoDB> {
+        return await ext.sdkClientBuilder.createAwsService(DynamoDB, undefined, this.regionCode)
+    }
+
+    private async retryThrottled<T>(fn: () => Promise<T>): Promise<T> {
+        for (let attempt = 1; ; attempt++) {
+            try {
+                return await fn()
+            } catch (e) {
+                const error = e as AWSError
+                if (error.code !== 'ProvisionedThroughputExceededException' || attempt >= this.throttleMaxAttempts) {
+                    throw error
+                }
+                const delay = this.throttleBaseDelayMillis * Math.pow(2, attempt - 1)
+                getLogger().debug(`DynamoDB read throttled (attempt ${attempt}), retrying in ${delay}ms`)
+                await new Promise(resolve => setTimeout(resolve, delay))
+            }
+        }
+    }
+}
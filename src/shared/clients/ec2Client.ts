@@ -0,0 +1,69 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { EC2 } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export type Ec2Client = ClassToInterfaceType<DefaultEc2Client>
+export class DefaultEc2Client {
+    public constructor(public readonly regionCode: string) {}
+
+    public async *describeInstances(): AsyncIterableIterator<EC2.Instance> {
+        const client = await this.createSdkClient()
+        const request: EC2.DescribeInstancesRequest = {}
+
+        do {
+            const response: EC2.DescribeInstancesResult = await client.describeInstances(request).promise()
+
+            for (const reservation of response.Reservations ?? []) {
+                yield* reservation.Instances ?? []
+            }
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async getInstance(instanceId: string): Promise<EC2.Instance | undefined> {
+        const client = await this.createSdkClient()
+        const response = await client.describeInstances({ InstanceIds: [instanceId] }).promise()
+
+        return response.Reservations?.[0]?.Instances?.[0]
+    }
+
+    public async startInstance(instanceId: string): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.startInstances({ InstanceIds: [instanceId] }).promise()
+    }
+
+    public async stopInstance(instanceId: string): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.stopInstances({ InstanceIds: [instanceId] }).promise()
+    }
+
+    public async rebootInstance(instanceId: string): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.rebootInstances({ InstanceIds: [instanceId] }).promise()
+    }
+
+    /**
+     * Lists all regions of the partition, including opt-in regions that are not enabled for the account.
+     */
+    public async describeRegions(): Promise<EC2.Region[]> {
+        const client = await this.createSdkClient()
+        const response = await client.describeRegions({ AllRegions: true }).promise()
+
+        return response.Regions ?? []
+    }
+
+    private async createSdkClient(): Promise<EC2> {
+        return await ext.sdkClientBuilder.createAwsService(EC2, undefined, this.regionCode)
+    }
+}
@@ -12,6 +12,13 @@ export interface EcrRepository {
     repositoryUri: string
 }
 
+/** Credentials for `docker login` to the registry of an account, which are valid for 12 hours. */
+export interface EcrAuthorization {
+    username: string
+    password: string
+    proxyEndpoint: string
+}
+
 export type EcrClient = ClassToInterfaceType<DefaultEcrClient>
 export class DefaultEcrClient {
     public constructor(public readonly regionCode: string) {}
@@ -75,6 +82,25 @@ export class DefaultEcrClient {
         await sdkClient.batchDeleteImage({ repositoryName: repositoryName, imageIds: [{ imageTag: tag }] }).promise()
     }
 
+    public async getAuthorizationToken(): Promise<EcrAuthorization> {
+        const sdkClient = await this.createSdkClient()
+        const response = await sdkClient.getAuthorizationToken().promise()
+        const data = response.authorizationData?.[0]
+        if (!data?.authorizationToken || !data.proxyEndpoint) {
+            throw new Error('GetAuthorizationToken did not return an authorization token')
+        }
+
+        // The token is the base64 encoding of "user:password"
+        const decoded = Buffer.from(data.authorizationToken, 'base64').toString()
+        const separator = decoded.indexOf(':')
+
+        return {
+            username: decoded.substring(0, separator),
+            password: decoded.substring(separator + 1),
+            proxyEndpoint: data.proxyEndpoint,
+        }
+    }
+
     protected async createSdkClient(): Promise<ECR> {
         return await ext.sdkClientBuilder.createAwsService(ECR, undefined, this.regionCode)
     }
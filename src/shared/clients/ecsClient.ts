@@ -50,6 +50,55 @@ export class DefaultEcsClient {
         } while (request.nextToken)
     }
 
+    public async describeServices(cluster: string, services: string[]): Promise<ECS.Service[]> {
+        const sdkClient = await this.createSdkClient()
+        const response = await sdkClient.describeServices({ cluster, services }).promise()
+
+        return response.services ?? []
+    }
+
+    /**
+     * Lists the ARNs of the running tasks of a service.
+     */
+    public async *listTasks(cluster: string, serviceName: string): AsyncIterableIterator<string> {
+        const sdkClient = await this.createSdkClient()
+        const request: ECS.ListTasksRequest = {
+            cluster,
+            serviceName,
+            desiredStatus: 'RUNNING',
+        }
+        do {
+            const response = await this.invokeListTasks(request, sdkClient)
+            if (response.taskArns) {
+                yield* response.taskArns
+            }
+            request.nextToken = response.nextToken
+        } while (request.nextToken)
+    }
+
+    public async describeTasks(cluster: string, tasks: string[]): Promise<ECS.Task[]> {
+        const sdkClient = await this.createSdkClient()
+        const response = await sdkClient.describeTasks({ cluster, tasks }).promise()
+
+        return response.tasks ?? []
+    }
+
+    /**
+     * Turns on ECS Exec for a service. Only tasks started after this (hence the new deployment) support it.
+     */
+    public async enableExecuteCommand(cluster: string, service: string): Promise<void> {
+        const sdkClient = await this.createSdkClient()
+        await sdkClient
+            .updateService({ cluster, service, enableExecuteCommand: true, forceNewDeployment: true })
+            .promise()
+    }
+
+    public async executeCommand(request: ECS.ExecuteCommandRequest): Promise<ECS.ExecuteCommandResponse> {
+        const sdkClient = await this.createSdkClient()
+
+        return await sdkClient.executeCommand(request).promise()
+    }
+
     protected async invokeListClusters(
         request: ECS.ListClustersRequest,
         sdkClient: ECS
@@ -64,6 +113,10 @@ export class DefaultEcsClient {
         return sdkClient.listServices(request).promise()
     }
 
+    protected async invokeListTasks(request: ECS.ListTasksRequest, sdkClient: ECS): Promise<ECS.ListTasksResponse> {
+        return sdkClient.listTasks(request).promise()
+    }
+
     protected async invokeListTaskDefinitionFamilies(
         request: ECS.ListTaskDefinitionFamiliesRequest,
         sdkClient: ECS
@@ -0,0 +1,61 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { Kinesis } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export type KinesisClient = ClassToInterfaceType<DefaultKinesisClient>
+export class DefaultKinesisClient {
+    public constructor(public readonly regionCode: string) {}
+
+    public async *listStreams(): AsyncIterableIterator<string> {
+        const client = await this.createSdkClient()
+        const request: Kinesis.ListStreamsInput = {}
+
+        let response: Kinesis.ListStreamsOutput
+        do {
+            response = await client.listStreams(request).promise()
+            yield* response.StreamNames
+
+            request.ExclusiveStartStreamName = response.StreamNames[response.StreamNames.length - 1]
+        } while (response.HasMoreStreams && request.ExclusiveStartStreamName)
+    }
+
+    public async *listShards(streamName: string): AsyncIterableIterator<Kinesis.Shard> {
+        const client = await this.createSdkClient()
+        // `StreamName` must not be set together with `NextToken`
+        let request: Kinesis.ListShardsInput = { StreamName: streamName }
+
+        do {
+            const response: Kinesis.ListShardsOutput = await client.listShards(request).promise()
+
+            if (response.Shards) {
+                yield* response.Shards
+            }
+
+            request = { NextToken: response.NextToken }
+        } while (request.NextToken)
+    }
+
+    public async getShardIterator(request: Kinesis.GetShardIteratorInput): Promise<string | undefined> {
+        const client = await this.createSdkClient()
+        const response = await client.getShardIterator(request).promise()
+
+        return response.ShardIterator
+    }
+
+    public async getRecords(shardIterator: string, limit?: number): Promise<Kinesis.GetRecordsOutput> {
+        const client = await this.createSdkClient()
+
+        return await client.getRecords({ ShardIterator: shardIterator, Limit: limit }).promise()
+    }
+
+    private async createSdkClient(): Promise<Kinesis> {
+        return await ext.sdkClientBuilder.createAwsService(Kinesis, undefined, this.regionCode)
+    }
+}
@@ -28,7 +28,11 @@ export class DefaultLambdaClient {
         }
     }
 
-    public async invoke(name: string, payload?: _Blob): Promise<Lambda.InvocationResponse> {
+    /**
+     * Invokes a function.
+     * @param qualifier Version or alias to invoke. Defaults to `$LATEST`.
+     */
+    public async invoke(name: string, payload?: _Blob, qualifier?: string): Promise<Lambda.InvocationResponse> {
         const sdkClient = await this.createSdkClient()
 
         const response = await sdkClient
@@ -36,6 +40,7 @@ export class DefaultLambdaClient {
                 FunctionName: name,
                 LogType: 'Tail',
                 Payload: payload,
+                Qualifier: qualifier,
             })
             .promise()
 
@@ -75,6 +80,44 @@ export class DefaultLambdaClient {
         }
     }
 
+    public async getFunctionConfiguration(name: string): Promise<Lambda.FunctionConfiguration> {
+        getLogger().debug(`GetFunctionConfiguration called for function: ${name}`)
+        const client = await this.createSdkClient()
+
+        try {
+            const response = await client.getFunctionConfiguration({ FunctionName: name }).promise()
+            // prune environment variables from logs, since they may hold secrets
+            getLogger().debug('GetFunctionConfiguration returned response (environment pruned): %O', {
+                ...response,
+                Environment: 'Pruned',
+            })
+            return response
+        } catch (e) {
+            getLogger().error('Failed to get function configuration: %O', e)
+            throw e
+        }
+    }
+
+    /**
+     * Starts updating the configuration of a function. The update is done once the `LastUpdateStatus` of the
+     * function's configuration is no longer `InProgress`.
+     */
+    public async updateFunctionConfiguration(
+        request: Lambda.UpdateFunctionConfigurationRequest
+    ): Promise<Lambda.FunctionConfiguration> {
+        getLogger().debug(`UpdateFunctionConfiguration called for function: ${request.FunctionName}`)
+        const client = await this.createSdkClient()
+
+        try {
+            const response = await client.updateFunctionConfiguration(request).promise()
+            getLogger().debug('UpdateFunctionConfiguration returned status: %s', response.LastUpdateStatus)
+            return response
+        } catch (e) {
+            getLogger().error('Failed to update function configuration: %O', e)
+            throw e
+        }
+    }
+
     public async updateFunctionCode(name: string, zipFile: Buffer): Promise<Lambda.FunctionConfiguration> {
         getLogger().debug(`updateFunctionCode called for function: ${name}`)
         const client = await this.createSdkClient()
@@ -95,6 +138,122 @@ export class DefaultLambdaClient {
         }
     }
 
+    /**
+     * Lists the versions of a function, including `$LATEST`.
+     */
+    public async *listVersions(name: string): AsyncIterableIterator<Lambda.FunctionConfiguration> {
+        const client = await this.createSdkClient()
+
+        const request: Lambda.ListVersionsByFunctionRequest = { FunctionName: name }
+        do {
+            const response: Lambda.ListVersionsByFunctionResponse = await client
+                .listVersionsByFunction(request)
+                .promise()
+
+            if (response.Versions) {
+                yield* response.Versions
+            }
+
+            request.Marker = response.NextMarker
+        } while (request.Marker)
+    }
+
+    /**
+     * Publishes a version from the current code and configuration of a function. If neither changed since the
+     * latest version was published, that version is returned instead.
+     */
+    public async publishVersion(name: string, description?: string): Promise<Lambda.FunctionConfiguration> {
+        getLogger().debug(`PublishVersion called for function: ${name}`)
+        const client = await this.createSdkClient()
+
+        return await client.publishVersion({ FunctionName: name, Description: description }).promise()
+    }
+
+    public async *listAliases(name: string): AsyncIterableIterator<Lambda.AliasConfiguration> {
+        const client = await this.createSdkClient()
+
+        const request: Lambda.ListAliasesRequest = { FunctionName: name }
+        do {
+            const response: Lambda.ListAliasesResponse = await client.listAliases(request).promise()
+
+            if (response.Aliases) {
+                yield* response.Aliases
+            }
+
+            request.Marker = response.NextMarker
+        } while (request.Marker)
+    }
+
+    public async createAlias(request: Lambda.CreateAliasRequest): Promise<Lambda.AliasConfiguration> {
+        getLogger().debug(`CreateAlias called for function: ${request.FunctionName}`)
+        const client = await this.createSdkClient()
+
+        return await client.createAlias(request).promise()
+    }
+
+    /**
+     * Updates the version an alias points at, and the weights of any additional version it routes traffic to.
+     */
+    public async updateAlias(request: Lambda.UpdateAliasRequest): Promise<Lambda.AliasConfiguration> {
+        getLogger().debug(`UpdateAlias called for function: ${request.FunctionName}`)
+        const client = await this.createSdkClient()
+
+        return await client.updateAlias(request).promise()
+    }
+
+    public async *listLayers(): AsyncIterableIterator<Lambda.LayersListItem> {
+        const client = await this.createSdkClient()
+
+        const request: Lambda.ListLayersRequest = {}
+        do {
+            const response: Lambda.ListLayersResponse = await client.listLayers(request).promise()
+
+            if (response.Layers) {
+                yield* response.Layers
+            }
+
+            request.Marker = response.NextMarker
+        } while (request.Marker)
+    }
+
+    public async *listLayerVersions(layerName: string): AsyncIterableIterator<Lambda.LayerVersionsListItem> {
+        const client = await this.createSdkClient()
+
+        const request: Lambda.ListLayerVersionsRequest = { LayerName: layerName }
+        do {
+            const response: Lambda.ListLayerVersionsResponse = await client.listLayerVersions(request).promise()
+
+            if (response.LayerVersions) {
+                yield* response.LayerVersions
+            }
+
+            request.Marker = response.NextMarker
+        } while (request.Marker)
+    }
+
+    /**
+     * Gets a layer version by ARN, which may belong to another account that shares it.
+     */
+    public async getLayerVersion(layerVersionArn: string): Promise<Lambda.GetLayerVersionResponse> {
+        getLogger().debug(`GetLayerVersionByArn called for layer version: ${layerVersionArn}`)
+        const client = await this.createSdkClient()
+
+        const response = await client.getLayerVersionByArn({ Arn: layerVersionArn }).promise()
+        // prune `Content` from logs so we don't reveal a signed link to customer resources.
+        getLogger().debug('GetLayerVersionByArn returned response (content section pruned): %O', {
+            ...response,
+            Content: 'Pruned',
+        })
+
+        return response
+    }
+
+    public async deleteLayerVersion(layerName: string, versionNumber: number): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.deleteLayerVersion({ LayerName: layerName, VersionNumber: versionNumber }).promise()
+    }
+
     private async createSdkClient(): Promise<Lambda> {
         return await ext.sdkClientBuilder.createAwsService(Lambda, undefined, this.regionCode)
     }
@@ -0,0 +1,81 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { AWSError, ResourceGroupsTaggingAPI } from 'aws-sdk'
+import * as _ from 'lodash'
+
+import { ext } from '../extensionGlobals'
+import { getLogger } from '../logger'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+/** Most resources a `TagResources` or `UntagResources` call accepts. */
+export const MAX_RESOURCES_PER_TAGGING_CALL = 20
+
+export type ResourceGroupsTaggingClient = ClassToInterfaceType<DefaultResourceGroupsTaggingClient>
+export class DefaultResourceGroupsTaggingClient {
+    public constructor(public readonly regionCode: string) {}
+
+    /**
+     * Adds tags to resources of the region, or updates their values, in batches.
+     *
+     * @returns the resources that couldn't be tagged, keyed by ARN.
+     */
+    public async tagResources(
+        arns: string[],
+        tags: ResourceGroupsTaggingAPI.TagMap
+    ): Promise<ResourceGroupsTaggingAPI.FailedResourcesMap> {
+        const client = await this.createSdkClient()
+
+        return await this.inBatches(arns, batch =>
+            client.tagResources({ ResourceARNList: batch, Tags: tags }).promise()
+        )
+    }
+
+    /**
+     * Removes tags from resources of the region, in batches.
+     *
+     * @returns the resources that couldn't be untagged, keyed by ARN.
+     */
+    public async untagResources(
+        arns: string[],
+        tagKeys: string[]
+    ): Promise<ResourceGroupsTaggingAPI.FailedResourcesMap> {
+        const client = await this.createSdkClient()
+
+        return await this.inBatches(arns, batch =>
+            client.untagResources({ ResourceARNList: batch, TagKeys: tagKeys }).promise()
+        )
+    }
+
+    /**
+     * Makes a call per batch of resources. A failed call fails only the resources of its batch, so the
+     * other batches are still made.
+     */
+    private async inBatches(
+        arns: string[],
+        call: (batch: string[]) => Promise<{ FailedResourcesMap?: ResourceGroupsTaggingAPI.FailedResourcesMap }>
+    ): Promise<ResourceGroupsTaggingAPI.FailedResourcesMap> {
+        let failed: ResourceGroupsTaggingAPI.FailedResourcesMap = {}
+
+        for (const batch of _.chunk(arns, MAX_RESOURCES_PER_TAGGING_CALL)) {
+            try {
+                const response = await call(batch)
+                failed = { ...failed, ...response.FailedResourcesMap }
+            } catch (e) {
+                const error = e as AWSError
+                getLogger().error(`Failed to change tags of resources in ${this.regionCode}: %O`, error)
+                for (const arn of batch) {
+                    failed[arn] = { ErrorMessage: error.message, StatusCode: error.statusCode }
+                }
+            }
+        }
+
+        return failed
+    }
+
+    protected async createSdkClient(): Promise<ResourceGroupsTaggingAPI> {
+        return await ext.sdkClientBuilder.createAwsService(ResourceGroupsTaggingAPI, undefined, this.regionCode)
+    }
+}
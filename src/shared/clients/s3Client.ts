@@ -57,6 +57,12 @@ export interface ListFilesResponse {
     readonly continuationToken?: string
 }
 
+export interface ListFilesRecursiveRequest {
+    readonly bucketName: string
+    readonly prefix: string
+    readonly maxResults?: number // Defaults to DEFAULT_MAX_KEYS
+}
+
 export interface CreateFolderRequest {
     readonly bucketName: string
     readonly path: string
@@ -71,6 +77,8 @@ export interface DownloadFileRequest {
     readonly key: string
     readonly progressListener?: (loadedBytes: number) => void
     readonly saveLocation: vscode.Uri
+    /** Aborts the request when cancellation is requested. */
+    readonly cancellationToken?: vscode.CancellationToken
 }
 
 export interface UploadFileRequest {
@@ -78,6 +86,56 @@ export interface UploadFileRequest {
     readonly key: string
     readonly progressListener?: (loadedBytes: number) => void
     readonly fileLocation: vscode.Uri
+    /** Overrides the content type detected from the file extension. */
+    readonly contentType?: string
+    readonly cacheControl?: string
+    /** User-defined metadata, sent as `x-amz-meta-*` headers. */
+    readonly metadata?: { [key: string]: string }
+}
+
+export interface SignedUrlRequest {
+    readonly bucketName: string
+    readonly key: string
+    readonly expiresInSeconds: number
+}
+
+export interface GetServerSideEncryptionRequest {
+    readonly bucketName: string
+    readonly key: string
+}
+
+export interface ListKeyVersionsRequest {
+    readonly bucketName: string
+    readonly key: string
+}
+
+export interface ObjectVersion {
+    readonly versionId: string
+    readonly isLatest: boolean
+    readonly lastModified?: Date
+    readonly sizeBytes?: number
+}
+
+export interface HeadObjectRequest {
+    readonly bucketName: string
+    readonly key: string
+    readonly versionId?: string
+}
+
+export interface HeadObjectResponse {
+    readonly sizeBytes?: number
+    readonly contentType?: string
+}
+
+export interface GetObjectRequest {
+    readonly bucketName: string
+    readonly key: string
+    readonly versionId?: string
+}
+
+export interface GetObjectResponse {
+    readonly body: Buffer
+    readonly contentType?: string
 }
 
 export interface ListObjectVersionsRequest {
@@ -109,7 +167,41 @@ export interface DeleteBucketRequest {
     readonly bucketName: string
 }
 
-const DEFAULT_CONTENT_TYPE = 'application/octet-stream'
+export interface CreateMultipartUploadRequest {
+    readonly bucketName: string
+    readonly key: string
+    /** Overrides the content type detected from the key. */
+    readonly contentType?: string
+    readonly cacheControl?: string
+    /** User-defined metadata, sent as `x-amz-meta-*` headers. */
+    readonly metadata?: { [key: string]: string }
+}
+
+export interface MultipartUploadRequest {
+    readonly bucketName: string
+    readonly key: string
+    readonly uploadId: string
+}
+
+export interface UploadPartRequest extends MultipartUploadRequest {
+    /** 1 to 10,000, in the order of the parts in the object. */
+    readonly partNumber: number
+    readonly body: Buffer
+    readonly progressListener?: (loadedBytes: number) => void
+    /** Aborts the request when cancellation is requested. */
+    readonly cancellationToken?: vscode.CancellationToken
+}
+
+export interface CompletedPart {
+    readonly partNumber: number
+    readonly eTag: string
+}
+
+export interface CompleteMultipartUploadRequest extends MultipartUploadRequest {
+    readonly parts: CompletedPart[]
+}
+
+export const DEFAULT_CONTENT_TYPE = 'application/octet-stream'
 
 export class DefaultS3Client {
     public constructor(
@@ -252,14 +344,18 @@ export class DefaultS3Client {
         const s3 = await this.createS3()
 
         // https://docs.aws.amazon.com/sdk-for-javascript/v2/developer-guide/requests-using-stream-objects.html
-        const readStream = s3.getObject({ Bucket: request.bucketName, Key: request.key }).createReadStream()
+        const getObjectRequest = s3.getObject({ Bucket: request.bucketName, Key: request.key })
+        const readStream = getObjectRequest.createReadStream()
         const writeStream = this.fileStreams.createWriteStream(request.saveLocation)
+        const cancellationListener = request.cancellationToken?.onCancellationRequested(() => getObjectRequest.abort())
 
         try {
             await pipe(readStream, writeStream, request.progressListener)
         } catch (e) {
             getLogger().error(`Failed to download %s from bucket %s: %O`, request.key, request.bucketName, e)
             throw e
+        } finally {
+            cancellationListener?.dispose()
         }
         getLogger().debug('DownloadFile succeeded')
     }
@@ -270,7 +366,7 @@ export class DefaultS3Client {
      * The destination bucket should reside in the same region as the one configured for the client.
      *
      * Pipes the file (read) stream into the request (write) stream.
-     * Assigns the target content type based on the mime type of the file.
+     * Assigns the target content type based on the mime type of the file, unless the request specifies one.
      * If content type cannot be determined, defaults to {@link DEFAULT_CONTENT_TYPE}.
      *
      * @throws Error if there is an error calling S3 or piping between streams.
@@ -286,13 +382,16 @@ export class DefaultS3Client {
 
         // https://docs.aws.amazon.com/sdk-for-javascript/v2/developer-guide/s3-example-creating-buckets.html#s3-example-creating-buckets-upload-file
         const readStream = this.fileStreams.createReadStream(request.fileLocation)
-        const contentType = mime.lookup(path.basename(request.fileLocation.fsPath)) || DEFAULT_CONTENT_TYPE
+        const contentType =
+            request.contentType || mime.lookup(path.basename(request.fileLocation.fsPath)) || DEFAULT_CONTENT_TYPE
 
         const managedUploaded = s3.upload({
             Bucket: request.bucketName,
             Key: request.key,
             Body: readStream,
             ContentType: contentType,
+            CacheControl: request.cacheControl,
+            Metadata: request.metadata,
         })
 
         const progressListener = request.progressListener
@@ -311,6 +410,255 @@ export class DefaultS3Client {
         getLogger().debug('UploadFile succeeded')
     }
 
+    /**
+     * Starts a multipart upload, whose parts are uploaded with {@link uploadPart}.
+     * Assigns the content type based on the mime type of the key, unless the request specifies one.
+     *
+     * @returns the ID of the upload.
+     * @throws Error if there is an error calling S3.
+     */
+    public async createMultipartUpload(request: CreateMultipartUploadRequest): Promise<string> {
+        getLogger().debug('CreateMultipartUpload called for bucketName: %s, key: %s', request.bucketName, request.key)
+        const s3 = await this.createS3()
+
+        let uploadId: string
+        try {
+            const output = await s3
+                .createMultipartUpload({
+                    Bucket: request.bucketName,
+                    Key: request.key,
+                    ContentType: request.contentType || mime.lookup(path.basename(request.key)) || DEFAULT_CONTENT_TYPE,
+                    CacheControl: request.cacheControl,
+                    Metadata: request.metadata,
+                })
+                .promise()
+            uploadId = output.UploadId!
+        } catch (e) {
+            getLogger().error(
+                'Failed to create multipart upload of %s to bucket %s: %O',
+                request.key,
+                request.bucketName,
+                e
+            )
+            throw e
+        }
+
+        getLogger().debug('CreateMultipartUpload returned uploadId: %s', uploadId)
+        return uploadId
+    }
+
+    /**
+     * Uploads a part of a multipart upload.
+     *
+     * @returns the part, with the ETag to complete the upload with.
+     * @throws Error if there is an error calling S3.
+     */
+    public async uploadPart(request: UploadPartRequest): Promise<CompletedPart> {
+        getLogger().debug('UploadPart called for uploadId: %s, partNumber: %d', request.uploadId, request.partNumber)
+        const s3 = await this.createS3()
+
+        const uploadPartRequest = s3.uploadPart({
+            Bucket: request.bucketName,
+            Key: request.key,
+            UploadId: request.uploadId,
+            PartNumber: request.partNumber,
+            Body: request.body,
+        })
+        const progressListener = request.progressListener
+        if (progressListener) {
+            uploadPartRequest.on('httpUploadProgress', progress => progressListener(progress.loaded))
+        }
+        const cancellationListener = request.cancellationToken?.onCancellationRequested(() =>
+            uploadPartRequest.abort()
+        )
+
+        let part: CompletedPart
+        try {
+            const output = await uploadPartRequest.promise()
+            part = { partNumber: request.partNumber, eTag: output.ETag! }
+        } catch (e) {
+            getLogger().error('Failed to upload part %d of upload %s: %O', request.partNumber, request.uploadId, e)
+            throw e
+        } finally {
+            cancellationListener?.dispose()
+        }
+
+        getLogger().debug('UploadPart returned part: %O', part)
+        return part
+    }
+
+    /**
+     * Lists the parts that were uploaded so far in a multipart upload.
+     *
+     * @throws Error if there is an error calling S3, e.g. `NoSuchUpload` if the upload was completed or aborted.
+     */
+    public async listParts(request: MultipartUploadRequest): Promise<CompletedPart[]> {
+        getLogger().debug('ListParts called for uploadId: %s', request.uploadId)
+        const s3 = await this.createS3()
+
+        const parts: CompletedPart[] = []
+        try {
+            let partNumberMarker: string | undefined
+            do {
+                const output = await s3
+                    .listParts({
+                        Bucket: request.bucketName,
+                        Key: request.key,
+                        UploadId: request.uploadId,
+                        PartNumberMarker: partNumberMarker,
+                    })
+                    .promise()
+                parts.push(...(output.Parts ?? []).map(part => ({ partNumber: part.PartNumber!, eTag: part.ETag! })))
+                partNumberMarker = output.IsTruncated ? output.NextPartNumberMarker : undefined
+            } while (partNumberMarker)
+        } catch (e) {
+            getLogger().error('Failed to list parts of upload %s: %O', request.uploadId, e)
+            throw e
+        }
+
+        getLogger().debug('ListParts returned %d parts', parts.length)
+        return parts
+    }
+
+    /**
+     * Completes a multipart upload, assembling the object from its parts.
+     *
+     * @throws Error if there is an error calling S3.
+     */
+    public async completeMultipartUpload(request: CompleteMultipartUploadRequest): Promise<void> {
+        getLogger().debug('CompleteMultipartUpload called for uploadId: %s', request.uploadId)
+        const s3 = await this.createS3()
+
+        try {
+            await s3
+                .completeMultipartUpload({
+                    Bucket: request.bucketName,
+                    Key: request.key,
+                    UploadId: request.uploadId,
+                    MultipartUpload: {
+                        Parts: request.parts.map(({ partNumber: PartNumber, eTag: ETag }) => ({ PartNumber, ETag })),
+                    },
+                })
+                .promise()
+        } catch (e) {
+            getLogger().error('Failed to complete upload %s: %O', request.uploadId, e)
+            throw e
+        }
+
+        getLogger().debug('CompleteMultipartUpload succeeded')
+    }
+
+    /**
+     * Aborts a multipart upload, deleting the parts that were uploaded so they are no longer charged for.
+     *
+     * @throws Error if there is an error calling S3.
+     */
+    public async abortMultipartUpload(request: MultipartUploadRequest): Promise<void> {
+        getLogger().debug('AbortMultipartUpload called for uploadId: %s', request.uploadId)
+        const s3 = await this.createS3()
+
+        try {
+            await s3
+                .abortMultipartUpload({ Bucket: request.bucketName, Key: request.key, UploadId: request.uploadId })
+                .promise()
+        } catch (e) {
+            getLogger().error('Failed to abort upload %s: %O', request.uploadId, e)
+            throw e
+        }
+
+        getLogger().debug('AbortMultipartUpload succeeded')
+    }
+
+    /**
+     * Generates a SigV4 presigned URL to download a file.
+     *
+     * The URL is signed with the credentials of the client, so it stops working when those expire, even if that is
+     * before the requested expiration.
+     *
+     * @throws Error if the URL cannot be signed.
+     */
+    public async getSignedUrl(request: SignedUrlRequest): Promise<string> {
+        getLogger().debug(
+            'GetSignedUrl called for bucketName: %s, key: %s, expiresInSeconds: %d',
+            request.bucketName,
+            request.key,
+            request.expiresInSeconds
+        )
+        const s3 = await this.createS3()
+
+        const url = await s3.getSignedUrlPromise('getObject', {
+            Bucket: request.bucketName,
+            Key: request.key,
+            Expires: request.expiresInSeconds,
+        })
+        getLogger().debug('GetSignedUrl succeeded')
+        return url
+    }
+
+    /**
+     * Gets the server-side encryption algorithm of a file, such as `aws:kms`.
+     *
+     * @throws Error if there is an error calling S3.
+     */
+    public async getServerSideEncryption(request: GetServerSideEncryptionRequest): Promise<string | undefined> {
+        getLogger().debug('GetServerSideEncryption called for bucketName: %s, key: %s', request.bucketName, request.key)
+        const s3 = await this.createS3()
+
+        const output = await s3.headObject({ Bucket: request.bucketName, Key: request.key }).promise()
+        getLogger().debug('GetServerSideEncryption returned: %s', output.ServerSideEncryption)
+        return output.ServerSideEncryption
+    }
+
+    /**
+     * Gets the size and content type of an object, or of a version of it.
+     *
+     * @throws Error if there is an error calling S3.
+     */
+    public async headObject(request: HeadObjectRequest): Promise<HeadObjectResponse> {
+        getLogger().debug('HeadObject called with request: %O', request)
+        const s3 = await this.createS3()
+
+        let output: S3.HeadObjectOutput
+        try {
+            output = await s3
+                .headObject({ Bucket: request.bucketName, Key: request.key, VersionId: request.versionId })
+                .promise()
+        } catch (e) {
+            getLogger().error('Failed to head object %s in bucket %s: %O', request.key, request.bucketName, e)
+            throw e
+        }
+
+        const response: HeadObjectResponse = { sizeBytes: output.ContentLength, contentType: output.ContentType }
+        getLogger().debug('HeadObject returned response: %O', response)
+        return response
+    }
+
+    /**
+     * Reads the content of an object, or of a version of it, into memory.
+     * Use {@link downloadFile} for objects that may be too large for that.
+     *
+     * @throws Error if there is an error calling S3.
+     */
+    public async getObject(request: GetObjectRequest): Promise<GetObjectResponse> {
+        getLogger().debug('GetObject called with request: %O', request)
+        const s3 = await this.createS3()
+
+        let output: S3.GetObjectOutput
+        try {
+            output = await s3
+                .getObject({ Bucket: request.bucketName, Key: request.key, VersionId: request.versionId })
+                .promise()
+        } catch (e) {
+            getLogger().error('Failed to get object %s from bucket %s: %O', request.key, request.bucketName, e)
+            throw e
+        }
+
+        // the SDK reads bodies into a Buffer in Node.js
+        const body = Buffer.from((output.Body as Uint8Array | undefined) ?? new Uint8Array())
+        getLogger().debug('GetObject returned %d bytes', body.length)
+        return { body, contentType: output.ContentType }
+    }
+
     /**
      * Lists all buckets owned by the client.
      *
@@ -446,6 +794,47 @@ export class DefaultS3Client {
         return response
     }
 
+    /**
+     * Lists all objects under a prefix, including those in nested folders.
+     *
+     * The bucket should reside in the same region as the one configured for the client.
+     *
+     * @throws Error from the iterable if there is an error calling S3.
+     */
+    public async *listFilesRecursive(request: ListFilesRecursiveRequest): AsyncIterableIterator<File> {
+        getLogger().debug('ListFilesRecursive called with request: %O', request)
+        const s3 = await this.createS3()
+
+        let continuationToken: string | undefined
+        do {
+            let output: S3.ListObjectsV2Output
+            try {
+                output = await s3
+                    .listObjectsV2({
+                        Bucket: request.bucketName,
+                        MaxKeys: request.maxResults ?? DEFAULT_MAX_KEYS,
+                        Prefix: request.prefix,
+                        ContinuationToken: continuationToken,
+                    })
+                    .promise()
+            } catch (e) {
+                getLogger().error('Failed to list files for bucket %s: %O', request.bucketName, e)
+                throw e
+            }
+
+            for (const file of output.Contents ?? []) {
+                yield new DefaultFile({
+                    key: file.Key!,
+                    partitionId: this.partitionId,
+                    bucketName: request.bucketName,
+                    lastModified: file.LastModified,
+                    sizeBytes: file.Size,
+                })
+            }
+            continuationToken = output.NextContinuationToken
+        } while (continuationToken)
+    }
+
     /**
      * Lists versions of all objects inside a bucket.
      *
@@ -510,6 +899,56 @@ export class DefaultS3Client {
         } while (continuationToken)
     }
 
+    /**
+     * Lists the versions of an object, newest first. Delete markers are left out.
+     *
+     * Objects of unversioned buckets have a single version, whose ID is `null`.
+     *
+     * @throws Error if there is an error calling S3.
+     */
+    public async listKeyVersions(request: ListKeyVersionsRequest): Promise<ObjectVersion[]> {
+        getLogger().debug('ListKeyVersions called with request: %O', request)
+        const s3 = await this.createS3()
+
+        const versions: ObjectVersion[] = []
+        let continuationToken: ContinuationToken | undefined
+        try {
+            do {
+                const output = await s3
+                    .listObjectVersions({
+                        Bucket: request.bucketName,
+                        Prefix: request.key,
+                        KeyMarker: continuationToken?.keyMarker,
+                        VersionIdMarker: continuationToken?.versionIdMarker,
+                    })
+                    .promise()
+
+                // versions are sorted by key, so those of longer keys with the same prefix come after the object's
+                for (const version of output.Versions ?? []) {
+                    if (version.Key === request.key) {
+                        versions.push({
+                            versionId: version.VersionId ?? 'null',
+                            isLatest: version.IsLatest ?? false,
+                            lastModified: version.LastModified,
+                            sizeBytes: version.Size,
+                        })
+                    }
+                }
+                const pastKey = output.NextKeyMarker !== undefined && output.NextKeyMarker !== request.key
+                continuationToken =
+                    output.IsTruncated && !pastKey
+                        ? { keyMarker: output.NextKeyMarker!, versionIdMarker: output.NextVersionIdMarker }
+                        : undefined
+            } while (continuationToken)
+        } catch (e) {
+            getLogger().error('Failed to list versions of %s in bucket %s: %O', request.key, request.bucketName, e)
+            throw e
+        }
+
+        getLogger().debug('ListKeyVersions returned %d versions', versions.length)
+        return versions
+    }
+
     /**
      * Deletes an object from a bucket.
      *
@@ -0,0 +1,49 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { SecretsManager } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export type SecretsManagerClient = ClassToInterfaceType<DefaultSecretsManagerClient>
+export class DefaultSecretsManagerClient {
+    public constructor(public readonly regionCode: string) {}
+
+    public async *listSecrets(): AsyncIterableIterator<SecretsManager.SecretListEntry> {
+        const client = await this.createSdkClient()
+        const request: SecretsManager.ListSecretsRequest = {}
+
+        do {
+            const response: SecretsManager.ListSecretsResponse = await client.listSecrets(request).promise()
+
+            if (response.SecretList) {
+                yield* response.SecretList
+            }
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async getSecretValue(secretId: string): Promise<SecretsManager.GetSecretValueResponse> {
+        const client = await this.createSdkClient()
+
+        return await client.getSecretValue({ SecretId: secretId }).promise()
+    }
+
+    public async putSecretValue(
+        secretId: string,
+        secretString: string
+    ): Promise<SecretsManager.PutSecretValueResponse> {
+        const client = await this.createSdkClient()
+
+        return await client.putSecretValue({ SecretId: secretId, SecretString: secretString }).promise()
+    }
+
+    private async createSdkClient(): Promise<SecretsManager> {
+        return await ext.sdkClientBuilder.createAwsService(SecretsManager, undefined, this.regionCode)
+    }
+}
@@ -0,0 +1,81 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { SQS } from 'aws-sdk'
+
+import { ext } from '../extensionGlobals'
+import '../utilities/asyncIteratorShim'
+import { ClassToInterfaceType } from '../utilities/tsUtils'
+
+export type SqsClient = ClassToInterfaceType<DefaultSqsClient>
+export class DefaultSqsClient {
+    public constructor(public readonly regionCode: string) {}
+
+    /**
+     * Lists the URLs of the queues in the region.
+     */
+    public async *listQueues(): AsyncIterableIterator<string> {
+        const client = await this.createSdkClient()
+        const request: SQS.ListQueuesRequest = {}
+
+        do {
+            const response: SQS.ListQueuesResult = await client.listQueues(request).promise()
+
+            yield* response.QueueUrls ?? []
+
+            request.NextToken = response.NextToken
+        } while (request.NextToken)
+    }
+
+    public async getQueueAttributes(queueUrl: string, attributeNames: string[]): Promise<SQS.QueueAttributeMap> {
+        const client = await this.createSdkClient()
+        const response = await client
+            .getQueueAttributes({ QueueUrl: queueUrl, AttributeNames: attributeNames })
+            .promise()
+
+        return response.Attributes ?? {}
+    }
+
+    /**
+     * Receives up to `maxNumberOfMessages` messages, with their attributes. Received messages are hidden from
+     * other consumers for `visibilityTimeout` seconds, then return to the queue unless deleted.
+     */
+    public async receiveMessages(
+        queueUrl: string,
+        maxNumberOfMessages: number,
+        visibilityTimeout: number
+    ): Promise<SQS.Message[]> {
+        const client = await this.createSdkClient()
+        const response = await client
+            .receiveMessage({
+                QueueUrl: queueUrl,
+                MaxNumberOfMessages: maxNumberOfMessages,
+                VisibilityTimeout: visibilityTimeout,
+                // Long polling queries all servers, so the messages of small queues aren't missed
+                WaitTimeSeconds: 1,
+                AttributeNames: ['All'],
+                MessageAttributeNames: ['All'],
+            })
+            .promise()
+
+        return response.Messages ?? []
+    }
+
+    public async sendMessage(request: SQS.SendMessageRequest): Promise<SQS.SendMessageResult> {
+        const client = await this.createSdkClient()
+
+        return await client.sendMessage(request).promise()
+    }
+
+    public async purgeQueue(queueUrl: string): Promise<void> {
+        const client = await this.createSdkClient()
+
+        await client.purgeQueue({ QueueUrl: queueUrl }).promise()
+    }
+
+    private async createSdkClient(): Promise<SQS> {
+        return await ext.sdkClientBuilder.createAwsService(SQS, undefined, this.regionCode)
+    }
+}
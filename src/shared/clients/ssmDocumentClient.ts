@@ -110,6 +110,55 @@ export class DefaultSsmDocumentClient {
         return await client.updateDocumentDefaultVersion(request).promise()
     }
 
+    public async terminateSession(sessionId: string): Promise<SSM.Types.TerminateSessionResponse> {
+        const client = await this.createSdkClient()
+
+        return await client.terminateSession({ SessionId: sessionId }).promise()
+    }
+
+    public async startSession(target: string): Promise<SSM.Types.StartSessionResponse> {
+        const client = await this.createSdkClient()
+
+        return await client.startSession({ Target: target }).promise()
+    }
+
+    /**
+     * The endpoint that sessions are started with, which the Session Manager plugin also needs.
+     */
+    public async getEndpoint(): Promise<string> {
+        const client = await this.createSdkClient()
+
+        return client.endpoint.href
+    }
+
+    /**
+     * Returns the IDs of the given instances that are managed by SSM, and whose SSM Agent is online.
+     */
+    public async getOnlineManagedInstanceIds(instanceIds: string[]): Promise<Set<string>> {
+        const client = await this.createSdkClient()
+        const online = new Set<string>()
+
+        // The `InstanceIds` filter accepts at most 50 values
+        for (let i = 0; i < instanceIds.length; i += 50) {
+            const request: SSM.Types.DescribeInstanceInformationRequest = {
+                Filters: [{ Key: 'InstanceIds', Values: instanceIds.slice(i, i + 50) }],
+            }
+
+            do {
+                const response = await client.describeInstanceInformation(request).promise()
+                for (const info of response.InstanceInformationList ?? []) {
+                    if (info.InstanceId && info.PingStatus === 'Online') {
+                        online.add(info.InstanceId)
+                    }
+                }
+
+                request.NextToken = response.NextToken
+            } while (request.NextToken)
+        }
+
+        return online
+    }
+
     private async createSdkClient(): Promise<SSM> {
         return await ext.sdkClientBuilder.createAwsService(SSM, undefined, this.regionCode)
     }
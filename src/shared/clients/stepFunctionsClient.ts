@@ -52,6 +52,36 @@ export class DefaultStepFunctionsClient {
         return response
     }
 
+    public async listExecutions(
+        arn: string,
+        maxResults?: number
+    ): Promise<StepFunctions.ListExecutionsOutput> {
+        const client = await this.createSdkClient()
+
+        const request: StepFunctions.ListExecutionsInput = {
+            stateMachineArn: arn,
+            maxResults: maxResults,
+        }
+
+        return client.listExecutions(request).promise()
+    }
+
+    public async getExecutionHistory(
+        executionArn: string,
+        nextToken?: string,
+        maxResults?: number
+    ): Promise<StepFunctions.GetExecutionHistoryOutput> {
+        const client = await this.createSdkClient()
+
+        const request: StepFunctions.GetExecutionHistoryInput = {
+            executionArn: executionArn,
+            nextToken: nextToken,
+            maxResults: maxResults,
+        }
+
+        return client.getExecutionHistory(request).promise()
+    }
+
     public async createStateMachine(
         params: StepFunctions.CreateStateMachineInput
     ): Promise<StepFunctions.CreateStateMachineOutput> {
@@ -5,15 +5,25 @@
 
 import { ServiceConfigurationOptions } from 'aws-sdk/lib/service'
 import { ApiGatewayClient, DefaultApiGatewayClient } from './apiGatewayClient'
+import { AppConfigClient, DefaultAppConfigClient } from './appConfigClient'
 import { CloudFormationClient, DefaultCloudFormationClient } from './cloudFormationClient'
+import { CloudTrailClient, DefaultCloudTrailClient } from './cloudTrailClient'
+import { CloudWatchClient, DefaultCloudWatchClient } from './cloudWatchClient'
 import { CloudWatchLogsClient, DefaultCloudWatchLogsClient } from './cloudWatchLogsClient'
+import { CognitoClient, DefaultCognitoClient } from './cognitoClient'
+import { DefaultDynamoDbClient, DynamoDbClient } from './dynamoDbClient'
+import { DefaultEc2Client, Ec2Client } from './ec2Client'
 import { DefaultEcrClient, EcrClient } from './ecrClient'
 import { DefaultEcsClient, EcsClient } from './ecsClient'
 import { DefaultIamClient, IamClient } from './iamClient'
+import { DefaultKinesisClient, KinesisClient } from './kinesisClient'
 import { DefaultLambdaClient, LambdaClient } from './lambdaClient'
+import { DefaultResourceGroupsTaggingClient, ResourceGroupsTaggingClient } from './resourceGroupsTaggingClient'
 import { DefaultSchemaClient, SchemaClient } from './schemaClient'
+import { DefaultSecretsManagerClient, SecretsManagerClient } from './secretsManagerClient'
 import { DefaultStepFunctionsClient, StepFunctionsClient } from './stepFunctionsClient'
 import { DefaultStsClient, StsClient } from './stsClient'
+import { DefaultSqsClient, SqsClient } from './sqsClient'
 import { DefaultSsmDocumentClient, SsmDocumentClient } from './ssmDocumentClient'
 import { DefaultS3Client, S3Client } from './s3Client'
 import { RegionProvider } from '../regions/regionProvider'
@@ -28,14 +38,38 @@ export class DefaultToolkitClientBuilder {
         return new DefaultApiGatewayClient(regionCode)
     }
 
+    public createAppConfigClient(regionCode: string): AppConfigClient {
+        return new DefaultAppConfigClient(regionCode)
+    }
+
     public createCloudFormationClient(regionCode: string): CloudFormationClient {
         return new DefaultCloudFormationClient(regionCode)
     }
 
+    public createCloudTrailClient(regionCode: string): CloudTrailClient {
+        return new DefaultCloudTrailClient(regionCode)
+    }
+
+    public createCloudWatchClient(regionCode: string): CloudWatchClient {
+        return new DefaultCloudWatchClient(regionCode)
+    }
+
     public createCloudWatchLogsClient(regionCode: string): CloudWatchLogsClient {
         return new DefaultCloudWatchLogsClient(regionCode)
     }
 
+    public createCognitoClient(regionCode: string): CognitoClient {
+        return new DefaultCognitoClient(regionCode)
+    }
+
+    public createDynamoDbClient(regionCode: string): DynamoDbClient {
+        return new DefaultDynamoDbClient(regionCode)
+    }
+
+    public createEc2Client(regionCode: string): Ec2Client {
+        return new DefaultEc2Client(regionCode)
+    }
+
     public createEcrClient(regionCode: string): EcrClient {
         return new DefaultEcrClient(regionCode)
     }
@@ -48,14 +82,26 @@ export class DefaultToolkitClientBuilder {
         return new DefaultIamClient(regionCode)
     }
 
+    public createKinesisClient(regionCode: string): KinesisClient {
+        return new DefaultKinesisClient(regionCode)
+    }
+
     public createLambdaClient(regionCode: string): LambdaClient {
         return new DefaultLambdaClient(regionCode)
     }
 
+    public createResourceGroupsTaggingClient(regionCode: string): ResourceGroupsTaggingClient {
+        return new DefaultResourceGroupsTaggingClient(regionCode)
+    }
+
     public createSchemaClient(regionCode: string): SchemaClient {
         return new DefaultSchemaClient(regionCode)
     }
 
+    public createSecretsManagerClient(regionCode: string): SecretsManagerClient {
+        return new DefaultSecretsManagerClient(regionCode)
+    }
+
     public createStepFunctionsClient(regionCode: string): StepFunctionsClient {
         return new DefaultStepFunctionsClient(regionCode)
     }
@@ -68,6 +114,10 @@ export class DefaultToolkitClientBuilder {
         return new DefaultS3Client(this.regionProvider.getPartitionId(regionCode) ?? DEFAULT_PARTITION, regionCode)
     }
 
+    public createSqsClient(regionCode: string): SqsClient {
+        return new DefaultSqsClient(regionCode)
+    }
+
     public createSsmClient(regionCode: string): SsmDocumentClient {
         return new DefaultSsmDocumentClient(regionCode)
     }
@@ -7,6 +7,7 @@ import * as vscode from 'vscode'
 import { getLogger } from '../logger'
 import { localize } from '../utilities/vsCodeUtils'
 
+import { CfnLinter } from './cfnLint'
 import { CloudFormationTemplateRegistry } from './templateRegistry'
 import { ext } from '../extensionGlobals'
 import { getIdeProperties } from '../extensionUtilities'
@@ -49,4 +50,24 @@ export async function activate(extensionContext: vscode.ExtensionContext): Promi
     }
     // If setting it up worked, add it to subscriptions so it is cleaned up at exit
     extensionContext.subscriptions.push(ext.templateRegistry)
+
+    activateCfnLint(extensionContext)
+}
+
+/**
+ * Reports cfn-lint findings for the templates tracked by the template registry.
+ */
+function activateCfnLint(extensionContext: vscode.ExtensionContext): void {
+    const linter = new CfnLinter(document => !!ext.templateRegistry.getRegisteredItem(document.uri))
+    vscode.workspace.textDocuments.forEach(document => linter.lintDocument(document))
+
+    extensionContext.subscriptions.push(
+        linter,
+        vscode.workspace.onDidOpenTextDocument(document => linter.lintDocument(document)),
+        vscode.workspace.onDidSaveTextDocument(document => linter.lintDocument(document)),
+        vscode.workspace.onDidCloseTextDocument(document => linter.clear(document.uri)),
+        vscode.languages.registerCodeActionsProvider({ scheme: 'file', language: 'yaml' }, linter, {
+            providedCodeActionKinds: [vscode.CodeActionKind.QuickFix],
+        })
+    )
 }
@@ -0,0 +1,206 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { debounce } from 'lodash'
+import * as vscode from 'vscode'
+import { getLogger } from '../logger'
+import { ChildProcess } from '../utilities/childProcess'
+import { localize } from '../utilities/vsCodeUtils'
+
+export const CFN_LINT_DIAGNOSTIC_SOURCE = 'cfn-lint'
+const CFN_LINT_DEBOUNCE_MS = 500
+
+/**
+ * A finding in the `cfn-lint --format json` output. Line and column numbers are 1-based.
+ */
+export interface CfnLintFinding {
+    Rule: {
+        Id: string
+        Description?: string
+        ShortDescription?: string
+        Source?: string
+    }
+    Location: {
+        Start: { LineNumber: number; ColumnNumber: number }
+        End: { LineNumber: number; ColumnNumber: number }
+    }
+    Level: 'Error' | 'Warning' | 'Informational' | string
+    Message: string
+}
+
+export interface CfnLintResult {
+    /** `undefined` if cfn-lint could not be run (e.g. it is not installed). */
+    findings?: CfnLintFinding[]
+}
+
+/**
+ * Runs cfn-lint against a template on disk.
+ *
+ * cfn-lint exits with a bitmask of the finding levels (2 = error, 4 = warning, 8 = info), so a non-zero exit
+ * code alone does not mean that linting failed.
+ */
+export async function runCfnLint(templatePath: string): Promise<CfnLintResult> {
+    const stdoutChunks: string[] = []
+    const result = await new ChildProcess(false, 'cfn-lint', undefined, '--format', 'json', '--', templatePath).run(
+        text => stdoutChunks.push(text)
+    )
+    if (result.error) {
+        getLogger().debug('cfn-lint could not be run: %O', result.error)
+
+        return { findings: undefined }
+    }
+
+    const stdout = stdoutChunks.join('')
+    if (!stdout.trim()) {
+        return { findings: [] }
+    }
+
+    try {
+        return { findings: JSON.parse(stdout) as CfnLintFinding[] }
+    } catch (err) {
+        getLogger().warn('Unexpected cfn-lint output for %s: %s', templatePath, result.stderr || stdout)
+
+        return { findings: [] }
+    }
+}
+
+/**
+ * Converts cfn-lint findings to diagnostics. Rule IDs (e.g. `E3012`) become the diagnostic codes.
+ */
+export function toDiagnostics(findings: CfnLintFinding[]): vscode.Diagnostic[] {
+    return findings.map(finding => {
+        const start = finding.Location.Start
+        const end = finding.Location.End
+        const range = new vscode.Range(
+            Math.max(start.LineNumber - 1, 0),
+            Math.max(start.ColumnNumber - 1, 0),
+            Math.max(end.LineNumber - 1, 0),
+            Math.max(end.ColumnNumber - 1, 0)
+        )
+        const diagnostic = new vscode.Diagnostic(range, finding.Message, toSeverity(finding.Level))
+        diagnostic.source = CFN_LINT_DIAGNOSTIC_SOURCE
+        diagnostic.code = finding.Rule.Id
+
+        return diagnostic
+    })
+}
+
+function toSeverity(level: string): vscode.DiagnosticSeverity {
+    switch (level) {
+        case 'Error':
+            return vscode.DiagnosticSeverity.Error
+        case 'Warning':
+            return vscode.DiagnosticSeverity.Warning
+        default:
+            return vscode.DiagnosticSeverity.Information
+    }
+}
+
+/**
+ * Lints CloudFormation templates with cfn-lint when they are opened or saved, and reports the findings as
+ * diagnostics.
+ *
+ * Runs are debounced per template so that rapid saves (e.g. with auto save) do not start a lint each time.
+ * If cfn-lint is not installed, the linter turns itself off instead of failing on every save.
+ */
+export class CfnLinter implements vscode.CodeActionProvider, vscode.Disposable {
+    private readonly diagnostics: vscode.DiagnosticCollection
+    private readonly pending = new Map<string, ReturnType<typeof debounce>>()
+    /** Documentation links of the rules that have been reported, keyed by rule ID. */
+    private readonly ruleSources = new Map<string, string>()
+    private available = true
+
+    public constructor(
+        private readonly isTemplate: (document: vscode.TextDocument) => boolean,
+        private readonly lint: (templatePath: string) => Promise<CfnLintResult> = runCfnLint,
+        private readonly debounceMs: number = CFN_LINT_DEBOUNCE_MS
+    ) {
+        this.diagnostics = vscode.languages.createDiagnosticCollection(CFN_LINT_DIAGNOSTIC_SOURCE)
+    }
+
+    /**
+     * Queues a lint of the document, if it is a template on disk.
+     */
+    public lintDocument(document: vscode.TextDocument): void {
+        if (!this.available || document.uri.scheme !== 'file' || !this.isTemplate(document)) {
+            return
+        }
+
+        const key = document.uri.toString()
+        let lintLater = this.pending.get(key)
+        if (!lintLater) {
+            lintLater = debounce(() => this.lintNow(document.uri), this.debounceMs)
+            this.pending.set(key, lintLater)
+        }
+        lintLater()
+    }
+
+    public async lintNow(uri: vscode.Uri): Promise<void> {
+        this.pending.delete(uri.toString())
+        const result = await this.lint(uri.fsPath)
+        if (!result.findings) {
+            getLogger().info('cfn-lint is not available; CloudFormation templates will not be linted.')
+            this.available = false
+            this.diagnostics.clear()
+
+            return
+        }
+
+        for (const finding of result.findings) {
+            if (finding.Rule.Source) {
+                this.ruleSources.set(finding.Rule.Id, finding.Rule.Source)
+            }
+        }
+        this.diagnostics.set(uri, toDiagnostics(result.findings))
+    }
+
+    public clear(uri: vscode.Uri): void {
+        this.pending.get(uri.toString())?.cancel()
+        this.pending.delete(uri.toString())
+        this.diagnostics.delete(uri)
+    }
+
+    public getDiagnostics(uri: vscode.Uri): readonly vscode.Diagnostic[] {
+        return this.diagnostics.get(uri) ?? []
+    }
+
+    /**
+     * Offers to open the documentation of the cfn-lint rules reported at the cursor.
+     */
+    public provideCodeActions(
+        document: vscode.TextDocument,
+        range: vscode.Range,
+        context: vscode.CodeActionContext
+    ): vscode.CodeAction[] {
+        const actions: vscode.CodeAction[] = []
+        for (const diagnostic of context.diagnostics) {
+            const ruleId = diagnostic.code?.toString()
+            const source = ruleId ? this.ruleSources.get(ruleId) : undefined
+            if (diagnostic.source !== CFN_LINT_DIAGNOSTIC_SOURCE || !ruleId || !source) {
+                continue
+            }
+
+            const action = new vscode.CodeAction(
+                localize('AWS.cloudFormation.cfnLint.showRule', 'Show documentation for cfn-lint rule {0}', ruleId),
+                vscode.CodeActionKind.QuickFix
+            )
+            action.diagnostics = [diagnostic]
+            action.command = {
+                title: action.title,
+                command: 'vscode.open',
+                arguments: [vscode.Uri.parse(source)],
+            }
+            actions.push(action)
+        }
+
+        return actions
+    }
+
+    public dispose(): void {
+        this.pending.forEach(lintLater => lintLater.cancel())
+        this.pending.clear()
+        this.diagnostics.dispose()
+    }
+}
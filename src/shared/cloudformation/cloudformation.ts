@@ -28,6 +28,13 @@ export namespace CloudFormation {
         return resource?.PackageType === 'Image'
     }
 
+    /**
+     * Returns true if the resource declares a Function URL with `InvokeMode: RESPONSE_STREAM`.
+     */
+    export function isStreamingLambdaResource(resource?: LambdaResourceProperties): boolean {
+        return resource?.FunctionUrlConfig?.InvokeMode === 'RESPONSE_STREAM'
+    }
+
     export function validateZipLambdaProperties({
         Handler,
         CodeUri,
@@ -54,12 +61,23 @@ export namespace CloudFormation {
         }
     }
 
+    export type Architecture = 'x86_64' | 'arm64'
+    export const ARCHITECTURES: Architecture[] = ['x86_64', 'arm64']
+
     export interface LambdaResourceProperties {
         MemorySize?: number | Ref
         Timeout?: number | Ref
+        Architectures?: Architecture[]
         Environment?: Environment
         Events?: Events
         PackageType?: 'Image' | 'Zip'
+        FunctionUrlConfig?: FunctionUrlConfig
+        [key: string]: any
+    }
+
+    export interface FunctionUrlConfig {
+        AuthType?: 'AWS_IAM' | 'NONE'
+        InvokeMode?: 'BUFFERED' | 'RESPONSE_STREAM'
         [key: string]: any
     }
 
@@ -192,7 +210,7 @@ export namespace CloudFormation {
     export interface Parameter {
         Type: ParameterType
         AllowedPattern?: string
-        AllowValues?: string[]
+        AllowedValues?: string[]
         ConstraintDescription?: string
         Default?: any
         Description?: string
@@ -204,6 +222,10 @@ export namespace CloudFormation {
     }
 
     export interface Template {
+        AWSTemplateFormatVersion?: string
+
+        Transform?: string | string[]
+
         Parameters?: {
             [key: string]: Parameter | undefined
         }
@@ -241,6 +263,7 @@ export namespace CloudFormation {
         | 'PermissionsBoundary'
         | 'ReservedConcurrentExecutions'
         | 'EventInvokeConfig'
+        | 'Architectures'
     const functionKeysSet: Set<string> = new Set([
         'Handler',
         'Runtime',
@@ -260,6 +283,7 @@ export namespace CloudFormation {
         'PermissionsBoundary',
         'ReservedConcurrentExecutions',
         'EventInvokeConfig',
+        'Architectures',
     ])
     type FunctionGlobals = {
         [key in FunctionKeys]?: string | number | Record<string, unknown> | undefined
@@ -544,6 +568,24 @@ export namespace CloudFormation {
         return getThingForProperty(targetObj, key, template, 'number') as number | undefined
     }
 
+    /**
+     * Gets the instruction set architecture of a Lambda function from its `Architectures` property, falling back to
+     * `Globals`. Returns undefined if neither specifies a (supported) architecture, in which case Lambda uses x86_64.
+     * @param properties Properties of the function resource
+     * @param template Full template object. Required for `Globals` lookup.
+     */
+    export function getArchitectureForResource(
+        properties: LambdaResourceProperties | undefined,
+        template: Template
+    ): Architecture | undefined {
+        const architectures: unknown = properties?.Architectures ?? template.Globals?.Function?.Architectures
+        if (!Array.isArray(architectures)) {
+            return undefined
+        }
+
+        return ARCHITECTURES.find(architecture => architecture === architectures[0])
+    }
+
     /**
      * Returns the "thing" that represents the property within `targetObj` or `Globals`:
      * * string if a string is requested and (the property is a string or if the property is a ref that is not a Number and has a default value)
@@ -3,23 +3,52 @@
  * SPDX-License-Identifier: Apache-2.0
  */
 
+import { getEndpointOptions } from '../awsClientBuilder'
+import { extensionSettingsPrefix } from '../constants'
 import { ext } from '../extensionGlobals'
 import { getLogger } from '../logger'
+import { DefaultSettingsConfiguration, SettingsConfiguration } from '../settingsConfiguration'
+
+export interface AccountIdentity {
+    accountId: string
+    /**
+     * Partition of the account, taken from the caller ARN (e.g. `aws-cn` for `arn:aws-cn:iam::...`)
+     */
+    partitionId?: string
+}
 
 /**
  * Looks up the Credentials associated Account ID with its own STS Client.
  */
 export async function getAccountId(credentials: AWS.Credentials, region: string): Promise<string | undefined> {
+    return (await getAccountIdentity(credentials, region))?.accountId
+}
+
+/**
+ * Looks up the Credentials associated Account ID and Partition with its own STS Client.
+ *
+ * @param credentialsId  ID of the credentials, to use the custom STS endpoint configured for them, if any
+ */
+export async function getAccountIdentity(
+    credentials: AWS.Credentials,
+    region: string,
+    credentialsId?: string,
+    settings: SettingsConfiguration = new DefaultSettingsConfiguration(extensionSettingsPrefix)
+): Promise<AccountIdentity | undefined> {
     try {
         getLogger().verbose(`Getting AccountId from region ${region}`)
 
         const sts = ext.toolkitClientBuilder.createStsClient(region, {
             credentials: credentials,
+            ...getEndpointOptions('sts', credentialsId, settings),
         })
 
         const response = await sts.getCallerIdentity()
+        if (!response.Account) {
+            return undefined
+        }
 
-        return response.Account
+        return { accountId: response.Account, partitionId: response.Arn?.split(':')[1] || undefined }
     } catch (err) {
         getLogger().error('Error getting AccountId: %O', err as Error)
 
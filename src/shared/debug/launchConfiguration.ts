@@ -99,6 +99,13 @@ export class LaunchConfiguration {
         configs[index] = editedDebugConfig
         await this.configSource.setDebugConfigurations(configs)
     }
+
+    /**
+     * Replaces all debug configurations.
+     */
+    public async setDebugConfigurations(debugConfigs: vscode.DebugConfiguration[]): Promise<void> {
+        await this.configSource.setDebugConfigurations(debugConfigs)
+    }
 }
 
 class DefaultDebugConfigSource implements DebugConfigurationSource {
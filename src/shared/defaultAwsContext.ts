@@ -60,6 +60,11 @@ export class DefaultAwsContext implements AwsContext {
         return this.currentCredentials?.accountId
     }
 
+    // returns the partition of the configured profile's account, if known
+    public getCredentialPartition(): string | undefined {
+        return this.currentCredentials?.partitionId
+    }
+
     public getCredentialDefaultRegion(): string {
         const credId = this.currentCredentials?.credentialsId ?? ''
         if (!logged.has(credId) && !this.currentCredentials?.defaultRegion) {
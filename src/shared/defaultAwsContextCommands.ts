@@ -26,7 +26,7 @@ import { ext } from './extensionGlobals'
 import * as localizedText from './localizedText'
 import { Region } from './regions/endpoints'
 import { RegionProvider } from './regions/regionProvider'
-import { getRegionsForActiveCredentials } from './regions/regionUtilities'
+import { getDisabledRegions, getRegionsForActiveCredentials } from './regions/regionUtilities'
 import { createQuickPick, promptUser } from './ui/picker'
 import { SharedCredentialsProvider } from '../credentials/providers/sharedCredentialsProvider'
 import { getIdeProperties } from './extensionUtilities'
@@ -93,7 +93,8 @@ export class DefaultAWSContextCommands {
         const newRegion = await this.promptForFilteredRegion(
             candidateRegion => !explorerRegions.has(candidateRegion.id),
             this.TITLE_SHOW_REGION,
-            { step: 1, totalSteps: 1 }
+            { step: 1, totalSteps: 1 },
+            await this.getDisabledRegions()
         )
 
         if (newRegion) {
@@ -119,6 +120,21 @@ export class DefaultAWSContextCommands {
         this._awsContextTrees.refreshTrees()
     }
 
+    /**
+     * Gets the opt-in regions of the active partition that the account has not enabled, asking from the
+     * profile's default region if it belongs to the partition.
+     */
+    private async getDisabledRegions(): Promise<Set<string>> {
+        const partitionRegions = getRegionsForActiveCredentials(this._awsContext, this._regionProvider)
+        const defaultRegion = this._awsContext.getCredentialDefaultRegion()
+        const region = partitionRegions.find(r => r.id === defaultRegion) ?? partitionRegions[0]
+        if (!region) {
+            return new Set()
+        }
+
+        return await getDisabledRegions(ext.toolkitClientBuilder.createEc2Client(region.id))
+    }
+
     /**
      * @description Ask user for credentials information, store
      * it in new credentials file.
@@ -271,17 +287,19 @@ export class DefaultAWSContextCommands {
      * The set shown to the user is filtered from all available regions.
      *
      * @param filter Filter to apply to the available regions
+     * @param disabledRegions Regions to mark as not enabled for the account
      */
     private async promptForFilteredRegion(
         filter: (region: Region) => boolean,
         title?: string,
-        params?: { step: number; totalSteps: number }
+        params?: { step: number; totalSteps: number },
+        disabledRegions?: Set<string>
     ): Promise<string | undefined> {
         const partitionRegions = getRegionsForActiveCredentials(this._awsContext, this._regionProvider)
 
         const regionsToShow = partitionRegions.filter(filter).map(r => r.id)
 
-        return this.promptForRegion(regionsToShow, title, params)
+        return this.promptForRegion(regionsToShow, title, params, disabledRegions)
     }
 
     /**
@@ -289,11 +307,13 @@ export class DefaultAWSContextCommands {
      *
      * @param regions (Optional) The regions to show the user. If none provided, all available
      * regions are shown. Regions provided must exist in the available regions to be shown.
+     * @param disabledRegions (Optional) Regions to mark as not enabled for the account
      */
     private async promptForRegion(
         regions?: string[],
         title?: string,
-        params?: { step?: number; totalSteps?: number }
+        params?: { step?: number; totalSteps?: number },
+        disabledRegions?: Set<string>
     ): Promise<string | undefined> {
         const partitionRegions = getRegionsForActiveCredentials(this._awsContext, this._regionProvider)
 
@@ -308,6 +328,9 @@ export class DefaultAWSContextCommands {
             .map(r => ({
                 label: r.name,
                 detail: r.id,
+                description: disabledRegions?.has(r.id)
+                    ? localize('AWS.message.prompt.region.notEnabled', 'Not enabled for this account')
+                    : undefined,
             }))
 
         const picker = createQuickPick({
@@ -4,15 +4,46 @@
  */
 
 import { AwsContext } from '../awsContext'
+import { Ec2Client } from '../clients/ec2Client'
+import { getLogger } from '../logger'
 import { Region } from './endpoints'
 import { RegionProvider } from './regionProvider'
 
 export const DEFAULT_PARTITION = 'aws'
 export const DEFAULT_DNS_SUFFIX = 'amazonaws.com'
 
-export function getRegionsForActiveCredentials(awsContext: AwsContext, regionProvider: RegionProvider): Region[] {
+/**
+ * Gets the partition of the active credentials. The partition detected from the account (STS caller ARN) takes
+ * precedence over the partition of the profile's default region, which may be unset or fall back to `us-east-1`.
+ */
+export function getPartitionForActiveCredentials(awsContext: AwsContext, regionProvider: RegionProvider): string {
     const defaultRegionId = awsContext.getCredentialDefaultRegion()
-    const partitionId = regionProvider.getPartitionId(defaultRegionId) ?? DEFAULT_PARTITION
+
+    return awsContext.getCredentialPartition() ?? regionProvider.getPartitionId(defaultRegionId) ?? DEFAULT_PARTITION
+}
+
+/**
+ * Gets the regions of the active credentials' partition, from the endpoints manifest. Regions that aren't in the
+ * manifest are not listed, since the Toolkit doesn't know which services they have.
+ */
+export function getRegionsForActiveCredentials(awsContext: AwsContext, regionProvider: RegionProvider): Region[] {
+    const partitionId = getPartitionForActiveCredentials(awsContext, regionProvider)
 
     return regionProvider.getRegions(partitionId)
 }
+
+/**
+ * Gets the opt-in regions that the account has not enabled.
+ * Returns an empty set if this can't be determined, e.g. if the credentials lack `ec2:DescribeRegions`.
+ */
+export async function getDisabledRegions(ec2: Ec2Client): Promise<Set<string>> {
+    try {
+        const disabledRegions = (await ec2.describeRegions()).filter(region => region.OptInStatus === 'not-opted-in')
+
+        return new Set(disabledRegions.map(region => region.RegionName ?? ''))
+    } catch (err) {
+        getLogger().warn('Unable to determine the regions enabled for the account: %O', err as Error)
+
+        return new Set()
+    }
+}
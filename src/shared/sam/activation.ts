@@ -7,6 +7,8 @@ import * as vscode from 'vscode'
 import * as nls from 'vscode-nls'
 import { createNewSamApplication, resumeCreateNewSamApp } from '../../lambda/commands/createNewSamApp'
 import { deploySamApplication } from '../../lambda/commands/deploySamApplication'
+import { disposeSamLocalApi, startSamLocalApi, stopSamLocalApi } from '../../lambda/commands/startSamLocalApi'
+import { onDidCloseTerminal, stopSamSync, syncSamApplication } from '../../lambda/commands/syncSamApplication'
 import { SamParameterCompletionItemProvider } from '../../lambda/config/samParameterCompletionItemProvider'
 import {
     DefaultSamDeployWizardContext,
@@ -28,12 +30,18 @@ import { SettingsConfiguration } from '../settingsConfiguration'
 import { TelemetryService } from '../telemetry/telemetryService'
 import { PromiseSharer } from '../utilities/promiseUtilities'
 import { NoopWatcher } from '../watchedFiles'
+import { DefaultSamCliConfiguration } from './cli/samCliConfiguration'
 import { initialize as initializeSamCliContext } from './cli/samCliContext'
 import { detectSamCli } from './cli/samCliDetection'
+import { DefaultSamCliLocationProvider } from './cli/samCliLocator'
 import { CodelensRootRegistry } from './codelensRootRegistry'
 import { AWS_SAM_DEBUG_TYPE } from './debugger/awsSamDebugConfiguration'
 import { SamDebugConfigProvider } from './debugger/awsSamDebugger'
 import { addSamDebugConfiguration } from './debugger/commands/addSamDebugConfiguration'
+import {
+    exportSamDebugConfigurations,
+    importSamDebugConfigurations,
+} from './debugger/commands/shareSamDebugConfigurations'
 import { lazyLoadSamTemplateStrings } from '../../lambda/models/samTemplates'
 const localize = nls.loadMessageBundle()
 
@@ -99,6 +107,12 @@ async function registerServerlessCommands(ctx: ExtContext): Promise<void> {
         }),
         vscode.commands.registerCommand('aws.addSamDebugConfiguration', addSamDebugConfiguration),
         vscode.commands.registerCommand('aws.pickAddSamDebugConfiguration', codelensUtils.pickAddSamDebugConfiguration),
+        vscode.commands.registerCommand('aws.sam.exportDebugConfigurations', async () => {
+            await exportSamDebugConfigurations()
+        }),
+        vscode.commands.registerCommand('aws.sam.importDebugConfigurations', async () => {
+            await importSamDebugConfigurations()
+        }),
         vscode.commands.registerCommand('aws.deploySamApplication', async arg => {
             // `arg` is one of :
             //  - undefined
@@ -120,7 +134,53 @@ async function registerServerlessCommands(ctx: ExtContext): Promise<void> {
                     settings: ctx.settings,
                 }
             )
-        })
+        }),
+        vscode.commands.registerCommand('aws.syncSamApplication', async arg => {
+            // `arg` is the same as for `aws.deploySamApplication`
+            const samDeployWizardContext = new DefaultSamDeployWizardContext(ctx)
+            const samCliConfiguration = new DefaultSamCliConfiguration(
+                ctx.settings,
+                new DefaultSamCliLocationProvider()
+            )
+
+            await syncSamApplication(
+                {
+                    samDeployWizard: async () => new SamDeployWizard(samDeployWizardContext, arg).run(),
+                    getSamCliPath: async () => (await samCliConfiguration.getOrDetectSamCli()).path,
+                },
+                {
+                    awsContext: ctx.awsContext,
+                    state: ctx.extensionContext.globalState,
+                }
+            )
+        }),
+        vscode.commands.registerCommand('aws.stopSamSync', () => stopSamSync()),
+        vscode.window.onDidCloseTerminal(onDidCloseTerminal)
+    )
+
+    const localApiOutputChannel = vscode.window.createOutputChannel(
+        localize('AWS.samcli.startApi.outputChannel', 'SAM Local API')
+    )
+    ctx.extensionContext.subscriptions.push(
+        localApiOutputChannel,
+        vscode.commands.registerCommand('aws.startSamLocalApi', async (templateUri?: vscode.Uri) => {
+            // `templateUri` is set when started from the context menu of a template in the File Explorer
+            const samCliConfiguration = new DefaultSamCliConfiguration(
+                ctx.settings,
+                new DefaultSamCliLocationProvider()
+            )
+
+            await startSamLocalApi(
+                {
+                    awsContext: ctx.awsContext,
+                    getSamCliPath: async () => (await samCliConfiguration.getOrDetectSamCli()).path,
+                    outputChannel: localApiOutputChannel,
+                },
+                templateUri instanceof vscode.Uri ? templateUri : undefined
+            )
+        }),
+        vscode.commands.registerCommand('aws.stopSamLocalApi', async () => await stopSamLocalApi()),
+        { dispose: disposeSamLocalApi }
     )
 }
 
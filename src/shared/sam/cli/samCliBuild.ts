@@ -37,6 +37,16 @@ export interface SamCliBuildInvocationArguments {
      * - false: Lambda will be built on local machine instead of in a Docker image.
      */
     useContainer?: boolean
+    /**
+     * - true: Reuse the build artifacts of functions and layers whose source has not changed since the
+     *   previous build (`--cached`).
+     * - false: Build everything.
+     */
+    cached?: boolean
+    /**
+     * The folder where cached build artifacts are stored. Only used if `cached` is true.
+     */
+    cacheDir?: string
     /**
      * Specifies the name or id of an existing Docker network to Lambda Docker containers should connect to,
      * along with the default bridge network.
@@ -76,6 +86,7 @@ export class SamCliBuildInvocation {
     ) {
         this.args.useContainer = !!this.args.useContainer
         this.args.skipPullImage = !!this.args.skipPullImage
+        this.args.cached = !!this.args.cached
     }
 
     /** Gets the failure message, or undefined if no failure was detected.  */
@@ -104,6 +115,8 @@ export class SamCliBuildInvocation {
         pushIf(invokeArgs, !!this.args.dockerNetwork, '--docker-network', this.args.dockerNetwork!)
         pushIf(invokeArgs, !!this.args.useContainer, '--use-container')
         pushIf(invokeArgs, !!this.args.skipPullImage, '--skip-pull-image')
+        pushIf(invokeArgs, !!this.args.cached, '--cached')
+        pushIf(invokeArgs, !!this.args.cached && !!this.args.cacheDir, '--cache-dir', this.args.cacheDir!)
         pushIf(invokeArgs, !!this.args.manifestPath, '--manifest', this.args.manifestPath!)
         pushIf(
             invokeArgs,
@@ -5,11 +5,14 @@
 
 import { getLogger } from '../../logger/logger'
 import { map } from '../../utilities/collectionUtils'
-import { logAndThrowIfUnexpectedExitCode, SamCliProcessInvoker } from './samCliInvokerUtils'
+import { logAndThrowIfUnexpectedExitCode, maskParameterOverrides, SamCliProcessInvoker } from './samCliInvokerUtils'
 
 export interface SamCliDeployParameters {
     templateFile: string
     parameterOverrides: Map<string, string>
+    /** Names of `NoEcho` parameters, whose values are masked where the command is logged. */
+    noEchoParameters?: Set<string>
+    tags?: Map<string, string>
     environmentVariables: NodeJS.ProcessEnv
     region: string
     stackName: string
@@ -47,9 +50,15 @@ export async function runSamCliDeploy(
         args.push('--parameter-overrides', ...overrides)
     }
 
+    if (deployArguments.tags && deployArguments.tags.size > 0) {
+        const tags = [...map(deployArguments.tags.entries(), ([key, value]) => `${key}=${value}`)]
+        args.push('--tags', ...tags)
+    }
+
     const childProcessResult = await invoker.invoke({
         arguments: args,
         spawnOptions: { env: deployArguments.environmentVariables },
+        maskArguments: args => maskParameterOverrides(args, deployArguments.noEchoParameters ?? new Set()),
     })
 
     logAndThrowIfUnexpectedExitCode(childProcessResult, 0)
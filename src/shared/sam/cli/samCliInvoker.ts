@@ -71,6 +71,9 @@ export class DefaultSamCliProcessInvoker implements SamCliProcessInvoker {
             invokeOptions.spawnOptions,
             ...invokeOptions.arguments
         )
+        if (options?.maskArguments) {
+            this.childProcess.maskArguments(options.maskArguments)
+        }
 
         getLogger('channel').info(localize('AWS.running.command', 'Running command: {0}', `${this.childProcess}`))
         log.verbose(`running: ${this.childProcess}`)
@@ -14,11 +14,13 @@ export interface SamCliProcessInvokeOptions {
     onStderr?: ChildProcessStartArguments['onStderr']
     /** Log command invocations (default: true). */
     logging?: boolean
+    /** Masks secret values in the arguments wherever the command is logged. */
+    maskArguments?(args: string[]): string[]
 }
 
 export function makeRequiredSamCliProcessInvokeOptions(
     options?: SamCliProcessInvokeOptions
-): Required<Omit<SamCliProcessInvokeOptions, 'channelLogger' | 'onStdout' | 'onStderr' | 'logging'>> {
+): Required<Omit<SamCliProcessInvokeOptions, 'channelLogger' | 'onStdout' | 'onStderr' | 'logging' | 'maskArguments'>> {
     options = options || {}
 
     return {
@@ -32,6 +34,23 @@ export interface SamCliProcessInvoker {
     stop(): void
 }
 
+/**
+ * Masks the values of `NoEcho` parameters in the `--parameter-overrides` arguments (`Name=value`), so that
+ * they can be logged.
+ */
+export function maskParameterOverrides(args: string[], noEchoParameters: Set<string>): string[] {
+    let inOverrides = false
+
+    return args.map(arg => {
+        if (arg.startsWith('--')) {
+            inOverrides = arg === '--parameter-overrides'
+            return arg
+        }
+        const name = arg.split('=', 1)[0]
+        return inOverrides && noEchoParameters.has(name) ? `${name}=****` : arg
+    })
+}
+
 export function makeUnexpectedExitCodeError(message: string): Error {
     return new Error(`Error with child process: ${message}`)
 }
@@ -35,6 +35,8 @@ export interface SamLocalInvokeCommandArgs {
     timeout?: Timeout
     /** Allows us to name debug sessions so we can terminate them later */
     name?: string
+    /** Called with (decoded) stdout text as it arrives, e.g. for response streaming functions. */
+    onStdout?(text: string): void
 }
 
 /**
@@ -72,6 +74,9 @@ export class DefaultSamLocalInvokeCommand implements SamLocalInvokeCommand {
             return childProcess.start({
                 onStdout: (text: string): void => {
                     getLogger('debugConsole').info(text, { raw: true })
+                    if (params.onStdout) {
+                        params.onStdout(text)
+                    }
                     // If we have a timeout (as we do on debug) refresh the timeout as we receive text
                     params.timeout?.refresh()
                     this.logger.verbose('SAM: pid %d: stdout: %s', childProcess.pid(), removeAnsi(text))
@@ -210,6 +215,8 @@ export interface SamCliLocalInvokeInvocationArguments {
     extraArgs?: string[]
     /** Debug session name */
     name?: string
+    /** Receives stdout as it arrives. See {@link SamLocalInvokeCommandArgs.onStdout}. */
+    onStdout?(text: string): void
 }
 
 /**
@@ -276,6 +283,7 @@ export class SamCliLocalInvokeInvocation {
             waitForCues: !!this.args.debugPort,
             timeout,
             name: this.args.name,
+            onStdout: this.args.onStdout,
         })
     }
 
@@ -0,0 +1,53 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { map } from '../../utilities/collectionUtils'
+
+export interface SamCliSyncParameters {
+    templateFile: string
+    stackName: string
+    region: string
+    s3Bucket?: string
+    ecrRepo?: string
+    parameterOverrides: Map<string, string>
+}
+
+/**
+ * Builds the arguments for `sam sync --watch`.
+ *
+ * In watch mode SAM CLI syncs code-only changes directly through the service APIs (e.g. Lambda
+ * `UpdateFunctionCode`), and only falls back to a CloudFormation deployment when the template changes.
+ */
+export function buildSamCliSyncArguments(syncArguments: SamCliSyncParameters): string[] {
+    const args = [
+        'sync',
+        '--watch',
+        '--template-file',
+        syncArguments.templateFile,
+        '--stack-name',
+        syncArguments.stackName,
+        '--capabilities',
+        'CAPABILITY_IAM',
+        'CAPABILITY_NAMED_IAM',
+        'CAPABILITY_AUTO_EXPAND',
+        '--region',
+        syncArguments.region,
+    ]
+
+    if (syncArguments.s3Bucket) {
+        args.push('--s3-bucket', syncArguments.s3Bucket)
+    }
+
+    if (syncArguments.ecrRepo) {
+        args.push('--image-repository', syncArguments.ecrRepo)
+    }
+
+    if (syncArguments.parameterOverrides.size > 0) {
+        const overrides = [...map(syncArguments.parameterOverrides.entries(), ([key, value]) => `${key}=${value}`)]
+        args.push('--parameter-overrides', ...overrides)
+    }
+
+    return args
+}
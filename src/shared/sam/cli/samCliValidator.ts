@@ -12,6 +12,7 @@ export const MINIMUM_SAM_CLI_VERSION_INCLUSIVE = '0.47.0'
 export const MINIMUM_SAM_CLI_VERSION_INCLUSIVE_FOR_IMAGE_SUPPORT = '1.13.0'
 export const MAXIMUM_SAM_CLI_VERSION_EXCLUSIVE = '2.0.0'
 export const MINIMUM_SAM_CLI_VERSION_INCLUSIVE_FOR_GO_SUPPORT = '1.18.1'
+export const MINIMUM_SAM_CLI_VERSION_INCLUSIVE_FOR_SYNC = '1.53.0'
 
 // Errors
 export class InvalidSamCliError extends Error {
@@ -279,6 +279,19 @@ export class DefaultAwsSamDebugConfigurationValidator implements AwsSamDebugConf
                 }
             }
         }
+        if (config.sam?.envVarsFile) {
+            const fullpath = tryGetAbsolutePath(this.workspaceFolder, config.sam.envVarsFile)
+            if (!fs.existsSync(fullpath)) {
+                return {
+                    isValid: false,
+                    message: localize(
+                        'AWS.sam.debugger.missingEnvVarsFile',
+                        'Environment variables file not found: "{0}"',
+                        config.sam.envVarsFile
+                    ),
+                }
+            }
+        }
 
         return { isValid: true }
     }
@@ -80,6 +80,8 @@ export interface SamLaunchRequestArgs extends AwsSamDebuggerConfiguration {
     /** Runtime id-name passed to vscode to select a debugger/launcher. */
     runtime: Runtime
     runtimeFamily: RuntimeFamily
+    /** Instruction set architecture of the function, from `lambda.architecture` or the template. */
+    architecture?: CloudFormation.Architecture
     /** Resolved (potentinally generated) handler name. This field is mutable and should adjust to whatever handler name is currently generated*/
     handlerName: string
     workspaceFolder: vscode.WorkspaceFolder
@@ -395,6 +397,10 @@ export class SamDebugConfigProvider implements vscode.DebugConfigurationProvider
                 : undefined) ??
             getDefaultRuntime(getRuntimeFamily(editor?.document?.languageId ?? 'unknown'))
 
+        const architecture =
+            (config.lambda?.architecture as CloudFormation.Architecture | undefined) ??
+            (template ? CloudFormation.getArchitectureForResource(templateResource?.Properties, template) : undefined)
+
         const lambdaMemory =
             (template
                 ? CloudFormation.getNumberForProperty(templateResource?.Properties, 'MemorySize', template)
@@ -524,6 +530,9 @@ export class SamDebugConfigProvider implements vscode.DebugConfigurationProvider
             parameterOverrides: parameterOverrideArr,
             useIkpdb: isCloud9() || !!(config as any).useIkpdb,
         }
+        if (architecture) {
+            launchConfig.architecture = architecture
+        }
 
         //
         // Configure and launch.
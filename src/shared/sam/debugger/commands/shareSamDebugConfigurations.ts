@@ -0,0 +1,355 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as _ from 'lodash'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import { URLSearchParams } from 'url'
+import * as vscode from 'vscode'
+import { LaunchConfiguration } from '../../../debug/launchConfiguration'
+import { getLogger } from '../../../logger'
+import { recordSamExportDebugConfigurations, recordSamImportDebugConfigurations } from '../../../telemetry/telemetry'
+import { showErrorWithLogs } from '../../../utilities/messages'
+import { localize } from '../../../utilities/vsCodeUtils'
+import { Window } from '../../../vscode/window'
+import { AwsSamDebuggerConfiguration, isAwsSamDebugConfiguration } from '../awsSamDebugConfiguration'
+
+export const SAM_DEBUG_CONFIGS_FILE_VERSION = 1
+export const DEFAULT_SAM_DEBUG_CONFIGS_FILE_NAME = 'aws-sam-debug-configurations.json'
+
+/**
+ * Environment variables, headers, query string parameters, and template parameters with names matching this are
+ * assumed to hold credentials or other secrets.
+ */
+const SECRET_NAME_PATTERN = /(ACCESS_?KEY|SECRET|TOKEN|PASSW(OR)?D|CREDENTIAL|PRIVATE_?KEY|API_?KEY|AUTHORIZATION|COOKIE)/i
+
+/**
+ * Shareable file of SAM debug configurations.
+ */
+export interface SamDebugConfigsFile {
+    version: number
+    configurations: AwsSamDebuggerConfiguration[]
+}
+
+export type CollisionResolution = 'overwrite' | 'keepBoth' | 'skip'
+
+/**
+ * Returns a copy of `config` with the values of secret-looking environment variables, API headers, query string
+ * parameters, and template parameters cleared. The names are kept, so that whoever imports the config knows which
+ * values to fill in.
+ *
+ * The credentials profile is removed, since it names a profile of the exporter's machine: imported configs use
+ * the credentials selected in the Toolkit.
+ *
+ * @returns the copy, and the names of the cleared values
+ */
+export function redactSecrets(
+    config: AwsSamDebuggerConfiguration
+): { config: AwsSamDebuggerConfiguration; redacted: string[] } {
+    const copy = _.cloneDeep(config)
+    const redacted: string[] = []
+
+    function redact(values: { [key: string]: string | number } | undefined): void {
+        for (const key of Object.keys(values ?? {})) {
+            if (SECRET_NAME_PATTERN.test(key) && values![key] !== '') {
+                values![key] = ''
+                redacted.push(key)
+            }
+        }
+    }
+
+    redact(copy.lambda?.environmentVariables)
+    redact(copy.api?.headers)
+    redact(copy.sam?.template?.parameters)
+    if (copy.api?.querystring) {
+        const query = new URLSearchParams(copy.api.querystring)
+        const secrets = _.uniq([...query.keys()]).filter(
+            key => SECRET_NAME_PATTERN.test(key) && query.getAll(key).some(value => value !== '')
+        )
+        if (secrets.length > 0) {
+            secrets.forEach(key => query.set(key, ''))
+            copy.api.querystring = query.toString()
+            redacted.push(...secrets)
+        }
+    }
+    if (copy.aws) {
+        delete copy.aws.credentials
+    }
+
+    return { config: copy, redacted }
+}
+
+/**
+ * Parses the contents of a file written by {@link exportSamDebugConfigurations}.
+ *
+ * @throws if the file is not a SAM debug configurations file
+ */
+export function parseSamDebugConfigsFile(text: string): AwsSamDebuggerConfiguration[] {
+    let parsed: Partial<SamDebugConfigsFile>
+    try {
+        parsed = JSON.parse(text)
+    } catch (e) {
+        throw new Error(`Invalid JSON: ${(e as Error).message}`)
+    }
+
+    if (!parsed || !Array.isArray(parsed.configurations)) {
+        throw new Error('Missing "configurations" list')
+    }
+    if (parsed.version !== undefined && parsed.version > SAM_DEBUG_CONFIGS_FILE_VERSION) {
+        throw new Error(`Unsupported version ${parsed.version}, update the Toolkit to import this file`)
+    }
+
+    return parsed.configurations.filter(config => isAwsSamDebugConfiguration(config) && config.name)
+}
+
+/**
+ * Merges `imported` configurations into `existing` ones. New configurations are added to the top of the list.
+ *
+ * @param resolveCollision  Decides what to do with an imported configuration whose name is already in use.
+ *                          Returning undefined cancels the merge.
+ *
+ * @returns the merged configurations, or undefined if cancelled
+ */
+export async function mergeSamDebugConfigurations(
+    existing: vscode.DebugConfiguration[],
+    imported: AwsSamDebuggerConfiguration[],
+    resolveCollision: (name: string) => Promise<CollisionResolution | undefined>
+): Promise<vscode.DebugConfiguration[] | undefined> {
+    const merged = [...existing]
+    const added: vscode.DebugConfiguration[] = []
+
+    for (const config of imported) {
+        const all = [...added, ...merged]
+        const collision = all.find(other => other.name === config.name)
+        if (!collision) {
+            added.push(config)
+            continue
+        }
+
+        const resolution = await resolveCollision(config.name)
+        switch (resolution) {
+            case undefined:
+                return undefined
+            case 'skip':
+                break
+            case 'overwrite':
+                if (added.includes(collision)) {
+                    added[added.indexOf(collision)] = config
+                } else {
+                    merged[merged.indexOf(collision)] = config
+                }
+                break
+            case 'keepBoth':
+                added.push({ ...config, name: makeUniqueName(config.name, all) })
+                break
+        }
+    }
+
+    return [...added, ...merged]
+}
+
+function makeUniqueName(name: string, configs: vscode.DebugConfiguration[]): string {
+    const names = new Set(configs.map(config => config.name))
+    let count = 2
+    while (names.has(`${name} (${count})`)) {
+        count++
+    }
+
+    return `${name} (${count})`
+}
+
+/**
+ * Writes the SAM debug configurations of a workspace folder's launch.json to a file that can be shared.
+ * Values that look like secrets are removed.
+ */
+export async function exportSamDebugConfigurations(
+    launchConfig?: LaunchConfiguration,
+    window = Window.vscode()
+): Promise<void> {
+    launchConfig = launchConfig ?? (await pickLaunchConfiguration(window))
+    if (!launchConfig) {
+        recordSamExportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    const configs = launchConfig.getDebugConfigurations().filter(isAwsSamDebugConfiguration)
+    if (configs.length === 0) {
+        window.showInformationMessage(
+            localize('AWS.sam.exportDebugConfigs.noConfigs', 'No SAM debug configurations found in launch.json')
+        )
+        recordSamExportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    const saveLocation = await window.showSaveDialog({
+        defaultUri: vscode.Uri.file(
+            path.join(launchConfig.workspaceFolder?.uri.fsPath ?? '', DEFAULT_SAM_DEBUG_CONFIGS_FILE_NAME)
+        ),
+        saveLabel: localize('AWS.sam.exportDebugConfigs.saveButton', 'Export'),
+        filters: { JSON: ['json'] },
+    })
+    if (!saveLocation) {
+        recordSamExportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    const redactedNames = new Set<string>()
+    const file: SamDebugConfigsFile = {
+        version: SAM_DEBUG_CONFIGS_FILE_VERSION,
+        configurations: configs.map(config => {
+            const { config: redactedConfig, redacted } = redactSecrets(config)
+            redacted.forEach(name => redactedNames.add(name))
+            return redactedConfig
+        }),
+    }
+
+    try {
+        await fs.writeFile(saveLocation.fsPath, JSON.stringify(file, undefined, 4))
+    } catch (e) {
+        getLogger().error(`Failed to export SAM debug configurations to ${saveLocation.fsPath}: %O`, e as Error)
+        showErrorWithLogs(
+            localize('AWS.sam.exportDebugConfigs.error', 'Failed to export SAM debug configurations'),
+            window
+        )
+        recordSamExportDebugConfigurations({ result: 'Failed' })
+        return
+    }
+
+    const message = localize(
+        'AWS.sam.exportDebugConfigs.success',
+        'Exported {0} SAM debug configuration(s) to {1}.',
+        configs.length,
+        saveLocation.fsPath
+    )
+    const redactedMessage =
+        redactedNames.size > 0
+            ? localize(
+                  'AWS.sam.exportDebugConfigs.redacted',
+                  'Values that may be secrets were removed: {0}',
+                  [...redactedNames].join(', ')
+              )
+            : undefined
+    window.showInformationMessage(redactedMessage ? `${message} ${redactedMessage}` : message)
+    recordSamExportDebugConfigurations({ result: 'Succeeded' })
+}
+
+/**
+ * Adds the configurations of a file written by {@link exportSamDebugConfigurations} to a workspace folder's
+ * launch.json, prompting when a configuration with the same name already exists.
+ */
+export async function importSamDebugConfigurations(
+    launchConfig?: LaunchConfiguration,
+    window = Window.vscode()
+): Promise<void> {
+    launchConfig = launchConfig ?? (await pickLaunchConfiguration(window))
+    if (!launchConfig) {
+        recordSamImportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    const selection = await window.showOpenDialog({
+        defaultUri: launchConfig.workspaceFolder?.uri,
+        openLabel: localize('AWS.sam.importDebugConfigs.openButton', 'Import'),
+        canSelectMany: false,
+        filters: { JSON: ['json'] },
+    })
+    if (!selection || selection.length === 0) {
+        recordSamImportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    let imported: AwsSamDebuggerConfiguration[]
+    try {
+        imported = parseSamDebugConfigsFile(await fs.readFile(selection[0].fsPath, 'utf8'))
+    } catch (e) {
+        getLogger().error(`Failed to read SAM debug configurations from ${selection[0].fsPath}: %O`, e as Error)
+        window.showErrorMessage(
+            localize(
+                'AWS.sam.importDebugConfigs.invalidFile',
+                '{0} is not a SAM debug configurations file: {1}',
+                selection[0].fsPath,
+                (e as Error).message
+            )
+        )
+        recordSamImportDebugConfigurations({ result: 'Failed' })
+        return
+    }
+
+    if (imported.length === 0) {
+        window.showInformationMessage(
+            localize(
+                'AWS.sam.importDebugConfigs.noConfigs',
+                'No SAM debug configurations found in {0}',
+                selection[0].fsPath
+            )
+        )
+        recordSamImportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    const merged = await mergeSamDebugConfigurations(launchConfig.getDebugConfigurations(), imported, name =>
+        promptForCollision(name, window)
+    )
+    if (!merged) {
+        recordSamImportDebugConfigurations({ result: 'Cancelled' })
+        return
+    }
+
+    await launchConfig.setDebugConfigurations(merged)
+    window.showInformationMessage(
+        localize(
+            'AWS.sam.importDebugConfigs.success',
+            'Imported SAM debug configurations from {0}',
+            selection[0].fsPath
+        )
+    )
+    recordSamImportDebugConfigurations({ result: 'Succeeded' })
+}
+
+async function promptForCollision(name: string, window: Window): Promise<CollisionResolution | undefined> {
+    const overwrite = localize('AWS.sam.importDebugConfigs.overwrite', 'Overwrite')
+    const keepBoth = localize('AWS.sam.importDebugConfigs.keepBoth', 'Keep Both')
+    const skip = localize('AWS.sam.importDebugConfigs.skip', 'Skip')
+
+    const response = await window.showWarningMessage(
+        localize(
+            'AWS.sam.importDebugConfigs.collision',
+            'A debug configuration named "{0}" already exists in launch.json.',
+            name
+        ),
+        { modal: true },
+        overwrite,
+        keepBoth,
+        skip
+    )
+
+    switch (response) {
+        case overwrite:
+            return 'overwrite'
+        case keepBoth:
+            return 'keepBoth'
+        case skip:
+            return 'skip'
+        default:
+            return undefined
+    }
+}
+
+async function pickLaunchConfiguration(window: Window): Promise<LaunchConfiguration | undefined> {
+    const folders = vscode.workspace.workspaceFolders ?? []
+    if (folders.length === 0) {
+        window.showErrorMessage(
+            localize(
+                'AWS.sam.debugConfigs.noWorkspace',
+                'Open a workspace folder to import or export debug configurations'
+            )
+        )
+        return undefined
+    }
+
+    const folder = folders.length === 1 ? folders[0] : await vscode.window.showWorkspaceFolderPick()
+
+    return folder ? new LaunchConfiguration(folder.uri) : undefined
+}
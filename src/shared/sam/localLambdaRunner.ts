@@ -9,7 +9,12 @@ import * as request from 'request'
 import * as tcpPortUsed from 'tcp-port-used'
 import * as vscode from 'vscode'
 import * as nls from 'vscode-nls'
-import { getTemplate, getTemplateResource, isImageLambdaConfig } from '../../lambda/local/debugConfiguration'
+import {
+    getTemplate,
+    getTemplateResource,
+    isImageLambdaConfig,
+    isStreamingResponseConfig,
+} from '../../lambda/local/debugConfiguration'
 import { getFamily, RuntimeFamily } from '../../lambda/models/samLambdaRuntime'
 import { ExtContext } from '../extensions'
 import { getLogger } from '../logger'
@@ -35,6 +40,7 @@ import { DefaultSamCliLocationProvider } from './cli/samCliLocator'
 import { getSamCliContext, getSamCliVersion } from './cli/samCliContext'
 import { CloudFormation } from '../cloudformation/cloudformation'
 import { getIdeProperties } from '../extensionUtilities'
+import { StreamingResponseWriter } from './streamingResponse'
 
 const localize = nls.loadMessageBundle()
 
@@ -62,6 +68,45 @@ function getEnvironmentVariables(
     return env ? { [resourceName]: env } : {}
 }
 
+/**
+ * Merges environment variables in the `--env-vars` format, with `overrides` taking precedence per variable.
+ */
+export function mergeEnvironmentVariables(
+    base: SAMTemplateEnvironmentVariables,
+    overrides: SAMTemplateEnvironmentVariables
+): SAMTemplateEnvironmentVariables {
+    const merged = { ...base }
+    for (const resource of Object.keys(overrides)) {
+        merged[resource] = { ...base[resource], ...overrides[resource] }
+    }
+
+    return merged
+}
+
+/**
+ * Reads the `sam.envVarsFile` of a debug config.
+ *
+ * @throws Error if the file is not a JSON object of `--env-vars` overrides
+ */
+async function readEnvVarsFile(config: SamLaunchRequestArgs): Promise<SAMTemplateEnvironmentVariables> {
+    const envVarsFile = config.sam?.envVarsFile
+    if (!envVarsFile) {
+        return {}
+    }
+
+    let env: unknown
+    try {
+        env = JSON.parse(await readFile(tryGetAbsolutePath(config.workspaceFolder, envVarsFile), { encoding: 'utf-8' }))
+    } catch (e) {
+        throw Error(`Invalid JSON in env-vars file: ${envVarsFile}`)
+    }
+    if (typeof env !== 'object' || env === null || Array.isArray(env)) {
+        throw Error(`env-vars file must contain a JSON object: ${envVarsFile}`)
+    }
+
+    return env as SAMTemplateEnvironmentVariables
+}
+
 /**
  * Decides the resource name for the generated template.yaml.
  */
@@ -117,6 +162,9 @@ export async function makeInputTemplate(config: SamLaunchRequestArgs): Promise<s
             .withResourceName(resourceName)
             .withRuntime(config.runtime)
             .withCodeUri(config.codeRoot)
+        if (config.architecture) {
+            newTemplate = newTemplate.withArchitectures([config.architecture])
+        }
         if (config.lambda?.environmentVariables) {
             newTemplate = newTemplate.withEnvironment({
                 Variables: config.lambda?.environmentVariables,
@@ -138,6 +186,13 @@ export async function makeInputTemplate(config: SamLaunchRequestArgs): Promise<s
     return pathutil.normalize(inputTemplatePath)
 }
 
+/**
+ * Gets the folder where "sam build --cached" stores build artifacts for the given launch config.
+ */
+function getBuildCacheDir(config: SamLaunchRequestArgs): string {
+    return path.join(path.dirname(config.templatePath), '.aws-sam', 'cache')
+}
+
 async function buildLambdaHandler(
     timer: Timeout,
     env: NodeJS.ProcessEnv,
@@ -152,6 +207,13 @@ async function buildLambdaHandler(
 
     getLogger('channel').info(localize('AWS.output.building.sam.application', 'Building SAM application...'))
     const samBuildOutputFolder = path.join(config.baseBuildDir!, 'output')
+    // baseBuildDir is a new temporary folder for each session, so keep the cache next to the (generated) template,
+    // where "sam build" would put it by default.
+    const samBuildCacheFolder = getBuildCacheDir(config)
+    if (config.sam?.forceCleanBuild) {
+        getLogger().info('SAM build: removing cached build artifacts in %s', samBuildCacheFolder)
+        await remove(samBuildCacheFolder)
+    }
 
     const samCliArgs: SamCliBuildInvocationArguments = {
         buildDir: samBuildOutputFolder,
@@ -164,6 +226,8 @@ async function buildLambdaHandler(
         useContainer: config.sam?.containerBuild || false,
         extraArgs: config.sam?.buildArguments,
         skipPullImage: config.sam?.skipNewImageCheck,
+        cached: !!config.sam?.cachedBuild && !config.sam?.forceCleanBuild,
+        cacheDir: samBuildCacheFolder,
         parameterOverrides: config.parameterOverrides,
     }
     if (!config.noDebug) {
@@ -285,6 +349,17 @@ async function invokeLambdaHandler(
         return true
     } else {
         // 'target=code' or 'target=template'
+        const streamingWriter = isStreamingResponseConfig(config)
+            ? new StreamingResponseWriter(ext.outputChannel)
+            : undefined
+        if (streamingWriter) {
+            getLogger('channel').info(
+                localize(
+                    'AWS.output.sam.local.streaming',
+                    'Response streaming enabled: output will be shown as it arrives.'
+                )
+            )
+        }
         const localInvokeArgs: SamCliLocalInvokeInvocationArguments = {
             templateResourceName: makeResourceName(config),
             templatePath: config.templatePath,
@@ -301,6 +376,7 @@ async function invokeLambdaHandler(
             skipPullImage: true, // We already built the image, but `sam local invoke` will try to build it again
             parameterOverrides: config.parameterOverrides,
             name: config.name,
+            onStdout: streamingWriter ? text => streamingWriter.write(text) : undefined,
         }
 
         // sam local invoke ...
@@ -311,15 +387,31 @@ async function invokeLambdaHandler(
         try {
             samVersion = await getSamCliVersion(getSamCliContext())
             await command.execute(timer)
+            const streamError = streamingWriter?.findError()
+            if (streamError) {
+                throw new Error(`${streamError.errorType ?? 'Error'}: ${streamError.errorMessage}`)
+            }
             invokeResult = 'Succeeded'
         } catch (err) {
-            getLogger('channel').error(
-                localize(
-                    'AWS.error.during.sam.local',
-                    'Failed to run SAM application locally: {0}',
-                    (err as Error).message
+            if (streamingWriter && streamingWriter.received > 0) {
+                // Partial output was already written; make it clear that the stream did not complete.
+                getLogger('channel').error(
+                    localize(
+                        'AWS.error.during.sam.local.streaming',
+                        'Response stream failed after {0} characters were received: {1}',
+                        streamingWriter.received.toString(),
+                        (err as Error).message
+                    )
                 )
-            )
+            } else {
+                getLogger('channel').error(
+                    localize(
+                        'AWS.error.during.sam.local',
+                        'Failed to run SAM application locally: {0}',
+                        (err as Error).message
+                    )
+                )
+            }
 
             return false
         } finally {
@@ -354,6 +446,9 @@ export async function runLambdaFunction(
     if (dockerResponse.exitCode !==0 || dockerResponse.stdout.includes('error during connect')) {
         throw new Error('Running AWS SAM projects locally requires Docker. Is it installed and running?')
     }
+    if (config.sam?.dockerNetwork) {
+        await checkDockerNetwork(config.sam.dockerNetwork)
+    }
     // Switch over to the output channel so the user has feedback that we're getting things ready
     ctx.outputChannel.show(true)
     if (!config.noDebug) {
@@ -525,7 +620,7 @@ function requestLocalApi(ctx: ExtContext, api: APIGatewayProperties, apiPort: nu
 }
 
 export interface AttachDebuggerContext {
-    debugConfig: SamLaunchRequestArgs
+    debugConfig: vscode.DebugConfiguration & Pick<SamLaunchRequestArgs, 'runtimeFamily'>
     retryDelayMillis?: number
     onStartDebugging?: typeof vscode.debug.startDebugging
     onRecordAttachDebuggerMetric?(attachResult: boolean | undefined, attempts: number): void
@@ -671,6 +766,22 @@ async function showDebugConsole(): Promise<void> {
     }
 }
 
+/**
+ * Fails fast if the Docker network of a debug config does not exist, instead of after the (possibly long) build.
+ */
+async function checkDockerNetwork(dockerNetwork: string): Promise<void> {
+    const result = await new ChildProcess(true, 'docker', undefined, 'network', 'inspect', dockerNetwork).run()
+    if (result.exitCode !== 0) {
+        throw new Error(
+            localize(
+                'AWS.sam.debugger.dockerNetworkNotFound',
+                'Docker network "{0}" does not exist. Create it with "docker network create {0}", or fix "sam.dockerNetwork" in the debug configuration.',
+                dockerNetwork
+            )
+        )
+    }
+}
+
 /**
  * Common logic shared by `makeCsharpConfig`, `makeTypescriptConfig`, `makePythonDebugConfig`.
  *
@@ -691,7 +802,13 @@ export async function makeJsonFiles(config: SamLaunchRequestArgs): Promise<void>
     config.envFile = path.join(config.baseBuildDir!, 'env-vars.json')
 
     // env-vars.json (NB: effectively ignored for the `target=code` case).
-    const env = JSON.stringify(getEnvironmentVariables(makeResourceName(config), config.lambda?.environmentVariables))
+    // Overrides from `sam.envVarsFile` are merged in, as SAM only accepts one file.
+    const env = JSON.stringify(
+        mergeEnvironmentVariables(
+            await readEnvVarsFile(config),
+            getEnvironmentVariables(makeResourceName(config), config.lambda?.environmentVariables)
+        )
+    )
     await writeFile(config.envFile, env)
 
     // container-env-vars.json
@@ -0,0 +1,74 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { removeAnsi } from '../utilities/textUtilities'
+
+/** Number of trailing characters kept to detect an error payload at the end of a stream. */
+const MAX_TAIL_LENGTH = 8192
+
+export interface StreamingResponseError {
+    errorType?: string
+    errorMessage: string
+}
+
+/**
+ * Writes the stdout of a response streaming Lambda (`sam local invoke`) to
+ * an output channel as it arrives, instead of waiting for the process to exit.
+ *
+ * Input is expected to be already-decoded text (see `ChildProcess`), so a
+ * UTF-8 character split across chunks is never written partially.
+ */
+export class StreamingResponseWriter {
+    private receivedChars: number = 0
+    private tail: string = ''
+
+    public constructor(private readonly outputChannel: Pick<vscode.OutputChannel, 'append' | 'appendLine'>) {}
+
+    /** Number of characters received so far. */
+    public get received(): number {
+        return this.receivedChars
+    }
+
+    public write(text: string): void {
+        if (!text) {
+            return
+        }
+        this.receivedChars += text.length
+        this.tail = (this.tail + text).slice(-MAX_TAIL_LENGTH)
+        this.outputChannel.append(text)
+    }
+
+    /**
+     * Looks for a Lambda error object (`{"errorType": ..., "errorMessage": ...}`)
+     * at the end of the stream, which is how the runtime reports a handler
+     * that failed after it started writing its response.
+     */
+    public findError(): StreamingResponseError | undefined {
+        const lines = removeAnsi(this.tail)
+            .split(/\r?\n/)
+            .map(line => line.trim())
+            .filter(line => line)
+        const lastLine = lines[lines.length - 1]
+        if (!lastLine) {
+            return undefined
+        }
+        // The error object may be appended to partial output on the same line.
+        const start = lastLine.lastIndexOf('{"error')
+        if (start < 0) {
+            return undefined
+        }
+        try {
+            const obj = JSON.parse(lastLine.substring(start))
+            if (typeof obj?.errorMessage === 'string') {
+                return { errorType: obj.errorType, errorMessage: obj.errorMessage }
+            }
+        } catch (e) {
+            // Not an error object.
+        }
+
+        return undefined
+    }
+}
@@ -14,6 +14,12 @@ export interface SettingsConfiguration {
     readSetting<T>(settingKey: string): T | undefined
     readSetting<T>(settingKey: string, defaultValue: T): T
 
+    /**
+     * Gets a config value from the user settings (or its default), ignoring workspace settings. For settings that a
+     * cloned repository must not be able to set, e.g. where SDK calls are sent.
+     */
+    readUserSetting<T>(settingKey: string): T | undefined
+
     // array values are serialized as a comma-delimited string
     /**
      * Sets a config value.
@@ -46,6 +52,12 @@ export class DefaultSettingsConfiguration implements SettingsConfiguration {
         return val ?? defaultValue
     }
 
+    public readUserSetting<T>(settingKey: string): T | undefined {
+        const inspected = vscode.workspace.getConfiguration(this.extensionSettingsPrefix).inspect<T>(settingKey)
+
+        return inspected?.globalValue ?? inspected?.defaultValue
+    }
+
     public async writeSetting<T>(settingKey: string, value: T, target: vscode.ConfigurationTarget): Promise<boolean> {
         try {
             const settings = vscode.workspace.getConfiguration(this.extensionSettingsPrefix)
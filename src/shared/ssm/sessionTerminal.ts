@@ -0,0 +1,105 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { fromString } from '../../credentials/providers/credentials'
+import { SharedCredentialsProvider } from '../../credentials/providers/sharedCredentialsProvider'
+import { AwsContext } from '../awsContext'
+import { SsmDocumentClient } from '../clients/ssmDocumentClient'
+import { ext } from '../extensionGlobals'
+import { getLogger } from '../logger'
+
+export const SESSION_MANAGER_PLUGIN = 'session-manager-plugin'
+
+interface Session {
+    sessionId: string
+    ssm: SsmDocumentClient
+}
+
+/** SSM sessions keyed by the terminal they are attached to, so they can be terminated when it closes. */
+const activeSessions = new Map<vscode.Terminal, Session>()
+
+export function getActiveSessionCount(): number {
+    return activeSessions.size
+}
+
+/**
+ * Hands a started SSM session to the Session Manager plugin, running in an integrated terminal. The session
+ * is terminated when that terminal is closed.
+ *
+ * @param session The session returned by the API that started it, e.g. SSM `StartSession` or ECS `ExecuteCommand`.
+ * @param target The target of the session, which the plugin needs to resume it after a network interruption.
+ */
+export async function openSessionTerminal(
+    {
+        name,
+        session,
+        sessionId,
+        target,
+        ssm,
+    }: {
+        name: string
+        session: object
+        sessionId: string
+        target: string
+        ssm: SsmDocumentClient
+    },
+    {
+        awsContext = ext.awsContext,
+        createTerminal = vscode.window.createTerminal,
+    }: {
+        awsContext?: Pick<AwsContext, 'getCredentialProfileName'>
+        createTerminal?(options: vscode.TerminalOptions): vscode.Terminal
+    } = {}
+): Promise<vscode.Terminal> {
+    // the same arguments as the AWS CLI passes to the plugin
+    const terminal = createTerminal({
+        name,
+        shellPath: SESSION_MANAGER_PLUGIN,
+        shellArgs: [
+            JSON.stringify(session),
+            ssm.regionCode,
+            'StartSession',
+            getProfileName(awsContext),
+            JSON.stringify({ Target: target }),
+            await ssm.getEndpoint(),
+        ],
+    })
+    activeSessions.set(terminal, { sessionId, ssm })
+    terminal.show()
+
+    return terminal
+}
+
+/**
+ * Terminates the SSM session of a closed session terminal.
+ */
+export async function onDidCloseTerminal(terminal: vscode.Terminal): Promise<void> {
+    const session = activeSessions.get(terminal)
+    if (!session) {
+        return
+    }
+
+    activeSessions.delete(terminal)
+    try {
+        await session.ssm.terminateSession(session.sessionId)
+        getLogger().info('Terminated SSM session: %s', session.sessionId)
+    } catch (err) {
+        getLogger().error('Failed to terminate SSM session %s: %O', session.sessionId, err)
+    }
+}
+
+/**
+ * The plugin can only use credentials from a shared credentials profile.
+ */
+function getProfileName(awsContext: Pick<AwsContext, 'getCredentialProfileName'>): string {
+    const credentialsId = awsContext.getCredentialProfileName()
+    if (!credentialsId) {
+        return ''
+    }
+    const { credentialSource, credentialTypeId } = fromString(credentialsId)
+
+    return credentialSource === SharedCredentialsProvider.getProviderType() ? credentialTypeId : ''
+}
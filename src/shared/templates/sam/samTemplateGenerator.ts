@@ -65,6 +65,12 @@ export class SamTemplateGenerator {
         return this
     }
 
+    public withArchitectures(architectures: CloudFormation.Architecture[]): SamTemplateGenerator {
+        this.properties.Architectures = architectures
+
+        return this
+    }
+
     public withEnvironment(env: CloudFormation.Environment): SamTemplateGenerator {
         this.properties.Environment = env
 
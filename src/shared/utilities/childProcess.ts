@@ -5,12 +5,15 @@
 
 import * as child_process from 'child_process'
 import * as crossSpawn from 'cross-spawn'
+import { StringDecoder } from 'string_decoder'
 import * as logger from '../logger'
 import { waitUntil } from './timeoutUtils'
 
 export interface ChildProcessStartArguments {
     /** Controls whether stdout/stderr is collected and returned in the `ChildProcessResult`. */
     collect?: boolean
+    /** Text written to stdin, which is then closed. Use for secrets that must not be passed as arguments. */
+    input?: string
     onStdout?(text: string): void
     onStderr?(text: string): void
     onError?(error: Error): void
@@ -35,6 +38,8 @@ export interface ChildProcessResult {
  */
 export class ChildProcess {
     private readonly args: string[]
+    /** The arguments shown wherever the command is logged. */
+    private loggedArgs: string[]
     private childProcess: child_process.ChildProcess | undefined
     private processError: Error | undefined
     private processResult: ChildProcessResult | undefined
@@ -62,6 +67,15 @@ export class ChildProcess {
     ) {
         this.log = logging ? logger.getLogger() : logger.getNullLogger()
         this.args = args
+        this.loggedArgs = args
+    }
+
+    /**
+     * Masks secret values in the arguments wherever the command is logged. The process still gets the
+     * actual arguments.
+     */
+    public maskArguments(mask: (args: string[]) => string[]): void {
+        this.loggedArgs = mask(this.args)
     }
 
     /**
@@ -69,7 +83,8 @@ export class ChildProcess {
      */
     public async run(
         onStdout?: ChildProcessStartArguments['onStdout'],
-        onStderr?: ChildProcessStartArguments['onStderr']
+        onStderr?: ChildProcessStartArguments['onStderr'],
+        input?: ChildProcessStartArguments['input']
     ): Promise<ChildProcessResult> {
         return await new Promise<ChildProcessResult>(async (resolve, reject) => {
             await this.start({
@@ -87,6 +102,7 @@ export class ChildProcess {
                 },
                 onStderr: onStderr,
                 onStdout: onStdout,
+                input: input,
             }).catch(reject)
             if (!this.childProcess) {
                 reject('child process not started')
@@ -129,29 +145,53 @@ export class ChildProcess {
         this.childProcess.stderr?.on('error', err => {
             errorHandler(this, params, err)
         })
+        if (params.input !== undefined) {
+            this.childProcess.stdin?.end(params.input)
+        }
 
-        this.childProcess.stdout?.on('data', (data: { toString(): string }) => {
+        // Chunk boundaries are arbitrary, so a multi-byte UTF-8 character may be
+        // split across two 'data' events. StringDecoder buffers the partial bytes.
+        const stdoutDecoder = new StringDecoder('utf8')
+        const stderrDecoder = new StringDecoder('utf8')
+        const onStdoutText = (text: string) => {
+            if (!text) {
+                return
+            }
             if (params.collect) {
-                this.stdoutChunks.push(data.toString())
+                this.stdoutChunks.push(text)
             }
 
             if (params.onStdout) {
-                params.onStdout(data.toString())
+                params.onStdout(text)
+            }
+        }
+        const onStderrText = (text: string) => {
+            if (!text) {
+                return
             }
-        })
-
-        this.childProcess.stderr?.on('data', (data: { toString(): string }) => {
             if (params.collect) {
-                this.stderrChunks.push(data.toString())
+                this.stderrChunks.push(text)
             }
 
             if (params.onStderr) {
-                params.onStderr(data.toString())
+                params.onStderr(text)
             }
+        }
+
+        this.childProcess.stdout?.on('data', (data: Buffer | string) => {
+            onStdoutText(typeof data === 'string' ? data : stdoutDecoder.write(data))
+        })
+
+        this.childProcess.stderr?.on('data', (data: Buffer | string) => {
+            onStderrText(typeof data === 'string' ? data : stderrDecoder.write(data))
         })
 
         // Emitted when streams are closed.
         this.childProcess.once('close', (code, signal) => {
+            // Flush any incomplete character left in the decoders.
+            onStdoutText(stdoutDecoder.end())
+            onStderrText(stderrDecoder.end())
+
             const result = this.makeResult(code)
             this.processResult = result
 
@@ -252,6 +292,6 @@ export class ChildProcess {
 
     public toString(): string {
         const pid = this.pid() > 0 ? `PID ${this.pid()}:` : '(not started)'
-        return `${pid} [${this.command} ${this.args.join(' ')}]`
+        return `${pid} [${this.command} ${this.loggedArgs.join(' ')}]`
     }
 }
@@ -0,0 +1,27 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { peekMessages } from './commands/peekMessages'
+import { purgeQueue } from './commands/purgeQueue'
+import { sendMessage } from './commands/sendMessage'
+import { SqsQueueNode } from './explorer/sqsQueueNode'
+
+/**
+ * Activates SQS components.
+ */
+export async function activate(extensionContext: vscode.ExtensionContext): Promise<void> {
+    extensionContext.subscriptions.push(
+        vscode.commands.registerCommand('aws.sqs.peekMessages', async (node: SqsQueueNode) => {
+            await peekMessages(node)
+        }),
+        vscode.commands.registerCommand('aws.sqs.sendMessage', async (node: SqsQueueNode) => {
+            await sendMessage(node)
+        }),
+        vscode.commands.registerCommand('aws.sqs.purgeQueue', async (node: SqsQueueNode) => {
+            await purgeQueue(node)
+        })
+    )
+}
@@ -0,0 +1,104 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { SQS } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { getLogger } from '../../shared/logger'
+import { recordSqsPeekMessages } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { SqsQueueNode } from '../explorer/sqsQueueNode'
+
+/** Most messages a receive returns. */
+export const PEEK_MAX_MESSAGES = 10
+
+/**
+ * How long peeked messages are hidden from the queue's consumers. They aren't deleted, so they return to the
+ * queue afterwards.
+ */
+export const PEEK_VISIBILITY_TIMEOUT_SECONDS = 5
+
+/**
+ * Formats received messages for display, leaving out their receipt handles.
+ */
+export function formatMessages(messages: SQS.Message[]): string {
+    const formatted = messages.map(message => {
+        const attributes = message.MessageAttributes ?? {}
+
+        return {
+            MessageId: message.MessageId,
+            Body: message.Body,
+            Attributes: message.Attributes ?? {},
+            MessageAttributes: Object.keys(attributes).reduce(
+                (result, name) => ({
+                    ...result,
+                    [name]: {
+                        DataType: attributes[name].DataType,
+                        Value:
+                            attributes[name].StringValue ??
+                            Buffer.from(attributes[name].BinaryValue ?? '').toString('base64'),
+                    },
+                }),
+                {}
+            ),
+        }
+    })
+
+    return JSON.stringify(formatted, undefined, 4)
+}
+
+/**
+ * Shows messages on a queue without consuming them: they're received with a short visibility timeout and not
+ * deleted, so they return to the queue.
+ */
+export async function peekMessages(
+    node: SqsQueueNode,
+    window = Window.vscode(),
+    openDocument = async (content: string) =>
+        vscode.window.showTextDocument(await vscode.workspace.openTextDocument({ language: 'json', content }))
+): Promise<void> {
+    getLogger().debug('PeekMessages called for %s', node.queueUrl)
+
+    let messages: SQS.Message[]
+    try {
+        messages = await window.withProgress(
+            {
+                location: vscode.ProgressLocation.Notification,
+                title: localize('AWS.sqs.peekMessages.progressTitle', 'Peeking messages in {0}...', node.name),
+            },
+            async () => node.sqs.receiveMessages(node.queueUrl, PEEK_MAX_MESSAGES, PEEK_VISIBILITY_TIMEOUT_SECONDS)
+        )
+    } catch (e) {
+        getLogger().error(`Failed to peek messages in queue ${node.queueUrl}: %O`, e as Error)
+        showErrorWithLogs(localize('AWS.sqs.peekMessages.error', 'Failed to peek messages in {0}', node.name), window)
+        recordSqsPeekMessages({ result: 'Failed' })
+        return
+    }
+
+    if (messages.length === 0) {
+        window.showInformationMessage(
+            localize(
+                'AWS.sqs.peekMessages.noMessages',
+                'No messages are available in {0}. Messages that consumers are processing are hidden until their visibility timeout ends.',
+                node.name
+            )
+        )
+        recordSqsPeekMessages({ result: 'Succeeded' })
+        return
+    }
+
+    await openDocument(formatMessages(messages))
+    window.showInformationMessage(
+        localize(
+            'AWS.sqs.peekMessages.success',
+            'Peeked {0} messages in {1}. They were not consumed: they are hidden from consumers for {2} seconds, then return to the queue. Each peek counts as a receive, which can move messages to a dead-letter queue.',
+            messages.length,
+            node.name,
+            PEEK_VISIBILITY_TIMEOUT_SECONDS
+        )
+    )
+    recordSqsPeekMessages({ result: 'Succeeded' })
+}
@@ -0,0 +1,54 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { getLogger } from '../../shared/logger'
+import { recordSqsPurgeQueue } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { SqsQueueNode } from '../explorer/sqsQueueNode'
+
+/**
+ * Deletes all messages of a queue. The user must type the queue name to confirm, since purged messages
+ * cannot be recovered.
+ */
+export async function purgeQueue(node: SqsQueueNode, window = Window.vscode()): Promise<void> {
+    getLogger().debug('PurgeQueue called for %s', node.queueUrl)
+
+    const prompt = localize(
+        'AWS.sqs.purgeQueue.prompt',
+        'All messages of {0} will be permanently deleted. Enter {0} to confirm.',
+        node.name
+    )
+    const confirmationInput = await window.showInputBox({
+        prompt,
+        placeHolder: node.name,
+        ignoreFocusOut: true,
+        validateInput: input => (input !== node.name ? prompt : undefined),
+    })
+    if (confirmationInput !== node.name) {
+        getLogger().info('PurgeQueue cancelled')
+        recordSqsPurgeQueue({ result: 'Cancelled' })
+        return
+    }
+
+    try {
+        await node.sqs.purgeQueue(node.queueUrl)
+
+        getLogger().info(`Purged queue ${node.queueUrl}`)
+        window.showInformationMessage(
+            localize(
+                'AWS.sqs.purgeQueue.success',
+                'Purging {0}. Deleting all of its messages can take up to 60 seconds.',
+                node.name
+            )
+        )
+        recordSqsPurgeQueue({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to purge queue ${node.queueUrl}: %O`, e as Error)
+        showErrorWithLogs(localize('AWS.sqs.purgeQueue.error', 'Failed to purge {0}', node.name), window)
+        recordSqsPurgeQueue({ result: 'Failed' })
+    }
+}
@@ -0,0 +1,167 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import { SQS } from 'aws-sdk'
+import { getLogger } from '../../shared/logger'
+import { recordSqsSendMessage } from '../../shared/telemetry/telemetry'
+import { showErrorWithLogs } from '../../shared/utilities/messages'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { Window } from '../../shared/vscode/window'
+import { SqsQueueNode } from '../explorer/sqsQueueNode'
+
+/**
+ * Parses message attributes entered as a JSON object, e.g. `{"source": "toolkit", "attempt": 1}`.
+ * String values become `String` attributes, and numbers `Number` attributes.
+ *
+ * @throws Error if the text isn't an object of string and number values
+ */
+export function parseMessageAttributes(text: string): SQS.MessageBodyAttributeMap {
+    if (!text.trim()) {
+        return {}
+    }
+
+    const parsed: unknown = JSON.parse(text)
+    if (typeof parsed !== 'object' || parsed === null || Array.isArray(parsed)) {
+        throw new Error(localize('AWS.sqs.sendMessage.attributes.notObject', 'Attributes must be a JSON object'))
+    }
+
+    const values = parsed as { [name: string]: unknown }
+
+    return Object.keys(values).reduce((attributes, name) => {
+        const value = values[name]
+        if (typeof value === 'string') {
+            return { ...attributes, [name]: { DataType: 'String', StringValue: value } }
+        }
+        if (typeof value === 'number') {
+            return { ...attributes, [name]: { DataType: 'Number', StringValue: `${value}` } }
+        }
+
+        throw new Error(
+            localize('AWS.sqs.sendMessage.attributes.invalidValue', 'Attribute {0} must be a string or a number', name)
+        )
+    }, {} as SQS.MessageBodyAttributeMap)
+}
+
+function validateAttributes(text: string): string | undefined {
+    try {
+        parseMessageAttributes(text)
+    } catch (e) {
+        return (e as Error).message
+    }
+
+    return undefined
+}
+
+/**
+ * Validates a FIFO message group ID or deduplication ID: 1 to 128 characters, alphanumeric or punctuation.
+ */
+export function validateFifoId(value: string): string | undefined {
+    if (!/^[\x21-\x7e]{1,128}$/.test(value)) {
+        return localize('AWS.sqs.sendMessage.fifoId.invalid', 'Enter 1 to 128 alphanumeric or punctuation characters')
+    }
+
+    return undefined
+}
+
+/**
+ * Sends a message with the entered body and attributes to a queue. For FIFO queues, also prompts for the
+ * message group ID and, unless the queue deduplicates by content, the deduplication ID.
+ */
+export async function sendMessage(node: SqsQueueNode, window = Window.vscode()): Promise<void> {
+    getLogger().debug('SendMessage called for %s', node.queueUrl)
+
+    try {
+        // prompting reads whether a FIFO queue deduplicates by content, which can fail too
+        const request = await promptForMessage(node, window)
+        if (!request) {
+            getLogger().info('SendMessage cancelled')
+            recordSqsSendMessage({ result: 'Cancelled' })
+            return
+        }
+
+        const response = await node.sqs.sendMessage(request)
+
+        getLogger().info(`Sent message ${response.MessageId} to ${node.queueUrl}`)
+        window.showInformationMessage(
+            localize('AWS.sqs.sendMessage.success', 'Sent message {0} to {1}', response.MessageId, node.name)
+        )
+        recordSqsSendMessage({ result: 'Succeeded' })
+    } catch (e) {
+        getLogger().error(`Failed to send message to queue ${node.queueUrl}: %O`, e as Error)
+        showErrorWithLogs(localize('AWS.sqs.sendMessage.error', 'Failed to send message to {0}', node.name), window)
+        recordSqsSendMessage({ result: 'Failed' })
+    }
+}
+
+async function promptForMessage(node: SqsQueueNode, window: Window): Promise<SQS.SendMessageRequest | undefined> {
+    const body = await window.showInputBox({
+        prompt: localize('AWS.sqs.sendMessage.body.prompt', 'Enter the message body'),
+        ignoreFocusOut: true,
+        validateInput: value =>
+            value ? undefined : localize('AWS.sqs.sendMessage.body.empty', 'The message body cannot be empty'),
+    })
+    if (!body) {
+        return undefined
+    }
+
+    const attributes = await window.showInputBox({
+        prompt: localize(
+            'AWS.sqs.sendMessage.attributes.prompt',
+            'Enter message attributes as a JSON object, or leave empty to send no attributes'
+        ),
+        placeHolder: '{"source": "toolkit", "attempt": 1}',
+        ignoreFocusOut: true,
+        validateInput: validateAttributes,
+    })
+    if (attributes === undefined) {
+        return undefined
+    }
+
+    const request: SQS.SendMessageRequest = {
+        QueueUrl: node.queueUrl,
+        MessageBody: body,
+        MessageAttributes: parseMessageAttributes(attributes),
+    }
+    if (!node.fifo) {
+        return request
+    }
+
+    const messageGroupId = await window.showInputBox({
+        prompt: localize(
+            'AWS.sqs.sendMessage.messageGroupId.prompt',
+            'Enter the message group ID. Messages of a group are received in order.'
+        ),
+        ignoreFocusOut: true,
+        validateInput: validateFifoId,
+    })
+    if (!messageGroupId) {
+        return undefined
+    }
+
+    const queueAttributes = await node.sqs.getQueueAttributes(node.queueUrl, ['ContentBasedDeduplication'])
+    const contentBasedDeduplication = queueAttributes.ContentBasedDeduplication === 'true'
+    const deduplicationId = await window.showInputBox({
+        prompt: contentBasedDeduplication
+            ? localize(
+                  'AWS.sqs.sendMessage.deduplicationId.optionalPrompt',
+                  'Enter the message deduplication ID, or leave empty to deduplicate by the message body'
+              )
+            : localize(
+                  'AWS.sqs.sendMessage.deduplicationId.prompt',
+                  'Enter the message deduplication ID. Messages with the same ID are only delivered once in 5 minutes.'
+              ),
+        ignoreFocusOut: true,
+        validateInput: value => (!value && contentBasedDeduplication ? undefined : validateFifoId(value)),
+    })
+    if (deduplicationId === undefined) {
+        return undefined
+    }
+
+    return {
+        ...request,
+        MessageGroupId: messageGroupId,
+        MessageDeduplicationId: deduplicationId || undefined,
+    }
+}
@@ -0,0 +1,45 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { inspect } from 'util'
+import { SqsClient } from '../../shared/clients/sqsClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../shared/treeview/nodes/placeholderNode'
+import { makeChildrenNodes } from '../../shared/treeview/treeNodeUtilities'
+import { toArrayAsync } from '../../shared/utilities/collectionUtils'
+import { localize } from '../../shared/utilities/vsCodeUtils'
+import { SqsQueueNode } from './sqsQueueNode'
+
+/**
+ * An AWS Explorer node representing SQS.
+ *
+ * Contains queues for a specific region as child nodes.
+ */
+export class SqsNode extends AWSTreeNodeBase {
+    public constructor(private readonly sqs: SqsClient) {
+        super('SQS', vscode.TreeItemCollapsibleState.Collapsed)
+        this.contextValue = 'awsSqsNode'
+    }
+
+    public async getChildren(): Promise<AWSTreeNodeBase[]> {
+        return await makeChildrenNodes({
+            getChildNodes: async () => {
+                const queueUrls = await toArrayAsync(this.sqs.listQueues())
+
+                return queueUrls.map(queueUrl => new SqsQueueNode(this, this.sqs, queueUrl))
+            },
+            getErrorNode: async (error: Error, logID: number) => new ErrorNode(this, error, logID),
+            getNoChildrenPlaceholderNode: async () =>
+                new PlaceholderNode(this, localize('AWS.explorerNode.sqs.noQueues', '[No queues found]')),
+            sort: (item1: SqsQueueNode, item2: SqsQueueNode) => item1.name.localeCompare(item2.name),
+        })
+    }
+
+    public [inspect.custom](): string {
+        return 'SqsNode'
+    }
+}
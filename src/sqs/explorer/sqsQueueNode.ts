@@ -0,0 +1,35 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as vscode from 'vscode'
+import { SqsClient } from '../../shared/clients/sqsClient'
+import { AWSTreeNodeBase } from '../../shared/treeview/nodes/awsTreeNodeBase'
+import { SqsNode } from './sqsNode'
+
+/**
+ * An SQS queue, identified by its URL, e.g. `https://sqs.us-east-1.amazonaws.com/123456789012/my-queue`.
+ */
+export class SqsQueueNode extends AWSTreeNodeBase {
+    public readonly name: string
+
+    public constructor(
+        public readonly parent: SqsNode,
+        public readonly sqs: SqsClient,
+        public readonly queueUrl: string
+    ) {
+        super('', vscode.TreeItemCollapsibleState.None)
+        this.name = queueUrl.substring(queueUrl.lastIndexOf('/') + 1)
+        this.label = this.name
+        this.tooltip = queueUrl
+        this.contextValue = 'awsSqsQueueNode'
+    }
+
+    /**
+     * Whether the queue is a FIFO queue, whose messages need a message group ID.
+     */
+    public get fifo(): boolean {
+        return this.name.endsWith('.fifo')
+    }
+}
@@ -20,6 +20,7 @@ import {
 import { BaseTemplates } from '../../shared/templates/baseTemplates'
 import { StateMachineNode } from '../explorer/stepFunctionsNodes'
 import { StepFunctionsTemplates } from '../templates/stepFunctionsTemplates'
+import { openExecutionHistory } from '../vue/executionHistory'
 
 interface CommandMessage {
     command: string
@@ -113,6 +114,26 @@ function createMessageReceivedFunc({
                         localize('AWS.message.info.stepFunctions.executeStateMachine.started', 'Execution started')
                     )
                     outputChannel.appendLine(startExecResponse.executionArn)
+
+                    const viewHistory = localize(
+                        'AWS.message.info.stepFunctions.executeStateMachine.viewHistory',
+                        'View Execution History'
+                    )
+                    vscode.window
+                        .showInformationMessage(
+                            localize(
+                                'AWS.message.info.stepFunctions.executeStateMachine.startedNotification',
+                                'Started execution of {0}',
+                                stateMachine.details.name
+                            ),
+                            viewHistory
+                        )
+                        .then(async selection => {
+                            if (selection === viewHistory) {
+                                const executionName = startExecResponse.executionArn.split(':').pop() ?? ''
+                                await openExecutionHistory(client, startExecResponse.executionArn, executionName)
+                            }
+                        })
                 } catch (e) {
                     executeResult = 'Failed'
                     const error = e as Error
@@ -0,0 +1,371 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as nls from 'vscode-nls'
+const localize = nls.loadMessageBundle()
+
+import { StepFunctions } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { StepFunctionsClient } from '../../shared/clients/stepFunctionsClient'
+import { ext } from '../../shared/extensionGlobals'
+import { getLogger } from '../../shared/logger'
+import { recordStepfunctionsViewExecutionHistory } from '../../shared/telemetry/telemetry'
+import * as picker from '../../shared/ui/picker'
+import { createVueWebview } from '../../webviews/main'
+import { ASL_FORMATS, YAML_FORMATS } from '../constants/aslFormats'
+import { StateMachineNode } from '../explorer/stepFunctionsNodes'
+
+/** Number of history events requested per page. */
+export const EXECUTION_HISTORY_PAGE_SIZE = 100
+
+/** Number of recent executions offered when opening the history from a state machine. */
+const RECENT_EXECUTIONS_LIMIT = 50
+
+/** Detail fields that usually hold JSON, and are pretty-printed when they do. */
+const JSON_DETAIL_FIELDS = ['input', 'output', 'cause', 'parameters']
+const FAILURE_EVENT_SUFFIXES = ['Failed', 'TimedOut', 'Aborted']
+
+/** States whose events (e.g. `MapStateStarted`) are not chained to the state's entered event. */
+const CONTAINER_STATE_TYPES = ['Map', 'Parallel']
+
+export interface HistoryEventItem {
+    id: number
+    type: string
+    timestamp: string
+    failed: boolean
+    details: { [field: string]: string }
+}
+
+export interface HistoryStateGroup {
+    /** Id of the group's first event. */
+    id: number
+    /** Name of the state, or undefined for events of the execution itself. */
+    name?: string
+    /** Type of the state, e.g. `Task` for a `TaskStateEntered` event. */
+    stateType?: string
+    input?: string
+    output?: string
+    failed: boolean
+    events: HistoryEventItem[]
+}
+
+export interface InitializeRequest {
+    command: 'initialize'
+}
+
+export interface LoadMoreRequest {
+    command: 'loadMore'
+}
+
+export interface RevealStateRequest {
+    command: 'revealState'
+    data: {
+        stateName: string
+    }
+}
+
+export interface ExecutionResponse {
+    command: 'execution'
+    data: {
+        executionName: string
+        executionArn: string
+    }
+}
+
+export interface HistoryResponse {
+    command: 'history'
+    data: {
+        groups: HistoryStateGroup[]
+        eventCount: number
+        hasMore: boolean
+    }
+}
+
+export interface ErrorResponse {
+    command: 'error'
+    data: {
+        message: string
+    }
+}
+
+export type ExecutionHistoryRequest = InitializeRequest | LoadMoreRequest | RevealStateRequest
+export type ExecutionHistoryResponse = ExecutionResponse | HistoryResponse | ErrorResponse
+
+/**
+ * Groups history events by the state they belong to, in the order the states were entered.
+ * Events of the execution itself (and any that can't be attributed to a state) are grouped together.
+ */
+export function groupEventsByState(events: StepFunctions.HistoryEvent[]): HistoryStateGroup[] {
+    const executionGroup: HistoryStateGroup = { id: 0, failed: false, events: [] }
+    const groups: HistoryStateGroup[] = [executionGroup]
+    const openGroups: HistoryStateGroup[] = []
+    const eventGroups = new Map<number, HistoryStateGroup>()
+
+    for (const event of [...events].sort((a, b) => a.id - b.id)) {
+        let group: HistoryStateGroup | undefined
+        if (event.type.startsWith('Execution')) {
+            group = executionGroup
+        } else if (event.type.endsWith('StateEntered')) {
+            group = {
+                id: event.id,
+                name: event.stateEnteredEventDetails?.name,
+                stateType: event.type.substring(0, event.type.length - 'StateEntered'.length),
+                input: formatDetail('input', event.stateEnteredEventDetails?.input),
+                failed: false,
+                events: [],
+            }
+            groups.push(group)
+            openGroups.push(group)
+        } else if (event.type.endsWith('StateExited')) {
+            const name = event.stateExitedEventDetails?.name
+            const index = findLastIndex(openGroups, open => open.name === name)
+            if (index !== -1) {
+                group = openGroups.splice(index, 1)[0]
+                group.output = formatDetail('output', event.stateExitedEventDetails?.output)
+            }
+        } else {
+            const stateType = CONTAINER_STATE_TYPES.find(type => event.type.startsWith(`${type}State`))
+            if (stateType) {
+                group = openGroups[findLastIndex(openGroups, open => open.stateType === stateType)]
+            } else if (event.previousEventId !== undefined) {
+                group = eventGroups.get(event.previousEventId)
+            }
+        }
+
+        group = group ?? executionGroup
+        const item = toHistoryEventItem(event)
+        group.events.push(item)
+        group.failed = group.failed || item.failed
+        eventGroups.set(event.id, group)
+    }
+
+    return executionGroup.events.length > 0 ? groups : groups.slice(1)
+}
+
+function toHistoryEventItem(event: StepFunctions.HistoryEvent): HistoryEventItem {
+    const details: HistoryEventItem['details'] = {}
+    for (const [key, value] of Object.entries(event)) {
+        if (!key.endsWith('EventDetails') || !value) {
+            continue
+        }
+        for (const [field, fieldValue] of Object.entries(value as { [field: string]: unknown })) {
+            // Nested objects only describe truncation of the input/output
+            if (fieldValue !== undefined && typeof fieldValue !== 'object') {
+                details[field] = formatDetail(field, String(fieldValue))!
+            }
+        }
+    }
+
+    return {
+        id: event.id,
+        type: event.type,
+        timestamp: new Date(event.timestamp).toISOString(),
+        failed: FAILURE_EVENT_SUFFIXES.some(suffix => event.type.endsWith(suffix)),
+        details,
+    }
+}
+
+function formatDetail(field: string, value: string | undefined): string | undefined {
+    if (value === undefined || !JSON_DETAIL_FIELDS.includes(field)) {
+        return value
+    }
+    try {
+        return JSON.stringify(JSON.parse(value), undefined, 4)
+    } catch {
+        return value
+    }
+}
+
+function findLastIndex<T>(items: T[], predicate: (item: T) => boolean): number {
+    for (let i = items.length - 1; i >= 0; i--) {
+        if (predicate(items[i])) {
+            return i
+        }
+    }
+
+    return -1
+}
+
+/**
+ * Finds where a state is defined within an Amazon States Language document.
+ *
+ * @returns the offset of the state's name, or undefined if the state isn't defined in the document.
+ */
+export function findStateDefinitionOffset(text: string, stateName: string, isYaml: boolean): number | undefined {
+    const name = stateName.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')
+    // Only match keys whose value is an object, so references such as `"Next": "name"` are skipped
+    const pattern = isYaml
+        ? new RegExp(`^([ \\t]*(['"]?))${name}\\2[ \\t]*:[ \\t]*(\\{|#|$)`, 'm')
+        : new RegExp(`(")${name}"\\s*:\\s*\\{`)
+    const match = pattern.exec(text)
+
+    return match ? match.index + match[1].length : undefined
+}
+
+/**
+ * Reads pages of an execution's history, keeping the events read so far.
+ */
+export class ExecutionHistoryReader {
+    private readonly events: StepFunctions.HistoryEvent[] = []
+    private nextToken: string | undefined
+    private done = false
+
+    public constructor(
+        private readonly client: StepFunctionsClient,
+        private readonly executionArn: string,
+        private readonly pageSize: number = EXECUTION_HISTORY_PAGE_SIZE
+    ) {}
+
+    public async loadMore(): Promise<HistoryResponse['data']> {
+        if (!this.done) {
+            const response = await this.client.getExecutionHistory(this.executionArn, this.nextToken, this.pageSize)
+            this.events.push(...response.events)
+            this.nextToken = response.nextToken
+            this.done = !this.nextToken
+        }
+
+        return {
+            groups: groupEventsByState(this.events),
+            eventCount: this.events.length,
+            hasMore: !this.done,
+        }
+    }
+}
+
+/**
+ * Selects the state in an ASL definition open in the editor, if any.
+ */
+async function revealState(stateName: string): Promise<void> {
+    for (const editor of vscode.window.visibleTextEditors) {
+        const document = editor.document
+        if (!ASL_FORMATS.includes(document.languageId)) {
+            continue
+        }
+        const offset = findStateDefinitionOffset(
+            document.getText(),
+            stateName,
+            YAML_FORMATS.includes(document.languageId)
+        )
+        if (offset !== undefined) {
+            const range = new vscode.Range(document.positionAt(offset), document.positionAt(offset + stateName.length))
+            await vscode.window.showTextDocument(document, { viewColumn: editor.viewColumn, selection: range })
+
+            return
+        }
+    }
+
+    vscode.window.showInformationMessage(
+        localize(
+            'AWS.stepFunctions.executionHistory.stateNotFound',
+            'State "{0}" was not found in an open state machine definition',
+            stateName
+        )
+    )
+}
+
+/**
+ * Opens a view of an execution's event history, grouped by state.
+ */
+export async function openExecutionHistory(
+    client: StepFunctionsClient,
+    executionArn: string,
+    executionName: string,
+    context: vscode.ExtensionContext = ext.context
+): Promise<void> {
+    const reader = new ExecutionHistoryReader(client, executionArn)
+
+    await createVueWebview<ExecutionHistoryRequest, ExecutionHistoryResponse>({
+        id: 'stepFunctionsExecutionHistory',
+        name: localize('AWS.stepFunctions.executionHistory.title', 'Execution: {0}', executionName),
+        webviewJs: 'stepFunctionsExecutionHistoryVue.js',
+        cssFiles: ['stepFunctionsExecutionHistory.css'],
+        context,
+        persistWithoutFocus: true,
+        onDidReceiveMessageFunction: async (message, postMessageFn) => {
+            try {
+                switch (message.command) {
+                    case 'initialize':
+                        await postMessageFn({ command: 'execution', data: { executionName, executionArn } })
+                        await postMessageFn({ command: 'history', data: await reader.loadMore() })
+                        break
+                    case 'loadMore':
+                        await postMessageFn({ command: 'history', data: await reader.loadMore() })
+                        break
+                    case 'revealState':
+                        await revealState(message.data.stateName)
+                        break
+                }
+            } catch (e) {
+                const error = e as Error
+                getLogger().error(`Failed to get execution history for ${executionArn}: %O`, error)
+                await postMessageFn({ command: 'error', data: { message: error.message } })
+            }
+        },
+    })
+}
+
+/**
+ * Prompts for one of the state machine's recent executions and opens its history.
+ */
+export async function viewExecutionHistoryCommand(node: StateMachineNode): Promise<void> {
+    const client = ext.toolkitClientBuilder.createStepFunctionsClient(node.regionCode)
+
+    try {
+        const response = await client.listExecutions(node.arn, RECENT_EXECUTIONS_LIMIT)
+        if (response.executions.length === 0) {
+            vscode.window.showInformationMessage(
+                localize(
+                    'AWS.stepFunctions.executionHistory.noExecutions',
+                    'No executions found for {0}',
+                    node.functionName
+                )
+            )
+            recordStepfunctionsViewExecutionHistory({ result: 'Cancelled' })
+
+            return
+        }
+
+        const quickPick = picker.createQuickPick<vscode.QuickPickItem & { execution: StepFunctions.ExecutionListItem }>(
+            {
+                options: {
+                    ignoreFocusOut: true,
+                    title: localize(
+                        'AWS.stepFunctions.executionHistory.pick.title',
+                        'Select an execution of {0}',
+                        node.functionName
+                    ),
+                    matchOnDescription: true,
+                },
+                items: response.executions.map(execution => ({
+                    label: execution.name,
+                    description: execution.status,
+                    detail: new Date(execution.startDate).toLocaleString(),
+                    execution,
+                })),
+            }
+        )
+        const choice = picker.verifySinglePickerOutput(await picker.promptUser({ picker: quickPick }))
+        if (!choice) {
+            recordStepfunctionsViewExecutionHistory({ result: 'Cancelled' })
+
+            return
+        }
+
+        await openExecutionHistory(client, choice.execution.executionArn, choice.execution.name)
+        recordStepfunctionsViewExecutionHistory({ result: 'Succeeded' })
+    } catch (e) {
+        const error = e as Error
+        getLogger().error(`Failed to list executions for ${node.arn}: %O`, error)
+        vscode.window.showErrorMessage(
+            localize(
+                'AWS.stepFunctions.executionHistory.error',
+                'Failed to list executions for {0}: {1}',
+                node.functionName,
+                error.message
+            )
+        )
+        recordStepfunctionsViewExecutionHistory({ result: 'Failed' })
+    }
+}
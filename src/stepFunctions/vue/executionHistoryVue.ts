@@ -0,0 +1,130 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import Vue, { VNode } from 'vue'
+import { WebviewApi } from 'vscode-webview'
+import { ExecutionHistoryResponse, HistoryEventItem, HistoryStateGroup } from './executionHistory'
+
+declare const vscode: WebviewApi<null>
+
+export interface ExecutionHistoryVueData {
+    executionName: string
+    executionArn: string
+    groups: HistoryStateGroup[]
+    eventCount: number
+    hasMore: boolean
+    loading: boolean
+    errorMsg: string
+}
+
+export const Component = Vue.extend({
+    created() {
+        window.addEventListener('message', ev => {
+            const event = ev.data as ExecutionHistoryResponse
+            switch (event.command) {
+                case 'execution':
+                    this.executionName = event.data.executionName
+                    this.executionArn = event.data.executionArn
+                    break
+                case 'history':
+                    this.loading = false
+                    this.errorMsg = ''
+                    this.groups = event.data.groups
+                    this.eventCount = event.data.eventCount
+                    this.hasMore = event.data.hasMore
+                    break
+                case 'error':
+                    this.loading = false
+                    this.errorMsg = event.data.message
+                    break
+            }
+        })
+        this.loading = true
+        vscode.postMessage({ command: 'initialize' })
+    },
+    data(): ExecutionHistoryVueData {
+        return {
+            executionName: '',
+            executionArn: '',
+            groups: [],
+            eventCount: 0,
+            hasMore: false,
+            loading: false,
+            errorMsg: '',
+        }
+    },
+    methods: {
+        loadMore() {
+            this.loading = true
+            vscode.postMessage({ command: 'loadMore' })
+        },
+        revealState(group: HistoryStateGroup, event: HistoryEventItem) {
+            if (group.name && event.failed) {
+                vscode.postMessage({ command: 'revealState', data: { stateName: group.name } })
+            }
+        },
+    },
+    template: `
+    <div class="execution-history">
+        <h1>{{ executionName }}</h1>
+        <p class="execution-arn">{{ executionArn }}</p>
+        <p class="error" v-if="errorMsg">{{ errorMsg }}</p>
+        <details class="state-group" v-for="group in groups" :key="group.id" :class="{ failed: group.failed }">
+            <summary>
+                <span class="state-name">{{ group.name || 'Execution' }}</span>
+                <span class="state-type" v-if="group.stateType">{{ group.stateType }}</span>
+                <span class="state-status" v-if="group.failed">Failed</span>
+            </summary>
+            <div class="state-body">
+                <details v-if="group.input !== undefined">
+                    <summary>Input</summary>
+                    <pre>{{ group.input }}</pre>
+                </details>
+                <details v-if="group.output !== undefined">
+                    <summary>Output</summary>
+                    <pre>{{ group.output }}</pre>
+                </details>
+                <details
+                    class="history-event"
+                    v-for="event in group.events"
+                    :key="event.id"
+                    :class="{ failed: event.failed }"
+                >
+                    <summary>
+                        <span class="event-id">{{ event.id }}</span>
+                        <a
+                            v-if="group.name && event.failed"
+                            href="#"
+                            title="Show state in the open definition"
+                            v-on:click.prevent="revealState(group, event)"
+                        >{{ event.type }}</a>
+                        <span v-else>{{ event.type }}</span>
+                        <span class="event-timestamp">{{ event.timestamp }}</span>
+                    </summary>
+                    <dl class="event-details">
+                        <template v-for="(value, field) in event.details">
+                            <dt :key="field + '-name'">{{ field }}</dt>
+                            <dd :key="field + '-value'"><pre>{{ value }}</pre></dd>
+                        </template>
+                    </dl>
+                </details>
+            </div>
+        </details>
+        <p v-if="!loading && !errorMsg && groups.length === 0">No events found.</p>
+        <div class="history-pager">
+            <span>{{ eventCount }} events loaded</span>
+            <button v-if="hasMore" :disabled="loading" v-on:click="loadMore">Load more</button>
+            <span v-if="loading">Loading...</span>
+        </div>
+    </div>
+    `,
+})
+
+new Vue({
+    el: '#vueApp',
+    render: (createElement): VNode => {
+        return createElement(Component)
+    },
+})
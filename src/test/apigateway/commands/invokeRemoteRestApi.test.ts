@@ -4,8 +4,15 @@
  */
 
 import * as assert from 'assert'
-import { listValidMethods } from '../../../apigateway/commands/invokeRemoteRestApi'
+import {
+    createMessageReceivedFunc,
+    listValidMethods,
+    StageInvokeArguments,
+} from '../../../apigateway/commands/invokeRemoteRestApi'
 import { Resource } from 'aws-sdk/clients/apigateway'
+import { RestApiNode } from '../../../apigateway/explorer/apiNodes'
+import { MockApiGatewayClient } from '../../shared/clients/mockClients'
+import { MockOutputChannel } from '../../mockOutputChannel'
 
 describe('listValidMethods', function () {
     const allMethods = ['DELETE', 'GET', 'HEAD', 'OPTIONS', 'PATCH', 'POST', 'PUT']
@@ -59,3 +66,107 @@ describe('listValidMethods', function () {
         assert.deepStrictEqual(actual, [])
     })
 })
+
+describe('createMessageReceivedFunc', function () {
+    const resources = new Map<string, Resource>([
+        ['pets', { id: 'pets', path: '/pets', resourceMethods: { GET: {} } }],
+        ['pet', { id: 'pet', path: '/pets/{petId}', resourceMethods: { GET: {}, DELETE: {} } }],
+    ])
+    const invokeMessage = {
+        command: 'invokeApi',
+        body: '',
+        selectedApiResource: 'pet',
+        selectedMethod: 'GET',
+        queryString: 'verbose=true',
+        pathParameters: { petId: '42' },
+        headers: 'Authorization: allow',
+    }
+
+    let messages: any[]
+    let testInvokes: any[][]
+    let stageInvokes: StageInvokeArguments[]
+    let receive: ReturnType<typeof createMessageReceivedFunc>
+
+    beforeEach(function () {
+        messages = []
+        testInvokes = []
+        stageInvokes = []
+        const client = new MockApiGatewayClient()
+        client.testInvokeMethod = async (...args: any[]) => {
+            testInvokes.push(args)
+
+            return { status: 200, latency: 12, body: '{"id":42}', log: 'Execution log for request abc' }
+        }
+        receive = createMessageReceivedFunc({
+            api: { id: 'api' } as RestApiNode,
+            client,
+            outputChannel: new MockOutputChannel(),
+            resources,
+            postMessage: async message => messages.push(message) > 0,
+            invokeStage: async args => {
+                stageInvokes.push(args)
+
+                return { status: 403, latency: 30, body: 'Forbidden' }
+            },
+        })
+    })
+
+    it('sends the methods and path parameters of the selected resource', async function () {
+        await receive({ command: 'apiResourceSelected', value: 'pet' } as any)
+
+        assert.deepStrictEqual(messages, [
+            { command: 'setMethods', methods: ['DELETE', 'GET'], pathParameters: ['petId'] },
+        ])
+    })
+
+    it('test invokes the method and posts the response with its log', async function () {
+        await receive(invokeMessage as any)
+
+        assert.deepStrictEqual(testInvokes, [
+            ['api', 'pet', 'GET', '', '/pets/42?verbose=true', { Authorization: 'allow' }],
+        ])
+        const result = messages.find(message => message.command === 'invokeApiResult')
+        assert.deepStrictEqual(result.result, {
+            status: 200,
+            latency: 12,
+            headers: undefined,
+            body: '{"id":42}',
+            log: 'Execution log for request abc',
+        })
+        assert.strictEqual(messages[messages.length - 1].command, 'invokeApiFinished')
+    })
+
+    it('invokes the stage URL when a stage is selected', async function () {
+        await receive({ ...invokeMessage, stageName: 'prod' } as any)
+
+        assert.strictEqual(testInvokes.length, 0)
+        assert.deepStrictEqual(stageInvokes, [
+            {
+                stageName: 'prod',
+                path: '/pets/42?verbose=true',
+                method: 'GET',
+                headers: { Authorization: 'allow' },
+                body: '',
+            },
+        ])
+        assert.strictEqual(messages.find(message => message.command === 'invokeApiResult').result.status, 403)
+    })
+
+    it('posts invoke errors', async function () {
+        receive = createMessageReceivedFunc({
+            api: { id: 'api' } as RestApiNode,
+            client: new MockApiGatewayClient(),
+            outputChannel: new MockOutputChannel(),
+            resources,
+            postMessage: async message => messages.push(message) > 0,
+            invokeStage: async () => {
+                throw new Error('Missing Authentication Token')
+            },
+        })
+
+        await receive({ ...invokeMessage, stageName: 'prod' } as any)
+
+        const error = messages.find(message => message.command === 'invokeApiError')
+        assert.deepStrictEqual(error, { command: 'invokeApiError', error: 'Missing Authentication Token' })
+    })
+})
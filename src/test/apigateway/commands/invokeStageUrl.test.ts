@@ -0,0 +1,48 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { buildInvokePath, getPathParameters, parseHeaders } from '../../../apigateway/commands/invokeStageUrl'
+
+describe('getPathParameters', function () {
+    it('lists path parameters, including greedy ones', function () {
+        assert.deepStrictEqual(getPathParameters('/owners/{ownerId}/pets/{proxy+}'), ['ownerId', 'proxy'])
+    })
+
+    it('returns nothing for paths without parameters', function () {
+        assert.deepStrictEqual(getPathParameters('/pets'), [])
+    })
+})
+
+describe('buildInvokePath', function () {
+    it('fills in encoded path parameters and appends the query string', function () {
+        assert.strictEqual(
+            buildInvokePath('/owners/{ownerId}/pets', { ownerId: 'a b/c' }, 'limit=10'),
+            '/owners/a%20b%2Fc/pets?limit=10'
+        )
+    })
+
+    it('keeps the slashes of greedy path parameters', function () {
+        const path = buildInvokePath('/files/{proxy+}', { proxy: 'docs/read me.txt' })
+
+        assert.strictEqual(path, '/files/docs/read%20me.txt')
+    })
+
+    it('strips a leading question mark from the query string', function () {
+        assert.strictEqual(buildInvokePath('/pets', {}, '?type=dog'), '/pets?type=dog')
+    })
+})
+
+describe('parseHeaders', function () {
+    it('parses one header per line', function () {
+        const headers = parseHeaders('Authorization: Bearer abc:def\r\n\nX-Empty\n  Accept : application/json ')
+
+        assert.deepStrictEqual(headers, {
+            Authorization: 'Bearer abc:def',
+            'X-Empty': '',
+            Accept: 'application/json',
+        })
+    })
+})
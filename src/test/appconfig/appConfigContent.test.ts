@@ -0,0 +1,29 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { getContentText, getLanguageId, validateContent } from '../../appconfig/appConfigContent'
+
+describe('appConfigContent', function () {
+    it('highlights content by its content type', function () {
+        assert.strictEqual(getLanguageId('application/json'), 'json')
+        assert.strictEqual(getLanguageId('application/x-yaml'), 'yaml')
+        assert.strictEqual(getLanguageId('text/plain'), 'plaintext')
+        assert.strictEqual(getLanguageId(undefined), 'plaintext')
+    })
+
+    it('validates JSON and YAML content', function () {
+        assert.strictEqual(validateContent('{"enabled": true}', 'application/json'), undefined)
+        assert.ok(validateContent('{"enabled": true', 'application/json')?.includes('not valid JSON'))
+        assert.strictEqual(validateContent('enabled: true', 'application/x-yaml'), undefined)
+        assert.ok(validateContent('enabled: [true', 'application/x-yaml')?.includes('not valid YAML'))
+        assert.strictEqual(validateContent('{ anything', 'text/plain'), undefined)
+    })
+
+    it('decodes binary content as UTF-8', function () {
+        assert.strictEqual(getContentText({ Content: Buffer.from('{"ünïcödé": 1}') }), '{"ünïcödé": 1}')
+        assert.strictEqual(getContentText({}), '')
+    })
+})
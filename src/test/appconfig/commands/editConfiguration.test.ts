@@ -0,0 +1,133 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as sinon from 'sinon'
+import * as vscode from 'vscode'
+import { editConfiguration } from '../../../appconfig/commands/editConfiguration'
+import { AppConfigApplicationNode } from '../../../appconfig/explorer/appConfigApplicationNode'
+import { AppConfigNode } from '../../../appconfig/explorer/appConfigNode'
+import { AppConfigProfileNode } from '../../../appconfig/explorer/appConfigProfileNode'
+import { CreateHostedConfigurationVersionRequest } from '../../../shared/clients/appConfigClient'
+import { MockAppConfigClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('editConfiguration', function () {
+    let requests: CreateHostedConfigurationVersionRequest[]
+    let informationMessages: string[]
+    let createError: Error | undefined
+
+    beforeEach(function () {
+        requests = []
+        informationMessages = []
+        createError = undefined
+    })
+
+    function makeNode(versions: number[] = [1, 2]): AppConfigProfileNode {
+        const appConfig = new MockAppConfigClient({
+            listHostedConfigurationVersions: () => asyncGenerator(versions.map(VersionNumber => ({ VersionNumber }))),
+            getHostedConfigurationVersion: async (applicationId, profileId, versionNumber) => ({
+                VersionNumber: versionNumber,
+                ContentType: 'application/json',
+                Content: Buffer.from('{"enabled": false}'),
+            }),
+            createHostedConfigurationVersion: async request => {
+                if (createError) {
+                    throw createError
+                }
+                requests.push(request)
+                return { VersionNumber: (request.latestVersionNumber ?? 0) + 1 }
+            },
+        })
+        const application = new AppConfigApplicationNode(new AppConfigNode(appConfig), appConfig, {
+            Id: 'app',
+            Name: 'checkout',
+        })
+
+        return new AppConfigProfileNode(application, { Id: 'profile', Name: 'flags', LocationUri: 'hosted' })
+    }
+
+    /** Makes a window that chooses "Create Version" whenever it is offered. */
+    function makeWindow(confirm: boolean = true): FakeWindow {
+        const window = new FakeWindow({ message: { warningSelection: confirm ? 'Create Version' : undefined } })
+        sinon.stub(window, 'showInformationMessage').callsFake(async (message: string, ...items: string[]) => {
+            informationMessages.push(message)
+            return items.find(item => item === 'Create Version')
+        })
+
+        return window
+    }
+
+    function editTo(text: string) {
+        return async () => ({ isClosed: false, getText: () => text } as vscode.TextDocument)
+    }
+
+    it('creates a new version from the edited content', async function () {
+        const window = makeWindow()
+
+        await editConfiguration(makeNode(), window, new FakeCommands(), editTo('{"enabled": true}'))
+
+        assert.deepStrictEqual(requests, [
+            {
+                applicationId: 'app',
+                profileId: 'profile',
+                content: '{"enabled": true}',
+                contentType: 'application/json',
+                latestVersionNumber: 2,
+            },
+        ])
+        assert.strictEqual(informationMessages[informationMessages.length - 1], 'Created version 3 of flags')
+    })
+
+    it('creates the first version of a profile without versions', async function () {
+        let opened: string | undefined
+        const window = makeWindow()
+
+        await editConfiguration(makeNode([]), window, new FakeCommands(), async content => {
+            opened = content
+            return { isClosed: false, getText: () => '{"enabled": true}' } as vscode.TextDocument
+        })
+
+        assert.strictEqual(opened, '{}')
+        assert.strictEqual(requests.length, 1)
+        assert.strictEqual(requests[0].latestVersionNumber, undefined)
+    })
+
+    it('does not create a version from invalid content', async function () {
+        const window = makeWindow()
+
+        await editConfiguration(makeNode(), window, new FakeCommands(), editTo('{"enabled": tru'))
+
+        assert.deepStrictEqual(requests, [])
+        assert.ok(window.message.error?.includes('not valid JSON'))
+    })
+
+    it('does not create a version from unchanged content', async function () {
+        const window = makeWindow()
+
+        await editConfiguration(makeNode(), window, new FakeCommands(), editTo('{"enabled": false}'))
+
+        assert.deepStrictEqual(requests, [])
+    })
+
+    it('does not create a version when not confirmed', async function () {
+        const window = makeWindow(false)
+
+        await editConfiguration(makeNode(), window, new FakeCommands(), editTo('{"enabled": true}'))
+
+        assert.deepStrictEqual(requests, [])
+    })
+
+    it('tells the user when the configuration changed since it was opened', async function () {
+        createError = Object.assign(new Error('precondition failed'), { code: 'PreconditionFailedException' })
+        const window = makeWindow()
+
+        await editConfiguration(makeNode(), window, new FakeCommands(), editTo('{"enabled": true}'))
+
+        assert.ok(window.message.error?.includes('changed since it was opened'))
+    })
+})
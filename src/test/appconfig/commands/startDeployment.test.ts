@@ -0,0 +1,108 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { AppConfig } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { startDeployment } from '../../../appconfig/commands/startDeployment'
+import { AppConfigApplicationNode } from '../../../appconfig/explorer/appConfigApplicationNode'
+import { AppConfigNode } from '../../../appconfig/explorer/appConfigNode'
+import { AppConfigProfileNode } from '../../../appconfig/explorer/appConfigProfileNode'
+import { StartDeploymentRequest } from '../../../shared/clients/appConfigClient'
+import { MockAppConfigClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('startDeployment', function () {
+    let requests: StartDeploymentRequest[]
+    let deployments: AppConfig.Deployment[]
+    let labels: string[]
+
+    beforeEach(function () {
+        requests = []
+        deployments = []
+        labels = []
+    })
+
+    function makeNode(locationUri: string = 'hosted'): AppConfigProfileNode {
+        const appConfig = new MockAppConfigClient({
+            listEnvironments: () => asyncGenerator([{ Id: 'env', Name: 'prod', State: 'READY_FOR_DEPLOYMENT' }]),
+            listDeploymentStrategies: () =>
+                asyncGenerator([{ Id: 'strategy', Name: 'AppConfig.AllAtOnce', GrowthFactor: 100 }]),
+            listHostedConfigurationVersions: () =>
+                asyncGenerator([{ VersionNumber: 1 }, { VersionNumber: 3 }, { VersionNumber: 2 }]),
+            startDeployment: async request => {
+                requests.push(request)
+                return { DeploymentNumber: 7, State: 'DEPLOYING', PercentageComplete: 0 }
+            },
+            getDeployment: async () => deployments.shift() ?? { State: 'COMPLETE', PercentageComplete: 100 },
+        })
+        const application = new AppConfigApplicationNode(new AppConfigNode(appConfig), appConfig, {
+            Id: 'app',
+            Name: 'checkout',
+        })
+
+        return new AppConfigProfileNode(application, { Id: 'profile', Name: 'flags', LocationUri: locationUri })
+    }
+
+    /** Picks the first item of each picker, recording the items offered. */
+    async function pickFirst<T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) {
+        labels.push(...picker.items.map(item => item.label))
+        return [picker.items[0]]
+    }
+
+    it('deploys the chosen version after confirming, and follows its state', async function () {
+        deployments = [{ State: 'BAKING', PercentageComplete: 100 }]
+        const window = new FakeWindow({ message: { warningSelection: 'Deploy' } })
+
+        await startDeployment(makeNode(), { window, promptUserFunction: pickFirst, pollIntervalMs: 0 })
+
+        assert.deepStrictEqual(requests, [
+            {
+                applicationId: 'app',
+                environmentId: 'env',
+                profileId: 'profile',
+                strategyId: 'strategy',
+                configurationVersion: '3',
+            },
+        ])
+        assert.deepStrictEqual(labels, ['prod', 'AppConfig.AllAtOnce', '3', '2', '1'])
+        assert.ok(window.message.warning?.includes('to environment prod of checkout'))
+        assert.deepStrictEqual(
+            window.progress.reported.map(report => report.message),
+            ['DEPLOYING (0%)', 'BAKING (100%)']
+        )
+        assert.strictEqual(window.message.information, 'Deployed version 3 of flags to prod')
+    })
+
+    it('asks for the version of configurations that are not hosted', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'v42' }, message: { warningSelection: 'Deploy' } })
+
+        await startDeployment(makeNode('s3://bucket/flags.json'), {
+            window,
+            promptUserFunction: pickFirst,
+            pollIntervalMs: 0,
+        })
+
+        assert.strictEqual(requests[0].configurationVersion, 'v42')
+    })
+
+    it('does not deploy when not confirmed', async function () {
+        const window = new FakeWindow()
+
+        await startDeployment(makeNode(), { window, promptUserFunction: pickFirst, pollIntervalMs: 0 })
+
+        assert.deepStrictEqual(requests, [])
+    })
+
+    it('shows an error when the deployment rolls back', async function () {
+        deployments = [{ State: 'ROLLED_BACK' }]
+        const window = new FakeWindow({ message: { warningSelection: 'Deploy' } })
+
+        await startDeployment(makeNode(), { window, promptUserFunction: pickFirst, pollIntervalMs: 0 })
+
+        assert.strictEqual(window.message.error, 'Deployment of flags to prod was rolled back')
+    })
+})
@@ -0,0 +1,84 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { viewConfiguration } from '../../../appconfig/commands/viewConfiguration'
+import { AppConfigApplicationNode } from '../../../appconfig/explorer/appConfigApplicationNode'
+import { AppConfigNode } from '../../../appconfig/explorer/appConfigNode'
+import { AppConfigProfileNode } from '../../../appconfig/explorer/appConfigProfileNode'
+import { AppConfigClient } from '../../../shared/clients/appConfigClient'
+import { MockAppConfigClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('viewConfiguration', function () {
+    let shown: { content: string; language: string }[]
+
+    beforeEach(function () {
+        shown = []
+    })
+
+    async function showDocument(content: string, language: string): Promise<vscode.TextDocument> {
+        shown.push({ content, language })
+        return {} as vscode.TextDocument
+    }
+
+    function makeNode(appConfig: AppConfigClient, locationUri: string = 'hosted'): AppConfigProfileNode {
+        const application = new AppConfigApplicationNode(new AppConfigNode(appConfig), appConfig, {
+            Id: 'app',
+            Name: 'checkout',
+        })
+
+        return new AppConfigProfileNode(application, { Id: 'profile', Name: 'flags', LocationUri: locationUri })
+    }
+
+    it('shows the latest version highlighted as its content type', async function () {
+        const versionNumbers: number[] = []
+        const appConfig = new MockAppConfigClient({
+            listHostedConfigurationVersions: () => asyncGenerator([{ VersionNumber: 1 }, { VersionNumber: 3 }]),
+            getHostedConfigurationVersion: async (applicationId, profileId, versionNumber) => {
+                versionNumbers.push(versionNumber)
+                return { ContentType: 'application/x-yaml', Content: Buffer.from('enabled: true') }
+            },
+        })
+
+        await viewConfiguration(makeNode(appConfig), new FakeWindow(), showDocument)
+
+        assert.deepStrictEqual(versionNumbers, [3])
+        assert.deepStrictEqual(shown, [{ content: 'enabled: true', language: 'yaml' }])
+    })
+
+    it('tells the user when the profile has no versions', async function () {
+        const window = new FakeWindow()
+
+        await viewConfiguration(makeNode(new MockAppConfigClient({})), window, showDocument)
+
+        assert.deepStrictEqual(shown, [])
+        assert.strictEqual(window.message.information, 'Configuration profile flags has no versions yet')
+    })
+
+    it('does not load configurations that are not hosted', async function () {
+        const window = new FakeWindow()
+
+        await viewConfiguration(makeNode(new MockAppConfigClient({}), 's3://bucket/flags.json'), window, showDocument)
+
+        assert.deepStrictEqual(shown, [])
+        assert.ok(window.message.information?.includes('s3://bucket/flags.json'))
+    })
+
+    it('shows an error when the configuration fails to load', async function () {
+        const window = new FakeWindow()
+        const appConfig = new MockAppConfigClient({
+            listHostedConfigurationVersions: () => {
+                throw new Error('access denied')
+            },
+        })
+
+        await viewConfiguration(makeNode(appConfig), window, showDocument)
+
+        assert.ok(window.message.error?.includes('access denied'))
+    })
+})
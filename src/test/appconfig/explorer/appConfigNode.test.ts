@@ -0,0 +1,88 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { AppConfigApplicationNode } from '../../../appconfig/explorer/appConfigApplicationNode'
+import { AppConfigNode } from '../../../appconfig/explorer/appConfigNode'
+import { AppConfigProfileNode } from '../../../appconfig/explorer/appConfigProfileNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { MockAppConfigClient } from '../../shared/clients/mockClients'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('AppConfigNode', function () {
+    const appConfig = new MockAppConfigClient({
+        listApplications: () =>
+            asyncGenerator([
+                { Id: 'b', Name: 'payments' },
+                { Id: 'a', Name: 'checkout' },
+            ]),
+        listEnvironments: () =>
+            asyncGenerator([
+                { Id: 'p', Name: 'prod', State: 'DEPLOYING' },
+                { Id: 'd', Name: 'beta', State: 'READY_FOR_DEPLOYMENT' },
+            ]),
+        listConfigurationProfiles: () =>
+            asyncGenerator([
+                { Id: '2', Name: 'limits', LocationUri: 's3://bucket/limits.json' },
+                { Id: '1', Name: 'flags', LocationUri: 'hosted' },
+            ]),
+    })
+
+    function makeApplication(): AppConfigApplicationNode {
+        return new AppConfigApplicationNode(new AppConfigNode(appConfig), appConfig, { Id: 'a', Name: 'checkout' })
+    }
+
+    it('lists applications sorted by name', async function () {
+        const children = await new AppConfigNode(appConfig).getChildren()
+
+        assert.deepStrictEqual(
+            children.map(node => node.label),
+            ['checkout', 'payments']
+        )
+    })
+
+    it('shows a placeholder when there are no applications', async function () {
+        const children = await new AppConfigNode(new MockAppConfigClient({})).getChildren()
+
+        assert.strictEqual(children.length, 1)
+        assert.ok(children[0] instanceof PlaceholderNode)
+    })
+
+    it('lists environments with their state', async function () {
+        const [environments] = await makeApplication().getChildren()
+        const children = await environments.getChildren()
+
+        assert.deepStrictEqual(
+            children.map(node => [node.label, node.description]),
+            [
+                ['beta', 'READY_FOR_DEPLOYMENT'],
+                ['prod', 'DEPLOYING'],
+            ]
+        )
+    })
+
+    it('marks hosted freeform profiles as editable', async function () {
+        const [, profiles] = await makeApplication().getChildren()
+        const [flags, limits] = (await profiles.getChildren()) as AppConfigProfileNode[]
+
+        assert.strictEqual(flags.label, 'flags')
+        assert.strictEqual(flags.description, 'hosted')
+        assert.strictEqual(flags.contextValue, 'awsAppConfigFreeformProfileNode')
+        assert.strictEqual(limits.description, 's3')
+        assert.strictEqual(limits.contextValue, 'awsAppConfigProfileNode')
+    })
+
+    it('does not mark feature flag profiles as editable', function () {
+        const node = new AppConfigProfileNode(makeApplication(), {
+            Id: '3',
+            Name: 'features',
+            LocationUri: 'hosted',
+            Type: 'AWS.AppConfig.FeatureFlags',
+        } as any)
+
+        assert.strictEqual(node.editable, false)
+        assert.strictEqual(node.contextValue, 'awsAppConfigProfileNode')
+    })
+})
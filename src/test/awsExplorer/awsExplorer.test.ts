@@ -4,11 +4,20 @@
  */
 
 import * as assert from 'assert'
+import * as fs from 'fs-extra'
 import * as sinon from 'sinon'
 import { AwsExplorer } from '../../awsexplorer/awsExplorer'
+import { CachedNode } from '../../awsexplorer/explorerCache'
+import { CloudWatchLogsNode } from '../../cloudWatchLogs/explorer/cloudWatchLogsNode'
 import { RegionNode } from '../../awsexplorer/regionNode'
+import { LambdaNode } from '../../lambda/explorer/lambdaNodes'
+import { AWSCommandTreeNode } from '../../shared/treeview/nodes/awsCommandTreeNode'
+import { ErrorNode } from '../../shared/treeview/nodes/errorNode'
 import { ToolkitClientBuilder } from '../../shared/clients/toolkitClientBuilder'
 import { ext } from '../../shared/extensionGlobals'
+import { makeTemporaryToolkitFolder } from '../../shared/filesystemUtilities'
+import { asyncGenerator } from '../utilities/collectionUtils'
+import { MockCloudWatchLogsClient } from '../shared/clients/mockClients'
 import { FakeExtensionContext } from '../fakeExtensionContext'
 import {
     DEFAULT_TEST_REGION_CODE,
@@ -16,29 +25,51 @@ import {
     FakeRegionProvider,
     makeFakeAwsContextWithPlaceholderIds,
 } from '../utilities/fakeAwsContext'
+import { assertNodeListOnlyContainsPlaceholderNode } from '../utilities/explorerNodeAssertions'
 
 describe('AwsExplorer', function () {
     let sandbox: sinon.SinonSandbox
+    let tempFolder: string
 
-    beforeEach(function () {
+    /** The explorer caches the nodes it loads in the global storage folder. */
+    function makeFakeContext(): FakeExtensionContext {
+        const fakeContext = new FakeExtensionContext()
+        fakeContext.globalStoragePath = tempFolder
+
+        return fakeContext
+    }
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
         sandbox = sinon.createSandbox()
         // contingency for current Node impl: requires a client built from ext.toolkitClientBuilder.
         const clientBuilder = {
             createS3Client: sandbox.stub().returns({}),
+            createDynamoDbClient: sandbox.stub().returns({}),
+            createEc2Client: sandbox.stub().returns({}),
+            createSsmClient: sandbox.stub().returns({}),
             createEcrClient: sandbox.stub().returns({}),
+            createEcsClient: sandbox.stub().returns({}),
+            createSecretsManagerClient: sandbox.stub().returns({}),
+            createKinesisClient: sandbox.stub().returns({}),
+            createSqsClient: sandbox.stub().returns({}),
+            createCognitoClient: sandbox.stub().returns({}),
+            createAppConfigClient: sandbox.stub().returns({}),
+            createLambdaClient: sandbox.stub().returns({}),
         }
         ext.toolkitClientBuilder = (clientBuilder as any) as ToolkitClientBuilder
     })
 
-    afterEach(function () {
+    afterEach(async function () {
         sandbox.restore()
+        await fs.remove(tempFolder)
     })
 
     it('displays region nodes with user-friendly region names', async function () {
         const awsContext = makeFakeAwsContextWithPlaceholderIds(({} as any) as AWS.Credentials)
         const regionProvider = new FakeRegionProvider()
 
-        const fakeContext = new FakeExtensionContext()
+        const fakeContext = makeFakeContext()
         const awsExplorer = new AwsExplorer(fakeContext, awsContext, regionProvider)
 
         const treeNodes = await awsExplorer.getChildren()
@@ -58,7 +89,7 @@ describe('AwsExplorer', function () {
         const awsContext = makeFakeAwsContextWithPlaceholderIds(({} as any) as AWS.Credentials)
         const regionProvider = new FakeRegionProvider()
 
-        const fakeContext = new FakeExtensionContext()
+        const fakeContext = makeFakeContext()
         const awsExplorer = new AwsExplorer(fakeContext, awsContext, regionProvider)
 
         const refreshStub = sandbox.stub(awsExplorer, 'refresh')
@@ -67,4 +98,129 @@ describe('AwsExplorer', function () {
 
         assert.ok(refreshStub.calledOnce, 'expected AWS Explorer to refresh itself')
     })
+
+    describe('filter', function () {
+        let awsExplorer: AwsExplorer
+        let lambdaNode: LambdaNode
+
+        beforeEach(async function () {
+            const awsContext = makeFakeAwsContextWithPlaceholderIds(({} as any) as AWS.Credentials)
+            awsExplorer = new AwsExplorer(makeFakeContext(), awsContext, new FakeRegionProvider())
+
+            const [regionNode] = await awsExplorer.getChildren()
+            const serviceNodes = await awsExplorer.getChildren(regionNode)
+            lambdaNode = serviceNodes.find(node => node instanceof LambdaNode) as LambdaNode
+            sandbox.stub(lambdaNode, 'getChildren').resolves([
+                new AWSCommandTreeNode(lambdaNode, 'my-Function', 'aws.invokeLambda'),
+                new AWSCommandTreeNode(lambdaNode, 'other', 'aws.invokeLambda'),
+            ])
+        })
+
+        it('filters the resources of services by name, ignoring case', async function () {
+            const refreshStub = sandbox.stub(awsExplorer, 'refresh')
+            awsExplorer.setFilter(' FUNCTION ')
+
+            assert.strictEqual(awsExplorer.filter, 'FUNCTION')
+            assert.ok(refreshStub.calledOnce, 'expected AWS Explorer to refresh itself')
+            const childNodes = await awsExplorer.getChildren(lambdaNode)
+            assert.deepStrictEqual(
+                childNodes.map(node => node.label),
+                ['my-Function']
+            )
+        })
+
+        it('matches log groups by any part of their name, ignoring case', async function () {
+            const logGroupNames = ['/aws/lambda/function1', '/ecs/service']
+            const client = new MockCloudWatchLogsClient('', () =>
+                asyncGenerator(logGroupNames.map(logGroupName => ({ logGroupName })))
+            )
+            ;(ext.toolkitClientBuilder as any).createCloudWatchLogsClient = sandbox.stub().returns(client)
+            const [regionNode] = await awsExplorer.getChildren()
+            const serviceNodes = await awsExplorer.getChildren(regionNode)
+            const logsNode = serviceNodes.find(node => node instanceof CloudWatchLogsNode)!
+
+            for (const filter of ['/lambda', '/AWS/LAMBDA']) {
+                awsExplorer.setFilter(filter)
+                const childNodes = await awsExplorer.getChildren(logsNode)
+                assert.deepStrictEqual(
+                    childNodes.map(node => node.label),
+                    ['/aws/lambda/function1'],
+                    filter
+                )
+            }
+        })
+
+        it('shows a placeholder when no resources match', async function () {
+            awsExplorer.setFilter('nothing')
+
+            const childNodes = await awsExplorer.getChildren(lambdaNode)
+            assertNodeListOnlyContainsPlaceholderNode(childNodes)
+        })
+
+        it('shows all resources when the filter is cleared', async function () {
+            awsExplorer.setFilter('function')
+            awsExplorer.setFilter('')
+
+            assert.strictEqual(awsExplorer.filter, undefined)
+            assert.strictEqual((await awsExplorer.getChildren(lambdaNode)).length, 2)
+        })
+    })
+
+    describe('offline', function () {
+        let awsExplorer: AwsExplorer
+        let lambdaNode: LambdaNode
+        let getChildrenStub: sinon.SinonStub
+
+        function makeError(code: string): Error {
+            return Object.assign(new Error(code), { code })
+        }
+
+        beforeEach(async function () {
+            const awsContext = makeFakeAwsContextWithPlaceholderIds(({} as any) as AWS.Credentials)
+            awsExplorer = new AwsExplorer(makeFakeContext(), awsContext, new FakeRegionProvider())
+
+            const [regionNode] = await awsExplorer.getChildren()
+            const serviceNodes = await awsExplorer.getChildren(regionNode)
+            lambdaNode = serviceNodes.find(node => node instanceof LambdaNode) as LambdaNode
+            getChildrenStub = sandbox.stub(lambdaNode, 'getChildren')
+            getChildrenStub.resolves([new AWSCommandTreeNode(lambdaNode, 'my-function', 'aws.invokeLambda')])
+            await awsExplorer.getChildren(lambdaNode)
+        })
+
+        it('shows the last loaded nodes after a network error', async function () {
+            getChildrenStub.resolves([new ErrorNode(lambdaNode, makeError('NetworkingError'))])
+
+            const childNodes = await awsExplorer.getChildren(lambdaNode)
+            assert.strictEqual(childNodes.length, 1)
+            assert.ok(childNodes[0] instanceof CachedNode)
+            assert.strictEqual(childNodes[0].label, 'my-function')
+            assert.strictEqual(childNodes[0].description, '(offline)')
+        })
+
+        it('shows the last loaded nodes after a thrown network error', async function () {
+            getChildrenStub.rejects(makeError('ENOTFOUND'))
+
+            const childNodes = await awsExplorer.getChildren(lambdaNode)
+            assert.ok(childNodes[0] instanceof CachedNode)
+        })
+
+        it('shows the error instead of the last loaded nodes after an API error', async function () {
+            getChildrenStub.resolves([new ErrorNode(lambdaNode, makeError('AccessDeniedException'))])
+            assert.ok((await awsExplorer.getChildren(lambdaNode))[0] instanceof ErrorNode)
+
+            // the API error also discards the cached nodes
+            getChildrenStub.resolves([new ErrorNode(lambdaNode, makeError('NetworkingError'))])
+            assert.ok((await awsExplorer.getChildren(lambdaNode))[0] instanceof ErrorNode)
+        })
+
+        it('loads live nodes again once the network is back', async function () {
+            getChildrenStub.resolves([new ErrorNode(lambdaNode, makeError('NetworkingError'))])
+            await awsExplorer.getChildren(lambdaNode)
+            getChildrenStub.resolves([new AWSCommandTreeNode(lambdaNode, 'new-function', 'aws.invokeLambda')])
+
+            const childNodes = await awsExplorer.getChildren(lambdaNode)
+            assert.ok(childNodes[0] instanceof AWSCommandTreeNode)
+            assert.strictEqual(childNodes[0].label, 'new-function')
+        })
+    })
 })
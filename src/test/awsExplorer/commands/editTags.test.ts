@@ -0,0 +1,200 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { editTagsCommand, parseTagKeys, parseTags, toTaggableResource } from '../../../awsexplorer/commands/editTags'
+import { LogGroupNode } from '../../../cloudWatchLogs/explorer/logGroupNode'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { S3BucketNode } from '../../../s3/explorer/s3BucketNode'
+import { S3Node } from '../../../s3/explorer/s3Nodes'
+import { AWSTreeNodeBase } from '../../../shared/treeview/nodes/awsTreeNodeBase'
+import { FakeParentNode } from '../../cdk/explorer/constructNode.test'
+import { MockResourceGroupsTaggingClient, MockS3Client } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('editTagsCommand', function () {
+    const parent = new FakeParentNode('parent')
+    const functionArn = 'arn:aws:lambda:us-west-2:123456789012:function:my-function'
+    const logGroupArn = 'arn:aws:logs:us-west-2:123456789012:log-group:my-logs'
+    const bucketArn = 'arn:aws:s3:::my-bucket'
+
+    const functionNode = new LambdaFunctionNode(parent, 'us-west-2', {
+        FunctionName: 'my-function',
+        FunctionArn: functionArn,
+    })
+    const logGroupNode = new LogGroupNode(parent, 'us-west-2', { logGroupName: 'my-logs', arn: `${logGroupArn}:*` })
+    const bucketNode = new S3BucketNode(
+        { name: 'my-bucket', region: 'us-east-1', arn: bucketArn },
+        {} as S3Node,
+        new MockS3Client({})
+    )
+
+    /** Picks the item whose label contains the given text. */
+    function pick(label: string) {
+        return async <T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) => {
+            const item = picker.items.find(item => item.label.includes(label))
+
+            return item ? [item] : undefined
+        }
+    }
+
+    describe('toTaggableResource', function () {
+        it('gets the region of the resource', function () {
+            assert.deepStrictEqual(toTaggableResource(functionNode), {
+                arn: functionArn,
+                name: 'my-function',
+                regionCode: 'us-west-2',
+            })
+            assert.strictEqual(toTaggableResource(bucketNode)?.regionCode, 'us-east-1')
+        })
+
+        it('removes the wildcard suffix of log group ARNs', function () {
+            assert.strictEqual(toTaggableResource(logGroupNode)?.arn, logGroupArn)
+        })
+
+        it('does not get resources of nodes that cannot be tagged', function () {
+            assert.strictEqual(toTaggableResource(parent), undefined)
+        })
+    })
+
+    describe('parseTags', function () {
+        it('parses comma-separated key=value pairs', function () {
+            assert.deepStrictEqual(parseTags(' team=payments, env = prod ,reviewed='), {
+                team: 'payments',
+                env: 'prod',
+                reviewed: '',
+            })
+        })
+
+        it('rejects pairs without a value and reserved keys', function () {
+            assert.throws(() => parseTags('team'), /team=value/)
+            assert.throws(() => parseTags('=payments'), /key/)
+            assert.throws(() => parseTags('aws:team=payments'), /reserved/)
+            assert.throws(() => parseTags(' , '), /at least one tag/)
+        })
+    })
+
+    describe('parseTagKeys', function () {
+        it('parses comma-separated keys without duplicates', function () {
+            assert.deepStrictEqual(parseTagKeys('team, env,team'), ['team', 'env'])
+            assert.throws(() => parseTagKeys(''), /at least one tag key/)
+        })
+    })
+
+    it('adds tags to the selected resources with a call per region', async function () {
+        const calls: { regionCode: string; arns: string[] }[] = []
+        const window = new FakeWindow({ inputBox: { input: 'team=payments' } })
+
+        await editTagsCommand(
+            functionNode,
+            [functionNode, logGroupNode, bucketNode],
+            window,
+            pick('Add or update tags'),
+            regionCode =>
+                new MockResourceGroupsTaggingClient({
+                    tagResources: async (arns, tags) => {
+                        assert.deepStrictEqual(tags, { team: 'payments' })
+                        calls.push({ regionCode, arns })
+                        return {}
+                    },
+                })
+        )
+
+        assert.deepStrictEqual(calls, [
+            { regionCode: 'us-west-2', arns: [functionArn, logGroupArn] },
+            { regionCode: 'us-east-1', arns: [bucketArn] },
+        ])
+        assert.strictEqual(window.message.information, 'Updated tags of 3 resources')
+    })
+
+    it('tags only the clicked node when it is not part of the selection', async function () {
+        let taggedArns: string[] = []
+
+        await editTagsCommand(
+            bucketNode,
+            [functionNode, logGroupNode],
+            new FakeWindow({ inputBox: { input: 'team=payments' } }),
+            pick('Add or update tags'),
+            () =>
+                new MockResourceGroupsTaggingClient({
+                    tagResources: async arns => {
+                        taggedArns = arns
+                        return {}
+                    },
+                })
+        )
+
+        assert.deepStrictEqual(taggedArns, [bucketArn])
+    })
+
+    it('removes tags after confirming', async function () {
+        let removed: string[] | undefined
+        const window = new FakeWindow({ inputBox: { input: 'team, env' }, message: { warningSelection: 'Remove' } })
+
+        await editTagsCommand(
+            functionNode,
+            [functionNode],
+            window,
+            pick('Remove tags'),
+            () =>
+                new MockResourceGroupsTaggingClient({
+                    untagResources: async (arns, tagKeys) => {
+                        removed = tagKeys
+                        return {}
+                    },
+                })
+        )
+
+        assert.ok(window.message.warning?.startsWith('Remove tags team, env from 1 resources?'))
+        assert.deepStrictEqual(removed, ['team', 'env'])
+    })
+
+    it('does not remove tags without confirming', async function () {
+        await editTagsCommand(
+            functionNode,
+            [functionNode],
+            new FakeWindow({ inputBox: { input: 'team' } }),
+            pick('Remove tags'),
+            () =>
+                new MockResourceGroupsTaggingClient({
+                    untagResources: async () => assert.fail('should not remove tags'),
+                })
+        )
+    })
+
+    it('summarizes failures after trying all resources', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'team=payments' } })
+
+        await editTagsCommand(
+            functionNode,
+            [functionNode, logGroupNode, bucketNode],
+            window,
+            pick('Add or update tags'),
+            regionCode =>
+                new MockResourceGroupsTaggingClient({
+                    tagResources: async () => {
+                        if (regionCode === 'us-east-1') {
+                            throw new Error('Expected failure')
+                        }
+                        return { [logGroupArn]: { ErrorCode: 'InvalidParameterException', ErrorMessage: 'denied' } }
+                    },
+                })
+        )
+
+        assert.ok(
+            window.message.error?.startsWith('Updated tags of 1 of 3 resources. Failed to update: my-logs, my-bucket')
+        )
+    })
+
+    it('shows an error message when no selected resource can be tagged', async function () {
+        const window = new FakeWindow()
+        const node: AWSTreeNodeBase = parent
+
+        await editTagsCommand(node, [node], window, async () => assert.fail('should not prompt'))
+
+        assert.ok(window.message.error?.startsWith('None of the selected resources can be tagged'))
+    })
+})
@@ -0,0 +1,98 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { moveFavoriteCommand, pinFavoriteCommand, unpinFavoriteCommand } from '../../../awsexplorer/commands/favorites'
+import { FavoriteNode } from '../../../awsexplorer/favoriteNode'
+import { FavoritesStore } from '../../../awsexplorer/favorites'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { FakeExtensionContext } from '../../fakeExtensionContext'
+import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { makeFakeAwsContextWithPlaceholderIds } from '../../utilities/fakeAwsContext'
+
+describe('favorites commands', function () {
+    const parentNode = new TestAWSTreeNode('test node')
+    const awsContext = makeFakeAwsContextWithPlaceholderIds(({} as any) as AWS.Credentials)
+
+    let store: FavoritesStore
+
+    function makeFunctionNode(name: string): LambdaFunctionNode {
+        return new LambdaFunctionNode(parentNode, 'us-west-2', {
+            FunctionName: name,
+            FunctionArn: `arn:aws:lambda:us-west-2:123456789012:function:${name}`,
+        })
+    }
+
+    beforeEach(function () {
+        store = new FavoritesStore(new FakeExtensionContext().globalState, 'file:///workspace')
+    })
+
+    describe('pinFavoriteCommand', function () {
+        it('pins a node', async function () {
+            const window = new FakeWindow()
+
+            await pinFavoriteCommand(makeFunctionNode('a'), undefined, store, awsContext, window)
+
+            assert.deepStrictEqual(
+                store.list().map(favorite => favorite.name),
+                ['a']
+            )
+            assert.strictEqual(window.message.information, 'Pinned a to Favorites')
+        })
+
+        it('pins the selected nodes, skipping those already pinned', async function () {
+            await pinFavoriteCommand(makeFunctionNode('a'), undefined, store, awsContext, new FakeWindow())
+            const window = new FakeWindow()
+            const selectedNodes = [makeFunctionNode('a'), makeFunctionNode('b'), makeFunctionNode('c')]
+
+            await pinFavoriteCommand(selectedNodes[1], selectedNodes, store, awsContext, window)
+
+            assert.deepStrictEqual(
+                store.list().map(favorite => favorite.name),
+                ['a', 'b', 'c']
+            )
+            assert.strictEqual(window.message.information, 'Pinned b, c to Favorites')
+        })
+
+        it('tells the user when a node is already pinned', async function () {
+            await pinFavoriteCommand(makeFunctionNode('a'), undefined, store, awsContext, new FakeWindow())
+            const window = new FakeWindow()
+
+            await pinFavoriteCommand(makeFunctionNode('a'), undefined, store, awsContext, window)
+
+            assert.strictEqual(store.list().length, 1)
+            assert.strictEqual(window.message.information, 'a is already pinned to Favorites')
+        })
+
+        it('shows an error message for nodes that cannot be pinned', async function () {
+            const window = new FakeWindow()
+
+            await pinFavoriteCommand(parentNode, undefined, store, awsContext, window)
+
+            assert.deepStrictEqual(store.list(), [])
+            assert.ok(window.message.error?.includes('cannot be pinned'))
+        })
+    })
+
+    it('unpins and moves favorites', async function () {
+        for (const name of ['a', 'b']) {
+            await pinFavoriteCommand(makeFunctionNode(name), undefined, store, awsContext, new FakeWindow())
+        }
+        const [a, b] = store.list().map(favorite => new FavoriteNode(favorite))
+
+        await moveFavoriteCommand(b, store, -1)
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['b', 'a']
+        )
+
+        await unpinFavoriteCommand(a, store)
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['b']
+        )
+    })
+})
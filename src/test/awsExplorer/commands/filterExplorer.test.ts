@@ -0,0 +1,44 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { AwsExplorer } from '../../../awsexplorer/awsExplorer'
+import { clearExplorerFilterCommand, filterExplorerCommand } from '../../../awsexplorer/commands/filterExplorer'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('filterExplorerCommand', function () {
+    let explorer: AwsExplorer
+    let filters: (string | undefined)[]
+
+    beforeEach(function () {
+        filters = []
+        explorer = ({
+            filter: 'current',
+            setFilter: (filter: string | undefined) => filters.push(filter),
+        } as any) as AwsExplorer
+    })
+
+    it('prompts for a filter, prefilled with the active filter, and applies it', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'my-function' } })
+        await filterExplorerCommand(explorer, window)
+
+        assert.strictEqual(window.inputBox.options?.value, 'current')
+        assert.deepStrictEqual(filters, ['my-function'])
+    })
+
+    it('does nothing when prompt is cancelled', async function () {
+        await filterExplorerCommand(explorer, new FakeWindow())
+
+        assert.deepStrictEqual(filters, [])
+    })
+
+    describe('clearExplorerFilterCommand', function () {
+        it('removes the filter', async function () {
+            await clearExplorerFilterCommand(explorer)
+
+            assert.deepStrictEqual(filters, [undefined])
+        })
+    })
+})
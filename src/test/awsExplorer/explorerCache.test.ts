@@ -0,0 +1,111 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { ExplorerCache, getChildKey, isNetworkError } from '../../awsexplorer/explorerCache'
+import { makeTemporaryToolkitFolder } from '../../shared/filesystemUtilities'
+import { FakeParentNode } from '../cdk/explorer/constructNode.test'
+
+describe('ExplorerCache', function () {
+    let tempFolder: string
+    let cacheFile: string
+    let cache: ExplorerCache
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        cacheFile = path.join(tempFolder, 'explorerCache.json')
+        cache = new ExplorerCache(cacheFile)
+    })
+
+    afterEach(async function () {
+        await fs.remove(tempFolder)
+    })
+
+    function makeNode(label: string, collapsible: boolean = false): FakeParentNode {
+        const node = new FakeParentNode(label)
+        node.collapsibleState = collapsible
+            ? vscode.TreeItemCollapsibleState.Collapsed
+            : vscode.TreeItemCollapsibleState.None
+        node.description = 'description'
+        node.iconPath = new vscode.ThemeIcon('folder')
+        node.contextValue = 'awsS3FolderNode'
+
+        return node
+    }
+
+    it('tells network errors from API errors', function () {
+        for (const code of ['NetworkingError', 'TimeoutError', 'UnknownEndpoint', 'ECONNRESET', 'ENOTFOUND']) {
+            assert.ok(isNetworkError(Object.assign(new Error(), { code })), code)
+        }
+        for (const code of ['AccessDenied', 'AccessDeniedException', 'ExpiredToken', undefined]) {
+            assert.ok(!isNetworkError(Object.assign(new Error(), { code })), code)
+        }
+    })
+
+    it('shows cached nodes without their context menus', async function () {
+        await cache.saveChildren('account/region', [makeNode('folder', true)])
+
+        const [node] = cache.getCachedChildren('account/region')!
+        assert.strictEqual(node.label, 'folder')
+        assert.strictEqual(node.description, 'description (offline)')
+        assert.strictEqual((node.iconPath as vscode.ThemeIcon).id, 'folder')
+        assert.strictEqual(node.collapsibleState, vscode.TreeItemCollapsibleState.Collapsed)
+        assert.strictEqual(node.contextValue, 'awsCachedNode')
+    })
+
+    it('shows the cached children of cached nodes', async function () {
+        await cache.saveChildren('account/region', [makeNode('folder', true)])
+        await cache.saveChildren(getChildKey('account/region', 'folder'), [makeNode('file')])
+
+        const [folder] = cache.getCachedChildren('account/region')!
+        const children = await folder.getChildren()
+        assert.deepStrictEqual(
+            children.map(node => node.label),
+            ['file']
+        )
+    })
+
+    it('keeps the cached children in a file for the next session', async function () {
+        await cache.saveChildren('account/region', [makeNode('file')])
+
+        const children = new ExplorerCache(cacheFile).getCachedChildren('account/region')
+        assert.deepStrictEqual(
+            children?.map(node => node.label),
+            ['file']
+        )
+    })
+
+    it('only writes the file when the children changed', async function () {
+        await cache.saveChildren('account/region', [makeNode('file')])
+        await fs.remove(cacheFile)
+
+        await cache.saveChildren('account/region', [makeNode('file')])
+        assert.ok(!(await fs.pathExists(cacheFile)), 'the same children are not written again')
+
+        await cache.saveChildren('account/region', [makeNode('file'), makeNode('other-file')])
+        assert.ok(await fs.pathExists(cacheFile))
+    })
+
+    it('caps the children cached per node', async function () {
+        const nodes = Array.from({ length: 300 }, (_, i) => makeNode(`file${i}`))
+        await cache.saveChildren('account/region', nodes)
+
+        assert.strictEqual(cache.getCachedChildren('account/region')?.length, 200)
+    })
+
+    it('only falls back to cached nodes after network errors', async function () {
+        await cache.saveChildren('account/region', [makeNode('file')])
+
+        const networkError = Object.assign(new Error(), { code: 'NetworkingError' })
+        assert.strictEqual((await cache.getFallbackChildren('account/region', networkError))?.length, 1)
+
+        const apiError = Object.assign(new Error(), { code: 'AccessDenied' })
+        assert.strictEqual(await cache.getFallbackChildren('account/region', apiError), undefined)
+        assert.strictEqual(cache.getCachedChildren('account/region'), undefined)
+    })
+})
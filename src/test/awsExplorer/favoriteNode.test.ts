@@ -0,0 +1,166 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { FavoriteNode } from '../../awsexplorer/favoriteNode'
+import { Favorite } from '../../awsexplorer/favorites'
+import { LambdaAliasesNode, LambdaVersionsNode } from '../../lambda/explorer/lambdaQualifierNodes'
+import { ToolkitClientBuilder } from '../../shared/clients/toolkitClientBuilder'
+import { ext } from '../../shared/extensionGlobals'
+import { MockLambdaClient, MockS3Client } from '../shared/clients/mockClients'
+import {
+    DEFAULT_TEST_ACCOUNT_ID,
+    FakeAwsContext,
+    makeFakeAwsContextWithPlaceholderIds,
+} from '../utilities/fakeAwsContext'
+
+describe('FavoriteNode', function () {
+    const awsContext = makeFakeAwsContextWithPlaceholderIds(({} as any) as AWS.Credentials)
+    const functionFavorite: Favorite = {
+        type: 'lambdaFunction',
+        arn: 'arn:aws:lambda:us-west-2:123456789012:function:my-function',
+        name: 'my-function',
+        regionCode: 'us-west-2',
+        credentialsId: 'profile:default',
+        accountId: DEFAULT_TEST_ACCOUNT_ID,
+    }
+    const bucketFavorite: Favorite = {
+        type: 's3Bucket',
+        arn: 'arn:aws:s3:::my-bucket',
+        name: 'my-bucket',
+        regionCode: 'eu-west-1',
+        accountId: DEFAULT_TEST_ACCOUNT_ID,
+    }
+
+    let originalClientBuilder: ToolkitClientBuilder
+    let lambda: MockLambdaClient
+    let s3: MockS3Client
+    let requestedRegions: string[]
+
+    before(function () {
+        originalClientBuilder = ext.toolkitClientBuilder
+    })
+
+    beforeEach(function () {
+        requestedRegions = []
+        lambda = new MockLambdaClient({})
+        s3 = new MockS3Client({})
+        ext.toolkitClientBuilder = ({
+            createLambdaClient: (regionCode: string) => {
+                requestedRegions.push(regionCode)
+                return lambda
+            },
+            createS3Client: (regionCode: string) => {
+                requestedRegions.push(regionCode)
+                return s3
+            },
+        } as any) as ToolkitClientBuilder
+    })
+
+    after(function () {
+        ext.toolkitClientBuilder = originalClientBuilder
+    })
+
+    it('shows the saved favorite until it is resolved', function () {
+        const node = new FavoriteNode(functionFavorite)
+
+        assert.strictEqual(node.state, 'loading')
+        assert.strictEqual(node.label, 'my-function')
+        assert.strictEqual(node.description, 'us-west-2')
+        assert.strictEqual(node.arn, functionFavorite.arn)
+        assert.strictEqual(node.collapsibleState, vscode.TreeItemCollapsibleState.None)
+    })
+
+    it('shows a resource that exists as the AWS Explorer does, in the region it was pinned in', async function () {
+        lambda = new MockLambdaClient({
+            getFunctionConfiguration: async name => ({ FunctionName: name, FunctionArn: functionFavorite.arn }),
+        })
+        const node = new FavoriteNode(functionFavorite)
+
+        await node.resolve(awsContext)
+
+        assert.strictEqual(node.state, 'available')
+        assert.deepStrictEqual(requestedRegions, ['us-west-2'])
+        assert.strictEqual(node.collapsibleState, vscode.TreeItemCollapsibleState.Collapsed)
+        const childNodes = await node.getChildren()
+        assert.ok(childNodes[0] instanceof LambdaVersionsNode)
+        assert.ok(childNodes[1] instanceof LambdaAliasesNode)
+    })
+
+    it('grays out a resource that no longer exists', async function () {
+        lambda = new MockLambdaClient({
+            getFunctionConfiguration: async () => {
+                throw Object.assign(new Error('Function not found'), { code: 'ResourceNotFoundException' })
+            },
+        })
+        const node = new FavoriteNode(functionFavorite)
+
+        await node.resolve(awsContext)
+
+        assert.strictEqual(node.state, 'notFound')
+        assert.strictEqual(node.description, 'us-west-2 (not found)')
+        assert.strictEqual((node.iconPath as vscode.ThemeIcon).id, 'circle-slash')
+        assert.deepStrictEqual(await node.getChildren(), [])
+    })
+
+    it('grays out a resource of another account without looking it up', async function () {
+        const otherAccount = new FakeAwsContext({
+            contextCredentials: {
+                credentials: ({} as any) as AWS.Credentials,
+                credentialsId: 'profile:other',
+                accountId: '999999999999',
+            },
+        })
+        const node = new FavoriteNode(functionFavorite)
+
+        await node.resolve(otherAccount)
+
+        assert.strictEqual(node.state, 'disconnected')
+        assert.strictEqual(node.description, 'us-west-2 (connect to profile:default)')
+        assert.deepStrictEqual(requestedRegions, [])
+    })
+
+    it('grays out resources while no account is connected', async function () {
+        const node = new FavoriteNode(functionFavorite)
+
+        await node.resolve(new FakeAwsContext())
+
+        assert.strictEqual(node.state, 'disconnected')
+    })
+
+    it('shows the error of a resource that could not be looked up', async function () {
+        lambda = new MockLambdaClient({
+            getFunctionConfiguration: async () => {
+                throw Object.assign(new Error('Access denied'), { code: 'AccessDeniedException' })
+            },
+        })
+        const node = new FavoriteNode(functionFavorite)
+
+        await node.resolve(awsContext)
+
+        assert.strictEqual(node.state, 'failed')
+        assert.ok((node.tooltip as string).includes('Access denied'))
+    })
+
+    it('finds buckets among all buckets', async function () {
+        s3 = new MockS3Client({ listAllBuckets: async () => [{ Name: 'other-bucket' }, { Name: 'my-bucket' }] })
+        const node = new FavoriteNode(bucketFavorite)
+
+        await node.resolve(awsContext)
+
+        assert.strictEqual(node.state, 'available')
+        assert.deepStrictEqual(requestedRegions, ['eu-west-1'])
+    })
+
+    it('grays out buckets that were deleted', async function () {
+        s3 = new MockS3Client({ listAllBuckets: async () => [{ Name: 'other-bucket' }] })
+        const node = new FavoriteNode(bucketFavorite)
+
+        await node.resolve(awsContext)
+
+        assert.strictEqual(node.state, 'notFound')
+    })
+})
@@ -0,0 +1,155 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { Favorite, FavoritesStore, toFavorite } from '../../awsexplorer/favorites'
+import { LogGroupNode } from '../../cloudWatchLogs/explorer/logGroupNode'
+import { LambdaFunctionNode } from '../../lambda/explorer/lambdaFunctionNode'
+import { S3BucketNode } from '../../s3/explorer/s3BucketNode'
+import { S3Node } from '../../s3/explorer/s3Nodes'
+import { FakeExtensionContext } from '../fakeExtensionContext'
+import { MockS3Client } from '../shared/clients/mockClients'
+import { TestAWSTreeNode } from '../shared/treeview/nodes/testAWSTreeNode'
+import { FakeAwsContext } from '../utilities/fakeAwsContext'
+
+function makeFavorite(name: string): Favorite {
+    return {
+        type: 'lambdaFunction',
+        arn: `arn:aws:lambda:us-west-2:123456789012:function:${name}`,
+        name,
+        regionCode: 'us-west-2',
+    }
+}
+
+describe('toFavorite', function () {
+    const parentNode = new TestAWSTreeNode('test node')
+    const awsContext = new FakeAwsContext({
+        contextCredentials: {
+            credentials: ({} as any) as AWS.Credentials,
+            credentialsId: 'profile:default',
+            accountId: '123456789012',
+        },
+    })
+
+    it('keeps the region and connection of a resource', function () {
+        const node = new LambdaFunctionNode(parentNode, 'us-west-2', {
+            FunctionName: 'my-function',
+            FunctionArn: 'arn:aws:lambda:us-west-2:123456789012:function:my-function',
+        })
+
+        assert.deepStrictEqual(toFavorite(node, awsContext), {
+            type: 'lambdaFunction',
+            arn: 'arn:aws:lambda:us-west-2:123456789012:function:my-function',
+            name: 'my-function',
+            regionCode: 'us-west-2',
+            credentialsId: 'profile:default',
+            accountId: '123456789012',
+        })
+    })
+
+    it('uses the region of buckets', function () {
+        const s3 = new MockS3Client({})
+        const bucket = { name: 'my-bucket', region: 'eu-west-1', arn: 'arn:aws:s3:::my-bucket' }
+
+        const favorite = toFavorite(new S3BucketNode(bucket, new S3Node(s3), s3), awsContext)
+
+        assert.strictEqual(favorite?.type, 's3Bucket')
+        assert.strictEqual(favorite?.regionCode, 'eu-west-1')
+    })
+
+    it('describes log groups', function () {
+        const node = new LogGroupNode(parentNode, 'us-west-2', {
+            logGroupName: '/aws/lambda/my-function',
+            arn: 'arn:aws:logs:us-west-2:123456789012:log-group:/aws/lambda/my-function:*',
+        })
+
+        assert.strictEqual(toFavorite(node, awsContext)?.type, 'logGroup')
+    })
+
+    it('does not describe other nodes', function () {
+        assert.strictEqual(toFavorite(parentNode, awsContext), undefined)
+    })
+})
+
+describe('FavoritesStore', function () {
+    let context: FakeExtensionContext
+    let store: FavoritesStore
+
+    beforeEach(function () {
+        context = new FakeExtensionContext()
+        store = new FavoritesStore(context.globalState, 'file:///workspace')
+    })
+
+    it('adds favorites in order, once each', async function () {
+        assert.ok(await store.add(makeFavorite('a')))
+        assert.ok(await store.add(makeFavorite('b')))
+        assert.ok(!(await store.add(makeFavorite('a'))))
+
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['a', 'b']
+        )
+    })
+
+    it('keeps the favorites of each workspace apart', async function () {
+        await store.add(makeFavorite('a'))
+        const otherStore = new FavoritesStore(context.globalState, 'file:///other')
+        await otherStore.add(makeFavorite('b'))
+
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['a']
+        )
+        assert.deepStrictEqual(
+            otherStore.list().map(favorite => favorite.name),
+            ['b']
+        )
+        assert.deepStrictEqual(
+            new FavoritesStore(context.globalState, 'file:///workspace').list().map(favorite => favorite.name),
+            ['a']
+        )
+    })
+
+    it('removes favorites', async function () {
+        await store.add(makeFavorite('a'))
+        await store.add(makeFavorite('b'))
+
+        await store.remove(makeFavorite('a').arn)
+
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['b']
+        )
+    })
+
+    it('moves favorites up and down within bounds', async function () {
+        for (const name of ['a', 'b', 'c']) {
+            await store.add(makeFavorite(name))
+        }
+
+        await store.move(makeFavorite('c').arn, -1)
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['a', 'c', 'b']
+        )
+
+        await store.move(makeFavorite('a').arn, -1)
+        await store.move(makeFavorite('b').arn, 1)
+        assert.deepStrictEqual(
+            store.list().map(favorite => favorite.name),
+            ['a', 'c', 'b']
+        )
+    })
+
+    it('fires an event when favorites change', async function () {
+        let changes = 0
+        store.onDidChange(() => changes++)
+
+        await store.add(makeFavorite('a'))
+        await store.remove(makeFavorite('a').arn)
+
+        assert.strictEqual(changes, 2)
+    })
+})
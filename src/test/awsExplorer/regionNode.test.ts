@@ -22,7 +22,17 @@ describe('RegionNode', function () {
         // contingency for current Node impl: requires a client built from ext.toolkitClientBuilder.
         const clientBuilder = {
             createS3Client: sandbox.stub().returns({}),
+            createDynamoDbClient: sandbox.stub().returns({}),
+            createEc2Client: sandbox.stub().returns({}),
+            createSsmClient: sandbox.stub().returns({}),
             createEcrClient: sandbox.stub().returns({}),
+            createEcsClient: sandbox.stub().returns({}),
+            createSecretsManagerClient: sandbox.stub().returns({}),
+            createKinesisClient: sandbox.stub().returns({}),
+            createSqsClient: sandbox.stub().returns({}),
+            createCognitoClient: sandbox.stub().returns({}),
+            createAppConfigClient: sandbox.stub().returns({}),
+            createLambdaClient: sandbox.stub().returns({}),
         }
         ext.toolkitClientBuilder = (clientBuilder as any) as ToolkitClientBuilder
 
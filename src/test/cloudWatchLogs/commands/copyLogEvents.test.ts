@@ -0,0 +1,100 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { copyLogEvents, formatLogMessage } from '../../../cloudWatchLogs/commands/copyLogEvents'
+import { CloudWatchLogStreamData, LogStreamRegistry } from '../../../cloudWatchLogs/registry/logStreamRegistry'
+import { CLOUDWATCH_LOGS_SCHEME } from '../../../shared/constants'
+import { FakeEnv } from '../../shared/vscode/fakeEnv'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { TestSettingsConfiguration } from '../../utilities/testSettingsConfiguration'
+
+describe('copyLogEvents', function () {
+    const uri = vscode.Uri.parse(`${CLOUDWATCH_LOGS_SCHEME}:group:stream:region`)
+    const stream: CloudWatchLogStreamData = {
+        data: [
+            { timestamp: 1624201162222, message: '{"level":"INFO","message":"started"}\n' },
+            { timestamp: 1624201162223, message: 'Error: boom\n' },
+            { timestamp: 1624201162224, message: '    at handler (index.js:3:9)\n' },
+        ],
+        busy: false,
+    }
+    let registry: LogStreamRegistry
+
+    beforeEach(function () {
+        registry = new LogStreamRegistry(new TestSettingsConfiguration(), new Map([[uri.path, stream]]))
+    })
+
+    function makeEditor(startLine: number, endLine: number): vscode.TextEditor {
+        return ({
+            document: { uri, fileName: uri.path },
+            selections: [new vscode.Selection(startLine, 0, endLine, 0)],
+        } as any) as vscode.TextEditor
+    }
+
+    describe('formatLogMessage', function () {
+        it('pretty-prints JSON messages', function () {
+            assert.strictEqual(formatLogMessage('{"a":{"b":1}}\n'), '{\n    "a": {\n        "b": 1\n    }\n}')
+        })
+
+        it('copies other messages as they are', function () {
+            assert.strictEqual(formatLogMessage('START RequestId: 123\n'), 'START RequestId: 123')
+            assert.strictEqual(formatLogMessage('42'), '42')
+        })
+
+        it('prepends the ISO timestamp', function () {
+            assert.strictEqual(formatLogMessage('{}', 1624201162222), '2021-06-20T14:59:22.222Z {}')
+        })
+    })
+
+    it('copies the event under the cursor as JSON', async function () {
+        const env = new FakeEnv()
+
+        await copyLogEvents(registry, {}, makeEditor(0, 0), new FakeWindow(), env)
+
+        assert.strictEqual(env.clipboard.text, '{\n    "level": "INFO",\n    "message": "started"\n}')
+    })
+
+    it('copies the event with its timestamp', async function () {
+        const env = new FakeEnv()
+
+        await copyLogEvents(registry, { timestamp: true }, makeEditor(1, 1), new FakeWindow(), env)
+
+        assert.strictEqual(env.clipboard.text, '2021-06-20T14:59:22.223Z Error: boom')
+    })
+
+    it('coalesces selected events into one message', async function () {
+        const env = new FakeEnv()
+        const window = new FakeWindow({ message: { informationSelection: 'Coalesce' } })
+
+        await copyLogEvents(registry, { timestamp: true }, makeEditor(1, 2), window, env)
+
+        assert.strictEqual(env.clipboard.text, '2021-06-20T14:59:22.223Z Error: boom\n    at handler (index.js:3:9)')
+    })
+
+    it('copies selected events separately', async function () {
+        const env = new FakeEnv()
+        const window = new FakeWindow({ message: { informationSelection: 'Copy Separately' } })
+
+        await copyLogEvents(registry, {}, makeEditor(0, 1), window, env)
+
+        assert.strictEqual(env.clipboard.text, '{\n    "level": "INFO",\n    "message": "started"\n}\nError: boom')
+    })
+
+    it('does not copy anything from other editors', async function () {
+        const env = new FakeEnv()
+        const window = new FakeWindow()
+        const editor = ({
+            document: { uri: vscode.Uri.file('/notes.txt'), fileName: '/notes.txt' },
+            selections: [new vscode.Selection(0, 0, 0, 0)],
+        } as any) as vscode.TextEditor
+
+        await copyLogEvents(registry, {}, editor, window, env)
+
+        assert.strictEqual(env.clipboard.text, undefined)
+        assert.strictEqual(window.message.error, 'Not a Cloudwatch Log stream: /notes.txt')
+    })
+})
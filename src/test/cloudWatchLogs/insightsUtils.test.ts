@@ -0,0 +1,122 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import {
+    compareCells,
+    isQueryFinished,
+    resolveTimeRange,
+    sortRows,
+    tokenizeQuery,
+    toResultTable,
+} from '../../cloudWatchLogs/insightsUtils'
+
+describe('insightsUtils', function () {
+    describe('isQueryFinished', function () {
+        it('is false while the query is scheduled or running', function () {
+            assert.strictEqual(isQueryFinished('Scheduled'), false)
+            assert.strictEqual(isQueryFinished('Running'), false)
+        })
+
+        it('is true for any other status', function () {
+            for (const status of ['Complete', 'Failed', 'Cancelled', 'Timeout', 'Unknown', undefined]) {
+                assert.strictEqual(isQueryFinished(status), true, `expected ${status} to be finished`)
+            }
+        })
+    })
+
+    describe('resolveTimeRange', function () {
+        it('ends a relative range now', function () {
+            const now = Date.UTC(2021, 0, 1, 12)
+
+            assert.deepStrictEqual(resolveTimeRange({ type: 'relative', minutes: 60 }, now), {
+                startTime: now / 1000 - 3600,
+                endTime: now / 1000,
+            })
+        })
+
+        it('converts an absolute range to seconds', function () {
+            assert.deepStrictEqual(resolveTimeRange({ type: 'absolute', start: 1500, end: 4500 }), {
+                startTime: 1,
+                endTime: 5,
+            })
+        })
+
+        it('throws if the range ends before it starts', function () {
+            assert.throws(() => resolveTimeRange({ type: 'absolute', start: 5000, end: 1000 }), /must be before/)
+            assert.throws(() => resolveTimeRange({ type: 'absolute', start: NaN, end: 1000 }), /must be before/)
+        })
+    })
+
+    describe('toResultTable', function () {
+        it('orders columns by first appearance and hides @ptr', function () {
+            const table = toResultTable([
+                [
+                    { field: '@timestamp', value: '2021-01-01 00:00:00.000' },
+                    { field: '@ptr', value: 'abc' },
+                ],
+                [
+                    { field: '@timestamp', value: '2021-01-01 00:00:01.000' },
+                    { field: '@message', value: 'hello' },
+                ],
+            ])
+
+            assert.deepStrictEqual(table.columns, ['@timestamp', '@message'])
+            assert.deepStrictEqual(table.rows, [
+                { '@timestamp': '2021-01-01 00:00:00.000' },
+                { '@timestamp': '2021-01-01 00:00:01.000', '@message': 'hello' },
+            ])
+        })
+
+        it('returns an empty table for no results', function () {
+            assert.deepStrictEqual(toResultTable([]), { columns: [], rows: [] })
+        })
+    })
+
+    describe('sortRows', function () {
+        const rows: { [column: string]: string }[] = [{ count: '10' }, { count: '9' }, { count: 'n/a' }, {}]
+
+        it('compares numbers numerically', function () {
+            assert.ok(compareCells('9', '10') < 0)
+            assert.ok(compareCells('abc', 'abd') < 0)
+        })
+
+        it('sorts ascending and descending without changing the rows', function () {
+            assert.deepStrictEqual(sortRows(rows, 'count', false).map(row => row.count), [undefined, '9', '10', 'n/a'])
+            assert.deepStrictEqual(sortRows(rows, 'count', true).map(row => row.count), ['n/a', '10', '9', undefined])
+            assert.strictEqual(rows[0].count, '10')
+        })
+    })
+
+    describe('tokenizeQuery', function () {
+        const query = "fields @timestamp, @message # recent\n| filter @message like /ERROR/ and level = 'warn'\n| stats count(*) / 2 as n by bin(5m)"
+
+        function tokensOf(type: string): string[] {
+            return tokenizeQuery(query).filter(token => token.type === type).map(token => token.text)
+        }
+
+        it('keeps all of the query text', function () {
+            assert.strictEqual(tokenizeQuery(query).map(token => token.text).join(''), query)
+        })
+
+        it('finds commands only at the start of a query or after a pipe', function () {
+            assert.deepStrictEqual(tokensOf('command'), ['fields', 'filter', 'stats'])
+            assert.strictEqual(tokenizeQuery('filter fields = 1').filter(token => token.type === 'command').length, 1)
+        })
+
+        it('finds fields, functions, keywords, strings, and comments', function () {
+            assert.deepStrictEqual(tokensOf('field'), ['@timestamp', '@message', '@message'])
+            assert.deepStrictEqual(tokensOf('function'), ['count', 'bin'])
+            assert.deepStrictEqual(tokensOf('keyword'), ['like', 'and', 'as', 'by'])
+            assert.deepStrictEqual(tokensOf('string'), ["'warn'"])
+            assert.deepStrictEqual(tokensOf('comment'), ['# recent'])
+        })
+
+        it('distinguishes patterns from division', function () {
+            assert.deepStrictEqual(tokensOf('regex'), ['/ERROR/'])
+            assert.deepStrictEqual(tokensOf('number'), ['2', '5'])
+        })
+    })
+})
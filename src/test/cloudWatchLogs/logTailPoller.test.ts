@@ -0,0 +1,85 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as FakeTimers from '@sinonjs/fake-timers'
+import { LogTailPoller, TAIL_MAX_BACKOFF_MILLIS } from '../../cloudWatchLogs/logTailPoller'
+
+describe('LogTailPoller', function () {
+    let clock: FakeTimers.InstalledClock
+    let poller: LogTailPoller | undefined
+
+    beforeEach(function () {
+        clock = FakeTimers.install()
+    })
+
+    afterEach(function () {
+        poller?.dispose()
+        clock.uninstall()
+    })
+
+    it('polls at the interval until stopped', async function () {
+        let polls = 0
+        poller = new LogTailPoller(
+            async () => {
+                polls++
+            },
+            { pollIntervalMillis: 100 }
+        )
+
+        poller.start()
+        await clock.tickAsync(250)
+        assert.strictEqual(polls, 3)
+
+        poller.stop()
+        await poller.done
+        await clock.tickAsync(1000)
+        assert.strictEqual(polls, 3, 'no polls are made after stopping')
+    })
+
+    it('backs off after failures, and resets once a poll succeeds', async function () {
+        const pollTimes: number[] = []
+        const failures: number[] = []
+        let recovered = 0
+        poller = new LogTailPoller(
+            async () => {
+                pollTimes.push(Date.now())
+                if (pollTimes.length <= 2) {
+                    throw new Error('throttled')
+                }
+            },
+            {
+                pollIntervalMillis: 100,
+                onFailure: (_, count) => failures.push(count),
+                onRecover: () => recovered++,
+            }
+        )
+
+        poller.start()
+        await clock.tickAsync(800)
+
+        assert.deepStrictEqual(failures, [1, 2])
+        assert.strictEqual(recovered, 1)
+        assert.strictEqual(poller.failures, 0)
+        // 200ms after the first failure, 400ms after the second, then back to the interval
+        assert.deepStrictEqual(pollTimes.slice(0, 4), [0, 200, 600, 700])
+    })
+
+    it('caps the backoff', async function () {
+        const pollTimes: number[] = []
+        poller = new LogTailPoller(
+            async () => {
+                pollTimes.push(Date.now())
+                throw new Error('offline')
+            },
+            { pollIntervalMillis: 10000 }
+        )
+
+        poller.start()
+        await clock.tickAsync(3 * TAIL_MAX_BACKOFF_MILLIS)
+
+        assert.deepStrictEqual(pollTimes.slice(0, 3), [0, 20000, 20000 + TAIL_MAX_BACKOFF_MILLIS])
+    })
+})
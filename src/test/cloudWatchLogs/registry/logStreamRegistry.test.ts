@@ -138,6 +138,21 @@ describe('LogStreamRegistry', async function () {
         })
     })
 
+    describe('getLogEventsInRange', function () {
+        it('gets the events shown on a range of lines', function () {
+            assert.deepStrictEqual(registry.getLogEventsInRange(registeredUri, 1, 2), [stream.data[1], stream.data[2]])
+            assert.deepStrictEqual(registry.getLogEventsInRange(registeredUri, 3, 10), [stream.data[3]])
+            assert.deepStrictEqual(registry.getLogEventsInRange(missingRegisteredUri, 0, 0), [])
+        })
+
+        it('counts the lines of events with newlines', function () {
+            map.set(newLineUri.path, { ...newLineStream, data: [...newLineStream.data, ...simplerStream.data] })
+
+            assert.deepStrictEqual(registry.getLogEventsInRange(newLineUri, 9, 9), [newLineStream.data[0]])
+            assert.deepStrictEqual(registry.getLogEventsInRange(newLineUri, 10, 10), [simplerStream.data[0]])
+        })
+    })
+
     describe('updateLog', async function () {
         it("adds content to existing streams at both head and tail ends and doesn't do anything if the log isn't registered", async () => {
             await registry.updateLog(shorterRegisteredUri, 'tail', config, getLogEventsFromUriComponentsFn)
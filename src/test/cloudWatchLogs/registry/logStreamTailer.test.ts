@@ -0,0 +1,105 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { CloudWatchLogs } from 'aws-sdk'
+import { CloudWatchLogStreamData, LogStreamRegistry } from '../../../cloudWatchLogs/registry/logStreamRegistry'
+import { LogStreamTailer } from '../../../cloudWatchLogs/registry/logStreamTailer'
+import { CLOUDWATCH_LOGS_SCHEME } from '../../../shared/constants'
+import { waitUntil } from '../../../shared/utilities/timeoutUtils'
+import { TestSettingsConfiguration } from '../../utilities/testSettingsConfiguration'
+
+describe('LogStreamTailer', function () {
+    const uri = vscode.Uri.parse(`${CLOUDWATCH_LOGS_SCHEME}:group:stream:us-west-2`)
+    const config = new TestSettingsConfiguration()
+
+    let registry: LogStreamRegistry
+    let tailer: LogStreamTailer | undefined
+    let calls: (string | undefined)[]
+
+    beforeEach(function () {
+        const map = new Map<string, CloudWatchLogStreamData>()
+        map.set(uri.path, { data: [{ message: 'first\n' }], next: { token: 'token0' }, busy: false })
+        registry = new LogStreamRegistry(config, map)
+        calls = []
+    })
+
+    afterEach(function () {
+        tailer?.dispose()
+    })
+
+    function makeTailer(
+        getLogEvents: (nextToken?: string) => Promise<CloudWatchLogs.GetLogEventsResponse>
+    ): LogStreamTailer {
+        tailer = new LogStreamTailer(
+            registry,
+            config,
+            async (_, nextToken) => {
+                calls.push(nextToken)
+                return getLogEvents(nextToken)
+            },
+            10
+        )
+        return tailer
+    }
+
+    it('appends new events using the forward token until stopped', async function () {
+        const t = makeTailer(async nextToken => ({
+            events: [{ message: `after ${nextToken}\n` }],
+            nextForwardToken: `${nextToken}+`,
+        }))
+
+        t.start(uri)
+        assert.strictEqual(t.isTailing(uri), true)
+        await waitUntil(async () => registry.getLogContent(uri)?.includes('after token0+'), {
+            timeout: 2000,
+            interval: 10,
+            truthy: true,
+        })
+        t.stop(uri)
+        const callCount = calls.length
+
+        assert.strictEqual(t.isTailing(uri), false)
+        assert.deepStrictEqual(calls.slice(0, 2), ['token0', 'token0+'])
+        assert.ok(registry.getLogContent(uri)?.startsWith('first\nafter token0\nafter token0+\n'))
+
+        await new Promise(resolve => setTimeout(resolve, 50))
+        assert.strictEqual(calls.length, callCount, 'no requests are made after stopping')
+    })
+
+    it('resumes from the same position after a failure', async function () {
+        let failed = false
+        const t = makeTailer(async nextToken => {
+            if (!failed) {
+                failed = true
+                throw new Error('session expired')
+            }
+            return nextToken === 'token0'
+                ? { events: [{ message: 'recovered\n' }], nextForwardToken: 'token1' }
+                : { events: [], nextForwardToken: nextToken }
+        })
+
+        t.start(uri)
+        await waitUntil(async () => registry.getLogContent(uri)?.includes('recovered'), {
+            timeout: 2000,
+            interval: 10,
+            truthy: true,
+        })
+        t.stop(uri)
+
+        assert.deepStrictEqual(calls.slice(0, 2), ['token0', 'token0'])
+        assert.strictEqual(registry.getLogContent(uri), 'first\nrecovered\n')
+    })
+
+    it('does not tail documents that are not registered', function () {
+        const t = makeTailer(async () => ({}))
+        const other = vscode.Uri.parse(`${CLOUDWATCH_LOGS_SCHEME}:group:other:us-west-2`)
+
+        t.start(other)
+
+        assert.strictEqual(t.isTailing(other), false)
+    })
+})
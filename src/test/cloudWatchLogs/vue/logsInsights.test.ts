@@ -0,0 +1,144 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudWatchLogs } from 'aws-sdk'
+import {
+    InsightsQueryRunner,
+    MAX_RECENT_QUERIES,
+    QueryResultsResponse,
+    RecentInsightsQueries,
+} from '../../../cloudWatchLogs/vue/logsInsights'
+import { FakeExtensionContext } from '../../fakeExtensionContext'
+import { MockCloudWatchLogsClient } from '../../shared/clients/mockClients'
+
+describe('InsightsQueryRunner', function () {
+    const request = { logGroupNames: ['group'], queryString: 'fields @message', startTime: 1, endTime: 2 }
+
+    let stoppedQueries: string[]
+    let responses: CloudWatchLogs.GetQueryResultsResponse[]
+    let reported: QueryResultsResponse['data'][]
+    let runner: InsightsQueryRunner
+
+    beforeEach(function () {
+        stoppedQueries = []
+        reported = []
+        responses = []
+        const client = new MockCloudWatchLogsClient(
+            'us-west-2',
+            undefined,
+            undefined,
+            undefined,
+            async () => ({ queryId: 'query' }),
+            async () => responses.shift() ?? { status: 'Complete' },
+            async queryId => {
+                stoppedQueries.push(queryId)
+
+                return { success: true }
+            }
+        )
+        runner = new InsightsQueryRunner(client, 0)
+    })
+
+    async function onResults(data: QueryResultsResponse['data']): Promise<void> {
+        reported.push(data)
+    }
+
+    it('polls until the query is complete', async function () {
+        responses = [
+            { status: 'Scheduled' },
+            { status: 'Running', results: [[{ field: '@message', value: 'first' }]] },
+            {
+                status: 'Complete',
+                results: [[{ field: '@message', value: 'first' }], [{ field: '@message', value: 'second' }]],
+                statistics: { recordsMatched: 2, recordsScanned: 10 },
+            },
+        ]
+
+        const status = await runner.run(request, onResults)
+
+        assert.strictEqual(status, 'Complete')
+        assert.deepStrictEqual(
+            reported.map(data => data.status),
+            ['Scheduled', 'Running', 'Complete']
+        )
+        assert.deepStrictEqual(reported[2].rows, [{ '@message': 'first' }, { '@message': 'second' }])
+        assert.strictEqual(reported[2].recordsMatched, 2)
+    })
+
+    it('completes with no rows when there is no data in range', async function () {
+        responses = [{ status: 'Complete', results: [] }]
+
+        assert.strictEqual(await runner.run(request, onResults), 'Complete')
+        assert.deepStrictEqual(reported, [
+            { status: 'Complete', columns: [], rows: [], recordsMatched: undefined, recordsScanned: undefined },
+        ])
+    })
+
+    it('stops polling when the query fails', async function () {
+        responses = [{ status: 'Failed' }, { status: 'Running' }]
+
+        assert.strictEqual(await runner.run(request, onResults), 'Failed')
+        assert.strictEqual(reported.length, 1)
+    })
+
+    it('stops the query when cancelled', async function () {
+        responses = [{ status: 'Running' }, { status: 'Running' }, { status: 'Running' }]
+
+        const status = await runner.run(request, async data => {
+            reported.push(data)
+            await runner.stop()
+        })
+
+        assert.strictEqual(status, 'Cancelled')
+        assert.deepStrictEqual(stoppedQueries, ['query'])
+        assert.deepStrictEqual(
+            reported.map(data => data.status),
+            ['Running', 'Cancelled']
+        )
+    })
+
+    it('does nothing when stopped before a query is started', async function () {
+        await runner.stop()
+
+        assert.deepStrictEqual(stoppedQueries, [])
+    })
+})
+
+describe('RecentInsightsQueries', function () {
+    let recentQueries: RecentInsightsQueries
+
+    beforeEach(async function () {
+        recentQueries = new RecentInsightsQueries((await FakeExtensionContext.getNew()).workspaceState)
+    })
+
+    it('keeps the most recent queries first, without duplicates', async function () {
+        await recentQueries.add({ queryString: 'first', logGroupNames: ['a', 'b'] })
+        await recentQueries.add({ queryString: 'second', logGroupNames: ['a'] })
+        await recentQueries.add({ queryString: 'first', logGroupNames: ['b', 'a'] })
+
+        assert.deepStrictEqual(recentQueries.get(), [
+            { queryString: 'first', logGroupNames: ['b', 'a'] },
+            { queryString: 'second', logGroupNames: ['a'] },
+        ])
+    })
+
+    it('keeps the same query for different log groups', async function () {
+        await recentQueries.add({ queryString: 'query', logGroupNames: ['a'] })
+        await recentQueries.add({ queryString: 'query', logGroupNames: ['b'] })
+
+        assert.strictEqual(recentQueries.get().length, 2)
+    })
+
+    it(`keeps at most ${MAX_RECENT_QUERIES} queries`, async function () {
+        for (let i = 0; i <= MAX_RECENT_QUERIES; i++) {
+            await recentQueries.add({ queryString: `query${i}`, logGroupNames: ['a'] })
+        }
+
+        const queries = recentQueries.get()
+        assert.strictEqual(queries.length, MAX_RECENT_QUERIES)
+        assert.strictEqual(queries[0].queryString, `query${MAX_RECENT_QUERIES}`)
+    })
+})
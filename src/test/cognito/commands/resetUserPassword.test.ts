@@ -0,0 +1,55 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { resetUserPassword } from '../../../cognito/commands/resetUserPassword'
+import { CognitoNode } from '../../../cognito/explorer/cognitoNode'
+import { CognitoUserNode } from '../../../cognito/explorer/cognitoUserNode'
+import { CognitoUserPoolNode } from '../../../cognito/explorer/cognitoUserPoolNode'
+import { MockCognitoClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('resetUserPassword', function () {
+    let passwords: { password: string; permanent: boolean }[]
+
+    beforeEach(function () {
+        passwords = []
+    })
+
+    function makeNode(): CognitoUserNode {
+        const cognito = new MockCognitoClient({
+            adminSetUserPassword: async (userPoolId, username, password, permanent) => {
+                passwords.push({ password, permanent })
+            },
+        })
+        const userPool = new CognitoUserPoolNode(new CognitoNode(cognito), cognito, {
+            Id: 'us-west-2_abc',
+            Name: 'customers',
+        })
+
+        return new CognitoUserNode(userPool, { Username: 'jane' })
+    }
+
+    it('sets a temporary password after confirming', async function () {
+        const window = new FakeWindow({
+            inputBox: { input: 'hunter2!' },
+            message: { warningSelection: 'Reset password' },
+        })
+
+        await resetUserPassword(makeNode(), window, new FakeCommands(), async ({ picker }) => [picker.items[0]])
+
+        assert.deepStrictEqual(passwords, [{ password: 'hunter2!', permanent: false }])
+        assert.strictEqual(window.message.information, 'Reset the password of user jane')
+    })
+
+    it('does not set the password when not confirmed', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'hunter2!' } })
+
+        await resetUserPassword(makeNode(), window, new FakeCommands(), async ({ picker }) => [picker.items[1]])
+
+        assert.deepStrictEqual(passwords, [])
+    })
+})
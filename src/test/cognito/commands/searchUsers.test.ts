@@ -0,0 +1,50 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { toUserFilter } from '../../../cognito/cognitoUsers'
+import { searchUsers } from '../../../cognito/commands/searchUsers'
+import { CognitoNode } from '../../../cognito/explorer/cognitoNode'
+import { CognitoUserPoolNode } from '../../../cognito/explorer/cognitoUserPoolNode'
+import { MockCognitoClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('searchUsers', function () {
+    function makeNode(): CognitoUserPoolNode {
+        const cognito = new MockCognitoClient({})
+
+        return new CognitoUserPoolNode(new CognitoNode(cognito), cognito, { Id: 'us-west-2_abc', Name: 'customers' })
+    }
+
+    it('searches emails, usernames, or filter expressions', function () {
+        assert.strictEqual(toUserFilter('jane@'), 'email ^= "jane@"')
+        assert.strictEqual(toUserFilter(' jane '), 'username ^= "jane"')
+        assert.strictEqual(toUserFilter('say "hi"'), 'username ^= "say \\"hi\\""')
+        assert.strictEqual(toUserFilter('phone_number ^= "+1"'), 'phone_number ^= "+1"')
+        assert.strictEqual(toUserFilter(''), undefined)
+    })
+
+    it('filters the users of the user pool and refreshes it', async function () {
+        const node = makeNode()
+        const commands = new FakeCommands()
+
+        await searchUsers(node, new FakeWindow({ inputBox: { input: 'jane@example.com' } }), commands)
+
+        assert.strictEqual(node.userFilter, 'email ^= "jane@example.com"')
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [node])
+    })
+
+    it('shows all users again when searching for nothing', async function () {
+        const node = makeNode()
+        node.setUserFilter('username ^= "jane"')
+
+        await searchUsers(node, new FakeWindow({ inputBox: { input: '' } }), new FakeCommands())
+
+        assert.strictEqual(node.userFilter, undefined)
+        assert.strictEqual(node.label, 'customers')
+    })
+})
@@ -0,0 +1,68 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { setUserEnabled } from '../../../cognito/commands/setUserEnabled'
+import { CognitoNode } from '../../../cognito/explorer/cognitoNode'
+import { CognitoUserNode } from '../../../cognito/explorer/cognitoUserNode'
+import { CognitoUserPoolNode } from '../../../cognito/explorer/cognitoUserPoolNode'
+import { MockCognitoClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('setUserEnabled', function () {
+    let disabledUsers: string[]
+
+    beforeEach(function () {
+        disabledUsers = []
+    })
+
+    function makeNode(
+        adminDisableUser = async (userPoolId: string, username: string) => {
+            disabledUsers.push(username)
+        }
+    ): CognitoUserNode {
+        const cognito = new MockCognitoClient({ adminDisableUser })
+        const userPool = new CognitoUserPoolNode(new CognitoNode(cognito), cognito, {
+            Id: 'us-west-2_abc',
+            Name: 'customers',
+        })
+
+        return new CognitoUserNode(userPool, { Username: 'jane', Enabled: true })
+    }
+
+    it('disables the user after confirming, and refreshes the user pool', async function () {
+        const node = makeNode()
+        const window = new FakeWindow({ message: { warningSelection: 'Disable' } })
+        const commands = new FakeCommands()
+
+        await setUserEnabled(node, false, window, commands)
+
+        assert.deepStrictEqual(disabledUsers, ['jane'])
+        assert.strictEqual(window.message.information, 'Disabled user jane')
+        assert.deepStrictEqual(commands.args, [node.parent])
+    })
+
+    it('does nothing when cancelled', async function () {
+        await setUserEnabled(makeNode(), false, new FakeWindow(), new FakeCommands())
+
+        assert.deepStrictEqual(disabledUsers, [])
+    })
+
+    it('shows a message and refreshes the user pool if the user was deleted', async function () {
+        const node = makeNode(async () => {
+            throw Object.assign(new Error('not found'), { code: 'UserNotFoundException' })
+        })
+        const window = new FakeWindow({ message: { warningSelection: 'Disable' } })
+        const commands = new FakeCommands()
+
+        await setUserEnabled(node, false, window, commands)
+
+        assert.strictEqual(window.message.warning, 'User jane no longer exists in customers')
+        assert.strictEqual(window.message.error, undefined)
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [node.parent])
+    })
+})
@@ -0,0 +1,100 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { MoreResultsNode } from '../../../awsexplorer/moreResultsNode'
+import { CognitoNode } from '../../../cognito/explorer/cognitoNode'
+import { CognitoUserNode } from '../../../cognito/explorer/cognitoUserNode'
+import { CognitoUserPoolNode } from '../../../cognito/explorer/cognitoUserPoolNode'
+import { ListUsersRequest, ListUsersResponse } from '../../../shared/clients/cognitoClient'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { MockCognitoClient } from '../../shared/clients/mockClients'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('CognitoUserPoolNode', function () {
+    const userPool = { Id: 'us-west-2_abc', Name: 'customers' }
+    let requests: ListUsersRequest[]
+
+    beforeEach(function () {
+        requests = []
+    })
+
+    function makeNode(...pages: ListUsersResponse[]): CognitoUserPoolNode {
+        const cognito = new MockCognitoClient({
+            listUsers: async request => {
+                requests.push(request)
+                return pages.shift() ?? { users: [] }
+            },
+        })
+
+        return new CognitoUserPoolNode(new CognitoNode(cognito), cognito, userPool)
+    }
+
+    it('lists user pools sorted by name', async function () {
+        const cognito = new MockCognitoClient({
+            listUserPools: () => asyncGenerator([{ Id: 'b', Name: 'staff' }, userPool]),
+        })
+
+        const children = await new CognitoNode(cognito).getChildren()
+
+        assert.deepStrictEqual(
+            children.map(node => node.label),
+            ['customers', 'staff']
+        )
+    })
+
+    it('loads users a page at a time', async function () {
+        const node = makeNode(
+            { users: [{ Username: 'jane' }], paginationToken: 'token' },
+            { users: [{ Username: 'john' }] }
+        )
+
+        const [first, more, ...others] = await node.getChildren()
+        assert.strictEqual(first.label, 'jane')
+        assert.ok(more instanceof MoreResultsNode)
+        assert.strictEqual(others.length, 0)
+
+        await node.loadMoreChildren()
+        const children = await node.getChildren()
+
+        assert.deepStrictEqual(
+            children.map(child => child.label),
+            ['jane', 'john']
+        )
+        assert.strictEqual(requests[1].paginationToken, 'token')
+    })
+
+    it('describes users by their email and status', async function () {
+        const node = makeNode({
+            users: [
+                {
+                    Username: 'jane',
+                    UserStatus: 'CONFIRMED',
+                    Enabled: true,
+                    Attributes: [{ Name: 'email', Value: 'jane@example.com' }],
+                },
+                { Username: 'john', UserStatus: 'FORCE_CHANGE_PASSWORD', Enabled: false },
+            ],
+        })
+
+        const [jane, john] = (await node.getChildren()) as CognitoUserNode[]
+
+        assert.strictEqual(jane.description, 'jane@example.com (CONFIRMED)')
+        assert.strictEqual(jane.contextValue, 'awsCognitoUserNode')
+        assert.strictEqual(john.description, 'FORCE_CHANGE_PASSWORD, disabled')
+        assert.strictEqual(john.contextValue, 'awsCognitoDisabledUserNode')
+    })
+
+    it('lists the users that match its filter', async function () {
+        const node = makeNode({ users: [] })
+
+        node.setUserFilter('email ^= "jane"')
+        const [placeholder] = await node.getChildren()
+
+        assert.strictEqual(requests[0].filter, 'email ^= "jane"')
+        assert.strictEqual(node.label, 'customers (filter: email ^= "jane")')
+        assert.strictEqual((placeholder as PlaceholderNode).label, '[No users match email ^= "jane"]')
+    })
+})
@@ -52,4 +52,12 @@ describe('updateCredentialsStatusBarItem', async function () {
             'expected statusbar item text to indicate that the profile connected'
         )
     })
+
+    it('shows when the credentials are expiring soon or expired', async function () {
+        await updateCredentialsStatusBarItem(statusBarItem, 'myprofile', 'expiringSoon')
+        assert.ok(statusBarItem.text.includes('myprofile (expiring soon)'))
+
+        await updateCredentialsStatusBarItem(statusBarItem, 'myprofile', 'expired')
+        assert.ok(statusBarItem.text.includes('myprofile (expired)'))
+    })
 })
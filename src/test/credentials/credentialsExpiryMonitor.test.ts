@@ -0,0 +1,122 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as FakeTimers from '@sinonjs/fake-timers'
+import { CredentialsExpiryMonitor, CredentialsExpiryState } from '../../credentials/credentialsExpiryMonitor'
+import { CredentialsId } from '../../credentials/providers/credentials'
+import { FakeWindow } from '../shared/vscode/fakeWindow'
+
+describe('CredentialsExpiryMonitor', function () {
+    const MINUTE_IN_MS = 60 * 1000
+    const credentialsId: CredentialsId = { credentialSource: 'profile', credentialTypeId: 'sso' }
+
+    let clock: FakeTimers.InstalledClock
+    let monitor: CredentialsExpiryMonitor
+    let states: CredentialsExpiryState[]
+    let reauthenticated: CredentialsId[]
+
+    function createMonitor(window: FakeWindow): CredentialsExpiryMonitor {
+        const newMonitor = new CredentialsExpiryMonitor(
+            async id => {
+                reauthenticated.push(id)
+            },
+            window,
+            10 * MINUTE_IN_MS
+        )
+        newMonitor.onDidChangeState(state => states.push(state))
+
+        return newMonitor
+    }
+
+    function expiresIn(ms: number): Date {
+        return new Date(Date.now() + ms)
+    }
+
+    beforeEach(function () {
+        clock = FakeTimers.install()
+        states = []
+        reauthenticated = []
+    })
+
+    afterEach(function () {
+        monitor.dispose()
+        clock.uninstall()
+    })
+
+    it('offers to re-authenticate shortly before the credentials expire', async function () {
+        const window = new FakeWindow({ message: { warningSelection: 'Re-authenticate' } })
+        monitor = createMonitor(window)
+
+        monitor.track({ credentialsId, expiration: expiresIn(60 * MINUTE_IN_MS) })
+        await clock.tickAsync(49 * MINUTE_IN_MS)
+        assert.strictEqual(monitor.state, 'valid')
+
+        await clock.tickAsync(MINUTE_IN_MS)
+        assert.strictEqual(monitor.state, 'expiringSoon')
+        assert.ok(window.message.warning?.includes('expire in 10 minute(s)'))
+        assert.deepStrictEqual(reauthenticated, [credentialsId])
+    })
+
+    it('reports expired credentials', async function () {
+        monitor = createMonitor(new FakeWindow())
+
+        monitor.track({ credentialsId, expiration: expiresIn(60 * MINUTE_IN_MS) })
+        await clock.tickAsync(60 * MINUTE_IN_MS)
+
+        assert.strictEqual(monitor.state, 'expired')
+        assert.deepStrictEqual(states, ['expiringSoon', 'expired'])
+    })
+
+    it('refreshes silently without prompting', async function () {
+        const window = new FakeWindow()
+        monitor = createMonitor(window)
+        let refreshes = 0
+        const credentials = {
+            credentialsId,
+            expiration: expiresIn(60 * MINUTE_IN_MS),
+            refresh: async () => {
+                refreshes++
+                monitor.track({ credentialsId, expiration: expiresIn(60 * MINUTE_IN_MS) })
+
+                return true
+            },
+        }
+
+        monitor.track(credentials)
+        await clock.tickAsync(60 * MINUTE_IN_MS)
+
+        assert.strictEqual(refreshes, 1)
+        assert.strictEqual(monitor.state, 'valid')
+        assert.strictEqual(window.message.warning, undefined)
+    })
+
+    it('prompts if the silent refresh fails', async function () {
+        const window = new FakeWindow()
+        monitor = createMonitor(window)
+
+        monitor.track({ credentialsId, expiration: expiresIn(60 * MINUTE_IN_MS), refresh: async () => false })
+        await clock.tickAsync(50 * MINUTE_IN_MS)
+
+        assert.strictEqual(monitor.state, 'expiringSoon')
+        assert.ok(window.message.warning?.includes('profile:sso'))
+        assert.deepStrictEqual(reauthenticated, [])
+    })
+
+    it('stops tracking when the credentials are replaced', async function () {
+        const window = new FakeWindow()
+        monitor = createMonitor(window)
+
+        monitor.track({ credentialsId, expiration: expiresIn(5 * MINUTE_IN_MS) })
+        await clock.tickAsync(0)
+        assert.strictEqual(monitor.state, 'expiringSoon')
+
+        monitor.track(undefined)
+        await clock.tickAsync(5 * MINUTE_IN_MS)
+
+        assert.strictEqual(monitor.state, 'valid')
+        assert.strictEqual(monitor.expiration, undefined)
+    })
+})
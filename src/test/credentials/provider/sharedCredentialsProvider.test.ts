@@ -6,7 +6,12 @@
 import * as assert from 'assert'
 import * as FakeTimers from '@sinonjs/fake-timers'
 import * as sinon from 'sinon'
-import { SharedCredentialsProvider } from '../../../credentials/providers/sharedCredentialsProvider'
+import * as credentialsCreator from '../../../credentials/credentialsCreator'
+import {
+    AssumedRoleCredentialsCache,
+    AssumeRoleFn,
+    SharedCredentialsProvider,
+} from '../../../credentials/providers/sharedCredentialsProvider'
 import { Profile } from '../../../shared/credentials/credentialsFile'
 import AWS = require('aws-sdk')
 import { tickPromise } from '../../testUtil'
@@ -253,6 +258,188 @@ describe('SharedCredentialsProvider', async function () {
 
         await tickPromise(assert.rejects(sut.getCredentials(), /expired/), clock, 10 * 60 * 1000)
     })
+
+    describe('source_profile chains', function () {
+        const profiles = new Map<string, Profile>([
+            ['profileA', { role_arn: 'roleA', source_profile: 'profileB' }],
+            ['profileB', { role_arn: 'roleB', source_profile: 'profileC', external_id: 'id' }],
+            ['profileC', { role_arn: 'roleC', source_profile: 'profileD' }],
+            ['profileD', { credential_process: 'get-credentials' }],
+        ])
+
+        let cache: AssumedRoleCredentialsCache
+        let assumedRoles: { roleArn: string; sourceAccessKeyId?: string; externalId?: string }[]
+        let expiration: Date
+
+        const assumeRole: AssumeRoleFn = async (request, sourceCredentials) => {
+            assumedRoles.push({
+                roleArn: request.RoleArn,
+                sourceAccessKeyId: sourceCredentials.accessKeyId,
+                ...(request.ExternalId ? { externalId: request.ExternalId } : {}),
+            })
+            return {
+                Credentials: {
+                    AccessKeyId: `${request.RoleArn}-key`,
+                    SecretAccessKey: 'secret',
+                    SessionToken: 'token',
+                    Expiration: expiration,
+                },
+            }
+        }
+
+        beforeEach(function () {
+            cache = new AssumedRoleCredentialsCache()
+            assumedRoles = []
+            expiration = new Date(Date.now() + 60 * 60 * 1000)
+            // Stands in for the credential_process at the root of the chain
+            sandbox
+                .stub(AWS.CredentialProviderChain.prototype, 'resolvePromise')
+                .resolves(new AWS.Credentials({ accessKeyId: 'process-key', secretAccessKey: 'secret' }))
+        })
+
+        it('assumes each role in the chain using the credentials of its source profile', async function () {
+            const sut = new SharedCredentialsProvider('profileA', profiles, cache, assumeRole)
+
+            const credentials = await sut.getCredentials()
+
+            assert.strictEqual(credentials.accessKeyId, 'roleA-key')
+            assert.deepStrictEqual(assumedRoles, [
+                { roleArn: 'roleC', sourceAccessKeyId: 'process-key' },
+                { roleArn: 'roleB', sourceAccessKeyId: 'roleC-key', externalId: 'id' },
+                { roleArn: 'roleA', sourceAccessKeyId: 'roleB-key' },
+            ])
+        })
+
+        it('reuses cached credentials of intermediate profiles until they expire', async function () {
+            await new SharedCredentialsProvider('profileB', profiles, cache, assumeRole).getCredentials()
+            assumedRoles = []
+
+            await new SharedCredentialsProvider('profileA', profiles, cache, assumeRole).getCredentials()
+            assert.deepStrictEqual(
+                assumedRoles.map(role => role.roleArn),
+                ['roleA']
+            )
+
+            clock.tick(60 * 60 * 1000)
+            assumedRoles = []
+            await new SharedCredentialsProvider('profileA', profiles, cache, assumeRole).getCredentials()
+            assert.deepStrictEqual(
+                assumedRoles.map(role => role.roleArn),
+                ['roleC', 'roleB', 'roleA']
+            )
+        })
+
+        it('fails with the reference chain when the chain contains a cycle', async function () {
+            const sut = new SharedCredentialsProvider(
+                'profileA',
+                new Map<string, Profile>([
+                    ['profileA', { role_arn: 'roleA', source_profile: 'profileB' }],
+                    ['profileB', { role_arn: 'roleB', source_profile: 'profileA' }],
+                ]),
+                cache,
+                assumeRole
+            )
+
+            await assert.rejects(sut.getCredentials(), /Cycle detected.*profileA -> profileB -> profileA/)
+            assert.strictEqual(assumedRoles.length, 0)
+        })
+
+        it('fails when a source profile does not exist', async function () {
+            const sut = new SharedCredentialsProvider(
+                'profileA',
+                new Map<string, Profile>([['profileA', { role_arn: 'roleA', source_profile: 'missing' }]]),
+                cache,
+                assumeRole
+            )
+
+            await assert.rejects(sut.getCredentials(), /missing not found. Reference chain: profileA -> missing/)
+        })
+    })
+
+    describe('mfa_serial', function () {
+        const mfaSerial = 'arn:aws:iam::123456789012:mfa/user'
+        const profiles = new Map<string, Profile>([
+            ['mfa', { role_arn: 'roleM', source_profile: 'base', mfa_serial: mfaSerial }],
+            ['base', { credential_process: 'get-credentials' }],
+        ])
+
+        let cache: AssumedRoleCredentialsCache
+        let tokenCodes: (string | undefined)[]
+        let validCode: string
+        let promptStub: sinon.SinonStub
+
+        const assumeRole: AssumeRoleFn = async request => {
+            tokenCodes.push(request.TokenCode)
+            assert.strictEqual(request.SerialNumber, mfaSerial)
+            if (request.TokenCode !== validCode) {
+                throw Object.assign(
+                    new Error('MultiFactorAuthentication failed with invalid MFA one time pass code.'),
+                    { code: 'AccessDenied' }
+                )
+            }
+            return {
+                Credentials: {
+                    AccessKeyId: 'roleM-key',
+                    SecretAccessKey: 'secret',
+                    SessionToken: 'token',
+                    Expiration: new Date(Date.now() + 60 * 60 * 1000),
+                },
+            }
+        }
+
+        beforeEach(function () {
+            cache = new AssumedRoleCredentialsCache()
+            tokenCodes = []
+            validCode = '123456'
+            sandbox
+                .stub(AWS.CredentialProviderChain.prototype, 'resolvePromise')
+                .resolves(new AWS.Credentials({ accessKeyId: 'process-key', secretAccessKey: 'secret' }))
+            promptStub = sandbox.stub(credentialsCreator, 'promptForMfaToken')
+        })
+
+        it('assumes the role with the entered code, and reuses the session until it expires', async function () {
+            promptStub.resolves('123456')
+
+            const credentials = await new SharedCredentialsProvider('mfa', profiles, cache, assumeRole).getCredentials()
+            await new SharedCredentialsProvider('mfa', profiles, cache, assumeRole).getCredentials()
+
+            assert.strictEqual(credentials.accessKeyId, 'roleM-key')
+            assert.deepStrictEqual(tokenCodes, ['123456'])
+            assert.strictEqual(promptStub.callCount, 1)
+            assert.strictEqual(promptStub.firstCall.args[0], mfaSerial)
+            assert.strictEqual(promptStub.firstCall.args[2].invalidCode, false)
+        })
+
+        it('prompts again once when the code is rejected', async function () {
+            promptStub.onFirstCall().resolves('000000').onSecondCall().resolves('123456')
+
+            const credentials = await new SharedCredentialsProvider('mfa', profiles, cache, assumeRole).getCredentials()
+
+            assert.strictEqual(credentials.accessKeyId, 'roleM-key')
+            assert.deepStrictEqual(tokenCodes, ['000000', '123456'])
+            assert.strictEqual(promptStub.secondCall.args[2].invalidCode, true)
+        })
+
+        it('fails with an invalid MFA code message when the code is rejected again', async function () {
+            promptStub.resolves('000000')
+
+            await assert.rejects(
+                new SharedCredentialsProvider('mfa', profiles, cache, assumeRole).getCredentials(),
+                /invalid MFA code/
+            )
+            assert.strictEqual(promptStub.callCount, 2)
+        })
+
+        it('does not assume the role when the user cancels the prompt', async function () {
+            promptStub.rejects(new Error('User cancelled entering authentication code'))
+
+            await assert.rejects(
+                new SharedCredentialsProvider('mfa', profiles, cache, assumeRole).getCredentials(),
+                /cancelled/
+            )
+            assert.deepStrictEqual(tokenCodes, [])
+        })
+    })
 })
 
 function assertSubstringsInText(text: string | undefined, ...substrings: string[]) {
@@ -214,6 +214,52 @@ describe('SsoAccessTokenProvider', function () {
         })
     })
 
+    describe('refreshAccessToken', function () {
+        const expiredAccessToken = {
+            ...validAccessToken,
+            expiresAt: new Date(Date.now() - HOUR_IN_MS).toISOString(),
+            refreshToken: 'dummyRefreshToken',
+        }
+
+        it('exchanges the refresh token of an expired token for a new token', async function () {
+            sandbox.stub(cache, 'loadAccessToken').returns(expiredAccessToken)
+            sandbox.stub(cache, 'loadClientRegistration').returns(validRegistation)
+            const stubCreateToken = sandbox.stub(ssoOidcClient, 'createToken').returns(({
+                promise: sandbox.stub().resolves({ accessToken: 'refreshedAccessToken', expiresIn: 120 }),
+            } as any) as SDK.Request<CreateTokenResponse, SDK.AWSError>)
+            const stubSaveAccessToken = sandbox.stub(cache, 'saveAccessToken').returns()
+
+            const receivedToken = await sut.refreshAccessToken()
+
+            assert.strictEqual(receivedToken?.accessToken, 'refreshedAccessToken')
+            assert.strictEqual(receivedToken?.refreshToken, 'dummyRefreshToken')
+            assert.strictEqual(stubCreateToken.firstCall.args[0].grantType, 'refresh_token')
+            assert.strictEqual(stubCreateToken.firstCall.args[0].refreshToken, 'dummyRefreshToken')
+            assert.strictEqual(stubSaveAccessToken.calledOnce, true)
+        })
+
+        it('returns undefined without a refresh token', async function () {
+            sandbox.stub(cache, 'loadAccessToken').returns({ ...expiredAccessToken, refreshToken: undefined })
+            sandbox.stub(cache, 'loadClientRegistration').returns(validRegistation)
+            const stubCreateToken = sandbox.stub(ssoOidcClient, 'createToken')
+
+            assert.strictEqual(await sut.refreshAccessToken(), undefined)
+            assert.strictEqual(stubCreateToken.called, false)
+        })
+
+        it('returns undefined if the service rejects the refresh token', async function () {
+            sandbox.stub(cache, 'loadAccessToken').returns(expiredAccessToken)
+            sandbox.stub(cache, 'loadClientRegistration').returns(validRegistation)
+            sandbox.stub(ssoOidcClient, 'createToken').returns(({
+                promise: sandbox.stub().throws({ code: 'InvalidGrantException' }),
+            } as any) as SDK.Request<CreateTokenResponse, SDK.AWSError>)
+            const stubSaveAccessToken = sandbox.stub(cache, 'saveAccessToken').returns()
+
+            assert.strictEqual(await sut.refreshAccessToken(), undefined)
+            assert.strictEqual(stubSaveAccessToken.called, false)
+        })
+    })
+
     describe('authorizeClient', function () {
         it('removes the client registration cache on InvalidClientException', async function () {
             const errToThrow = new Error() as SDK.AWSError
@@ -0,0 +1,48 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { DynamoDbNode } from '../../../dynamoDb/explorer/dynamoDbNode'
+import { DynamoDbTableNode } from '../../../dynamoDb/explorer/dynamoDbTableNode'
+import { ErrorNode } from '../../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import { MockDynamoDbClient } from '../../shared/clients/mockClients'
+
+describe('DynamoDbNode', function () {
+    it('gets children and sorts them by table name', async function () {
+        const dynamoDb = new MockDynamoDbClient({ listTables: () => asyncGenerator(['orders', 'customers']) })
+
+        const [firstNode, secondNode, ...otherNodes] = await new DynamoDbNode(dynamoDb).getChildren()
+
+        assert.strictEqual((firstNode as DynamoDbTableNode).tableName, 'customers')
+        assert.strictEqual(firstNode.label, 'customers')
+        assert.strictEqual((secondNode as DynamoDbTableNode).tableName, 'orders')
+        assert.deepStrictEqual(firstNode.command?.arguments, [firstNode])
+        assert.strictEqual(otherNodes.length, 0)
+    })
+
+    it('shows placeholder node when there are no tables', async function () {
+        const [firstNode, ...otherNodes] = await new DynamoDbNode(new MockDynamoDbClient({})).getChildren()
+
+        assert.strictEqual((firstNode as PlaceholderNode).label, '[No tables found]')
+        assert.strictEqual(otherNodes.length, 0)
+    })
+
+    it('shows error node when listing tables fails', async function () {
+        const dynamoDb = new MockDynamoDbClient({
+            listTables: async function* () {
+                throw new Error('network super busted')
+                // at least one yield is required for async generator even if it is unreachable
+                yield ''
+            },
+        })
+
+        const [firstNode, ...otherNodes] = await new DynamoDbNode(dynamoDb).getChildren()
+
+        assert.ok(firstNode instanceof ErrorNode)
+        assert.strictEqual(otherNodes.length, 0)
+    })
+})
@@ -0,0 +1,159 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import {
+    buildQueryInput,
+    buildScanInput,
+    formatAttributeValue,
+    getKeySchema,
+    TableKeySchema,
+    toItemGridPage,
+} from '../../dynamoDb/utils'
+
+describe('DynamoDB utils', function () {
+    const keySchema: TableKeySchema = {
+        partitionKey: { name: 'customer', type: 'S' },
+        sortKey: { name: 'orderDate', type: 'N' },
+    }
+
+    describe('getKeySchema', function () {
+        it('reads the key names and types', function () {
+            const schema = getKeySchema({
+                TableName: 'orders',
+                KeySchema: [
+                    { AttributeName: 'orderDate', KeyType: 'RANGE' },
+                    { AttributeName: 'customer', KeyType: 'HASH' },
+                ],
+                AttributeDefinitions: [
+                    { AttributeName: 'customer', AttributeType: 'S' },
+                    { AttributeName: 'orderDate', AttributeType: 'N' },
+                ],
+            })
+
+            assert.deepStrictEqual(schema, keySchema)
+        })
+
+        it('omits the sort key for tables that only have a partition key', function () {
+            const schema = getKeySchema({
+                KeySchema: [{ AttributeName: 'id', KeyType: 'HASH' }],
+                AttributeDefinitions: [{ AttributeName: 'id', AttributeType: 'B' }],
+            })
+
+            assert.deepStrictEqual(schema, { partitionKey: { name: 'id', type: 'B' }, sortKey: undefined })
+        })
+    })
+
+    describe('formatAttributeValue', function () {
+        it('formats scalar values', function () {
+            assert.strictEqual(formatAttributeValue({ S: 'text' }), 'text')
+            assert.strictEqual(formatAttributeValue({ N: '1.5' }), '1.5')
+            assert.strictEqual(formatAttributeValue({ BOOL: false }), 'false')
+            assert.strictEqual(formatAttributeValue({ NULL: true }), 'null')
+        })
+
+        it('formats binary values and sets', function () {
+            assert.strictEqual(formatAttributeValue({ B: Buffer.from('hi') }), '<binary> aGk=')
+            assert.strictEqual(formatAttributeValue({ SS: ['a', 'b'] }), '<string set> [a, b]')
+            assert.strictEqual(formatAttributeValue({ NS: ['1', '2'] }), '<number set> [1, 2]')
+            assert.strictEqual(
+                formatAttributeValue({ BS: [Buffer.from('hi'), Buffer.from('yo')] }),
+                '<binary set> [aGk=, eW8=]'
+            )
+        })
+
+        it('formats lists and maps as JSON', function () {
+            const value = { M: { tags: { L: [{ S: 'a' }, { N: '2' }] }, data: { B: Buffer.from('hi') } } }
+
+            assert.strictEqual(formatAttributeValue(value), '{"tags":["a",2],"data":"<binary> aGk="}')
+        })
+    })
+
+    describe('toItemGridPage', function () {
+        it('shows key columns first and formats each item', function () {
+            const page = toItemGridPage(
+                [
+                    { status: { S: 'shipped' }, customer: { S: 'bob' }, orderDate: { N: '2' } },
+                    { customer: { S: 'alice' }, orderDate: { N: '1' }, amount: { N: '10' } },
+                ],
+                keySchema
+            )
+
+            assert.deepStrictEqual(page.columns, ['customer', 'orderDate', 'amount', 'status'])
+            assert.deepStrictEqual(page.rows[1], { customer: 'alice', orderDate: '1', amount: '10' })
+        })
+    })
+
+    describe('buildScanInput', function () {
+        it('uses placeholders for projected attributes', function () {
+            const input = buildScanInput('orders', { projectionExpression: 'customer, info.status, tags[0]' }, 50)
+
+            assert.deepStrictEqual(input, {
+                TableName: 'orders',
+                Limit: 50,
+                ProjectionExpression: '#p0, #p1.#p2, #p3[0]',
+                ExpressionAttributeNames: { '#p0': 'customer', '#p1': 'info', '#p2': 'status', '#p3': 'tags' },
+            })
+        })
+    })
+
+    describe('buildQueryInput', function () {
+        it('queries by partition key', function () {
+            const input = buildQueryInput('orders', keySchema, { partitionKeyValue: 'alice' })
+
+            assert.strictEqual(input.KeyConditionExpression, '#pk = :pk')
+            assert.deepStrictEqual(input.ExpressionAttributeNames, { '#pk': 'customer' })
+            assert.deepStrictEqual(input.ExpressionAttributeValues, { ':pk': { S: 'alice' } })
+        })
+
+        it('adds sort key conditions using the key type', function () {
+            const input = buildQueryInput('orders', keySchema, {
+                partitionKeyValue: 'alice',
+                sortKeyCondition: { operator: 'between', value: '1', value2: '5' },
+            })
+
+            assert.strictEqual(input.KeyConditionExpression, '#pk = :pk AND #sk BETWEEN :sk AND :sk2')
+            assert.deepStrictEqual(input.ExpressionAttributeValues, {
+                ':pk': { S: 'alice' },
+                ':sk': { N: '1' },
+                ':sk2': { N: '5' },
+            })
+        })
+
+        it('supports begins_with', function () {
+            const schema: TableKeySchema = {
+                partitionKey: { name: 'id', type: 'S' },
+                sortKey: { name: 'sk', type: 'S' },
+            }
+            const input = buildQueryInput('orders', schema, {
+                partitionKeyValue: 'a',
+                sortKeyCondition: { operator: 'begins_with', value: 'ORDER#' },
+            })
+
+            assert.strictEqual(input.KeyConditionExpression, '#pk = :pk AND begins_with(#sk, :sk)')
+        })
+
+        it('ignores an empty sort key condition', function () {
+            const input = buildQueryInput('orders', keySchema, {
+                partitionKeyValue: 'alice',
+                sortKeyCondition: { operator: '=', value: '' },
+            })
+
+            assert.strictEqual(input.KeyConditionExpression, '#pk = :pk')
+        })
+
+        it('rejects missing partition keys and invalid numbers', function () {
+            assert.throws(() => buildQueryInput('orders', keySchema, {}), /partition key customer is required/)
+            assert.throws(
+                () =>
+                    buildQueryInput('orders', keySchema, {
+                        partitionKeyValue: 'alice',
+                        sortKeyCondition: { operator: '>', value: 'abc' },
+                    }),
+                /must be a number/
+            )
+        })
+    })
+})
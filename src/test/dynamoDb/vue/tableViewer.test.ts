@@ -0,0 +1,70 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { DynamoDB } from 'aws-sdk'
+import { TableItemReader } from '../../../dynamoDb/vue/tableViewer'
+import { MockDynamoDbClient } from '../../shared/clients/mockClients'
+
+describe('TableItemReader', function () {
+    const keySchema = { partitionKey: { name: 'id', type: 'S' as const } }
+
+    let requests: DynamoDB.ScanInput[]
+    let reader: TableItemReader
+
+    beforeEach(function () {
+        requests = []
+        const dynamoDb = new MockDynamoDbClient({
+            scan: async request => {
+                requests.push(request)
+                const page = Number(request.ExclusiveStartKey?.id.S ?? '0')
+
+                return {
+                    Items: [{ id: { S: `item${page}` } }],
+                    LastEvaluatedKey: page < 2 ? { id: { S: `${page + 1}` } } : undefined,
+                }
+            },
+        })
+        reader = new TableItemReader(dynamoDb, 'table', keySchema, 1)
+    })
+
+    it('pages forwards and backwards through scan results', async function () {
+        const first = await reader.read('scan', {}, 'first')
+        assert.deepStrictEqual(first.rows, [{ id: 'item0' }])
+        assert.strictEqual(first.hasPreviousPage, false)
+        assert.strictEqual(first.hasNextPage, true)
+
+        await reader.read('scan', {}, 'next')
+        const third = await reader.read('scan', {}, 'next')
+        assert.deepStrictEqual(third.rows, [{ id: 'item2' }])
+        assert.strictEqual(third.pageNumber, 3)
+        assert.strictEqual(third.hasNextPage, false)
+
+        const second = await reader.read('scan', {}, 'previous')
+        assert.deepStrictEqual(second.rows, [{ id: 'item1' }])
+        assert.strictEqual(second.pageNumber, 2)
+        assert.strictEqual(second.hasPreviousPage, true)
+        assert.deepStrictEqual(requests[3].ExclusiveStartKey, { id: { S: '1' } })
+    })
+
+    it('starts over from the first page', async function () {
+        await reader.read('scan', {}, 'first')
+        await reader.read('scan', {}, 'next')
+        const page = await reader.read('scan', { projectionExpression: 'id' }, 'first')
+
+        assert.strictEqual(page.pageNumber, 1)
+        assert.strictEqual(requests[2].ExclusiveStartKey, undefined)
+        assert.strictEqual(requests[2].ProjectionExpression, '#p0')
+    })
+
+    it('does not move when the query input is invalid', async function () {
+        await reader.read('scan', {}, 'first')
+
+        await assert.rejects(reader.read('query', {}, 'next'), /partition key id is required/)
+        const page = await reader.read('scan', {}, 'next')
+
+        assert.strictEqual(page.pageNumber, 2)
+    })
+})
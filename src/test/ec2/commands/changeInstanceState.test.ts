@@ -0,0 +1,128 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { EC2 } from 'aws-sdk'
+import { rebootInstance, startInstance, stopInstance } from '../../../ec2/commands/changeInstanceState'
+import { Ec2InstanceNode } from '../../../ec2/explorer/ec2InstanceNode'
+import { Ec2Node } from '../../../ec2/explorer/ec2Node'
+import { MockEc2Client } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('changeInstanceState', function () {
+    const instanceId = 'i-0123'
+
+    let calls: string[]
+    let describeCalls: number
+
+    function makeInstance(state: string, tags: EC2.Tag[] = []): EC2.Instance {
+        return { InstanceId: instanceId, State: { Name: state }, Tags: [{ Key: 'Name', Value: 'web' }, ...tags] }
+    }
+
+    function makeNode(initialState: string, states: string[], tags?: EC2.Tag[]): Ec2InstanceNode {
+        const ec2 = new MockEc2Client({
+            getInstance: async () => makeInstance(states[Math.min(describeCalls++, states.length - 1)], tags),
+            startInstance: async id => {
+                calls.push(`start ${id}`)
+            },
+            stopInstance: async id => {
+                calls.push(`stop ${id}`)
+            },
+            rebootInstance: async id => {
+                calls.push(`reboot ${id}`)
+            },
+        })
+
+        return new Ec2InstanceNode({} as Ec2Node, ec2, makeInstance(initialState, tags))
+    }
+
+    beforeEach(function () {
+        calls = []
+        describeCalls = 0
+    })
+
+    it('starts an instance and polls until it is running', async function () {
+        const node = makeNode('stopped', ['pending', 'pending', 'running'])
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await startInstance(node, { window, commands, pollIntervalMs: 1 })
+
+        assert.deepStrictEqual(calls, [`start ${instanceId}`])
+        assert.strictEqual(describeCalls, 3)
+        assert.deepStrictEqual(
+            window.progress.reported.map(item => item.message),
+            ['pending', 'pending', 'running']
+        )
+        assert.strictEqual(node.description, 'running')
+        assert.strictEqual(node.contextValue, 'awsEc2RunningNode')
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.strictEqual(window.message.information, 'Instance web is running')
+    })
+
+    it('stops an instance after confirmation and polls until it is stopped', async function () {
+        const node = makeNode('running', ['stopping', 'stopped'])
+        const window = new FakeWindow({ message: { warningSelection: 'Stop' } })
+
+        await stopInstance(node, { window, commands: new FakeCommands(), pollIntervalMs: 1 })
+
+        assert.strictEqual(window.message.warning, `Are you sure you want to stop instance web (${instanceId})?`)
+        assert.deepStrictEqual(calls, [`stop ${instanceId}`])
+        assert.strictEqual(node.description, 'stopped')
+        assert.strictEqual(node.contextValue, 'awsEc2StoppedNode')
+    })
+
+    it('does not stop an instance without confirmation', async function () {
+        const node = makeNode('running', ['stopped'])
+
+        await stopInstance(node, { window: new FakeWindow(), commands: new FakeCommands() })
+
+        assert.deepStrictEqual(calls, [])
+        assert.strictEqual(node.description, 'running')
+    })
+
+    it('warns that the Auto Scaling group may replace the instance', async function () {
+        const node = makeNode('running', ['running'], [{ Key: 'aws:autoscaling:groupName', Value: 'my-asg' }])
+        const window = new FakeWindow({ message: { warningSelection: 'Reboot' } })
+
+        await rebootInstance(node, window)
+
+        assert.ok(window.message.warning?.includes('Auto Scaling group my-asg'))
+        assert.deepStrictEqual(calls, [`reboot ${instanceId}`])
+        assert.strictEqual(window.message.information, 'Rebooting instance web')
+    })
+
+    it('fails if the instance is terminated while waiting', async function () {
+        const node = makeNode('running', ['stopping', 'terminated'])
+        const window = new FakeWindow({ message: { warningSelection: 'Stop' } })
+
+        await stopInstance(node, { window, commands: new FakeCommands(), pollIntervalMs: 1 })
+
+        assert.strictEqual(node.description, 'terminated')
+        assert.ok(window.message.error?.includes(`instance ${instanceId} is terminated`))
+    })
+
+    it('stops polling when cancelled', async function () {
+        const node = makeNode('stopped', ['pending'])
+        const window = new FakeWindow({ progress: { cancel: true } })
+
+        await startInstance(node, { window, commands: new FakeCommands(), pollIntervalMs: 1 })
+
+        assert.deepStrictEqual(calls, [`start ${instanceId}`])
+        assert.strictEqual(describeCalls, 0)
+        assert.strictEqual(window.message.error, undefined)
+    })
+
+    it('times out if the instance does not reach the state', async function () {
+        const node = makeNode('stopped', ['pending'])
+        const window = new FakeWindow()
+
+        await startInstance(node, { window, commands: new FakeCommands(), pollIntervalMs: 1, timeoutMs: 0 })
+
+        assert.strictEqual(node.description, 'pending')
+        assert.ok(window.message.error?.includes('did not reach the running state within 0 seconds'))
+    })
+})
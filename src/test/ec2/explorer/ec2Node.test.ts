@@ -0,0 +1,110 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { EC2 } from 'aws-sdk'
+import { Ec2InstanceNode } from '../../../ec2/explorer/ec2InstanceNode'
+import { Ec2Node } from '../../../ec2/explorer/ec2Node'
+import { ErrorNode } from '../../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { MockEc2Client, MockSsmDocumentClient } from '../../shared/clients/mockClients'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+function makeInstance(instanceId: string, state: string, name?: string): EC2.Instance {
+    return { InstanceId: instanceId, State: { Name: state }, Tags: name ? [{ Key: 'Name', Value: name }] : [] }
+}
+
+describe('Ec2Node', function () {
+    const instances = [
+        makeInstance('i-1', 'running', 'web'),
+        makeInstance('i-2', 'running', 'api'),
+        makeInstance('i-3', 'stopped'),
+    ]
+
+    function makeSsm(
+        getOnlineManagedInstanceIds: (instanceIds: string[]) => Promise<Set<string>>
+    ): MockSsmDocumentClient {
+        return { ...new MockSsmDocumentClient(), getOnlineManagedInstanceIds }
+    }
+
+    it('Gets instances, sorts them by name, and sets their context by state and SSM status', async function () {
+        let requestedIds: string[] = []
+        const ssm = makeSsm(async instanceIds => {
+            requestedIds = instanceIds
+            return new Set(['i-1'])
+        })
+        const ec2 = new MockEc2Client({ describeInstances: () => asyncGenerator(instances) })
+
+        const children = (await new Ec2Node(ec2, ssm).getChildren()) as Ec2InstanceNode[]
+
+        assert.deepStrictEqual(requestedIds, ['i-1', 'i-2'])
+        assert.deepStrictEqual(
+            children.map(node => [node.label, node.description, node.contextValue]),
+            [
+                ['api', 'running', 'awsEc2RunningNode'],
+                ['i-3', 'stopped', 'awsEc2StoppedNode'],
+                ['web', 'running', 'awsEc2RunningSsmNode'],
+            ]
+        )
+    })
+
+    it('Shows instances when SSM managed instances cannot be listed', async function () {
+        const ssm = makeSsm(async () => {
+            throw new Error('AccessDenied')
+        })
+        const ec2 = new MockEc2Client({ describeInstances: () => asyncGenerator(instances) })
+
+        const children = (await new Ec2Node(ec2, ssm).getChildren()) as Ec2InstanceNode[]
+
+        assert.strictEqual(children.length, 3)
+        assert.ok(children.every(node => !node.ssmManaged))
+    })
+
+    it('Shows empty node on no children', async function () {
+        const node = new Ec2Node(new MockEc2Client({}), new MockSsmDocumentClient())
+
+        const [firstNode, ...otherNodes] = await node.getChildren()
+
+        assert.strictEqual((firstNode as PlaceholderNode).label, '[No instances found]')
+        assert.strictEqual(otherNodes.length, 0)
+    })
+
+    it('Shows error node when getting children fails', async function () {
+        const ec2 = new MockEc2Client({
+            describeInstances: async function* () {
+                throw Error('network super busted')
+                // at least one yield is required for async generator even if it is unreachable
+                yield {}
+            },
+        })
+
+        const [firstNode] = await new Ec2Node(ec2, new MockSsmDocumentClient()).getChildren()
+
+        assert.ok(firstNode instanceof ErrorNode)
+    })
+})
+
+describe('Ec2InstanceNode', function () {
+    it('Reads the Auto Scaling group from the instance tags', function () {
+        const instance = makeInstance('i-1', 'running')
+        instance.Tags = [{ Key: 'aws:autoscaling:groupName', Value: 'my-asg' }]
+
+        const node = new Ec2InstanceNode({} as Ec2Node, new MockEc2Client({}), instance)
+
+        assert.strictEqual(node.autoScalingGroup, 'my-asg')
+        assert.strictEqual(node.label, 'i-1')
+    })
+
+    it('Is no longer SSM managed once it leaves the running state', function () {
+        const node = new Ec2InstanceNode({} as Ec2Node, new MockEc2Client({}), makeInstance('i-1', 'running'), true)
+        assert.strictEqual(node.contextValue, 'awsEc2RunningSsmNode')
+
+        node.update(makeInstance('i-1', 'stopping'))
+        assert.strictEqual(node.contextValue, 'awsEc2InstanceNode')
+
+        node.update(makeInstance('i-1', 'running'))
+        assert.strictEqual(node.contextValue, 'awsEc2RunningNode')
+    })
+})
@@ -0,0 +1,68 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { pullImage } from '../../../ecr/commands/pullImage'
+import { EcrRepositoryNode } from '../../../ecr/explorer/ecrRepositoryNode'
+import { EcrTagNode } from '../../../ecr/explorer/ecrTagNode'
+import { MockEcrClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('pullImage', function () {
+    const repository = {
+        repositoryName: 'my-repo',
+        repositoryArn: 'arn',
+        repositoryUri: '123456789012.dkr.ecr.us-west-2.amazonaws.com/my-repo',
+    }
+    const node = new EcrTagNode(
+        {} as EcrRepositoryNode,
+        new MockEcrClient({
+            getAuthorizationToken: async () => ({
+                username: 'AWS',
+                password: 'secret',
+                proxyEndpoint: 'https://registry',
+            }),
+        }),
+        repository,
+        'v1'
+    )
+
+    it('logs in and pulls the tag in a terminal', async function () {
+        const dockerCalls: string[][] = []
+        const terminalOptions: vscode.TerminalOptions[] = []
+
+        await pullImage(node, {
+            window: new FakeWindow({}),
+            run: async args => {
+                dockerCalls.push(args)
+
+                return { exitCode: 0, error: undefined, stdout: '', stderr: '' }
+            },
+            createTerminal: options => {
+                terminalOptions.push(options)
+
+                return ({ show: () => {} } as any) as vscode.Terminal
+            },
+        })
+
+        assert.deepStrictEqual(dockerCalls[1], ['login', '--username', 'AWS', '--password-stdin', 'https://registry'])
+        assert.deepStrictEqual(terminalOptions[0].shellArgs, ['pull', `${repository.repositoryUri}:v1`])
+    })
+
+    it('shows a clear error when Docker is not installed', async function () {
+        const window = new FakeWindow({})
+
+        await pullImage(node, {
+            window,
+            run: async () => ({ exitCode: -1, error: new Error('spawn docker ENOENT'), stdout: '', stderr: '' }),
+        })
+
+        assert.strictEqual(
+            window.message.error,
+            'Docker was not found. Install Docker and make sure "docker" is on your PATH.'
+        )
+    })
+})
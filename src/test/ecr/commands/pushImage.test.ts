@@ -0,0 +1,99 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { pushImage } from '../../../ecr/commands/pushImage'
+import { DockerRunner } from '../../../ecr/docker'
+import { EcrNode } from '../../../ecr/explorer/ecrNode'
+import { EcrRepositoryNode } from '../../../ecr/explorer/ecrRepositoryNode'
+import { MockEcrClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('pushImage', function () {
+    const repository = {
+        repositoryName: 'my-repo',
+        repositoryArn: 'arn',
+        repositoryUri: '123456789012.dkr.ecr.us-west-2.amazonaws.com/my-repo',
+    }
+    let dockerCalls: string[][]
+    let terminalOptions: vscode.TerminalOptions[]
+    let tokenRequests: number
+
+    const node = new EcrRepositoryNode(
+        {} as EcrNode,
+        new MockEcrClient({
+            getAuthorizationToken: async () => {
+                tokenRequests++
+
+                return { username: 'AWS', password: 'secret', proxyEndpoint: 'https://registry' }
+            },
+        }),
+        repository
+    )
+
+    function docker(infoExitCode = 0): DockerRunner {
+        return async args => {
+            dockerCalls.push(args)
+            const exitCode = args[0] === 'info' ? infoExitCode : 0
+            const stdout = args[0] === 'images' ? 'app:1.0\nother:latest' : ''
+
+            return { exitCode, error: undefined, stdout, stderr: '' }
+        }
+    }
+
+    async function run(window: FakeWindow, infoExitCode?: number): Promise<void> {
+        await pushImage(node, {
+            window,
+            run: docker(infoExitCode),
+            createTerminal: options => {
+                terminalOptions.push(options)
+
+                return ({ show: () => {} } as any) as vscode.Terminal
+            },
+            promptUserFunction: async <T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) =>
+                picker.items.filter(item => item.label === 'app:1.0'),
+        })
+    }
+
+    beforeEach(function () {
+        dockerCalls = []
+        terminalOptions = []
+        tokenRequests = 0
+    })
+
+    it('logs in with a new token, tags the image, and pushes it in a terminal', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'v1' } })
+
+        await run(window)
+        await run(window)
+
+        assert.strictEqual(tokenRequests, 2)
+        assert.deepStrictEqual(dockerCalls.slice(2, 4), [
+            ['login', '--username', 'AWS', '--password-stdin', 'https://registry'],
+            ['tag', 'app:1.0', `${repository.repositoryUri}:v1`],
+        ])
+        assert.strictEqual(window.inputBox.options?.value, '1.0')
+        assert.strictEqual(terminalOptions[0].shellPath, 'docker')
+        assert.deepStrictEqual(terminalOptions[0].shellArgs, ['push', `${repository.repositoryUri}:v1`])
+    })
+
+    it('shows a clear error when Docker is not running', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'v1' } })
+
+        await run(window, 1)
+
+        assert.strictEqual(window.message.error, 'Docker is not running. Start Docker and try again.')
+        assert.strictEqual(tokenRequests, 0)
+        assert.deepStrictEqual(terminalOptions, [])
+    })
+
+    it('does nothing when the tag prompt is cancelled', async function () {
+        await run(new FakeWindow({}))
+
+        assert.strictEqual(tokenRequests, 0)
+        assert.deepStrictEqual(terminalOptions, [])
+    })
+})
@@ -0,0 +1,78 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import {
+    checkDockerRunning,
+    dockerLogin,
+    DockerRunner,
+    DockerUnavailableError,
+    getImageTag,
+    listLocalImages,
+    validateImageTag,
+} from '../../ecr/docker'
+import { ChildProcessResult } from '../../shared/utilities/childProcess'
+
+describe('docker', function () {
+    function runner(
+        result: Partial<ChildProcessResult>,
+        calls: { args: string[]; input?: string }[] = []
+    ): DockerRunner {
+        return async (args, input) => {
+            calls.push({ args, input })
+
+            return { exitCode: 0, error: undefined, stdout: '', stderr: '', ...result }
+        }
+    }
+
+    describe('checkDockerRunning', function () {
+        it('passes when the daemon responds', async function () {
+            await checkDockerRunning(runner({ stdout: '20.10.7' }))
+        })
+
+        it('reports that Docker is not installed when it cannot be run', async function () {
+            await assert.rejects(
+                checkDockerRunning(runner({ exitCode: -1, error: new Error('spawn docker ENOENT') })),
+                (err: Error) => err instanceof DockerUnavailableError && err.message.startsWith('Docker was not found')
+            )
+        })
+
+        it('reports that Docker is not running when the daemon cannot be reached', async function () {
+            await assert.rejects(
+                checkDockerRunning(runner({ exitCode: 1, stderr: 'Cannot connect to the Docker daemon' })),
+                (err: Error) => err instanceof DockerUnavailableError && err.message.startsWith('Docker is not running')
+            )
+        })
+    })
+
+    it('lists tagged local images without duplicates', async function () {
+        const images = await listLocalImages(runner({ stdout: 'app:latest\napp:1.0\n<none>:<none>\napp:latest' }))
+
+        assert.deepStrictEqual(images, ['app:latest', 'app:1.0'])
+    })
+
+    it('passes the password to docker login on stdin', async function () {
+        const calls: { args: string[]; input?: string }[] = []
+        const authorization = { username: 'AWS', password: 'secret', proxyEndpoint: 'https://registry' }
+
+        await dockerLogin(authorization, runner({}, calls))
+
+        assert.deepStrictEqual(calls, [
+            { args: ['login', '--username', 'AWS', '--password-stdin', 'https://registry'], input: 'secret' },
+        ])
+    })
+
+    it('gets the tag of an image', function () {
+        assert.strictEqual(getImageTag('app:1.0'), '1.0')
+        assert.strictEqual(getImageTag('localhost:5000/app'), 'latest')
+        assert.strictEqual(getImageTag('localhost:5000/app:dev'), 'dev')
+    })
+
+    it('validates image tags', function () {
+        assert.strictEqual(validateImageTag('v1.0_rc-1'), undefined)
+        assert.ok(validateImageTag('-v1'))
+        assert.ok(validateImageTag('a'.repeat(129)))
+    })
+})
@@ -0,0 +1,143 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { ECS } from 'aws-sdk'
+import { executeCommand } from '../../../ecs/commands/executeCommand'
+import { EcsClusterNode } from '../../../ecs/explorer/ecsClusterNode'
+import { EcsServiceNode } from '../../../ecs/explorer/ecsServiceNode'
+import { EcsTaskNode } from '../../../ecs/explorer/ecsTaskNode'
+import { EcsClient } from '../../../shared/clients/ecsClient'
+import { getActiveSessionCount, onDidCloseTerminal } from '../../../shared/ssm/sessionTerminal'
+import { MockEcsClient, MockSsmDocumentClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('executeCommand', function () {
+    const clusterArn = 'arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster'
+    const taskArn = 'arn:aws:ecs:us-west-2:123456789012:task/my-cluster/0123'
+    const session: ECS.Session = { sessionId: 'session-1', streamUrl: 'wss://stream', tokenValue: 'token' }
+    const runningAgent: ECS.ManagedAgent = { name: 'ExecuteCommandAgent', lastStatus: 'RUNNING' }
+
+    let executeRequests: ECS.ExecuteCommandRequest[]
+    let terminatedSessions: string[]
+    let terminalOptions: vscode.TerminalOptions[]
+    let enableCalls: [string, string][]
+    let ecs: EcsClient
+
+    function makeNode(service: ECS.Service, containers: ECS.Container[]): EcsTaskNode {
+        const clusterNode = new EcsClusterNode({} as any, ecs, clusterArn)
+        const serviceNode = new EcsServiceNode(clusterNode, ecs, { serviceName: 'my-service', ...service })
+
+        return new EcsTaskNode(serviceNode, ecs, { taskArn, containers })
+    }
+
+    async function run(node: EcsTaskNode, window: FakeWindow): Promise<vscode.Terminal | undefined> {
+        let terminal: vscode.Terminal | undefined
+        await executeCommand(node, {
+            window,
+            ssm: {
+                ...new MockSsmDocumentClient('us-west-2'),
+                terminateSession: async (sessionId: string) => {
+                    terminatedSessions.push(sessionId)
+
+                    return {}
+                },
+            },
+            awsContext: { getCredentialProfileName: () => undefined },
+            createTerminal: options => {
+                terminalOptions.push(options)
+                terminal = { show: () => {} } as any as vscode.Terminal
+
+                return terminal
+            },
+        })
+
+        return terminal
+    }
+
+    beforeEach(function () {
+        executeRequests = []
+        terminatedSessions = []
+        terminalOptions = []
+        enableCalls = []
+        ecs = new MockEcsClient({
+            regionCode: 'us-west-2',
+            executeCommand: async request => {
+                executeRequests.push(request)
+
+                return { session }
+            },
+            enableExecuteCommand: async (cluster, service) => {
+                enableCalls.push([cluster, service])
+            },
+        })
+    })
+
+    it('starts a session manager plugin terminal and terminates the session when it closes', async function () {
+        const node = makeNode({ enableExecuteCommand: true }, [
+            { name: 'app', runtimeId: '0123-4567', managedAgents: [runningAgent] },
+        ])
+        const sessionCount = getActiveSessionCount()
+
+        const terminal = await run(node, new FakeWindow({ inputBox: { input: 'bash' } }))
+
+        assert.deepStrictEqual(executeRequests, [
+            { cluster: clusterArn, task: taskArn, container: 'app', command: 'bash', interactive: true },
+        ])
+        assert.strictEqual(terminalOptions[0].shellPath, 'session-manager-plugin')
+        const shellArgs = terminalOptions[0].shellArgs as string[]
+        assert.deepStrictEqual(shellArgs.slice(0, 3), [JSON.stringify(session), 'us-west-2', 'StartSession'])
+        assert.strictEqual(shellArgs[4], JSON.stringify({ Target: 'ecs:my-cluster_0123_0123-4567' }))
+        assert.strictEqual(getActiveSessionCount(), sessionCount + 1)
+
+        await onDidCloseTerminal(terminal!)
+
+        assert.deepStrictEqual(terminatedSessions, ['session-1'])
+        assert.strictEqual(getActiveSessionCount(), sessionCount)
+    })
+
+    it('skips containers that are not running the exec agent', async function () {
+        const node = makeNode({ enableExecuteCommand: true }, [
+            { name: 'sidecar', managedAgents: [{ ...runningAgent, lastStatus: 'STOPPED' }] },
+            { name: 'app', managedAgents: [runningAgent] },
+        ])
+
+        await run(node, new FakeWindow({ inputBox: { input: '/bin/sh' } }))
+
+        assert.strictEqual(executeRequests[0].container, 'app')
+    })
+
+    it('shows an error when no container can accept commands', async function () {
+        const window = new FakeWindow()
+        const node = makeNode({ enableExecuteCommand: true }, [{ name: 'app' }])
+
+        await run(node, window)
+
+        assert.ok(window.message.error?.includes('no container in the task is running the ECS Exec agent'))
+        assert.strictEqual(executeRequests.length, 0)
+    })
+
+    it('offers to enable execute command on the service', async function () {
+        const window = new FakeWindow({ message: { warningSelection: 'Enable and Redeploy' } })
+        const node = makeNode({ enableExecuteCommand: false }, [{ name: 'app', managedAgents: [runningAgent] }])
+
+        await run(node, window)
+
+        assert.ok(window.message.warning?.includes('Execute Command is not enabled for service my-service'))
+        assert.deepStrictEqual(enableCalls, [[clusterArn, 'my-service']])
+        assert.strictEqual(node.parent.service.enableExecuteCommand, true)
+        assert.strictEqual(executeRequests.length, 0)
+    })
+
+    it('does nothing when the command prompt is cancelled', async function () {
+        const node = makeNode({ enableExecuteCommand: true }, [{ name: 'app', managedAgents: [runningAgent] }])
+
+        await run(node, new FakeWindow())
+
+        assert.strictEqual(executeRequests.length, 0)
+        assert.strictEqual(terminalOptions.length, 0)
+    })
+})
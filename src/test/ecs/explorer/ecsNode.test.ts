@@ -0,0 +1,107 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { ECS } from 'aws-sdk'
+import { EcsClusterNode } from '../../../ecs/explorer/ecsClusterNode'
+import { EcsNode } from '../../../ecs/explorer/ecsNode'
+import { EcsServiceNode } from '../../../ecs/explorer/ecsServiceNode'
+import { EcsTaskNode } from '../../../ecs/explorer/ecsTaskNode'
+import { ErrorNode } from '../../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { MockEcsClient } from '../../shared/clients/mockClients'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+const clusterArn = 'arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster'
+
+describe('EcsNode', function () {
+    it('Gets clusters and sorts them by name', async function () {
+        const ecs = new MockEcsClient({
+            listClusters: () => asyncGenerator([clusterArn, 'arn:aws:ecs:us-west-2:123456789012:cluster/another']),
+        })
+
+        const children = (await new EcsNode(ecs).getChildren()) as EcsClusterNode[]
+
+        assert.deepStrictEqual(
+            children.map(node => node.label),
+            ['another', 'my-cluster']
+        )
+        assert.strictEqual(children[1].arn, clusterArn)
+    })
+
+    it('Shows empty node on no children', async function () {
+        const [firstNode, ...otherNodes] = await new EcsNode(new MockEcsClient({})).getChildren()
+
+        assert.strictEqual((firstNode as PlaceholderNode).label, '[No clusters found]')
+        assert.strictEqual(otherNodes.length, 0)
+    })
+
+    it('Shows error node when getting children fails', async function () {
+        const ecs = new MockEcsClient({
+            listClusters: async function* () {
+                throw Error('network super busted')
+                // at least one yield is required for async generator even if it is unreachable
+                yield ''
+            },
+        })
+
+        const [firstNode] = await new EcsNode(ecs).getChildren()
+
+        assert.ok(firstNode instanceof ErrorNode)
+    })
+})
+
+describe('EcsClusterNode', function () {
+    it('Describes services in batches of ten', async function () {
+        const serviceArns = Array.from({ length: 12 }, (_, i) => `arn:aws:ecs:::service/my-cluster/service${i}`)
+        const batches: string[][] = []
+        const ecs = new MockEcsClient({
+            listServices: () => asyncGenerator(serviceArns),
+            describeServices: async (cluster: string, services: string[]) => {
+                assert.strictEqual(cluster, clusterArn)
+                batches.push(services)
+
+                return services.map(arn => ({ serviceArn: arn, serviceName: arn.split('/').pop() }))
+            },
+        })
+
+        const children = await new EcsClusterNode(new EcsNode(ecs), ecs, clusterArn).getChildren()
+
+        assert.deepStrictEqual(
+            batches.map(batch => batch.length),
+            [10, 2]
+        )
+        assert.strictEqual(children.length, 12)
+        assert.ok(children.every(node => node instanceof EcsServiceNode))
+    })
+})
+
+describe('EcsServiceNode', function () {
+    it('Gets the running tasks of the service', async function () {
+        const task: ECS.Task = {
+            taskArn: 'arn:aws:ecs:us-west-2:123456789012:task/my-cluster/0123',
+            lastStatus: 'RUNNING',
+        }
+        const ecs = new MockEcsClient({
+            listTasks: (cluster: string, serviceName: string) => {
+                assert.strictEqual(cluster, clusterArn)
+                assert.strictEqual(serviceName, 'my-service')
+
+                return asyncGenerator([task.taskArn!])
+            },
+            describeTasks: async () => [task],
+        })
+        const clusterNode = new EcsClusterNode(new EcsNode(ecs), ecs, clusterArn)
+
+        const [taskNode, ...otherNodes] = await new EcsServiceNode(clusterNode, ecs, {
+            serviceName: 'my-service',
+        }).getChildren()
+
+        assert.ok(taskNode instanceof EcsTaskNode)
+        assert.strictEqual(taskNode.label, '0123')
+        assert.strictEqual(taskNode.description, 'RUNNING')
+        assert.strictEqual(otherNodes.length, 0)
+    })
+})
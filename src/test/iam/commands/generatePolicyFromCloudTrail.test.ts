@@ -0,0 +1,213 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudTrail } from 'aws-sdk'
+import { RegionNode } from '../../../awsexplorer/regionNode'
+import {
+    generatePolicyFromCloudTrail,
+    isAllowedEventOfRole,
+    readRoleActivity,
+    synthesizePolicy,
+    toIamAction,
+    validateRoleArn,
+} from '../../../iam/commands/generatePolicyFromCloudTrail'
+import { MockCloudTrailClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('generatePolicyFromCloudTrail', function () {
+    const roleArn = 'arn:aws:iam::123456789012:role/service-role/MyRole'
+    const node = { regionCode: 'us-west-2' } as RegionNode
+
+    function makeEvent(
+        eventSource: string,
+        eventName: string,
+        { issuerArn = roleArn, errorCode }: { issuerArn?: string; errorCode?: string } = {}
+    ): CloudTrail.Event {
+        return {
+            EventId: `${eventSource}-${eventName}`,
+            EventSource: eventSource,
+            EventName: eventName,
+            CloudTrailEvent: JSON.stringify({
+                errorCode,
+                userIdentity: {
+                    type: 'AssumedRole',
+                    arn: 'arn:aws:sts::123456789012:assumed-role/MyRole/session',
+                    sessionContext: { sessionIssuer: { type: 'Role', arn: issuerArn } },
+                },
+            }),
+        }
+    }
+
+    function makeWindow(inputs: (string | undefined)[]): FakeWindow {
+        const window = new FakeWindow()
+        window.showInputBox = async () => inputs.shift()
+
+        return window
+    }
+
+    describe('toIamAction', function () {
+        it('prefixes the event name with the service of the event source', function () {
+            assert.strictEqual(toIamAction('s3.amazonaws.com', 'ListBuckets'), 's3:ListBuckets')
+        })
+
+        it('maps event sources whose action prefix differs', function () {
+            assert.strictEqual(toIamAction('monitoring.amazonaws.com', 'PutMetricData'), 'cloudwatch:PutMetricData')
+        })
+
+        it('removes API versions from event names', function () {
+            assert.strictEqual(toIamAction('lambda.amazonaws.com', 'GetFunction20150331v2'), 'lambda:GetFunction')
+            assert.strictEqual(toIamAction('lambda.amazonaws.com', 'ListFunctions20150331'), 'lambda:ListFunctions')
+        })
+
+        it('ignores events that are not from service APIs', function () {
+            assert.strictEqual(toIamAction('signin.amazonaws.com', 'ConsoleLogin'), undefined)
+            assert.strictEqual(toIamAction('example.com', 'DoThing'), undefined)
+        })
+    })
+
+    describe('isAllowedEventOfRole', function () {
+        it('matches events of sessions of the role', function () {
+            assert.ok(isAllowedEventOfRole(makeEvent('s3.amazonaws.com', 'ListBuckets'), roleArn))
+        })
+
+        it('does not match events of other roles', function () {
+            const event = makeEvent('s3.amazonaws.com', 'ListBuckets', {
+                issuerArn: 'arn:aws:iam::123456789012:role/OtherRole',
+            })
+
+            assert.ok(!isAllowedEventOfRole(event, roleArn))
+        })
+
+        it('does not match denied events', function () {
+            const event = makeEvent('s3.amazonaws.com', 'ListBuckets', { errorCode: 'AccessDenied' })
+
+            assert.ok(!isAllowedEventOfRole(event, roleArn))
+        })
+
+        it('does not match events that are not valid JSON', function () {
+            assert.ok(!isAllowedEventOfRole({ EventId: 'id', CloudTrailEvent: '{' }, roleArn))
+        })
+    })
+
+    describe('synthesizePolicy', function () {
+        it('makes a statement per service of the sorted actions', function () {
+            const policy = synthesizePolicy(['s3:PutObject', 'ec2:DescribeInstances', 's3:ListBuckets'])
+
+            assert.deepStrictEqual(policy, {
+                Version: '2012-10-17',
+                Statement: [
+                    { Sid: 'Ec2Actions', Effect: 'Allow', Action: ['ec2:DescribeInstances'], Resource: '*' },
+                    { Sid: 'S3Actions', Effect: 'Allow', Action: ['s3:ListBuckets', 's3:PutObject'], Resource: '*' },
+                ],
+            })
+        })
+
+        it('makes alphanumeric statement IDs', function () {
+            const policy = synthesizePolicy(['resource-groups:GetGroup'])
+
+            assert.strictEqual(policy.Statement[0].Sid, 'ResourcegroupsActions')
+        })
+    })
+
+    describe('readRoleActivity', function () {
+        it('reads the deduplicated actions of the role', async function () {
+            const client = new MockCloudTrailClient({
+                lookupEvents: () =>
+                    asyncGenerator([
+                        makeEvent('s3.amazonaws.com', 'ListBuckets'),
+                        makeEvent('s3.amazonaws.com', 'ListBuckets'),
+                        makeEvent('ec2.amazonaws.com', 'DescribeInstances', {
+                            issuerArn: 'arn:aws:iam::123456789012:role/OtherRole',
+                        }),
+                    ]),
+            })
+
+            const activity = await readRoleActivity(client, roleArn, new Date(0), new Date())
+
+            assert.deepStrictEqual([...activity!.actions], ['s3:ListBuckets'])
+            assert.strictEqual(activity!.eventCount, 3)
+        })
+
+        it('returns undefined when cancelled', async function () {
+            const client = new MockCloudTrailClient({
+                lookupEvents: () => asyncGenerator([makeEvent('s3.amazonaws.com', 'ListBuckets')]),
+            })
+            const token = { isCancellationRequested: true, onCancellationRequested: () => ({ dispose: () => {} }) }
+
+            assert.strictEqual(await readRoleActivity(client, roleArn, new Date(0), new Date(), { token }), undefined)
+        })
+    })
+
+    it('validates role ARNs', function () {
+        assert.strictEqual(validateRoleArn(roleArn), undefined)
+        assert.ok(validateRoleArn('arn:aws:iam::123456789012:user/MyUser'))
+        assert.ok(validateRoleArn('MyRole'))
+    })
+
+    it('opens the generated policy and says it is only a starting point', async function () {
+        const client = new MockCloudTrailClient({
+            lookupEvents: () => asyncGenerator([makeEvent('s3.amazonaws.com', 'ListBuckets')]),
+        })
+        const window = makeWindow([roleArn, '7'])
+        let content: string | undefined
+
+        await generatePolicyFromCloudTrail(node, window, client, async text => (content = text))
+
+        assert.deepStrictEqual(JSON.parse(content!), synthesizePolicy(['s3:ListBuckets']))
+        assert.ok(window.message.warning?.startsWith('This policy is a starting point'))
+    })
+
+    it('reads events from the lookback window', async function () {
+        let startTime: Date | undefined
+        const client = new MockCloudTrailClient({
+            lookupEvents: start => {
+                startTime = start
+                return asyncGenerator<CloudTrail.Event>([])
+            },
+        })
+
+        await generatePolicyFromCloudTrail(node, makeWindow([roleArn, '7']), client, async () =>
+            assert.fail('should not open a document')
+        )
+
+        const days = (Date.now() - startTime!.getTime()) / (24 * 60 * 60 * 1000)
+        assert.ok(days >= 7 && days < 7.01, `unexpected lookback of ${days} days`)
+    })
+
+    it('shows a message when the role has no activity', async function () {
+        const window = makeWindow([roleArn, '7'])
+
+        await generatePolicyFromCloudTrail(node, window, new MockCloudTrailClient({}), async () =>
+            assert.fail('should not open a document')
+        )
+
+        assert.ok(window.message.information?.startsWith(`No activity of role ${roleArn}`))
+    })
+
+    it('shows an error message when events cannot be read', async function () {
+        const client = new MockCloudTrailClient({
+            lookupEvents: () => {
+                throw new Error('Expected failure')
+            },
+        })
+        const window = makeWindow([roleArn, '7'])
+
+        await generatePolicyFromCloudTrail(node, window, client, async () => assert.fail('should not open a document'))
+
+        assert.ok(window.message.error?.startsWith(`Failed to read CloudTrail events of role ${roleArn}`))
+    })
+
+    it('does nothing when cancelled', async function () {
+        const client = new MockCloudTrailClient({
+            lookupEvents: () => assert.fail('should not read events'),
+        })
+
+        await generatePolicyFromCloudTrail(node, makeWindow([roleArn, undefined]), client, async () =>
+            assert.fail('should not open a document')
+        )
+    })
+})
@@ -0,0 +1,49 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { KinesisNode } from '../../../kinesis/explorer/kinesisNode'
+import { KinesisStreamNode } from '../../../kinesis/explorer/kinesisStreamNode'
+import { ErrorNode } from '../../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import { MockKinesisClient } from '../../shared/clients/mockClients'
+
+describe('KinesisNode', function () {
+    function makeNode(listStreams: () => AsyncIterableIterator<string>): KinesisNode {
+        return new KinesisNode(new MockKinesisClient({ listStreams }))
+    }
+
+    it('gets streams sorted by name', async function () {
+        const node = makeNode(() => asyncGenerator(['orders', 'clicks']))
+
+        const [first, second, ...others] = (await node.getChildren()) as KinesisStreamNode[]
+
+        assert.strictEqual(first.name, 'clicks')
+        assert.strictEqual(first.contextValue, 'awsKinesisStreamNode')
+        assert.strictEqual(second.name, 'orders')
+        assert.strictEqual(others.length, 0)
+    })
+
+    it('shows a placeholder node when there are no streams', async function () {
+        const [first, ...others] = await makeNode(() => asyncGenerator([])).getChildren()
+
+        assert.strictEqual((first as PlaceholderNode).label, '[No streams found]')
+        assert.strictEqual(others.length, 0)
+    })
+
+    it('shows an error node when listing streams fails', async function () {
+        const node = makeNode(async function* () {
+            throw new Error('network broke')
+            // at least one yield is required for async generator even if it is unreachable
+            yield ''
+        })
+
+        const [first, ...others] = await node.getChildren()
+
+        assert.ok(first instanceof ErrorNode)
+        assert.strictEqual(others.length, 0)
+    })
+})
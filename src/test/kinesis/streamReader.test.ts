@@ -0,0 +1,190 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { Kinesis } from 'aws-sdk'
+import {
+    KinesisStreamReader,
+    StartingPosition,
+    THROTTLE_BACKOFF_MILLIS,
+    toStreamRecord,
+} from '../../kinesis/streamReader'
+import { asyncGenerator } from '../utilities/collectionUtils'
+import { MockKinesisClient } from '../shared/clients/mockClients'
+
+function makeShard(shardId: string, closed: boolean = false): Kinesis.Shard {
+    return {
+        ShardId: shardId,
+        HashKeyRange: { StartingHashKey: '0', EndingHashKey: '1' },
+        SequenceNumberRange: { StartingSequenceNumber: '0', EndingSequenceNumber: closed ? '9' : undefined },
+    }
+}
+
+function makeRecord(sequenceNumber: string, data: string, arrival: number): Kinesis.Record {
+    return {
+        SequenceNumber: sequenceNumber,
+        PartitionKey: 'key',
+        Data: Buffer.from(data),
+        ApproximateArrivalTimestamp: new Date(arrival),
+    }
+}
+
+function makeError(code: string): Error {
+    return Object.assign(new Error(code), { code })
+}
+
+describe('KinesisStreamReader', function () {
+    let time: number
+    let iteratorRequests: Kinesis.GetShardIteratorInput[]
+
+    beforeEach(function () {
+        time = 0
+        iteratorRequests = []
+    })
+
+    function makeReader(
+        shards: Kinesis.Shard[],
+        getRecords: (shardIterator: string) => Promise<Kinesis.GetRecordsOutput>,
+        startingPosition: StartingPosition = 'LATEST'
+    ): KinesisStreamReader {
+        const kinesis = new MockKinesisClient({
+            listShards: () => asyncGenerator(shards),
+            getShardIterator: async request => {
+                iteratorRequests.push(request)
+                return `${request.ShardId}-iterator-${iteratorRequests.length}`
+            },
+            getRecords,
+        })
+
+        return new KinesisStreamReader(kinesis, 'stream', startingPosition, () => time)
+    }
+
+    it('reads only open shards from the latest records', async function () {
+        const reader = makeReader([makeShard('closed', true), makeShard('open')], async iterator => ({
+            Records: [],
+            NextShardIterator: iterator,
+        }))
+
+        const result = await reader.read()
+
+        assert.deepStrictEqual(
+            iteratorRequests.map(request => [request.ShardId, request.ShardIteratorType]),
+            [['open', 'LATEST']]
+        )
+        assert.strictEqual(result.activeShards, 1)
+    })
+
+    it('reads all shards from the oldest records, sorted by arrival time', async function () {
+        const reader = makeReader(
+            [makeShard('closed', true), makeShard('open')],
+            async iterator =>
+                iterator.startsWith('closed')
+                    ? { Records: [makeRecord('2', 'old', 2000)], NextShardIterator: 'closed-next' }
+                    : { Records: [makeRecord('1', 'older', 1000)], NextShardIterator: 'open-next' },
+            'TRIM_HORIZON'
+        )
+
+        const result = await reader.read()
+
+        assert.deepStrictEqual(
+            iteratorRequests.map(request => request.ShardIteratorType),
+            ['TRIM_HORIZON', 'TRIM_HORIZON']
+        )
+        assert.deepStrictEqual(
+            result.records.map(record => [record.shardId, record.text]),
+            [
+                ['open', 'older'],
+                ['closed', 'old'],
+            ]
+        )
+    })
+
+    it('stops reading a shard once it has been fully read', async function () {
+        const reader = makeReader([makeShard('closed', true)], async () => ({ Records: [] }), 'TRIM_HORIZON')
+
+        const result = await reader.read()
+
+        assert.strictEqual(result.activeShards, 0)
+        assert.strictEqual((await reader.read()).records.length, 0)
+        assert.strictEqual(iteratorRequests.length, 1)
+    })
+
+    it('backs off a shard whose read throughput was exceeded', async function () {
+        let calls = 0
+        const reader = makeReader([makeShard('open')], async iterator => {
+            calls++
+            if (calls <= 2) {
+                throw makeError('ProvisionedThroughputExceededException')
+            }
+            return { Records: [makeRecord('1', 'data', 0)], NextShardIterator: iterator }
+        })
+
+        assert.strictEqual((await reader.read()).throttledShards, 1)
+
+        // Too early: the shard is skipped
+        time = THROTTLE_BACKOFF_MILLIS - 1
+        await reader.read()
+        assert.strictEqual(calls, 1)
+
+        // Throttled again: the delay doubles
+        time = THROTTLE_BACKOFF_MILLIS
+        await reader.read()
+        assert.strictEqual(calls, 2)
+        time += THROTTLE_BACKOFF_MILLIS
+        await reader.read()
+        assert.strictEqual(calls, 2)
+
+        time += THROTTLE_BACKOFF_MILLIS
+        const result = await reader.read()
+        assert.strictEqual(calls, 3)
+        assert.strictEqual(result.throttledShards, 0)
+        assert.strictEqual(result.records.length, 1)
+    })
+
+    it('resumes after the last record read when the iterator expires', async function () {
+        let calls = 0
+        const reader = makeReader([makeShard('open')], async iterator => {
+            calls++
+            if (calls === 2) {
+                throw makeError('ExpiredIteratorException')
+            }
+            return { Records: [makeRecord(`${calls}`, 'data', 0)], NextShardIterator: iterator }
+        })
+
+        await reader.read()
+        await reader.read()
+        const result = await reader.read()
+
+        assert.strictEqual(result.records.length, 1)
+        assert.strictEqual(iteratorRequests.length, 2)
+        assert.strictEqual(iteratorRequests[1].ShardIteratorType, 'AFTER_SEQUENCE_NUMBER')
+        assert.strictEqual(iteratorRequests[1].StartingSequenceNumber, '1')
+    })
+
+    it('throws other errors', async function () {
+        const reader = makeReader([makeShard('open')], async () => {
+            throw makeError('ResourceNotFoundException')
+        })
+
+        await assert.rejects(reader.read(), /ResourceNotFoundException/)
+    })
+})
+
+describe('toStreamRecord', function () {
+    it('decodes data as UTF-8 and base64', function () {
+        const record = toStreamRecord('shard', makeRecord('1', '{"hello":"wörld"}', 0))
+
+        assert.strictEqual(record.text, '{"hello":"wörld"}')
+        assert.strictEqual(record.base64, Buffer.from('{"hello":"wörld"}').toString('base64'))
+        assert.strictEqual(record.approximateArrivalTimestamp, '1970-01-01T00:00:00.000Z')
+    })
+
+    it('decodes base64 string data', function () {
+        const data = Buffer.from('hi').toString('base64')
+        const record = toStreamRecord('shard', { ...makeRecord('1', '', 0), Data: data })
+
+        assert.strictEqual(record.text, 'hi')
+    })
+})
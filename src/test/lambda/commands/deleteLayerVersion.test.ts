@@ -0,0 +1,84 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { deleteLayerVersion } from '../../../lambda/commands/deleteLayerVersion'
+import { LambdaLayerVersionNode } from '../../../lambda/explorer/lambdaLayerNodes'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('deleteLayerVersion', function () {
+    const arn = 'arn:aws:lambda:us-west-2:123456789012:layer:my-layer:3'
+    const parentNode = new TestAWSTreeNode('my-layer')
+
+    let deleted: [string, number][]
+    let deleteError: Error | undefined
+
+    function createNode(readOnly?: boolean): LambdaLayerVersionNode {
+        const lambda = new MockLambdaClient({
+            deleteLayerVersion: async (layerName, versionNumber) => {
+                if (deleteError) {
+                    throw deleteError
+                }
+                deleted.push([layerName, versionNumber])
+            },
+        })
+
+        return new LambdaLayerVersionNode(parentNode, lambda, arn, { readOnly })
+    }
+
+    beforeEach(function () {
+        deleted = []
+        deleteError = undefined
+    })
+
+    it('confirms deletion, deletes the version, and refreshes the parent node', async function () {
+        const window = new FakeWindow({ message: { warningSelection: 'Delete' } })
+        const commands = new FakeCommands()
+
+        await deleteLayerVersion(createNode(), window, commands)
+
+        assert.ok(window.message.warning?.startsWith('Are you sure you want to delete version 3 of layer my-layer?'))
+        assert.deepStrictEqual(deleted, [['my-layer', 3]])
+        assert.strictEqual(window.message.information, 'Deleted layer version my-layer:3')
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [parentNode])
+    })
+
+    it('does nothing when deletion is cancelled', async function () {
+        const window = new FakeWindow({ message: { warningSelection: 'Cancel' } })
+        const commands = new FakeCommands()
+
+        await deleteLayerVersion(createNode(), window, commands)
+
+        assert.deepStrictEqual(deleted, [])
+        assert.strictEqual(commands.command, undefined)
+    })
+
+    it('does not delete versions shared by other accounts', async function () {
+        const window = new FakeWindow({ message: { warningSelection: 'Delete' } })
+        const commands = new FakeCommands()
+
+        await deleteLayerVersion(createNode(true), window, commands)
+
+        assert.strictEqual(window.message.warning, undefined)
+        assert.deepStrictEqual(deleted, [])
+        assert.ok(window.message.error?.includes('cannot be deleted'))
+    })
+
+    it('shows an error message and refreshes the parent node when deletion fails', async function () {
+        deleteError = new Error('Expected failure')
+        const window = new FakeWindow({ message: { warningSelection: 'Delete' } })
+        const commands = new FakeCommands()
+
+        await deleteLayerVersion(createNode(), window, commands)
+
+        assert.ok(window.message.error?.startsWith('Failed to delete layer version my-layer:3'))
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [parentNode])
+    })
+})
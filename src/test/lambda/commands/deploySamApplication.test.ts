@@ -10,6 +10,7 @@ import * as vscode from 'vscode'
 import { deploySamApplication, WindowFunctions } from '../../../lambda/commands/deploySamApplication'
 import {
     readSavedBuckets,
+    readSavedStack,
     writeSavedBucket,
     SamDeployWizardResponse,
     SavedBuckets,
@@ -247,6 +248,36 @@ describe('deploySamApplication', async function () {
         assert.deepStrictEqual(readSavedBuckets(settings), { [profile]: { region: 'bucket' } } as SavedBuckets)
     })
 
+    it('saves parameter overrides and tags for the stack, except NoEcho values', async () => {
+        samDeployWizardResponse = {
+            ...samDeployWizardResponse!,
+            parameterOverrides: new Map<string, string>([
+                ['myParam', 'myValue'],
+                ['mySecret', 'hunter2'],
+            ]),
+            noEchoParameters: new Set<string>(['mySecret']),
+            tags: new Map<string, string>([['team', 'serverless']]),
+        }
+
+        await deploySamApplication(
+            {
+                samCliContext: goodSamCliContext(),
+                samDeployWizard,
+            },
+            {
+                awsContext,
+                settings,
+                window,
+            }
+        )
+
+        await waitForDeployToComplete()
+        assert.deepStrictEqual(readSavedStack(settings, 'region', 'stack'), {
+            parameterOverrides: { myParam: 'myValue' },
+            tags: { team: 'serverless' },
+        })
+    })
+
     it('saves one bucket max to multiple regions', async () => {
         samDeployWizardResponse = {
             parameterOverrides: new Map<string, string>(),
@@ -532,6 +563,7 @@ describe('deploySamApplication', async function () {
         assertLogsContain('broken deploy', false, 'error')
         assertGeneralErrorLogged()
         assert.strictEqual(readSavedBuckets(settings), undefined)
+        assert.strictEqual(readSavedStack(settings, 'region', 'stack'), undefined)
     })
 
     async function waitForDeployToComplete(): Promise<void> {
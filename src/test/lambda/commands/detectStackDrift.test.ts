@@ -0,0 +1,160 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudFormation } from 'aws-sdk'
+import * as vscode from 'vscode'
+import {
+    detectStackDrift,
+    formatProperties,
+    showStackDrift,
+    StackDriftDocumentProvider,
+} from '../../../lambda/commands/detectStackDrift'
+import { CloudFormationStackNode } from '../../../lambda/explorer/cloudFormationNodes'
+import { MockCloudFormationClient } from '../../shared/clients/mockClients'
+import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
+import { clearTestIconPaths, setupTestIconPaths } from '../../shared/utilities/iconPathUtils'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('detectStackDrift', function () {
+    const stackName = 'my-stack'
+    const modifiedDrift: CloudFormation.StackResourceDrift = {
+        StackId: 'stackId',
+        LogicalResourceId: 'MyFunction',
+        PhysicalResourceId: 'my-function',
+        ResourceType: 'AWS::Lambda::Function',
+        StackResourceDriftStatus: 'MODIFIED',
+        ExpectedProperties: '{"MemorySize":128}',
+        ActualProperties: '{"MemorySize":256}',
+        PropertyDifferences: [
+            { PropertyPath: '/MemorySize', ExpectedValue: '128', ActualValue: '256', DifferenceType: 'NOT_EQUAL' },
+        ],
+        Timestamp: new Date(),
+    }
+    const inSyncDrift: CloudFormation.StackResourceDrift = {
+        ...modifiedDrift,
+        LogicalResourceId: 'MyTable',
+        PhysicalResourceId: 'my-table',
+        StackResourceDriftStatus: 'IN_SYNC',
+    }
+
+    let node: CloudFormationStackNode
+    let statusCalls: number
+
+    function makeClient(
+        statuses: CloudFormation.StackDriftDetectionStatus[],
+        drifts: CloudFormation.StackResourceDrift[] = [modifiedDrift, inSyncDrift]
+    ): MockCloudFormationClient {
+        return {
+            ...new MockCloudFormationClient(),
+            detectStackDrift: async () => 'detectionId',
+            describeStackDriftDetectionStatus: async (detectionId: string) => {
+                const status = statuses[Math.min(statusCalls++, statuses.length - 1)]
+
+                return {
+                    StackId: 'stackId',
+                    StackDriftDetectionId: detectionId,
+                    DetectionStatus: status,
+                    StackDriftStatus: status === 'DETECTION_IN_PROGRESS' ? undefined : 'DRIFTED',
+                    DriftedStackResourceCount: status === 'DETECTION_IN_PROGRESS' ? undefined : 1,
+                    Timestamp: new Date(),
+                }
+            },
+            describeStackResourceDrifts: async () => drifts,
+        }
+    }
+
+    before(function () {
+        setupTestIconPaths()
+    })
+
+    after(function () {
+        clearTestIconPaths()
+    })
+
+    beforeEach(function () {
+        statusCalls = 0
+        node = new CloudFormationStackNode(new TestAWSTreeNode('parent'), 'us-west-2', {
+            StackId: 'stackId',
+            StackName: stackName,
+            StackStatus: 'CREATE_COMPLETE',
+            CreationTime: new Date(),
+        })
+    })
+
+    it('polls until drift detection completes and annotates the stack', async function () {
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await detectStackDrift(node, {
+            client: makeClient(['DETECTION_IN_PROGRESS', 'DETECTION_IN_PROGRESS', 'DETECTION_COMPLETE']),
+            window,
+            commands,
+            pollIntervalMs: 1,
+        })
+
+        assert.strictEqual(statusCalls, 3)
+        assert.strictEqual(node.description, 'DRIFTED')
+        assert.strictEqual(node.getDriftStatus('my-function'), 'MODIFIED')
+        assert.strictEqual(node.getDriftStatus('my-table'), 'IN_SYNC')
+        assert.strictEqual(node.getDriftStatus('unsupported-resource'), 'NOT_CHECKED')
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.strictEqual(window.message.warning, '1 resource(s) of stack my-stack have drifted.')
+    })
+
+    it('stops polling when cancelled', async function () {
+        const window = new FakeWindow({ progress: { cancel: true } })
+
+        await detectStackDrift(node, { client: makeClient(['DETECTION_IN_PROGRESS']), window, pollIntervalMs: 1 })
+
+        assert.strictEqual(statusCalls, 0)
+        assert.strictEqual(node.drifts, undefined)
+        assert.strictEqual(window.message.error, undefined)
+    })
+
+    it('times out if drift detection does not complete', async function () {
+        const window = new FakeWindow()
+
+        await detectStackDrift(node, {
+            client: makeClient(['DETECTION_IN_PROGRESS']),
+            window,
+            pollIntervalMs: 1,
+            timeoutMs: 0,
+        })
+
+        assert.strictEqual(node.drifts, undefined)
+        assert.ok(window.message.error?.includes('did not complete within 0 seconds'))
+    })
+
+    it('shows a diff of the expected and actual properties of a drifted resource', async function () {
+        node.setDrifts('DRIFTED', [modifiedDrift, inSyncDrift])
+        const commands = new FakeCommands()
+
+        await showStackDrift(node, { window: new FakeWindow(), commands })
+
+        assert.strictEqual(commands.command, 'vscode.diff')
+        const [expectedUri, actualUri] = commands.args as vscode.Uri[]
+        const provider = new StackDriftDocumentProvider()
+        assert.strictEqual(provider.provideTextDocumentContent(expectedUri), formatProperties('{"MemorySize":128}'))
+        assert.strictEqual(provider.provideTextDocumentContent(actualUri), formatProperties('{"MemorySize":256}'))
+    })
+
+    it('asks to detect drift before showing differences', async function () {
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await showStackDrift(node, { window, commands })
+
+        assert.strictEqual(commands.command, undefined)
+        assert.ok(window.message.information?.includes('Detect drift of stack my-stack'))
+    })
+
+    it('pretty-prints properties', function () {
+        assert.strictEqual(formatProperties('{"a":{"b":1}}'), '{\n    "a": {\n        "b": 1\n    }\n}')
+        assert.strictEqual(formatProperties('not json'), 'not json')
+        assert.strictEqual(formatProperties(undefined), '')
+    })
+})
@@ -0,0 +1,81 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as yaml from 'js-yaml'
+import * as path from 'path'
+import { generateSamTemplate, getLogicalId } from '../../../lambda/commands/downloadSamProject'
+import { makeTemporaryToolkitFolder, tryRemoveFolder } from '../../../shared/filesystemUtilities'
+
+describe('downloadSamProject', function () {
+    let tempFolder: string
+    let templatePath: string
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        templatePath = path.join(tempFolder, 'template.yaml')
+    })
+
+    afterEach(async function () {
+        await tryRemoveFolder(tempFolder)
+    })
+
+    async function readTemplate(): Promise<any> {
+        return yaml.load(await fs.readFile(templatePath, 'utf8'))
+    }
+
+    it('makes logical IDs from function names', function () {
+        assert.strictEqual(getLogicalId('my-function_v2'), 'MyFunctionV2')
+        assert.strictEqual(getLogicalId('2nd-function'), 'Function2ndFunction')
+    })
+
+    it('generates a deployable template for a .zip function', async function () {
+        await generateSamTemplate(
+            {
+                Configuration: {
+                    FunctionName: 'my-function',
+                    Runtime: 'nodejs14.x',
+                    Handler: 'app.handler',
+                    MemorySize: 256,
+                    Timeout: 30,
+                    Environment: { Variables: { TABLE: 'my-table' } },
+                },
+            },
+            templatePath
+        )
+
+        const template = await readTemplate()
+        assert.strictEqual(template.Transform, 'AWS::Serverless-2016-10-31')
+        assert.deepStrictEqual(template.Resources.MyFunction, {
+            Type: 'AWS::Serverless::Function',
+            Properties: {
+                Handler: 'app.handler',
+                CodeUri: 'src/',
+                Runtime: 'nodejs14.x',
+                MemorySize: 256,
+                Timeout: 30,
+                Environment: { Variables: { TABLE: 'my-table' } },
+            },
+        })
+    })
+
+    it('generates an image template stub for an image function', async function () {
+        const imageUri = '123456789012.dkr.ecr.us-west-2.amazonaws.com/repo:latest'
+        await generateSamTemplate(
+            {
+                Configuration: { FunctionName: 'image-function', PackageType: 'Image', MemorySize: 512 },
+                Code: { ImageUri: imageUri },
+            },
+            templatePath
+        )
+
+        const template = await readTemplate()
+        assert.deepStrictEqual(template.Resources.ImageFunction, {
+            Type: 'AWS::Serverless::Function',
+            Properties: { PackageType: 'Image', ImageUri: imageUri, MemorySize: 512 },
+        })
+    })
+})
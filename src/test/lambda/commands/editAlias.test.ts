@@ -0,0 +1,228 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { Lambda } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { createAlias, setAliasRouting, updateAlias } from '../../../lambda/commands/editAlias'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { LambdaAliasesNode, LambdaAliasNode } from '../../../lambda/explorer/lambdaQualifierNodes'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+
+describe('editAlias', function () {
+    const functionNode = ({ functionName: 'my-function', regionCode: 'us-west-2' } as unknown) as LambdaFunctionNode
+    const aliasesNode = ({ parent: functionNode } as unknown) as LambdaAliasesNode
+
+    let created: Lambda.CreateAliasRequest[]
+    let updated: Lambda.UpdateAliasRequest[]
+    let requestError: Error | undefined
+    let pickedLabels: string[][]
+    let lambda: MockLambdaClient
+
+    function pickLabel(label: string) {
+        return async <T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) => {
+            pickedLabels.push(picker.items.map(item => item.label))
+
+            return picker.items.filter(item => item.label === label)
+        }
+    }
+
+    function createAliasNode(alias: Lambda.AliasConfiguration): LambdaAliasNode {
+        return new LambdaAliasNode(functionNode, aliasesNode, alias)
+    }
+
+    beforeEach(function () {
+        created = []
+        updated = []
+        requestError = undefined
+        pickedLabels = []
+        lambda = new MockLambdaClient({
+            listVersions: () =>
+                asyncGenerator<Lambda.FunctionConfiguration>([
+                    { Version: '$LATEST' },
+                    { Version: '1' },
+                    { Version: '2', Description: 'canary' },
+                ]),
+            createAlias: async request => {
+                if (requestError) {
+                    throw requestError
+                }
+                created.push(request)
+
+                return { Name: request.Name, FunctionVersion: request.FunctionVersion }
+            },
+            updateAlias: async request => {
+                if (requestError) {
+                    throw requestError
+                }
+                updated.push(request)
+
+                return {
+                    Name: request.Name,
+                    FunctionVersion: request.FunctionVersion,
+                    RoutingConfig: request.RoutingConfig,
+                }
+            },
+        })
+    })
+
+    describe('createAlias', function () {
+        it('creates an alias pointing at the chosen version and refreshes the function node', async function () {
+            const window = new FakeWindow({ inputBox: { input: 'live' } })
+            const commands = new FakeCommands()
+
+            await createAlias(functionNode, { window, commands, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.deepStrictEqual(pickedLabels, [['$LATEST', 'Version 2', 'Version 1']])
+            assert.deepStrictEqual(created, [{ FunctionName: 'my-function', Name: 'live', FunctionVersion: '2' }])
+            assert.strictEqual(window.message.information, 'Created alias live of my-function, pointing at version 2')
+            assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+            assert.deepStrictEqual(commands.args, [functionNode])
+        })
+
+        it('rejects alias names that are all digits', async function () {
+            const window = new FakeWindow({ inputBox: { input: '123' } })
+
+            await createAlias(functionNode, { window, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.ok(window.inputBox.errorMessage)
+            assert.deepStrictEqual(created, [])
+        })
+
+        it('does nothing when no version is chosen', async function () {
+            const window = new FakeWindow({ inputBox: { input: 'live' } })
+            const commands = new FakeCommands()
+
+            await createAlias(functionNode, { window, commands, promptUserFunction: pickLabel('none'), lambda })
+
+            assert.deepStrictEqual(created, [])
+            assert.strictEqual(commands.command, undefined)
+        })
+
+        it('shows an error message when creation fails', async function () {
+            requestError = new Error('Expected failure')
+            const window = new FakeWindow({ inputBox: { input: 'live' } })
+
+            await createAlias(functionNode, { window, promptUserFunction: pickLabel('$LATEST'), lambda })
+
+            assert.ok(window.message.error?.startsWith('Failed to create alias live of my-function'))
+        })
+    })
+
+    describe('updateAlias', function () {
+        it('confirms, points the alias at the version, and removes weighted routing', async function () {
+            const node = createAliasNode({
+                Name: 'live',
+                FunctionVersion: '1',
+                RoutingConfig: { AdditionalVersionWeights: { '2': 0.1 } },
+            })
+            const window = new FakeWindow({ message: { warningSelection: 'Update Alias' } })
+            const commands = new FakeCommands()
+
+            await updateAlias(node, { window, commands, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.ok(window.message.warning?.includes('it currently goes to 1 (90%), 2 (10%)'))
+            assert.deepStrictEqual(updated, [
+                {
+                    FunctionName: 'my-function',
+                    Name: 'live',
+                    FunctionVersion: '2',
+                    RoutingConfig: { AdditionalVersionWeights: {} },
+                },
+            ])
+            assert.strictEqual(window.message.information, 'Alias live of my-function now routes to 2')
+            assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+            assert.deepStrictEqual(commands.args, [aliasesNode])
+        })
+
+        it('does nothing when cancelled', async function () {
+            const node = createAliasNode({ Name: 'live', FunctionVersion: '1' })
+            const window = new FakeWindow({ message: { warningSelection: 'Cancel' } })
+            const commands = new FakeCommands()
+
+            await updateAlias(node, { window, commands, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.deepStrictEqual(updated, [])
+            assert.strictEqual(commands.command, undefined)
+        })
+
+        it('shows an error message when the update fails', async function () {
+            requestError = new Error('Expected failure')
+            const node = createAliasNode({ Name: 'live', FunctionVersion: '1' })
+            const window = new FakeWindow({ message: { warningSelection: 'Update Alias' } })
+
+            await updateAlias(node, { window, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.ok(window.message.error?.startsWith('Failed to update alias live of my-function'))
+        })
+    })
+
+    describe('setAliasRouting', function () {
+        it('routes a percentage of the traffic to another published version', async function () {
+            const node = createAliasNode({ Name: 'live', FunctionVersion: '1' })
+            const window = new FakeWindow({
+                inputBox: { input: '12.5' },
+                message: { warningSelection: 'Update Routing' },
+            })
+            const commands = new FakeCommands()
+
+            await setAliasRouting(node, { window, commands, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.deepStrictEqual(pickedLabels, [['Version 2']])
+            assert.strictEqual(
+                window.message.warning,
+                'Route 12.5% of the traffic of alias live to version 2, and the rest to version 1?'
+            )
+            assert.deepStrictEqual(updated, [
+                {
+                    FunctionName: 'my-function',
+                    Name: 'live',
+                    FunctionVersion: '1',
+                    RoutingConfig: { AdditionalVersionWeights: { '2': 0.125 } },
+                },
+            ])
+            assert.strictEqual(
+                window.message.information,
+                'Alias live of my-function now routes to 1 (87.5%), 2 (12.5%)'
+            )
+            assert.deepStrictEqual(commands.args, [aliasesNode])
+        })
+
+        it('rejects percentages outside 0 to 100', async function () {
+            const node = createAliasNode({ Name: 'live', FunctionVersion: '1' })
+            const window = new FakeWindow({ inputBox: { input: '150' } })
+
+            await setAliasRouting(node, { window, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.ok(window.inputBox.errorMessage)
+            assert.deepStrictEqual(updated, [])
+        })
+
+        it('does not route aliases that point at $LATEST', async function () {
+            const node = createAliasNode({ Name: 'beta', FunctionVersion: '$LATEST' })
+            const window = new FakeWindow()
+
+            await setAliasRouting(node, { window, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.ok(window.message.error?.includes('Point it at a published version'))
+            assert.deepStrictEqual(pickedLabels, [])
+            assert.deepStrictEqual(updated, [])
+        })
+
+        it('shows an error message when there is no other published version', async function () {
+            lambda = new MockLambdaClient({ listVersions: () => asyncGenerator([{ Version: '1' }]) })
+            const node = createAliasNode({ Name: 'live', FunctionVersion: '1' })
+            const window = new FakeWindow()
+
+            await setAliasRouting(node, { window, promptUserFunction: pickLabel('Version 2'), lambda })
+
+            assert.ok(window.message.error?.includes('has no other published versions'))
+            assert.deepStrictEqual(pickedLabels, [])
+        })
+    })
+})
@@ -0,0 +1,137 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudWatchLogs, Lambda } from 'aws-sdk'
+import * as vscode from 'vscode'
+import { invokeAndTailLogs, InvocationLogDocumentProvider } from '../../../lambda/commands/invokeAndTailLogs'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { InvokePayloadStore } from '../../../lambda/invokePayloadStore'
+import { FakeExtensionContext } from '../../fakeExtensionContext'
+import { MockOutputChannel } from '../../mockOutputChannel'
+import { MockCloudWatchLogsClient, MockLambdaClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('invokeAndTailLogs', function () {
+    const requestId = '6f8b2a3c-1d2e-4f5a-9b8c-7d6e5f4a3b2c'
+    const node = ({
+        name: 'my-function',
+        functionName: 'my-function',
+        arn: 'arn:aws:lambda:us-west-2:123456789012:function:my-function',
+        regionCode: 'us-west-2',
+    } as unknown) as LambdaFunctionNode
+
+    let provider: InvocationLogDocumentProvider
+    let payloadStore: InvokePayloadStore
+    let outputChannel: MockOutputChannel
+    let uri: vscode.Uri | undefined
+    let invoked: (Lambda._Blob | undefined)[]
+
+    beforeEach(function () {
+        provider = new InvocationLogDocumentProvider()
+        payloadStore = new InvokePayloadStore(new FakeExtensionContext().globalState)
+        outputChannel = new MockOutputChannel()
+        uri = undefined
+        invoked = []
+    })
+
+    async function run(
+        window: FakeWindow,
+        invoke: () => Promise<Lambda.InvocationResponse>,
+        filterLogEvents: () => Promise<CloudWatchLogs.FilterLogEventsResponse> = async () => ({})
+    ): Promise<void> {
+        await invokeAndTailLogs(node, provider, {
+            lambdaClient: new MockLambdaClient({
+                invoke: async (name, payload) => {
+                    invoked.push(payload)
+                    return await invoke()
+                },
+            }),
+            logsClient: new MockCloudWatchLogsClient(
+                'us-west-2',
+                undefined,
+                undefined,
+                undefined,
+                undefined,
+                undefined,
+                undefined,
+                filterLogEvents
+            ),
+            window,
+            outputChannel,
+            payloadStore,
+            accountId: '123456789012',
+            pollIntervalMillis: 1,
+            gracePeriodMillis: 5,
+            timeoutMillis: 1000,
+            showLogs: async logsUri => {
+                uri = logsUri
+                return undefined
+            },
+        })
+    }
+
+    it('invokes the function and collects its logs until the invocation ends', async function () {
+        const events: CloudWatchLogs.FilteredLogEvent[] = []
+        const logs = `START RequestId: ${requestId} Version: $LATEST\n`
+        let polls = 0
+
+        await run(
+            new FakeWindow({ inputBox: { input: '{"key": "value"}' } }),
+            async () => {
+                events.push(
+                    { eventId: '1', timestamp: Date.now(), message: logs },
+                    { eventId: '2', timestamp: Date.now(), message: 'hello from the function\n' }
+                )
+                return { LogResult: Buffer.from(logs).toString('base64'), Payload: '"ok"' }
+            },
+            async () => {
+                // The end of the invocation is logged a few polls later
+                if (++polls === 3) {
+                    events.push({ eventId: '3', timestamp: Date.now(), message: `REPORT RequestId: ${requestId}\n` })
+                }
+                return { events: [...events] }
+            }
+        )
+
+        assert.deepStrictEqual(invoked, ['{"key": "value"}'])
+        assert.ok(uri)
+        const content = provider.provideTextDocumentContent(uri!)
+        assert.ok(content.includes('hello from the function'), content)
+        assert.ok(content.includes(`REPORT RequestId: ${requestId}`), content)
+        assert.ok(outputChannel.value.includes(`request ID: ${requestId}`))
+        assert.ok(outputChannel.value.includes('"ok"'))
+        assert.strictEqual(await payloadStore.load('123456789012', node.arn), '{"key": "value"}')
+    })
+
+    it('offers the last payload', async function () {
+        await payloadStore.set('123456789012', node.arn, { json: '{"last": true}' })
+        const window = new FakeWindow()
+
+        await run(window, async () => ({}))
+
+        assert.strictEqual(window.inputBox.options?.value, '{"last": true}')
+        assert.deepStrictEqual(invoked, [])
+    })
+
+    it('rejects payloads that are not JSON', async function () {
+        const window = new FakeWindow({ inputBox: { input: '{' } })
+
+        await run(window, async () => ({}))
+
+        assert.strictEqual(window.inputBox.errorMessage, 'Payload must be valid JSON')
+        assert.strictEqual(uri, undefined)
+    })
+
+    it('shows an error when the invoke fails', async function () {
+        const window = new FakeWindow({ inputBox: { input: '{}' } })
+
+        await run(window, async () => {
+            throw new Error('Function not found')
+        })
+
+        assert.ok(window.message.error?.includes('Function not found'))
+    })
+})
@@ -0,0 +1,75 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { publishVersion } from '../../../lambda/commands/publishVersion'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('publishVersion', function () {
+    const node = ({ functionName: 'my-function', regionCode: 'us-west-2' } as unknown) as LambdaFunctionNode
+
+    let published: [string, string | undefined][]
+    let publishError: Error | undefined
+    let lambda: MockLambdaClient
+
+    beforeEach(function () {
+        published = []
+        publishError = undefined
+        lambda = new MockLambdaClient({
+            publishVersion: async (name, description) => {
+                if (publishError) {
+                    throw publishError
+                }
+                published.push([name, description])
+
+                return { Version: '4' }
+            },
+        })
+    })
+
+    it('publishes a version with a description and refreshes the function node', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'release candidate' } })
+        const commands = new FakeCommands()
+
+        await publishVersion(node, window, commands, lambda)
+
+        assert.deepStrictEqual(published, [['my-function', 'release candidate']])
+        assert.strictEqual(window.message.information, 'Published version 4 of my-function')
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [node])
+    })
+
+    it('publishes a version without a description', async function () {
+        const window = new FakeWindow({ inputBox: { input: '' } })
+
+        await publishVersion(node, window, new FakeCommands(), lambda)
+
+        assert.deepStrictEqual(published, [['my-function', undefined]])
+    })
+
+    it('does nothing when cancelled', async function () {
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await publishVersion(node, window, commands, lambda)
+
+        assert.deepStrictEqual(published, [])
+        assert.strictEqual(commands.command, undefined)
+    })
+
+    it('shows an error message when publishing fails', async function () {
+        publishError = new Error('Expected failure')
+        const window = new FakeWindow({ inputBox: { input: '' } })
+        const commands = new FakeCommands()
+
+        await publishVersion(node, window, commands, lambda)
+
+        assert.ok(window.message.error?.startsWith('Failed to publish a version of my-function'))
+        assert.strictEqual(commands.command, undefined)
+    })
+})
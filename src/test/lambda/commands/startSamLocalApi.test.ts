@@ -0,0 +1,194 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import {
+    getActiveLocalApi,
+    getCodeFolders,
+    isPortInUseError,
+    makeLocalApiAttachConfig,
+    startSamLocalApi,
+    stopSamLocalApi,
+} from '../../../lambda/commands/startSamLocalApi'
+import { RuntimeFamily } from '../../../lambda/models/samLambdaRuntime'
+import { CloudFormation } from '../../../shared/cloudformation/cloudformation'
+import { makeTemporaryToolkitFolder } from '../../../shared/filesystemUtilities'
+import { ChildProcess, ChildProcessStartArguments } from '../../../shared/utilities/childProcess'
+import { waitUntil } from '../../../shared/utilities/timeoutUtils'
+import { MockOutputChannel } from '../../mockOutputChannel'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('startSamLocalApi', function () {
+    const port = '49321'
+    let tempFolder: string
+    let templatePath: string
+    let outputChannel: MockOutputChannel
+    let started: { command: string; args: string[]; params?: ChildProcessStartArguments }[]
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        templatePath = path.join(tempFolder, 'template.yaml')
+        await fs.writeFile(templatePath, '')
+        outputChannel = new MockOutputChannel()
+        started = []
+    })
+
+    afterEach(async function () {
+        if (getActiveLocalApi()) {
+            await stopSamLocalApi(new FakeWindow())
+        }
+        await fs.remove(tempFolder)
+    })
+
+    function createProcess(command: string, args: string[]): ChildProcess {
+        const process = { command, args, params: undefined as ChildProcessStartArguments | undefined }
+        started.push(process)
+        let stopped = false
+
+        return ({
+            start: async (params: ChildProcessStartArguments) => {
+                process.params = params
+            },
+            stop: () => {
+                stopped = true
+                process.params?.onClose?.(0, '')
+            },
+            get stopped() {
+                return stopped
+            },
+            toString: () => `[${command} ${args.join(' ')}]`,
+        } as any) as ChildProcess
+    }
+
+    async function start(window: FakeWindow, startDebugging?: typeof vscode.debug.startDebugging): Promise<void> {
+        await startSamLocalApi(
+            {
+                awsContext: { getCredentials: async () => undefined },
+                getSamCliPath: async () => '/bin/sam',
+                outputChannel,
+                window,
+                createProcess,
+                startDebugging,
+            },
+            { fsPath: templatePath } as any
+        )
+    }
+
+    it('starts the local API with the chosen ports, and streams its output', async function () {
+        await start(new FakeWindow({ inputBox: { input: port } }))
+
+        assert.strictEqual(started.length, 1)
+        assert.strictEqual(started[0].command, '/bin/sam')
+        assert.deepStrictEqual(started[0].args.slice(0, 2), ['local', 'start-api'])
+        assert.strictEqual(started[0].args[started[0].args.indexOf('--port') + 1], port)
+        assert.strictEqual(started[0].args[started[0].args.indexOf('--debug-port') + 1], port)
+
+        started[0].params!.onStderr!('\u001b[32mGET /hello 200\u001b[0m\n')
+        assert.ok(outputChannel.value.includes('GET /hello 200\n'))
+        assert.ok(outputChannel.isShown)
+    })
+
+    it('only runs one local API at a time', async function () {
+        await start(new FakeWindow({ inputBox: { input: port } }))
+        const window = new FakeWindow({ inputBox: { input: port } })
+
+        await start(window)
+
+        assert.strictEqual(started.length, 1)
+        assert.ok(window.message.information?.includes('already running on port 49321'))
+    })
+
+    it('stops the local API', async function () {
+        await start(new FakeWindow({ inputBox: { input: port } }))
+        const window = new FakeWindow()
+
+        await stopSamLocalApi(window)
+
+        assert.strictEqual(getActiveLocalApi(), undefined)
+        assert.strictEqual(window.message.information, 'Stopped the local API on port 49321.')
+        assert.ok(outputChannel.value.includes('The local API stopped'))
+    })
+
+    it('suggests another port when SAM CLI cannot use the port', async function () {
+        const window = new FakeWindow({ inputBox: { input: port } })
+        await start(window)
+
+        started[0].params!.onStderr!('OSError: [Errno 98] Address already in use\n')
+        started[0].params!.onClose!(1, '')
+
+        assert.strictEqual(getActiveLocalApi(), undefined)
+        await waitUntil(async () => window.message.error, { timeout: 2000, interval: 50, truthy: true })
+        assert.ok(window.message.error?.startsWith('Port 49321 is already in use'))
+    })
+
+    it('offers to attach a debugger to a request waiting for one', async function () {
+        const debugConfigs: vscode.DebugConfiguration[] = []
+        const window = new FakeWindow({
+            inputBox: { input: port },
+            message: { informationSelection: 'Attach Debugger' },
+        })
+        await start(window, async (_, config) => {
+            debugConfigs.push(config as vscode.DebugConfiguration)
+
+            return true
+        })
+
+        started[0].params!.onStderr!('Debugger listening on ws://0.0.0.0:49321/c5a7d2a0\n')
+
+        await waitUntil(async () => debugConfigs.length, { timeout: 2000, interval: 50, truthy: true })
+        assert.ok(window.message.information?.includes('waiting for a debugger on port 49321'))
+        assert.strictEqual(debugConfigs[0].type, 'node')
+        assert.strictEqual(debugConfigs[0].request, 'attach')
+        assert.strictEqual(debugConfigs[0].port, Number(port))
+        assert.strictEqual(debugConfigs[0].localRoot, tempFolder)
+        assert.strictEqual(debugConfigs[0].remoteRoot, '/var/task')
+    })
+
+    it('makes attach configurations for the runtimes started in debug mode by SAM CLI', function () {
+        const python = makeLocalApiAttachConfig(RuntimeFamily.Python, 5858, tempFolder)
+        assert.strictEqual(python?.type, 'python')
+        assert.ok(python?.pathMappings.every((mapping: { remoteRoot: string }) => mapping.remoteRoot === '/var/task'))
+
+        assert.strictEqual(makeLocalApiAttachConfig(RuntimeFamily.Java, 5858, tempFolder)?.type, 'java')
+        // .NET and Go need a debugger in the container
+        assert.strictEqual(makeLocalApiAttachConfig(RuntimeFamily.DotNetCore, 5858, tempFolder), undefined)
+        assert.strictEqual(makeLocalApiAttachConfig(RuntimeFamily.Go, 5858, tempFolder), undefined)
+    })
+
+    it('tells port-in-use errors apart from other errors', function () {
+        assert.ok(isPortInUseError('OSError: [Errno 48] Address already in use'))
+        assert.ok(isPortInUseError('OSError: [WinError 10048] Only one usage of each socket address'))
+        assert.ok(!isPortInUseError('Error: Template file not found'))
+    })
+
+    it('watches the code of each function', function () {
+        const template: CloudFormation.Template = {
+            Globals: { Function: { CodeUri: 'src' } },
+            Resources: {
+                Hello: { Type: CloudFormation.SERVERLESS_FUNCTION_TYPE, Properties: { Handler: 'app.hello' } as any },
+                World: {
+                    Type: CloudFormation.SERVERLESS_FUNCTION_TYPE,
+                    Properties: { Handler: 'app.world', CodeUri: 'world' } as any,
+                },
+                Image: {
+                    Type: CloudFormation.SERVERLESS_FUNCTION_TYPE,
+                    Properties: { PackageType: 'Image' } as any,
+                },
+                Remote: {
+                    Type: CloudFormation.SERVERLESS_FUNCTION_TYPE,
+                    Properties: { Handler: 'app.remote', CodeUri: 's3://bucket/code.zip' } as any,
+                },
+            },
+        }
+
+        assert.deepStrictEqual(getCodeFolders(templatePath, template), [
+            path.join(tempFolder, 'src'),
+            path.join(tempFolder, 'world'),
+        ])
+    })
+})
@@ -0,0 +1,162 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import {
+    getActiveSync,
+    LAST_SYNC_TARGET_KEY,
+    SamSyncTarget,
+    stopSamSync,
+    syncSamApplication,
+} from '../../../lambda/commands/syncSamApplication'
+import { SamDeployWizardResponse } from '../../../lambda/wizards/samDeployWizard'
+import { SamCliContext } from '../../../shared/sam/cli/samCliContext'
+import { SamCliProcessInvoker } from '../../../shared/sam/cli/samCliInvokerUtils'
+import { FakeExtensionContext, FakeSamCliValidator } from '../../fakeExtensionContext'
+import { getTestLogger } from '../../globalSetup.test'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('syncSamApplication', function () {
+    const awsContext = {
+        getCredentials: async () => ({ accessKeyId: 'key', secretAccessKey: 'secret' } as any as AWS.Credentials),
+        getCredentialProfileName: () => 'profile',
+    }
+    const wizardResponse: SamDeployWizardResponse = {
+        template: vscode.Uri.file('/app/template.yaml'),
+        region: 'us-west-2',
+        stackName: 'my-stack',
+        s3Bucket: 'bucket',
+        parameterOverrides: new Map([['Stage', 'dev']]),
+    }
+
+    let state: vscode.Memento
+    let terminalOptions: vscode.TerminalOptions[]
+    let disposedTerminals: number
+    let wizardCalls: number
+    let wizardResult: SamDeployWizardResponse | undefined
+
+    function samCliContext(version: string = '1.53.0'): SamCliContext {
+        return { validator: new FakeSamCliValidator(version), invoker: {} as any as SamCliProcessInvoker }
+    }
+
+    async function sync(window: FakeWindow, version?: string): Promise<void> {
+        await syncSamApplication(
+            {
+                samCliContext: samCliContext(version),
+                samDeployWizard: async () => {
+                    wizardCalls++
+                    return wizardResult
+                },
+                getSamCliPath: async () => '/bin/sam.exe',
+            },
+            {
+                awsContext,
+                state,
+                window,
+                createTerminal: options => {
+                    terminalOptions.push(options)
+                    return {
+                        show: () => {},
+                        dispose: () => {
+                            disposedTerminals++
+                        },
+                    } as any as vscode.Terminal
+                },
+            }
+        )
+    }
+
+    beforeEach(function () {
+        state = new FakeExtensionContext().globalState
+        terminalOptions = []
+        disposedTerminals = 0
+        wizardCalls = 0
+        wizardResult = wizardResponse
+    })
+
+    afterEach(function () {
+        if (getActiveSync()) {
+            stopSamSync(new FakeWindow())
+        }
+    })
+
+    it('runs sam sync --watch in a terminal and remembers the target', async function () {
+        await sync(new FakeWindow())
+
+        assert.strictEqual(terminalOptions.length, 1)
+        const shellArgs = terminalOptions[0].shellArgs as string[]
+        assert.ok(shellArgs.includes('--watch'))
+        assert.ok(shellArgs.includes('my-stack'))
+        assert.ok(shellArgs.includes('Stage=dev'))
+        assert.strictEqual(terminalOptions[0].env?.AWS_ACCESS_KEY_ID, 'key')
+
+        const target = state.get<SamSyncTarget>(LAST_SYNC_TARGET_KEY)
+        assert.strictEqual(target?.stackName, 'my-stack')
+        assert.strictEqual(target?.profile, 'profile')
+        assert.deepStrictEqual(target?.parameterOverrides, [['Stage', 'dev']])
+    })
+
+    it('does not save or log the values of NoEcho parameters', async function () {
+        wizardResult = {
+            ...wizardResponse,
+            parameterOverrides: new Map([
+                ['Stage', 'dev'],
+                ['DbPassword', 'hunter2'],
+            ]),
+            noEchoParameters: new Set(['DbPassword', 'ApiKey']),
+        }
+
+        await sync(new FakeWindow())
+
+        assert.ok((terminalOptions[0].shellArgs as string[]).includes('DbPassword=hunter2'))
+        const target = state.get<SamSyncTarget>(LAST_SYNC_TARGET_KEY)
+        assert.deepStrictEqual(target?.parameterOverrides, [['Stage', 'dev']])
+        assert.deepStrictEqual(target?.noEchoParameters, ['DbPassword'])
+        const logged = JSON.stringify(getTestLogger().getLoggedEntries())
+        assert.ok(logged.includes('DbPassword=****'), logged)
+        assert.ok(!logged.includes('hunter2'), logged)
+    })
+
+    it('does not start a second sync while one is running', async function () {
+        await sync(new FakeWindow())
+        const window = new FakeWindow()
+
+        await sync(window)
+
+        assert.strictEqual(terminalOptions.length, 1)
+        assert.ok(window.message.information?.includes('already running'))
+    })
+
+    it('stops the running sync', async function () {
+        await sync(new FakeWindow())
+        const window = new FakeWindow()
+
+        stopSamSync(window)
+
+        assert.strictEqual(disposedTerminals, 1)
+        assert.strictEqual(getActiveSync(), undefined)
+        assert.strictEqual(window.message.information, 'Stopped SAM sync for stack my-stack.')
+    })
+
+    it('shows an error when SAM CLI does not support sync', async function () {
+        const window = new FakeWindow()
+
+        await sync(window, '1.52.0')
+
+        assert.ok(window.message.error?.includes('requires SAM CLI 1.53.0 or newer'))
+        assert.strictEqual(wizardCalls, 0)
+        assert.strictEqual(terminalOptions.length, 0)
+    })
+
+    it('does nothing when the wizard is cancelled', async function () {
+        wizardResult = undefined
+
+        await sync(new FakeWindow())
+
+        assert.strictEqual(terminalOptions.length, 0)
+        assert.strictEqual(state.get(LAST_SYNC_TARGET_KEY), undefined)
+    })
+})
@@ -0,0 +1,88 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudWatchLogs } from 'aws-sdk'
+import { SelectLogStreamResponse } from '../../../cloudWatchLogs/commands/viewLogStream'
+import { LogStreamRegistry } from '../../../cloudWatchLogs/registry/logStreamRegistry'
+import { viewLambdaLogs } from '../../../lambda/commands/viewLambdaLogs'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { MockCloudWatchLogsClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('viewLambdaLogs', function () {
+    const node = { functionName: 'my-function', regionCode: 'us-west-2' } as LambdaFunctionNode
+    const registry = {} as LogStreamRegistry
+    let requests: CloudWatchLogs.DescribeLogStreamsRequest[]
+    let opened: SelectLogStreamResponse[]
+
+    async function view(
+        describeLogStreams: () => Promise<CloudWatchLogs.DescribeLogStreamsResponse>,
+        window: FakeWindow = new FakeWindow()
+    ): Promise<void> {
+        await viewLambdaLogs(node, registry, {
+            client: new MockCloudWatchLogsClient('us-west-2', undefined, async request => {
+                requests.push(request)
+
+                return await describeLogStreams()
+            }),
+            window,
+            open: async logStream => {
+                opened.push(logStream)
+            },
+        })
+    }
+
+    beforeEach(function () {
+        requests = []
+        opened = []
+    })
+
+    it('opens the most recent log stream of the function', async function () {
+        await view(async () => ({ logStreams: [{ logStreamName: '2021/06/01/[$LATEST]abc' }] }))
+
+        assert.deepStrictEqual(requests, [
+            { logGroupName: '/aws/lambda/my-function', orderBy: 'LastEventTime', descending: true, limit: 1 },
+        ])
+        assert.deepStrictEqual(opened, [
+            { region: 'us-west-2', logGroupName: '/aws/lambda/my-function', logStreamName: '2021/06/01/[$LATEST]abc' },
+        ])
+    })
+
+    it('shows a message when the log group does not exist yet', async function () {
+        const window = new FakeWindow()
+
+        await view(async () => {
+            throw Object.assign(new Error('The specified log group does not exist.'), {
+                code: 'ResourceNotFoundException',
+            })
+        }, window)
+
+        assert.strictEqual(
+            window.message.information,
+            'There are no logs for my-function yet. Logs appear after the function is invoked.'
+        )
+        assert.strictEqual(window.message.error, undefined)
+        assert.strictEqual(opened.length, 0)
+    })
+
+    it('shows a message when the log group has no streams', async function () {
+        const window = new FakeWindow()
+
+        await view(async () => ({ logStreams: [] }), window)
+
+        assert.ok(window.message.information?.startsWith('There are no logs for my-function yet.'))
+    })
+
+    it('shows other errors', async function () {
+        const window = new FakeWindow()
+
+        await view(async () => {
+            throw new Error('Access denied')
+        }, window)
+
+        assert.strictEqual(window.message.error, 'Failed to open logs for my-function: Access denied')
+    })
+})
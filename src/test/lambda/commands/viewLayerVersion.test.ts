@@ -0,0 +1,62 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { Lambda } from 'aws-sdk'
+import { formatLayerVersionDetails, viewLayerVersion } from '../../../lambda/commands/viewLayerVersion'
+import { LambdaLayerVersionNode } from '../../../lambda/explorer/lambdaLayerNodes'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('viewLayerVersion', function () {
+    const arn = 'arn:aws:lambda:us-west-2:123456789012:layer:my-layer:3'
+    const layerVersion = {
+        LayerVersionArn: arn,
+        Description: 'my layer',
+        CompatibleRuntimes: ['nodejs14.x'],
+        CompatibleArchitectures: ['arm64'],
+        Content: { Location: 'https://presigned', CodeSize: 42, CodeSha256: 'sha' },
+    } as Lambda.GetLayerVersionResponse
+
+    it('opens the layer version details', async function () {
+        const lambda = new MockLambdaClient({ getLayerVersion: async () => layerVersion })
+        let content: string | undefined
+
+        await viewLayerVersion(
+            new LambdaLayerVersionNode(new TestAWSTreeNode('parent'), lambda, arn),
+            new FakeWindow(),
+            async text => (content = text)
+        )
+
+        assert.strictEqual(content, formatLayerVersionDetails(layerVersion))
+    })
+
+    it('shows an error message when the layer version cannot be retrieved', async function () {
+        const lambda = new MockLambdaClient({
+            getLayerVersion: async () => {
+                throw new Error('Expected failure')
+            },
+        })
+        const window = new FakeWindow()
+
+        await viewLayerVersion(
+            new LambdaLayerVersionNode(new TestAWSTreeNode('parent'), lambda, arn),
+            window,
+            async () => assert.fail('should not open a document')
+        )
+
+        assert.ok(window.message.error?.startsWith('Failed to get layer version my-layer:3'))
+    })
+
+    it('includes compatible runtimes and architectures but not the content location', function () {
+        const details = JSON.parse(formatLayerVersionDetails(layerVersion))
+
+        assert.deepStrictEqual(details.CompatibleRuntimes, ['nodejs14.x'])
+        assert.deepStrictEqual(details.CompatibleArchitectures, ['arm64'])
+        assert.strictEqual(details.CodeSize, 42)
+        assert.ok(!JSON.stringify(details).includes('presigned'))
+    })
+})
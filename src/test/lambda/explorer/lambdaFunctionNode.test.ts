@@ -6,8 +6,13 @@
 import * as assert from 'assert'
 import { Lambda } from 'aws-sdk'
 import * as os from 'os'
+import * as vscode from 'vscode'
 import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import { LambdaLayerVersionNode } from '../../../lambda/explorer/lambdaLayerNodes'
+import { LambdaAliasesNode, LambdaVersionsNode } from '../../../lambda/explorer/lambdaQualifierNodes'
+import { ToolkitClientBuilder } from '../../../shared/clients/toolkitClientBuilder'
 import { ext } from '../../../shared/extensionGlobals'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
 import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
 import { clearTestIconPaths, IconPath, setupTestIconPaths } from '../../shared/utilities/iconPathUtils'
 
@@ -64,9 +69,40 @@ describe('LambdaFunctionNode', function () {
         assert.strictEqual(iconPath.light.path, ext.iconPaths.light.lambda, 'Unexpected light icon path')
     })
 
-    it('has no children', async function () {
+    it('has folders for its versions and aliases', async function () {
+        ext.toolkitClientBuilder = ({
+            createLambdaClient: () => new MockLambdaClient({}),
+        } as any) as ToolkitClientBuilder
+
         const childNodes = await testNode.getChildren()
-        assert.ok(childNodes)
-        assert.strictEqual(childNodes.length, 0, 'Expected node to have no children')
+
+        assert.strictEqual(testNode.collapsibleState, vscode.TreeItemCollapsibleState.Collapsed)
+        assert.strictEqual(childNodes.length, 2, 'Unexpected child count')
+        assert.ok(childNodes[0] instanceof LambdaVersionsNode, 'Expected the first child to be the versions')
+        assert.ok(childNodes[1] instanceof LambdaAliasesNode, 'Expected the second child to be the aliases')
+    })
+
+    it('has child nodes for its layers', async function () {
+        ext.toolkitClientBuilder = ({
+            createLambdaClient: () => new MockLambdaClient({}),
+        } as any) as ToolkitClientBuilder
+        const node = new LambdaFunctionNode(parentNode, 'someregioncode', {
+            FunctionName: 'testFunctionName',
+            FunctionArn: 'arn:aws:lambda:us-west-2:123456789012:function:testFunctionName',
+            Layers: [
+                { Arn: 'arn:aws:lambda:us-west-2:123456789012:layer:my-layer:3' },
+                { Arn: 'arn:aws:lambda:us-west-2:999999999999:layer:shared:7' },
+            ],
+        })
+
+        const childNodes = (await node.getChildren()).filter(child => child instanceof LambdaLayerVersionNode)
+
+        assert.deepStrictEqual(
+            (childNodes as LambdaLayerVersionNode[]).map(child => [child.name, child.readOnly]),
+            [
+                ['my-layer:3', false],
+                ['shared:7', true],
+            ]
+        )
     })
 })
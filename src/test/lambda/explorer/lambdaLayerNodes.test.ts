@@ -0,0 +1,133 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import {
+    CONTEXT_VALUE_LAYER_VERSION,
+    CONTEXT_VALUE_LAYER_VERSION_READ_ONLY,
+    LambdaLayerNode,
+    LambdaLayersNode,
+    LambdaLayerVersionNode,
+    makeLayerReferenceNodes,
+    parseLayerVersionArn,
+} from '../../../lambda/explorer/lambdaLayerNodes'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import {
+    assertNodeListOnlyContainsErrorNode,
+    assertNodeListOnlyContainsPlaceholderNode,
+} from '../../utilities/explorerNodeAssertions'
+
+const LAYER_ARN = 'arn:aws:lambda:us-west-2:123456789012:layer:my-layer'
+
+describe('parseLayerVersionArn', function () {
+    it('parses a layer version ARN', function () {
+        assert.deepStrictEqual(parseLayerVersionArn(`${LAYER_ARN}:3`), {
+            accountId: '123456789012',
+            layerName: 'my-layer',
+            version: 3,
+        })
+    })
+
+    it('rejects ARNs without a version', function () {
+        assert.strictEqual(parseLayerVersionArn(LAYER_ARN), undefined)
+        assert.strictEqual(parseLayerVersionArn('arn:aws:lambda:us-west-2:123456789012:function:foo'), undefined)
+    })
+})
+
+describe('LambdaLayersNode', function () {
+    it('has sorted LambdaLayerNode child nodes', async function () {
+        const lambda = new MockLambdaClient({
+            listLayers: () => asyncGenerator([{ LayerName: 'zebra' }, { LayerName: 'aardvark' }]),
+        })
+
+        const childNodes = await new LambdaLayersNode(lambda).getChildren()
+
+        childNodes.forEach(node => assert.ok(node instanceof LambdaLayerNode))
+        assert.deepStrictEqual(
+            childNodes.map(node => node.label),
+            ['aardvark', 'zebra']
+        )
+    })
+
+    it('returns placeholder node if no children are present', async function () {
+        const childNodes = await new LambdaLayersNode(new MockLambdaClient({})).getChildren()
+
+        assertNodeListOnlyContainsPlaceholderNode(childNodes)
+    })
+
+    it('has an error node for a child if an error happens during loading', async function () {
+        const lambda = new MockLambdaClient({
+            listLayers: () => {
+                throw new Error('Expected failure')
+            },
+        })
+
+        const childNodes = await new LambdaLayersNode(lambda).getChildren()
+
+        assertNodeListOnlyContainsErrorNode(childNodes)
+    })
+})
+
+describe('LambdaLayerNode', function () {
+    it('has version child nodes, newest first', async function () {
+        const lambda = new MockLambdaClient({
+            listLayerVersions: () =>
+                asyncGenerator([
+                    { LayerVersionArn: `${LAYER_ARN}:1`, Version: 1 },
+                    { LayerVersionArn: `${LAYER_ARN}:2`, Version: 2, Description: 'newer' },
+                ]),
+        })
+        const node = new LambdaLayerNode(new TestAWSTreeNode('parent'), lambda, { LayerName: 'my-layer' })
+
+        const childNodes = (await node.getChildren()) as LambdaLayerVersionNode[]
+
+        assert.deepStrictEqual(
+            childNodes.map(child => [child.label, child.description, child.name]),
+            [
+                ['Version 2', 'newer', 'my-layer:2'],
+                ['Version 1', undefined, 'my-layer:1'],
+            ]
+        )
+        childNodes.forEach(child => assert.strictEqual(child.contextValue, CONTEXT_VALUE_LAYER_VERSION))
+    })
+
+    it('returns placeholder node if no versions are present', async function () {
+        const node = new LambdaLayerNode(new TestAWSTreeNode('parent'), new MockLambdaClient({}), {
+            LayerName: 'my-layer',
+        })
+
+        assertNodeListOnlyContainsPlaceholderNode(await node.getChildren())
+    })
+})
+
+describe('makeLayerReferenceNodes', function () {
+    const parent = new TestAWSTreeNode('function')
+    const layers = [{ Arn: `${LAYER_ARN}:3` }, { Arn: 'arn:aws:lambda:us-west-2:999999999999:layer:shared:7' }]
+
+    it('makes layers of other accounts read-only', function () {
+        const nodes = makeLayerReferenceNodes(parent, new MockLambdaClient({}), layers, '123456789012')
+
+        assert.deepStrictEqual(
+            nodes.map(node => [node.label, node.description, node.readOnly]),
+            [
+                ['my-layer:3', undefined, false],
+                ['shared:7', 'Shared by account 999999999999', true],
+            ]
+        )
+        assert.strictEqual(nodes[1].contextValue, CONTEXT_VALUE_LAYER_VERSION_READ_ONLY)
+        assert.strictEqual(nodes[1].parent, parent)
+    })
+
+    it('assumes layers are owned by the current account if it is unknown', function () {
+        const nodes = makeLayerReferenceNodes(parent, new MockLambdaClient({}), layers, undefined)
+
+        assert.deepStrictEqual(
+            nodes.map(node => node.readOnly),
+            [false, false]
+        )
+    })
+})
@@ -0,0 +1,150 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { LambdaFunctionNode } from '../../../lambda/explorer/lambdaFunctionNode'
+import {
+    describeAliasRouting,
+    LambdaAliasesNode,
+    LambdaAliasNode,
+    LambdaVersionNode,
+    LambdaVersionsNode,
+} from '../../../lambda/explorer/lambdaQualifierNodes'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+import { TestAWSTreeNode } from '../../shared/treeview/nodes/testAWSTreeNode'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import {
+    assertNodeListOnlyContainsErrorNode,
+    assertNodeListOnlyContainsPlaceholderNode,
+} from '../../utilities/explorerNodeAssertions'
+
+const FUNCTION_ARN = 'arn:aws:lambda:us-west-2:123456789012:function:myFunction'
+
+describe('describeAliasRouting', function () {
+    it('describes an alias that routes to one version', function () {
+        assert.strictEqual(describeAliasRouting({ Name: 'live', FunctionVersion: '3' }), '3')
+    })
+
+    it('describes the weights of an alias that routes to several versions', function () {
+        const description = describeAliasRouting({
+            Name: 'live',
+            FunctionVersion: '3',
+            RoutingConfig: { AdditionalVersionWeights: { '4': 0.1 } },
+        })
+
+        assert.strictEqual(description, '3 (90%), 4 (10%)')
+    })
+
+    it('keeps fractional percentages', function () {
+        const description = describeAliasRouting({
+            Name: 'live',
+            FunctionVersion: '3',
+            RoutingConfig: { AdditionalVersionWeights: { '4': 0.00125 } },
+        })
+
+        assert.strictEqual(description, '3 (99.875%), 4 (0.125%)')
+    })
+})
+
+describe('LambdaVersionsNode', function () {
+    const functionNode = new LambdaFunctionNode(new TestAWSTreeNode('test node'), 'us-west-2', {
+        FunctionName: 'myFunction',
+        FunctionArn: FUNCTION_ARN,
+    })
+
+    it('has published version child nodes, newest first', async function () {
+        const lambda = new MockLambdaClient({
+            listVersions: () =>
+                asyncGenerator([
+                    { Version: '$LATEST', FunctionArn: `${FUNCTION_ARN}:$LATEST` },
+                    { Version: '2', FunctionArn: `${FUNCTION_ARN}:2` },
+                    { Version: '10', FunctionArn: `${FUNCTION_ARN}:10`, Description: 'release' },
+                ]),
+        })
+
+        const childNodes = await new LambdaVersionsNode(functionNode, lambda).getChildren()
+
+        childNodes.forEach(node => assert.ok(node instanceof LambdaVersionNode))
+        assert.deepStrictEqual(
+            childNodes.map(node => node.label),
+            ['Version 10', 'Version 2']
+        )
+        const newest = childNodes[0] as LambdaVersionNode
+        assert.strictEqual(newest.qualifier, '10')
+        assert.strictEqual(newest.description, 'release')
+        assert.strictEqual(newest.functionNode, functionNode)
+    })
+
+    it('returns placeholder node if only $LATEST exists', async function () {
+        const lambda = new MockLambdaClient({ listVersions: () => asyncGenerator([{ Version: '$LATEST' }]) })
+
+        const childNodes = await new LambdaVersionsNode(functionNode, lambda).getChildren()
+
+        assertNodeListOnlyContainsPlaceholderNode(childNodes)
+    })
+
+    it('handles error', async function () {
+        const lambda = new MockLambdaClient({
+            listVersions: () => {
+                throw new Error('Expected failure')
+            },
+        })
+
+        const childNodes = await new LambdaVersionsNode(functionNode, lambda).getChildren()
+
+        assertNodeListOnlyContainsErrorNode(childNodes)
+    })
+})
+
+describe('LambdaAliasesNode', function () {
+    const functionNode = new LambdaFunctionNode(new TestAWSTreeNode('test node'), 'us-west-2', {
+        FunctionName: 'myFunction',
+        FunctionArn: FUNCTION_ARN,
+    })
+
+    it('has sorted alias child nodes labeled with their routing', async function () {
+        const lambda = new MockLambdaClient({
+            listAliases: () =>
+                asyncGenerator([
+                    {
+                        Name: 'live',
+                        FunctionVersion: '3',
+                        RoutingConfig: { AdditionalVersionWeights: { '4': 0.25 } },
+                    },
+                    { Name: 'beta', FunctionVersion: '$LATEST' },
+                ]),
+        })
+
+        const childNodes = await new LambdaAliasesNode(functionNode, lambda).getChildren()
+
+        childNodes.forEach(node => assert.ok(node instanceof LambdaAliasNode))
+        assert.deepStrictEqual(
+            childNodes.map(node => node.label),
+            ['beta → $LATEST', 'live → 3 (75%), 4 (25%)']
+        )
+        assert.deepStrictEqual(
+            (childNodes as LambdaAliasNode[]).map(node => node.qualifier),
+            ['beta', 'live']
+        )
+    })
+
+    it('returns placeholder node if no children are present', async function () {
+        const childNodes = await new LambdaAliasesNode(functionNode, new MockLambdaClient({})).getChildren()
+
+        assertNodeListOnlyContainsPlaceholderNode(childNodes)
+    })
+
+    it('handles error', async function () {
+        const lambda = new MockLambdaClient({
+            listAliases: () => {
+                throw new Error('Expected failure')
+            },
+        })
+
+        const childNodes = await new LambdaAliasesNode(functionNode, lambda).getChildren()
+
+        assertNodeListOnlyContainsErrorNode(childNodes)
+    })
+})
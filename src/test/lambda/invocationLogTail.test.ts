@@ -0,0 +1,132 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudWatchLogs, Lambda } from 'aws-sdk'
+import { formatLogEvent, getRequestId, InvocationLogTail } from '../../lambda/invocationLogTail'
+import { MockCloudWatchLogsClient } from '../shared/clients/mockClients'
+
+const REQUEST_ID = '6f8b2a3c-1d2e-4f5a-9b8c-7d6e5f4a3b2c'
+
+function makeTail(
+    filterLogEvents: (request: CloudWatchLogs.FilterLogEventsRequest) => Promise<CloudWatchLogs.FilterLogEventsResponse>
+): InvocationLogTail {
+    const client = new MockCloudWatchLogsClient(
+        'us-west-2',
+        undefined,
+        undefined,
+        undefined,
+        undefined,
+        undefined,
+        undefined,
+        filterLogEvents
+    )
+
+    return new InvocationLogTail(client, '/aws/lambda/my-function', 100)
+}
+
+function makeEvent(eventId: string, timestamp: number, message: string): CloudWatchLogs.FilteredLogEvent {
+    return { eventId, timestamp, message, logStreamName: 'stream' }
+}
+
+describe('getRequestId', function () {
+    it('finds the request ID in the log tail of the invocation', function () {
+        const logs = `START RequestId: ${REQUEST_ID} Version: $LATEST\nhello\nEND RequestId: ${REQUEST_ID}\n`
+        const response: Lambda.InvocationResponse = { LogResult: Buffer.from(logs).toString('base64') }
+
+        assert.strictEqual(getRequestId(response), REQUEST_ID)
+    })
+
+    it('falls back to the ID of the invoke request', function () {
+        const response = ({ $response: { requestId: REQUEST_ID } } as unknown) as Lambda.InvocationResponse
+
+        assert.strictEqual(getRequestId(response), REQUEST_ID)
+    })
+})
+
+describe('InvocationLogTail', function () {
+    it('requests events since the latest one, skipping events that were already seen', async function () {
+        const requests: CloudWatchLogs.FilterLogEventsRequest[] = []
+        const responses: CloudWatchLogs.FilterLogEventsResponse[] = [
+            { events: [makeEvent('1', 200, 'first'), makeEvent('2', 300, 'second')] },
+            { events: [makeEvent('2', 300, 'second'), makeEvent('3', 300, 'third')] },
+            { events: [makeEvent('3', 300, 'third')] },
+        ]
+        const tail = makeTail(async request => {
+            requests.push(request)
+            return responses.shift()!
+        })
+
+        assert.strictEqual(await tail.poll(), true)
+        assert.strictEqual(await tail.poll(), true)
+        assert.strictEqual(await tail.poll(), false)
+
+        assert.deepStrictEqual(
+            requests.map(request => request.startTime),
+            [100, 300, 300]
+        )
+        assert.deepStrictEqual(
+            tail.getContent().split('\n').map(line => line.split('\t')[1]),
+            ['first', 'second', 'third', undefined]
+        )
+    })
+
+    it('requests all pages of events', async function () {
+        const tokens: (string | undefined)[] = []
+        const tail = makeTail(async request => {
+            tokens.push(request.nextToken)
+            return request.nextToken
+                ? { events: [makeEvent('2', 200, 'second')] }
+                : { events: [makeEvent('1', 200, 'first')], nextToken: 'page2' }
+        })
+
+        await tail.poll()
+
+        assert.deepStrictEqual(tokens, [undefined, 'page2'])
+        assert.ok(tail.getContent().includes('second'))
+    })
+
+    it('has no events before the log group exists', async function () {
+        const tail = makeTail(async () => {
+            throw Object.assign(new Error('The specified log group does not exist.'), {
+                code: 'ResourceNotFoundException',
+            })
+        })
+
+        assert.strictEqual(await tail.poll(), false)
+        assert.strictEqual(tail.getContent(), '')
+    })
+
+    it('finds the logs of the invocation by its request ID', async function () {
+        const events = [
+            makeEvent('1', 200, 'START RequestId: other Version: $LATEST\n'),
+            makeEvent('2', 201, 'multi\nline\n'),
+            makeEvent('3', 300, `START RequestId: ${REQUEST_ID} Version: $LATEST\n`),
+        ]
+        const tail = makeTail(async () => ({ events: [...events] }))
+        await tail.poll()
+
+        assert.strictEqual(tail.getRequestStartLine(), undefined, 'request ID is not known yet')
+        tail.setRequestId(REQUEST_ID)
+        assert.strictEqual(tail.getRequestStartLine(), 3)
+        assert.strictEqual(tail.isRequestComplete(), false)
+
+        events.push(makeEvent('4', 400, `REPORT RequestId: ${REQUEST_ID}\tDuration: 1.00 ms\n`))
+        await tail.poll()
+
+        assert.strictEqual(tail.isRequestComplete(), true)
+    })
+})
+
+describe('formatLogEvent', function () {
+    it('indents continuation lines past the timestamp', function () {
+        const [first, second, ...rest] = formatLogEvent(makeEvent('1', 200, 'multi\nline\n')).split('\n')
+
+        const [timestamp, message] = first.split('\t')
+        assert.strictEqual(message, 'multi')
+        assert.strictEqual(second, `${' '.repeat(timestamp.length)}\tline`)
+        assert.deepStrictEqual(rest, [''])
+    })
+})
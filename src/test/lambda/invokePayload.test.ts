@@ -0,0 +1,77 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import {
+    formatResponsePayload,
+    isBinaryPayload,
+    MAX_HEX_VIEW_BYTES,
+    toHexView,
+    toInvokePayload,
+} from '../../lambda/invokePayload'
+import { makeTemporaryToolkitFolder } from '../../shared/filesystemUtilities'
+
+describe('invokePayload', function () {
+    describe('toInvokePayload', function () {
+        it('validates JSON payloads', async function () {
+            assert.strictEqual(await toInvokePayload('json', '{"a": 1}'), '{"a": 1}')
+            await assert.rejects(toInvokePayload('json', 'a=1&b=2'), /not valid JSON/)
+        })
+
+        it('sends raw text payloads as they are', async function () {
+            assert.strictEqual(await toInvokePayload('text', 'a=1&b=2'), 'a=1&b=2')
+        })
+
+        it('decodes base64 payloads', async function () {
+            assert.deepStrictEqual(await toInvokePayload('base64', 'iVBO\nRw0K'), Buffer.from('iVBORw0K', 'base64'))
+            await assert.rejects(toInvokePayload('base64', 'not base64!'), /not valid base64/)
+        })
+
+        it('reads the bytes of file payloads', async function () {
+            const tempFolder = await makeTemporaryToolkitFolder()
+            try {
+                const filePath = path.join(tempFolder, 'payload.bin')
+                await fs.writeFile(filePath, Buffer.from([0x00, 0xff, 0x10]))
+
+                assert.deepStrictEqual(await toInvokePayload('file', filePath), Buffer.from([0x00, 0xff, 0x10]))
+                await assert.rejects(toInvokePayload('file', ''), /Choose a file/)
+            } finally {
+                await fs.remove(tempFolder)
+            }
+        })
+    })
+
+    describe('formatResponsePayload', function () {
+        it('formats text responses as text', function () {
+            const formatted = formatResponsePayload(Buffer.from('"ünïcödé"'))
+
+            assert.strictEqual(formatted.binary, false)
+            assert.strictEqual(formatted.text, '"ünïcödé"')
+        })
+
+        it('formats binary responses as base64 and hex', function () {
+            const formatted = formatResponsePayload(Buffer.from([0x89, 0x50, 0x4e, 0x47, 0x00]))
+
+            assert.strictEqual(formatted.binary, true)
+            assert.strictEqual(formatted.text, undefined)
+            assert.strictEqual(formatted.base64, 'iVBORwA=')
+            assert.strictEqual(formatted.hex, '00000000  89 50 4e 47 00')
+        })
+    })
+
+    it('tells binary payloads from UTF-8 text', function () {
+        assert.ok(isBinaryPayload(Buffer.from([0xff, 0xfe, 0x41])))
+        assert.ok(!isBinaryPayload(Buffer.from('{"text": "ünïcödé"}')))
+    })
+
+    it('cuts off the hex view of large payloads', function () {
+        const lines = toHexView(Buffer.alloc(MAX_HEX_VIEW_BYTES + 10)).split('\n')
+
+        assert.strictEqual(lines[1], `00000010  ${Array(16).fill('00').join(' ')}`)
+        assert.ok(lines[lines.length - 1].startsWith('... 10 more bytes'))
+    })
+})
@@ -0,0 +1,55 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import { InvokePayloadStore } from '../../lambda/invokePayloadStore'
+import { makeTemporaryToolkitFolder } from '../../shared/filesystemUtilities'
+import { FakeExtensionContext } from '../fakeExtensionContext'
+
+describe('InvokePayloadStore', function () {
+    const arn = 'arn:aws:lambda:us-west-2:123456789012:function:myFunction'
+    let tempFolder: string
+    let store: InvokePayloadStore
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        store = new InvokePayloadStore(new FakeExtensionContext().globalState)
+    })
+
+    afterEach(async function () {
+        await fs.remove(tempFolder)
+    })
+
+    it('returns undefined when nothing was saved', async function () {
+        assert.strictEqual(store.get('123456789012', arn), undefined)
+        assert.strictEqual(await store.load('123456789012', arn), undefined)
+    })
+
+    it('scopes payloads by account', async function () {
+        await store.set('123456789012', arn, { json: '{"a":1}' })
+        await store.set('210987654321', arn, { json: '{"b":2}' })
+
+        assert.strictEqual(await store.load('123456789012', arn), '{"a":1}')
+        assert.strictEqual(await store.load('210987654321', arn), '{"b":2}')
+        assert.strictEqual(await store.load('111111111111', arn), undefined)
+    })
+
+    it('reads the payload file when it still exists', async function () {
+        const filePath = path.join(tempFolder, 'event.json')
+        await fs.writeFile(filePath, '{"fromFile":true}')
+        await store.set('123456789012', arn, { json: '{"inline":true}', filePath })
+
+        assert.strictEqual(await store.load('123456789012', arn), '{"fromFile":true}')
+    })
+
+    it('falls back to the inline copy when the payload file was deleted', async function () {
+        const filePath = path.join(tempFolder, 'deleted.json')
+        await store.set('123456789012', arn, { json: '{"inline":true}', filePath })
+
+        assert.strictEqual(await store.load('123456789012', arn), '{"inline":true}')
+    })
+})
@@ -0,0 +1,202 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { generatePayload, getPayloadSources, parseSchemaDocument } from '../../lambda/schemaPayload'
+
+function generateFirst(document: any) {
+    return generatePayload(document, getPayloadSources(document)[0])
+}
+
+describe('schemaPayload', function () {
+    describe('parseSchemaDocument', function () {
+        it('parses JSON and YAML documents', function () {
+            assert.deepStrictEqual(parseSchemaDocument('{"type": "object"}'), { type: 'object' })
+            assert.deepStrictEqual(parseSchemaDocument('openapi: 3.0.0\npaths: {}'), { openapi: '3.0.0', paths: {} })
+        })
+
+        it('rejects documents that are not objects', function () {
+            assert.throws(() => parseSchemaDocument('{ unclosed'), /not valid JSON or YAML/)
+            assert.throws(() => parseSchemaDocument('just a string'), /not an OpenAPI or JSON Schema document/)
+        })
+    })
+
+    describe('getPayloadSources', function () {
+        it('lists the request bodies of OpenAPI operations', function () {
+            const sources = getPayloadSources({
+                openapi: '3.0.0',
+                paths: {
+                    '/pets': {
+                        get: { summary: 'List pets' },
+                        post: {
+                            summary: 'Create a pet',
+                            requestBody: { content: { 'application/json': { schema: { type: 'object' } } } },
+                        },
+                    },
+                },
+            })
+
+            assert.deepStrictEqual(
+                sources.map(source => [source.label, source.detail]),
+                [['POST /pets', 'Create a pet']]
+            )
+        })
+
+        it('lists the body parameters of Swagger 2 operations', function () {
+            const sources = getPayloadSources({
+                swagger: '2.0',
+                paths: {
+                    '/pets': {
+                        put: {
+                            operationId: 'updatePet',
+                            parameters: [{ in: 'body', name: 'pet', schema: { $ref: '#/definitions/Pet' } }],
+                        },
+                    },
+                },
+                definitions: { Pet: { type: 'object' } },
+            })
+
+            assert.deepStrictEqual(
+                sources.map(source => source.label),
+                ['PUT /pets']
+            )
+        })
+
+        it('lists the schemas of EventBridge schemas, event first', function () {
+            const sources = getPayloadSources({
+                openapi: '3.0.0',
+                paths: {},
+                components: { schemas: { Detail: { type: 'object' }, AWSEvent: { type: 'object' } } },
+            })
+
+            assert.deepStrictEqual(
+                sources.map(source => source.label),
+                ['AWSEvent', 'Detail']
+            )
+        })
+
+        it('treats other documents as a JSON Schema', function () {
+            const document = { title: 'Order', type: 'object' }
+
+            assert.deepStrictEqual(getPayloadSources(document), [{ label: 'Order', schema: document }])
+        })
+    })
+
+    describe('generatePayload', function () {
+        it('uses the examples of a request body', function () {
+            const payload = generateFirst({
+                openapi: '3.0.0',
+                paths: {
+                    '/pets': {
+                        post: {
+                            requestBody: {
+                                content: {
+                                    'text/plain': { example: 'text' },
+                                    'application/json': {
+                                        schema: { type: 'object' },
+                                        examples: { dog: { value: { name: 'Rex' } } },
+                                    },
+                                },
+                            },
+                        },
+                    },
+                },
+            })
+
+            assert.deepStrictEqual(payload, { payload: { name: 'Rex' }, warnings: [] })
+        })
+
+        it('generates placeholders from the types and examples of schemas', function () {
+            const { payload, warnings } = generateFirst({
+                type: 'object',
+                properties: {
+                    id: { type: 'string', format: 'uuid', readOnly: true },
+                    name: { type: 'string', example: 'Rex' },
+                    age: { type: 'integer', minimum: 1 },
+                    status: { type: 'string', enum: ['available', 'sold'] },
+                    vaccinated: { type: 'boolean' },
+                    born: { type: 'string', format: 'date-time' },
+                    tags: { type: 'array', items: { type: 'string' } },
+                    owner: { type: ['object', 'null'], properties: { email: { type: 'string', format: 'email' } } },
+                },
+            })
+
+            assert.deepStrictEqual(payload, {
+                name: 'Rex',
+                age: 1,
+                status: 'available',
+                vaccinated: false,
+                born: '2021-01-01T00:00:00Z',
+                tags: ['string'],
+                owner: { email: 'user@example.com' },
+            })
+            assert.deepStrictEqual(warnings, [])
+        })
+
+        it('resolves references and combines schemas', function () {
+            const { payload } = generateFirst({
+                openapi: '3.0.0',
+                paths: {
+                    '/pets': {
+                        post: { requestBody: { $ref: '#/components/requestBodies/Pet' } },
+                    },
+                },
+                components: {
+                    requestBodies: {
+                        Pet: { content: { 'application/json': { schema: { $ref: '#/components/schemas/Dog' } } } },
+                    },
+                    schemas: {
+                        Pet: { type: 'object', properties: { name: { type: 'string' } } },
+                        Dog: {
+                            allOf: [
+                                { $ref: '#/components/schemas/Pet' },
+                                {
+                                    type: 'object',
+                                    properties: { size: { oneOf: [{ type: 'number' }, { type: 'string' }] } },
+                                },
+                            ],
+                        },
+                    },
+                },
+            })
+
+            assert.deepStrictEqual(payload, { name: 'string', size: 0 })
+        })
+
+        it('stops expanding recursive schemas', function () {
+            const { payload } = generateFirst({
+                definitions: {
+                    Node: {
+                        type: 'object',
+                        properties: {
+                            value: { type: 'number' },
+                            children: { type: 'array', items: { $ref: '#/definitions/Node' } },
+                        },
+                    },
+                },
+                $ref: '#/definitions/Node',
+            })
+
+            assert.deepStrictEqual(payload, { value: 0, children: [] })
+        })
+
+        it('warns about references that cannot be resolved', function () {
+            const { payload, warnings } = generateFirst({
+                type: 'object',
+                properties: {
+                    missing: { $ref: '#/definitions/Missing' },
+                    external: { $ref: 'other.json#/definitions/Other' },
+                    name: { type: 'string' },
+                },
+            })
+
+            assert.deepStrictEqual(payload, { missing: {}, external: {}, name: 'string' })
+            assert.deepStrictEqual(warnings, [
+                'Could not resolve reference: #/definitions/Missing',
+                'References to other documents are not supported: other.json#/definitions/Other',
+            ])
+        })
+    })
+})
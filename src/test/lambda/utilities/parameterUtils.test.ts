@@ -95,6 +95,37 @@ describe('parameterUtils', async function () {
             assert.ok(parameter)
             assert.strictEqual(parameter!.required, false)
         })
+
+        it('reads the default value, allowed values, description, and NoEcho', async function () {
+            const context: GetParametersContext = {
+                loadTemplate: async () => ({
+                    Parameters: {
+                        Stage: {
+                            Type: 'String',
+                            Default: 'beta',
+                            AllowedValues: ['beta', 'prod'],
+                            Description: 'Deployment stage',
+                        },
+                        Password: {
+                            Type: 'String',
+                            NoEcho: true,
+                        },
+                    },
+                }),
+            }
+
+            const actual = await getParameters(vscode.Uri.file(''), context)
+
+            assert.deepStrictEqual(actual.get('Stage'), {
+                required: false,
+                defaultValue: 'beta',
+                allowedValues: ['beta', 'prod'],
+                description: 'Deployment stage',
+                noEcho: false,
+            })
+            assert.strictEqual(actual.get('Password')!.noEcho, true)
+            assert.strictEqual(actual.get('Password')!.defaultValue, undefined)
+        })
     })
 
     describe('getParameterNames', async function () {
@@ -4,7 +4,7 @@
  */
 
 import * as assert from 'assert'
-import { getLambdaDetails } from '../../lambda/utils'
+import { getLambdaDetails, getSampleLambdaPayload } from '../../lambda/utils'
 
 describe('lambda utils', async function () {
     describe('getLambdaDetails', function () {
@@ -47,4 +47,21 @@ describe('lambda utils', async function () {
             assert.throws(() => getLambdaDetails({ Runtime: 'COBOL-60', Handler: 'asdf.asdf' }))
         })
     })
+
+    describe('getSampleLambdaPayload', function () {
+        it('returns bundled EventBridge samples without fetching them', async function () {
+            const schedule = JSON.parse(await getSampleLambdaPayload('EventBridgeRuleSchedule.json'))
+            assert.strictEqual(schedule.source, 'aws.events')
+            assert.strictEqual(schedule['detail-type'], 'Scheduled Event')
+            assert.ok(schedule.resources[0].startsWith('arn:aws:events:'))
+
+            // Scheduler sends the input of the schedule, rather than an event
+            const scheduler = JSON.parse(await getSampleLambdaPayload('EventBridgeScheduler.json'))
+            assert.deepStrictEqual(scheduler, { key1: 'value1' })
+
+            const rule = JSON.parse(await getSampleLambdaPayload('EventBridgeRuleCustomEvent.json'))
+            assert.strictEqual(rule.source, 'com.mycompany.myapp')
+            assert.deepStrictEqual(rule.detail, { key1: 'value1' })
+        })
+    })
 })
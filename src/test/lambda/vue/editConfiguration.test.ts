@@ -0,0 +1,153 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { Lambda } from 'aws-sdk'
+import {
+    ConfigurationForm,
+    isSecretName,
+    toConfigurationForm,
+    toUpdateRequest,
+    validateConfigurationForm,
+    waitForConfigurationUpdate,
+} from '../../../lambda/vue/editConfiguration'
+import { MockLambdaClient } from '../../shared/clients/mockClients'
+
+describe('editConfiguration', function () {
+    const form: ConfigurationForm = {
+        memorySize: 256,
+        timeout: 30,
+        ephemeralStorage: 512,
+        handler: 'index.handler',
+        variables: [
+            { name: 'API_KEY', value: 'abc', masked: true },
+            { name: 'STAGE', value: 'prod', masked: false },
+        ],
+    }
+
+    describe('isSecretName', function () {
+        it('tells names of secrets from other names', function () {
+            for (const name of ['DB_PASSWORD', 'API_KEY', 'apiKey', 'GITHUB_TOKEN', 'CLIENT_SECRET']) {
+                assert.ok(isSecretName(name), name)
+            }
+            for (const name of ['STAGE', 'TABLE_NAME', 'LOG_LEVEL']) {
+                assert.ok(!isSecretName(name), name)
+            }
+        })
+    })
+
+    describe('toConfigurationForm', function () {
+        it('masks variables that look like secrets', function () {
+            assert.deepStrictEqual(
+                toConfigurationForm({
+                    MemorySize: 256,
+                    Timeout: 30,
+                    Handler: 'index.handler',
+                    Environment: { Variables: { STAGE: 'prod', API_KEY: 'abc' } },
+                }),
+                form
+            )
+        })
+
+        it('has no handler for container image functions', function () {
+            assert.strictEqual(toConfigurationForm({ PackageType: 'Image' }).handler, undefined)
+        })
+    })
+
+    describe('validateConfigurationForm', function () {
+        it('accepts a valid configuration', function () {
+            assert.deepStrictEqual(validateConfigurationForm(form), [])
+        })
+
+        it('finds values out of range and invalid variables', function () {
+            const errors = validateConfigurationForm({
+                memorySize: 64,
+                timeout: 1.5,
+                ephemeralStorage: 512,
+                handler: ' ',
+                variables: [
+                    { name: '1ST', value: '', masked: false },
+                    { name: 'AWS_REGION', value: '', masked: false },
+                    { name: 'STAGE', value: 'a', masked: false },
+                    { name: 'STAGE', value: 'b', masked: false },
+                ],
+            })
+
+            assert.deepStrictEqual(errors, [
+                'Memory (MB) must be a whole number from 128 to 10240',
+                'Timeout (seconds) must be a whole number from 1 to 900',
+                'Handler cannot be empty',
+                'Environment variable "1ST" must start with a letter and have only letters, numbers, and underscores',
+                'Environment variable AWS_REGION is reserved by Lambda',
+                'Environment variable STAGE is set twice',
+            ])
+        })
+    })
+
+    describe('toUpdateRequest', function () {
+        it('updates only what was changed', function () {
+            assert.deepStrictEqual(toUpdateRequest('my-function', form, { ...form, timeout: 60 }), {
+                FunctionName: 'my-function',
+                Timeout: 60,
+            })
+        })
+
+        it('updates all variables together', function () {
+            const edited = { ...form, variables: [form.variables[1], { name: 'NEW', value: 'x', masked: false }] }
+
+            assert.deepStrictEqual(toUpdateRequest('my-function', form, edited), {
+                FunctionName: 'my-function',
+                Environment: { Variables: { STAGE: 'prod', NEW: 'x' } },
+            })
+        })
+
+        it('does not update when nothing was changed', function () {
+            const edited = { ...form, variables: [...form.variables] }
+
+            assert.strictEqual(toUpdateRequest('my-function', form, edited), undefined)
+        })
+    })
+
+    describe('waitForConfigurationUpdate', function () {
+        function makeClient(statuses: Lambda.FunctionConfiguration[]): MockLambdaClient {
+            return new MockLambdaClient({ getFunctionConfiguration: async () => statuses.shift() ?? {} })
+        }
+
+        it('polls until the update finishes', async function () {
+            const client = makeClient([
+                { LastUpdateStatus: 'InProgress' },
+                { LastUpdateStatus: 'InProgress' },
+                { LastUpdateStatus: 'Successful', Timeout: 60 },
+            ])
+
+            const configuration = await waitForConfigurationUpdate(client, 'my-function', { pollIntervalMillis: 0 })
+
+            assert.strictEqual(configuration.Timeout, 60)
+        })
+
+        it('throws the reason the update failed', async function () {
+            const client = makeClient([
+                { LastUpdateStatus: 'InProgress' },
+                { LastUpdateStatus: 'Failed', LastUpdateStatusReason: 'Insufficient permissions' },
+            ])
+
+            await assert.rejects(
+                waitForConfigurationUpdate(client, 'my-function', { pollIntervalMillis: 0 }),
+                /Insufficient permissions/
+            )
+        })
+
+        it('throws when the update does not finish in time', async function () {
+            const client = new MockLambdaClient({
+                getFunctionConfiguration: async () => ({ LastUpdateStatus: 'InProgress' }),
+            })
+
+            await assert.rejects(
+                waitForConfigurationUpdate(client, 'my-function', { pollIntervalMillis: 0, timeoutMillis: 0 }),
+                /did not finish/
+            )
+        })
+    })
+})
@@ -0,0 +1,105 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudWatch } from 'aws-sdk'
+import { getFunctionMetrics } from '../../../lambda/vue/functionMetrics'
+import { MockCloudWatchClient } from '../../shared/clients/mockClients'
+
+describe('getFunctionMetrics', function () {
+    const now = new Date(Date.UTC(2021, 0, 1, 12, 0, 30))
+
+    let requests: CloudWatch.GetMetricDataInput[]
+    let results: CloudWatch.MetricDataResult[]
+    const client = new MockCloudWatchClient({
+        getMetricData: async request => {
+            requests.push(request)
+            return results
+        },
+    })
+
+    beforeEach(function () {
+        requests = []
+        results = []
+    })
+
+    it('queries the metrics of the function over whole periods of the time range', async function () {
+        await getFunctionMetrics(client, 'my-function', { timeRange: '1h', now })
+
+        const request = requests[0]
+        assert.strictEqual(request.EndTime.valueOf(), Date.UTC(2021, 0, 1, 12, 0))
+        assert.strictEqual(request.StartTime.valueOf(), Date.UTC(2021, 0, 1, 11, 0))
+        assert.deepStrictEqual(
+            request.MetricDataQueries.map(query => [
+                query.Id,
+                query.MetricStat?.Metric.MetricName,
+                query.MetricStat?.Stat,
+            ]),
+            [
+                ['invocations', 'Invocations', 'Sum'],
+                ['durationP50', 'Duration', 'p50'],
+                ['durationP99', 'Duration', 'p99'],
+                ['errors', 'Errors', 'Sum'],
+                ['throttles', 'Throttles', 'Sum'],
+            ]
+        )
+        assert.deepStrictEqual(request.MetricDataQueries[0].MetricStat?.Metric.Dimensions, [
+            { Name: 'FunctionName', Value: 'my-function' },
+        ])
+        assert.strictEqual(request.MetricDataQueries[0].MetricStat?.Period, 60)
+    })
+
+    it('leaves out the period still in progress', async function () {
+        await getFunctionMetrics(client, 'my-function', {
+            timeRange: '3h',
+            now: new Date(Date.UTC(2021, 0, 1, 12, 4, 59)),
+        })
+
+        assert.strictEqual(requests[0].EndTime.valueOf(), Date.UTC(2021, 0, 1, 12, 0))
+        assert.strictEqual(requests[0].StartTime.valueOf(), Date.UTC(2021, 0, 1, 9, 0))
+    })
+
+    it('narrows the metrics to an alias or version', async function () {
+        await getFunctionMetrics(client, 'my-function', { timeRange: '1d', qualifier: 'live', now })
+
+        assert.deepStrictEqual(requests[0].MetricDataQueries[0].MetricStat?.Metric.Dimensions, [
+            { Name: 'FunctionName', Value: 'my-function' },
+            { Name: 'Resource', Value: 'my-function:live' },
+        ])
+        assert.strictEqual(requests[0].MetricDataQueries[0].MetricStat?.Period, 900)
+    })
+
+    it('returns the data points of each metric, oldest first', async function () {
+        results = [
+            {
+                Id: 'invocations',
+                Timestamps: [new Date(Date.UTC(2021, 0, 1, 11, 30)), new Date(Date.UTC(2021, 0, 1, 11, 20))],
+                Values: [3, 5],
+            },
+            { Id: 'durationP99', Timestamps: [new Date(Date.UTC(2021, 0, 1, 11, 20))], Values: [1200] },
+        ]
+
+        const metrics = await getFunctionMetrics(client, 'my-function', { timeRange: '1h', now })
+
+        assert.strictEqual(metrics.empty, false)
+        assert.deepStrictEqual(metrics.series[0], {
+            id: 'invocations',
+            label: 'Invocations',
+            unit: 'count',
+            timestamps: [Date.UTC(2021, 0, 1, 11, 20), Date.UTC(2021, 0, 1, 11, 30)],
+            values: [5, 3],
+        })
+        assert.deepStrictEqual(metrics.series[2].values, [1200])
+        assert.deepStrictEqual(metrics.series[3].values, [])
+    })
+
+    it('is empty if the function was not invoked', async function () {
+        results = [{ Id: 'invocations', Timestamps: [now], Values: [0] }]
+
+        const metrics = await getFunctionMetrics(client, 'my-function', { timeRange: '1h', now })
+
+        assert.strictEqual(metrics.empty, true)
+    })
+})
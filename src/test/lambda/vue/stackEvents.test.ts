@@ -0,0 +1,172 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { CloudFormation } from 'aws-sdk'
+import { findResourceDefinitionOffset, isTerminalStackStatus, StackEventsReader } from '../../../lambda/vue/stackEvents'
+import { MockCloudFormationClient } from '../../shared/clients/mockClients'
+
+function stackEvent(
+    id: string,
+    minute: number,
+    logicalId: string,
+    status: string,
+    reason?: string
+): CloudFormation.StackEvent {
+    return {
+        StackId: 'stackId',
+        StackName: 'my-stack',
+        EventId: id,
+        Timestamp: new Date(Date.UTC(2021, 0, 1, 0, minute)),
+        LogicalResourceId: logicalId,
+        ResourceType: logicalId === 'my-stack' ? 'AWS::CloudFormation::Stack' : 'AWS::Lambda::Function',
+        ResourceStatus: status,
+        ResourceStatusReason: reason,
+    }
+}
+
+describe('StackEventsReader', function () {
+    /** Events of the stack, newest first. */
+    let events: CloudFormation.StackEvent[]
+    let stackStatus: string | undefined
+    let pageSize: number
+
+    const client: MockCloudFormationClient = {
+        ...new MockCloudFormationClient(),
+        describeStackEvents: async (name: string, nextToken?: string) => {
+            const start = nextToken ? parseInt(nextToken, 10) : 0
+            const end = start + pageSize
+
+            return {
+                StackEvents: events.slice(start, end),
+                NextToken: end < events.length ? String(end) : undefined,
+            }
+        },
+        describeStack: async () =>
+            stackStatus ? { StackName: 'my-stack', StackStatus: stackStatus, CreationTime: new Date() } : undefined,
+    }
+
+    beforeEach(function () {
+        events = [
+            stackEvent('3', 3, 'MyFunction', 'CREATE_FAILED', 'Resource handler returned message: "boom"'),
+            stackEvent('2', 2, 'MyFunction', 'CREATE_IN_PROGRESS'),
+            stackEvent('1', 1, 'my-stack', 'CREATE_IN_PROGRESS', 'User Initiated'),
+            stackEvent('0', 0, 'my-stack', 'DELETE_COMPLETE'),
+        ]
+        stackStatus = 'CREATE_IN_PROGRESS'
+        pageSize = 2
+    })
+
+    it('reads the events since the given time, across pages', async function () {
+        const reader = new StackEventsReader(client, 'my-stack', new Date(Date.UTC(2021, 0, 1, 0, 1)))
+
+        const data = await reader.poll()
+
+        assert.deepStrictEqual(
+            data.events.map(event => event.id),
+            ['3', '2', '1']
+        )
+        assert.strictEqual(data.stackStatus, 'CREATE_IN_PROGRESS')
+        assert.deepStrictEqual(data.events[0], {
+            id: '3',
+            timestamp: '2021-01-01T00:03:00.000Z',
+            logicalId: 'MyFunction',
+            resourceType: 'AWS::Lambda::Function',
+            status: 'CREATE_FAILED',
+            reason: 'Resource handler returned message: "boom"',
+            failed: true,
+        })
+        assert.strictEqual(data.events[1].failed, false)
+    })
+
+    it('reads only the most recent page if no time is given', async function () {
+        const reader = new StackEventsReader(client, 'my-stack')
+
+        assert.deepStrictEqual(
+            (await reader.poll()).events.map(event => event.id),
+            ['3', '2']
+        )
+    })
+
+    it('adds new events to the front on later polls', async function () {
+        const reader = new StackEventsReader(client, 'my-stack', new Date(Date.UTC(2021, 0, 1, 0, 1)))
+        await reader.poll()
+
+        events.unshift(stackEvent('4', 4, 'my-stack', 'ROLLBACK_IN_PROGRESS'))
+        stackStatus = 'ROLLBACK_IN_PROGRESS'
+        const data = await reader.poll()
+
+        assert.deepStrictEqual(
+            data.events.map(event => event.id),
+            ['4', '3', '2', '1']
+        )
+        assert.strictEqual(data.stackStatus, 'ROLLBACK_IN_PROGRESS')
+    })
+
+    it('has no events or status if the stack does not exist yet', async function () {
+        const error = new Error('Stack with id my-stack does not exist')
+        const reader = new StackEventsReader(
+            {
+                ...client,
+                describeStackEvents: async () => {
+                    throw Object.assign(error, { code: 'ValidationError' })
+                },
+            },
+            'my-stack',
+            new Date()
+        )
+
+        assert.deepStrictEqual(await reader.poll(), { events: [], stackStatus: undefined })
+    })
+
+    it('throws other errors', async function () {
+        const reader = new StackEventsReader(
+            {
+                ...client,
+                describeStackEvents: async () => {
+                    throw new Error('Access denied')
+                },
+            },
+            'my-stack'
+        )
+
+        await assert.rejects(reader.poll(), /Access denied/)
+    })
+})
+
+describe('isTerminalStackStatus', function () {
+    it('treats statuses that are not in progress as terminal', function () {
+        assert.strictEqual(isTerminalStackStatus('CREATE_COMPLETE'), true)
+        assert.strictEqual(isTerminalStackStatus('UPDATE_ROLLBACK_FAILED'), true)
+        assert.strictEqual(isTerminalStackStatus('UPDATE_COMPLETE_CLEANUP_IN_PROGRESS'), false)
+        assert.strictEqual(isTerminalStackStatus('REVIEW_IN_PROGRESS'), false)
+    })
+})
+
+describe('findResourceDefinitionOffset', function () {
+    it('finds a resource in a YAML template', function () {
+        const text = [
+            'Outputs:',
+            '  FunctionArn:',
+            '    Value: !GetAtt MyFunction.Arn',
+            'Resources:',
+            '  MyFunction: # the function',
+            '    Type: AWS::Serverless::Function',
+        ].join('\n')
+
+        assert.strictEqual(findResourceDefinitionOffset(text, 'MyFunction', true), text.indexOf('MyFunction: #'))
+    })
+
+    it('finds a resource in a JSON template', function () {
+        const text = '{"Outputs": {"Arn": {"Ref": "MyFunction"}}, "Resources": {"MyFunction": {"Type": "x"}}}'
+
+        assert.strictEqual(findResourceDefinitionOffset(text, 'MyFunction', false), text.indexOf('MyFunction": {'))
+    })
+
+    it('returns undefined if the resource is not defined', function () {
+        assert.strictEqual(findResourceDefinitionOffset('Resources:\n  Other:\n', 'MyFunction', true), undefined)
+        assert.strictEqual(findResourceDefinitionOffset('MyFunction:\n', 'MyFunction', true), undefined)
+    })
+})
@@ -11,14 +11,16 @@ import * as paramUtils from '../../../lambda/config/parameterUtils'
 import * as input from '../../../shared/ui/input'
 import * as picker from '../../../shared/ui/picker'
 import {
-    ParameterPromptResult,
+    parseTags,
     SamDeployWizard,
     SamDeployWizardContext,
     DefaultSamDeployWizardContext,
+    writeSavedStack,
 } from '../../../lambda/wizards/samDeployWizard'
 import { EcrRepository } from '../../../shared/clients/ecrClient'
 import { FakeExtensionContext } from '../../fakeExtensionContext'
 import { ExtContext } from '../../../shared/extensions'
+import { TestSettingsConfiguration } from '../../utilities/testSettingsConfiguration'
 
 interface QuickPickUriResponseItem extends vscode.QuickPickItem {
     uri: vscode.Uri
@@ -60,7 +62,8 @@ class MockSamDeployWizardContext implements SamDeployWizardContext {
         private readonly promptForNewS3BucketResponses: (string | undefined)[] = [],
         private readonly promptForEcrRepoResponses: (EcrRepository | undefined)[] = [],
         private readonly promptForStackNameResponses: (string | undefined)[] = [],
-        private readonly hasImages: boolean = false
+        private readonly hasImages: boolean = false,
+        private readonly promptForTagsResponses: (string | undefined)[] = ['']
     ) {
         this.workspaceFoldersResponses = workspaceFoldersResponses.reverse()
         this.promptForSamTemplateResponses = promptForSamTemplateResponses.reverse()
@@ -69,6 +72,7 @@ class MockSamDeployWizardContext implements SamDeployWizardContext {
         this.promptForNewS3BucketResponses = promptForNewS3BucketResponses.reverse()
         this.promptForEcrRepoResponses = promptForEcrRepoResponses.reverse()
         this.promptForStackNameResponses = promptForStackNameResponses.reverse()
+        this.promptForTagsResponses = promptForTagsResponses.reverse()
     }
 
     additionalSteps: number = 0
@@ -77,10 +81,9 @@ class MockSamDeployWizardContext implements SamDeployWizardContext {
 
     public readonly getParameters: typeof paramUtils.getParameters = async () => new Map()
 
-    public readonly promptUserForParametersIfApplicable: (options: {
-        templateUri: vscode.Uri
-        missingParameters?: Set<string>
-    }) => Promise<ParameterPromptResult> = async () => ParameterPromptResult.Continue
+    public async promptUserForParameter(): Promise<string | undefined> {
+        throw new Error('promptUserForParameter was called more times than expected')
+    }
 
     public async determineIfTemplateHasImages(templatePath: vscode.Uri): Promise<boolean> {
         return this.hasImages
@@ -177,6 +180,14 @@ class MockSamDeployWizardContext implements SamDeployWizardContext {
 
         return response
     }
+
+    public async promptUserForTags(): Promise<string | undefined> {
+        if (this.promptForTagsResponses.length <= 0) {
+            throw new Error('promptUserForTags was called more times than expected')
+        }
+
+        return this.promptForTagsResponses.pop()
+    }
 }
 
 function normalizePath(...paths: string[]): string {
@@ -259,16 +270,17 @@ describe('SamDeployWizard', async function () {
         async function makeFakeContext({
             getParameters,
             getOverriddenParameters,
-            promptUserForParametersIfApplicable,
+            promptUserForParameter = async () => {
+                throw new Error('Should skip prompting for parameters')
+            },
+            promptUserForTags = async () => '',
             templatePath = path.join('my', 'template'),
             region = 'us-east-1',
             s3Bucket = 'mys3bucket',
             stackName = 'mystackname',
             hasImages = false,
-        }: Pick<
-            SamDeployWizardContext,
-            'getParameters' | 'getOverriddenParameters' | 'promptUserForParametersIfApplicable'
-        > & {
+        }: Pick<SamDeployWizardContext, 'getParameters' | 'getOverriddenParameters'> &
+            Partial<Pick<SamDeployWizardContext, 'promptUserForParameter' | 'promptUserForTags'>> & {
             templatePath?: string
             region?: string
             s3Bucket?: string
@@ -283,7 +295,8 @@ describe('SamDeployWizard', async function () {
 
                 getParameters,
                 getOverriddenParameters,
-                promptUserForParametersIfApplicable,
+                promptUserForParameter,
+                promptUserForTags,
                 promptUserForSamTemplate: async () => vscode.Uri.file(templatePath),
                 promptUserForRegion: async () => region,
                 promptUserForS3Bucket: async () => s3Bucket,
@@ -301,9 +314,6 @@ describe('SamDeployWizard', async function () {
                     getOverriddenParameters: async () => {
                         throw new Error('Should skip loading overrides')
                     },
-                    promptUserForParametersIfApplicable: async () => {
-                        throw new Error('Should skip configuring overrides')
-                    },
                 })
 
                 const wizard = new SamDeployWizard(context)
@@ -320,9 +330,6 @@ describe('SamDeployWizard', async function () {
                     getParameters: async () =>
                         new Map<string, { required: boolean }>([['myParam', { required: false }]]),
                     getOverriddenParameters: async () => new Map<string, string>(),
-                    promptUserForParametersIfApplicable: async () => {
-                        throw new Error('Should skip configuring overrides')
-                    },
                 })
 
                 const wizard = new SamDeployWizard(context)
@@ -332,115 +339,108 @@ describe('SamDeployWizard', async function () {
                 assert.strictEqual(result!.parameterOverrides.size, 0)
             })
 
-            it('skips configuring overrides and continues wizard if parameterOverrides is undefined and user declines prompt', async function () {
+            it('prompts for each parameter, pre-filled with its default, if parameterOverrides is undefined', async function () {
+                const promptedOptions: { name: string; initialValue?: string }[] = []
+
                 const context = await makeFakeContext({
                     getParameters: async () =>
-                        new Map<string, { required: boolean }>([['myParam', { required: false }]]),
+                        new Map([
+                            ['myParam', { required: false, defaultValue: 'myDefault' }],
+                            ['otherParam', { required: false, defaultValue: 'otherDefault' }],
+                        ]),
                     getOverriddenParameters: async () => undefined,
-                    promptUserForParametersIfApplicable: async () => ParameterPromptResult.Continue,
+                    async promptUserForParameter(step, { name, initialValue }): Promise<string | undefined> {
+                        promptedOptions.push({ name, initialValue })
+
+                        return name === 'myParam' ? 'myValue' : initialValue
+                    },
                 })
 
                 const wizard = new SamDeployWizard(context)
                 const result = await wizard.run()
 
                 assert.ok(result)
-                assert.strictEqual(result!.parameterOverrides.size, 0)
+                assert.deepStrictEqual(promptedOptions, [
+                    { name: 'myParam', initialValue: 'myDefault' },
+                    { name: 'otherParam', initialValue: 'otherDefault' },
+                ])
+                assert.deepStrictEqual(
+                    [...result!.parameterOverrides],
+                    [
+                        ['myParam', 'myValue'],
+                        ['otherParam', 'otherDefault'],
+                    ]
+                )
             })
 
-            it('configures overrides and cancels wizard if parameterOverrides is undefined and user accepts prompt', async function () {
-                const configureParameterOverridesArgs: {
-                    templateUri: vscode.Uri
-                    missingParameters?: Set<string> | undefined
-                }[] = []
-
+            it('leaves a parameter to the template default if its value is cleared', async function () {
                 const context = await makeFakeContext({
-                    getParameters: async () =>
-                        new Map<string, { required: boolean }>([['myParam', { required: false }]]),
+                    getParameters: async () => new Map([['myParam', { required: false, defaultValue: 'myDefault' }]]),
                     getOverriddenParameters: async () => undefined,
-                    async promptUserForParametersIfApplicable(options): Promise<ParameterPromptResult> {
-                        configureParameterOverridesArgs.push(options)
-
-                        return ParameterPromptResult.Cancel
-                    },
+                    promptUserForParameter: async () => '',
                 })
 
                 const wizard = new SamDeployWizard(context)
                 const result = await wizard.run()
 
-                assert.strictEqual(result, undefined)
-                assert.strictEqual(configureParameterOverridesArgs.length, 1)
-                assert.strictEqual(configureParameterOverridesArgs[0].missingParameters, undefined)
+                assert.ok(result)
+                assert.strictEqual(result!.parameterOverrides.size, 0)
             })
         })
 
         describe('SAM template has required parameters', async function () {
-            it('configures overrides and cancels wizard if overrides are not defined', async function () {
-                const configureParameterOverridesArgs: {
-                    templateUri: vscode.Uri
-                    missingParameters?: Set<string> | undefined
-                }[] = []
-
+            it('prompts for parameters if overrides are not defined', async function () {
                 const context = await makeFakeContext({
                     getParameters: async () =>
                         new Map<string, { required: boolean }>([['myParam', { required: true }]]),
                     getOverriddenParameters: async () => undefined,
-                    async promptUserForParametersIfApplicable(options): Promise<ParameterPromptResult> {
-                        configureParameterOverridesArgs.push(options)
-
-                        return ParameterPromptResult.Cancel
-                    },
+                    promptUserForParameter: async () => 'myValue',
                 })
 
                 const wizard = new SamDeployWizard(context)
                 const result = await wizard.run()
 
-                assert.strictEqual(result, undefined)
-                assert.strictEqual(configureParameterOverridesArgs.length, 1)
-                assert.ok(configureParameterOverridesArgs[0].missingParameters)
-                assert.strictEqual(configureParameterOverridesArgs[0].missingParameters!.has('myParam'), true)
+                assert.ok(result)
+                assert.strictEqual(result!.parameterOverrides.get('myParam'), 'myValue')
             })
 
-            it('configures overrides and cancels wizard if there are missing overrides', async function () {
-                const configureParameterOverridesArgs: {
-                    templateUri: vscode.Uri
-                    missingParameters?: Set<string> | undefined
-                }[] = []
+            it('prompts for parameters, pre-filled with existing overrides, if there are missing overrides', async function () {
+                const initialValues = new Map<string, string | undefined>()
 
                 const context = await makeFakeContext({
                     getParameters: async () =>
-                        new Map<string, { required: boolean }>([['myParam', { required: true }]]),
-                    getOverriddenParameters: async () => new Map<string, string>(),
-                    async promptUserForParametersIfApplicable(options): Promise<ParameterPromptResult> {
-                        configureParameterOverridesArgs.push(options)
-
-                        return ParameterPromptResult.Cancel
+                        new Map<string, { required: boolean }>([
+                            ['myParam', { required: true }],
+                            ['otherParam', { required: false }],
+                        ]),
+                    getOverriddenParameters: async () => new Map<string, string>([['otherParam', 'otherValue']]),
+                    async promptUserForParameter(step, { name, initialValue }): Promise<string | undefined> {
+                        initialValues.set(name, initialValue)
+
+                        return initialValue ?? 'myValue'
                     },
                 })
 
                 const wizard = new SamDeployWizard(context)
                 const result = await wizard.run()
 
-                assert.strictEqual(result, undefined)
-                assert.strictEqual(configureParameterOverridesArgs.length, 1)
-                assert.ok(configureParameterOverridesArgs[0].missingParameters)
-                assert.strictEqual(configureParameterOverridesArgs[0].missingParameters!.has('myParam'), true)
+                assert.ok(result)
+                assert.deepStrictEqual(
+                    [...initialValues],
+                    [
+                        ['myParam', undefined],
+                        ['otherParam', 'otherValue'],
+                    ]
+                )
+                assert.strictEqual(result!.parameterOverrides.get('myParam'), 'myValue')
+                assert.strictEqual(result!.parameterOverrides.get('otherParam'), 'otherValue')
             })
 
             it('stores existing overrides and continues without configuring overrides if there are no missing overrides', async function () {
-                const configureParameterOverridesArgs: {
-                    templateUri: vscode.Uri
-                    missingParameters?: Set<string> | undefined
-                }[] = []
-
                 const context = await makeFakeContext({
                     getParameters: async () =>
                         new Map<string, { required: boolean }>([['myParam', { required: true }]]),
                     getOverriddenParameters: async () => new Map<string, string>([['myParam', 'myValue']]),
-                    async promptUserForParametersIfApplicable(options): Promise<ParameterPromptResult> {
-                        configureParameterOverridesArgs.push(options)
-
-                        return ParameterPromptResult.Cancel
-                    },
                 })
 
                 const wizard = new SamDeployWizard(context)
@@ -450,11 +450,131 @@ describe('SamDeployWizard', async function () {
                 assert.strictEqual(result!.parameterOverrides.size, 1)
                 assert.strictEqual(result!.parameterOverrides.has('myParam'), true)
                 assert.strictEqual(result!.parameterOverrides.get('myParam'), 'myValue')
-                assert.strictEqual(configureParameterOverridesArgs.length, 0)
+            })
+
+            it('goes back to the stack name when cancelled', async function () {
+                const parameterResponses = [undefined, 'myValue']
+                let stackNameCalls = 0
+
+                const context = await makeFakeContext({
+                    getParameters: async () =>
+                        new Map<string, { required: boolean }>([['myParam', { required: true }]]),
+                    getOverriddenParameters: async () => undefined,
+                    promptUserForParameter: async () => parameterResponses.shift(),
+                })
+                context.promptUserForStackName = async () => {
+                    stackNameCalls++
+                    return 'mystackname'
+                }
+
+                const wizard = new SamDeployWizard(context)
+                const result = await wizard.run()
+
+                assert.ok(result)
+                assert.strictEqual(stackNameCalls, 2)
+                assert.strictEqual(result!.parameterOverrides.get('myParam'), 'myValue')
+            })
+        })
+
+        describe('values saved from the last deploy to the stack', async function () {
+            it('pre-fills saved values, except for NoEcho parameters', async function () {
+                const initialValues = new Map<string, string | undefined>()
+
+                const context = await makeFakeContext({
+                    getParameters: async () =>
+                        new Map([
+                            ['myParam', { required: true }],
+                            ['mySecret', { required: true, noEcho: true }],
+                        ]),
+                    getOverriddenParameters: async () => undefined,
+                    async promptUserForParameter(step, { name, initialValue }): Promise<string | undefined> {
+                        initialValues.set(name, initialValue)
+
+                        return 'newValue'
+                    },
+                    promptUserForTags: async (step, initialValue) => initialValue,
+                })
+                context.extContext.settings = new TestSettingsConfiguration()
+                writeSavedStack(context.extContext.settings, 'us-east-1', 'mystackname', {
+                    parameterOverrides: new Map([
+                        ['myParam', 'savedValue'],
+                        ['mySecret', 'savedSecret'],
+                    ]),
+                    tags: new Map([['team', 'serverless']]),
+                })
+
+                const wizard = new SamDeployWizard(context)
+                const result = await wizard.run()
+
+                assert.ok(result)
+                assert.deepStrictEqual(
+                    [...initialValues],
+                    [
+                        ['myParam', 'savedValue'],
+                        ['mySecret', undefined],
+                    ]
+                )
+                assert.deepStrictEqual([...result!.noEchoParameters!], ['mySecret'])
+                assert.deepStrictEqual([...result!.tags!], [['team', 'serverless']])
             })
         })
     })
 
+    describe('TAGS', async function () {
+        it('uses user response as tags', async function () {
+            const workspaceFolderPath = normalizePath('my', 'workspace', 'folder')
+            const templatePath = normalizePath(workspaceFolderPath, 'template.yaml')
+            const wizard = new SamDeployWizard(
+                new MockSamDeployWizardContext(
+                    extContext,
+                    [[vscode.Uri.file(workspaceFolderPath)]],
+                    [createQuickPickUriResponseItem(vscode.Uri.file(templatePath))],
+                    [createQuickPickRegionResponseItem('asdf')],
+                    ['mys3bucketname'],
+                    [],
+                    [],
+                    ['myStackName'],
+                    false,
+                    ['team=serverless stage=beta']
+                )
+            )
+            const result = await wizard.run()
+
+            assert.ok(result)
+            assert.deepStrictEqual(
+                [...result!.tags!],
+                [
+                    ['team', 'serverless'],
+                    ['stage', 'beta'],
+                ]
+            )
+        })
+
+        it('goes back when cancelled', async function () {
+            const workspaceFolderPath = normalizePath('my', 'workspace', 'folder')
+            const templatePath = normalizePath(workspaceFolderPath, 'template.yaml')
+            const wizard = new SamDeployWizard(
+                new MockSamDeployWizardContext(
+                    extContext,
+                    [[vscode.Uri.file(workspaceFolderPath)]],
+                    [createQuickPickUriResponseItem(vscode.Uri.file(templatePath))],
+                    [createQuickPickRegionResponseItem('asdf')],
+                    ['mys3bucketname'],
+                    [],
+                    [],
+                    ['myStackName1', 'myStackName2'],
+                    false,
+                    [undefined, '']
+                )
+            )
+            const result = await wizard.run()
+
+            assert.ok(result)
+            assert.strictEqual(result!.stackName, 'myStackName2')
+            assert.strictEqual(result!.tags!.size, 0)
+        })
+    })
+
     describe('REGION', async function () {
         it('uses user response for region', async function () {
             const workspaceFolderPath = normalizePath('my', 'workspace', 'folder', '1')
@@ -802,3 +922,22 @@ describe('DefaultSamDeployWizardContext', async function () {
         })
     })
 })
+
+describe('parseTags', function () {
+    it('parses space-separated key=value pairs', function () {
+        assert.deepStrictEqual(
+            [...parseTags(' team=serverless  stage= url=a=b ')!],
+            [
+                ['team', 'serverless'],
+                ['stage', ''],
+                ['url', 'a=b'],
+            ]
+        )
+        assert.strictEqual(parseTags('')!.size, 0)
+    })
+
+    it('rejects pairs without a key', function () {
+        assert.strictEqual(parseTags('team'), undefined)
+        assert.strictEqual(parseTags('=serverless'), undefined)
+    })
+})
@@ -0,0 +1,154 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import {
+    compareObjectsCommand,
+    isBinary,
+    LARGE_OBJECT_BYTES,
+    S3ObjectDocumentProvider,
+} from '../../../s3/commands/compareObjects'
+import { S3BucketNode } from '../../../s3/explorer/s3BucketNode'
+import { S3FileNode } from '../../../s3/explorer/s3FileNode'
+import { S3Node } from '../../../s3/explorer/s3Nodes'
+import { Bucket, GetObjectRequest, ObjectVersion } from '../../../shared/clients/s3Client'
+import { MockS3Client } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('compareObjectsCommand', function () {
+    const bucket: Bucket = { name: 'bucket-name', region: 'region', arn: 'arn' }
+    const key = 'path/to/file.json'
+    const versions: ObjectVersion[] = [
+        { versionId: 'v2', isLatest: true, lastModified: new Date(2021, 5, 2), sizeBytes: 8 },
+        { versionId: 'v1', isLatest: false, lastModified: new Date(2021, 5, 1), sizeBytes: 8 },
+    ]
+    const contents: { [keyAndVersion: string]: string } = {
+        [`${key}@v1`]: '{"a": 1}',
+        [`${key}@`]: '{"a": 2}',
+        'path/to/other.json@': '{"b": 1}',
+    }
+
+    function makeNode(s3: MockS3Client, sizeBytes = 8): S3FileNode {
+        const bucketNode = new S3BucketNode(bucket, {} as S3Node, s3)
+
+        return new S3FileNode(bucket, { name: 'file.json', key, arn: 'arn', sizeBytes }, bucketNode, s3)
+    }
+
+    function makeS3(overrides: ConstructorParameters<typeof MockS3Client>[0] = {}): MockS3Client {
+        return new MockS3Client({
+            listKeyVersions: async () => versions,
+            headObject: async () => ({ sizeBytes: 8 }),
+            getObject: async (request: GetObjectRequest) => ({
+                body: Buffer.from(contents[`${request.key}@${request.versionId ?? ''}`]),
+            }),
+            ...overrides,
+        })
+    }
+
+    /** Picks the item whose label contains the given text. */
+    function pick(label: string) {
+        return async <T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) => {
+            const item = picker.items.find(item => item.label.includes(label))
+
+            return item ? [item] : undefined
+        }
+    }
+
+    function getContent(uri: vscode.Uri): string {
+        return new S3ObjectDocumentProvider().provideTextDocumentContent(uri)
+    }
+
+    it('diffs a previous version with the latest', async function () {
+        const commands = new FakeCommands()
+
+        await compareObjectsCommand(makeNode(makeS3()), new FakeWindow(), commands, async ({ picker }) => [
+            picker.items[0],
+        ])
+
+        const [left, right, title] = commands.args!
+        assert.strictEqual(commands.command, 'vscode.diff')
+        assert.strictEqual(getContent(left), '{"a": 1}')
+        assert.strictEqual(getContent(right), '{"a": 2}')
+        assert.ok(left.path.endsWith('.json'))
+        assert.ok(title.endsWith('↔ file.json (latest)'))
+    })
+
+    it('diffs another object of the bucket', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'path/to/other.json' } })
+        const commands = new FakeCommands()
+
+        await compareObjectsCommand(makeNode(makeS3()), window, commands, pick('Another object'))
+
+        assert.strictEqual(window.inputBox.options?.value, 'path/to/')
+        assert.strictEqual(getContent(commands.args![0]), '{"b": 1}')
+        assert.strictEqual(getContent(commands.args![1]), '{"a": 2}')
+    })
+
+    it('prompts for another object when the object has no previous versions', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'path/to/other.json' } })
+        const commands = new FakeCommands()
+
+        await compareObjectsCommand(
+            makeNode(makeS3({ listKeyVersions: async () => [versions[0]] })),
+            window,
+            commands,
+            async () => assert.fail('should not show versions')
+        )
+
+        assert.strictEqual(getContent(commands.args![0]), '{"b": 1}')
+    })
+
+    it('does not download large objects without confirming', async function () {
+        const s3 = makeS3({ getObject: async () => assert.fail('should not download objects') })
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await compareObjectsCommand(makeNode(s3, LARGE_OBJECT_BYTES + 1), window, commands, async ({ picker }) => [
+            picker.items[0],
+        ])
+
+        assert.ok(window.message.warning?.startsWith('The objects to compare are'))
+        assert.strictEqual(commands.command, undefined)
+    })
+
+    it('refuses binary objects', async function () {
+        const s3 = makeS3({ getObject: async () => ({ body: Buffer.from([0x89, 0x50, 0x4e, 0x47, 0x00, 0x01]) }) })
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await compareObjectsCommand(makeNode(s3), window, commands, async ({ picker }) => [picker.items[0]])
+
+        assert.ok(window.message.warning?.includes('is a binary file and cannot be compared as text'))
+        assert.strictEqual(commands.command, undefined)
+    })
+
+    it('shows an error message when an object cannot be downloaded', async function () {
+        const s3 = makeS3({
+            getObject: async () => {
+                throw new Error('Expected failure')
+            },
+        })
+        const window = new FakeWindow()
+
+        await compareObjectsCommand(makeNode(s3), window, new FakeCommands(), async ({ picker }) => [picker.items[0]])
+
+        assert.ok(window.message.error?.startsWith('Failed to compare file.json with path/to/file.json'))
+    })
+
+    it('does nothing when cancelled', async function () {
+        const commands = new FakeCommands()
+
+        await compareObjectsCommand(makeNode(makeS3()), new FakeWindow(), commands, async () => undefined)
+
+        assert.strictEqual(commands.command, undefined)
+    })
+
+    it('tells binary content from text', function () {
+        assert.ok(isBinary(Buffer.from([0x50, 0x4b, 0x03, 0x04, 0x00])))
+        assert.ok(!isBinary(Buffer.from('{"text": "ünïcödé"}')))
+    })
+})
@@ -0,0 +1,111 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as AWS from 'aws-sdk'
+import { copyPresignedUrlCommand, parseExpiration } from '../../../s3/commands/copyPresignedUrl'
+import { S3BucketNode } from '../../../s3/explorer/s3BucketNode'
+import { S3FileNode } from '../../../s3/explorer/s3FileNode'
+import { Bucket, SignedUrlRequest } from '../../../shared/clients/s3Client'
+import { MockS3Client } from '../../shared/clients/mockClients'
+import { FakeEnv } from '../../shared/vscode/fakeEnv'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('copyPresignedUrlCommand', function () {
+    const bucket: Bucket = { name: 'bucket-name', region: 'region', arn: 'arn' }
+    const url = 'https://bucket-name.s3.amazonaws.com/path/to/file.jpg?X-Amz-Signature=signature'
+
+    let signedUrlRequests: SignedUrlRequest[]
+    let encryption: string | undefined
+
+    function createFileNode(): S3FileNode {
+        const s3 = new MockS3Client({
+            getSignedUrl: async request => {
+                signedUrlRequests.push(request)
+                return url
+            },
+            getServerSideEncryption: async () => encryption,
+        })
+
+        return new S3FileNode(bucket, { name: 'file.jpg', key: 'path/to/file.jpg', arn: 'arn' }, {} as S3BucketNode, s3)
+    }
+
+    beforeEach(function () {
+        signedUrlRequests = []
+        encryption = undefined
+    })
+
+    it('copies a presigned URL with the chosen expiration to the clipboard', async function () {
+        const window = new FakeWindow({ inputBox: { input: '12h' } })
+        const env = new FakeEnv()
+
+        await copyPresignedUrlCommand(createFileNode(), window, env, async () => undefined)
+
+        assert.strictEqual(window.inputBox.options?.value, '1h')
+        assert.deepStrictEqual(signedUrlRequests, [
+            { bucketName: 'bucket-name', key: 'path/to/file.jpg', expiresInSeconds: 12 * 60 * 60 },
+        ])
+        assert.strictEqual(env.clipboard.text, url)
+        assert.strictEqual(window.message.warning, undefined)
+        assert.strictEqual(window.message.information, undefined)
+    })
+
+    it('does nothing when cancelled', async function () {
+        const env = new FakeEnv()
+
+        await copyPresignedUrlCommand(createFileNode(), new FakeWindow(), env, async () => undefined)
+
+        assert.deepStrictEqual(signedUrlRequests, [])
+        assert.strictEqual(env.clipboard.text, undefined)
+    })
+
+    it('shortens the expiration to that of temporary credentials, with a warning', async function () {
+        const credentials = new AWS.Credentials('accessKeyId', 'secretAccessKey', 'sessionToken')
+        credentials.expireTime = new Date(Date.now() + 60 * 60 * 1000)
+        const window = new FakeWindow({ inputBox: { input: '7d' } })
+
+        await copyPresignedUrlCommand(createFileNode(), window, new FakeEnv(), async () => credentials)
+
+        const expiresInSeconds = signedUrlRequests[0].expiresInSeconds
+        assert.ok(expiresInSeconds <= 60 * 60 && expiresInSeconds > 60 * 60 - 10, `${expiresInSeconds}`)
+        assert.ok(window.message.warning?.includes('instead of 7 days'))
+    })
+
+    it('notes that the object is encrypted with KMS', async function () {
+        encryption = 'aws:kms'
+        const window = new FakeWindow({ inputBox: { input: '1h' } })
+
+        await copyPresignedUrlCommand(createFileNode(), window, new FakeEnv(), async () => undefined)
+
+        assert.ok(window.message.information?.includes('file.jpg is encrypted with AWS KMS'))
+    })
+
+    it('rejects expirations that SigV4 does not support', async function () {
+        const window = new FakeWindow({ inputBox: { input: '8d' } })
+
+        await copyPresignedUrlCommand(createFileNode(), window, new FakeEnv(), async () => undefined)
+
+        assert.strictEqual(
+            window.inputBox.errorMessage,
+            'Enter a duration between 1 second and 7 days, e.g. 30m, 12h, or 7d'
+        )
+    })
+})
+
+describe('parseExpiration', function () {
+    it('parses durations in seconds, minutes, hours, and days', function () {
+        assert.strictEqual(parseExpiration('90s'), 90)
+        assert.strictEqual(parseExpiration('30m'), 30 * 60)
+        assert.strictEqual(parseExpiration(' 12 H '), 12 * 60 * 60)
+        assert.strictEqual(parseExpiration('7d'), 7 * 24 * 60 * 60)
+    })
+
+    it('rejects invalid durations', function () {
+        assert.strictEqual(parseExpiration('0s'), undefined)
+        assert.strictEqual(parseExpiration('169h'), undefined)
+        assert.strictEqual(parseExpiration('1w'), undefined)
+        assert.strictEqual(parseExpiration('soon'), undefined)
+    })
+})
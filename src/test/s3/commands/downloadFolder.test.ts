@@ -0,0 +1,177 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { downloadFolderCommand } from '../../../s3/commands/downloadFolder'
+import { S3FolderNode } from '../../../s3/explorer/s3FolderNode'
+import { Bucket, DownloadFileRequest, File } from '../../../shared/clients/s3Client'
+import { makeTemporaryToolkitFolder } from '../../../shared/filesystemUtilities'
+import { MockOutputChannel } from '../../mockOutputChannel'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import { MockS3Client } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('downloadFolderCommand', function () {
+    const bucket: Bucket = { name: 'bucket-name', region: 'region', arn: 'arn' }
+    const folderPath = 'logs/'
+
+    let tempFolder: string
+    let downloads: DownloadFileRequest[]
+
+    function file(key: string): File {
+        return { key, name: path.basename(key), arn: 'arn' }
+    }
+
+    function makeNode(keys: string[], downloadFile?: (request: DownloadFileRequest) => Promise<void>): S3FolderNode {
+        const s3 = new MockS3Client({
+            listFilesRecursive: request => {
+                assert.deepStrictEqual(request, { bucketName: bucket.name, prefix: folderPath })
+                return asyncGenerator(keys.map(file))
+            },
+            downloadFile: async request => {
+                downloads.push(request)
+                if (downloadFile) {
+                    return downloadFile(request)
+                }
+                await fs.writeFile(request.saveLocation.fsPath, request.key)
+            },
+        })
+
+        return new S3FolderNode(bucket, { name: 'logs', path: folderPath, arn: 'arn' }, s3)
+    }
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        downloads = []
+    })
+
+    afterEach(async function () {
+        await fs.remove(tempFolder)
+    })
+
+    it('downloads all objects under the folder, preserving the key structure', async function () {
+        const node = makeNode(['logs/', 'logs/a.log', 'logs/2021/b.log', 'logs/2021/'])
+        const window = new FakeWindow({ dialog: { openSelections: [vscode.Uri.file(tempFolder)] } })
+        const outputChannel = new MockOutputChannel()
+
+        await downloadFolderCommand(node, window, outputChannel)
+
+        assert.strictEqual(window.dialog.openOptions?.canSelectFolders, true)
+        assert.strictEqual(await fs.readFile(path.join(tempFolder, 'a.log'), 'utf-8'), 'logs/a.log')
+        assert.strictEqual(await fs.readFile(path.join(tempFolder, '2021', 'b.log'), 'utf-8'), 'logs/2021/b.log')
+        assert.strictEqual(downloads.length, 2)
+        assert.strictEqual(window.progress.options?.location, vscode.ProgressLocation.Notification)
+        assert.strictEqual((window.progress.options as vscode.ProgressOptions).cancellable, true)
+        assert.strictEqual(
+            outputChannel.lines[outputChannel.lines.length - 1],
+            `Downloaded 2 of 2 file(s) from s3://bucket-name/logs/ to ${tempFolder}`
+        )
+        assert.strictEqual(window.message.error, undefined)
+    })
+
+    it('limits the number of parallel downloads', async function () {
+        const keys = Array.from({ length: 20 }, (_, i) => `logs/${i}.log`)
+        let active = 0
+        let maxActive = 0
+        const node = makeNode(keys, async request => {
+            active++
+            maxActive = Math.max(maxActive, active)
+            await new Promise(resolve => setTimeout(resolve, 5))
+            await fs.writeFile(request.saveLocation.fsPath, '')
+            active--
+        })
+
+        await downloadFolderCommand(
+            node,
+            new FakeWindow({ dialog: { openSelections: [vscode.Uri.file(tempFolder)] } }),
+            new MockOutputChannel(),
+            3
+        )
+
+        assert.strictEqual(downloads.length, 20)
+        assert.strictEqual(maxActive, 3)
+    })
+
+    it('skips existing files when requested', async function () {
+        await fs.writeFile(path.join(tempFolder, 'a.log'), 'local')
+        const node = makeNode(['logs/a.log', 'logs/b.log'])
+        const window = new FakeWindow({
+            dialog: { openSelections: [vscode.Uri.file(tempFolder)] },
+            message: { warningSelection: 'Skip Existing' },
+        })
+
+        await downloadFolderCommand(node, window, new MockOutputChannel())
+
+        assert.ok(window.message.warning?.startsWith('1 of 2 files already exist'))
+        assert.deepStrictEqual(
+            downloads.map(request => request.key),
+            ['logs/b.log']
+        )
+        assert.strictEqual(await fs.readFile(path.join(tempFolder, 'a.log'), 'utf-8'), 'local')
+    })
+
+    it('overwrites existing files when requested', async function () {
+        await fs.writeFile(path.join(tempFolder, 'a.log'), 'local')
+        const node = makeNode(['logs/a.log'])
+        const window = new FakeWindow({
+            dialog: { openSelections: [vscode.Uri.file(tempFolder)] },
+            message: { warningSelection: 'Overwrite' },
+        })
+
+        await downloadFolderCommand(node, window, new MockOutputChannel())
+
+        assert.strictEqual(await fs.readFile(path.join(tempFolder, 'a.log'), 'utf-8'), 'logs/a.log')
+    })
+
+    it('does nothing when the existing files prompt is cancelled', async function () {
+        await fs.writeFile(path.join(tempFolder, 'a.log'), 'local')
+        const node = makeNode(['logs/a.log'])
+
+        await downloadFolderCommand(
+            node,
+            new FakeWindow({ dialog: { openSelections: [vscode.Uri.file(tempFolder)] } }),
+            new MockOutputChannel()
+        )
+
+        assert.strictEqual(downloads.length, 0)
+    })
+
+    it('reports keys that were renamed', async function () {
+        const node = makeNode(['logs/../escape.log'])
+        const outputChannel = new MockOutputChannel()
+
+        await downloadFolderCommand(
+            node,
+            new FakeWindow({ dialog: { openSelections: [vscode.Uri.file(tempFolder)] } }),
+            outputChannel
+        )
+
+        const localPath = path.join(tempFolder, '__', 'escape.log')
+        assert.ok(await fs.pathExists(localPath))
+        assert.ok(outputChannel.lines.includes(`Renamed logs/../escape.log to ${localPath}`))
+    })
+
+    it('shows an error message when some downloads fail', async function () {
+        const node = makeNode(['logs/a.log', 'logs/b.log'], async request => {
+            if (request.key === 'logs/b.log') {
+                throw new Error('Access denied')
+            }
+        })
+        const window = new FakeWindow({ dialog: { openSelections: [vscode.Uri.file(tempFolder)] } })
+
+        await downloadFolderCommand(node, window, new MockOutputChannel())
+
+        assert.ok(window.message.error?.includes('Failed to download 1 of 2 files'))
+    })
+
+    it('does nothing when prompt is cancelled', async function () {
+        await downloadFolderCommand(makeNode(['logs/a.log']), new FakeWindow(), new MockOutputChannel())
+
+        assert.strictEqual(downloads.length, 0)
+    })
+})
@@ -0,0 +1,76 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { clearObjectFilterCommand, filterObjectsCommand } from '../../../s3/commands/filterObjects'
+import { S3BucketNode } from '../../../s3/explorer/s3BucketNode'
+import { S3Node } from '../../../s3/explorer/s3Nodes'
+import { S3Client } from '../../../shared/clients/s3Client'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+import { instance, mock } from '../../utilities/mockito'
+
+describe('filterObjectsCommand', function () {
+    const bucketName = 'bucket-name'
+
+    let s3: S3Client
+    let node: S3BucketNode
+
+    beforeEach(function () {
+        s3 = mock()
+        node = new S3BucketNode(
+            { name: bucketName, region: 'region', arn: 'arn' },
+            new S3Node(instance(s3)),
+            instance(s3)
+        )
+    })
+
+    it('prompts for a filter, applies it to the node, and refreshes the node', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'logs/2024*.gz' } })
+        const commands = new FakeCommands()
+        await filterObjectsCommand(node, window, commands)
+
+        assert.deepStrictEqual(node.objectFilter, { prefix: 'logs/2024', suffix: '.gz' })
+        assert.strictEqual(node.label, 'bucket-name (filter: logs/2024*.gz)')
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [node])
+    })
+
+    it('prefills the prompt with the active filter', async function () {
+        node.setObjectFilter({ prefix: 'logs/' })
+        const window = new FakeWindow({ inputBox: { input: 'logs/' } })
+        await filterObjectsCommand(node, window, new FakeCommands())
+
+        assert.strictEqual(window.inputBox.options?.value, 'logs/')
+    })
+
+    it('clears the filter when the input is empty', async function () {
+        node.setObjectFilter({ prefix: 'logs/' })
+        await filterObjectsCommand(node, new FakeWindow({ inputBox: { input: '' } }), new FakeCommands())
+
+        assert.strictEqual(node.objectFilter, undefined)
+        assert.strictEqual(node.label, bucketName)
+    })
+
+    it('does nothing when prompt is cancelled', async function () {
+        node.setObjectFilter({ prefix: 'logs/' })
+        const commands = new FakeCommands()
+        await filterObjectsCommand(node, new FakeWindow(), commands)
+
+        assert.deepStrictEqual(node.objectFilter, { prefix: 'logs/' })
+        assert.strictEqual(commands.command, undefined)
+    })
+
+    describe('clearObjectFilterCommand', function () {
+        it('removes the filter and refreshes the node', async function () {
+            node.setObjectFilter({ prefix: 'logs/' })
+            const commands = new FakeCommands()
+            await clearObjectFilterCommand(node, commands)
+
+            assert.strictEqual(node.objectFilter, undefined)
+            assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        })
+    })
+})
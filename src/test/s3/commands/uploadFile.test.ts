@@ -5,6 +5,7 @@
 
 import * as assert from 'assert'
 import * as vscode from 'vscode'
+import * as fs from 'fs-extra'
 import * as path from 'path'
 import { S3 } from 'aws-sdk'
 import { FileSizeBytes, getFileToUpload, promptUserForBucket, uploadFileCommand } from '../../../s3/commands/uploadFile'
@@ -17,6 +18,10 @@ import { anything, mock, instance, when, capture } from '../../utilities/mockito
 import { Commands } from '../../../shared/vscode/commands'
 import { Window } from '../../../shared/vscode/window'
 import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { UploadMetadata } from '../../../s3/commands/uploadMetadata'
+import { MULTIPART_THRESHOLD_BYTES, MultipartUploadStore } from '../../../s3/multipartUpload'
+import { makeTemporaryToolkitFolder } from '../../../shared/filesystemUtilities'
+import { FakeExtensionContext } from '../../fakeExtensionContext'
 
 describe('uploadFileCommand', function () {
     const bucketName = 'bucket-name'
@@ -32,6 +37,8 @@ describe('uploadFileCommand', function () {
     let getBucket: (s3client: S3Client, window?: Window) => Promise<S3.Bucket | string>
     let getFile: (document?: vscode.Uri, window?: Window) => Promise<vscode.Uri | undefined>
     let commands: Commands
+    const metadata: UploadMetadata = { contentType: 'image/jpeg', cacheControl: 'max-age=60' }
+    const getMetadata = async (file: vscode.Uri) => metadata
 
     beforeEach(function () {
         s3 = mock()
@@ -77,7 +84,8 @@ describe('uploadFileCommand', function () {
                 getFile,
                 window,
                 outputChannel,
-                commands
+                commands,
+                getMetadata
             )
 
             // eslint-disable-next-line @typescript-eslint/unbound-method
@@ -86,6 +94,8 @@ describe('uploadFileCommand', function () {
             assert.strictEqual(uploadFileRequest.bucketName, bucketName)
             assert.strictEqual(uploadFileRequest.key, key)
             assert.strictEqual(uploadFileRequest.fileLocation, fileLocation)
+            assert.strictEqual(uploadFileRequest.contentType, 'image/jpeg')
+            assert.strictEqual(uploadFileRequest.cacheControl, 'max-age=60')
 
             uploadFileRequest.progressListener!(4) // +25% (+4/16)
 
@@ -99,6 +109,69 @@ describe('uploadFileCommand', function () {
             ])
         })
 
+        it('offers to resume an interrupted upload of a large file', async function () {
+            const tempFolder = await makeTemporaryToolkitFolder()
+            try {
+                const largeFile = vscode.Uri.file(path.join(tempFolder, fileName))
+                await fs.writeFile(largeFile.fsPath, 'abcdefghij')
+                const stats = await fs.stat(largeFile.fsPath)
+                const store = new MultipartUploadStore(new FakeExtensionContext().globalState)
+                await store.set({
+                    bucketName,
+                    key,
+                    uploadId: 'uploadId',
+                    filePath: largeFile.fsPath,
+                    fileSizeBytes: stats.size,
+                    fileModifiedMillis: stats.mtimeMs,
+                    partSizeBytes: 4,
+                    parts: [{ partNumber: 1, eTag: 'etag1' }],
+                    metadata,
+                })
+                when(s3.listParts(anything())).thenResolve([{ partNumber: 1, eTag: 'etag1' }])
+                when(s3.uploadPart(anything())).thenCall(async request => ({
+                    partNumber: request.partNumber,
+                    eTag: `etag${request.partNumber}`,
+                }))
+                when(s3.completeMultipartUpload(anything())).thenResolve()
+                window = new FakeWindow({ message: { informationSelection: 'Resume Upload' } })
+
+                await uploadFileCommand(
+                    instance(s3),
+                    bucketNode,
+                    () => MULTIPART_THRESHOLD_BYTES,
+                    undefined,
+                    async () => largeFile,
+                    window,
+                    outputChannel,
+                    commands,
+                    async () => {
+                        throw new Error('resumed uploads keep their metadata')
+                    },
+                    store
+                )
+
+                assert.strictEqual(
+                    window.message.information,
+                    'An earlier upload of file.jpg to s3://bucket-name/file.jpg was interrupted after 1 of 3 parts.'
+                )
+                // eslint-disable-next-line @typescript-eslint/unbound-method
+                const [completeRequest] = capture(s3.completeMultipartUpload).last()
+                assert.strictEqual(completeRequest.uploadId, 'uploadId')
+                assert.deepStrictEqual(
+                    completeRequest.parts.map(part => part.partNumber),
+                    [1, 2, 3]
+                )
+                assert.strictEqual(store.get(bucketName, key), undefined)
+                assert.strictEqual(window.progress.options?.cancellable, true)
+                assert.deepStrictEqual(outputChannel.lines, [
+                    `Uploading file ${fileName} to s3://bucket-name/file.jpg`,
+                    `Successfully uploaded file ${fileName} to bucket-name`,
+                ])
+            } finally {
+                await fs.remove(tempFolder)
+            }
+        })
+
         it('cancels and displays a message if a user does not select a file', async function () {
             window = new FakeWindow({ dialog: { openSelections: undefined } })
 
@@ -116,10 +189,28 @@ describe('uploadFileCommand', function () {
                 getFile,
                 window,
                 outputChannel,
-                commands
+                commands,
+                getMetadata
             )
             assert.deepStrictEqual(outputChannel.lines, ['No file selected, cancelling upload'])
         })
+
+        it('cancels and displays a message if a user cancels setting the metadata', async function () {
+            getFile = async () => fileLocation
+
+            await uploadFileCommand(
+                instance(s3),
+                bucketNode,
+                statFile,
+                undefined,
+                getFile,
+                window,
+                outputChannel,
+                commands,
+                async () => undefined
+            )
+            assert.deepStrictEqual(outputChannel.lines, [`Cancelled upload of ${fileName}`])
+        })
     })
 
     describe('without node parameter', async function () {
@@ -152,7 +243,8 @@ describe('uploadFileCommand', function () {
                 getFile,
                 window,
                 outputChannel,
-                commands
+                commands,
+                getMetadata
             )
             assert.deepStrictEqual(outputChannel.lines, [
                 `Uploading file ${fileName} to s3://bucket-name/file.jpg`,
@@ -175,7 +267,8 @@ describe('uploadFileCommand', function () {
                 getFile,
                 window,
                 outputChannel,
-                commands
+                commands,
+                getMetadata
             )
             assert.deepStrictEqual(outputChannel.lines, ['No bucket selected, cancelling upload'])
         })
@@ -195,7 +288,8 @@ describe('uploadFileCommand', function () {
                 getFile,
                 window,
                 outputChannel,
-                commands
+                commands,
+                getMetadata
             )
             assert.deepStrictEqual(outputChannel.lines, ['No file selected, cancelling upload'])
         })
@@ -226,7 +320,8 @@ describe('uploadFileCommand', function () {
             getFile,
             window,
             outputChannel,
-            commands
+            commands,
+            getMetadata
         )
 
         // eslint-disable-next-line @typescript-eslint/unbound-method
@@ -261,7 +356,8 @@ describe('uploadFileCommand', function () {
             getFile,
             window,
             outputChannel,
-            commands
+            commands,
+            getMetadata
         )
 
         assert.ok(window.message.error?.includes('Failed to upload file'))
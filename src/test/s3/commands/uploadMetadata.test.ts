@@ -0,0 +1,88 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import {
+    clearUploadMetadataOverrides,
+    getDefaultContentType,
+    parseMetadata,
+    promptForUploadMetadata,
+} from '../../../s3/commands/uploadMetadata'
+import { Window } from '../../../shared/vscode/window'
+
+describe('uploadMetadata', function () {
+    const htmlFile = vscode.Uri.file('/site/index.html')
+
+    afterEach(function () {
+        clearUploadMetadataOverrides()
+    })
+
+    describe('getDefaultContentType', function () {
+        it('detects the content type from the extension', function () {
+            assert.strictEqual(getDefaultContentType(htmlFile), 'text/html')
+            assert.strictEqual(getDefaultContentType(vscode.Uri.file('/file.unknown-ext')), 'application/octet-stream')
+        })
+    })
+
+    describe('parseMetadata', function () {
+        it('parses key=value pairs and strips the header prefix', function () {
+            assert.deepStrictEqual(parseMetadata('x-amz-meta-Author=me, version=2,,flag'), {
+                author: 'me',
+                version: '2',
+                flag: '',
+            })
+        })
+
+        it('rejects pairs without a key', function () {
+            assert.throws(() => parseMetadata('=value'))
+        })
+    })
+
+    describe('promptForUploadMetadata', function () {
+        function pick(label: string) {
+            return async <T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) =>
+                picker.items.filter(item => item.label === label)
+        }
+
+        function inputs(...values: (string | undefined)[]): Window {
+            return ({ showInputBox: async () => values.shift() } as any) as Window
+        }
+
+        it('uploads with the detected content type by default', async function () {
+            const metadata = await promptForUploadMetadata(htmlFile, inputs(), pick('Upload'))
+
+            assert.deepStrictEqual(metadata, { contentType: 'text/html', cacheControl: undefined, metadata: undefined })
+        })
+
+        it('remembers overrides for the session, with content types per extension', async function () {
+            const metadata = await promptForUploadMetadata(
+                htmlFile,
+                inputs('text/html; charset=utf-8', 'no-cache', 'owner=me'),
+                pick('Set content type and metadata...')
+            )
+            assert.deepStrictEqual(metadata, {
+                contentType: 'text/html; charset=utf-8',
+                cacheControl: 'no-cache',
+                metadata: { owner: 'me' },
+            })
+
+            const nextHtml = await promptForUploadMetadata(vscode.Uri.file('/site/about.html'), inputs(), pick('Upload'))
+            assert.strictEqual(nextHtml?.contentType, 'text/html; charset=utf-8')
+            assert.strictEqual(nextHtml?.cacheControl, 'no-cache')
+
+            const css = await promptForUploadMetadata(vscode.Uri.file('/site/style.css'), inputs(), pick('Upload'))
+            assert.strictEqual(css?.contentType, 'text/css')
+        })
+
+        it('returns undefined when cancelled', async function () {
+            assert.strictEqual(await promptForUploadMetadata(htmlFile, inputs(), async () => undefined), undefined)
+            assert.strictEqual(
+                await promptForUploadMetadata(htmlFile, inputs(undefined), pick('Set content type and metadata...')),
+                undefined
+            )
+        })
+    })
+})
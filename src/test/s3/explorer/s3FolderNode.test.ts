@@ -85,5 +85,28 @@ describe('S3FolderNode', function () {
             assertMoreResultsNode(moreResultsNode)
             assert.strictEqual(otherNodes.length, 0)
         })
+
+        it('passes the filter prefix to S3 and applies the suffix to each page', async function () {
+            const gzFile: File = { name: 'a.gz', key: `${path}logs/a.gz`, arn: 'arn' }
+            const txtFile: File = { name: 'b.txt', key: `${path}logs/b.txt`, arn: 'arn' }
+            when(
+                s3.listFiles(
+                    deepEqual({ bucketName, folderPath: `${path}logs/`, continuationToken: undefined, maxResults })
+                )
+            ).thenResolve({
+                folders: [],
+                files: [gzFile, txtFile],
+                continuationToken,
+            })
+
+            const node = new S3FolderNode(bucket, folder, instance(s3), workspace)
+            node.setObjectFilter({ prefix: 'logs/', suffix: '.gz' })
+            const [fileNode, moreResultsNode, ...otherNodes] = await node.getChildren()
+
+            assert.strictEqual(node.label, 'folder (filter: logs/*.gz)')
+            assertFileNode(fileNode, gzFile)
+            assertMoreResultsNode(moreResultsNode)
+            assert.strictEqual(otherNodes.length, 0)
+        })
     })
 })
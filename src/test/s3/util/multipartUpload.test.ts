@@ -0,0 +1,288 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import {
+    findIncompleteUpload,
+    getPartSizeBytes,
+    IncompleteUpload,
+    MultipartUploadProgress,
+    MultipartUploadStore,
+    uploadMultipart,
+} from '../../../s3/multipartUpload'
+import {
+    CompleteMultipartUploadRequest,
+    CreateMultipartUploadRequest,
+    UploadPartRequest,
+} from '../../../shared/clients/s3Client'
+import { makeTemporaryToolkitFolder } from '../../../shared/filesystemUtilities'
+import { FakeExtensionContext } from '../../fakeExtensionContext'
+import { MockS3Client } from '../../shared/clients/mockClients'
+
+describe('multipartUpload', function () {
+    const bucketName = 'bucket-name'
+    const key = 'artifact.zip'
+
+    let tempFolder: string
+    let fileLocation: vscode.Uri
+    let store: MultipartUploadStore
+    let uploadedParts: { partNumber: number; body: string }[]
+    let completed: CompleteMultipartUploadRequest | undefined
+    let aborted: string[]
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        fileLocation = vscode.Uri.file(path.join(tempFolder, key))
+        await fs.writeFile(fileLocation.fsPath, 'abcdefghij')
+        store = new MultipartUploadStore(new FakeExtensionContext().globalState)
+        uploadedParts = []
+        completed = undefined
+        aborted = []
+    })
+
+    afterEach(async function () {
+        await fs.remove(tempFolder)
+    })
+
+    function makeClient({
+        beforeUploadPart,
+        ...overrides
+    }: { beforeUploadPart?(request: UploadPartRequest): void } & ConstructorParameters<typeof MockS3Client>[0] = {}) {
+        return new MockS3Client({
+            createMultipartUpload: async () => 'new-upload',
+            uploadPart: async request => {
+                beforeUploadPart?.(request)
+                uploadedParts.push({ partNumber: request.partNumber, body: request.body.toString() })
+                request.progressListener?.(request.body.length)
+                return { partNumber: request.partNumber, eTag: `etag-${request.partNumber}` }
+            },
+            completeMultipartUpload: async request => {
+                completed = request
+            },
+            abortMultipartUpload: async request => {
+                aborted.push(request.uploadId)
+            },
+            ...overrides,
+        })
+    }
+
+    async function makeIncompleteUpload(
+        parts: number[],
+        metadata?: IncompleteUpload['metadata']
+    ): Promise<IncompleteUpload> {
+        const stats = await fs.stat(fileLocation.fsPath)
+        const upload: IncompleteUpload = {
+            bucketName,
+            key,
+            uploadId: 'old-upload',
+            filePath: fileLocation.fsPath,
+            fileSizeBytes: stats.size,
+            fileModifiedMillis: stats.mtimeMs,
+            partSizeBytes: 4,
+            parts: parts.map(partNumber => ({ partNumber, eTag: `etag-${partNumber}` })),
+            metadata,
+        }
+        await store.set(upload)
+
+        return upload
+    }
+
+    it('uses parts of at least 8 MiB, and at most 10,000 parts', function () {
+        assert.strictEqual(getPartSizeBytes(100 * 1024 * 1024), 8 * 1024 * 1024)
+        assert.strictEqual(getPartSizeBytes(200 * 1024 * 1024 * 1024), 21 * 1024 * 1024)
+    })
+
+    it('uploads a file in parts and forgets the upload once it is completed', async function () {
+        let createdWith: CreateMultipartUploadRequest | undefined
+        const s3Client = makeClient({
+            createMultipartUpload: async request => {
+                createdWith = request
+                return 'new-upload'
+            },
+        })
+
+        const result = await uploadMultipart({
+            s3Client,
+            store,
+            bucketName,
+            key,
+            fileLocation,
+            metadata: { contentType: 'application/zip', cacheControl: 'no-cache' },
+        })
+
+        assert.strictEqual(result, true)
+        assert.strictEqual(createdWith?.contentType, 'application/zip')
+        assert.strictEqual(createdWith?.cacheControl, 'no-cache')
+        assert.deepStrictEqual(uploadedParts, [{ partNumber: 1, body: 'abcdefghij' }])
+        assert.deepStrictEqual(completed, {
+            bucketName,
+            key,
+            uploadId: 'new-upload',
+            parts: [{ partNumber: 1, eTag: 'etag-1' }],
+        })
+        assert.strictEqual(store.get(bucketName, key), undefined)
+    })
+
+    it('resumes an upload from the parts that were not uploaded yet', async function () {
+        const resume = await makeIncompleteUpload([1])
+        const progress: MultipartUploadProgress[] = []
+
+        await uploadMultipart({
+            s3Client: makeClient(),
+            store,
+            bucketName,
+            key,
+            fileLocation,
+            resume,
+            onProgress: report => progress.push(report),
+            concurrency: 1,
+        })
+
+        assert.deepStrictEqual(uploadedParts, [
+            { partNumber: 2, body: 'efgh' },
+            { partNumber: 3, body: 'ij' },
+        ])
+        assert.deepStrictEqual(
+            completed?.parts.map(part => part.partNumber),
+            [1, 2, 3]
+        )
+        assert.strictEqual(completed?.uploadId, 'old-upload')
+        assert.deepStrictEqual(progress[0], { loadedBytes: 4, completedParts: 1, totalParts: 3 })
+        assert.deepStrictEqual(progress[progress.length - 1], { loadedBytes: 10, completedParts: 3, totalParts: 3 })
+    })
+
+    it('starts over if S3 no longer has the upload', async function () {
+        const resume = await makeIncompleteUpload([1])
+        const s3Client = makeClient({
+            listParts: async () => {
+                throw Object.assign(new Error('The specified upload does not exist'), { code: 'NoSuchUpload' })
+            },
+        })
+
+        await uploadMultipart({ s3Client, store, bucketName, key, fileLocation, resume })
+
+        assert.strictEqual(completed?.uploadId, 'new-upload')
+        assert.deepStrictEqual(uploadedParts, [{ partNumber: 1, body: 'abcdefghij' }])
+    })
+
+    it('starts over with the metadata of the expired upload', async function () {
+        const resume = await makeIncompleteUpload([1], { contentType: 'application/zip', metadata: { build: '42' } })
+        let createdWith: CreateMultipartUploadRequest | undefined
+        const s3Client = makeClient({
+            listParts: async () => {
+                throw Object.assign(new Error('The specified upload does not exist'), { code: 'NoSuchUpload' })
+            },
+            createMultipartUpload: async request => {
+                createdWith = request
+                return 'new-upload'
+            },
+        })
+
+        await uploadMultipart({ s3Client, store, bucketName, key, fileLocation, resume })
+
+        assert.strictEqual(createdWith?.contentType, 'application/zip')
+        assert.deepStrictEqual(createdWith?.metadata, { build: '42' })
+        assert.strictEqual(completed?.uploadId, 'new-upload')
+    })
+
+    it('remembers the metadata of an upload to start it over with', async function () {
+        const s3Client = makeClient({
+            uploadPart: async () => {
+                throw new Error('Connection reset')
+            },
+        })
+
+        await assert.rejects(
+            uploadMultipart({
+                s3Client,
+                store,
+                bucketName,
+                key,
+                fileLocation,
+                metadata: { contentType: 'application/zip', cacheControl: 'no-cache' },
+            }),
+            /Connection reset/
+        )
+
+        assert.deepStrictEqual(store.get(bucketName, key)?.metadata, {
+            contentType: 'application/zip',
+            cacheControl: 'no-cache',
+        })
+    })
+
+    it('keeps the uploaded parts to resume with when a part fails', async function () {
+        const resume = await makeIncompleteUpload([])
+        const s3Client = makeClient({
+            beforeUploadPart: request => {
+                if (request.partNumber === 3) {
+                    throw new Error('Connection reset')
+                }
+            },
+        })
+
+        await assert.rejects(
+            uploadMultipart({ s3Client, store, bucketName, key, fileLocation, resume, concurrency: 1 }),
+            /Connection reset/
+        )
+
+        assert.deepStrictEqual(
+            store.get(bucketName, key)?.parts.map(part => part.partNumber),
+            [1, 2]
+        )
+        assert.strictEqual(completed, undefined)
+        assert.deepStrictEqual(aborted, [])
+    })
+
+    it('aborts the upload when cancelled', async function () {
+        const resume = await makeIncompleteUpload([])
+        const cancellationToken = {
+            isCancellationRequested: false,
+            onCancellationRequested: new vscode.EventEmitter<any>().event,
+        }
+        const s3Client = makeClient({
+            beforeUploadPart: request => {
+                if (request.partNumber === 2) {
+                    cancellationToken.isCancellationRequested = true
+                    throw new Error('Request aborted')
+                }
+            },
+        })
+
+        const result = await uploadMultipart({
+            s3Client,
+            store,
+            bucketName,
+            key,
+            fileLocation,
+            resume,
+            cancellationToken,
+            concurrency: 1,
+        })
+
+        assert.strictEqual(result, false)
+        assert.deepStrictEqual(aborted, ['old-upload'])
+        assert.strictEqual(store.get(bucketName, key), undefined)
+        assert.strictEqual(completed, undefined)
+    })
+
+    describe('findIncompleteUpload', function () {
+        it('finds the incomplete upload of an unchanged file', async function () {
+            const upload = await makeIncompleteUpload([1])
+
+            assert.deepStrictEqual(await findIncompleteUpload(store, bucketName, key, fileLocation), upload)
+            assert.strictEqual(await findIncompleteUpload(store, bucketName, 'other-key', fileLocation), undefined)
+        })
+
+        it('does not resume uploads of a file that changed since', async function () {
+            await makeIncompleteUpload([1])
+            await fs.appendFile(fileLocation.fsPath, 'klmnop')
+
+            assert.strictEqual(await findIncompleteUpload(store, bucketName, key, fileLocation), undefined)
+        })
+    })
+})
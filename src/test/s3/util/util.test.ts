@@ -4,7 +4,7 @@
  */
 
 import * as assert from 'assert'
-import { readablePath } from '../../../s3/util'
+import { formatObjectFilter, parseObjectFilter, readablePath, toLocalRelativePath } from '../../../s3/util'
 
 describe('messages', function () {
     describe('readablePath', function () {
@@ -24,4 +24,47 @@ describe('messages', function () {
             assert.strictEqual(path, 's3://bucket-name/path/to/object')
         })
     })
+
+    describe('parseObjectFilter', function () {
+        it('parses a prefix and suffix', function () {
+            assert.deepStrictEqual(parseObjectFilter('logs/2024*.gz'), { prefix: 'logs/2024', suffix: '.gz' })
+        })
+
+        it('parses a prefix only', function () {
+            assert.deepStrictEqual(parseObjectFilter('logs/2024'), { prefix: 'logs/2024' })
+            assert.deepStrictEqual(parseObjectFilter('logs/2024*'), { prefix: 'logs/2024', suffix: undefined })
+        })
+
+        it('parses a suffix only', function () {
+            assert.deepStrictEqual(parseObjectFilter('*.gz'), { prefix: '', suffix: '.gz' })
+        })
+
+        it('returns undefined for empty input', function () {
+            assert.strictEqual(parseObjectFilter('  '), undefined)
+        })
+
+        it('round trips through formatObjectFilter', function () {
+            assert.strictEqual(formatObjectFilter(parseObjectFilter('logs/2024*.gz')!), 'logs/2024*.gz')
+            assert.strictEqual(formatObjectFilter(parseObjectFilter('logs/')!), 'logs/')
+        })
+    })
+
+    describe('toLocalRelativePath', function () {
+        it('keeps keys that are valid file names', function () {
+            assert.strictEqual(toLocalRelativePath('logs/2024/app.log', 'win32'), 'logs/2024/app.log')
+            assert.strictEqual(toLocalRelativePath('a:b/c?.txt', 'linux'), 'a:b/c?.txt')
+        })
+
+        it('replaces segments that would point outside of the download directory', function () {
+            assert.strictEqual(toLocalRelativePath('../../etc/passwd', 'linux'), '__/__/etc/passwd')
+            assert.strictEqual(toLocalRelativePath('a//./b', 'linux'), 'a/_/_/b')
+        })
+
+        it('sanitizes names that are not allowed on Windows', function () {
+            assert.strictEqual(toLocalRelativePath('a:b/c?.txt', 'win32'), 'a_b/c_.txt')
+            assert.strictEqual(toLocalRelativePath('dir\\file', 'win32'), 'dir_file')
+            assert.strictEqual(toLocalRelativePath('con/nul.txt', 'win32'), '_con/_nul.txt')
+            assert.strictEqual(toLocalRelativePath('trailing. /dot.', 'win32'), 'trailing__/dot_')
+        })
+    })
 })
@@ -0,0 +1,121 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { editSecret } from '../../../secretsManager/commands/editSecret'
+import { SecretNode } from '../../../secretsManager/explorer/secretNode'
+import { SecretsManagerNode } from '../../../secretsManager/explorer/secretsManagerNode'
+import { MockSecretsManagerClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('editSecret', function () {
+    const parent = {} as SecretsManagerNode
+    const secret = { Name: 'prod/db', ARN: 'arn:prod' }
+    const confirm = 'Write new version'
+    let written: string[]
+
+    function makeNode(
+        secretString: string,
+        putSecretValue: MockSecretsManagerClient['putSecretValue'] = async (secretId, secretString) => {
+            written.push(secretString)
+            return { Name: secretId }
+        }
+    ): SecretNode {
+        return new SecretNode(
+            parent,
+            {
+                ...new MockSecretsManagerClient(),
+                getSecretValue: async () => ({ Name: secret.Name, SecretString: secretString }),
+                putSecretValue,
+            },
+            secret
+        )
+    }
+
+    /** Picks the items whose labels contain the given texts, in order. */
+    function pickInOrder(...labels: string[]) {
+        return async <T extends vscode.QuickPickItem>({ picker }: { picker: vscode.QuickPick<T> }) => {
+            const label = labels.shift()
+            const item = picker.items.find(item => label !== undefined && item.label.includes(label))
+
+            return item ? [item] : undefined
+        }
+    }
+
+    beforeEach(function () {
+        written = []
+    })
+
+    it('edits a key of a JSON secret and writes a new version after confirming', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'new-password' }, message: { warningSelection: confirm } })
+
+        await editSecret(
+            makeNode('{"username":"admin","password":"old"}'),
+            window,
+            new FakeCommands(),
+            pickInOrder('password', 'Save new version')
+        )
+
+        assert.deepStrictEqual(written, ['{"username":"admin","password":"new-password"}'])
+        assert.strictEqual(window.inputBox.options?.password, true)
+        assert.strictEqual(window.message.information, 'Wrote a new version of secret prod/db')
+    })
+
+    it('adds a key to a JSON secret', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'host' }, message: { warningSelection: confirm } })
+
+        await editSecret(
+            makeNode('{"username":"admin"}'),
+            window,
+            new FakeCommands(),
+            pickInOrder('Add key', 'Save new version')
+        )
+
+        assert.deepStrictEqual(written, ['{"username":"admin","host":"host"}'])
+    })
+
+    it('edits a plaintext secret', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'hunter3' }, message: { warningSelection: confirm } })
+
+        await editSecret(makeNode('hunter2'), window, new FakeCommands(), pickInOrder())
+
+        assert.deepStrictEqual(written, ['hunter3'])
+    })
+
+    it('does not write a new version without confirmation', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'hunter3' } })
+
+        await editSecret(makeNode('hunter2'), window, new FakeCommands(), pickInOrder())
+
+        assert.ok(window.message.warning?.startsWith('Write a new version of secret prod/db?'))
+        assert.deepStrictEqual(written, [])
+    })
+
+    it('does not write a new version if nothing changed', async function () {
+        const window = new FakeWindow({ message: { warningSelection: confirm } })
+
+        await editSecret(makeNode('{"username":"admin"}'), window, new FakeCommands(), pickInOrder('Save new version'))
+
+        assert.deepStrictEqual(written, [])
+        assert.strictEqual(window.message.information, 'Secret prod/db was not changed')
+    })
+
+    it('shows a message and refreshes the explorer if the secret was deleted while editing', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'hunter3' }, message: { warningSelection: confirm } })
+        const commands = new FakeCommands()
+        const node = makeNode('hunter2', async () => {
+            throw Object.assign(new Error('not found'), { code: 'ResourceNotFoundException' })
+        })
+
+        await editSecret(node, window, commands, pickInOrder())
+
+        assert.strictEqual(window.message.warning, 'Secret prod/db no longer exists')
+        assert.strictEqual(window.message.error, undefined)
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [parent])
+    })
+})
@@ -0,0 +1,79 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { viewSecret } from '../../../secretsManager/commands/viewSecret'
+import { SecretNode } from '../../../secretsManager/explorer/secretNode'
+import { SecretsManagerNode } from '../../../secretsManager/explorer/secretsManagerNode'
+import { MASKED_VALUE } from '../../../secretsManager/secretValue'
+import { MockSecretsManagerClient } from '../../shared/clients/mockClients'
+import { FakeCommands } from '../../shared/vscode/fakeCommands'
+import { FakeEnv } from '../../shared/vscode/fakeEnv'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('viewSecret', function () {
+    const parent = {} as SecretsManagerNode
+    const secret = { Name: 'prod/db', ARN: 'arn:prod' }
+
+    function makeNode(getSecretValue: MockSecretsManagerClient['getSecretValue']): SecretNode {
+        return new SecretNode(parent, { ...new MockSecretsManagerClient(), getSecretValue }, secret)
+    }
+
+    it('masks values until revealed, and copies the chosen value', async function () {
+        const node = makeNode(async () => ({ Name: secret.Name, SecretString: '{"username":"admin"}' }))
+        const window = new FakeWindow()
+        const env = new FakeEnv()
+        const shown: (string | undefined)[] = []
+
+        await viewSecret(node, window, env, new FakeCommands(), async ({ picker, onDidTriggerButton }) => {
+            shown.push(picker.items[0].description)
+            onDidTriggerButton!(picker.buttons[0], () => {}, () => {})
+            shown.push(picker.items[0].description)
+            onDidTriggerButton!(picker.buttons[0], () => {}, () => {})
+            shown.push(picker.items[0].description)
+
+            return [picker.items[0]]
+        })
+
+        assert.deepStrictEqual(shown, [MASKED_VALUE, 'admin', MASKED_VALUE])
+        assert.strictEqual(env.clipboard.text, 'admin')
+        assert.strictEqual(window.statusBar.message, '$(clippy) Copied username to clipboard')
+    })
+
+    it('shows a message and refreshes the explorer if the secret was deleted', async function () {
+        const node = makeNode(async () => {
+            throw Object.assign(new Error('not found'), { code: 'ResourceNotFoundException' })
+        })
+        const window = new FakeWindow()
+        const commands = new FakeCommands()
+
+        await viewSecret(node, window, new FakeEnv(), commands, async () => {
+            assert.fail('should not prompt')
+        })
+
+        assert.strictEqual(window.message.warning, 'Secret prod/db no longer exists')
+        assert.strictEqual(window.message.error, undefined)
+        assert.strictEqual(commands.command, 'aws.refreshAwsExplorerNode')
+        assert.deepStrictEqual(commands.args, [parent])
+    })
+
+    it('shows an error for binary secrets', async function () {
+        const node = makeNode(async () => ({ Name: secret.Name, SecretBinary: 'AAAA' }))
+        const window = new FakeWindow()
+
+        await viewSecret(node, window, new FakeEnv(), new FakeCommands(), async () => undefined)
+
+        assert.ok(window.message.error?.includes('has a binary value'))
+    })
+
+    it('does not copy anything when cancelled', async function () {
+        const node = makeNode(async () => ({ Name: secret.Name, SecretString: 'hunter2' }))
+        const env = new FakeEnv()
+
+        await viewSecret(node, new FakeWindow(), env, new FakeCommands(), async () => undefined)
+
+        assert.strictEqual(env.clipboard.text, undefined)
+    })
+})
@@ -0,0 +1,57 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { SecretsManager } from 'aws-sdk'
+import { SecretNode } from '../../../secretsManager/explorer/secretNode'
+import { SecretsManagerNode } from '../../../secretsManager/explorer/secretsManagerNode'
+import { ErrorNode } from '../../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import { MockSecretsManagerClient } from '../../shared/clients/mockClients'
+
+describe('SecretsManagerNode', function () {
+    function makeNode(listSecrets: () => AsyncIterableIterator<SecretsManager.SecretListEntry>): SecretsManagerNode {
+        return new SecretsManagerNode({ ...new MockSecretsManagerClient(), listSecrets })
+    }
+
+    it('gets secrets sorted by name', async function () {
+        const node = makeNode(() =>
+            asyncGenerator<SecretsManager.SecretListEntry>([
+                { Name: 'prod/db', ARN: 'arn:prod' },
+                { Name: 'dev/db', ARN: 'arn:dev', DeletedDate: new Date() },
+            ])
+        )
+
+        const [first, second, ...others] = (await node.getChildren()) as SecretNode[]
+
+        assert.strictEqual(first.label, 'dev/db')
+        assert.strictEqual(first.arn, 'arn:dev')
+        assert.strictEqual(first.description, '(scheduled for deletion)')
+        assert.strictEqual(second.label, 'prod/db')
+        assert.strictEqual(second.description, undefined)
+        assert.strictEqual(others.length, 0)
+    })
+
+    it('shows a placeholder node when there are no secrets', async function () {
+        const [first, ...others] = await makeNode(() => asyncGenerator([])).getChildren()
+
+        assert.strictEqual((first as PlaceholderNode).label, '[No secrets found]')
+        assert.strictEqual(others.length, 0)
+    })
+
+    it('shows an error node when listing secrets fails', async function () {
+        const node = makeNode(async function* () {
+            throw new Error('network broke')
+            // at least one yield is required for async generator even if it is unreachable
+            yield {}
+        })
+
+        const [first, ...others] = await node.getChildren()
+
+        assert.ok(first instanceof ErrorNode)
+        assert.strictEqual(others.length, 0)
+    })
+})
@@ -0,0 +1,41 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { parseFieldValue, parseSecretValue, serializeSecretValue } from '../../secretsManager/secretValue'
+
+describe('secretValue', function () {
+    describe('parseSecretValue', function () {
+        it('parses flat JSON objects as key/value pairs', function () {
+            const value = parseSecretValue({ Name: 'secret', SecretString: '{"username":"admin","port":5432}' })
+
+            assert.deepStrictEqual(value, { kind: 'json', fields: { username: 'admin', port: 5432 } })
+            assert.strictEqual(serializeSecretValue(value), '{"username":"admin","port":5432}')
+        })
+
+        it('falls back to plaintext', function () {
+            for (const text of ['hunter2', '["a"]', '{"nested":{"a":1}}', '42']) {
+                const value = parseSecretValue({ Name: 'secret', SecretString: text })
+
+                assert.deepStrictEqual(value, { kind: 'text', text })
+                assert.strictEqual(serializeSecretValue(value), text)
+            }
+        })
+
+        it('rejects binary secrets', function () {
+            assert.throws(() => parseSecretValue({ Name: 'secret', SecretBinary: 'AAAA' }), /binary value/)
+        })
+    })
+
+    describe('parseFieldValue', function () {
+        it('keeps the type of non-string values', function () {
+            assert.strictEqual(parseFieldValue('5433', 5432), 5433)
+            assert.strictEqual(parseFieldValue('false', true), false)
+            assert.strictEqual(parseFieldValue('not a number', 5432), 'not a number')
+            assert.strictEqual(parseFieldValue('5433', '5432'), '5433')
+            assert.strictEqual(parseFieldValue('value', undefined), 'value')
+        })
+    })
+})
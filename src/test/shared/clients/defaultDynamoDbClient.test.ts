@@ -0,0 +1,93 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { AWSError, DynamoDB } from 'aws-sdk'
+import { DefaultDynamoDbClient } from '../../../shared/clients/dynamoDbClient'
+
+describe('DefaultDynamoDbClient', function () {
+    describe('listTables', function () {
+        it('lists tables from multiple pages', async function () {
+            const client = new TestDynamoDbClient()
+            client.listTablesResponses = [
+                { TableNames: ['table1', 'table2'], LastEvaluatedTableName: 'table2' },
+                { TableNames: ['table3'] },
+            ]
+
+            const tables = []
+            for await (const table of client.listTables()) {
+                tables.push(table)
+            }
+
+            assert.deepStrictEqual(tables, ['table1', 'table2', 'table3'])
+            assert.deepStrictEqual(client.listTablesRequests, [{}, { ExclusiveStartTableName: 'table2' }])
+        })
+    })
+
+    describe('scan', function () {
+        it('retries when the provisioned throughput is exceeded', async function () {
+            const client = new TestDynamoDbClient(3)
+            client.scanResponses = [throttlingError(), throttlingError(), { Items: [{ id: { S: 'item' } }] }]
+
+            const response = await client.scan({ TableName: 'table' })
+
+            assert.deepStrictEqual(response.Items, [{ id: { S: 'item' } }])
+            assert.strictEqual(client.scanCalls, 3)
+        })
+
+        it('fails after the maximum number of attempts', async function () {
+            const client = new TestDynamoDbClient(2)
+            client.scanResponses = [throttlingError(), throttlingError(), {}]
+
+            await assert.rejects(client.scan({ TableName: 'table' }), /Throughput exceeds/)
+            assert.strictEqual(client.scanCalls, 2)
+        })
+
+        it('does not retry other errors', async function () {
+            const client = new TestDynamoDbClient(3)
+            client.scanResponses = [new Error('Requested resource not found') as AWSError, {}]
+
+            await assert.rejects(client.scan({ TableName: 'table' }), /Requested resource not found/)
+            assert.strictEqual(client.scanCalls, 1)
+        })
+    })
+})
+
+function throttlingError(): AWSError {
+    const error = new Error('Throughput exceeds the current capacity of your table') as AWSError
+    error.code = 'ProvisionedThroughputExceededException'
+
+    return error
+}
+
+class TestDynamoDbClient extends DefaultDynamoDbClient {
+    public listTablesResponses: DynamoDB.ListTablesOutput[] = []
+    public listTablesRequests: DynamoDB.ListTablesInput[] = []
+    public scanResponses: (DynamoDB.ScanOutput | AWSError)[] = []
+    public scanCalls = 0
+
+    public constructor(throttleMaxAttempts?: number) {
+        super('us-weast-1', throttleMaxAttempts, 1)
+    }
+
+    protected async invokeListTables(request: DynamoDB.ListTablesInput): Promise<DynamoDB.ListTablesOutput> {
+        this.listTablesRequests.push({ ...request })
+
+        return this.listTablesResponses.shift() ?? {}
+    }
+
+    protected async invokeScan(): Promise<DynamoDB.ScanOutput> {
+        const response = this.scanResponses[this.scanCalls++]
+        if (response instanceof Error) {
+            throw response
+        }
+
+        return response
+    }
+
+    protected async createSdkClient(): Promise<DynamoDB> {
+        return {} as DynamoDB
+    }
+}
@@ -172,6 +172,45 @@ describe('defaultEcsClient', async function () {
             })
         })
     })
+
+    describe('listTasks', async function () {
+        it('lists running tasks of a service from multiple pages', async function () {
+            const targetArr1 = ['task1', 'task2']
+            const targetArr2 = ['task3']
+            testClient.listTasksResponses = [
+                {
+                    taskArns: targetArr1,
+                    nextToken: 'next',
+                },
+                {
+                    taskArns: targetArr2,
+                },
+            ]
+            const iterator = testClient.listTasks('mycluster', 'myservice')
+            const arr = []
+            for await (const item of iterator) {
+                arr.push(item)
+            }
+            assert.deepStrictEqual(targetArr1.concat(targetArr2), arr)
+            assert.deepStrictEqual(testClient.lastListTasksRequest, {
+                cluster: 'mycluster',
+                serviceName: 'myservice',
+                desiredStatus: 'RUNNING',
+                nextToken: 'next',
+            })
+        })
+
+        it('handles errors', async function () {
+            testClient.listTasksResponses = new Error() as AWSError
+            await assert.rejects(async () => {
+                const iterator = testClient.listTasks('mycluster', 'myservice')
+                const arr = []
+                for await (const item of iterator) {
+                    arr.push(item)
+                }
+            })
+        })
+    })
 })
 
 class TestEcsClient extends DefaultEcsClient {
@@ -181,6 +220,10 @@ class TestEcsClient extends DefaultEcsClient {
 
     public listTaskDefinitionFamiliesResponses: ECS.ListTaskDefinitionFamiliesResponse[] | AWSError = [{}]
 
+    public listTasksResponses: ECS.ListTasksResponse[] | AWSError = [{}]
+
+    public lastListTasksRequest: ECS.ListTasksRequest | undefined
+
     private pageNum: number = 0
 
     public constructor(regionCode: string = 'us-weast-1') {
@@ -213,6 +256,17 @@ class TestEcsClient extends DefaultEcsClient {
         }
     }
 
+    protected async invokeListTasks(request: ECS.ListTasksRequest): Promise<ECS.ListTasksResponse> {
+        this.lastListTasksRequest = { ...request }
+        const responseDatum = this.getResponseDatum<ECS.ListTasksResponse>(this.listTasksResponses, request.nextToken)
+
+        if (responseDatum instanceof Error) {
+            throw responseDatum
+        } else {
+            return responseDatum
+        }
+    }
+
     protected async invokeListTaskDefinitionFamilies(
         request: ECS.ListTaskDefinitionFamiliesRequest
     ): Promise<ECS.ListTaskDefinitionFamiliesResponse> {
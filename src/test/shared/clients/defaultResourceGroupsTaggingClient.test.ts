@@ -0,0 +1,74 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { AWSError, ResourceGroupsTaggingAPI } from 'aws-sdk'
+import {
+    DefaultResourceGroupsTaggingClient,
+    MAX_RESOURCES_PER_TAGGING_CALL,
+} from '../../../shared/clients/resourceGroupsTaggingClient'
+
+class TestResourceGroupsTaggingClient extends DefaultResourceGroupsTaggingClient {
+    public batches: string[][] = []
+
+    public constructor(private readonly respond: (batch: string[]) => ResourceGroupsTaggingAPI.FailedResourcesMap) {
+        super('us-west-2')
+    }
+
+    protected async createSdkClient(): Promise<ResourceGroupsTaggingAPI> {
+        const call = (request: { ResourceARNList: string[] }) => ({
+            promise: async () => {
+                this.batches.push(request.ResourceARNList)
+
+                return { FailedResourcesMap: this.respond(request.ResourceARNList) }
+            },
+        })
+
+        return ({ tagResources: call, untagResources: call } as any) as ResourceGroupsTaggingAPI
+    }
+}
+
+describe('DefaultResourceGroupsTaggingClient', function () {
+    const arns = Array.from({ length: MAX_RESOURCES_PER_TAGGING_CALL * 2 + 1 }, (_, i) => `arn:aws:sqs:::queue${i}`)
+
+    it('tags resources in batches, returning the resources that failed', async function () {
+        const client = new TestResourceGroupsTaggingClient(batch =>
+            batch.includes(arns[0]) ? { [arns[0]]: { ErrorMessage: 'denied', StatusCode: 403 } } : {}
+        )
+
+        const failed = await client.tagResources(arns, { team: 'blue' })
+
+        assert.deepStrictEqual(
+            client.batches.map(batch => batch.length),
+            [MAX_RESOURCES_PER_TAGGING_CALL, MAX_RESOURCES_PER_TAGGING_CALL, 1]
+        )
+        assert.deepStrictEqual(failed, { [arns[0]]: { ErrorMessage: 'denied', StatusCode: 403 } })
+    })
+
+    it('fails only the resources of a batch whose call fails', async function () {
+        const client = new TestResourceGroupsTaggingClient(batch => {
+            if (batch.includes(arns[MAX_RESOURCES_PER_TAGGING_CALL])) {
+                const error = new Error('Rate exceeded') as AWSError
+                error.statusCode = 400
+                throw error
+            }
+
+            return {}
+        })
+
+        const failed = await client.untagResources(arns, ['team'])
+
+        assert.strictEqual(client.batches.length, 3, 'the batches after the failed one are still made')
+        assert.deepStrictEqual(
+            Object.keys(failed),
+            client.batches[1],
+            'only the resources of the failed batch are reported'
+        )
+        assert.deepStrictEqual(failed[arns[MAX_RESOURCES_PER_TAGGING_CALL]], {
+            ErrorMessage: 'Rate exceeded',
+            StatusCode: 400,
+        })
+    })
+})
@@ -4,9 +4,11 @@
  */
 
 import * as assert from 'assert'
+import { PassThrough } from 'stream'
 import { AWSError, Request, S3 } from 'aws-sdk'
 import { DeleteObjectsRequest, ListObjectVersionsOutput, ListObjectVersionsRequest } from 'aws-sdk/clients/s3'
 import { ManagedUpload } from 'aws-sdk/lib/s3/managed_upload'
+import { toArrayAsync } from '../../../shared/utilities/collectionUtils'
 import { FileStreams } from '../../../shared/utilities/streamUtilities'
 import { anyFunction, anything, capture, deepEqual, instance, mock, verify, when } from '../../utilities/mockito'
 import * as vscode from 'vscode'
@@ -272,6 +274,30 @@ describe('DefaultS3Client', function () {
             assert.ok(progressCaptor.progress > 0)
         })
 
+        it('aborts the request when cancellation is requested', async function () {
+            const readStream = new PassThrough()
+            let aborted = false
+            when(mockS3.getObject(anything())).thenReturn({
+                createReadStream: () => readStream,
+                abort: () => {
+                    aborted = true
+                    readStream.destroy(new Error('Request aborted'))
+                },
+            } as any)
+            const cancellation = new vscode.CancellationTokenSource()
+
+            const download = createClient().downloadFile({
+                bucketName,
+                key: fileKey,
+                saveLocation: fileLocation,
+                cancellationToken: cancellation.token,
+            })
+            cancellation.cancel()
+
+            await assert.rejects(download, /Request aborted/)
+            assert.strictEqual(aborted, true)
+        })
+
         it('throws an Error on failure', async function () {
             when(mockS3.getObject(anything())).thenReturn(failure())
 
@@ -334,6 +360,87 @@ describe('DefaultS3Client', function () {
         })
     })
 
+    describe('createMultipartUpload', function () {
+        it('starts an upload with the content type of the key', async function () {
+            when(mockS3.createMultipartUpload(anything())).thenReturn(success({ UploadId: 'uploadId' }))
+
+            const uploadId = await createClient().createMultipartUpload({ bucketName, key: fileKey })
+
+            assert.strictEqual(uploadId, 'uploadId')
+            // eslint-disable-next-line @typescript-eslint/unbound-method
+            const [{ Bucket, Key, ContentType }] = capture(mockS3.createMultipartUpload).last()
+            assert.strictEqual(Bucket, bucketName)
+            assert.strictEqual(Key, fileKey)
+            assert.strictEqual(ContentType, 'image/jpeg')
+        })
+
+        it('throws an Error on failure', async function () {
+            when(mockS3.createMultipartUpload(anything())).thenReturn(failure())
+
+            await assert.rejects(createClient().createMultipartUpload({ bucketName, key: fileKey }), error)
+        })
+    })
+
+    describe('listParts', function () {
+        it('lists the parts of all pages', async function () {
+            when(
+                mockS3.listParts(
+                    deepEqual({ Bucket: bucketName, Key: fileKey, UploadId: 'uploadId', PartNumberMarker: undefined })
+                )
+            ).thenReturn(
+                success({ Parts: [{ PartNumber: 1, ETag: 'etag1' }], IsTruncated: true, NextPartNumberMarker: '1' })
+            )
+            when(
+                mockS3.listParts(
+                    deepEqual({ Bucket: bucketName, Key: fileKey, UploadId: 'uploadId', PartNumberMarker: '1' })
+                )
+            ).thenReturn(success({ Parts: [{ PartNumber: 2, ETag: 'etag2' }], IsTruncated: false }))
+
+            const parts = await createClient().listParts({ bucketName, key: fileKey, uploadId: 'uploadId' })
+
+            assert.deepStrictEqual(parts, [
+                { partNumber: 1, eTag: 'etag1' },
+                { partNumber: 2, eTag: 'etag2' },
+            ])
+        })
+    })
+
+    describe('completeMultipartUpload', function () {
+        it('completes an upload from its parts', async function () {
+            when(
+                mockS3.completeMultipartUpload(
+                    deepEqual({
+                        Bucket: bucketName,
+                        Key: fileKey,
+                        UploadId: 'uploadId',
+                        MultipartUpload: { Parts: [{ PartNumber: 1, ETag: 'etag1' }] },
+                    })
+                )
+            ).thenReturn(success({}))
+
+            await createClient().completeMultipartUpload({
+                bucketName,
+                key: fileKey,
+                uploadId: 'uploadId',
+                parts: [{ partNumber: 1, eTag: 'etag1' }],
+            })
+
+            verify(mockS3.completeMultipartUpload(anything())).once()
+        })
+    })
+
+    describe('abortMultipartUpload', function () {
+        it('aborts an upload', async function () {
+            when(
+                mockS3.abortMultipartUpload(deepEqual({ Bucket: bucketName, Key: fileKey, UploadId: 'uploadId' }))
+            ).thenReturn(success({}))
+
+            await createClient().abortMultipartUpload({ bucketName, key: fileKey, uploadId: 'uploadId' })
+
+            verify(mockS3.abortMultipartUpload(anything())).once()
+        })
+    })
+
     describe('listBuckets', function () {
         it('lists a bucket', async function () {
             when(mockS3.listBuckets()).thenReturn(
@@ -480,6 +587,47 @@ describe('DefaultS3Client', function () {
         })
     })
 
+    describe('listFilesRecursive', function () {
+        it('lists all objects under the prefix across pages', async function () {
+            when(
+                mockS3.listObjectsV2(
+                    deepEqual({
+                        Bucket: bucketName,
+                        MaxKeys: DEFAULT_MAX_KEYS,
+                        Prefix: folderPath,
+                        ContinuationToken: undefined,
+                    })
+                )
+            ).thenReturn(success({ Contents: [{ Key: fileKey }], NextContinuationToken: nextContinuationToken }))
+            when(
+                mockS3.listObjectsV2(
+                    deepEqual({
+                        Bucket: bucketName,
+                        MaxKeys: DEFAULT_MAX_KEYS,
+                        Prefix: folderPath,
+                        ContinuationToken: nextContinuationToken,
+                    })
+                )
+            ).thenReturn(success({ Contents: [{ Key: `${subFolderPath}file.jpg`, Size: fileSizeBytes }] }))
+
+            const files = await toArrayAsync(createClient().listFilesRecursive({ bucketName, prefix: folderPath }))
+
+            assert.deepStrictEqual(
+                files.map(file => file.key),
+                [fileKey, `${subFolderPath}file.jpg`]
+            )
+        })
+
+        it('throws an Error on failure', async function () {
+            when(mockS3.listObjectsV2(anything())).thenReturn(failure())
+
+            await assert.rejects(
+                toArrayAsync(createClient().listFilesRecursive({ bucketName, prefix: folderPath })),
+                error
+            )
+        })
+    })
+
     describe('listObjectVersions', function () {
         const { firstPageRequest, secondPageRequest, firstPageResponse, secondPageResponse } =
             new ListObjectVersionsFixtures()
@@ -3,17 +3,52 @@
  * SPDX-License-Identifier: Apache-2.0
  */
 import { S3 } from 'aws-sdk'
-import { APIGateway, CloudFormation, CloudWatchLogs, IAM, Lambda, Schemas, StepFunctions, STS, SSM } from 'aws-sdk'
+import {
+    APIGateway,
+    AppConfig,
+    CloudFormation,
+    CloudTrail,
+    CloudWatch,
+    CloudWatchLogs,
+    CognitoIdentityServiceProvider,
+    DynamoDB,
+    EC2,
+    ECS,
+    IAM,
+    Kinesis,
+    Lambda,
+    ResourceGroupsTaggingAPI,
+    Schemas,
+    SecretsManager,
+    SQS,
+    StepFunctions,
+    STS,
+    SSM,
+} from 'aws-sdk'
 import { ApiGatewayClient } from '../../../shared/clients/apiGatewayClient'
+import {
+    AppConfigClient,
+    CreateHostedConfigurationVersionRequest,
+    StartDeploymentRequest,
+} from '../../../shared/clients/appConfigClient'
 import { CloudFormationClient } from '../../../shared/clients/cloudFormationClient'
+import { CloudTrailClient } from '../../../shared/clients/cloudTrailClient'
+import { CloudWatchClient } from '../../../shared/clients/cloudWatchClient'
 import { CloudWatchLogsClient } from '../../../shared/clients/cloudWatchLogsClient'
-import { EcrClient, EcrRepository } from '../../../shared/clients/ecrClient'
+import { CognitoClient, ListUsersRequest, ListUsersResponse } from '../../../shared/clients/cognitoClient'
+import { DynamoDbClient } from '../../../shared/clients/dynamoDbClient'
+import { Ec2Client } from '../../../shared/clients/ec2Client'
+import { EcrAuthorization, EcrClient, EcrRepository } from '../../../shared/clients/ecrClient'
 import { EcsClient } from '../../../shared/clients/ecsClient'
 import { IamClient } from '../../../shared/clients/iamClient'
+import { KinesisClient } from '../../../shared/clients/kinesisClient'
 import { LambdaClient } from '../../../shared/clients/lambdaClient'
+import { ResourceGroupsTaggingClient } from '../../../shared/clients/resourceGroupsTaggingClient'
 import { SchemaClient } from '../../../shared/clients/schemaClient'
+import { SecretsManagerClient } from '../../../shared/clients/secretsManagerClient'
 import { StepFunctionsClient } from '../../../shared/clients/stepFunctionsClient'
 import { StsClient } from '../../../shared/clients/stsClient'
+import { SqsClient } from '../../../shared/clients/sqsClient'
 import { SsmDocumentClient } from '../../../shared/clients/ssmDocumentClient'
 import { ToolkitClientBuilder } from '../../../shared/clients/toolkitClientBuilder'
 
@@ -23,9 +58,19 @@ import {
     S3Client,
     CreateBucketRequest,
     ListFilesRequest,
+    ListFilesRecursiveRequest,
+    File,
     CreateFolderRequest,
     DownloadFileRequest,
     UploadFileRequest,
+    SignedUrlRequest,
+    GetServerSideEncryptionRequest,
+    HeadObjectRequest,
+    HeadObjectResponse,
+    GetObjectRequest,
+    GetObjectResponse,
+    ListKeyVersionsRequest,
+    ObjectVersion,
     ListObjectVersionsRequest,
     DeleteObjectRequest,
     DeleteObjectsRequest,
@@ -36,20 +81,35 @@ import {
     CreateFolderResponse,
     ListObjectVersionsResponse,
     DeleteObjectsResponse,
+    CreateMultipartUploadRequest,
+    UploadPartRequest,
+    CompletedPart,
+    MultipartUploadRequest,
+    CompleteMultipartUploadRequest,
 } from '../../../shared/clients/s3Client'
 
 interface Clients {
     apiGatewayClient: ApiGatewayClient
+    appConfigClient: AppConfigClient
     cloudFormationClient: CloudFormationClient
+    cloudTrailClient: CloudTrailClient
+    cloudWatchClient: CloudWatchClient
     cloudWatchLogsClient: CloudWatchLogsClient
+    cognitoClient: CognitoClient
+    dynamoDbClient: DynamoDbClient
+    ec2Client: Ec2Client
     ecrClient: EcrClient
     ecsClient: EcsClient
     iamClient: IamClient
+    kinesisClient: KinesisClient
     lambdaClient: LambdaClient
+    resourceGroupsTaggingClient: ResourceGroupsTaggingClient
     schemaClient: SchemaClient
+    secretsManagerClient: SecretsManagerClient
     stepFunctionsClient: StepFunctionsClient
     stsClient: StsClient
     s3Client: S3Client
+    sqsClient: SqsClient
     ssmDocumentClient: SsmDocumentClient
 }
 
@@ -58,16 +118,26 @@ export class MockToolkitClientBuilder implements ToolkitClientBuilder {
     public constructor(overrideClients?: Partial<Clients>) {
         this.clients = {
             apiGatewayClient: new MockApiGatewayClient(),
+            appConfigClient: new MockAppConfigClient({}),
             cloudFormationClient: new MockCloudFormationClient(),
+            cloudTrailClient: new MockCloudTrailClient({}),
+            cloudWatchClient: new MockCloudWatchClient({}),
             cloudWatchLogsClient: new MockCloudWatchLogsClient(),
+            cognitoClient: new MockCognitoClient({}),
+            dynamoDbClient: new MockDynamoDbClient({}),
+            ec2Client: new MockEc2Client({}),
             ecsClient: new MockEcsClient({}),
             ecrClient: new MockEcrClient({}),
             iamClient: new MockIamClient({}),
+            kinesisClient: new MockKinesisClient({}),
             lambdaClient: new MockLambdaClient({}),
+            resourceGroupsTaggingClient: new MockResourceGroupsTaggingClient({}),
             schemaClient: new MockSchemaClient(),
+            secretsManagerClient: new MockSecretsManagerClient(),
             stepFunctionsClient: new MockStepFunctionsClient(),
             stsClient: new MockStsClient({}),
             s3Client: new MockS3Client({}),
+            sqsClient: new MockSqsClient({}),
             ssmDocumentClient: new MockSsmDocumentClient(),
             ...overrideClients,
         }
@@ -77,18 +147,42 @@ export class MockToolkitClientBuilder implements ToolkitClientBuilder {
         return this.clients.apiGatewayClient
     }
 
+    public createAppConfigClient(regionCode: string): AppConfigClient {
+        return this.clients.appConfigClient
+    }
+
     public createCloudFormationClient(regionCode: string): CloudFormationClient {
         return this.clients.cloudFormationClient
     }
 
+    public createCloudTrailClient(regionCode: string): CloudTrailClient {
+        return this.clients.cloudTrailClient
+    }
+
+    public createCloudWatchClient(regionCode: string): CloudWatchClient {
+        return this.clients.cloudWatchClient
+    }
+
     public createCloudWatchLogsClient(regionCode: string): CloudWatchLogsClient {
         return this.clients.cloudWatchLogsClient
     }
 
+    public createCognitoClient(regionCode: string): CognitoClient {
+        return this.clients.cognitoClient
+    }
+
     public createSchemaClient(regionCode: string): SchemaClient {
         return this.clients.schemaClient
     }
 
+    public createDynamoDbClient(regionCode: string): DynamoDbClient {
+        return this.clients.dynamoDbClient
+    }
+
+    public createEc2Client(regionCode: string): Ec2Client {
+        return this.clients.ec2Client
+    }
+
     public createIamClient(): IamClient {
         return this.clients.iamClient
     }
@@ -101,10 +195,22 @@ export class MockToolkitClientBuilder implements ToolkitClientBuilder {
         return this.clients.ecsClient
     }
 
+    public createKinesisClient(regionCode: string): KinesisClient {
+        return this.clients.kinesisClient
+    }
+
     public createLambdaClient(regionCode: string): LambdaClient {
         return this.clients.lambdaClient
     }
 
+    public createResourceGroupsTaggingClient(regionCode: string): ResourceGroupsTaggingClient {
+        return this.clients.resourceGroupsTaggingClient
+    }
+
+    public createSecretsManagerClient(regionCode: string): SecretsManagerClient {
+        return this.clients.secretsManagerClient
+    }
+
     public createStepFunctionsClient(regionCode: string): StepFunctionsClient {
         return this.clients.stepFunctionsClient
     }
@@ -117,6 +223,10 @@ export class MockToolkitClientBuilder implements ToolkitClientBuilder {
         return this.clients.s3Client
     }
 
+    public createSqsClient(regionCode: string): SqsClient {
+        return this.clients.sqsClient
+    }
+
     public createSsmClient(regionCode: string): SsmDocumentClient {
         return this.clients.ssmDocumentClient
     }
@@ -142,12 +252,89 @@ export class MockApiGatewayClient implements ApiGatewayClient {
         resourceId: string,
         method: string,
         body: string,
-        pathWithQueryString: string | undefined
+        pathWithQueryString: string | undefined,
+        headers?: APIGateway.MapOfStringToString
     ): Promise<APIGateway.TestInvokeMethodResponse> {
         return Promise.resolve({})
     }
 }
 
+export class MockAppConfigClient implements AppConfigClient {
+    public readonly regionCode: string
+    public readonly listApplications: () => AsyncIterableIterator<AppConfig.Application>
+    public readonly listEnvironments: (applicationId: string) => AsyncIterableIterator<AppConfig.Environment>
+    public readonly listConfigurationProfiles: (
+        applicationId: string
+    ) => AsyncIterableIterator<AppConfig.ConfigurationProfileSummary>
+    public readonly listDeploymentStrategies: () => AsyncIterableIterator<AppConfig.DeploymentStrategy>
+    public readonly listHostedConfigurationVersions: (
+        applicationId: string,
+        profileId: string
+    ) => AsyncIterableIterator<AppConfig.HostedConfigurationVersionSummary>
+    public readonly getHostedConfigurationVersion: (
+        applicationId: string,
+        profileId: string,
+        versionNumber: number
+    ) => Promise<AppConfig.HostedConfigurationVersion>
+    public readonly createHostedConfigurationVersion: (
+        request: CreateHostedConfigurationVersionRequest
+    ) => Promise<AppConfig.HostedConfigurationVersion>
+    public readonly startDeployment: (request: StartDeploymentRequest) => Promise<AppConfig.Deployment>
+    public readonly getDeployment: (
+        applicationId: string,
+        environmentId: string,
+        deploymentNumber: number
+    ) => Promise<AppConfig.Deployment>
+
+    public constructor({
+        regionCode = '',
+        listApplications = () => asyncGenerator([]),
+        listEnvironments = () => asyncGenerator([]),
+        listConfigurationProfiles = () => asyncGenerator([]),
+        listDeploymentStrategies = () => asyncGenerator([]),
+        listHostedConfigurationVersions = () => asyncGenerator([]),
+        getHostedConfigurationVersion = async () => ({}),
+        createHostedConfigurationVersion = async () => ({}),
+        startDeployment = async () => ({}),
+        getDeployment = async () => ({}),
+    }: {
+        regionCode?: string
+        listApplications?(): AsyncIterableIterator<AppConfig.Application>
+        listEnvironments?(applicationId: string): AsyncIterableIterator<AppConfig.Environment>
+        listConfigurationProfiles?(applicationId: string): AsyncIterableIterator<AppConfig.ConfigurationProfileSummary>
+        listDeploymentStrategies?(): AsyncIterableIterator<AppConfig.DeploymentStrategy>
+        listHostedConfigurationVersions?(
+            applicationId: string,
+            profileId: string
+        ): AsyncIterableIterator<AppConfig.HostedConfigurationVersionSummary>
+        getHostedConfigurationVersion?(
+            applicationId: string,
+            profileId: string,
+            versionNumber: number
+        ): Promise<AppConfig.HostedConfigurationVersion>
+        createHostedConfigurationVersion?(
+            request: CreateHostedConfigurationVersionRequest
+        ): Promise<AppConfig.HostedConfigurationVersion>
+        startDeployment?(request: StartDeploymentRequest): Promise<AppConfig.Deployment>
+        getDeployment?(
+            applicationId: string,
+            environmentId: string,
+            deploymentNumber: number
+        ): Promise<AppConfig.Deployment>
+    }) {
+        this.regionCode = regionCode
+        this.listApplications = listApplications
+        this.listEnvironments = listEnvironments
+        this.listConfigurationProfiles = listConfigurationProfiles
+        this.listDeploymentStrategies = listDeploymentStrategies
+        this.listHostedConfigurationVersions = listHostedConfigurationVersions
+        this.getHostedConfigurationVersion = getHostedConfigurationVersion
+        this.createHostedConfigurationVersion = createHostedConfigurationVersion
+        this.startDeployment = startDeployment
+        this.getDeployment = getDeployment
+    }
+}
+
 export class MockCloudFormationClient implements CloudFormationClient {
     public constructor(
         public readonly regionCode: string = '',
@@ -162,17 +349,80 @@ export class MockCloudFormationClient implements CloudFormationClient {
             name: string
         ) => Promise<CloudFormation.DescribeStackResourcesOutput> = async (name: string) => ({
             StackResources: [],
+        }),
+
+        public readonly detectStackDrift: (name: string) => Promise<CloudFormation.StackDriftDetectionId> = async (
+            name: string
+        ) => '',
+
+        public readonly describeStackDriftDetectionStatus: (
+            detectionId: CloudFormation.StackDriftDetectionId
+        ) => Promise<CloudFormation.DescribeStackDriftDetectionStatusOutput> = async (
+            detectionId: CloudFormation.StackDriftDetectionId
+        ) => ({
+            StackId: '',
+            StackDriftDetectionId: detectionId,
+            DetectionStatus: 'DETECTION_COMPLETE',
+            Timestamp: new Date(),
+        }),
+
+        public readonly describeStackResourceDrifts: (
+            name: string
+        ) => Promise<CloudFormation.StackResourceDrift[]> = async (name: string) => [],
+
+        public readonly describeStack: (name: string) => Promise<CloudFormation.Stack | undefined> = async (
+            name: string
+        ) => undefined,
+
+        public readonly describeStackEvents: (
+            name: string,
+            nextToken?: string
+        ) => Promise<CloudFormation.DescribeStackEventsOutput> = async (name: string, nextToken?: string) => ({
+            StackEvents: [],
         })
     ) {}
 }
 
+export class MockCloudTrailClient implements CloudTrailClient {
+    public readonly regionCode: string
+    public readonly lookupEvents: (startTime: Date, endTime: Date) => AsyncIterableIterator<CloudTrail.Event>
+
+    public constructor({
+        regionCode = '',
+        lookupEvents = () => asyncGenerator([]),
+    }: {
+        regionCode?: string
+        lookupEvents?(startTime: Date, endTime: Date): AsyncIterableIterator<CloudTrail.Event>
+    }) {
+        this.regionCode = regionCode
+        this.lookupEvents = lookupEvents
+    }
+}
+
+export class MockCloudWatchClient implements CloudWatchClient {
+    public readonly regionCode: string
+    public readonly getMetricData: (request: CloudWatch.GetMetricDataInput) => Promise<CloudWatch.MetricDataResult[]>
+
+    public constructor({
+        regionCode = '',
+        getMetricData = async () => [],
+    }: {
+        regionCode?: string
+        getMetricData?(request: CloudWatch.GetMetricDataInput): Promise<CloudWatch.MetricDataResult[]>
+    }) {
+        this.regionCode = regionCode
+        this.getMetricData = getMetricData
+    }
+}
+
 export class MockCloudWatchLogsClient implements CloudWatchLogsClient {
     public constructor(
         public readonly regionCode: string = '',
 
         public readonly describeLogGroups: (
-            statusFilter?: string[]
-        ) => AsyncIterableIterator<CloudWatchLogs.LogGroup> = (statusFilter?: string[]) => asyncGenerator([]),
+            request?: CloudWatchLogs.DescribeLogGroupsRequest
+        ) => AsyncIterableIterator<CloudWatchLogs.LogGroup> = (request?: CloudWatchLogs.DescribeLogGroupsRequest) =>
+            asyncGenerator([]),
 
         public readonly describeLogStreams: (
             request: CloudWatchLogs.DescribeLogStreamsRequest
@@ -186,10 +436,65 @@ export class MockCloudWatchLogsClient implements CloudWatchLogsClient {
             request: CloudWatchLogs.GetLogEventsRequest
         ) => Promise<CloudWatchLogs.GetLogEventsResponse> = async (request: CloudWatchLogs.GetLogEventsRequest) => {
             return {}
+        },
+
+        public readonly startQuery: (
+            request: CloudWatchLogs.StartQueryRequest
+        ) => Promise<CloudWatchLogs.StartQueryResponse> = async (request: CloudWatchLogs.StartQueryRequest) => {
+            return {}
+        },
+
+        public readonly getQueryResults: (queryId: string) => Promise<CloudWatchLogs.GetQueryResultsResponse> = async (
+            queryId: string
+        ) => {
+            return {}
+        },
+
+        public readonly stopQuery: (queryId: string) => Promise<CloudWatchLogs.StopQueryResponse> = async (
+            queryId: string
+        ) => {
+            return {}
+        },
+
+        public readonly filterLogEvents: (
+            request: CloudWatchLogs.FilterLogEventsRequest
+        ) => Promise<CloudWatchLogs.FilterLogEventsResponse> = async (
+            request: CloudWatchLogs.FilterLogEventsRequest
+        ) => {
+            return {}
         }
     ) {}
 }
 
+export class MockResourceGroupsTaggingClient implements ResourceGroupsTaggingClient {
+    public readonly regionCode: string
+    public readonly tagResources: (
+        arns: string[],
+        tags: ResourceGroupsTaggingAPI.TagMap
+    ) => Promise<ResourceGroupsTaggingAPI.FailedResourcesMap>
+    public readonly untagResources: (
+        arns: string[],
+        tagKeys: string[]
+    ) => Promise<ResourceGroupsTaggingAPI.FailedResourcesMap>
+
+    public constructor({
+        regionCode = '',
+        tagResources = async () => ({}),
+        untagResources = async () => ({}),
+    }: {
+        regionCode?: string
+        tagResources?(
+            arns: string[],
+            tags: ResourceGroupsTaggingAPI.TagMap
+        ): Promise<ResourceGroupsTaggingAPI.FailedResourcesMap>
+        untagResources?(arns: string[], tagKeys: string[]): Promise<ResourceGroupsTaggingAPI.FailedResourcesMap>
+    }) {
+        this.regionCode = regionCode
+        this.tagResources = tagResources
+        this.untagResources = untagResources
+    }
+}
+
 export class MockSchemaClient implements SchemaClient {
     public constructor(
         public readonly regionCode: string = '',
@@ -262,6 +567,90 @@ export class MockSchemaClient implements SchemaClient {
     ) {}
 }
 
+export class MockCognitoClient implements CognitoClient {
+    public readonly regionCode: string
+    public readonly listUserPools: () => AsyncIterableIterator<CognitoIdentityServiceProvider.UserPoolDescriptionType>
+    public readonly describeUserPool: (userPoolId: string) => Promise<CognitoIdentityServiceProvider.UserPoolType>
+    public readonly listUsers: (request: ListUsersRequest) => Promise<ListUsersResponse>
+    public readonly adminGetUser: (
+        userPoolId: string,
+        username: string
+    ) => Promise<CognitoIdentityServiceProvider.AdminGetUserResponse>
+    public readonly adminSetUserPassword: (
+        userPoolId: string,
+        username: string,
+        password: string,
+        permanent: boolean
+    ) => Promise<void>
+    public readonly adminEnableUser: (userPoolId: string, username: string) => Promise<void>
+    public readonly adminDisableUser: (userPoolId: string, username: string) => Promise<void>
+
+    public constructor({
+        regionCode = '',
+        listUserPools = () => asyncGenerator([]),
+        describeUserPool = async () => ({}),
+        listUsers = async () => ({ users: [] }),
+        adminGetUser = async (userPoolId: string, username: string) => ({ Username: username }),
+        adminSetUserPassword = async () => {},
+        adminEnableUser = async () => {},
+        adminDisableUser = async () => {},
+    }: {
+        regionCode?: string
+        listUserPools?(): AsyncIterableIterator<CognitoIdentityServiceProvider.UserPoolDescriptionType>
+        describeUserPool?(userPoolId: string): Promise<CognitoIdentityServiceProvider.UserPoolType>
+        listUsers?(request: ListUsersRequest): Promise<ListUsersResponse>
+        adminGetUser?(
+            userPoolId: string,
+            username: string
+        ): Promise<CognitoIdentityServiceProvider.AdminGetUserResponse>
+        adminSetUserPassword?(
+            userPoolId: string,
+            username: string,
+            password: string,
+            permanent: boolean
+        ): Promise<void>
+        adminEnableUser?(userPoolId: string, username: string): Promise<void>
+        adminDisableUser?(userPoolId: string, username: string): Promise<void>
+    }) {
+        this.regionCode = regionCode
+        this.listUserPools = listUserPools
+        this.describeUserPool = describeUserPool
+        this.listUsers = listUsers
+        this.adminGetUser = adminGetUser
+        this.adminSetUserPassword = adminSetUserPassword
+        this.adminEnableUser = adminEnableUser
+        this.adminDisableUser = adminDisableUser
+    }
+}
+
+export class MockDynamoDbClient implements DynamoDbClient {
+    public readonly regionCode: string
+    public readonly listTables: () => AsyncIterableIterator<string>
+    public readonly describeTable: (tableName: string) => Promise<DynamoDB.TableDescription>
+    public readonly scan: (request: DynamoDB.ScanInput) => Promise<DynamoDB.ScanOutput>
+    public readonly query: (request: DynamoDB.QueryInput) => Promise<DynamoDB.QueryOutput>
+
+    public constructor({
+        regionCode = '',
+        listTables = () => asyncGenerator([]),
+        describeTable = async () => ({}),
+        scan = async () => ({}),
+        query = async () => ({}),
+    }: {
+        regionCode?: string
+        listTables?(): AsyncIterableIterator<string>
+        describeTable?(tableName: string): Promise<DynamoDB.TableDescription>
+        scan?(request: DynamoDB.ScanInput): Promise<DynamoDB.ScanOutput>
+        query?(request: DynamoDB.QueryInput): Promise<DynamoDB.QueryOutput>
+    }) {
+        this.regionCode = regionCode
+        this.listTables = listTables
+        this.describeTable = describeTable
+        this.scan = scan
+        this.query = query
+    }
+}
+
 export class MockEcrClient implements EcrClient {
     public readonly regionCode: string
     public readonly describeRepositories: () => AsyncIterableIterator<EcrRepository>
@@ -269,6 +658,7 @@ export class MockEcrClient implements EcrClient {
     public readonly deleteRepository: (repositoryName: string) => Promise<void>
     public readonly deleteTag: (repositoryName: string, tag: string) => Promise<void>
     public readonly createRepository: (repositoryName: string) => Promise<void>
+    public readonly getAuthorizationToken: () => Promise<EcrAuthorization>
 
     public constructor({
         regionCode = '',
@@ -277,6 +667,7 @@ export class MockEcrClient implements EcrClient {
         deleteRepository = async () => {},
         deleteTag = async () => {},
         createRepository = async () => {},
+        getAuthorizationToken = async () => ({ username: '', password: '', proxyEndpoint: '' }),
     }: {
         regionCode?: string
         describeRepositories?(): AsyncIterableIterator<EcrRepository>
@@ -284,6 +675,7 @@ export class MockEcrClient implements EcrClient {
         deleteRepository?(): Promise<void>
         deleteTag?(): Promise<void>
         createRepository?(): Promise<void>
+        getAuthorizationToken?(): Promise<EcrAuthorization>
     }) {
         this.regionCode = regionCode
         this.describeRepositories = describeRepositories
@@ -291,6 +683,71 @@ export class MockEcrClient implements EcrClient {
         this.deleteRepository = deleteRepository
         this.deleteTag = deleteTag
         this.createRepository = createRepository
+        this.getAuthorizationToken = getAuthorizationToken
+    }
+}
+
+export class MockEc2Client implements Ec2Client {
+    public readonly regionCode: string
+    public readonly describeInstances: () => AsyncIterableIterator<EC2.Instance>
+    public readonly getInstance: (instanceId: string) => Promise<EC2.Instance | undefined>
+    public readonly startInstance: (instanceId: string) => Promise<void>
+    public readonly stopInstance: (instanceId: string) => Promise<void>
+    public readonly rebootInstance: (instanceId: string) => Promise<void>
+    public readonly describeRegions: () => Promise<EC2.Region[]>
+
+    public constructor({
+        regionCode = '',
+        describeInstances = () => asyncGenerator([]),
+        getInstance = async () => undefined,
+        startInstance = async () => {},
+        stopInstance = async () => {},
+        rebootInstance = async () => {},
+        describeRegions = async () => [],
+    }: {
+        regionCode?: string
+        describeInstances?(): AsyncIterableIterator<EC2.Instance>
+        getInstance?(instanceId: string): Promise<EC2.Instance | undefined>
+        startInstance?(instanceId: string): Promise<void>
+        stopInstance?(instanceId: string): Promise<void>
+        rebootInstance?(instanceId: string): Promise<void>
+        describeRegions?(): Promise<EC2.Region[]>
+    }) {
+        this.regionCode = regionCode
+        this.describeInstances = describeInstances
+        this.getInstance = getInstance
+        this.startInstance = startInstance
+        this.stopInstance = stopInstance
+        this.rebootInstance = rebootInstance
+        this.describeRegions = describeRegions
+    }
+}
+
+export class MockKinesisClient implements KinesisClient {
+    public readonly regionCode: string
+    public readonly listStreams: () => AsyncIterableIterator<string>
+    public readonly listShards: (streamName: string) => AsyncIterableIterator<Kinesis.Shard>
+    public readonly getShardIterator: (request: Kinesis.GetShardIteratorInput) => Promise<string | undefined>
+    public readonly getRecords: (shardIterator: string, limit?: number) => Promise<Kinesis.GetRecordsOutput>
+
+    public constructor({
+        regionCode = '',
+        listStreams = () => asyncGenerator([]),
+        listShards = () => asyncGenerator([]),
+        getShardIterator = async () => undefined,
+        getRecords = async () => ({ Records: [] }),
+    }: {
+        regionCode?: string
+        listStreams?(): AsyncIterableIterator<string>
+        listShards?(streamName: string): AsyncIterableIterator<Kinesis.Shard>
+        getShardIterator?(request: Kinesis.GetShardIteratorInput): Promise<string | undefined>
+        getRecords?(shardIterator: string, limit?: number): Promise<Kinesis.GetRecordsOutput>
+    }) {
+        this.regionCode = regionCode
+        this.listStreams = listStreams
+        this.listShards = listShards
+        this.getShardIterator = getShardIterator
+        this.getRecords = getRecords
     }
 }
 
@@ -299,22 +756,42 @@ export class MockEcsClient implements EcsClient {
     public readonly listClusters: () => AsyncIterableIterator<string>
     public readonly listServices: (cluster: string) => AsyncIterableIterator<string>
     public readonly listTaskDefinitionFamilies: () => AsyncIterableIterator<string>
+    public readonly describeServices: (cluster: string, services: string[]) => Promise<ECS.Service[]>
+    public readonly listTasks: (cluster: string, serviceName: string) => AsyncIterableIterator<string>
+    public readonly describeTasks: (cluster: string, tasks: string[]) => Promise<ECS.Task[]>
+    public readonly enableExecuteCommand: (cluster: string, service: string) => Promise<void>
+    public readonly executeCommand: (request: ECS.ExecuteCommandRequest) => Promise<ECS.ExecuteCommandResponse>
 
     public constructor({
         regionCode = '',
         listClusters = () => asyncGenerator([]),
         listServices = (cluster: string) => asyncGenerator([]),
         listTaskDefinitionFamilies = () => asyncGenerator([]),
+        describeServices = async (cluster: string, services: string[]) => [],
+        listTasks = (cluster: string, serviceName: string) => asyncGenerator([]),
+        describeTasks = async (cluster: string, tasks: string[]) => [],
+        enableExecuteCommand = async (cluster: string, service: string) => {},
+        executeCommand = async (request: ECS.ExecuteCommandRequest) => ({}),
     }: {
         regionCode?: string
         listClusters?(): AsyncIterableIterator<string>
         listServices?(cluster: string): AsyncIterableIterator<string>
         listTaskDefinitionFamilies?(): AsyncIterableIterator<string>
+        describeServices?(cluster: string, services: string[]): Promise<ECS.Service[]>
+        listTasks?(cluster: string, serviceName: string): AsyncIterableIterator<string>
+        describeTasks?(cluster: string, tasks: string[]): Promise<ECS.Task[]>
+        enableExecuteCommand?(cluster: string, service: string): Promise<void>
+        executeCommand?(request: ECS.ExecuteCommandRequest): Promise<ECS.ExecuteCommandResponse>
     }) {
         this.regionCode = regionCode
         this.listClusters = listClusters
         this.listServices = listServices
         this.listTaskDefinitionFamilies = listTaskDefinitionFamilies
+        this.describeServices = describeServices
+        this.listTasks = listTasks
+        this.describeTasks = describeTasks
+        this.enableExecuteCommand = enableExecuteCommand
+        this.executeCommand = executeCommand
     }
 }
 
@@ -330,32 +807,84 @@ export class MockIamClient implements IamClient {
 export class MockLambdaClient implements LambdaClient {
     public readonly regionCode: string
     public readonly deleteFunction: (name: string) => Promise<void>
-    public readonly invoke: (name: string, payload?: Lambda._Blob) => Promise<Lambda.InvocationResponse>
+    public readonly invoke: (
+        name: string,
+        payload?: Lambda._Blob,
+        qualifier?: string
+    ) => Promise<Lambda.InvocationResponse>
     public readonly listFunctions: () => AsyncIterableIterator<Lambda.FunctionConfiguration>
     public readonly getFunction: (name: string) => Promise<Lambda.GetFunctionResponse>
+    public readonly getFunctionConfiguration: (name: string) => Promise<Lambda.FunctionConfiguration>
+    public readonly updateFunctionConfiguration: (
+        request: Lambda.UpdateFunctionConfigurationRequest
+    ) => Promise<Lambda.FunctionConfiguration>
     public readonly updateFunctionCode: (name: string, zipFile: Buffer) => Promise<Lambda.FunctionConfiguration>
+    public readonly listVersions: (name: string) => AsyncIterableIterator<Lambda.FunctionConfiguration>
+    public readonly publishVersion: (name: string, description?: string) => Promise<Lambda.FunctionConfiguration>
+    public readonly listAliases: (name: string) => AsyncIterableIterator<Lambda.AliasConfiguration>
+    public readonly createAlias: (request: Lambda.CreateAliasRequest) => Promise<Lambda.AliasConfiguration>
+    public readonly updateAlias: (request: Lambda.UpdateAliasRequest) => Promise<Lambda.AliasConfiguration>
+    public readonly listLayers: () => AsyncIterableIterator<Lambda.LayersListItem>
+    public readonly listLayerVersions: (layerName: string) => AsyncIterableIterator<Lambda.LayerVersionsListItem>
+    public readonly getLayerVersion: (layerVersionArn: string) => Promise<Lambda.GetLayerVersionResponse>
+    public readonly deleteLayerVersion: (layerName: string, versionNumber: number) => Promise<void>
 
     public constructor({
         regionCode = '',
         deleteFunction = async (name: string) => {},
-        invoke = async (name: string, payload?: Lambda._Blob) => ({}),
+        invoke = async (name: string, payload?: Lambda._Blob, qualifier?: string) => ({}),
         listFunctions = () => asyncGenerator([]),
         getFunction = async (name: string) => ({}),
+        getFunctionConfiguration = async (name: string) => ({}),
+        updateFunctionConfiguration = async (request: Lambda.UpdateFunctionConfigurationRequest) => ({}),
         updateFunctionCode = async (name: string, zipFile: Buffer) => ({}),
+        listVersions = (name: string) => asyncGenerator([]),
+        publishVersion = async (name: string, description?: string) => ({}),
+        listAliases = (name: string) => asyncGenerator([]),
+        createAlias = async (request: Lambda.CreateAliasRequest) => ({}),
+        updateAlias = async (request: Lambda.UpdateAliasRequest) => ({}),
+        listLayers = () => asyncGenerator([]),
+        listLayerVersions = (layerName: string) => asyncGenerator([]),
+        getLayerVersion = async (layerVersionArn: string) => ({}),
+        deleteLayerVersion = async (layerName: string, versionNumber: number) => {},
     }: {
         regionCode?: string
         deleteFunction?(name: string): Promise<void>
-        invoke?(name: string, payload?: Lambda._Blob): Promise<Lambda.InvocationResponse>
+        invoke?(name: string, payload?: Lambda._Blob, qualifier?: string): Promise<Lambda.InvocationResponse>
         listFunctions?(): AsyncIterableIterator<Lambda.FunctionConfiguration>
         getFunction?(name: string): Promise<Lambda.GetFunctionResponse>
+        getFunctionConfiguration?(name: string): Promise<Lambda.FunctionConfiguration>
+        updateFunctionConfiguration?(
+            request: Lambda.UpdateFunctionConfigurationRequest
+        ): Promise<Lambda.FunctionConfiguration>
         updateFunctionCode?(name: string, zipFile: Buffer): Promise<Lambda.FunctionConfiguration>
+        listVersions?(name: string): AsyncIterableIterator<Lambda.FunctionConfiguration>
+        publishVersion?(name: string, description?: string): Promise<Lambda.FunctionConfiguration>
+        listAliases?(name: string): AsyncIterableIterator<Lambda.AliasConfiguration>
+        createAlias?(request: Lambda.CreateAliasRequest): Promise<Lambda.AliasConfiguration>
+        updateAlias?(request: Lambda.UpdateAliasRequest): Promise<Lambda.AliasConfiguration>
+        listLayers?(): AsyncIterableIterator<Lambda.LayersListItem>
+        listLayerVersions?(layerName: string): AsyncIterableIterator<Lambda.LayerVersionsListItem>
+        getLayerVersion?(layerVersionArn: string): Promise<Lambda.GetLayerVersionResponse>
+        deleteLayerVersion?(layerName: string, versionNumber: number): Promise<void>
     }) {
         this.regionCode = regionCode
         this.deleteFunction = deleteFunction
         this.invoke = invoke
         this.listFunctions = listFunctions
         this.getFunction = getFunction
+        this.getFunctionConfiguration = getFunctionConfiguration
+        this.updateFunctionConfiguration = updateFunctionConfiguration
         this.updateFunctionCode = updateFunctionCode
+        this.listVersions = listVersions
+        this.publishVersion = publishVersion
+        this.listAliases = listAliases
+        this.createAlias = createAlias
+        this.updateAlias = updateAlias
+        this.listLayers = listLayers
+        this.listLayerVersions = listLayerVersions
+        this.getLayerVersion = getLayerVersion
+        this.deleteLayerVersion = deleteLayerVersion
     }
 }
 
@@ -400,6 +929,25 @@ export class MockStepFunctionsClient implements StepFunctionsClient {
             params: StepFunctions.UpdateStateMachineInput
         ) => ({
             updateDate: new Date(),
+        }),
+
+        public readonly listExecutions: (
+            arn: string,
+            maxResults?: number
+        ) => Promise<StepFunctions.ListExecutionsOutput> = async (arn: string, maxResults?: number) => ({
+            executions: [],
+        }),
+
+        public readonly getExecutionHistory: (
+            executionArn: string,
+            nextToken?: string,
+            maxResults?: number
+        ) => Promise<StepFunctions.GetExecutionHistoryOutput> = async (
+            executionArn: string,
+            nextToken?: string,
+            maxResults?: number
+        ) => ({
+            events: [],
         })
     ) {}
 }
@@ -420,6 +968,66 @@ export class MockStsClient implements StsClient {
     }
 }
 
+export class MockSecretsManagerClient implements SecretsManagerClient {
+    public constructor(
+        public readonly regionCode: string = '',
+
+        public readonly listSecrets: () => AsyncIterableIterator<SecretsManager.SecretListEntry> = () =>
+            asyncGenerator([]),
+
+        public readonly getSecretValue: (secretId: string) => Promise<SecretsManager.GetSecretValueResponse> = async (
+            secretId: string
+        ) => ({ Name: secretId, SecretString: '' }),
+
+        public readonly putSecretValue: (
+            secretId: string,
+            secretString: string
+        ) => Promise<SecretsManager.PutSecretValueResponse> = async (secretId: string, secretString: string) => ({
+            Name: secretId,
+        })
+    ) {}
+}
+
+export class MockSqsClient implements SqsClient {
+    public readonly regionCode: string
+    public readonly listQueues: () => AsyncIterableIterator<string>
+    public readonly getQueueAttributes: (queueUrl: string, attributeNames: string[]) => Promise<SQS.QueueAttributeMap>
+    public readonly receiveMessages: (
+        queueUrl: string,
+        maxNumberOfMessages: number,
+        visibilityTimeout: number
+    ) => Promise<SQS.Message[]>
+    public readonly sendMessage: (request: SQS.SendMessageRequest) => Promise<SQS.SendMessageResult>
+    public readonly purgeQueue: (queueUrl: string) => Promise<void>
+
+    public constructor({
+        regionCode = '',
+        listQueues = () => asyncGenerator([]),
+        getQueueAttributes = async () => ({}),
+        receiveMessages = async () => [],
+        sendMessage = async () => ({}),
+        purgeQueue = async () => {},
+    }: {
+        regionCode?: string
+        listQueues?(): AsyncIterableIterator<string>
+        getQueueAttributes?(queueUrl: string, attributeNames: string[]): Promise<SQS.QueueAttributeMap>
+        receiveMessages?(
+            queueUrl: string,
+            maxNumberOfMessages: number,
+            visibilityTimeout: number
+        ): Promise<SQS.Message[]>
+        sendMessage?(request: SQS.SendMessageRequest): Promise<SQS.SendMessageResult>
+        purgeQueue?(queueUrl: string): Promise<void>
+    }) {
+        this.regionCode = regionCode
+        this.listQueues = listQueues
+        this.getQueueAttributes = getQueueAttributes
+        this.receiveMessages = receiveMessages
+        this.sendMessage = sendMessage
+        this.purgeQueue = purgeQueue
+    }
+}
+
 export class MockSsmDocumentClient implements SsmDocumentClient {
     public constructor(
         public readonly regionCode: string = '',
@@ -471,7 +1079,21 @@ export class MockSsmDocumentClient implements SsmDocumentClient {
         ) => Promise<SSM.Types.UpdateDocumentDefaultVersionResult> = async (
             documentName: string,
             documentVersion: string
-        ) => ({})
+        ) => ({}),
+
+        public readonly terminateSession: (
+            sessionId: string
+        ) => Promise<SSM.Types.TerminateSessionResponse> = async (sessionId: string) => ({}),
+
+        public readonly startSession: (target: string) => Promise<SSM.Types.StartSessionResponse> = async (
+            target: string
+        ) => ({}),
+
+        public readonly getOnlineManagedInstanceIds: (instanceIds: string[]) => Promise<Set<string>> = async (
+            instanceIds: string[]
+        ) => new Set(),
+
+        public readonly getEndpoint: () => Promise<string> = async () => ''
     ) {}
 }
 export class MockS3Client implements S3Client {
@@ -481,9 +1103,15 @@ export class MockS3Client implements S3Client {
     public readonly listAllBuckets: () => Promise<S3.Bucket[]>
     public readonly listBuckets: () => Promise<ListBucketsResponse>
     public readonly listFiles: (request: ListFilesRequest) => Promise<ListFilesResponse>
+    public readonly listFilesRecursive: (request: ListFilesRecursiveRequest) => AsyncIterableIterator<File>
     public readonly createFolder: (request: CreateFolderRequest) => Promise<CreateFolderResponse>
     public readonly downloadFile: (request: DownloadFileRequest) => Promise<void>
     public readonly uploadFile: (request: UploadFileRequest) => Promise<void>
+    public readonly getSignedUrl: (request: SignedUrlRequest) => Promise<string>
+    public readonly getServerSideEncryption: (request: GetServerSideEncryptionRequest) => Promise<string | undefined>
+    public readonly headObject: (request: HeadObjectRequest) => Promise<HeadObjectResponse>
+    public readonly getObject: (request: GetObjectRequest) => Promise<GetObjectResponse>
+    public readonly listKeyVersions: (request: ListKeyVersionsRequest) => Promise<ObjectVersion[]>
     public readonly listObjectVersions: (request: ListObjectVersionsRequest) => Promise<ListObjectVersionsResponse>
     public readonly listObjectVersionsIterable: (
         request: ListObjectVersionsRequest
@@ -491,6 +1119,11 @@ export class MockS3Client implements S3Client {
     public readonly deleteObject: (request: DeleteObjectRequest) => Promise<void>
     public readonly deleteObjects: (request: DeleteObjectsRequest) => Promise<DeleteObjectsResponse>
     public readonly deleteBucket: (request: DeleteBucketRequest) => Promise<void>
+    public readonly createMultipartUpload: (request: CreateMultipartUploadRequest) => Promise<string>
+    public readonly uploadPart: (request: UploadPartRequest) => Promise<CompletedPart>
+    public readonly listParts: (request: MultipartUploadRequest) => Promise<CompletedPart[]>
+    public readonly completeMultipartUpload: (request: CompleteMultipartUploadRequest) => Promise<void>
+    public readonly abortMultipartUpload: (request: MultipartUploadRequest) => Promise<void>
 
     public constructor({
         regionCode = '',
@@ -498,23 +1131,40 @@ export class MockS3Client implements S3Client {
         listAllBuckets = async () => [],
         listBuckets = async () => ({ buckets: [] }),
         listFiles = async (request: ListFilesRequest) => ({ files: [], folders: [] }),
+        listFilesRecursive = (request: ListFilesRecursiveRequest) => asyncGenerator([]),
         createFolder = async (request: CreateFolderRequest) => ({ folder: { name: '', path: '', arn: '' } }),
         downloadFile = async (request: DownloadFileRequest) => {},
         uploadFile = async (request: UploadFileRequest) => {},
+        getSignedUrl = async (request: SignedUrlRequest) => '',
+        getServerSideEncryption = async (request: GetServerSideEncryptionRequest) => undefined,
+        headObject = async (request: HeadObjectRequest) => ({}),
+        getObject = async (request: GetObjectRequest) => ({ body: Buffer.from('') }),
+        listKeyVersions = async (request: ListKeyVersionsRequest) => [],
         listObjectVersions = async (request: ListObjectVersionsRequest) => ({ objects: [] }),
         listObjectVersionsIterable = (request: ListObjectVersionsRequest) => asyncGenerator([]),
         deleteObject = async (request: DeleteObjectRequest) => {},
         deleteObjects = async (request: DeleteObjectsRequest) => ({ errors: [] }),
         deleteBucket = async (request: DeleteBucketRequest) => {},
+        createMultipartUpload = async (request: CreateMultipartUploadRequest) => '',
+        uploadPart = async (request: UploadPartRequest) => ({ partNumber: request.partNumber, eTag: '' }),
+        listParts = async (request: MultipartUploadRequest) => [],
+        completeMultipartUpload = async (request: CompleteMultipartUploadRequest) => {},
+        abortMultipartUpload = async (request: MultipartUploadRequest) => {},
     }: {
         regionCode?: string
         createBucket?(request: CreateBucketRequest): Promise<CreateBucketResponse>
         listAllBuckets?(): Promise<S3.Bucket[]>
         listBuckets?(): Promise<ListBucketsResponse>
         listFiles?(request: ListFilesRequest): Promise<ListFilesResponse>
+        listFilesRecursive?(request: ListFilesRecursiveRequest): AsyncIterableIterator<File>
         createFolder?(request: CreateFolderRequest): Promise<CreateFolderResponse>
         downloadFile?(request: DownloadFileRequest): Promise<void>
         uploadFile?(request: UploadFileRequest): Promise<void>
+        getSignedUrl?(request: SignedUrlRequest): Promise<string>
+        getServerSideEncryption?(request: GetServerSideEncryptionRequest): Promise<string | undefined>
+        headObject?(request: HeadObjectRequest): Promise<HeadObjectResponse>
+        getObject?(request: GetObjectRequest): Promise<GetObjectResponse>
+        listKeyVersions?(request: ListKeyVersionsRequest): Promise<ObjectVersion[]>
         listObjectVersions?(request: ListObjectVersionsRequest): Promise<ListObjectVersionsResponse>
         listObjectVersionsIterable?(
             request: ListObjectVersionsRequest
@@ -522,19 +1172,35 @@ export class MockS3Client implements S3Client {
         deleteObject?(request: DeleteObjectRequest): Promise<void>
         deleteObjects?(request: DeleteObjectsRequest): Promise<DeleteObjectsResponse>
         deleteBucket?(request: DeleteBucketRequest): Promise<void>
+        createMultipartUpload?(request: CreateMultipartUploadRequest): Promise<string>
+        uploadPart?(request: UploadPartRequest): Promise<CompletedPart>
+        listParts?(request: MultipartUploadRequest): Promise<CompletedPart[]>
+        completeMultipartUpload?(request: CompleteMultipartUploadRequest): Promise<void>
+        abortMultipartUpload?(request: MultipartUploadRequest): Promise<void>
     }) {
         this.regionCode = regionCode
         this.createBucket = createBucket
         this.listAllBuckets = listAllBuckets
         this.listBuckets = listBuckets
         this.listFiles = listFiles
+        this.listFilesRecursive = listFilesRecursive
         this.createFolder = createFolder
         this.downloadFile = downloadFile
         this.uploadFile = uploadFile
+        this.getSignedUrl = getSignedUrl
+        this.getServerSideEncryption = getServerSideEncryption
+        this.headObject = headObject
+        this.getObject = getObject
+        this.listKeyVersions = listKeyVersions
         this.listObjectVersions = listObjectVersions
         this.listObjectVersionsIterable = listObjectVersionsIterable
         this.deleteObject = deleteObject
         this.deleteObjects = deleteObjects
         this.deleteBucket = deleteBucket
+        this.createMultipartUpload = createMultipartUpload
+        this.uploadPart = uploadPart
+        this.listParts = listParts
+        this.completeMultipartUpload = completeMultipartUpload
+        this.abortMultipartUpload = abortMultipartUpload
     }
 }
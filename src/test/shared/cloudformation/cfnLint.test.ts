@@ -0,0 +1,124 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { CfnLinter, CfnLintFinding, CfnLintResult, toDiagnostics } from '../../../shared/cloudformation/cfnLint'
+
+const finding: CfnLintFinding = {
+    Rule: {
+        Id: 'E3012',
+        ShortDescription: 'Check resource properties values',
+        Source: 'https://github.com/aws-cloudformation/cfn-python-lint/blob/main/docs/cfn-resource-specification.md',
+    },
+    Location: {
+        Start: { LineNumber: 8, ColumnNumber: 7 },
+        End: { LineNumber: 8, ColumnNumber: 14 },
+    },
+    Level: 'Error',
+    Message: 'Property Resources/Function/Properties/Timeout should be of type Integer',
+}
+
+describe('toDiagnostics', function () {
+    it('maps cfn-lint findings to zero-based diagnostics', function () {
+        const [diagnostic] = toDiagnostics([finding])
+
+        assert.deepStrictEqual(diagnostic.range, new vscode.Range(7, 6, 7, 13))
+        assert.strictEqual(diagnostic.severity, vscode.DiagnosticSeverity.Error)
+        assert.strictEqual(diagnostic.code, 'E3012')
+        assert.strictEqual(diagnostic.source, 'cfn-lint')
+        assert.strictEqual(diagnostic.message, finding.Message)
+    })
+
+    it('maps warning and informational levels', function () {
+        const diagnostics = toDiagnostics([
+            { ...finding, Level: 'Warning' },
+            { ...finding, Level: 'Informational' },
+        ])
+
+        assert.deepStrictEqual(
+            diagnostics.map(diagnostic => diagnostic.severity),
+            [vscode.DiagnosticSeverity.Warning, vscode.DiagnosticSeverity.Information]
+        )
+    })
+})
+
+async function waitForDebounce(): Promise<void> {
+    await new Promise(resolve => setTimeout(resolve, 50))
+}
+
+describe('CfnLinter', function () {
+    const template = { uri: vscode.Uri.file('/app/template.yaml') } as vscode.TextDocument
+    let lintedPaths: string[]
+    let lintResult: CfnLintResult
+    let linter: CfnLinter
+
+    beforeEach(function () {
+        lintedPaths = []
+        lintResult = { findings: [finding] }
+        linter = new CfnLinter(
+            document => document.uri.fsPath.endsWith('template.yaml'),
+            async templatePath => {
+                lintedPaths.push(templatePath)
+
+                return lintResult
+            },
+            10
+        )
+    })
+
+    afterEach(function () {
+        linter.dispose()
+    })
+
+    it('debounces lints of the same template', async function () {
+        linter.lintDocument(template)
+        linter.lintDocument(template)
+        linter.lintDocument(template)
+        await waitForDebounce()
+
+        assert.deepStrictEqual(lintedPaths, [template.uri.fsPath])
+        assert.strictEqual(linter.getDiagnostics(template.uri).length, 1)
+    })
+
+    it('ignores documents that are not templates', async function () {
+        linter.lintDocument({ uri: vscode.Uri.file('/app/buildspec.yaml') } as vscode.TextDocument)
+        await waitForDebounce()
+
+        assert.deepStrictEqual(lintedPaths, [])
+    })
+
+    it('stops linting when cfn-lint is not available', async function () {
+        lintResult = { findings: undefined }
+        await linter.lintNow(template.uri)
+
+        linter.lintDocument(template)
+        await waitForDebounce()
+
+        assert.strictEqual(lintedPaths.length, 1)
+        assert.strictEqual(linter.getDiagnostics(template.uri).length, 0)
+    })
+
+    it('offers to open the documentation of reported rules', async function () {
+        await linter.lintNow(template.uri)
+        const diagnostics = linter.getDiagnostics(template.uri)
+
+        const actions = linter.provideCodeActions(template, diagnostics[0].range, {
+            diagnostics,
+        } as vscode.CodeActionContext)
+
+        assert.strictEqual(actions.length, 1)
+        assert.strictEqual(actions[0].title, 'Show documentation for cfn-lint rule E3012')
+        assert.deepStrictEqual(actions[0].command?.arguments, [vscode.Uri.parse(finding.Rule.Source!)])
+    })
+
+    it('clears the diagnostics of closed templates', async function () {
+        await linter.lintNow(template.uri)
+
+        linter.clear(template.uri)
+
+        assert.strictEqual(linter.getDiagnostics(template.uri).length, 0)
+    })
+})
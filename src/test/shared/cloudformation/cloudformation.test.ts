@@ -251,6 +251,45 @@ describe('CloudFormation', function () {
         return path.join(path.dirname(__filename), 'yaml', templateFileName)
     }
 
+    describe('isStreamingLambdaResource', function () {
+        it('detects RESPONSE_STREAM invoke mode', function () {
+            const resource = createBaseResource()
+            resource.Properties!.FunctionUrlConfig = { AuthType: 'NONE', InvokeMode: 'RESPONSE_STREAM' }
+            assert.strictEqual(CloudFormation.isStreamingLambdaResource(resource.Properties), true)
+        })
+
+        it('returns false for buffered or missing function URLs', function () {
+            const resource = createBaseResource()
+            assert.strictEqual(CloudFormation.isStreamingLambdaResource(resource.Properties), false)
+            resource.Properties!.FunctionUrlConfig = { AuthType: 'NONE', InvokeMode: 'BUFFERED' }
+            assert.strictEqual(CloudFormation.isStreamingLambdaResource(resource.Properties), false)
+            assert.strictEqual(CloudFormation.isStreamingLambdaResource(undefined), false)
+        })
+    })
+
+    describe('getArchitectureForResource', function () {
+        it('gets the architecture of the function, falling back to Globals', function () {
+            const resource = createBaseResource()
+            const template: CloudFormation.Template = { Resources: { resource } }
+            assert.strictEqual(CloudFormation.getArchitectureForResource(resource.Properties, template), undefined)
+
+            template.Globals = { Function: { Architectures: ['arm64'] } as any }
+            assert.strictEqual(CloudFormation.getArchitectureForResource(resource.Properties, template), 'arm64')
+
+            resource.Properties!.Architectures = ['x86_64']
+            assert.strictEqual(CloudFormation.getArchitectureForResource(resource.Properties, template), 'x86_64')
+        })
+
+        it('ignores unsupported architectures', function () {
+            const resource = createBaseResource()
+            resource.Properties!.Architectures = ['sparc' as CloudFormation.Architecture]
+            assert.strictEqual(
+                CloudFormation.getArchitectureForResource(resource.Properties, { Resources: { resource } }),
+                undefined
+            )
+        })
+    })
+
     describe('getResourceFromTemplate', async function () {
         for (const scenario of templateWithExistingHandlerScenarios) {
             it(`should retrieve resource for ${scenario.title}`, async () => {
@@ -179,6 +179,21 @@ describe('localLambdaRunner', async function () {
         })
     })
 
+    describe('mergeEnvironmentVariables', function () {
+        it('overrides variables per resource and keeps the rest', function () {
+            const merged = localLambdaRunner.mergeEnvironmentVariables(
+                { MyFunction: { STAGE: 'file', TABLE: 'table' }, Other: { KEY: 'value' } },
+                { MyFunction: { STAGE: 'config' }, Added: { NEW: 'new' } }
+            )
+
+            assert.deepStrictEqual(merged, {
+                MyFunction: { STAGE: 'config', TABLE: 'table' },
+                Other: { KEY: 'value' },
+                Added: { NEW: 'new' },
+            })
+        })
+    })
+
     describe('executeSamBuild', function () {
         const failedChildProcess: ChildProcessResult = {
             exitCode: 1,
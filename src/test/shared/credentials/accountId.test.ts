@@ -7,7 +7,7 @@ import * as assert from 'assert'
 import * as sinon from 'sinon'
 import { StsClient } from '../../../shared/clients/stsClient'
 import { ToolkitClientBuilder } from '../../../shared/clients/toolkitClientBuilder'
-import { getAccountId } from '../../../shared/credentials/accountId'
+import { getAccountId, getAccountIdentity } from '../../../shared/credentials/accountId'
 import { ext } from '../../../shared/extensionGlobals'
 
 describe('getAccountId', function () {
@@ -53,6 +53,19 @@ describe('getAccountId', function () {
         assert.strictEqual(accountId, mockResponse.Account)
     })
 
+    it('returns the partition of the caller ARN', async function () {
+        const mockResponse: AWS.STS.GetCallerIdentityResponse = {
+            Account: '123456789012',
+            Arn: 'arn:aws-us-gov:iam::123456789012:user/someuser',
+        }
+
+        sandbox.stub(stsClient, 'getCallerIdentity').resolves(mockResponse)
+
+        const identity = await getAccountIdentity(credentials, 'someregion')
+
+        assert.deepStrictEqual(identity, { accountId: '123456789012', partitionId: 'aws-us-gov' })
+    })
+
     it('returns undefined if getCallerIdentity returns an undefined account', async function () {
         const mockResponse: AWS.STS.GetCallerIdentityResponse = {
             Account: undefined,
@@ -30,7 +30,10 @@ describe('LoginManager', async function () {
 
     let loginManager: LoginManager
     let credentialsProvider: CredentialsProvider
-    let getAccountIdStub: sinon.SinonStub<[AWS.Credentials, string], Promise<string | undefined>>
+    let getAccountIdentityStub: sinon.SinonStub<
+        Parameters<typeof accountId.getAccountIdentity>,
+        Promise<accountId.AccountIdentity | undefined>
+    >
     let getCredentialsProviderStub: sinon.SinonStub<[CredentialsId], Promise<CredentialsProvider | undefined>>
     let recordAwsSetCredentialsSpy: any
 
@@ -50,8 +53,8 @@ describe('LoginManager', async function () {
             isAvailable: sandbox.stub().returns(Promise.resolve(true))
         }
 
-        getAccountIdStub = sandbox.stub(accountId, 'getAccountId')
-        getAccountIdStub.resolves('AccountId1234')
+        getAccountIdentityStub = sandbox.stub(accountId, 'getAccountIdentity')
+        getAccountIdentityStub.resolves({ accountId: 'AccountId1234', partitionId: 'aws-us-gov' })
         getCredentialsProviderStub = sandbox.stub(CredentialsProviderManager.getInstance(), 'getCredentialsProvider')
         getCredentialsProviderStub.resolves(credentialsProvider)
     })
@@ -102,6 +105,16 @@ describe('LoginManager', async function () {
         )
     })
 
+    it('sets the account and partition of the credentials', async function () {
+        const setCredentialsStub = sandbox.stub(awsContext, 'setCredentials')
+
+        await loginManager.login({ passive: false, providerId: sampleCredentialsId })
+
+        const credentials = setCredentialsStub.firstCall.args[0]
+        assert.strictEqual(credentials?.accountId, 'AccountId1234')
+        assert.strictEqual(credentials?.partitionId, 'aws-us-gov')
+    })
+
     it('logs out (happy path)', async function () {
         const setCredentialsStub = sandbox.stub(awsContext, 'setCredentials')
 
@@ -137,8 +150,8 @@ describe('LoginManager', async function () {
     })
 
     it('logs out if an account Id could not be determined', async function () {
-        getAccountIdStub.reset()
-        getAccountIdStub.resolves(undefined)
+        getAccountIdentityStub.reset()
+        getAccountIdentityStub.resolves(undefined)
         const setCredentialsStub = sandbox.stub(awsContext, 'setCredentials').callsFake(async credentials => {
             // Verify that logout is called
             assert.strictEqual(credentials, undefined)
@@ -156,8 +169,8 @@ describe('LoginManager', async function () {
     })
 
     it('logs out if getting an account Id throws an Error', async function () {
-        getAccountIdStub.reset()
-        getAccountIdStub.throws('Simulating getAccountId throwing an Error')
+        getAccountIdentityStub.reset()
+        getAccountIdentityStub.throws('Simulating getAccountIdentity throwing an Error')
         const setCredentialsStub = sandbox.stub(awsContext, 'setCredentials').callsFake(async credentials => {
             // Verify that logout is called
             assert.strictEqual(credentials, undefined)
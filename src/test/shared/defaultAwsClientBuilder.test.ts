@@ -4,11 +4,12 @@
  */
 
 import * as assert from 'assert'
-import { Service } from 'aws-sdk'
+import { Credentials, Service } from 'aws-sdk'
 import { ServiceConfigurationOptions } from 'aws-sdk/lib/service'
-import { version } from 'vscode'
-import { DefaultAWSClientBuilder } from '../../shared/awsClientBuilder'
+import { ConfigurationTarget, version } from 'vscode'
+import { DefaultAWSClientBuilder, getEndpointOptions } from '../../shared/awsClientBuilder'
 import { FakeAwsContext } from '../utilities/fakeAwsContext'
+import { TestSettingsConfiguration } from '../utilities/testSettingsConfiguration'
 
 describe('DefaultAwsClientBuilder', function () {
     describe('createAndConfigureSdkClient', function () {
@@ -37,5 +38,107 @@ describe('DefaultAwsClientBuilder', function () {
 
             assert.strictEqual(service.config.customUserAgent, 'CUSTOM USER AGENT')
         })
+
+        describe('custom endpoints', function () {
+            /** Records its options, and identifies as S3 like the SDK's service classes. */
+            class RecordingS3Service {
+                public static readonly serviceIdentifier = 's3'
+
+                public constructor(public readonly options: ServiceConfigurationOptions) {}
+            }
+
+            async function createService(
+                builder: DefaultAWSClientBuilder,
+                options?: ServiceConfigurationOptions
+            ): Promise<ServiceConfigurationOptions> {
+                const type = (RecordingS3Service as unknown) as new (o: ServiceConfigurationOptions) => Service
+                const service = ((await builder.createAwsService(type, options)) as unknown) as RecordingS3Service
+
+                return service.options
+            }
+
+            let settings: TestSettingsConfiguration
+
+            beforeEach(async function () {
+                settings = new TestSettingsConfiguration()
+                await settings.writeSetting('endpoints', { s3: 'http://localhost:4566' })
+            })
+
+            it('uses the endpoint configured for the service', async function () {
+                const builder = new DefaultAWSClientBuilder(new FakeAwsContext(), settings)
+
+                assert.strictEqual((await createService(builder)).endpoint, 'http://localhost:4566')
+            })
+
+            it('does not override the endpoint of clients with their own credentials', async function () {
+                const builder = new DefaultAWSClientBuilder(new FakeAwsContext(), settings)
+
+                const options = await createService(builder, { credentials: new Credentials('id', 'secret') })
+
+                assert.strictEqual(options.endpoint, undefined)
+            })
+        })
+    })
+
+    describe('getEndpointOptions', function () {
+        let settings: TestSettingsConfiguration
+
+        beforeEach(async function () {
+            settings = new TestSettingsConfiguration()
+            await settings.writeSetting('endpoints', { lambda: 'http://localhost:4566', s3: 'http://localhost:4566' })
+        })
+
+        it('gets the endpoint of the service', function () {
+            assert.deepStrictEqual(getEndpointOptions('lambda', 'profile:default', settings), {
+                endpoint: 'http://localhost:4566',
+            })
+        })
+
+        it('gets no options for services without endpoints', function () {
+            assert.deepStrictEqual(getEndpointOptions('dynamodb', 'profile:default', settings), {})
+            assert.deepStrictEqual(getEndpointOptions(undefined, 'profile:default', settings), {})
+        })
+
+        it('only gets endpoints for the configured profile', async function () {
+            await settings.writeSetting('endpointsProfile', 'localstack')
+
+            assert.deepStrictEqual(getEndpointOptions('lambda', 'profile:default', settings), {})
+            assert.deepStrictEqual(getEndpointOptions('lambda', undefined, settings), {})
+            assert.deepStrictEqual(getEndpointOptions('lambda', 'profile:localstack', settings), {
+                endpoint: 'http://localhost:4566',
+            })
+        })
+
+        it('uses path-style addressing for S3 when configured', async function () {
+            assert.deepStrictEqual(getEndpointOptions('s3', 'profile:default', settings), {
+                endpoint: 'http://localhost:4566',
+            })
+
+            await settings.writeSetting('s3.forcePathStyle', true)
+
+            assert.deepStrictEqual(getEndpointOptions('s3', 'profile:default', settings), {
+                endpoint: 'http://localhost:4566',
+                s3ForcePathStyle: true,
+            })
+        })
+
+        it('ignores endpoints set by the workspace', async function () {
+            settings = new TestSettingsConfiguration()
+            await settings.writeSetting('endpoints', { lambda: 'https://example.com' }, ConfigurationTarget.Workspace)
+            await settings.writeSetting('s3.forcePathStyle', true, ConfigurationTarget.Workspace)
+
+            assert.deepStrictEqual(getEndpointOptions('lambda', 'profile:default', settings), {})
+            assert.deepStrictEqual(getEndpointOptions('s3', 'profile:default', settings), {})
+        })
+
+        it('throws for endpoints that are not http or https URLs', async function () {
+            await settings.writeSetting('endpoints', { lambda: 'localhost:4566', s3: 'not a url' })
+
+            assert.throws(
+                () => getEndpointOptions('lambda', 'profile:default', settings),
+                /Invalid endpoint for lambda/
+            )
+            assert.throws(() => getEndpointOptions('s3', 'profile:default', settings), /"not a url" is not an http/)
+        })
     })
 })
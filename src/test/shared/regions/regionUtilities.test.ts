@@ -10,7 +10,12 @@ import * as sinon from 'sinon'
 import { AwsContext } from '../../../shared/awsContext'
 import { Region } from '../../../shared/regions/endpoints'
 import { RegionProvider } from '../../../shared/regions/regionProvider'
-import { getRegionsForActiveCredentials } from '../../../shared/regions/regionUtilities'
+import {
+    getDisabledRegions,
+    getPartitionForActiveCredentials,
+    getRegionsForActiveCredentials,
+} from '../../../shared/regions/regionUtilities'
+import { MockEc2Client } from '../clients/mockClients'
 
 describe('getRegionsForActiveCredentials', async function () {
     let sandbox: sinon.SinonSandbox
@@ -18,6 +23,7 @@ describe('getRegionsForActiveCredentials', async function () {
     let regionProvider: RegionProvider
 
     let fnGetCredentialDefaultRegion: sinon.SinonStub<[], string | undefined>
+    let fnGetCredentialPartition: sinon.SinonStub<[], string | undefined>
     let fnGetPartitionId: sinon.SinonStub<[string], string | undefined>
     let fnGetRegions: sinon.SinonStub<[string], Region[]>
 
@@ -37,6 +43,7 @@ describe('getRegionsForActiveCredentials', async function () {
         sandbox = sinon.createSandbox()
 
         fnGetCredentialDefaultRegion = sandbox.stub()
+        fnGetCredentialPartition = sandbox.stub()
 
         fnGetPartitionId = sandbox.stub()
         fnGetPartitionId.returns(samplePartitionId)
@@ -46,6 +53,7 @@ describe('getRegionsForActiveCredentials', async function () {
 
         awsContext = {
             getCredentialDefaultRegion: fnGetCredentialDefaultRegion,
+            getCredentialPartition: fnGetCredentialPartition,
         } as any as AwsContext
 
         regionProvider = {
@@ -81,4 +89,37 @@ describe('getRegionsForActiveCredentials', async function () {
         getRegionsForActiveCredentials(awsContext, regionProvider)
         assert.ok(fnGetRegions.alwaysCalledWith('aws'), 'expected default partition to be used')
     })
+
+    it('uses the partition of the account over that of the default region', async function () {
+        fnGetCredentialDefaultRegion.returns('us-east-1')
+        fnGetCredentialPartition.returns('aws-us-gov')
+
+        assert.strictEqual(getPartitionForActiveCredentials(awsContext, regionProvider), 'aws-us-gov')
+        getRegionsForActiveCredentials(awsContext, regionProvider)
+        assert.ok(fnGetRegions.alwaysCalledWith('aws-us-gov'), 'expected account partition to be used')
+    })
+})
+
+describe('getDisabledRegions', async function () {
+    it('returns opt-in regions that are not enabled', async function () {
+        const ec2 = new MockEc2Client({
+            describeRegions: async () => [
+                { RegionName: 'us-east-1', OptInStatus: 'opt-in-not-required' },
+                { RegionName: 'af-south-1', OptInStatus: 'not-opted-in' },
+                { RegionName: 'ap-east-1', OptInStatus: 'opted-in' },
+            ],
+        })
+
+        assert.deepStrictEqual([...(await getDisabledRegions(ec2))], ['af-south-1'])
+    })
+
+    it('returns no regions if they cannot be listed', async function () {
+        const ec2 = new MockEc2Client({
+            describeRegions: async () => {
+                throw new Error('Access denied')
+            },
+        })
+
+        assert.strictEqual((await getDisabledRegions(ec2)).size, 0)
+    })
 })
@@ -13,7 +13,7 @@ import { FileFunctions, SamCliBuildInvocation } from '../../../../shared/sam/cli
 import { SamCliProcessInvoker } from '../../../../shared/sam/cli/samCliInvokerUtils'
 import { ChildProcessResult } from '../../../../shared/utilities/childProcess'
 import { getTestLogger } from '../../../globalSetup.test'
-import { assertArgNotPresent, assertArgsContainArgument } from './samCliTestUtils'
+import { assertArgIsPresent, assertArgNotPresent, assertArgsContainArgument } from './samCliTestUtils'
 import {
     assertLogContainsBadExitInformation,
     BadExitCodeSamCliProcessInvoker,
@@ -268,6 +268,37 @@ describe('SamCliBuildInvocation', async function () {
         }).execute()
     })
 
+    it('passes --cached and --cache-dir to sam cli if cached is true', async function () {
+        const expectedCacheDir = 'my/cache'
+        const processInvoker: SamCliProcessInvoker = new ExtendedTestSamCliProcessInvoker((args: any[]) => {
+            assertArgIsPresent(args, '--cached')
+            assertArgsContainArgument(args, '--cache-dir', expectedCacheDir)
+        })
+
+        await new SamCliBuildInvocation({
+            buildDir: nonRelevantArg,
+            templatePath: placeholderTemplateFile,
+            invoker: processInvoker,
+            cached: true,
+            cacheDir: expectedCacheDir,
+        }).execute()
+    })
+
+    it('does not pass --cached or --cache-dir to sam cli if cached is false', async function () {
+        const processInvoker: SamCliProcessInvoker = new ExtendedTestSamCliProcessInvoker((args: any[]) => {
+            assertArgNotPresent(args, '--cached')
+            assertArgNotPresent(args, '--cache-dir')
+        })
+
+        await new SamCliBuildInvocation({
+            buildDir: nonRelevantArg,
+            templatePath: placeholderTemplateFile,
+            invoker: processInvoker,
+            cached: false,
+            cacheDir: 'my/cache',
+        }).execute()
+    })
+
     it('throws on unexpected exit code', async function () {
         const builder = new SamCliBuildInvocation(
             {
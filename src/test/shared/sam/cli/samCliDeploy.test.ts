@@ -61,6 +61,40 @@ describe('runSamCliDeploy', async function () {
         assert.strictEqual(invokeCount, 1, 'Unexpected invoke count')
     })
 
+    it('includes tags as key=value pairs', async function () {
+        const invoker = new MockSamCliProcessInvoker(args => {
+            invokeCount++
+            const tagsIndex = args.findIndex(arg => arg === '--tags')
+            assert.strictEqual(tagsIndex > -1, true)
+            assert.strictEqual(args[tagsIndex + 1], 'team=serverless')
+            assert.strictEqual(args[tagsIndex + 2], 'stage=beta')
+        })
+
+        await runSamCliDeploy(
+            {
+                ...makeSampleSamCliDeployParameters(new Map<string, string>()),
+                tags: new Map<string, string>([
+                    ['team', 'serverless'],
+                    ['stage', 'beta'],
+                ]),
+            },
+            invoker
+        )
+
+        assert.strictEqual(invokeCount, 1, 'Unexpected invoke count')
+    })
+
+    it('does not include --tags if there are no tags', async function () {
+        const invoker = new MockSamCliProcessInvoker(args => {
+            invokeCount++
+            assertArgNotPresent(args, '--tags')
+        })
+
+        await runSamCliDeploy(makeSampleSamCliDeployParameters(new Map<string, string>()), invoker)
+
+        assert.strictEqual(invokeCount, 1, 'Unexpected invoke count')
+    })
+
     it('includes a template, stack name, bucket, and region', async function () {
         const invoker = new MockSamCliProcessInvoker(args => {
             invokeCount++
@@ -89,6 +123,33 @@ describe('runSamCliDeploy', async function () {
         assert.strictEqual(invokeCount, 1, 'Unexpected invoke count')
     })
 
+    it('masks the values of NoEcho parameters where the command is logged', async function () {
+        let maskedArgs: string[] | undefined
+        const invoker = new MockSamCliProcessInvoker(() => {})
+        const invoke = invoker.invoke.bind(invoker)
+        invoker.invoke = async options => {
+            maskedArgs = options?.maskArguments?.(options.arguments ?? [])
+            return invoke(options)
+        }
+
+        await runSamCliDeploy(
+            {
+                ...makeSampleSamCliDeployParameters(
+                    new Map<string, string>([
+                        ['Stage', 'dev'],
+                        ['DbPassword', 'hunter2'],
+                    ])
+                ),
+                noEchoParameters: new Set(['DbPassword']),
+            },
+            invoker
+        )
+
+        assert.ok(maskedArgs?.includes('Stage=dev'))
+        assert.ok(maskedArgs?.includes('DbPassword=****'))
+        assert.ok(!maskedArgs?.includes('DbPassword=hunter2'))
+    })
+
     it('throws on unexpected exit code', async function () {
         const badExitCodeProcessInvoker = new BadExitCodeSamCliProcessInvoker({})
 
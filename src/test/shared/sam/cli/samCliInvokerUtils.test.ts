@@ -7,6 +7,7 @@ import * as assert from 'assert'
 import {
     logAndThrowIfUnexpectedExitCode,
     makeUnexpectedExitCodeError,
+    maskParameterOverrides,
 } from '../../../../shared/sam/cli/samCliInvokerUtils'
 import { getTestLogger } from '../../../globalSetup.test'
 import { assertLogContainsBadExitInformation } from './testSamCliProcessInvoker'
@@ -42,3 +43,18 @@ describe('logAndThrowIfUnexpectedExitCode', async function () {
         await assertLogContainsBadExitInformation(getTestLogger(), childProcessResult, 456)
     })
 })
+
+describe('maskParameterOverrides', function () {
+    it('masks the values of NoEcho parameter overrides', function () {
+        const args = ['deploy', '--parameter-overrides', 'Stage=dev', 'DbPassword=hunter2', '--tags', 'DbPassword=tag']
+
+        assert.deepStrictEqual(maskParameterOverrides(args, new Set(['DbPassword'])), [
+            'deploy',
+            '--parameter-overrides',
+            'Stage=dev',
+            'DbPassword=****',
+            '--tags',
+            'DbPassword=tag',
+        ])
+    })
+})
@@ -67,6 +67,14 @@ describe('SamCliStartApi', async function () {
         assertArgsContainArgument(invokeArgs, '--env-vars', expectedEnvVarsPath)
     })
 
+    it('Does not pass env-vars to sam cli when undefined', async function () {
+        const invokeArgs = await buildSamCliStartApiArguments({
+            templatePath: placeholderTemplateFile,
+        })
+
+        assertArgNotPresent(invokeArgs, '--env-vars')
+    })
+
     it('Passes debug port to sam cli', async function () {
         const expectedDebugPort = '1234'
 
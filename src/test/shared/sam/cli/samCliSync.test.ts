@@ -0,0 +1,52 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { buildSamCliSyncArguments, SamCliSyncParameters } from '../../../../shared/sam/cli/samCliSync'
+import { assertArgIsPresent, assertArgNotPresent, assertArgsContainArgument } from './samCliTestUtils'
+
+describe('buildSamCliSyncArguments', function () {
+    const parameters: SamCliSyncParameters = {
+        templateFile: 'template',
+        stackName: 'stackName',
+        region: 'region',
+        parameterOverrides: new Map<string, string>(),
+    }
+
+    it('watches the template, stack and region', function () {
+        const args = buildSamCliSyncArguments(parameters)
+
+        assert.deepStrictEqual(args.slice(0, 2), ['sync', '--watch'])
+        assertArgsContainArgument(args, '--template-file', 'template')
+        assertArgsContainArgument(args, '--stack-name', 'stackName')
+        assertArgsContainArgument(args, '--region', 'region')
+        assertArgNotPresent(args, '--s3-bucket')
+        assertArgNotPresent(args, '--image-repository')
+        assertArgNotPresent(args, '--parameter-overrides')
+    })
+
+    it('does not restrict the sync to code changes', function () {
+        // `--code` would skip template changes; watch mode already syncs code-only changes without deploying
+        assertArgNotPresent(buildSamCliSyncArguments(parameters), '--code')
+    })
+
+    it('includes the bucket, image repository and parameter overrides', function () {
+        const args = buildSamCliSyncArguments({
+            ...parameters,
+            s3Bucket: 'bucket',
+            ecrRepo: 'repo',
+            parameterOverrides: new Map([
+                ['key1', 'value1'],
+                ['key2', 'value2'],
+            ]),
+        })
+
+        assertArgsContainArgument(args, '--s3-bucket', 'bucket')
+        assertArgsContainArgument(args, '--image-repository', 'repo')
+        assertArgIsPresent(args, '--parameter-overrides')
+        const overridesIndex = args.indexOf('--parameter-overrides')
+        assert.deepStrictEqual(args.slice(overridesIndex + 1, overridesIndex + 3), ['key1=value1', 'key2=value2'])
+    })
+})
@@ -211,4 +211,12 @@ describe('DefaultAwsSamDebugConfigurationValidator', function () {
         const result = validator.validate(templateConfig)
         assert.strictEqual(result.isValid, false)
     })
+
+    it('returns invalid when the env-vars file does not exist', function () {
+        const config = createTemplateConfig()
+        config.sam = { envVarsFile: '/nonexistent/env.json' }
+
+        const result = validator.validate(config)
+        assert.strictEqual(result.isValid, false)
+    })
 })
@@ -0,0 +1,204 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as fs from 'fs-extra'
+import * as path from 'path'
+import * as vscode from 'vscode'
+import { DebugConfigurationSource, LaunchConfiguration } from '../../../../../shared/debug/launchConfiguration'
+import { makeTemporaryToolkitFolder, tryRemoveFolder } from '../../../../../shared/filesystemUtilities'
+import { AwsSamDebuggerConfiguration } from '../../../../../shared/sam/debugger/awsSamDebugConfiguration'
+import {
+    exportSamDebugConfigurations,
+    importSamDebugConfigurations,
+    mergeSamDebugConfigurations,
+    parseSamDebugConfigsFile,
+    redactSecrets,
+    SAM_DEBUG_CONFIGS_FILE_VERSION,
+} from '../../../../../shared/sam/debugger/commands/shareSamDebugConfigurations'
+import { FakeWindow } from '../../../vscode/fakeWindow'
+
+function makeConfig(name: string, environmentVariables: { [key: string]: string } = {}): AwsSamDebuggerConfiguration {
+    return {
+        type: 'aws-sam',
+        request: 'direct-invoke',
+        name,
+        invokeTarget: { target: 'template', templatePath: 'template.yaml', logicalId: 'HelloWorldFunction' },
+        lambda: { payload: { json: { hello: 'world' } }, environmentVariables },
+        aws: { credentials: 'profile:default', region: 'us-west-2' },
+    }
+}
+
+class FakeDebugConfigSource implements DebugConfigurationSource {
+    public constructor(public configs: vscode.DebugConfiguration[]) {}
+
+    public getDebugConfigurations(): vscode.DebugConfiguration[] {
+        return this.configs
+    }
+
+    public async setDebugConfigurations(value: vscode.DebugConfiguration[]): Promise<void> {
+        this.configs = value
+    }
+}
+
+describe('redactSecrets', function () {
+    it('clears values of secret environment variables and headers', function () {
+        const config = makeConfig('config', {
+            AWS_ACCESS_KEY_ID: 'AKIA',
+            AWS_SECRET_ACCESS_KEY: 'secret',
+            DB_PASSWORD: 'hunter2',
+            TABLE_NAME: 'table',
+        })
+        config.api = { path: '/', httpMethod: 'get', headers: { Authorization: 'Bearer abc', Accept: 'text/html' } }
+
+        const { config: redactedConfig, redacted } = redactSecrets(config)
+
+        assert.deepStrictEqual(redactedConfig.lambda?.environmentVariables, {
+            AWS_ACCESS_KEY_ID: '',
+            AWS_SECRET_ACCESS_KEY: '',
+            DB_PASSWORD: '',
+            TABLE_NAME: 'table',
+        })
+        assert.deepStrictEqual(redactedConfig.api?.headers, { Authorization: '', Accept: 'text/html' })
+        assert.deepStrictEqual(redacted, ['AWS_ACCESS_KEY_ID', 'AWS_SECRET_ACCESS_KEY', 'DB_PASSWORD', 'Authorization'])
+        assert.strictEqual(config.lambda?.environmentVariables?.DB_PASSWORD, 'hunter2', 'original was modified')
+    })
+
+    it('clears values of secret template and query string parameters', function () {
+        const config = makeConfig('config')
+        config.sam = { template: { parameters: { DbPassword: 'hunter2', ApiKey: 1234, Stage: 'dev' } } }
+        config.api = { path: '/', httpMethod: 'get', querystring: 'token=abc&page=2' }
+
+        const { config: redactedConfig, redacted } = redactSecrets(config)
+
+        assert.deepStrictEqual(redactedConfig.sam?.template?.parameters, { DbPassword: '', ApiKey: '', Stage: 'dev' })
+        assert.strictEqual(redactedConfig.api?.querystring, 'token=&page=2')
+        assert.deepStrictEqual(redacted, ['DbPassword', 'ApiKey', 'token'])
+    })
+
+    it('removes the credentials profile, and keeps the region', function () {
+        const { config: redactedConfig } = redactSecrets(makeConfig('config'))
+
+        assert.deepStrictEqual(redactedConfig.aws, { region: 'us-west-2' })
+    })
+})
+
+describe('parseSamDebugConfigsFile', function () {
+    it('returns named SAM debug configurations', function () {
+        const text = JSON.stringify({
+            version: SAM_DEBUG_CONFIGS_FILE_VERSION,
+            configurations: [makeConfig('sam'), { type: 'node', name: 'node' }, { ...makeConfig(''), name: '' }],
+        })
+
+        assert.deepStrictEqual(parseSamDebugConfigsFile(text), [makeConfig('sam')])
+    })
+
+    it('throws on invalid files', function () {
+        assert.throws(() => parseSamDebugConfigsFile('{'), /Invalid JSON/)
+        assert.throws(() => parseSamDebugConfigsFile('{}'), /Missing "configurations" list/)
+        assert.throws(
+            () => parseSamDebugConfigsFile(JSON.stringify({ version: 99, configurations: [] })),
+            /Unsupported version 99/
+        )
+    })
+})
+
+describe('mergeSamDebugConfigurations', function () {
+    const existing = [makeConfig('a'), { type: 'node', request: 'launch', name: 'b' }]
+
+    it('adds new configurations to the top', async function () {
+        const merged = await mergeSamDebugConfigurations(existing, [makeConfig('c')], async () => {
+            throw new Error('unexpected prompt')
+        })
+
+        assert.deepStrictEqual(merged, [makeConfig('c'), ...existing])
+    })
+
+    it('resolves name collisions', async function () {
+        const imported = [makeConfig('a', { KEY: 'new' }), makeConfig('b'), makeConfig('b'), makeConfig('a')]
+        const resolutions = ['overwrite', 'keepBoth', 'keepBoth', 'skip'] as const
+        const prompted: string[] = []
+
+        const merged = await mergeSamDebugConfigurations(existing, imported, async name => {
+            return resolutions[prompted.push(name) - 1]
+        })
+
+        assert.deepStrictEqual(prompted, ['a', 'b', 'b', 'a'])
+        assert.deepStrictEqual(merged?.map(config => config.name), ['b (2)', 'b (3)', 'a', 'b'])
+        assert.deepStrictEqual(merged?.[2], makeConfig('a', { KEY: 'new' }))
+    })
+
+    it('returns undefined when cancelled', async function () {
+        const merged = await mergeSamDebugConfigurations(existing, [makeConfig('a')], async () => undefined)
+
+        assert.strictEqual(merged, undefined)
+    })
+})
+
+describe('SAM debug configuration import and export', function () {
+    let tempFolder: string
+    let file: vscode.Uri
+    let configSource: FakeDebugConfigSource
+    let launchConfig: LaunchConfiguration
+
+    beforeEach(async function () {
+        tempFolder = await makeTemporaryToolkitFolder()
+        file = vscode.Uri.file(path.join(tempFolder, 'configs.json'))
+        configSource = new FakeDebugConfigSource([
+            makeConfig('sam', { SECRET: 'value' }),
+            { type: 'node', request: 'launch', name: 'node' },
+        ])
+        launchConfig = new LaunchConfiguration(vscode.Uri.file(tempFolder), configSource, {
+            validate: () => ({ isValid: true }),
+        })
+    })
+
+    afterEach(async function () {
+        await tryRemoveFolder(tempFolder)
+    })
+
+    it('exports SAM debug configurations without secrets', async function () {
+        const window = new FakeWindow({ dialog: { saveSelection: file } })
+
+        await exportSamDebugConfigurations(launchConfig, window)
+
+        assert.deepStrictEqual(JSON.parse(await fs.readFile(file.fsPath, 'utf8')), {
+            version: SAM_DEBUG_CONFIGS_FILE_VERSION,
+            configurations: [{ ...makeConfig('sam', { SECRET: '' }), aws: { region: 'us-west-2' } }],
+        })
+        assert.ok(window.message.information?.includes('SECRET'))
+    })
+
+    it('imports SAM debug configurations, prompting on name collisions', async function () {
+        await fs.writeFile(
+            file.fsPath,
+            JSON.stringify({
+                version: SAM_DEBUG_CONFIGS_FILE_VERSION,
+                configurations: [makeConfig('sam'), makeConfig('new')],
+            })
+        )
+        const window = new FakeWindow({
+            dialog: { openSelections: [file] },
+            message: { warningSelection: 'Keep Both' },
+        })
+
+        await importSamDebugConfigurations(launchConfig, window)
+
+        assert.ok(window.message.warning?.includes('"sam"'))
+        assert.deepStrictEqual(
+            configSource.configs.map(config => config.name),
+            ['sam (2)', 'new', 'sam', 'node']
+        )
+    })
+
+    it('does not change launch.json when the import is cancelled', async function () {
+        await fs.writeFile(file.fsPath, JSON.stringify({ configurations: [makeConfig('sam')] }))
+        const configs = configSource.configs
+
+        await importSamDebugConfigurations(launchConfig, new FakeWindow({ dialog: { openSelections: [file] } }))
+
+        assert.strictEqual(configSource.configs, configs)
+    })
+})
@@ -0,0 +1,46 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { StreamingResponseWriter } from '../../../shared/sam/streamingResponse'
+
+describe('StreamingResponseWriter', function () {
+    let output: string
+    let writer: StreamingResponseWriter
+
+    beforeEach(function () {
+        output = ''
+        writer = new StreamingResponseWriter({
+            append: (text: string) => (output += text),
+            appendLine: (text: string) => (output += `${text}\n`),
+        })
+    })
+
+    it('writes chunks as they arrive', function () {
+        writer.write('hello ')
+        assert.strictEqual(output, 'hello ')
+        writer.write('world')
+        assert.strictEqual(output, 'hello world')
+        assert.strictEqual(writer.received, 11)
+    })
+
+    it('returns undefined when the stream completed normally', function () {
+        writer.write('part 1\n')
+        writer.write('part 2\n')
+        assert.strictEqual(writer.findError(), undefined)
+    })
+
+    it('detects an error object written after partial output', function () {
+        writer.write('partial output')
+        writer.write('{"errorType":"Error","errorMessage":"boom"}\n')
+        assert.deepStrictEqual(writer.findError(), { errorType: 'Error', errorMessage: 'boom' })
+        assert.strictEqual(writer.received, 58)
+    })
+
+    it('ignores output that only looks like an error object', function () {
+        writer.write('{"errors": [1, 2')
+        assert.strictEqual(writer.findError(), undefined)
+    })
+})
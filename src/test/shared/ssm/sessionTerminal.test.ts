@@ -0,0 +1,85 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import * as vscode from 'vscode'
+import { getActiveSessionCount, onDidCloseTerminal, openSessionTerminal } from '../../../shared/ssm/sessionTerminal'
+import { MockSsmDocumentClient } from '../clients/mockClients'
+
+describe('openSessionTerminal', function () {
+    const session = { SessionId: 'session-1', StreamUrl: 'wss://stream', TokenValue: 'token' }
+
+    let terminatedSessions: string[]
+    let terminalOptions: vscode.TerminalOptions[]
+    let shown: number
+
+    async function open(credentialsId: string | undefined): Promise<vscode.Terminal> {
+        const ssm = {
+            ...new MockSsmDocumentClient('us-west-2'),
+            getEndpoint: async () => 'https://ssm.us-west-2.amazonaws.com/',
+            terminateSession: async (sessionId: string) => {
+                terminatedSessions.push(sessionId)
+
+                return {}
+            },
+        }
+
+        return await openSessionTerminal(
+            { name: 'EC2: my-instance', session, sessionId: 'session-1', target: 'i-0123', ssm },
+            {
+                awsContext: { getCredentialProfileName: () => credentialsId },
+                createTerminal: options => {
+                    terminalOptions.push(options)
+
+                    return ({
+                        show: () => {
+                            shown++
+                        },
+                    } as any) as vscode.Terminal
+                },
+            }
+        )
+    }
+
+    beforeEach(function () {
+        terminatedSessions = []
+        terminalOptions = []
+        shown = 0
+    })
+
+    it('runs the session manager plugin with the arguments of the AWS CLI', async function () {
+        await open('profile:default')
+
+        assert.strictEqual(terminalOptions[0].name, 'EC2: my-instance')
+        assert.strictEqual(terminalOptions[0].shellPath, 'session-manager-plugin')
+        assert.deepStrictEqual(terminalOptions[0].shellArgs, [
+            JSON.stringify(session),
+            'us-west-2',
+            'StartSession',
+            'default',
+            JSON.stringify({ Target: 'i-0123' }),
+            'https://ssm.us-west-2.amazonaws.com/',
+        ])
+        assert.strictEqual(shown, 1)
+    })
+
+    it('passes no profile for credentials that are not from a profile', async function () {
+        await open('ec2:instance')
+
+        assert.strictEqual((terminalOptions[0].shellArgs as string[])[3], '')
+    })
+
+    it('terminates the session when its terminal closes', async function () {
+        const terminal = await open('profile:default')
+        const count = getActiveSessionCount()
+
+        await onDidCloseTerminal(({} as any) as vscode.Terminal)
+        assert.deepStrictEqual(terminatedSessions, [])
+
+        await onDidCloseTerminal(terminal)
+        assert.deepStrictEqual(terminatedSessions, ['session-1'])
+        assert.strictEqual(getActiveSessionCount(), count - 1)
+    })
+})
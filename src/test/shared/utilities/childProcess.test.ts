@@ -150,6 +150,20 @@ describe('ChildProcess', async function () {
             assert.notStrictEqual(result.error, undefined)
         })
 
+        it('decodes multi-byte characters split across chunks', async function () {
+            // Writes the UTF-8 bytes of '€' (e2 82 ac) in two separate chunks.
+            const script = `process.stdout.write(Buffer.from([0xe2, 0x82]), () => setTimeout(() => process.stdout.write(Buffer.from([0xac])), 50))`
+            const childProcess = new ChildProcess(true, process.execPath, undefined, '-e', script)
+
+            const result = await childProcess.run()
+
+            validateChildProcessResult({
+                childProcessResult: result,
+                expectedExitCode: 0,
+                expectedOutput: '€',
+            })
+        })
+
         function validateChildProcessResult({
             childProcessResult,
             expectedExitCode,
@@ -287,8 +287,12 @@ class DefaultFakeMessage implements FakeMessage {
     }
 }
 
-// eslint-disable-next-line @typescript-eslint/no-empty-interface
-export interface ProgressOptions {}
+export interface ProgressOptions {
+    /**
+     * Whether the user cancels the task as soon as it starts. Defaults to false.
+     */
+    cancel?: boolean
+}
 
 export interface FakeProgress {
     /**
@@ -327,14 +331,18 @@ class DefaultFakeProgress implements FakeProgress {
         }
 
         const token: vscode.CancellationToken = {
-            isCancellationRequested: false,
+            isCancellationRequested: this.cancel,
             onCancellationRequested: new vscode.EventEmitter().event,
         }
 
         return task(reporter, token)
     }
 
-    public constructor(_options: ProgressOptions = {}) {}
+    private readonly cancel: boolean
+
+    public constructor({ cancel }: ProgressOptions = {}) {
+        this.cancel = cancel ?? false
+    }
 }
 
 export interface DialogOptions {
@@ -0,0 +1,90 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { SQS } from 'aws-sdk'
+import {
+    formatMessages,
+    peekMessages,
+    PEEK_MAX_MESSAGES,
+    PEEK_VISIBILITY_TIMEOUT_SECONDS,
+} from '../../../sqs/commands/peekMessages'
+import { SqsNode } from '../../../sqs/explorer/sqsNode'
+import { SqsQueueNode } from '../../../sqs/explorer/sqsQueueNode'
+import { MockSqsClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('peekMessages', function () {
+    const queueUrl = 'https://sqs.us-west-2.amazonaws.com/123456789012/my-queue'
+    const message: SQS.Message = {
+        MessageId: 'id',
+        ReceiptHandle: 'handle',
+        Body: 'hello',
+        Attributes: { ApproximateReceiveCount: '1' },
+        MessageAttributes: {
+            source: { DataType: 'String', StringValue: 'toolkit' },
+            data: { DataType: 'Binary', BinaryValue: Buffer.from('bytes') },
+        },
+    }
+
+    function makeNode(sqs: MockSqsClient): SqsQueueNode {
+        return new SqsQueueNode(new SqsNode(sqs), sqs, queueUrl)
+    }
+
+    it('receives messages with a short visibility timeout and opens them', async function () {
+        let receiveArgs: [string, number, number] | undefined
+        const sqs = new MockSqsClient({
+            receiveMessages: async (...args) => {
+                receiveArgs = args
+                return [message]
+            },
+        })
+        const window = new FakeWindow()
+        let content: string | undefined
+
+        await peekMessages(makeNode(sqs), window, async text => (content = text))
+
+        assert.deepStrictEqual(receiveArgs, [queueUrl, PEEK_MAX_MESSAGES, PEEK_VISIBILITY_TIMEOUT_SECONDS])
+        assert.strictEqual(content, formatMessages([message]))
+        assert.ok(window.message.information?.includes('They were not consumed'))
+    })
+
+    it('shows a message when no messages are available', async function () {
+        const window = new FakeWindow()
+
+        await peekMessages(makeNode(new MockSqsClient({})), window, async () =>
+            assert.fail('should not open a document')
+        )
+
+        assert.ok(window.message.information?.startsWith('No messages are available in my-queue'))
+    })
+
+    it('shows an error message when messages cannot be received', async function () {
+        const sqs = new MockSqsClient({
+            receiveMessages: async () => {
+                throw new Error('Expected failure')
+            },
+        })
+        const window = new FakeWindow()
+
+        await peekMessages(makeNode(sqs), window, async () => assert.fail('should not open a document'))
+
+        assert.ok(window.message.error?.startsWith('Failed to peek messages in my-queue'))
+    })
+
+    it('formats message attributes without receipt handles', function () {
+        const [formatted] = JSON.parse(formatMessages([message]))
+
+        assert.deepStrictEqual(formatted, {
+            MessageId: 'id',
+            Body: 'hello',
+            Attributes: { ApproximateReceiveCount: '1' },
+            MessageAttributes: {
+                source: { DataType: 'String', Value: 'toolkit' },
+                data: { DataType: 'Binary', Value: Buffer.from('bytes').toString('base64') },
+            },
+        })
+    })
+})
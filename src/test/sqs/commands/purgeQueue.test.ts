@@ -0,0 +1,61 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { purgeQueue } from '../../../sqs/commands/purgeQueue'
+import { SqsNode } from '../../../sqs/explorer/sqsNode'
+import { SqsQueueNode } from '../../../sqs/explorer/sqsQueueNode'
+import { MockSqsClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('purgeQueue', function () {
+    const queueUrl = 'https://sqs.us-west-2.amazonaws.com/123456789012/my-queue'
+    let purgedQueueUrl: string | undefined
+
+    beforeEach(function () {
+        purgedQueueUrl = undefined
+    })
+
+    function makeNode(
+        purge = async (url: string) => {
+            purgedQueueUrl = url
+        }
+    ): SqsQueueNode {
+        const sqs = new MockSqsClient({ purgeQueue: purge })
+
+        return new SqsQueueNode(new SqsNode(sqs), sqs, queueUrl)
+    }
+
+    it('purges the queue when its name is entered', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'my-queue' } })
+
+        await purgeQueue(makeNode(), window)
+
+        assert.strictEqual(purgedQueueUrl, queueUrl)
+        assert.ok(window.message.information?.startsWith('Purging my-queue'))
+    })
+
+    it('does not purge the queue when another name is entered', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'other-queue' } })
+
+        await purgeQueue(makeNode(), window)
+
+        assert.strictEqual(purgedQueueUrl, undefined)
+        assert.ok(window.inputBox.errorMessage?.includes('Enter my-queue to confirm'))
+    })
+
+    it('shows an error message when the queue cannot be purged', async function () {
+        const window = new FakeWindow({ inputBox: { input: 'my-queue' } })
+
+        await purgeQueue(
+            makeNode(async () => {
+                throw new Error('Expected failure')
+            }),
+            window
+        )
+
+        assert.ok(window.message.error?.startsWith('Failed to purge my-queue'))
+    })
+})
@@ -0,0 +1,116 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { SQS } from 'aws-sdk'
+import { parseMessageAttributes, sendMessage, validateFifoId } from '../../../sqs/commands/sendMessage'
+import { SqsNode } from '../../../sqs/explorer/sqsNode'
+import { SqsQueueNode } from '../../../sqs/explorer/sqsQueueNode'
+import { MockSqsClient } from '../../shared/clients/mockClients'
+import { FakeWindow } from '../../shared/vscode/fakeWindow'
+
+describe('sendMessage', function () {
+    const queueUrl = 'https://sqs.us-west-2.amazonaws.com/123456789012/my-queue'
+    let sentRequest: SQS.SendMessageRequest | undefined
+
+    beforeEach(function () {
+        sentRequest = undefined
+    })
+
+    function makeNode(url: string, options: ConstructorParameters<typeof MockSqsClient>[0] = {}): SqsQueueNode {
+        const sqs = new MockSqsClient({
+            sendMessage: async request => {
+                sentRequest = request
+                return { MessageId: 'id' }
+            },
+            ...options,
+        })
+
+        return new SqsQueueNode(new SqsNode(sqs), sqs, url)
+    }
+
+    function makeWindow(inputs: (string | undefined)[]): FakeWindow {
+        const window = new FakeWindow()
+        window.showInputBox = async () => inputs.shift()
+
+        return window
+    }
+
+    it('sends a message with the entered body and attributes', async function () {
+        const window = makeWindow(['hello', '{"source": "toolkit"}'])
+
+        await sendMessage(makeNode(queueUrl), window)
+
+        assert.deepStrictEqual(sentRequest, {
+            QueueUrl: queueUrl,
+            MessageBody: 'hello',
+            MessageAttributes: { source: { DataType: 'String', StringValue: 'toolkit' } },
+        })
+        assert.strictEqual(window.message.information, 'Sent message id to my-queue')
+    })
+
+    it('prompts for the message group and deduplication IDs of FIFO queues', async function () {
+        await sendMessage(makeNode(`${queueUrl}.fifo`), makeWindow(['hello', '', 'group', 'dedup']))
+
+        assert.strictEqual(sentRequest?.MessageGroupId, 'group')
+        assert.strictEqual(sentRequest?.MessageDeduplicationId, 'dedup')
+    })
+
+    it('allows no deduplication ID for FIFO queues that deduplicate by content', async function () {
+        const node = makeNode(`${queueUrl}.fifo`, {
+            getQueueAttributes: async () => ({ ContentBasedDeduplication: 'true' }),
+        })
+
+        await sendMessage(node, makeWindow(['hello', '', 'group', '']))
+
+        assert.strictEqual(sentRequest?.MessageGroupId, 'group')
+        assert.strictEqual(sentRequest?.MessageDeduplicationId, undefined)
+    })
+
+    it('does not send a message when cancelled', async function () {
+        await sendMessage(makeNode(queueUrl), makeWindow(['hello', undefined]))
+
+        assert.strictEqual(sentRequest, undefined)
+    })
+
+    it('shows an error message when the message cannot be sent', async function () {
+        const node = makeNode(queueUrl, {
+            sendMessage: async () => {
+                throw new Error('Expected failure')
+            },
+        })
+        const window = makeWindow(['hello', ''])
+
+        await sendMessage(node, window)
+
+        assert.ok(window.message.error?.startsWith('Failed to send message to my-queue'))
+    })
+
+    describe('parseMessageAttributes', function () {
+        it('parses string and number attributes', function () {
+            assert.deepStrictEqual(parseMessageAttributes('{"source": "toolkit", "attempt": 2}'), {
+                source: { DataType: 'String', StringValue: 'toolkit' },
+                attempt: { DataType: 'Number', StringValue: '2' },
+            })
+        })
+
+        it('parses no attributes from empty text', function () {
+            assert.deepStrictEqual(parseMessageAttributes(' '), {})
+        })
+
+        it('rejects values that are not objects of strings and numbers', function () {
+            assert.throws(() => parseMessageAttributes('["a"]'), /must be a JSON object/)
+            assert.throws(() => parseMessageAttributes('{"flag": true}'), /Attribute flag must be a string or a number/)
+            assert.throws(() => parseMessageAttributes('{'))
+        })
+    })
+
+    it('validates FIFO IDs', function () {
+        assert.strictEqual(validateFifoId('group-1'), undefined)
+        assert.ok(validateFifoId(''))
+        assert.ok(validateFifoId('has space'))
+        assert.ok(validateFifoId('a'.repeat(129)))
+    })
+})
@@ -0,0 +1,54 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { SqsNode } from '../../../sqs/explorer/sqsNode'
+import { SqsQueueNode } from '../../../sqs/explorer/sqsQueueNode'
+import { ErrorNode } from '../../../shared/treeview/nodes/errorNode'
+import { PlaceholderNode } from '../../../shared/treeview/nodes/placeholderNode'
+import { asyncGenerator } from '../../utilities/collectionUtils'
+import { MockSqsClient } from '../../shared/clients/mockClients'
+
+describe('SqsNode', function () {
+    const queueUrlPrefix = 'https://sqs.us-west-2.amazonaws.com/123456789012/'
+
+    function makeNode(listQueues: () => AsyncIterableIterator<string>): SqsNode {
+        return new SqsNode(new MockSqsClient({ listQueues }))
+    }
+
+    it('gets queues sorted by name', async function () {
+        const node = makeNode(() => asyncGenerator([`${queueUrlPrefix}orders.fifo`, `${queueUrlPrefix}emails`]))
+
+        const [first, second, ...others] = (await node.getChildren()) as SqsQueueNode[]
+
+        assert.strictEqual(first.name, 'emails')
+        assert.strictEqual(first.queueUrl, `${queueUrlPrefix}emails`)
+        assert.strictEqual(first.contextValue, 'awsSqsQueueNode')
+        assert.ok(!first.fifo)
+        assert.strictEqual(second.name, 'orders.fifo')
+        assert.ok(second.fifo)
+        assert.strictEqual(others.length, 0)
+    })
+
+    it('shows a placeholder node when there are no queues', async function () {
+        const [first, ...others] = await makeNode(() => asyncGenerator([])).getChildren()
+
+        assert.strictEqual((first as PlaceholderNode).label, '[No queues found]')
+        assert.strictEqual(others.length, 0)
+    })
+
+    it('shows an error node when listing queues fails', async function () {
+        const node = makeNode(async function* () {
+            throw new Error('network broke')
+            // at least one yield is required for async generator even if it is unreachable
+            yield ''
+        })
+
+        const [first, ...others] = await node.getChildren()
+
+        assert.ok(first instanceof ErrorNode)
+        assert.strictEqual(others.length, 0)
+    })
+})
@@ -0,0 +1,155 @@
+/*!
+ * Copyright 2021 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+import * as assert from 'assert'
+import { StepFunctions } from 'aws-sdk'
+import { StepFunctionsClient } from '../../../shared/clients/stepFunctionsClient'
+import {
+    ExecutionHistoryReader,
+    findStateDefinitionOffset,
+    groupEventsByState,
+} from '../../../stepFunctions/vue/executionHistory'
+import { MockStepFunctionsClient } from '../../shared/clients/mockClients'
+
+function historyEvent(
+    id: number,
+    type: string,
+    details: Partial<StepFunctions.HistoryEvent> = {},
+    previousEventId: number = id - 1
+): StepFunctions.HistoryEvent {
+    return { id, previousEventId, type, timestamp: new Date(0), ...details }
+}
+
+describe('groupEventsByState', function () {
+    it('groups task events under the state that scheduled them', function () {
+        const groups = groupEventsByState([
+            historyEvent(1, 'ExecutionStarted', { executionStartedEventDetails: { input: '{"a":1}' } }, 0),
+            historyEvent(2, 'TaskStateEntered', { stateEnteredEventDetails: { name: 'Call API', input: '{"a":1}' } }),
+            historyEvent(3, 'TaskScheduled', {
+                taskScheduledEventDetails: {
+                    resource: 'invoke',
+                    resourceType: 'lambda',
+                    region: 'us-east-1',
+                    parameters: '{}',
+                },
+            }),
+            historyEvent(4, 'TaskStarted'),
+            historyEvent(5, 'TaskFailed', { taskFailedEventDetails: { error: 'Lambda.Unknown', cause: 'boom' } }),
+            historyEvent(6, 'ExecutionFailed', { executionFailedEventDetails: { error: 'Lambda.Unknown' } }),
+        ])
+
+        assert.deepStrictEqual(
+            groups.map(group => group.name),
+            [undefined, 'Call API']
+        )
+        const [execution, task] = groups
+        assert.deepStrictEqual(
+            execution.events.map(event => event.type),
+            ['ExecutionStarted', 'ExecutionFailed']
+        )
+        assert.strictEqual(execution.failed, true)
+        assert.strictEqual(task.stateType, 'Task')
+        assert.strictEqual(task.input, '{\n    "a": 1\n}')
+        assert.deepStrictEqual(
+            task.events.map(event => event.type),
+            ['TaskStateEntered', 'TaskScheduled', 'TaskStarted', 'TaskFailed']
+        )
+        assert.strictEqual(task.failed, true)
+        assert.deepStrictEqual(task.events[3].details, { error: 'Lambda.Unknown', cause: 'boom' })
+    })
+
+    it('sets the output of exited states', function () {
+        const groups = groupEventsByState([
+            historyEvent(1, 'PassStateEntered', { stateEnteredEventDetails: { name: 'First' } }, 0),
+            historyEvent(2, 'PassStateExited', { stateExitedEventDetails: { name: 'First', output: 'not json' } }),
+            historyEvent(3, 'SucceedStateEntered', { stateEnteredEventDetails: { name: 'Done' } }),
+        ])
+
+        assert.deepStrictEqual(
+            groups.map(group => group.name),
+            ['First', 'Done']
+        )
+        assert.strictEqual(groups[0].output, 'not json')
+        assert.strictEqual(groups[0].failed, false)
+        assert.strictEqual(groups[1].output, undefined)
+    })
+
+    it('keeps parallel state events apart from the states in its branches', function () {
+        const groups = groupEventsByState([
+            historyEvent(1, 'ParallelStateEntered', { stateEnteredEventDetails: { name: 'Fan Out' } }, 0),
+            historyEvent(2, 'ParallelStateStarted'),
+            historyEvent(3, 'PassStateEntered', { stateEnteredEventDetails: { name: 'Branch' } }),
+            historyEvent(4, 'PassStateExited', { stateExitedEventDetails: { name: 'Branch' } }),
+            historyEvent(5, 'ParallelStateSucceeded'),
+            historyEvent(6, 'ParallelStateExited', { stateExitedEventDetails: { name: 'Fan Out', output: '[]' } }),
+        ])
+
+        assert.deepStrictEqual(
+            groups.map(group => group.name),
+            ['Fan Out', 'Branch']
+        )
+        assert.deepStrictEqual(
+            groups[0].events.map(event => event.id),
+            [1, 2, 5, 6]
+        )
+        assert.strictEqual(groups[0].output, '[]')
+    })
+})
+
+describe('findStateDefinitionOffset', function () {
+    it('finds states in JSON definitions', function () {
+        const text = '{"StartAt": "Call API", "States": {"Call API": {"Type": "Task", "Next": "Done"}, "Done": {}}}'
+
+        assert.strictEqual(findStateDefinitionOffset(text, 'Call API', false), text.indexOf('Call API": {'))
+        assert.strictEqual(findStateDefinitionOffset(text, 'Done', false), text.lastIndexOf('Done'))
+        assert.strictEqual(findStateDefinitionOffset(text, 'Missing', false), undefined)
+    })
+
+    it('finds states in YAML definitions', function () {
+        const text = [
+            'StartAt: Call API',
+            'States:',
+            '  Call API:',
+            '    Type: Task',
+            '    Next: Done',
+            '  "Done":',
+            '    Type: Succeed',
+        ].join('\n')
+
+        assert.strictEqual(findStateDefinitionOffset(text, 'Call API', true), text.indexOf('Call API:\n'))
+        assert.strictEqual(findStateDefinitionOffset(text, 'Done', true), text.indexOf('Done"'))
+    })
+})
+
+describe('ExecutionHistoryReader', function () {
+    it('loads pages of events with the next token', async function () {
+        const requests: (string | undefined)[] = []
+        const client: StepFunctionsClient = {
+            ...new MockStepFunctionsClient(),
+            getExecutionHistory: async (executionArn: string, nextToken?: string) => {
+                requests.push(nextToken)
+
+                return nextToken
+                    ? { events: [historyEvent(2, 'ExecutionSucceeded')] }
+                    : { events: [historyEvent(1, 'ExecutionStarted', {}, 0)], nextToken: 'page2' }
+            },
+        }
+        const reader = new ExecutionHistoryReader(client, 'arn', 1)
+
+        const first = await reader.loadMore()
+        assert.strictEqual(first.hasMore, true)
+        assert.strictEqual(first.eventCount, 1)
+
+        const second = await reader.loadMore()
+        assert.strictEqual(second.hasMore, false)
+        assert.deepStrictEqual(
+            second.groups[0].events.map(event => event.type),
+            ['ExecutionStarted', 'ExecutionSucceeded']
+        )
+
+        await reader.loadMore()
+        assert.deepStrictEqual(requests, [undefined, 'page2'])
+    })
+})
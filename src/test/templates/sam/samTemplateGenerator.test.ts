@@ -84,6 +84,16 @@ describe('SamTemplateGenerator', function () {
         assert.strictEqual(resource!.Properties!.Timeout, sampleTimeout)
     })
 
+    it('Produces a template containing Architectures', async function () {
+        await makeMinimalTemplate().withArchitectures(['arm64']).generate(templateFilename)
+
+        const template: CloudFormation.Template = await CloudFormation.load(templateFilename)
+        const resource = template.Resources![sampleResourceNameValue]
+        assert.ok(resource)
+        assert.deepStrictEqual(resource!.Properties!.Architectures, ['arm64'])
+        assert.strictEqual(CloudFormation.getArchitectureForResource(resource!.Properties, template), 'arm64')
+    })
+
     it('Produces a template containing Environment', async function () {
         await makeMinimalTemplate().withEnvironment(sampleEnvironment).generate(templateFilename)
 
@@ -85,6 +85,10 @@ export class FakeAwsContext implements AwsContext {
         return this.awsContextCredentials?.accountId
     }
 
+    public getCredentialPartition(): string | undefined {
+        return this.awsContextCredentials?.partitionId
+    }
+
     public getCredentialDefaultRegion(): string {
         return this.awsContextCredentials?.defaultRegion ?? DEFAULT_REGION
     }
@@ -3,6 +3,7 @@
  * SPDX-License-Identifier: Apache-2.0
  */
 
+import * as vscode from 'vscode'
 import { SettingsConfiguration } from '../../shared/settingsConfiguration'
 
 /**
@@ -10,13 +11,25 @@ import { SettingsConfiguration } from '../../shared/settingsConfiguration'
  */
 export class TestSettingsConfiguration implements SettingsConfiguration {
     private readonly _data: { [key: string]: any } = {}
+    private readonly _workspaceData: { [key: string]: any } = {}
 
     public readSetting<T>(settingKey: string, defaultValue?: T | undefined): T | undefined {
+        return (settingKey in this._workspaceData ? this._workspaceData[settingKey] : this._data[settingKey]) as T
+    }
+
+    public readUserSetting<T>(settingKey: string): T | undefined {
         return this._data[settingKey] as T
     }
 
+    /**
+     * @param target Values written to the workspace (or a workspace folder) are kept apart from user values
+     */
     public async writeSetting<T>(settingKey: string, value: T, target?: any): Promise<boolean> {
-        this._data[settingKey] = value
+        if (target === vscode.ConfigurationTarget.Workspace || target === vscode.ConfigurationTarget.WorkspaceFolder) {
+            this._workspaceData[settingKey] = value
+        } else {
+            this._data[settingKey] = value
+        }
         return true
     }
 }
@@ -25,6 +25,19 @@ const baseConfig = {
         extension: './src/extension.ts',
         'src/stepFunctions/asl/aslServer': './src/stepFunctions/asl/aslServer.ts',
         samInvokeVue: path.resolve(__dirname, 'src', 'lambda', 'vue', 'samInvokeVue.ts'),
+        dynamoDbTableViewerVue: path.resolve(__dirname, 'src', 'dynamoDb', 'vue', 'tableViewerVue.ts'),
+        stepFunctionsExecutionHistoryVue: path.resolve(
+            __dirname,
+            'src',
+            'stepFunctions',
+            'vue',
+            'executionHistoryVue.ts'
+        ),
+        cloudFormationStackEventsVue: path.resolve(__dirname, 'src', 'lambda', 'vue', 'stackEventsVue.ts'),
+        cloudWatchLogsInsightsVue: path.resolve(__dirname, 'src', 'cloudWatchLogs', 'vue', 'logsInsightsVue.ts'),
+        kinesisRecordViewerVue: path.resolve(__dirname, 'src', 'kinesis', 'vue', 'recordViewerVue.ts'),
+        lambdaEditConfigurationVue: path.resolve(__dirname, 'src', 'lambda', 'vue', 'editConfigurationVue.ts'),
+        lambdaFunctionMetricsVue: path.resolve(__dirname, 'src', 'lambda', 'vue', 'functionMetricsVue.ts'),
     },
     output: {
         path: path.resolve(__dirname, 'dist'),